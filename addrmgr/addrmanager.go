@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2014 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -93,6 +93,12 @@ type AddrManager struct {
 
 	// triedBucketSize is the maximum number of addresses in each tried bucket.
 	triedBucketSize int
+
+	// asmap is an optional IP to autonomous system number map that, when set,
+	// is used to group addresses by the autonomous system that announces them
+	// instead of by their network prefix.  It is not modified once the address
+	// manager is started.
+	asmap *ASMap
 }
 
 // serializedKnownAddress is used to represent the serializable state of a
@@ -115,6 +121,7 @@ type serializedAddrManager struct {
 	Addresses    []*serializedKnownAddress
 	NewBuckets   [newBucketCount][]string
 	TriedBuckets [triedBucketCount][]string
+	ASMap        string `json:",omitempty"`
 }
 
 type localAddress struct {
@@ -354,13 +361,13 @@ func (a *AddrManager) getOldestAddressIndex(bucket int) int {
 	return idx
 }
 
-// getNewBucket returns a psuedorandom new bucket index for the provided
-// addresses.
-func getNewBucket(key [32]byte, netAddr, srcAddr *NetAddress) int {
+// getNewBucket returns a psuedorandom new bucket index for addresses in the
+// provided network groups.
+func getNewBucket(key [32]byte, netGroup, srcGroup string) int {
 	data1 := []byte{}
 	data1 = append(data1, key[:]...)
-	data1 = append(data1, []byte(netAddr.GroupKey())...)
-	data1 = append(data1, []byte(srcAddr.GroupKey())...)
+	data1 = append(data1, []byte(netGroup)...)
+	data1 = append(data1, []byte(srcGroup)...)
 	hash1 := chainhash.HashB(data1)
 	hash64 := binary.LittleEndian.Uint64(hash1)
 	hash64 %= newBucketsPerGroup
@@ -368,7 +375,7 @@ func getNewBucket(key [32]byte, netAddr, srcAddr *NetAddress) int {
 	binary.LittleEndian.PutUint64(hashbuf[:], hash64)
 	data2 := []byte{}
 	data2 = append(data2, key[:]...)
-	data2 = append(data2, srcAddr.GroupKey()...)
+	data2 = append(data2, srcGroup...)
 	data2 = append(data2, hashbuf[:]...)
 
 	hash2 := chainhash.HashB(data2)
@@ -376,8 +383,8 @@ func getNewBucket(key [32]byte, netAddr, srcAddr *NetAddress) int {
 }
 
 // getTriedBucket returns a psuedorandom tried bucket index for the provided
-// address.
-func getTriedBucket(key [32]byte, netAddr *NetAddress) int {
+// address which is part of the given network group.
+func getTriedBucket(key [32]byte, netAddr *NetAddress, netGroup string) int {
 	data1 := []byte{}
 	data1 = append(data1, key[:]...)
 	data1 = append(data1, []byte(netAddr.Key())...)
@@ -388,7 +395,7 @@ func getTriedBucket(key [32]byte, netAddr *NetAddress) int {
 	binary.LittleEndian.PutUint64(hashbuf[:], hash64)
 	data2 := []byte{}
 	data2 = append(data2, key[:]...)
-	data2 = append(data2, netAddr.GroupKey()...)
+	data2 = append(data2, netGroup...)
 	data2 = append(data2, hashbuf[:]...)
 
 	hash2 := chainhash.HashB(data2)
@@ -430,6 +437,7 @@ func (a *AddrManager) savePeers() {
 	sam := new(serializedAddrManager)
 	sam.Version = serialisationVersion
 	copy(sam.Key[:], a.key[:])
+	sam.ASMap = a.asmapID()
 
 	sam.Addresses = make([]*serializedKnownAddress, len(a.addrIndex))
 	i := 0
@@ -596,9 +604,108 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 		}
 	}
 
+	// The buckets depend on the network groups of the addresses, so they
+	// must be recalculated when the map used to determine the autonomous
+	// systems of the addresses changed.
+	if sam.ASMap != a.asmapID() {
+		log.Infof("IP to ASN map changed since the peers file was saved -- "+
+			"rebucketing %d addresses", len(a.addrIndex))
+		a.rebucket()
+	}
+
 	return nil
 }
 
+// rebucket redistributes all known addresses among the new and tried buckets
+// according to their current bucket assignments.  Tried addresses that no
+// longer fit in their tried bucket are moved to the new buckets and new
+// addresses that no longer fit in their new bucket are removed.
+//
+// This function MUST be called with the address manager lock held (for writes).
+func (a *AddrManager) rebucket() {
+	var tried []*KnownAddress
+	for i := range a.addrTried {
+		tried = append(tried, a.addrTried[i]...)
+		a.addrTried[i] = nil
+	}
+	for i := range a.addrNew {
+		a.addrNew[i] = make(map[string]*KnownAddress)
+	}
+	a.nTried = 0
+	a.nNew = 0
+
+	for _, ka := range tried {
+		bucket := a.getTriedBucket(ka.na)
+		if len(a.addrTried[bucket]) >= a.triedBucketSize {
+			ka.tried = false
+			continue
+		}
+		a.addrTried[bucket] = append(a.addrTried[bucket], ka)
+		a.nTried++
+	}
+	for key, ka := range a.addrIndex {
+		if ka.tried {
+			continue
+		}
+		ka.refs = 0
+		bucket := a.getNewBucket(ka.na, ka.srcAddr)
+		if len(a.addrNew[bucket]) >= newBucketSize {
+			delete(a.addrIndex, key)
+			continue
+		}
+		ka.refs = 1
+		a.addrNew[bucket][key] = ka
+		a.nNew++
+	}
+	a.addrChanged = true
+}
+
+// SetASMap sets the IP to autonomous system number map used to group
+// addresses.  When set, addresses that map to an autonomous system are grouped
+// by it instead of by their network prefix which both limits the number of
+// buckets a single network operator is able to occupy and ensures outbound
+// connections are spread across distinct operators.
+//
+// This function MUST be called prior to starting the address manager.
+func (a *AddrManager) SetASMap(asmap *ASMap) {
+	a.asmap = asmap
+}
+
+// asmapID returns the hash of the IP to autonomous system number map in use as
+// a string, or an empty string when there is not one.
+func (a *AddrManager) asmapID() string {
+	if a.asmap == nil {
+		return ""
+	}
+	hash := a.asmap.Hash()
+	return hash.String()
+}
+
+// ASN returns the autonomous system number the passed network address maps to
+// using the map set via SetASMap.  Zero is returned when there is no map or the
+// address does not map to an autonomous system.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) ASN(na *NetAddress) uint32 {
+	if a.asmap == nil {
+		return 0
+	}
+	return a.asmap.ASN(na)
+}
+
+// GroupKey returns a string representing the network group the passed address
+// is part of.  This is the autonomous system number prefixed by "as" when the
+// address maps to one via the map set via SetASMap.  Otherwise, it is the same
+// as the GroupKey method of the address.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) GroupKey(na *NetAddress) string {
+	if asn := a.ASN(na); asn != 0 {
+		return fmt.Sprintf("as%d", asn)
+	}
+	return na.GroupKey()
+}
+
 // Start begins the core address handler which manages a pool of known
 // addresses, timeouts, and interval based writes.  If the address manager is
 // starting or has already been started, invoking this method has no
@@ -732,10 +839,10 @@ func (a *AddrManager) reset() {
 	}
 	a.addrChanged = true
 	a.getNewBucket = func(netAddr, srcAddr *NetAddress) int {
-		return getNewBucket(a.key, netAddr, srcAddr)
+		return getNewBucket(a.key, a.GroupKey(netAddr), a.GroupKey(srcAddr))
 	}
 	a.getTriedBucket = func(netAddr *NetAddress) int {
-		return getTriedBucket(a.key, netAddr)
+		return getTriedBucket(a.key, netAddr, a.GroupKey(netAddr))
 	}
 }
 
@@ -752,6 +859,14 @@ func EncodeHost(host string) (NetAddressType, []byte) {
 		return IPv6Address, ip
 	}
 
+	// Look for Tor v3 onion service and I2P destination addresses.
+	if pubKey, ok := decodeTorV3Host(host); ok {
+		return TorV3Address, pubKey
+	}
+	if hash, ok := decodeI2PHost(host); ok {
+		return I2PAddress, hash
+	}
+
 	// The given host address could not be recognized
 	return UnknownAddressType, nil
 }
@@ -771,54 +886,89 @@ func (a *AddrManager) GetAddress() *KnownAddress {
 	}
 
 	// Use a 50% chance for choosing between tried and new table entries.
+	if a.nTried > 0 && (a.nNew == 0 || rand.IntN(2) == 0) {
+		return a.selectTried()
+	}
+	return a.selectNew()
+}
+
+// GetUntriedAddress returns a single address from the new address buckets
+// using the same selection criteria as GetAddress.  These are the addresses
+// that have not been confirmed to be reachable, which makes this useful for
+// selecting addresses to probe via short-lived connections so the reachable
+// ones can be moved to the tried buckets via Good.  It returns nil when there
+// are no new addresses.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) GetUntriedAddress() *KnownAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.nNew == 0 {
+		return nil
+	}
+	return a.selectNew()
+}
+
+// selectTried returns a random address from the tried buckets with preference
+// given to ones that have not been used recently.
+//
+// This function MUST be called with the address manager lock held and there
+// MUST be at least one tried address.
+func (a *AddrManager) selectTried() *KnownAddress {
 	large := 1 << 30
 	factor := 1.0
-	if a.nTried > 0 && (a.nNew == 0 || rand.IntN(2) == 0) {
-		// Tried entry.
-		for {
-			// Pick a random bucket.
-			bucket := rand.IntN(len(a.addrTried))
-			if len(a.addrTried[bucket]) == 0 {
-				continue
-			}
+	for {
+		// Pick a random bucket.
+		bucket := rand.IntN(len(a.addrTried))
+		if len(a.addrTried[bucket]) == 0 {
+			continue
+		}
 
-			// Then, a random entry in the list.
-			randEntry := rand.IntN(len(a.addrTried[bucket]))
-			ka := a.addrTried[bucket][randEntry]
+		// Then, a random entry in the list.
+		randEntry := rand.IntN(len(a.addrTried[bucket]))
+		ka := a.addrTried[bucket][randEntry]
 
-			randval := rand.IntN(large)
-			if float64(randval) < (factor * ka.chance() * float64(large)) {
-				log.Tracef("Selected %v from tried bucket", ka.na.Key())
-				return ka
-			}
-			factor *= 1.2
+		randval := rand.IntN(large)
+		if float64(randval) < (factor * ka.chance() * float64(large)) {
+			log.Tracef("Selected %v from tried bucket", ka.na.Key())
+			return ka
 		}
-	} else {
-		// New node.
-		for {
-			// Pick a random bucket.
-			bucket := rand.IntN(len(a.addrNew))
-			if len(a.addrNew[bucket]) == 0 {
-				continue
-			}
+		factor *= 1.2
+	}
+}
 
-			// Then, a random entry in it.
-			var ka *KnownAddress
-			nth := rand.IntN(len(a.addrNew[bucket]))
-			for _, value := range a.addrNew[bucket] {
-				if nth == 0 {
-					ka = value
-					break
-				}
-				nth--
-			}
-			randval := rand.IntN(large)
-			if float64(randval) < (factor * ka.chance() * float64(large)) {
-				log.Tracef("Selected %s from new bucket", ka.na)
-				return ka
+// selectNew returns a random address from the new buckets with preference
+// given to ones that have not been used recently.
+//
+// This function MUST be called with the address manager lock held and there
+// MUST be at least one new address.
+func (a *AddrManager) selectNew() *KnownAddress {
+	large := 1 << 30
+	factor := 1.0
+	for {
+		// Pick a random bucket.
+		bucket := rand.IntN(len(a.addrNew))
+		if len(a.addrNew[bucket]) == 0 {
+			continue
+		}
+
+		// Then, a random entry in it.
+		var ka *KnownAddress
+		nth := rand.IntN(len(a.addrNew[bucket]))
+		for _, value := range a.addrNew[bucket] {
+			if nth == 0 {
+				ka = value
+				break
 			}
-			factor *= 1.2
+			nth--
+		}
+		randval := rand.IntN(large)
+		if float64(randval) < (factor * ka.chance() * float64(large)) {
+			log.Tracef("Selected %s from new bucket", ka.na)
+			return ka
 		}
+		factor *= 1.2
 	}
 }
 
@@ -1082,6 +1232,10 @@ const (
 
 	// Ipv6Strong represents a connection state between two IPv6 addresses.
 	Ipv6Strong
+
+	// Private represents a connection state between two addresses on the same
+	// overlay network such as Tor or I2P.
+	Private
 )
 
 // getRemoteReachabilityFromLocal returns the type of connection reachability
@@ -1109,6 +1263,8 @@ func getRemoteReachabilityFromLocal(localAddr, remoteAddr *NetAddress) NetAddres
 		switch {
 		case localAddr.IsRoutable() && localAddr.Type == IPv4Address:
 			return Ipv4
+		case isOverlayNetwork(localAddr.Type):
+			return Default
 		default:
 			return Unreachable
 		}
@@ -1121,6 +1277,8 @@ func getRemoteReachabilityFromLocal(localAddr, remoteAddr *NetAddress) NetAddres
 			return Teredo
 		case localAddr.Type == IPv4Address:
 			return Ipv4
+		case isOverlayNetwork(localAddr.Type):
+			return Default
 
 		// Is our IPv6 tunneled?
 		case isRFC3964(localAddr.IP) || isRFC6052(localAddr.IP) ||
@@ -1131,6 +1289,25 @@ func getRemoteReachabilityFromLocal(localAddr, remoteAddr *NetAddress) NetAddres
 			return Ipv6Strong
 		}
 
+	case remoteAddr.Type == TorV3Address:
+		switch {
+		case localAddr.Type == TorV3Address:
+			return Private
+		case localAddr.IsRoutable() && localAddr.Type == IPv4Address:
+			// Tor exit nodes are able to reach IPv4 addresses.
+			return Ipv4
+		default:
+			return Default
+		}
+
+	case remoteAddr.Type == I2PAddress:
+		switch {
+		case localAddr.Type == I2PAddress:
+			return Private
+		default:
+			return Default
+		}
+
 	default:
 		return Default
 	}
@@ -388,6 +388,39 @@ func TestGetAddress(t *testing.T) {
 	}
 }
 
+// TestGetUntriedAddress ensures that GetUntriedAddress only returns addresses
+// that have not been marked good.
+func TestGetUntriedAddress(t *testing.T) {
+	n := New("testgetuntriedaddress")
+
+	// Get an address from an empty set (should be nil).
+	if ka := n.GetUntriedAddress(); ka != nil {
+		t.Fatalf("GetUntriedAddress failed - got: %v, want: %v", ka, nil)
+	}
+
+	// Add a new address and get it.
+	n.addAddressByIP(routableIPv4Addr, 8333)
+	ka := n.GetUntriedAddress()
+	if ka == nil {
+		t.Fatal("did not get an address where there is one in the pool")
+	}
+	ipKey := net.JoinHostPort(routableIPv4Addr, "8333")
+	if got := ka.NetAddress().String(); got != ipKey {
+		t.Fatalf("unexpected ip - got %s, want %s", got, ipKey)
+	}
+
+	// Ensure the address is no longer returned once it is marked good.
+	if err := n.Good(ka.NetAddress()); err != nil {
+		t.Fatalf("marking address as good failed: %v", err)
+	}
+	if ka := n.GetUntriedAddress(); ka != nil {
+		t.Fatalf("GetUntriedAddress returned tried address %v", ka.NetAddress())
+	}
+	if ka := n.GetAddress(); ka == nil {
+		t.Fatal("did not get tried address from GetAddress")
+	}
+}
+
 // TestAttempt ensures that Attempt will correctly update the lastAttempt time.
 func TestAttempt(t *testing.T) {
 	n := New("testattempt")
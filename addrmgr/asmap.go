@@ -0,0 +1,368 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"fmt"
+	"math/bits"
+	"net"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// asmapInstruction identifies an instruction of an asmap program.
+type asmapInstruction uint32
+
+// These constants define the instructions of an asmap program.
+const (
+	// asmapReturn returns the autonomous system number that follows it.
+	asmapReturn asmapInstruction = 0
+
+	// asmapJump skips the number of bits of the program that follows it when
+	// the next bit of the IP address is set.
+	asmapJump asmapInstruction = 1
+
+	// asmapMatch compares the next bits of the IP address to the bits that
+	// follow it and returns the current default autonomous system number when
+	// they do not match.
+	asmapMatch asmapInstruction = 2
+
+	// asmapDefault sets the default autonomous system number to the one that
+	// follows it.
+	asmapDefault asmapInstruction = 3
+)
+
+// asmapInvalid is returned when decoding a value from an asmap program fails
+// due to reaching the end of the program.
+const asmapInvalid = 0xffffffff
+
+// asmapIPBits is the number of IP address bits an asmap program operates on.
+// IPv4 addresses are mapped into the IPv6 address space.
+const asmapIPBits = 128
+
+var (
+	// asmapTypeBitSizes, asmapASNBitSizes, asmapMatchBitSizes, and
+	// asmapJumpBitSizes define the variable length encodings of instructions,
+	// autonomous system numbers, match bits, and jump offsets, respectively.
+	asmapTypeBitSizes  = []uint8{0, 0, 1}
+	asmapASNBitSizes   = []uint8{15, 16, 17, 18, 19, 20, 21, 22, 23, 24}
+	asmapMatchBitSizes = []uint8{1, 2, 3, 4, 5, 6, 7, 8}
+	asmapJumpBitSizes  = []uint8{5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+		17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30}
+)
+
+// ASMap maps IP addresses to the autonomous system number (ASN) of the network
+// that announces them.
+//
+// The map is a program in the compact binary trie format used by Bitcoin Core
+// which is typically generated from BGP routing data.  It consists of a series
+// of bit-packed instructions that are interpreted by walking the bits of an IP
+// address from the most significant to the least significant bit.
+type ASMap struct {
+	data []byte
+	hash chainhash.Hash
+}
+
+// asmapBit returns the bit at the provided position of the passed asmap
+// program.  The bits of each byte are consumed from the least significant bit
+// to the most significant bit.
+func asmapBit(data []byte, pos int) uint32 {
+	return uint32(data[pos/8]>>(pos%8)) & 1
+}
+
+// ipBit returns the bit at the provided position of the passed 16-byte IP
+// address starting from the most significant bit.
+func ipBit(ip net.IP, pos int) uint32 {
+	return uint32(ip[pos/8]>>(7-pos%8)) & 1
+}
+
+// asmapDecodeBits decodes a variable length integer with the provided minimum
+// value and encoding from the passed asmap program starting at the given bit
+// position.  It returns the decoded value along with the updated position, or
+// asmapInvalid when the encoding extends beyond the end of the program.
+//
+// Each size, other than the last, is preceded by a bit that indicates whether
+// the value is larger than can be represented with that size.  The value is
+// then stored as a big-endian integer of the first size for which that bit is
+// not set.
+func asmapDecodeBits(data []byte, pos int, minVal uint32, bitSizes []uint8) (uint32, int) {
+	endPos := len(data) * 8
+	val := uint64(minVal)
+	for i, bitSize := range bitSizes {
+		var bit uint32
+		if i+1 != len(bitSizes) {
+			if pos == endPos {
+				break
+			}
+			bit = asmapBit(data, pos)
+			pos++
+		}
+		if bit == 1 {
+			val += 1 << bitSize
+			continue
+		}
+		for b := 0; b < int(bitSize); b++ {
+			if pos == endPos {
+				return asmapInvalid, pos
+			}
+			bit = asmapBit(data, pos)
+			pos++
+			val += uint64(bit) << (int(bitSize) - 1 - b)
+		}
+		return uint32(val), pos
+	}
+	return asmapInvalid, pos
+}
+
+// asmapDecodeType decodes an instruction from the passed asmap program starting
+// at the given bit position.
+func asmapDecodeType(data []byte, pos int) (asmapInstruction, int) {
+	v, pos := asmapDecodeBits(data, pos, 0, asmapTypeBitSizes)
+	return asmapInstruction(v), pos
+}
+
+// asmapDecodeASN decodes an autonomous system number from the passed asmap
+// program starting at the given bit position.
+func asmapDecodeASN(data []byte, pos int) (uint32, int) {
+	return asmapDecodeBits(data, pos, 1, asmapASNBitSizes)
+}
+
+// asmapDecodeMatch decodes the bits to match from the passed asmap program
+// starting at the given bit position.  The highest set bit of the result is a
+// marker that indicates the number of bits to match.
+func asmapDecodeMatch(data []byte, pos int) (uint32, int) {
+	return asmapDecodeBits(data, pos, 2, asmapMatchBitSizes)
+}
+
+// asmapDecodeJump decodes a jump offset from the passed asmap program starting
+// at the given bit position.
+func asmapDecodeJump(data []byte, pos int) (uint32, int) {
+	return asmapDecodeBits(data, pos, 17, asmapJumpBitSizes)
+}
+
+// checkASMap ensures the passed asmap program is well formed for IP addresses
+// with the given number of bits.  In particular, it ensures the program always
+// terminates with an autonomous system number for every possible address, does
+// not contain any unreachable code or redundant instructions, and is not
+// followed by more than the minimum padding required to fill the final byte.
+func checkASMap(data []byte, numBits int) error {
+	type jumpTarget struct {
+		offset   int
+		bitsLeft int
+	}
+
+	endPos := len(data) * 8
+	var pos int
+	var jumps []jumpTarget
+	prevOp := asmapJump
+	var hadIncompleteMatch bool
+	for pos != endPos {
+		if len(jumps) > 0 && pos >= jumps[len(jumps)-1].offset {
+			return fmt.Errorf("jump into the middle of the instruction "+
+				"preceding bit %d", pos)
+		}
+
+		var op asmapInstruction
+		op, pos = asmapDecodeType(data, pos)
+		switch op {
+		case asmapReturn:
+			if prevOp == asmapDefault {
+				return fmt.Errorf("return immediately follows default at "+
+					"bit %d", pos)
+			}
+			var asn uint32
+			asn, pos = asmapDecodeASN(data, pos)
+			if asn == asmapInvalid {
+				return fmt.Errorf("truncated return instruction")
+			}
+			if len(jumps) == 0 {
+				// There is nothing left to execute, so the remaining bits must
+				// be zero padding to the end of the final byte.
+				if endPos-pos > 7 {
+					return fmt.Errorf("excessive padding of %d bits",
+						endPos-pos)
+				}
+				for ; pos != endPos; pos++ {
+					if asmapBit(data, pos) != 0 {
+						return fmt.Errorf("non-zero padding bit %d", pos)
+					}
+				}
+				return nil
+			}
+
+			// Continue as if the most recent jump was taken.
+			target := jumps[len(jumps)-1]
+			if pos != target.offset {
+				return fmt.Errorf("unreachable code at bit %d", pos)
+			}
+			numBits = target.bitsLeft
+			jumps = jumps[:len(jumps)-1]
+			prevOp = asmapJump
+
+		case asmapJump:
+			var jump uint32
+			jump, pos = asmapDecodeJump(data, pos)
+			if jump == asmapInvalid {
+				return fmt.Errorf("truncated jump instruction")
+			}
+			if int64(jump) > int64(endPos-pos) {
+				return fmt.Errorf("jump at bit %d is out of range", pos)
+			}
+			if numBits == 0 {
+				return fmt.Errorf("jump at bit %d consumes bits beyond the "+
+					"end of the address", pos)
+			}
+			numBits--
+			offset := pos + int(jump)
+			if len(jumps) > 0 && offset >= jumps[len(jumps)-1].offset {
+				return fmt.Errorf("intersecting jumps at bit %d", pos)
+			}
+			jumps = append(jumps, jumpTarget{offset, numBits})
+			prevOp = asmapJump
+
+		case asmapMatch:
+			var match uint32
+			match, pos = asmapDecodeMatch(data, pos)
+			if match == asmapInvalid {
+				return fmt.Errorf("truncated match instruction")
+			}
+			matchLen := bits.Len32(match) - 1
+			if prevOp != asmapMatch {
+				hadIncompleteMatch = false
+			}
+			if matchLen < 8 && hadIncompleteMatch {
+				return fmt.Errorf("multiple incomplete matches in sequence "+
+					"at bit %d", pos)
+			}
+			hadIncompleteMatch = matchLen < 8
+			if numBits < matchLen {
+				return fmt.Errorf("match at bit %d consumes bits beyond the "+
+					"end of the address", pos)
+			}
+			numBits -= matchLen
+			prevOp = asmapMatch
+
+		case asmapDefault:
+			if prevOp == asmapDefault {
+				return fmt.Errorf("successive default instructions at bit %d",
+					pos)
+			}
+			var asn uint32
+			asn, pos = asmapDecodeASN(data, pos)
+			if asn == asmapInvalid {
+				return fmt.Errorf("truncated default instruction")
+			}
+			prevOp = asmapDefault
+
+		default:
+			return fmt.Errorf("truncated instruction")
+		}
+	}
+	return fmt.Errorf("no return instruction before the end of the map")
+}
+
+// NewASMap returns an IP to autonomous system number map from the provided
+// data which must be in the compact binary trie format used by Bitcoin Core.
+//
+// An error with kind ErrInvalidASMap is returned when the data is malformed.
+func NewASMap(data []byte) (*ASMap, error) {
+	if err := checkASMap(data, asmapIPBits); err != nil {
+		str := fmt.Sprintf("invalid asmap: %v", err)
+		return nil, makeError(ErrInvalidASMap, str)
+	}
+	return &ASMap{data: data, hash: chainhash.HashH(data)}, nil
+}
+
+// Hash returns the hash of the underlying map data.  It uniquely identifies the
+// map.
+func (m *ASMap) Hash() chainhash.Hash {
+	return m.hash
+}
+
+// lookup returns the autonomous system number the passed 16-byte IP address
+// maps to by interpreting the underlying asmap program.
+func (m *ASMap) lookup(ip net.IP) uint32 {
+	data := m.data
+	endPos := len(data) * 8
+	var pos, ipPos int
+	var defaultASN uint32
+	for pos != endPos {
+		var op asmapInstruction
+		op, pos = asmapDecodeType(data, pos)
+		switch op {
+		case asmapReturn:
+			asn, _ := asmapDecodeASN(data, pos)
+			if asn == asmapInvalid {
+				return 0
+			}
+			return asn
+
+		case asmapJump:
+			var jump uint32
+			jump, pos = asmapDecodeJump(data, pos)
+			if jump == asmapInvalid || ipPos == asmapIPBits {
+				return 0
+			}
+			if ipBit(ip, ipPos) == 1 {
+				if int64(jump) >= int64(endPos-pos) {
+					return 0
+				}
+				pos += int(jump)
+			}
+			ipPos++
+
+		case asmapMatch:
+			var match uint32
+			match, pos = asmapDecodeMatch(data, pos)
+			if match == asmapInvalid {
+				return 0
+			}
+			matchLen := bits.Len32(match) - 1
+			if asmapIPBits-ipPos < matchLen {
+				return 0
+			}
+			for bit := 0; bit < matchLen; bit++ {
+				if ipBit(ip, ipPos) != (match>>(matchLen-1-bit))&1 {
+					return defaultASN
+				}
+				ipPos++
+			}
+
+		case asmapDefault:
+			defaultASN, pos = asmapDecodeASN(data, pos)
+			if defaultASN == asmapInvalid {
+				return 0
+			}
+
+		default:
+			return 0
+		}
+	}
+
+	// Not reachable for maps that passed the sanity checks.
+	return 0
+}
+
+// ASN returns the autonomous system number the passed network address maps to.
+// IPv6 addresses that embed an IPv4 address, such as 6to4 and Teredo addresses,
+// are mapped via the embedded IPv4 address.
+//
+// Zero is returned for addresses that are not routable IP addresses and for
+// addresses the map does not cover.
+func (m *ASMap) ASN(na *NetAddress) uint32 {
+	if na.Type != IPv4Address && na.Type != IPv6Address {
+		return 0
+	}
+	netIP := net.IP(na.IP)
+	if isLocal(netIP) || !IsRoutable(netIP) {
+		return 0
+	}
+	if na.Type == IPv6Address {
+		if ipv4 := linkedIPv4(netIP); ipv4 != nil {
+			netIP = ipv4
+		}
+	}
+	return m.lookup(netIP.To16())
+}
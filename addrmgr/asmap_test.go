@@ -0,0 +1,423 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// asmapEncodeBits returns the bits of the variable length encoding of the
+// provided value with the given minimum value and encoding.  It is the inverse
+// of asmapDecodeBits.
+func asmapEncodeBits(val, minVal uint32, bitSizes []uint8) []uint8 {
+	var bits []uint8
+	val -= minVal
+	for i, bitSize := range bitSizes {
+		if i+1 != len(bitSizes) {
+			if val >= 1<<bitSize {
+				bits = append(bits, 1)
+				val -= 1 << bitSize
+				continue
+			}
+			bits = append(bits, 0)
+		}
+		for b := int(bitSize) - 1; b >= 0; b-- {
+			bits = append(bits, uint8(val>>b)&1)
+		}
+		break
+	}
+	return bits
+}
+
+// The following functions return the bits of the respective asmap
+// instructions.
+func asmapReturnBits(asn uint32) []uint8 {
+	bits := asmapEncodeBits(uint32(asmapReturn), 0, asmapTypeBitSizes)
+	return append(bits, asmapEncodeBits(asn, 1, asmapASNBitSizes)...)
+}
+func asmapJumpBits(jump int) []uint8 {
+	bits := asmapEncodeBits(uint32(asmapJump), 0, asmapTypeBitSizes)
+	return append(bits, asmapEncodeBits(uint32(jump), 17, asmapJumpBitSizes)...)
+}
+func asmapMatchBits(match []uint8) []uint8 {
+	val := uint32(1)
+	for _, bit := range match {
+		val = val<<1 | uint32(bit)
+	}
+	bits := asmapEncodeBits(uint32(asmapMatch), 0, asmapTypeBitSizes)
+	return append(bits, asmapEncodeBits(val, 2, asmapMatchBitSizes)...)
+}
+func asmapDefaultBits(asn uint32) []uint8 {
+	bits := asmapEncodeBits(uint32(asmapDefault), 0, asmapTypeBitSizes)
+	return append(bits, asmapEncodeBits(asn, 1, asmapASNBitSizes)...)
+}
+
+// asmapPack packs the provided program bits into bytes in the order they are
+// consumed by the interpreter.
+func asmapPack(programs ...[]uint8) []byte {
+	var bits []uint8
+	for _, program := range programs {
+		bits = append(bits, program...)
+	}
+	data := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		data[i/8] |= bit << (i % 8)
+	}
+	return data
+}
+
+// asmapTestNode is a node of a binary trie of IP address prefixes used to
+// generate asmap programs for testing.  Leaf nodes must have an autonomous
+// system number while it is optional for internal nodes, where it serves as
+// the default for addresses that do not match a more specific prefix.
+type asmapTestNode struct {
+	asn      uint32
+	children [2]*asmapTestNode
+}
+
+// insert adds the provided IP address prefix to the trie.  IPv4 prefixes are
+// mapped into the IPv6 address space.
+func (n *asmapTestNode) insert(prefix string, asn uint32) {
+	_, ipNet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		panic(err)
+	}
+	ones, numBits := ipNet.Mask.Size()
+	ones += asmapIPBits - numBits
+	ip := ipNet.IP.To16()
+	for i := 0; i < ones; i++ {
+		bit := ipBit(ip, i)
+		if n.children[bit] == nil {
+			n.children[bit] = &asmapTestNode{}
+		}
+		n = n.children[bit]
+	}
+	n.asn = asn
+}
+
+// isLeaf returns whether or not the node is a leaf node.
+func (n *asmapTestNode) isLeaf() bool {
+	return n.children[0] == nil && n.children[1] == nil
+}
+
+// encode returns the bits of the asmap program for the trie rooted at the node
+// given the default autonomous system number in effect.
+func (n *asmapTestNode) encode(defaultASN uint32) []uint8 {
+	if n.isLeaf() {
+		return asmapReturnBits(n.asn)
+	}
+
+	var bits []uint8
+	if n.asn != 0 && n.asn != defaultASN {
+		bits = asmapDefaultBits(n.asn)
+		defaultASN = n.asn
+	}
+	if n.children[0] != nil && n.children[1] != nil {
+		left := n.children[0].encode(defaultASN)
+		right := n.children[1].encode(defaultASN)
+		bits = append(bits, asmapJumpBits(len(left))...)
+		bits = append(bits, left...)
+		return append(bits, right...)
+	}
+
+	// Match the longest chain of nodes with a single child up to the maximum
+	// number of bits a single match instruction supports.
+	var match []uint8
+	for len(match) < 8 {
+		var bit uint8
+		if n.children[0] == nil {
+			bit = 1
+		}
+		match = append(match, bit)
+		n = n.children[bit]
+		if n.isLeaf() || n.children[0] != nil && n.children[1] != nil ||
+			n.asn != 0 && n.asn != defaultASN {
+
+			break
+		}
+	}
+	bits = append(bits, asmapMatchBits(match)...)
+	return append(bits, n.encode(defaultASN)...)
+}
+
+// mustNewTestASMap returns an asmap that maps the provided IP address prefixes
+// to the associated autonomous system numbers.
+func mustNewTestASMap(t *testing.T, prefixes map[string]uint32) *ASMap {
+	t.Helper()
+
+	var root asmapTestNode
+	for prefix, asn := range prefixes {
+		root.insert(prefix, asn)
+	}
+	asmap, err := NewASMap(asmapPack(root.encode(0)))
+	if err != nil {
+		t.Fatalf("unexpected error creating asmap: %v", err)
+	}
+	return asmap
+}
+
+// TestASMapLookup ensures network addresses are mapped to the expected
+// autonomous system numbers.
+func TestASMapLookup(t *testing.T) {
+	asmap := mustNewTestASMap(t, map[string]uint32{
+		"1.2.0.0/16":     100,
+		"1.2.3.0/24":     200,
+		"8.8.0.0/16":     15169,
+		"173.194.0.0/16": 15169,
+		"2a00:1450::/32": 15169,
+		"2600::/16":      401696,
+	})
+
+	onionAddr, err := NewNetAddressFromParams(TorV3Address,
+		bytes.Repeat([]byte{0x12}, torV3PubKeySize), 9108, time.Now(),
+		wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		addr *NetAddress
+		want uint32
+	}{{
+		name: "ipv4 in covering prefix",
+		addr: NewNetAddressFromIPPort(net.ParseIP("1.2.4.5"), 9108, 0),
+		want: 100,
+	}, {
+		name: "ipv4 in more specific prefix",
+		addr: NewNetAddressFromIPPort(net.ParseIP("1.2.3.4"), 9108, 0),
+		want: 200,
+	}, {
+		name: "ipv4 in other prefix of same asn",
+		addr: NewNetAddressFromIPPort(net.ParseIP("173.194.115.66"), 9108, 0),
+		want: 15169,
+	}, {
+		name: "unmapped ipv4",
+		addr: NewNetAddressFromIPPort(net.ParseIP("9.9.9.9"), 9108, 0),
+		want: 0,
+	}, {
+		name: "ipv6",
+		addr: NewNetAddressFromIPPort(net.ParseIP("2a00:1450::1"), 9108, 0),
+		want: 15169,
+	}, {
+		name: "ipv6 with large asn",
+		addr: NewNetAddressFromIPPort(net.ParseIP("2600:1::1"), 9108, 0),
+		want: 401696,
+	}, {
+		name: "unmapped ipv6",
+		addr: NewNetAddressFromIPPort(net.ParseIP("2602:100::1"), 9108, 0),
+		want: 0,
+	}, {
+		name: "ipv6 rfc3964 with ipv4 encap",
+		addr: NewNetAddressFromIPPort(net.ParseIP("2002:0808:0808::"), 9108, 0),
+		want: 15169,
+	}, {
+		name: "ipv6 rfc4380 teredo ipv4",
+		addr: NewNetAddressFromIPPort(net.ParseIP("2001:0:1234::fefd:fcfb"),
+			9108, 0),
+		want: 200,
+	}, {
+		name: "unroutable ipv4",
+		addr: NewNetAddressFromIPPort(net.ParseIP("10.1.2.3"), 9108, 0),
+		want: 0,
+	}, {
+		name: "local ipv4",
+		addr: NewNetAddressFromIPPort(net.ParseIP("127.0.0.1"), 9108, 0),
+		want: 0,
+	}, {
+		name: "tor v3",
+		addr: onionAddr,
+		want: 0,
+	}}
+
+	amgr := New("TestASMapLookup")
+	amgr.SetASMap(asmap)
+	for _, test := range tests {
+		if got := asmap.ASN(test.addr); got != test.want {
+			t.Errorf("%q: unexpected asn -- got %d, want %d", test.name, got,
+				test.want)
+			continue
+		}
+
+		// Ensure the address manager groups addresses by the autonomous system
+		// when they map to one and by the network group otherwise.
+		wantKey := test.addr.GroupKey()
+		if test.want != 0 {
+			wantKey = "as" + fmt.Sprint(test.want)
+		}
+		if got := amgr.GroupKey(test.addr); got != wantKey {
+			t.Errorf("%q: unexpected group key -- got %q, want %q", test.name,
+				got, wantKey)
+		}
+		if got := amgr.ASN(test.addr); got != test.want {
+			t.Errorf("%q: unexpected address manager asn -- got %d, want %d",
+				test.name, got, test.want)
+		}
+	}
+
+	// Ensure the address manager groups addresses by the network group when
+	// there is no map.
+	amgr = New("TestASMapLookup")
+	for _, test := range tests {
+		if got, want := amgr.GroupKey(test.addr), test.addr.GroupKey(); got != want {
+			t.Errorf("%q: unexpected group key without asmap -- got %q, want %q",
+				test.name, got, want)
+		}
+		if got := amgr.ASN(test.addr); got != 0 {
+			t.Errorf("%q: unexpected asn without asmap -- got %d", test.name,
+				got)
+		}
+	}
+}
+
+// TestASMapInvalid ensures malformed asmaps are rejected.
+func TestASMapInvalid(t *testing.T) {
+	match8 := asmapMatchBits([]uint8{0, 0, 0, 0, 0, 0, 0, 0})
+	var match136 [][]uint8
+	for i := 0; i < 17; i++ {
+		match136 = append(match136, match8)
+	}
+	match136 = append(match136, asmapReturnBits(1))
+
+	tests := []struct {
+		name  string
+		data  []byte
+		valid bool
+	}{{
+		name:  "single return",
+		data:  asmapPack(asmapReturnBits(1)),
+		valid: true,
+	}, {
+		name:  "default followed by match",
+		data:  asmapPack(asmapDefaultBits(2), asmapMatchBits([]uint8{1}), asmapReturnBits(1)),
+		valid: true,
+	}, {
+		name: "empty",
+		data: nil,
+	}, {
+		name: "truncated return",
+		data: asmapPack(asmapReturnBits(1))[:2],
+	}, {
+		name: "non-zero padding",
+		data: func() []byte {
+			data := asmapPack(asmapReturnBits(1))
+			data[len(data)-1] |= 0x80
+			return data
+		}(),
+	}, {
+		name: "excessive padding",
+		data: append(asmapPack(asmapReturnBits(1)), 0),
+	}, {
+		name: "return immediately follows default",
+		data: asmapPack(asmapDefaultBits(2), asmapReturnBits(1)),
+	}, {
+		name: "successive defaults",
+		data: asmapPack(asmapDefaultBits(2), asmapDefaultBits(3),
+			asmapMatchBits([]uint8{1}), asmapReturnBits(1)),
+	}, {
+		name: "multiple incomplete matches",
+		data: asmapPack(asmapMatchBits([]uint8{1}), asmapMatchBits([]uint8{1}),
+			asmapReturnBits(1)),
+	}, {
+		name: "match beyond end of address",
+		data: asmapPack(match136...),
+	}, {
+		name: "jump out of range",
+		data: asmapPack(asmapJumpBits(1000), asmapReturnBits(1)),
+	}, {
+		name: "unreachable code",
+		data: asmapPack(asmapJumpBits(34), asmapReturnBits(1),
+			asmapReturnBits(2), asmapReturnBits(3)),
+	}, {
+		name: "missing return",
+		data: asmapPack(asmapDefaultBits(2), asmapMatchBits([]uint8{1})),
+	}}
+
+	for _, test := range tests {
+		_, err := NewASMap(test.data)
+		if test.valid {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidASMap) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name, err,
+				ErrInvalidASMap)
+		}
+	}
+}
+
+// TestASMapRebucket ensures known addresses are rebucketed according to their
+// autonomous systems when the address manager is loaded with a different asmap
+// than the one it was saved with.
+func TestASMapRebucket(t *testing.T) {
+	dir := t.TempDir()
+
+	// Add addresses from a few networks and mark some of them good so both
+	// new and tried addresses are saved.
+	amgr := New(dir)
+	amgr.Start()
+	const numAddrs = 40
+	var addrs []*NetAddress
+	for i := 0; i < numAddrs; i++ {
+		ip := net.IPv4(byte(1+i%4), 2, byte(i), 1)
+		na := NewNetAddressFromIPPort(ip, 9108, 0)
+		amgr.AddAddresses([]*NetAddress{na}, na)
+		addrs = append(addrs, na)
+	}
+	for _, na := range addrs[:numAddrs/4] {
+		if err := amgr.Good(na); err != nil {
+			t.Fatalf("unexpected error marking address good: %v", err)
+		}
+	}
+	if err := amgr.Stop(); err != nil {
+		t.Fatalf("address manager failed to stop: %v", err)
+	}
+
+	// Load the saved addresses with an asmap that maps all of them to the same
+	// autonomous system.
+	asmap := mustNewTestASMap(t, map[string]uint32{"0.0.0.0/5": 64512})
+	amgr = New(dir)
+	amgr.SetASMap(asmap)
+	amgr.Start()
+	defer amgr.Stop()
+
+	// Ensure all addresses are still known and are in the buckets they belong
+	// in according to their autonomous systems.
+	amgr.mtx.Lock()
+	defer amgr.mtx.Unlock()
+	if got := amgr.numAddresses(); got != numAddrs {
+		t.Fatalf("unexpected number of addresses -- got %d, want %d", got,
+			numAddrs)
+	}
+	if amgr.nTried != numAddrs/4 {
+		t.Fatalf("unexpected number of tried addresses -- got %d, want %d",
+			amgr.nTried, numAddrs/4)
+	}
+	for i, bucket := range amgr.addrNew {
+		for _, ka := range bucket {
+			if want := amgr.getNewBucket(ka.na, ka.srcAddr); want != i {
+				t.Fatalf("address %v in new bucket %d, want %d", ka.na, i,
+					want)
+			}
+		}
+	}
+	for i, bucket := range amgr.addrTried {
+		for _, ka := range bucket {
+			if want := amgr.getTriedBucket(ka.na); want != i {
+				t.Fatalf("address %v in tried bucket %d, want %d", ka.na, i,
+					want)
+			}
+		}
+	}
+}
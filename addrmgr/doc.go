@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -31,6 +31,14 @@ generally helps provide greater peer diversity.  More importantly, it
 drastically reduces the chances of an attacker coercing your peer into
 connecting only to nodes they control.
 
+By default, addresses are grouped by their network prefix, such as the /16 for
+IPv4 addresses.  Since a single network operator, such as a large hosting
+provider, typically announces many prefixes, the address manager may also be
+provided with a map of IP addresses to the autonomous system numbers (ASNs) that
+announce them via SetASMap.  Addresses are then grouped by their ASN instead,
+which further limits the influence any single operator has over the selected
+peers.  The map must be in the compact binary trie format used by Bitcoin Core.
+
 The address manager also understands routability, and tries hard to only return
 routable addresses.  In addition, it uses the information provided by the caller
 about connected, known good, and attempted addresses to periodically purge peers
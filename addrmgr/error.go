@@ -22,6 +22,10 @@ const (
 	// ErrMismatchedAddressType indicates that a network address was expected to
 	// be a certain type, but the derived type does not match.
 	ErrMismatchedAddressType = ErrorKind("ErrMismatchedAddressType")
+
+	// ErrInvalidASMap indicates that an IP to autonomous system number map is
+	// malformed.
+	ErrInvalidASMap = ErrorKind("ErrInvalidASMap")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
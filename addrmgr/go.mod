@@ -7,12 +7,12 @@ require (
 	github.com/decred/dcrd/crypto/rand v1.0.1
 	github.com/decred/dcrd/wire v1.7.1
 	github.com/decred/slog v1.2.0
+	golang.org/x/crypto v0.33.0
 )
 
 require (
 	github.com/decred/dcrd/crypto/blake256 v1.1.0 // indirect
 	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
-	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	lukechampine.com/blake3 v1.3.0 // indirect
 )
@@ -1,4 +1,4 @@
-// Copyright (c) 2024 The Decred developers
+// Copyright (c) 2024-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -36,6 +36,11 @@ type NetAddress struct {
 // IsRoutable returns a boolean indicating whether the network address is
 // routable.
 func (netAddr *NetAddress) IsRoutable() bool {
+	// Addresses on overlay networks such as Tor and I2P are always routable
+	// over their respective network.
+	if isOverlayNetwork(netAddr.Type) {
+		return true
+	}
 	return IsRoutable(netAddr.IP)
 }
 
@@ -48,6 +53,10 @@ func (netAddr *NetAddress) ipString() string {
 		return net.IP(netIP).String()
 	case IPv4Address:
 		return net.IP(netIP).String()
+	case TorV3Address:
+		return encodeTorV3Host(netIP)
+	case I2PAddress:
+		return encodeI2PHost(netIP)
 	}
 
 	// If the netAddr.Type is not recognized in the switch:
@@ -115,6 +124,25 @@ func canonicalizeIP(addrType NetAddressType, addrBytes []byte) []byte {
 // checkNetAddressType returns an error if the suggested address type does not
 // appear to match the provided address.
 func checkNetAddressType(addrType NetAddressType, addrBytes []byte) error {
+	// The address type of Tor v3 and I2P addresses can't be derived from the
+	// raw bytes since both are the same size, so only the size is checked.
+	var wantSize int
+	switch addrType {
+	case TorV3Address:
+		wantSize = torV3PubKeySize
+	case I2PAddress:
+		wantSize = i2pHashSize
+	}
+	if wantSize != 0 {
+		if len(addrBytes) != wantSize {
+			str := fmt.Sprintf("address of type %v must be %d bytes (got %d "+
+				"bytes, address bytes %v)", addrType, wantSize,
+				len(addrBytes), addrBytes)
+			return makeError(ErrMismatchedAddressType, str)
+		}
+		return nil
+	}
+
 	derivedAddressType, err := deriveNetAddressType(addrBytes)
 	if err != nil {
 		return err
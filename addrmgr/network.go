@@ -1,11 +1,12 @@
 // Copyright (c) 2013-2014 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
 package addrmgr
 
 import (
+	"fmt"
 	"net"
 )
 
@@ -105,6 +106,8 @@ const (
 	IPv4Address        NetAddressType = 1
 	IPv6Address        NetAddressType = 2
 	// TorV2Address       NetAddressType = 3  // No longer supported
+	TorV3Address NetAddressType = 4
+	I2PAddress   NetAddressType = 5
 )
 
 // NetAddressTypeFilter represents a function that returns whether a particular
@@ -225,11 +228,43 @@ func IsRoutable(netIP net.IP) bool {
 		isLocal(netIP) || isRFC4193(netIP))
 }
 
+// linkedIPv4 returns the IPv4 address embedded in the passed IPv6 address when
+// it is an IPv4-translated (RFC6145), IPv4-embedded (RFC6052), 6to4 (RFC3964),
+// or Teredo (RFC4380) address.  It returns nil for all other addresses.
+func linkedIPv4(netIP net.IP) net.IP {
+	switch {
+	case isRFC6145(netIP) || isRFC6052(netIP):
+		// last four bytes are the ip address
+		return netIP[12:16]
+
+	case isRFC3964(netIP):
+		return netIP[2:6]
+
+	case isRFC4380(netIP):
+		// teredo tunnels have the last 4 bytes as the v4 address XOR
+		// 0xff.
+		newIP := net.IP(make([]byte, 4))
+		for i, byte := range netIP[12:16] {
+			newIP[i] = byte ^ 0xff
+		}
+		return newIP
+	}
+	return nil
+}
+
 // GroupKey returns a string representing the network group an address is part
-// of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
-// "local" for a local address, and the string "unroutable" for an unroutable
-// address.
+// of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the network
+// name followed by the first 4 bits of the address for Tor v3 and I2P, the
+// string "local" for a local address, and the string "unroutable" for an
+// unroutable address.
 func (na *NetAddress) GroupKey() string {
+	switch na.Type {
+	case TorV3Address:
+		return fmt.Sprintf("torv3:%d", na.IP[0]>>4)
+	case I2PAddress:
+		return fmt.Sprintf("i2p:%d", na.IP[0]>>4)
+	}
+
 	netIP := net.IP(na.IP)
 	if isLocal(netIP) {
 		return "local"
@@ -240,23 +275,7 @@ func (na *NetAddress) GroupKey() string {
 	if na.Type == IPv4Address {
 		return netIP.Mask(net.CIDRMask(16, 32)).String()
 	}
-	if isRFC6145(netIP) || isRFC6052(netIP) {
-		// last four bytes are the ip address
-		newIP := netIP[12:16]
-		return newIP.Mask(net.CIDRMask(16, 32)).String()
-	}
-
-	if isRFC3964(netIP) {
-		newIP := netIP[2:6]
-		return newIP.Mask(net.CIDRMask(16, 32)).String()
-	}
-	if isRFC4380(netIP) {
-		// teredo tunnels have the last 4 bytes as the v4 address XOR
-		// 0xff.
-		newIP := net.IP(make([]byte, 4))
-		for i, byte := range netIP[12:16] {
-			newIP[i] = byte ^ 0xff
-		}
+	if newIP := linkedIPv4(netIP); newIP != nil {
 		return newIP.Mask(net.CIDRMask(16, 32)).String()
 	}
 
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"encoding/base32"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// torV3Suffix is the suffix of the host name of Tor onion services.
+	torV3Suffix = ".onion"
+
+	// torV3PubKeySize is the size of the ed25519 public key that identifies a
+	// Tor v3 onion service.
+	torV3PubKeySize = 32
+
+	// torV3ChecksumSize is the size of the checksum that is part of the host
+	// name of a Tor v3 onion service.
+	torV3ChecksumSize = 2
+
+	// torV3Version is the version byte that is part of the host name of a Tor
+	// v3 onion service.
+	torV3Version = 3
+
+	// torV3HostLen is the length of the base32-encoded portion of the host
+	// name of a Tor v3 onion service.  It encodes the public key, checksum,
+	// and version.
+	torV3HostLen = 56
+
+	// i2pSuffix is the suffix of the host name of I2P destinations which
+	// encode the destination hash.
+	i2pSuffix = ".b32.i2p"
+
+	// i2pHashSize is the size of the SHA-256 hash that identifies an I2P
+	// destination.
+	i2pHashSize = 32
+
+	// i2pHostLen is the length of the base32-encoded portion of the host name
+	// of an I2P destination.
+	i2pHostLen = 52
+)
+
+// overlayEncoding is the encoding used by the host names of both Tor v3 onion
+// services and I2P destinations.  The host names are lowercase, so they must be
+// converted to uppercase prior to decoding.
+var overlayEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// isOverlayNetwork returns whether or not the passed address type belongs to an
+// overlay network, such as Tor or I2P, that does not use IP addresses.
+func isOverlayNetwork(addrType NetAddressType) bool {
+	return addrType == TorV3Address || addrType == I2PAddress
+}
+
+// torV3Checksum returns the checksum of the provided Tor v3 onion service
+// public key as defined by the Tor v3 onion service specification.
+func torV3Checksum(pubKey []byte) []byte {
+	h := sha3.New256()
+	h.Write([]byte(".onion checksum"))
+	h.Write(pubKey)
+	h.Write([]byte{torV3Version})
+	return h.Sum(nil)[:torV3ChecksumSize]
+}
+
+// encodeTorV3Host returns the host name of the Tor v3 onion service identified
+// by the provided public key.
+func encodeTorV3Host(pubKey []byte) string {
+	data := make([]byte, 0, torV3PubKeySize+torV3ChecksumSize+1)
+	data = append(data, pubKey...)
+	data = append(data, torV3Checksum(pubKey)...)
+	data = append(data, torV3Version)
+	return strings.ToLower(overlayEncoding.EncodeToString(data)) + torV3Suffix
+}
+
+// decodeTorV3Host attempts to decode the provided host as a Tor v3 onion
+// service and returns its public key when successful.
+func decodeTorV3Host(host string) ([]byte, bool) {
+	if len(host) != torV3HostLen+len(torV3Suffix) ||
+		!strings.EqualFold(host[torV3HostLen:], torV3Suffix) {
+
+		return nil, false
+	}
+	data, err := overlayEncoding.DecodeString(strings.ToUpper(
+		host[:torV3HostLen]))
+	if err != nil {
+		return nil, false
+	}
+
+	pubKey := data[:torV3PubKeySize]
+	checksum := data[torV3PubKeySize : torV3PubKeySize+torV3ChecksumSize]
+	version := data[torV3PubKeySize+torV3ChecksumSize]
+	if version != torV3Version || !bytes.Equal(checksum, torV3Checksum(pubKey)) {
+		return nil, false
+	}
+	return pubKey, true
+}
+
+// encodeI2PHost returns the host name of the I2P destination identified by the
+// provided destination hash.
+func encodeI2PHost(hash []byte) string {
+	return strings.ToLower(overlayEncoding.EncodeToString(hash)) + i2pSuffix
+}
+
+// decodeI2PHost attempts to decode the provided host as an I2P destination and
+// returns its destination hash when successful.
+func decodeI2PHost(host string) ([]byte, bool) {
+	if len(host) != i2pHostLen+len(i2pSuffix) ||
+		!strings.EqualFold(host[i2pHostLen:], i2pSuffix) {
+
+		return nil, false
+	}
+	hash, err := overlayEncoding.DecodeString(strings.ToUpper(
+		host[:i2pHostLen]))
+	if err != nil || len(hash) != i2pHashSize {
+		return nil, false
+	}
+	return hash, true
+}
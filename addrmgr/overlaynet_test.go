@@ -0,0 +1,205 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// hexToBytes converts the passed hex string into bytes and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors in
+// the source code can be detected.  It will only (and must only) be called with
+// hard-coded values.
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// TestEncodeHostOverlay ensures Tor v3 onion service and I2P destination host
+// names are encoded and decoded as expected.
+func TestEncodeHostOverlay(t *testing.T) {
+	const (
+		onionHost = "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad" +
+			".onion"
+		i2pHost = "ukeu3k5oycgaauneqgtnvselmt4yemvoilkln7jpvamvfx7dnkdq.b32.i2p"
+	)
+	onionPubKey := hexToBytes("1d04a1d04a338c6e6ae970bfabee49049d6702250984" +
+		"ca950c01673f4ec034ad")
+	i2pHash := hexToBytes("a2894dabaec08c0051a481a6dac88b64f98232ae42d4b6fd2f" +
+		"a81952dfe36a87")
+
+	tests := []struct {
+		name     string         // test description
+		host     string         // host to encode
+		wantType NetAddressType // expected address type
+		want     []byte         // expected encoded address
+	}{{
+		name:     "tor v3 onion service",
+		host:     onionHost,
+		wantType: TorV3Address,
+		want:     onionPubKey,
+	}, {
+		name:     "tor v3 onion service uppercase",
+		host:     strings.ToUpper(onionHost),
+		wantType: TorV3Address,
+		want:     onionPubKey,
+	}, {
+		name:     "tor v3 onion service with bad checksum",
+		host:     "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaad.onion",
+		wantType: UnknownAddressType,
+	}, {
+		name: "tor v3 onion service with bad version",
+		host: strings.Replace(onionHost, "czad.onion", "czab.onion",
+			1),
+		wantType: UnknownAddressType,
+	}, {
+		name:     "tor v2 onion service",
+		host:     "aaaaaaaaaaaaaaaa.onion",
+		wantType: UnknownAddressType,
+	}, {
+		name:     "tor v3 onion service with invalid base32",
+		host:     strings.Replace(onionHost, "duck", "d!ck", 1),
+		wantType: UnknownAddressType,
+	}, {
+		name:     "i2p destination",
+		host:     i2pHost,
+		wantType: I2PAddress,
+		want:     i2pHash,
+	}, {
+		name:     "i2p destination with bad length",
+		host:     "ukeu3k5oycgaauneqgtnvselmt4yemvoilkln7jpvamvfx7dnkd.b32.i2p",
+		wantType: UnknownAddressType,
+	}, {
+		name:     "i2p named destination",
+		host:     "decred.i2p",
+		wantType: UnknownAddressType,
+	}}
+
+	for _, test := range tests {
+		addrType, addrBytes := EncodeHost(test.host)
+		if addrType != test.wantType {
+			t.Errorf("%q: unexpected address type -- got %v, want %v",
+				test.name, addrType, test.wantType)
+			continue
+		}
+		if !bytes.Equal(addrBytes, test.want) {
+			t.Errorf("%q: unexpected address bytes -- got %x, want %x",
+				test.name, addrBytes, test.want)
+			continue
+		}
+		if addrType == UnknownAddressType {
+			continue
+		}
+
+		// Ensure the address round trips through the network address key.
+		netAddr, err := NewNetAddressFromParams(addrType, addrBytes, 9108,
+			time.Now(), wire.SFNodeNetwork)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		wantKey := strings.ToLower(test.host) + ":9108"
+		if key := netAddr.Key(); key != wantKey {
+			t.Errorf("%q: unexpected key -- got %q, want %q", test.name, key,
+				wantKey)
+			continue
+		}
+		amgr := New("TestEncodeHostOverlay")
+		decoded, err := amgr.newNetAddressFromString(netAddr.Key())
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if decoded.Type != addrType || !bytes.Equal(decoded.IP, addrBytes) {
+			t.Errorf("%q: unexpected decoded address -- got %v (%x), want "+
+				"%v (%x)", test.name, decoded.Type, decoded.IP, addrType,
+				addrBytes)
+			continue
+		}
+		if !netAddr.IsRoutable() {
+			t.Errorf("%q: address is not routable", test.name)
+			continue
+		}
+	}
+
+	// Ensure overlay addresses with the wrong size are rejected.
+	_, err := NewNetAddressFromParams(TorV3Address, net.ParseIP("::1"), 9108,
+		time.Now(), wire.SFNodeNetwork)
+	if err == nil {
+		t.Fatal("did not receive error for tor v3 address with bad size")
+	}
+}
+
+// TestOverlayGroupKeyAndReach ensures addresses on overlay networks are grouped
+// and reachable as expected.
+func TestOverlayGroupKeyAndReach(t *testing.T) {
+	onionAddr, err := NewNetAddressFromParams(TorV3Address,
+		bytes.Repeat([]byte{0x12}, torV3PubKeySize), 9108, time.Now(),
+		wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	i2pAddr, err := NewNetAddressFromParams(I2PAddress,
+		bytes.Repeat([]byte{0xf0}, i2pHashSize), 0, time.Now(),
+		wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ipv4Addr := NewNetAddressFromIPPort(net.ParseIP("204.124.8.1"), 9108,
+		wire.SFNodeNetwork)
+
+	if key := onionAddr.GroupKey(); key != "torv3:1" {
+		t.Errorf("unexpected tor v3 group key -- got %q, want %q", key,
+			"torv3:1")
+	}
+	if key := i2pAddr.GroupKey(); key != "i2p:15" {
+		t.Errorf("unexpected i2p group key -- got %q, want %q", key, "i2p:15")
+	}
+
+	tests := []struct {
+		name   string
+		local  *NetAddress
+		remote *NetAddress
+		want   NetAddressReach
+	}{
+		{"onion to onion", onionAddr, onionAddr, Private},
+		{"i2p to i2p", i2pAddr, i2pAddr, Private},
+		{"onion to i2p", onionAddr, i2pAddr, Default},
+		{"ipv4 to onion", ipv4Addr, onionAddr, Ipv4},
+		{"onion to ipv4", onionAddr, ipv4Addr, Default},
+		{"i2p to ipv4", i2pAddr, ipv4Addr, Default},
+	}
+	for _, test := range tests {
+		reach := getRemoteReachabilityFromLocal(test.local, test.remote)
+		if reach != test.want {
+			t.Errorf("%q: unexpected reach -- got %v, want %v", test.name,
+				reach, test.want)
+		}
+	}
+
+	// Ensure a local onion address is advertised to remote peers when there
+	// are no better local addresses.
+	amgr := New("TestOverlayGroupKeyAndReach")
+	if err := amgr.AddLocalAddress(onionAddr, ManualPrio); err != nil {
+		t.Fatalf("unexpected error adding local address: %v", err)
+	}
+	for _, remote := range []*NetAddress{ipv4Addr, onionAddr} {
+		got := amgr.GetBestLocalAddress(remote, natfAny)
+		if got.Key() != onionAddr.Key() {
+			t.Errorf("unexpected best local address for %v -- got %v, want "+
+				"%v", remote, got, onionAddr)
+		}
+	}
+}
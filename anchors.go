@@ -0,0 +1,119 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// anchorsFilename is the filename used to store the anchor peers.
+	anchorsFilename = "anchors.json"
+
+	// anchorsVersion is the current version of the serialized anchor peers.
+	anchorsVersion = 1
+)
+
+// serializedAnchors is the JSON representation of the anchor peers.
+type serializedAnchors struct {
+	Version int      `json:"version"`
+	Addrs   []string `json:"addrs"`
+}
+
+// anchorPeers houses the addresses of the outbound peers that were in use when
+// the server was last shutdown.
+//
+// These peers are reconnected to first on startup in order to preserve the
+// connections that were already established to functioning peers across
+// restarts.  This makes it harder for an attacker that is able to cause a
+// restart, or simply waits for one to happen, to eclipse the server by
+// occupying the freshly selected outbound connections.
+type anchorPeers struct {
+	filePath string
+
+	// addrs houses the anchor peer addresses that have not been connected to
+	// yet and is protected by the mutex.
+	mtx   sync.Mutex
+	addrs []string
+}
+
+// newAnchorPeers returns a new set of anchor peers that is persisted to a file
+// in the provided data directory.
+//
+// Load must be called to restore the anchor peers saved during the previous
+// shutdown.
+func newAnchorPeers(dataDir string) *anchorPeers {
+	return &anchorPeers{filePath: filepath.Join(dataDir, anchorsFilename)}
+}
+
+// Load restores the anchor peers saved during the previous shutdown and then
+// removes the file they were saved to so they are not reused in the case the
+// server does not shutdown cleanly.  It is not an error if there are no saved
+// anchor peers.
+func (a *anchorPeers) Load() error {
+	serialized, err := os.ReadFile(a.filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err := os.Remove(a.filePath); err != nil {
+		return err
+	}
+
+	var sa serializedAnchors
+	if err := json.Unmarshal(serialized, &sa); err != nil {
+		return fmt.Errorf("unable to decode anchor peers %s: %w", a.filePath,
+			err)
+	}
+	if sa.Version != anchorsVersion {
+		return fmt.Errorf("unknown anchor peers version %d in %s", sa.Version,
+			a.filePath)
+	}
+
+	a.mtx.Lock()
+	a.addrs = sa.Addrs
+	a.mtx.Unlock()
+	return nil
+}
+
+// Save writes the provided anchor peer addresses to disk so they can be
+// restored on the next startup.
+func (a *anchorPeers) Save(addrs []string) error {
+	sa := serializedAnchors{Version: anchorsVersion, Addrs: addrs}
+	serialized, err := json.Marshal(&sa)
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and then move it into place.
+	tmpFile := a.filePath + ".new"
+	if err := os.WriteFile(tmpFile, serialized, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, a.filePath)
+}
+
+// Next removes and returns the next anchor peer address to connect to.  The
+// flag is false when there are no remaining anchor peers.
+//
+// This function is safe for concurrent access.
+func (a *anchorPeers) Next() (string, bool) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if len(a.addrs) == 0 {
+		return "", false
+	}
+	addr := a.addrs[0]
+	a.addrs = a.addrs[1:]
+	return addr, true
+}
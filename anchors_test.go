@@ -0,0 +1,66 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAnchorPeers ensures anchor peers are saved, restored in order exactly
+// once, and that invalid anchor peer files are rejected.
+func TestAnchorPeers(t *testing.T) {
+	t.Parallel()
+
+	// Ensure loading without any saved anchor peers is not an error.
+	dir := t.TempDir()
+	anchors := newAnchorPeers(dir)
+	if err := anchors.Load(); err != nil {
+		t.Fatalf("Load: unexpected error without saved anchors: %v", err)
+	}
+	if addr, ok := anchors.Next(); ok {
+		t.Fatalf("Next: unexpected anchor %q without saved anchors", addr)
+	}
+
+	// Ensure saved anchor peers are restored in order.
+	want := []string{"203.0.113.1:9108", "[2001:db8::1]:9108"}
+	if err := anchors.Save(want); err != nil {
+		t.Fatalf("Save: unexpected error: %v", err)
+	}
+	anchors = newAnchorPeers(dir)
+	if err := anchors.Load(); err != nil {
+		t.Fatalf("Load: unexpected error: %v", err)
+	}
+	for i, wantAddr := range want {
+		addr, ok := anchors.Next()
+		if !ok || addr != wantAddr {
+			t.Fatalf("Next #%d: got %q (ok %v), want %q", i, addr, ok,
+				wantAddr)
+		}
+	}
+	if addr, ok := anchors.Next(); ok {
+		t.Fatalf("Next: unexpected anchor %q after all were used", addr)
+	}
+
+	// Ensure the anchor peers file is removed once loaded so the anchors are
+	// not reused after an unclean shutdown.
+	filePath := filepath.Join(dir, anchorsFilename)
+	if _, err := os.Stat(filePath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("anchor peers file still exists after load: %v", err)
+	}
+
+	// Ensure anchor peers files with an unknown version or invalid encoding
+	// are rejected.
+	for _, contents := range []string{`{"version":2,"addrs":[]}`, `{`} {
+		if err := os.WriteFile(filePath, []byte(contents), 0600); err != nil {
+			t.Fatalf("unable to write anchor peers file: %v", err)
+		}
+		if err := newAnchorPeers(dir).Load(); err == nil {
+			t.Fatalf("Load: did not fail for anchor peers file %q", contents)
+		}
+	}
+}
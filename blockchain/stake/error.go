@@ -1,5 +1,5 @@
 // Copyright (c) 2014 Conformal Systems LLC.
-// Copyright (c) 2015-2021 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -214,6 +214,10 @@ const (
 	// the block.
 	ErrUnknownTicketSpent = ErrorKind("ErrUnknownTicketSpent")
 
+	// ErrInvalidSnapshotRecord indicates that a ticket database record of a
+	// chain state snapshot is malformed or of an unsupported version.
+	ErrInvalidSnapshotRecord = ErrorKind("ErrInvalidSnapshotRecord")
+
 	// ErrTAddInvalidTxVersion indicates that this transaction has the
 	// wrong version.
 	ErrTAddInvalidTxVersion = ErrorKind("ErrTAddInvalidTxVersion")
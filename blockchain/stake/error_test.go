@@ -1,5 +1,5 @@
 // Copyright (c) 2014 Conformal Systems LLC.
-// Copyright (c) 2015-2021 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -66,6 +66,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrMissingTicket, "ErrMissingTicket"},
 		{ErrDuplicateTicket, "ErrDuplicateTicket"},
 		{ErrUnknownTicketSpent, "ErrUnknownTicketSpent"},
+		{ErrInvalidSnapshotRecord, "ErrInvalidSnapshotRecord"},
 		{ErrTAddInvalidTxVersion, "ErrTAddInvalidTxVersion"},
 		{ErrTAddInvalidCount, "ErrTAddInvalidCount"},
 		{ErrTAddInvalidVersion, "ErrTAddInvalidVersion"},
@@ -0,0 +1,191 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stake
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/blockchain/stake/v5/internal/dbnamespace"
+	"github.com/decred/dcrd/blockchain/stake/v5/internal/ticketdb"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database/v3"
+)
+
+// SnapshotBucket identifies the part of the ticket database that a record of a
+// chain state snapshot belongs to.
+type SnapshotBucket uint8
+
+// These constants define the parts of the ticket database that are included in
+// chain state snapshots.
+const (
+	// SnapshotVersion is the record that houses the version of the ticket
+	// database.  It has an empty key.
+	SnapshotVersion SnapshotBucket = iota
+
+	// SnapshotBestState is the record that houses the best chain state from
+	// the perspective of the ticket database.  It has an empty key.
+	SnapshotBestState
+
+	// SnapshotLiveTickets are the records for the live tickets keyed by their
+	// hash.
+	SnapshotLiveTickets
+
+	// SnapshotMissedTickets are the records for the missed tickets keyed by
+	// their hash.
+	SnapshotMissedTickets
+
+	// SnapshotRevokedTickets are the records for the revoked tickets keyed by
+	// their hash.
+	SnapshotRevokedTickets
+
+	// SnapshotBlockUndo are the records for the data needed to undo the
+	// changes made by main chain blocks keyed by their height.
+	SnapshotBlockUndo
+
+	// SnapshotNewTickets are the records for the tickets purchased in main
+	// chain blocks keyed by their height.
+	SnapshotNewTickets
+)
+
+// ticketSnapshotBuckets maps the snapshot buckets for the individual tickets to
+// the database buckets that house them.
+var ticketSnapshotBuckets = []struct {
+	bucket SnapshotBucket
+	name   []byte
+}{
+	{SnapshotLiveTickets, dbnamespace.LiveTicketsBucketName},
+	{SnapshotMissedTickets, dbnamespace.MissedTicketsBucketName},
+	{SnapshotRevokedTickets, dbnamespace.RevokedTicketsBucketName},
+}
+
+// heightSnapshotBuckets maps the snapshot buckets for the per-block data to the
+// database buckets that house them.
+var heightSnapshotBuckets = []struct {
+	bucket SnapshotBucket
+	name   []byte
+}{
+	{SnapshotBlockUndo, dbnamespace.StakeBlockUndoDataBucketName},
+	{SnapshotNewTickets, dbnamespace.TicketsInBlockBucketName},
+}
+
+// ForEachSnapshotRecord invokes the provided function with all of the records
+// of the ticket database that are needed to resume from its current best state
+// in the order they must be stored.  The per-block undo and new tickets data is
+// limited to the blocks at or after the provided minimum height.
+//
+// The records are provided in a deterministic order so that snapshots of the
+// same state are always identical.  The key and value passed to the function
+// are only valid for the duration of the call.
+func ForEachSnapshotRecord(dbTx database.Tx, minHeight uint32, fn func(bucket SnapshotBucket, key, value []byte) error) error {
+	info, err := ticketdb.DbFetchDatabaseInfo(dbTx)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		str := "ticket database is not initialized"
+		return stakeRuleError(ErrDatabaseCorrupt, str)
+	}
+	var version [4]byte
+	dbnamespace.ByteOrder.PutUint32(version[:], info.Version)
+	if err := fn(SnapshotVersion, nil, version[:]); err != nil {
+		return err
+	}
+
+	meta := dbTx.Metadata()
+	state := meta.Get(dbnamespace.StakeChainStateKeyName)
+	if err := fn(SnapshotBestState, nil, state); err != nil {
+		return err
+	}
+
+	for _, b := range ticketSnapshotBuckets {
+		err := meta.Bucket(b.name).ForEach(func(k, v []byte) error {
+			return fn(b.bucket, k, v)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, b := range heightSnapshotBuckets {
+		err := meta.Bucket(b.name).ForEach(func(k, v []byte) error {
+			if len(k) == 4 && dbnamespace.ByteOrder.Uint32(k) < minHeight {
+				return nil
+			}
+			return fn(b.bucket, k, v)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PutSnapshotRecord stores the provided record of a chain state snapshot that
+// was produced by ForEachSnapshotRecord in the ticket database.  The database
+// must have already been initialized with InitDatabaseState.
+//
+// The best state is not checked against the rest of the records, so callers
+// must ensure the snapshot is authentic and then load the best node with
+// LoadBestNode to ensure the resulting state is consistent.
+func PutSnapshotRecord(dbTx database.Tx, bucket SnapshotBucket, key, value []byte) error {
+	meta := dbTx.Metadata()
+	switch bucket {
+	case SnapshotVersion:
+		info, err := ticketdb.DbFetchDatabaseInfo(dbTx)
+		if err != nil {
+			return err
+		}
+		if info == nil {
+			str := "ticket database is not initialized"
+			return stakeRuleError(ErrDatabaseCorrupt, str)
+		}
+		if len(key) != 0 || len(value) != 4 {
+			str := "malformed ticket database version snapshot record"
+			return stakeRuleError(ErrInvalidSnapshotRecord, str)
+		}
+		version := dbnamespace.ByteOrder.Uint32(value)
+		if version != info.Version {
+			str := fmt.Sprintf("unsupported ticket database version %d in "+
+				"snapshot (expected %d)", version, info.Version)
+			return stakeRuleError(ErrInvalidSnapshotRecord, str)
+		}
+		return nil
+
+	case SnapshotBestState:
+		if len(key) != 0 || len(value) == 0 {
+			str := "malformed ticket database best state snapshot record"
+			return stakeRuleError(ErrInvalidSnapshotRecord, str)
+		}
+		return meta.Put(dbnamespace.StakeChainStateKeyName, value)
+	}
+
+	for _, b := range ticketSnapshotBuckets {
+		if b.bucket != bucket {
+			continue
+		}
+		if len(key) != chainhash.HashSize || len(value) != 5 {
+			str := fmt.Sprintf("malformed ticket snapshot record for key %x",
+				key)
+			return stakeRuleError(ErrInvalidSnapshotRecord, str)
+		}
+		return meta.Bucket(b.name).Put(key, value)
+	}
+
+	for _, b := range heightSnapshotBuckets {
+		if b.bucket != bucket {
+			continue
+		}
+		if len(key) != 4 {
+			str := fmt.Sprintf("malformed per-block ticket snapshot record "+
+				"for key %x", key)
+			return stakeRuleError(ErrInvalidSnapshotRecord, str)
+		}
+		return meta.Bucket(b.name).Put(key, value)
+	}
+
+	str := fmt.Sprintf("unknown ticket database snapshot bucket %d", bucket)
+	return stakeRuleError(ErrInvalidSnapshotRecord, str)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stake
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/stake/v5/internal/dbnamespace"
+	"github.com/decred/dcrd/blockchain/stake/v5/internal/ticketdb"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+)
+
+// snapshotRecord is a ticket database snapshot record used in the tests.
+type snapshotRecord struct {
+	bucket     SnapshotBucket
+	key, value []byte
+}
+
+// collectSnapshotRecords returns all of the ticket database snapshot records of
+// the provided database.
+func collectSnapshotRecords(t *testing.T, db database.DB, minHeight uint32) []snapshotRecord {
+	t.Helper()
+
+	var records []snapshotRecord
+	err := db.View(func(dbTx database.Tx) error {
+		return ForEachSnapshotRecord(dbTx, minHeight, func(bucket SnapshotBucket, k, v []byte) error {
+			records = append(records, snapshotRecord{
+				bucket: bucket,
+				key:    bytes.Clone(k),
+				value:  bytes.Clone(v),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error collecting snapshot records: %v", err)
+	}
+	return records
+}
+
+// TestSnapshotRecords ensures the ticket database snapshot records round trip
+// through a new database and that malformed records are rejected.
+func TestSnapshotRecords(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.RegNetParams()
+	newDb := func() database.DB {
+		db, err := database.Create(testDbType, t.TempDir(), params.Net)
+		if err != nil {
+			t.Fatalf("error creating db: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		err = db.Update(func(dbTx database.Tx) error {
+			_, err := InitDatabaseState(dbTx, params, &params.GenesisHash)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("error initializing db: %v", err)
+		}
+		return db
+	}
+
+	// Populate the source database with tickets in all of the buckets along
+	// with undo and new ticket data for several blocks.
+	const tipHeight = 5
+	srcDb := newDb()
+	err := srcDb.Update(func(dbTx database.Tx) error {
+		buckets := [][]byte{
+			dbnamespace.LiveTicketsBucketName,
+			dbnamespace.MissedTicketsBucketName,
+			dbnamespace.RevokedTicketsBucketName,
+		}
+		for i, bucket := range buckets {
+			hash := chainhash.Hash{byte(i + 1)}
+			err := ticketdb.DbPutTicket(dbTx, bucket, &hash, uint32(i), i == 1,
+				i == 2, false, false)
+			if err != nil {
+				return err
+			}
+		}
+		for height := uint32(1); height <= tipHeight; height++ {
+			ticket := chainhash.Hash{0xff, byte(height)}
+			err := ticketdb.DbPutBlockUndoData(dbTx, height,
+				[]ticketdb.UndoTicketData{{TicketHash: ticket, TicketHeight: height}})
+			if err != nil {
+				return err
+			}
+			err = ticketdb.DbPutNewTickets(dbTx, height,
+				ticketdb.TicketHashes{ticket})
+			if err != nil {
+				return err
+			}
+		}
+		return ticketdb.DbPutBestState(dbTx, ticketdb.BestChainState{
+			Hash:        chainhash.Hash{0x01},
+			Height:      tipHeight,
+			Live:        1,
+			Missed:      1,
+			Revoked:     1,
+			PerBlock:    params.VotesPerBlock(),
+			NextWinners: make([]chainhash.Hash, params.VotesPerBlock()),
+		})
+	})
+	if err != nil {
+		t.Fatalf("error populating db: %v", err)
+	}
+
+	// Ensure the per-block data prior to the minimum height is excluded.
+	const minHeight = 3
+	records := collectSnapshotRecords(t, srcDb, minHeight)
+	for _, r := range records {
+		if r.bucket != SnapshotBlockUndo && r.bucket != SnapshotNewTickets {
+			continue
+		}
+		if height := dbnamespace.ByteOrder.Uint32(r.key); height < minHeight {
+			t.Fatalf("snapshot includes data for height %d", height)
+		}
+	}
+
+	// Ensure the records are the same once stored in a new database.
+	dstDb := newDb()
+	err = dstDb.Update(func(dbTx database.Tx) error {
+		for _, r := range records {
+			err := PutSnapshotRecord(dbTx, r.bucket, r.key, r.value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error storing snapshot records: %v", err)
+	}
+	gotRecords := collectSnapshotRecords(t, dstDb, minHeight)
+	if len(gotRecords) != len(records) {
+		t.Fatalf("unexpected number of records: got %d, want %d",
+			len(gotRecords), len(records))
+	}
+	for i := range records {
+		got, want := gotRecords[i], records[i]
+		if got.bucket != want.bucket || !bytes.Equal(got.key, want.key) ||
+			!bytes.Equal(got.value, want.value) {
+
+			t.Fatalf("mismatched record %d: got %+v, want %+v", i, got, want)
+		}
+	}
+
+	// Ensure malformed records and unsupported versions are rejected.
+	badRecords := []snapshotRecord{
+		{bucket: SnapshotVersion, value: []byte{0xff, 0, 0, 0}},
+		{bucket: SnapshotVersion, value: []byte{1}},
+		{bucket: SnapshotBestState},
+		{bucket: SnapshotLiveTickets, key: []byte{1}, value: make([]byte, 5)},
+		{bucket: SnapshotMissedTickets, key: make([]byte, 32), value: nil},
+		{bucket: SnapshotBlockUndo, key: []byte{1, 2}},
+		{bucket: SnapshotNewTickets + 1, key: []byte{1, 2, 3, 4}},
+	}
+	for i, r := range badRecords {
+		err := dstDb.Update(func(dbTx database.Tx) error {
+			return PutSnapshotRecord(dbTx, r.bucket, r.key, r.value)
+		})
+		if !errors.Is(err, ErrInvalidSnapshotRecord) {
+			t.Errorf("bad record %d: unexpected error -- got %v, want %v", i,
+				err, ErrInvalidSnapshotRecord)
+		}
+	}
+}
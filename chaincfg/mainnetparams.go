@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -145,6 +145,14 @@ func MainNetParams() *Params {
 		// Height: 1030629
 		MinKnownChainWork: hexToBigInt("000000000000000000000000000000000000000000243868232c14b8224643b6"),
 
+		// AssumeUtxo houses the snapshots of the chain state that have been
+		// externally verified and may be imported to bootstrap new nodes.
+		// This is intended to be updated periodically with new releases.
+		//
+		// No snapshots have been reviewed for inclusion yet, so importing a
+		// snapshot is not possible until they are added.
+		AssumeUtxo: nil,
+
 		// The miner confirmation window is defined as:
 		//   target proof of work timespan / target proof of work spacing
 		RuleChangeActivationQuorum:     4032, // 10 % of RuleChangeActivationInterval * TicketsPerBlock
@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -31,6 +31,19 @@ type Checkpoint struct {
 	Hash   *chainhash.Hash
 }
 
+// AssumeUtxo identifies an externally verified snapshot of the UTXO set, the
+// stake ticket database, and the treasury state as of a specific main chain
+// block.
+//
+// SnapshotHash commits to the entire serialized snapshot, so nodes that import
+// a snapshot with a matching hash are guaranteed to have the same state that
+// fully validating nodes had at the block.
+type AssumeUtxo struct {
+	Height       int64
+	BlockHash    chainhash.Hash
+	SnapshotHash chainhash.Hash
+}
+
 // Vote describes a voting instance.  It is self-describing so that the UI can
 // be directly implemented using the fields.  Mask determines which bits can be
 // used.  Bits are enumerated and must be consecutive.  Each vote requires one
@@ -433,6 +446,14 @@ type Params struct {
 	// with new releases.  It may be nil for networks that do not require it.
 	MinKnownChainWork *big.Int
 
+	// AssumeUtxo houses the snapshots of the chain state that have been
+	// externally verified and may therefore be imported in order to bootstrap
+	// new nodes without first validating the entire history, which is then
+	// validated in the background instead.  This is intended to be updated
+	// periodically with new releases.  It may be empty for networks that do
+	// not require it.
+	AssumeUtxo []AssumeUtxo
+
 	// These fields are related to voting on consensus rule changes as
 	// defined by BIP0009.
 	//
@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2025 The Decred developers
+// Copyright (c) 2018-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -139,6 +139,12 @@ func RegNetParams() *Params {
 		// Not set for regression test network since its chain is dynamic.
 		MinKnownChainWork: nil,
 
+		// AssumeUtxo houses the snapshots of the chain state that have been
+		// externally verified and may be imported to bootstrap new nodes.
+		//
+		// Not set for regression test network since its chain is dynamic.
+		AssumeUtxo: nil,
+
 		// Consensus rule change deployments.
 		//
 		// The miner confirmation window is defined as:
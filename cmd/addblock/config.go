@@ -42,6 +42,8 @@ type config struct {
 	InFile            string `short:"i" long:"infile" description:"File containing the block(s)"`
 	NoExistsAddrIndex bool   `long:"noexistsaddrindex" description:"Do not build a full index of which addresses were ever seen on the blockchain"`
 	TxIndex           bool   `long:"txindex" description:"Build a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
+	AddrIndex         bool   `long:"addrindex" description:"Build a full address-based transaction index which makes the searchrawtransactions RPC available (requires --txindex)"`
+	SpendIndex        bool   `long:"spendindex" description:"Build a full spent output index which makes the getspendinginfo RPC available"`
 	Progress          int    `short:"p" long:"progress" description:"Show a progress message each time this number of seconds have passed -- Use 0 to disable progress announcements"`
 }
 
@@ -119,6 +121,15 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// The address index relies on the transaction index.
+	if cfg.AddrIndex && !cfg.TxIndex {
+		str := "%s: the --addrindex option requires the --txindex option"
+		err := fmt.Errorf(str, "loadConfig")
+		fmt.Fprintln(os.Stderr, err)
+		parser.WriteHelp(os.Stderr)
+		return nil, nil, err
+	}
+
 	// Append the network type to the data directory so it is "namespaced"
 	// per network.  In addition to the block database, there are other
 	// pieces of data that are saved to disk such as address manager state.
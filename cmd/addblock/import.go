@@ -54,6 +54,8 @@ type blockImporter struct {
 	startTime         time.Time
 
 	txIndex         *indexers.TxIndex
+	addrIndex       *indexers.AddrIndex
+	spendIndex      *indexers.SpendIndex
 	existsAddrIndex *indexers.ExistsAddrIndex
 	cancel          context.CancelFunc
 }
@@ -339,6 +341,8 @@ func newBlockImporter(ctx context.Context, db database.DB, utxoDb *leveldb.DB, r
 
 	// Create the various indexes as needed.
 	var txIndex *indexers.TxIndex
+	var addrIndex *indexers.AddrIndex
+	var spendIndex *indexers.SpendIndex
 	var existsAddrIndex *indexers.ExistsAddrIndex
 	if cfg.TxIndex {
 		log.Info("Transaction index is enabled")
@@ -348,6 +352,22 @@ func newBlockImporter(ctx context.Context, db database.DB, utxoDb *leveldb.DB, r
 			return nil, err
 		}
 	}
+	if cfg.AddrIndex {
+		log.Info("Address index is enabled")
+
+		addrIndex, err = indexers.NewAddrIndex(subber, db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SpendIndex {
+		log.Info("Spend index is enabled")
+
+		spendIndex, err = indexers.NewSpendIndex(subber, db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if !cfg.NoExistsAddrIndex {
 		log.Info("Exists address index is enabled")
 		existsAddrIndex, err = indexers.NewExistsAddrIndex(subber, db, queryer)
@@ -372,6 +392,8 @@ func newBlockImporter(ctx context.Context, db database.DB, utxoDb *leveldb.DB, r
 		lastLogTime:     time.Now(),
 		startTime:       time.Now(),
 		txIndex:         txIndex,
+		addrIndex:       addrIndex,
+		spendIndex:      spendIndex,
 		existsAddrIndex: existsAddrIndex,
 		cancel:          cancel,
 	}, nil
@@ -0,0 +1,66 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// genrpcauth generates a salted and hashed RPC credential suitable for use
+// with the dcrd --rpcauth option so the password does not need to be stored in
+// the configuration file.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/decred/dcrd/internal/rpcserver"
+	"golang.org/x/term"
+)
+
+var group = flag.String("group", rpcserver.AdminGroupName,
+	"permission group of the user")
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format, args...)
+	os.Exit(1)
+}
+
+func readPassword(prompt string) []byte {
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprint(os.Stderr, "\n")
+	if err != nil {
+		fatalf("unable to read password: %v\n", err)
+	}
+	return password
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: genrpcauth [-group=<group>] "+
+			"<username>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	username := flag.Arg(0)
+	if username == "" || strings.Contains(username, ":") {
+		fatalf("username must not be empty or contain a colon\n")
+	}
+
+	password := readPassword("Password: ")
+	confirm := readPassword("Confirm password: ")
+	if !bytes.Equal(password, confirm) {
+		fatalf("passwords do not match\n")
+	}
+	if len(password) == 0 {
+		fatalf("password must not be empty\n")
+	}
+
+	credential := rpcserver.GenerateAuthCredential(username, string(password))
+	fmt.Printf("rpcauth=%s:%s\n", credential, *group)
+}
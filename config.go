@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -28,7 +28,9 @@ import (
 	_ "github.com/decred/dcrd/database/v3/ffldb"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/rpcserver"
 	"github.com/decred/dcrd/internal/version"
+	"github.com/decred/dcrd/internal/zmq"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 	"github.com/decred/dcrd/sampleconfig"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
@@ -50,12 +52,16 @@ const (
 	defaultUtxoCacheMaxSize = 150
 	minUtxoCacheMaxSize     = 25
 	maxUtxoCacheMaxSize     = 32768 // 32 GiB
+	minPruneTarget          = 1024  // 1 GiB
 
 	// Defaults for RPC server options and policy.
 	defaultTLSCurve             = "P-256"
 	defaultMaxRPCClients        = 10
 	defaultMaxRPCWebsockets     = 25
 	defaultMaxRPCConcurrentReqs = 20
+	defaultRPCRateLimit         = 20
+	defaultRPCRateBurst         = 100
+	defaultRPCUnixMode          = "0600"
 
 	// Defaults for P2P network options.
 	defaultMaxSameIP       = 5
@@ -69,6 +75,8 @@ const (
 
 	// Defaults for relay and mempool policy options.
 	defaultMaxOrphanTransactions = 100
+	defaultMaxMempoolSize        = 300
+	minMaxMempoolSize            = 5
 	defaultAllowOldVotes         = false
 
 	// Defaults for mining options and policy.
@@ -80,6 +88,8 @@ const (
 
 	// Defaults for indexing options.
 	defaultTxIndex           = false
+	defaultAddrIndex         = false
+	defaultSpendIndex        = false
 	defaultNoExistsAddrIndex = false
 
 	// Authorization types.
@@ -122,22 +132,30 @@ type config struct {
 	Profile          string `long:"profile" description:"Enable HTTP profiling on given [addr:]port -- NOTE port must be between 1024 and 65536"`
 	CPUProfile       string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
 	MemProfile       string `long:"memprofile" description:"Write mem profile to the specified file"`
+	MetricsListen    string `long:"metricslisten" description:"Serve Prometheus metrics via HTTP at /metrics on the given [addr:]port -- NOTE port must be between 1024 and 65536"`
 	TestNet          bool   `long:"testnet" description:"Use the test network"`
 	SimNet           bool   `long:"simnet" description:"Use the simulation test network"`
 	RegNet           bool   `long:"regnet" description:"Use the regression test network"`
 	DebugLevel       string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
 	SigCacheMaxSize  uint   `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
 	UtxoCacheMaxSize uint   `long:"utxocachemaxsize" description:"The maximum size in MiB of the utxo cache; (min: 25, max: 32768)"`
+	Prune            uint64 `long:"prune" description:"Delete old block data as needed to keep the stored blocks under the specified target size in MiB while retaining recent blocks; 0 disables pruning (min: 1024) -- NOTE: Not compatible with --txindex, --addrindex, or --spendindex"`
+	LoadSnapshot     string `long:"loadsnapshot" description:"Initialize a new data directory from the chain state snapshot in the specified file and validate the history prior to it in the background -- NOTE: The snapshot hash must match one defined by the network parameters and no network defines any yet"`
 
 	// RPC server options and policy.
-	DisableRPC           bool     `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
-	RPCListeners         []string `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
+	DisableRPC           bool     `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass, rpclimituser/rpclimitpass, or rpcauth is specified"`
+	RPCListeners         []string `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109) or a Unix domain socket in the form unix:<path>"`
+	RPCUnixMode          string   `long:"rpcunixmode" description:"File permissions in octal for RPC Unix domain sockets"`
+	RPCUnixOwner         string   `long:"rpcunixowner" description:"Owner of RPC Unix domain sockets in the form <user>[:<group>] by name or numeric ID"`
 	RPCUser              string   `short:"u" long:"rpcuser" description:"Username for RPC connections"`
 	RPCPass              string   `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
 	RPCAuthType          string   `long:"authtype" description:"Method for RPC client authentication (basic or clientcert)"`
 	RPCClientCAs         string   `long:"clientcafile" description:"File containing Certificate Authorities to verify TLS client certificates; requires authtype=clientcert"`
 	RPCLimitUser         string   `long:"rpclimituser" description:"Username for limited RPC connections"`
 	RPCLimitPass         string   `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
+	RPCAuth              []string `long:"rpcauth" default-mask:"-" description:"Add an RPC user with a hashed credential and permission group in the form <username>:<salt>$<hash>:<group> -- NOTE: Use the genrpcauth utility to generate the credential"`
+	RPCGroups            []string `long:"rpcgroup" description:"Add an RPC permission group in the form <group>:<method>[,<method>...] -- NOTE: The builtin admin and limited groups may be included as @admin and @limited"`
+	RPCClientCertGroups  []string `long:"rpcclientcert" description:"Map the subject of TLS client certificates to an RPC permission group in the form <subject>:<group> (e.g. CN=dcrwallet:admin); requires authtype=clientcert"`
 	RPCCert              string   `long:"rpccert" description:"File containing the certificate file"`
 	RPCKey               string   `long:"rpckey" description:"File containing the certificate key"`
 	TLSCurve             string   `long:"tlscurve" description:"Curve to use when generating TLS keypairs"`
@@ -146,8 +164,14 @@ type config struct {
 	RPCMaxClients        int      `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
 	RPCMaxWebsockets     int      `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
 	RPCMaxConcurrentReqs int      `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	RPCRateLimit         float64  `long:"rpcratelimit" description:"Sustained request weight per second permitted for each RPC user and remote IP outside of the admin permission group -- 0 to disable"`
+	RPCRateBurst         float64  `long:"rpcrateburst" description:"Max request weight each rate limited RPC user and remote IP may accumulate for bursts of requests"`
+	RESTListen           string   `long:"restlisten" description:"Serve the unauthenticated read-only REST interface via HTTP at /rest/ on the given [addr:]port -- NOTE port must be between 1024 and 65536"`
 
-	// P2P proxy and Tor settings.
+	// ZeroMQ compatible publisher options.
+	ZMQPubEndpoints []string `long:"zmqpub" description:"Add a ZeroMQ compatible endpoint to publish the rawblock, hashblock, rawtx, hashtx, and blockdisconnected topics on (tcp://[addr]:port or ipc://path)"`
+
+	// P2P proxy, Tor, and I2P settings.
 	Proxy          string `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser      string `long:"proxyuser" description:"Username for proxy server"`
 	ProxyPass      string `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
@@ -156,6 +180,7 @@ type config struct {
 	OnionProxyPass string `long:"onionpass" default-mask:"-" description:"Password for onion proxy server"`
 	NoOnion        bool   `long:"noonion" description:"Disable connecting to tor hidden services"`
 	TorIsolation   bool   `long:"torisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection"`
+	I2PProxy       string `long:"i2pproxy" description:"Connect to I2P destinations via SOCKS5 proxy (eg. 127.0.0.1:4447)"`
 
 	// P2P network options.
 	AddPeers        []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
@@ -166,6 +191,8 @@ type config struct {
 	MaxPeers        int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
 	DialTimeout     time.Duration `long:"dialtimeout" description:"How long to wait for TCP connection completion.  Valid time units are {s, m, h}.  Minimum 1 second"`
 	PeerIdleTimeout time.Duration `long:"peeridletimeout" description:"The duration of inactivity before a peer is timed out.  Valid time units are {s,m,h}.  Minimum 15 seconds"`
+	NoP2PEncryption bool          `long:"nop2pencryption" description:"Disable opportunistic encryption of connections with peers that support it"`
+	ASMap           string        `long:"asmap" description:"File containing a map of IP addresses to the autonomous system numbers (ASNs) that announce them in the compact binary trie format used by Bitcoin Core -- When set, known addresses and outbound peers are diversified by ASN instead of by network prefix"`
 
 	// P2P network discovery options.
 	DisableSeeders bool     `long:"noseeders" description:"Disable seeding for peer discovery"`
@@ -190,7 +217,10 @@ type config struct {
 	FreeTxRelayLimit float64 `long:"limitfreerelay" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
 	NoRelayPriority  bool    `long:"norelaypriority" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
 	MaxOrphanTxs     int     `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	MaxMempool       uint    `long:"maxmempool" description:"The maximum size in MiB of the transactions to keep in the memory pool; the lowest fee rate transactions are evicted when it is exceeded (min: 5)"`
+	NoPersistMempool bool    `long:"nopersistmempool" description:"Do not save the memory pool to disk on shutdown and reload it on startup"`
 	BlocksOnly       bool    `long:"blocksonly" description:"Do not accept transactions from remote peers"`
+	StemRelay        bool    `long:"stemrelay" description:"Relay transactions submitted via RPC and transactions received in the stem phase from other peers along a randomly selected path of outbound peers before they are diffused to the rest of the network in order to obscure their origin"`
 	AcceptNonStd     bool    `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network"`
 	RejectNonStd     bool    `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network"`
 	AllowOldVotes    bool    `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
@@ -209,6 +239,10 @@ type config struct {
 	// Indexing options.
 	TxIndex             bool `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
 	DropTxIndex         bool `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits"`
+	AddrIndex           bool `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions RPC available (requires --txindex)"`
+	DropAddrIndex       bool `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits"`
+	SpendIndex          bool `long:"spendindex" description:"Maintain a full spent output index which makes the getspendinginfo RPC available"`
+	DropSpendIndex      bool `long:"dropspendindex" description:"Deletes the spent output index from the database on start up and then exits"`
 	NoExistsAddrIndex   bool `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used"`
 	DropExistsAddrIndex bool `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits"`
 
@@ -222,10 +256,17 @@ type config struct {
 	onionlookup   func(string) ([]net.IP, error)
 	lookup        func(string) ([]net.IP, error)
 	oniondial     func(context.Context, string, string) (net.Conn, error)
+	i2pdial       func(context.Context, string, string) (net.Conn, error)
 	dial          func(context.Context, string, string) (net.Conn, error)
 	miningAddrs   []stdaddr.Address
 	minRelayTxFee dcrutil.Amount
 	whitelists    []*net.IPNet
+	rpcAuthUsers  []*rpcserver.AuthUser
+	rpcCertGroups map[string]*rpcserver.PermissionGroup
+	rpcUnixPaths  []string
+	rpcUnixMode   os.FileMode
+	rpcUnixUID    int
+	rpcUnixGID    int
 	ipv4NetInfo   types.NetworksResult
 	ipv6NetInfo   types.NetworksResult
 	onionNetInfo  types.NetworksResult
@@ -388,6 +429,183 @@ func removeDuplicateAddresses(addrs []string) []string {
 	return result
 }
 
+// parseRPCAuthOptions parses the configured RPC permission groups, hashed
+// user credentials, and TLS client certificate subject mappings into the
+// cooked forms used by the RPC server.
+func parseRPCAuthOptions(cfg *config) error {
+	groups := make(map[string]*rpcserver.PermissionGroup, len(cfg.RPCGroups))
+	for _, groupDef := range cfg.RPCGroups {
+		name, methodList, ok := strings.Cut(groupDef, ":")
+		if !ok || methodList == "" {
+			return fmt.Errorf("the rpcgroup value of %q is not in the form "+
+				"<group>:<method>[,<method>...]", groupDef)
+		}
+		if _, ok := groups[name]; ok {
+			return fmt.Errorf("the rpcgroup %q is defined more than once",
+				name)
+		}
+		methods := strings.Split(methodList, ",")
+		for i := range methods {
+			methods[i] = strings.TrimSpace(methods[i])
+		}
+		group, err := rpcserver.NewPermissionGroup(name, methods)
+		if err != nil {
+			return fmt.Errorf("invalid rpcgroup %q: %w", name, err)
+		}
+		groups[name] = group
+	}
+	lookupGroup := func(name string) (*rpcserver.PermissionGroup, error) {
+		if group := rpcserver.BuiltinPermissionGroup(name); group != nil {
+			return group, nil
+		}
+		if group, ok := groups[name]; ok {
+			return group, nil
+		}
+		return nil, fmt.Errorf("unknown RPC permission group %q", name)
+	}
+
+	// Parse the hashed user credentials.  They are only allowed under basic
+	// user/pass authentication and the usernames must be unique.
+	if len(cfg.RPCAuth) > 0 && cfg.RPCAuthType != authTypeBasic {
+		return errors.New("the --rpcauth option may not be used with " +
+			"--authtype=clientcert")
+	}
+	usernames := make(map[string]struct{}, len(cfg.RPCAuth)+2)
+	for _, username := range []string{cfg.RPCUser, cfg.RPCLimitUser} {
+		if username != "" {
+			usernames[username] = struct{}{}
+		}
+	}
+	cfg.rpcAuthUsers = make([]*rpcserver.AuthUser, 0, len(cfg.RPCAuth))
+	for _, auth := range cfg.RPCAuth {
+		idx := strings.LastIndexByte(auth, ':')
+		if idx == -1 {
+			return errors.New("rpcauth values must be in the form " +
+				"<username>:<salt>$<hash>:<group>")
+		}
+		group, err := lookupGroup(auth[idx+1:])
+		if err != nil {
+			return fmt.Errorf("invalid rpcauth value: %w", err)
+		}
+		user, err := rpcserver.ParseAuthCredential(auth[:idx], group)
+		if err != nil {
+			return fmt.Errorf("invalid rpcauth value: %w", err)
+		}
+		if _, ok := usernames[user.Name]; ok {
+			return fmt.Errorf("the RPC username %q is specified more than "+
+				"once", user.Name)
+		}
+		usernames[user.Name] = struct{}{}
+		cfg.rpcAuthUsers = append(cfg.rpcAuthUsers, user)
+	}
+
+	// Parse the client certificate subject mappings.  The subject is
+	// separated from the group by the final colon since the group names
+	// can't contain one.
+	if len(cfg.RPCClientCertGroups) > 0 &&
+		cfg.RPCAuthType != authTypeClientCert {
+
+		return errors.New("the --rpcclientcert option requires " +
+			"--authtype=clientcert")
+	}
+	cfg.rpcCertGroups = make(map[string]*rpcserver.PermissionGroup,
+		len(cfg.RPCClientCertGroups))
+	for _, mapping := range cfg.RPCClientCertGroups {
+		idx := strings.LastIndexByte(mapping, ':')
+		if idx <= 0 {
+			return fmt.Errorf("the rpcclientcert value of %q is not in the "+
+				"form <subject>:<group>", mapping)
+		}
+		subject := mapping[:idx]
+		group, err := lookupGroup(mapping[idx+1:])
+		if err != nil {
+			return fmt.Errorf("invalid rpcclientcert value %q: %w", mapping,
+				err)
+		}
+		if _, ok := cfg.rpcCertGroups[subject]; ok {
+			return fmt.Errorf("the client certificate subject %q is mapped "+
+				"more than once", subject)
+		}
+		cfg.rpcCertGroups[subject] = group
+	}
+
+	return nil
+}
+
+// rpcUnixListenerPrefix is the prefix of RPC listen addresses that specify the
+// path of a Unix domain socket.
+const rpcUnixListenerPrefix = "unix:"
+
+// parseRPCUnixListeners removes the RPC listeners for Unix domain sockets from
+// the configured RPC listen addresses and parses them along with the file
+// permissions and owner to apply to the sockets into the cooked forms used
+// when creating the listeners.
+func parseRPCUnixListeners(cfg *config) error {
+	cfg.rpcUnixUID, cfg.rpcUnixGID = -1, -1
+	netListeners := make([]string, 0, len(cfg.RPCListeners))
+	for _, addr := range cfg.RPCListeners {
+		path, ok := strings.CutPrefix(addr, rpcUnixListenerPrefix)
+		if !ok {
+			netListeners = append(netListeners, addr)
+			continue
+		}
+		if path == "" {
+			return fmt.Errorf("the rpclisten value of %q does not specify "+
+				"a Unix domain socket path", addr)
+		}
+		cfg.rpcUnixPaths = append(cfg.rpcUnixPaths, cleanAndExpandPath(path))
+	}
+	cfg.RPCListeners = netListeners
+
+	// Unix domain sockets do not support TLS, so clients connected to them
+	// are unable to present a certificate.  Since RPC usernames and
+	// passwords are not allowed with client certificate authentication,
+	// such clients would otherwise be unauthenticated.
+	if len(cfg.rpcUnixPaths) > 0 && cfg.RPCAuthType == authTypeClientCert {
+		return errors.New("RPC listeners for Unix domain sockets may not " +
+			"be used with --authtype=clientcert")
+	}
+
+	mode, err := strconv.ParseUint(cfg.RPCUnixMode, 8, 32)
+	if err != nil || os.FileMode(mode)&^os.ModePerm != 0 {
+		return fmt.Errorf("the rpcunixmode value of %q is not a valid "+
+			"octal file mode", cfg.RPCUnixMode)
+	}
+	cfg.rpcUnixMode = os.FileMode(mode)
+
+	if cfg.RPCUnixOwner == "" {
+		return nil
+	}
+	if runtime.GOOS == "windows" {
+		return errors.New("the --rpcunixowner option is not supported on " +
+			"Windows")
+	}
+	userName, groupName, hasGroup := strings.Cut(cfg.RPCUnixOwner, ":")
+	if userName != "" {
+		uid, err := strconv.Atoi(userName)
+		if err != nil {
+			u, err := user.Lookup(userName)
+			if err != nil {
+				return fmt.Errorf("invalid rpcunixowner user: %w", err)
+			}
+			uid, _ = strconv.Atoi(u.Uid)
+		}
+		cfg.rpcUnixUID = uid
+	}
+	if hasGroup && groupName != "" {
+		gid, err := strconv.Atoi(groupName)
+		if err != nil {
+			g, err := user.LookupGroup(groupName)
+			if err != nil {
+				return fmt.Errorf("invalid rpcunixowner group: %w", err)
+			}
+			gid, _ = strconv.Atoi(g.Gid)
+		}
+		cfg.rpcUnixGID = gid
+	}
+	return nil
+}
+
 const (
 	normalizeInterfaceAddrs = 1 << iota
 	normalizeInterfaceFirstAddr
@@ -611,10 +829,13 @@ func loadConfig(appName string) (*config, []string, error) {
 		RPCKey:               defaultRPCKeyFile,
 		RPCAuthType:          defaultRPCAuthType,
 		RPCClientCAs:         defaultRPCClientCAs,
+		RPCUnixMode:          defaultRPCUnixMode,
 		TLSCurve:             defaultTLSCurve,
 		RPCMaxClients:        defaultMaxRPCClients,
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
+		RPCRateLimit:         defaultRPCRateLimit,
+		RPCRateBurst:         defaultRPCRateBurst,
 
 		// P2P network options.
 		MaxSameIP:       defaultMaxSameIP,
@@ -629,6 +850,7 @@ func loadConfig(appName string) (*config, []string, error) {
 		// Relay and mempool policy.
 		MinRelayTxFee: mempool.DefaultMinRelayTxFee.ToCoin(),
 		MaxOrphanTxs:  defaultMaxOrphanTransactions,
+		MaxMempool:    defaultMaxMempoolSize,
 		AllowOldVotes: defaultAllowOldVotes,
 
 		// Mining options and policy.
@@ -639,6 +861,8 @@ func loadConfig(appName string) (*config, []string, error) {
 
 		// Indexing options.
 		TxIndex:           defaultTxIndex,
+		AddrIndex:         defaultAddrIndex,
+		SpendIndex:        defaultSpendIndex,
 		NoExistsAddrIndex: defaultNoExistsAddrIndex,
 
 		// Cooked options ready for use.
@@ -931,6 +1155,14 @@ func loadConfig(appName string) (*config, []string, error) {
 		cfg.UtxoCacheMaxSize = maxUtxoCacheMaxSize
 	}
 
+	// Enforce the minimum prune target size when pruning is enabled.
+	if cfg.Prune != 0 && cfg.Prune < minPruneTarget {
+		str := "%s: the prune target size must be at least %d MiB -- " +
+			"parsed [%d]"
+		err := fmt.Errorf(str, funcName, minPruneTarget, cfg.Prune)
+		return nil, nil, err
+	}
+
 	// Validate format of profile address.  It may either be an address:port or
 	// just a port.  The port also must be between 1024 and 65535.
 	if cfg.Profile != "" {
@@ -942,6 +1174,16 @@ func loadConfig(appName string) (*config, []string, error) {
 		}
 	}
 
+	// Validate the metrics server listen address when specified.
+	if cfg.MetricsListen != "" {
+		cfg.MetricsListen = portToLocalHostAddr(cfg.MetricsListen)
+		if err := validateProfileAddr(cfg.MetricsListen); err != nil {
+			str := "%s: metricslisten: %w"
+			err := fmt.Errorf(str, funcName, err)
+			return nil, nil, err
+		}
+	}
+
 	// Don't allow ban durations that are too short.
 	if cfg.BanDuration < time.Second {
 		str := "%s: the banduration option may not be less than 1s -- parsed [%v]"
@@ -1042,7 +1284,8 @@ func loadConfig(appName string) (*config, []string, error) {
 	// under basic user/pass authentication.
 	if cfg.RPCAuthType == authTypeBasic &&
 		(cfg.RPCUser == "" || cfg.RPCPass == "") &&
-		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") {
+		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") &&
+		len(cfg.RPCAuth) == 0 {
 		cfg.DisableRPC = true
 	}
 
@@ -1059,6 +1302,13 @@ func loadConfig(appName string) (*config, []string, error) {
 		}
 	}
 
+	// Parse the RPC permission groups, hashed user credentials, and client
+	// certificate subject mappings.
+	if err := parseRPCAuthOptions(&cfg); err != nil {
+		err := fmt.Errorf("%s: %w", funcName, err)
+		return nil, nil, err
+	}
+
 	// Default RPC to listen on localhost only.
 	if !cfg.DisableRPC && len(cfg.RPCListeners) == 0 {
 		addrs, err := net.LookupHost("localhost")
@@ -1079,6 +1329,47 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Validate the RPC rate limiting options.
+	if cfg.RPCRateLimit < 0 {
+		str := "%s: the rpcratelimit option may not be less than 0 -- " +
+			"parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RPCRateLimit)
+		return nil, nil, err
+	}
+	if cfg.RPCRateLimit > 0 && cfg.RPCRateBurst < 1 {
+		str := "%s: the rpcrateburst option may not be less than 1 when " +
+			"rate limiting is enabled -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RPCRateBurst)
+		return nil, nil, err
+	}
+
+	// Validate the ZeroMQ compatible publisher endpoints.
+	for _, endpoint := range cfg.ZMQPubEndpoints {
+		if _, _, err := zmq.ParseEndpoint(endpoint); err != nil {
+			str := "%s: zmqpub: %w"
+			err := fmt.Errorf(str, funcName, err)
+			return nil, nil, err
+		}
+	}
+
+	// Validate the REST interface listen address when specified.  The REST
+	// interface is served by the RPC server, so it requires the RPC server to
+	// be enabled.
+	if cfg.RESTListen != "" {
+		if cfg.DisableRPC {
+			str := "%s: the restlisten option requires the RPC server to " +
+				"be enabled"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
+		cfg.RESTListen = portToLocalHostAddr(cfg.RESTListen)
+		if err := validateProfileAddr(cfg.RESTListen); err != nil {
+			str := "%s: restlisten: %w"
+			err := fmt.Errorf(str, funcName, err)
+			return nil, nil, err
+		}
+	}
+
 	// Validate the minrelaytxfee.
 	cfg.minRelayTxFee, err = dcrutil.NewAmount(cfg.MinRelayTxFee)
 	if err != nil {
@@ -1124,6 +1415,14 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Enforce the minimum max mempool size.
+	if cfg.MaxMempool < minMaxMempoolSize {
+		str := "%s: the maxmempool option may not be less than %d MiB " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, minMaxMempoolSize, cfg.MaxMempool)
+		return nil, nil, err
+	}
+
 	// --txindex and --droptxindex do not mix.
 	if cfg.TxIndex && cfg.DropTxIndex {
 		err := fmt.Errorf("%s: the --txindex and --droptxindex "+
@@ -1132,6 +1431,73 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --addrindex and --dropaddrindex do not mix.
+	if cfg.AddrIndex && cfg.DropAddrIndex {
+		err := fmt.Errorf("%s: the --addrindex and --dropaddrindex "+
+			"options may not be activated at the same time",
+			funcName)
+		return nil, nil, err
+	}
+
+	// --addrindex and --droptxindex do not mix.
+	if cfg.AddrIndex && cfg.DropTxIndex {
+		err := fmt.Errorf("%s: the --addrindex and --droptxindex "+
+			"options may not be activated at the same time "+
+			"because the address index relies on the transaction "+
+			"index", funcName)
+		return nil, nil, err
+	}
+
+	// --addrindex requires --txindex.
+	if cfg.AddrIndex && !cfg.TxIndex {
+		err := fmt.Errorf("%s: the --addrindex option requires the "+
+			"--txindex option because the address index relies on "+
+			"the transaction index", funcName)
+		return nil, nil, err
+	}
+
+	// --spendindex and --dropspendindex do not mix.
+	if cfg.SpendIndex && cfg.DropSpendIndex {
+		err := fmt.Errorf("%s: the --spendindex and --dropspendindex "+
+			"options may not be activated at the same time",
+			funcName)
+		return nil, nil, err
+	}
+
+	// --prune does not mix with the indexes that require the full block
+	// history.
+	if cfg.Prune != 0 && (cfg.TxIndex || cfg.AddrIndex || cfg.SpendIndex) {
+		err := fmt.Errorf("%s: the --prune option may not be activated "+
+			"with the --txindex, --addrindex, or --spendindex options "+
+			"because those indexes require the full block history",
+			funcName)
+		return nil, nil, err
+	}
+
+	// --loadsnapshot does not mix with the indexes that require the full block
+	// history.
+	if cfg.LoadSnapshot != "" && (cfg.TxIndex || cfg.AddrIndex || cfg.SpendIndex) {
+		err := fmt.Errorf("%s: the --loadsnapshot option may not be "+
+			"activated with the --txindex, --addrindex, or --spendindex "+
+			"options because those indexes require the full block history",
+			funcName)
+		return nil, nil, err
+	}
+	// --loadsnapshot is only possible when the network parameters define
+	// snapshots that may be imported.
+	if cfg.LoadSnapshot != "" && len(cfg.params.AssumeUtxo) == 0 {
+		err := fmt.Errorf("%s: the --loadsnapshot option is not available "+
+			"on %s since its network parameters do not define any "+
+			"snapshots", funcName, cfg.params.Name)
+		return nil, nil, err
+	}
+	if cfg.LoadSnapshot != "" {
+		cfg.LoadSnapshot = cleanAndExpandPath(cfg.LoadSnapshot)
+	}
+	if cfg.ASMap != "" {
+		cfg.ASMap = cleanAndExpandPath(cfg.ASMap)
+	}
+
 	// !--noexistsaddrindex and --dropexistsaddrindex do not mix.
 	if !cfg.NoExistsAddrIndex && cfg.DropExistsAddrIndex {
 		err := fmt.Errorf("dropexistsaddrindex cannot be activated when " +
@@ -1177,6 +1543,13 @@ func loadConfig(appName string) (*config, []string, error) {
 	cfg.Listeners = normalizeAddresses(cfg.Listeners,
 		cfg.params.DefaultPort, normalizeInterfaceAddrs)
 
+	// Separate the RPC listeners for Unix domain sockets from the network
+	// addresses and parse the permissions and owner to apply to them.
+	if err := parseRPCUnixListeners(&cfg); err != nil {
+		err := fmt.Errorf("%s: %w", funcName, err)
+		return nil, nil, err
+	}
+
 	// Add default port to all rpc listener addresses if needed and remove
 	// duplicate addresses.
 	cfg.RPCListeners = normalizeAddresses(cfg.RPCListeners,
@@ -1330,6 +1703,29 @@ func loadConfig(appName string) (*config, []string, error) {
 		}
 	}
 
+	// Setup the dial function for I2P destinations.  I2P destinations are only
+	// reachable via an I2P router, so the dial function results in an error
+	// unless an I2P-specific proxy is specified.
+	if cfg.I2PProxy != "" {
+		host, port, err := net.SplitHostPort(cfg.I2PProxy)
+		if err != nil {
+			str := "%s: I2P proxy address '%s' is invalid: %w"
+			err := fmt.Errorf(str, funcName, cfg.I2PProxy, err)
+			return nil, nil, err
+		}
+		cfg.I2PProxy = normalizeAddresses([]string{host}, port,
+			normalizeInterfaceFirstAddr)[0]
+
+		cfg.i2pdial = func(ctx context.Context, a, b string) (net.Conn, error) {
+			proxy := &socks.Proxy{Addr: cfg.I2PProxy}
+			return proxy.DialContext(ctx, a, b)
+		}
+	} else {
+		cfg.i2pdial = func(ctx context.Context, a, b string) (net.Conn, error) {
+			return nil, errors.New("i2p proxy is not configured")
+		}
+	}
+
 	// Warn if old testnet directory is present.
 	for _, oldDir := range oldTestNets {
 		if fileExists(oldDir) {
@@ -1364,11 +1760,15 @@ func loadConfig(appName string) (*config, []string, error) {
 // dial function depending on the address and configuration options.  For
 // example, .onion addresses will be dialed using the onion specific proxy if
 // one was specified, but will otherwise use the normal dial function (which
-// could itself use a proxy or not).
+// could itself use a proxy or not).  Similarly, .i2p addresses will be dialed
+// using the I2P proxy and fail when one was not specified.
 func dcrdDial(ctx context.Context, network, addr string) (net.Conn, error) {
 	if strings.Contains(addr, ".onion:") {
 		return cfg.oniondial(ctx, network, addr)
 	}
+	if strings.Contains(addr, ".i2p:") {
+		return cfg.i2pdial(ctx, network, addr)
+	}
 	return cfg.dial(ctx, network, addr)
 }
 
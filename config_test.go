@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024 The Decred developers
+// Copyright (c) 2018-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,6 +8,8 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -91,3 +93,243 @@ func TestAltDNSNamesWithArg(t *testing.T) {
 	}
 	os.Args = old
 }
+
+// TestParseRPCAuthOptions ensures the RPC permission group, hashed credential,
+// and client certificate options are parsed and validated as intended.
+func TestParseRPCAuthOptions(t *testing.T) {
+	const credential = "alice:0011$" +
+		"6f0a6c4a2b1d1b6f0f3b9a0a1d1a8c2f0c6b8d9e7f1a2b3c4d5e6f708192a3b4"
+
+	tests := []struct {
+		name      string
+		cfg       config
+		wantErr   bool
+		wantUsers int
+		wantCerts int
+	}{{
+		name: "user in custom group",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCGroups:   []string{"monitor:getblockcount, notifyblocks"},
+			RPCAuth:     []string{credential + ":monitor"},
+		},
+		wantUsers: 1,
+	}, {
+		name: "user in builtin group",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCAuth:     []string{credential + ":limited"},
+		},
+		wantUsers: 1,
+	}, {
+		name: "unknown group",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCAuth:     []string{credential + ":monitor"},
+		},
+		wantErr: true,
+	}, {
+		name: "missing group",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCAuth:     []string{"alice0011$00"},
+		},
+		wantErr: true,
+	}, {
+		name: "username conflicts with rpcuser",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCUser:     "alice",
+			RPCAuth:     []string{credential + ":admin"},
+		},
+		wantErr: true,
+	}, {
+		name: "duplicate username",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCAuth:     []string{credential + ":admin", credential + ":limited"},
+		},
+		wantErr: true,
+	}, {
+		name: "duplicate group",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCGroups:   []string{"monitor:getblockcount", "monitor:getinfo"},
+		},
+		wantErr: true,
+	}, {
+		name: "group without methods",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCGroups:   []string{"monitor:"},
+		},
+		wantErr: true,
+	}, {
+		name: "group with unknown method",
+		cfg: config{
+			RPCAuthType: authTypeBasic,
+			RPCGroups:   []string{"monitor:notamethod"},
+		},
+		wantErr: true,
+	}, {
+		name: "hashed credential with client certs",
+		cfg: config{
+			RPCAuthType: authTypeClientCert,
+			RPCAuth:     []string{credential + ":admin"},
+		},
+		wantErr: true,
+	}, {
+		name: "client cert subjects",
+		cfg: config{
+			RPCAuthType:         authTypeClientCert,
+			RPCGroups:           []string{"monitor:@limited,getpeerinfo"},
+			RPCClientCertGroups: []string{"CN=wallet:admin", "CN=mon,O=x:monitor"},
+		},
+		wantCerts: 2,
+	}, {
+		name: "client cert subject without clientcert auth",
+		cfg: config{
+			RPCAuthType:         authTypeBasic,
+			RPCClientCertGroups: []string{"CN=wallet:admin"},
+		},
+		wantErr: true,
+	}, {
+		name: "duplicate client cert subject",
+		cfg: config{
+			RPCAuthType:         authTypeClientCert,
+			RPCClientCertGroups: []string{"CN=wallet:admin", "CN=wallet:limited"},
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		err := parseRPCAuthOptions(&test.cfg)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if len(test.cfg.rpcAuthUsers) != test.wantUsers {
+			t.Errorf("%q: unexpected number of users -- got %d, want %d",
+				test.name, len(test.cfg.rpcAuthUsers), test.wantUsers)
+		}
+		if len(test.cfg.rpcCertGroups) != test.wantCerts {
+			t.Errorf("%q: unexpected number of client cert subjects -- got "+
+				"%d, want %d", test.name, len(test.cfg.rpcCertGroups),
+				test.wantCerts)
+		}
+	}
+}
+
+// TestParseRPCUnixListeners ensures RPC listeners for Unix domain sockets are
+// separated from the network listeners and their options are validated.
+func TestParseRPCUnixListeners(t *testing.T) {
+	tests := []struct {
+		name      string
+		listeners []string
+		mode      string
+		owner     string
+		authType  string
+		wantErr   bool
+		wantNet   []string
+		wantPaths []string
+		wantMode  os.FileMode
+		wantUID   int
+		wantGID   int
+	}{{
+		name:      "mixed listeners",
+		listeners: []string{"127.0.0.1", "unix:/run/dcrd/rpc.sock", "[::1]"},
+		mode:      "0660",
+		wantNet:   []string{"127.0.0.1", "[::1]"},
+		wantPaths: []string{"/run/dcrd/rpc.sock"},
+		wantMode:  0660,
+		wantUID:   -1,
+		wantGID:   -1,
+	}, {
+		name:      "numeric owner and group",
+		listeners: []string{"unix:/run/dcrd/rpc.sock"},
+		mode:      "600",
+		owner:     "1000:1001",
+		wantNet:   []string{},
+		wantPaths: []string{"/run/dcrd/rpc.sock"},
+		wantMode:  0600,
+		wantUID:   1000,
+		wantGID:   1001,
+	}, {
+		name:      "numeric group only",
+		listeners: []string{"unix:/run/dcrd/rpc.sock"},
+		mode:      "0600",
+		owner:     ":1001",
+		wantNet:   []string{},
+		wantPaths: []string{"/run/dcrd/rpc.sock"},
+		wantMode:  0600,
+		wantUID:   -1,
+		wantGID:   1001,
+	}, {
+		name:      "empty path",
+		listeners: []string{"unix:"},
+		mode:      "0600",
+		wantErr:   true,
+	}, {
+		name:      "invalid mode",
+		listeners: []string{"unix:/run/dcrd/rpc.sock"},
+		mode:      "0800",
+		wantErr:   true,
+	}, {
+		name:      "mode with non-permission bits",
+		listeners: []string{"unix:/run/dcrd/rpc.sock"},
+		mode:      "4755",
+		wantErr:   true,
+	}, {
+		name:      "client certificate authentication",
+		listeners: []string{"127.0.0.1", "unix:/run/dcrd/rpc.sock"},
+		mode:      "0600",
+		authType:  authTypeClientCert,
+		wantErr:   true,
+	}}
+
+	for _, test := range tests {
+		if test.owner != "" && runtime.GOOS == "windows" {
+			continue
+		}
+		cfg := config{
+			RPCListeners: test.listeners,
+			RPCUnixMode:  test.mode,
+			RPCUnixOwner: test.owner,
+			RPCAuthType:  test.authType,
+		}
+		err := parseRPCUnixListeners(&cfg)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(cfg.RPCListeners, test.wantNet) {
+			t.Errorf("%q: unexpected network listeners -- got %v, want %v",
+				test.name, cfg.RPCListeners, test.wantNet)
+		}
+		wantPaths := make([]string, 0, len(test.wantPaths))
+		for _, path := range test.wantPaths {
+			wantPaths = append(wantPaths, filepath.Clean(path))
+		}
+		if !reflect.DeepEqual(cfg.rpcUnixPaths, wantPaths) {
+			t.Errorf("%q: unexpected socket paths -- got %v, want %v",
+				test.name, cfg.rpcUnixPaths, wantPaths)
+		}
+		if cfg.rpcUnixMode != test.wantMode {
+			t.Errorf("%q: unexpected mode -- got %v, want %v", test.name,
+				cfg.rpcUnixMode, test.wantMode)
+		}
+		if cfg.rpcUnixUID != test.wantUID || cfg.rpcUnixGID != test.wantGID {
+			t.Errorf("%q: unexpected owner -- got %d:%d, want %d:%d",
+				test.name, cfg.rpcUnixUID, cfg.rpcUnixGID, test.wantUID,
+				test.wantGID)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2016 The btcsuite developers
-// Copyright (c) 2017-2024 The Decred developers
+// Copyright (c) 2017-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -36,6 +36,10 @@ const (
 	// defaultTargetOutbound is the default number of outbound connections to
 	// maintain.
 	defaultTargetOutbound = uint32(8)
+
+	// defaultFeelerInterval is the default interval at which feeler
+	// connections are made.
+	defaultFeelerInterval = time.Minute * 2
 )
 
 // ConnState represents the state of the requested connection.
@@ -165,6 +169,29 @@ type Config struct {
 	// Timeout specifies the amount of time to wait for a connection
 	// to complete before giving up.
 	Timeout time.Duration
+
+	// GetFeelerAddress is a way to get an address to make a feeler connection
+	// to.  Feeler connections are short-lived connections that are made
+	// periodically in order to test whether addresses are reachable without
+	// occupying any of the outbound connection slots.  If nil, no feeler
+	// connections will be made.
+	//
+	// This field will not have any effect if the OnFeelerConnection field is
+	// not also specified.
+	GetFeelerAddress func() (net.Addr, error)
+
+	// OnFeelerConnection is a callback that is fired when a feeler connection
+	// is established.  It is invoked synchronously, so only a single feeler
+	// connection is ever active, and it is the caller's responsibility to
+	// close the connection before returning.
+	//
+	// This field will not have any effect if the GetFeelerAddress field is
+	// not also specified.
+	OnFeelerConnection func(net.Addr, net.Conn)
+
+	// FeelerInterval is the interval at which feeler connections are made.
+	// Defaults to 2m.
+	FeelerInterval time.Duration
 }
 
 // registerPending is used to register a pending connection attempt. By
@@ -574,18 +601,7 @@ func (cm *ConnManager) Connect(ctx context.Context, c *ConnReq) {
 
 	log.Debugf("Attempting to connect to %v", c)
 
-	if cm.cfg.Timeout != 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, cm.cfg.Timeout)
-		defer cancel()
-	}
-	var conn net.Conn
-	var err error
-	if cm.cfg.Dial != nil {
-		conn, err = cm.cfg.Dial(ctx, c.Addr.Network(), c.Addr.String())
-	} else {
-		conn, err = cm.cfg.DialAddr(ctx, c.Addr)
-	}
+	conn, err := cm.dial(ctx, c.Addr)
 	if err != nil {
 		select {
 		case cm.requests <- handleFailed{c, err}:
@@ -600,6 +616,59 @@ func (cm *ConnManager) Connect(ctx context.Context, c *ConnReq) {
 	}
 }
 
+// dial connects to the provided address using the dial function and timeout
+// configured when initially creating the connection manager.
+func (cm *ConnManager) dial(ctx context.Context, addr net.Addr) (net.Conn, error) {
+	if cm.cfg.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cm.cfg.Timeout)
+		defer cancel()
+	}
+	if cm.cfg.Dial != nil {
+		return cm.cfg.Dial(ctx, addr.Network(), addr.String())
+	}
+	return cm.cfg.DialAddr(ctx, addr)
+}
+
+// feeler makes a feeler connection to an address obtained from the configured
+// feeler address function and hands the connection to the configured feeler
+// connection callback.  Feeler connections are not tracked by the connection
+// handler, so they never count towards the target number of outbound
+// connections and are never retried.
+func (cm *ConnManager) feeler(ctx context.Context) {
+	addr, err := cm.cfg.GetFeelerAddress()
+	if err != nil {
+		log.Debugf("Skipping feeler connection: %v", err)
+		return
+	}
+
+	log.Debugf("Attempting feeler connection to %v", addr)
+	conn, err := cm.dial(ctx, addr)
+	if err != nil {
+		log.Debugf("Failed feeler connection to %v: %v", addr, err)
+		return
+	}
+	cm.cfg.OnFeelerConnection(addr, conn)
+}
+
+// feelerHandler periodically makes feeler connections until the provided
+// context is cancelled.  It must be run as a goroutine.
+func (cm *ConnManager) feelerHandler(ctx context.Context) {
+	ticker := time.NewTicker(cm.cfg.FeelerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cm.feeler(ctx)
+
+		case <-ctx.Done():
+			log.Trace("Feeler handler done")
+			return
+		}
+	}
+}
+
 // Disconnect disconnects the connection corresponding to the given connection
 // id. If permanent, the connection will be retried with an increasing backoff
 // duration.
@@ -724,6 +793,15 @@ func (cm *ConnManager) Run(ctx context.Context) {
 		}
 	}
 
+	// Start making periodic feeler connections when requested.
+	if cm.cfg.GetFeelerAddress != nil && cm.cfg.OnFeelerConnection != nil {
+		wg.Add(1)
+		go func() {
+			cm.feelerHandler(ctx)
+			wg.Done()
+		}()
+	}
+
 	// Stop all the listeners and shutdown the connection manager when the
 	// context is cancelled.  There will not be any listeners if listening is
 	// disabled.
@@ -756,6 +834,9 @@ func New(cfg *Config) (*ConnManager, error) {
 	if cfg.TargetOutbound == 0 {
 		cfg.TargetOutbound = defaultTargetOutbound
 	}
+	if cfg.FeelerInterval <= 0 {
+		cfg.FeelerInterval = defaultFeelerInterval
+	}
 	cm := ConnManager{
 		cfg:      *cfg, // Copy so caller can't mutate
 		requests: make(chan interface{}),
@@ -237,6 +237,89 @@ func TestTargetOutbound(t *testing.T) {
 	wg.Wait()
 }
 
+// TestFeelerConnections ensures feeler connections are made periodically to
+// the addresses provided by the feeler address function and that they are
+// neither tracked as connection requests nor count towards the target number
+// of outbound connections.
+func TestFeelerConnections(t *testing.T) {
+	targetOutbound := uint32(2)
+	connected := make(chan *ConnReq)
+	feelers := make(chan net.Addr)
+	feelerAddr := &net.TCPAddr{IP: net.ParseIP("127.0.0.2"), Port: 18555}
+	cmgr, err := New(&Config{
+		TargetOutbound: targetOutbound,
+		Dial:           mockDialer,
+		GetNewAddress: func() (net.Addr, error) {
+			return &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 18555,
+			}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+		GetFeelerAddress: func() (net.Addr, error) {
+			return feelerAddr, nil
+		},
+		OnFeelerConnection: func(addr net.Addr, conn net.Conn) {
+			conn.Close()
+			feelers <- addr
+		},
+		FeelerInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	_, shutdown, wg := runConnMgrAsync(context.Background(), cmgr)
+
+	// Wait for the target outbound conns to be established along with multiple
+	// feeler connections.
+	var numConnected uint32
+	var numFeelers int
+	for numConnected < targetOutbound || numFeelers < 3 {
+		select {
+		case <-connected:
+			numConnected++
+		case addr := <-feelers:
+			if addr != feelerAddr {
+				t.Fatalf("unexpected feeler address: got %v, want %v", addr,
+					feelerAddr)
+			}
+			numFeelers++
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for connections (%d outbound, %d "+
+				"feelers)", numConnected, numFeelers)
+		}
+	}
+
+	// Ensure the feeler connections are not tracked as connection requests.
+	var numConnReqs int
+	err = cmgr.ForEachConnReq(func(c *ConnReq) error {
+		if c.Addr.String() == feelerAddr.String() {
+			return fmt.Errorf("feeler connection tracked as %v", c)
+		}
+		numConnReqs++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numConnReqs != int(targetOutbound) {
+		t.Fatalf("unexpected number of connection requests: got %d, want %d",
+			numConnReqs, targetOutbound)
+	}
+
+	// Ensure clean shutdown of connection manager while draining any further
+	// feeler connections.
+	go func() {
+		for range feelers {
+		}
+	}()
+	shutdown()
+	wg.Wait()
+	close(feelers)
+}
+
 // TestPassAddrAlongDialAddr tests if when using the DialAddr config option,
 // any address object returned by GetNewAddress will be correctly passed along
 // to DialAddr to be used for connecting to a host.
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/decred/dcrd/database/v3"
+)
+
+// dbStatsCmd defines the configuration options for the dbstats command.
+type dbStatsCmd struct{}
+
+var (
+	// dbStatsCfg defines the configuration options for the command.
+	dbStatsCfg = dbStatsCmd{}
+)
+
+// bucketStats houses the number of keys and the total size of the keys and
+// values in a bucket excluding any nested buckets.
+type bucketStats struct {
+	path    string
+	numKeys uint64
+	size    uint64
+}
+
+// collectBucketStats appends the stats for the provided bucket followed by all
+// of its nested buckets to the passed slice and returns it.
+func collectBucketStats(stats []bucketStats, path string, bucket database.Bucket) ([]bucketStats, error) {
+	entry := bucketStats{path: path}
+	err := bucket.ForEach(func(k, v []byte) error {
+		entry.numKeys++
+		entry.size += uint64(len(k) + len(v))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats = append(stats, entry)
+
+	// Copy the nested bucket names since they are only valid during the
+	// iteration.
+	var names [][]byte
+	err = bucket.ForEachBucket(func(k []byte) error {
+		names = append(names, append([]byte(nil), k...))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		stats, err = collectBucketStats(stats, path+"/"+string(name),
+			bucket.Bucket(name))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// Execute is the main entry point for the command.  It's invoked by the parser.
+func (cmd *dbStatsCmd) Execute(args []string) error {
+	// Setup the global config options and ensure they are valid.
+	if err := setupGlobalConfig(); err != nil {
+		return err
+	}
+
+	// Load the block database.
+	db, err := loadBlockDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(func(tx database.Tx) error {
+		stats, err := collectBucketStats(nil, "", tx.Metadata())
+		if err != nil {
+			return err
+		}
+
+		var totalKeys, totalSize uint64
+		for _, entry := range stats {
+			path := entry.path
+			if path == "" {
+				path = "/"
+			}
+			log.Infof("Bucket %q: %d keys, %d bytes", path, entry.numKeys,
+				entry.size)
+			totalKeys += entry.numKeys
+			totalSize += entry.size
+		}
+		log.Infof("Total: %d buckets, %d keys, %d bytes", len(stats),
+			totalKeys, totalSize)
+		return nil
+	})
+}
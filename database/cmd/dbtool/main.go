@@ -89,6 +89,14 @@ func realMain() error {
 	parser.AddCommand("fetchblockregion",
 		"Fetch the specified block region from the database", "",
 		&blockRegionCfg)
+	parser.AddCommand("verifyblocks",
+		"Verify the integrity of all blocks in the database",
+		"Verify the stored data of every block in the block index "+
+			"matches its checksum and report any missing or orphaned "+
+			"block files.", &verifyBlocksCfg)
+	parser.AddCommand("dbstats",
+		"Show the number of keys and their sizes for all database buckets",
+		"", &dbStatsCfg)
 
 	// Parse command line and invoke the Execute function for the specified
 	// command.
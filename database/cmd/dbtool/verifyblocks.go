@@ -0,0 +1,90 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/database/v3"
+)
+
+// verifyBlocksCmd defines the configuration options for the verifyblocks
+// command.
+type verifyBlocksCmd struct{}
+
+var (
+	// verifyBlocksCfg defines the configuration options for the command.
+	verifyBlocksCfg = verifyBlocksCmd{}
+)
+
+// Execute is the main entry point for the command.  It's invoked by the parser.
+func (cmd *verifyBlocksCmd) Execute(args []string) error {
+	// Setup the global config options and ensure they are valid.
+	if err := setupGlobalConfig(); err != nil {
+		return err
+	}
+
+	// Load the block database.
+	db, err := loadBlockDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Verify the integrity of every stored block.
+	var report *database.BlockStoreReport
+	err = db.View(func(tx database.Tx) error {
+		verifier, ok := tx.(database.BlockStoreVerifier)
+		if !ok {
+			return fmt.Errorf("database type %q does not support block "+
+				"storage verification", cfg.DbType)
+		}
+
+		log.Infof("Verifying stored blocks...")
+		startTime := time.Now()
+		report, err = verifier.VerifyBlockStore(func(checked, total int) {
+			log.Infof("Verified %d of %d blocks", checked, total)
+		})
+		if err != nil {
+			return err
+		}
+		log.Infof("Verified %d blocks (%d bytes) in %d files in %v",
+			report.NumBlocks, report.NumBytes, report.NumFiles,
+			time.Since(startTime))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Report the results.
+	for _, fileNum := range report.MissingFiles {
+		log.Warnf("Block file %d is referenced by the block index but does "+
+			"not exist", fileNum)
+	}
+	for _, fileNum := range report.OrphanedFiles {
+		log.Warnf("Block file %d is not referenced by the block index",
+			fileNum)
+	}
+	for _, bad := range report.BadBlocks {
+		log.Warnf("Bad block %s (file %d, offset %d): %v", bad.Hash,
+			bad.File, bad.Offset, bad.Err)
+	}
+	if len(report.BadBlocks) == 0 {
+		if len(report.MissingFiles) == 0 && len(report.OrphanedFiles) == 0 {
+			log.Info("No problems found")
+		}
+		return nil
+	}
+
+	// The chain state and indexes reference the stored blocks, so there is
+	// no way to safely discard the bad blocks without also rolling them back.
+	// Resyncing from a fresh data directory is the only supported recovery.
+	log.Warn("The data directory must be removed to resync the chain from " +
+		"scratch")
+	return errors.New("the block storage is not consistent")
+}
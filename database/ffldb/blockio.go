@@ -16,6 +16,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -180,6 +182,12 @@ type blockStore struct {
 	deleteFileFunc    func(fileNum uint32) error
 }
 
+// blockFileInfo houses the number and size of a flat block file on disk.
+type blockFileInfo struct {
+	num  uint32
+	size uint64
+}
+
 // blockLocation identifies a particular block file and location.
 type blockLocation struct {
 	blockFileNum uint32
@@ -718,6 +726,78 @@ func (s *blockStore) handleRollback(oldBlockFileNum, oldBlockOffset uint32) {
 	}
 }
 
+// removeFiles closes and deletes the block files for the passed flat file
+// numbers.  It is used to remove block files once all of the blocks they house
+// have been pruned from the block index.
+//
+// Any failures are logged at a warning level rather than being returned since
+// the block index no longer references the files and any leftover files will be
+// removed the next time blocks are pruned.
+func (s *blockStore) removeFiles(fileNums []uint32) {
+	for _, fileNum := range fileNums {
+		// Close the file if it is open under the write lock for the file in
+		// case any readers are currently reading from it so it's not closed
+		// out from under them.
+		s.obfMutex.Lock()
+		if obf, ok := s.openBlockFiles[fileNum]; ok {
+			s.lruMutex.Lock()
+			s.openBlocksLRU.Remove(s.fileNumToLRUElem[fileNum])
+			delete(s.fileNumToLRUElem, fileNum)
+			s.lruMutex.Unlock()
+
+			obf.Lock()
+			_ = obf.file.Close()
+			obf.Unlock()
+			delete(s.openBlockFiles, fileNum)
+		}
+		s.obfMutex.Unlock()
+
+		if err := s.deleteFileFunc(fileNum); err != nil {
+			log.Warnf("Failed to remove pruned block file %d: %v", fileNum,
+				err)
+			continue
+		}
+		log.Tracef("Removed pruned block file #%d", fileNum)
+	}
+}
+
+// scanBlockFileSizes returns the number and size of all flat block files in the
+// database directory ordered from oldest to newest.  Note that the oldest files
+// will not start at zero when block data has been pruned.
+func scanBlockFileSizes(dbPath string) ([]blockFileInfo, error) {
+	entries, err := os.ReadDir(dbPath)
+	if err != nil {
+		str := fmt.Sprintf("failed to read directory %q: %v", dbPath, err)
+		return nil, makeDbErr(database.ErrDriverSpecific, str)
+	}
+
+	// Directory entries are sorted by filename and the block files are zero
+	// padded, so they are also sorted by file number.
+	var files []blockFileInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".fdb") {
+			continue
+		}
+		fileNum, err := strconv.ParseUint(strings.TrimSuffix(name, ".fdb"),
+			10, 32)
+		if err != nil || fmt.Sprintf(blockFilenameTemplate, fileNum) != name {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			str := fmt.Sprintf("failed to stat file %q: %v", name, err)
+			return nil, makeDbErr(database.ErrDriverSpecific, str)
+		}
+		files = append(files, blockFileInfo{
+			num:  uint32(fileNum),
+			size: uint64(info.Size()),
+		})
+	}
+
+	return files, nil
+}
+
 // scanBlockFiles searches the database directory for all flat block files to
 // find the end of the most recent file.  This position is considered the
 // current write cursor which is also stored in the metadata.  Thus, it is used
@@ -726,15 +806,10 @@ func (s *blockStore) handleRollback(oldBlockFileNum, oldBlockOffset uint32) {
 func scanBlockFiles(dbPath string) (int, uint32) {
 	lastFile := -1
 	fileLen := uint32(0)
-	for i := 0; ; i++ {
-		filePath := blockFilePath(dbPath, uint32(i))
-		st, err := os.Stat(filePath)
-		if err != nil {
-			break
-		}
-		lastFile = i
-
-		fileLen = uint32(st.Size())
+	files, err := scanBlockFileSizes(dbPath)
+	if err == nil && len(files) > 0 {
+		lastFile = int(files[len(files)-1].num)
+		fileLen = uint32(files[len(files)-1].size)
 	}
 
 	log.Tracef("Scan found latest block file #%d with length %d", lastFile,
@@ -75,6 +75,10 @@ var (
 	// writeLocKeyName is the key used to store the current write file
 	// location.
 	writeLocKeyName = []byte("ffldb-writeloc")
+
+	// prunedKeyName is the key used to record that block data has been
+	// pruned from the database.
+	prunedKeyName = []byte("ffldb-pruned")
 )
 
 // Common error strings.
@@ -973,6 +977,9 @@ type transaction struct {
 	pendingBlocks    map[chainhash.Hash]int
 	pendingBlockData []pendingBlock
 
+	// Block files that need to be deleted on commit due to pruning.
+	pendingPruneFiles []uint32
+
 	// Keys that need to be stored or deleted on commit.
 	pendingKeys   *treap.Mutable
 	pendingRemove *treap.Mutable
@@ -987,6 +994,12 @@ type transaction struct {
 // Enforce transaction implements the database.Tx interface.
 var _ database.Tx = (*transaction)(nil)
 
+// Enforce transaction implements the database.BlockPruner interface.
+var _ database.BlockPruner = (*transaction)(nil)
+
+// Enforce transaction implements the database.BlockStoreVerifier interface.
+var _ database.BlockStoreVerifier = (*transaction)(nil)
+
 // removeActiveIter removes the passed iterator from the list of active
 // iterators against the pending keys treap.
 func (tx *transaction) removeActiveIter(iter *treap.Iterator) {
@@ -1633,6 +1646,287 @@ func (tx *transaction) FetchBlockRegions(regions []database.BlockRegion) ([][]by
 	return blockRegions, nil
 }
 
+// PruneBlocks deletes the oldest block files as needed to bring the total size
+// of all block files to at or below the provided target size in bytes.  Files
+// are removed oldest first and pruning stops before removing any file that
+// contains a block for which the provided keep function returns true.  The
+// current write file is never removed.
+//
+// The blocks contained in the removed files are removed from the block index
+// immediately from the viewpoint of the transaction, however, the files
+// themselves are not deleted until the transaction is committed.
+//
+// Returns the following errors as required by the interface contract:
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.BlockPruner interface implementation.
+func (tx *transaction) PruneBlocks(targetSize uint64, keep func(hash *chainhash.Hash) bool) ([]chainhash.Hash, error) {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	// Ensure the transaction is writable.
+	if !tx.writable {
+		str := "prune blocks requires a writable database transaction"
+		return nil, makeDbErr(database.ErrTxNotWritable, str)
+	}
+
+	// Nothing to do when the total size of the block files is already within
+	// the target.
+	files, err := scanBlockFileSizes(tx.db.store.basePath)
+	if err != nil {
+		return nil, err
+	}
+	var totalSize uint64
+	for _, file := range files {
+		totalSize += file.size
+	}
+	if totalSize <= targetSize {
+		return nil, nil
+	}
+
+	// Determine the candidate files to remove oldest first while excluding
+	// the current write file and any files that were already scheduled for
+	// removal by this transaction.
+	wc := tx.db.store.writeCursor
+	wc.RLock()
+	curFileNum := wc.curFileNum
+	wc.RUnlock()
+	alreadyPending := make(map[uint32]struct{}, len(tx.pendingPruneFiles))
+	for _, fileNum := range tx.pendingPruneFiles {
+		alreadyPending[fileNum] = struct{}{}
+	}
+	candidates := make(map[uint32][]chainhash.Hash)
+	var candidateNums []uint32
+	for _, file := range files {
+		if totalSize <= targetSize || file.num >= curFileNum {
+			break
+		}
+		totalSize -= file.size
+		if _, ok := alreadyPending[file.num]; ok {
+			continue
+		}
+		candidates[file.num] = nil
+		candidateNums = append(candidateNums, file.num)
+	}
+	if len(candidateNums) == 0 {
+		return nil, nil
+	}
+
+	// Find all of the blocks that are stored in the candidate files.
+	err = tx.blockIdxBucket.ForEach(func(k, v []byte) error {
+		loc := deserializeBlockLoc(v)
+		hashes, ok := candidates[loc.blockFileNum]
+		if !ok {
+			return nil
+		}
+		var hash chainhash.Hash
+		copy(hash[:], k)
+		candidates[loc.blockFileNum] = append(hashes, hash)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the candidate files oldest first until one that contains a
+	// block that must be kept is found.
+	var prunedFiles []uint32
+	var prunedHashes []chainhash.Hash
+	for _, fileNum := range candidateNums {
+		hashes := candidates[fileNum]
+		var keepFile bool
+		for i := range hashes {
+			if keep != nil && keep(&hashes[i]) {
+				keepFile = true
+				break
+			}
+		}
+		if keepFile {
+			break
+		}
+
+		for i := range hashes {
+			if err := tx.blockIdxBucket.Delete(hashes[i][:]); err != nil {
+				return nil, err
+			}
+		}
+		prunedFiles = append(prunedFiles, fileNum)
+		prunedHashes = append(prunedHashes, hashes...)
+	}
+	if len(prunedFiles) == 0 {
+		return nil, nil
+	}
+
+	// Record that block data has been pruned and schedule the files to be
+	// removed once the transaction is committed.
+	if err := tx.metaBucket.Put(prunedKeyName, []byte{1}); err != nil {
+		return nil, err
+	}
+	tx.pendingPruneFiles = append(tx.pendingPruneFiles, prunedFiles...)
+
+	log.Debugf("Pruned %d blocks from %d block files", len(prunedHashes),
+		len(prunedFiles))
+	return prunedHashes, nil
+}
+
+// BeenPruned returns whether or not block data has ever been pruned from the
+// database.
+//
+// Returns the following errors as required by the interface contract:
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.BlockPruner interface implementation.
+func (tx *transaction) BeenPruned() (bool, error) {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return false, err
+	}
+
+	return tx.metaBucket.Get(prunedKeyName) != nil, nil
+}
+
+// storedBlock houses the hash and location of a block in the block index.
+type storedBlock struct {
+	hash chainhash.Hash
+	loc  blockLocation
+}
+
+// storedBlocks returns the hashes and locations of all blocks in the block
+// index sorted by the order they were stored in the block files.
+func (tx *transaction) storedBlocks() ([]storedBlock, error) {
+	var blocks []storedBlock
+	err := tx.blockIdxBucket.ForEach(func(k, v []byte) error {
+		if len(k) != chainhash.HashSize || len(v) < blockLocSize {
+			str := fmt.Sprintf("malformed block index entry for key %x",
+				k)
+			return makeDbErr(database.ErrCorruption, str)
+		}
+		var block storedBlock
+		copy(block.hash[:], k)
+		block.loc = deserializeBlockLoc(v)
+		blocks = append(blocks, block)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		a, b := &blocks[i].loc, &blocks[j].loc
+		if a.blockFileNum != b.blockFileNum {
+			return a.blockFileNum < b.blockFileNum
+		}
+		return a.fileOffset < b.fileOffset
+	})
+	return blocks, nil
+}
+
+// VerifyBlockStore reads the block record of every block in the block index
+// from the flat block files and ensures it is fully present, is for the
+// network associated with the database, and matches the checksum stored with
+// it.  It also reports any block files that are referenced by the block index
+// but do not exist and any block files other than the current write file that
+// are not referenced by the block index.
+//
+// The optional progress function is invoked after every 1000 blocks checked as
+// well as once all blocks have been checked.
+//
+// Returns the following errors as required by the interface contract:
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.BlockStoreVerifier interface
+// implementation.
+func (tx *transaction) VerifyBlockStore(progress func(checked, total int)) (*database.BlockStoreReport, error) {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	blocks, err := tx.storedBlocks()
+	if err != nil {
+		return nil, err
+	}
+	files, err := scanBlockFileSizes(tx.db.store.basePath)
+	if err != nil {
+		return nil, err
+	}
+	fileSizes := make(map[uint32]uint64, len(files))
+	for _, file := range files {
+		fileSizes[file.num] = file.size
+	}
+
+	report := &database.BlockStoreReport{
+		NumBlocks: len(blocks),
+		NumFiles:  len(files),
+	}
+	referenced := make(map[uint32]struct{}, len(files))
+	for i := range blocks {
+		block := &blocks[i]
+		loc := block.loc
+		report.NumBytes += uint64(loc.blockLen)
+
+		var verifyErr error
+		fileNum := loc.blockFileNum
+		fileSize, exists := fileSizes[fileNum]
+		_, seen := referenced[fileNum]
+		referenced[fileNum] = struct{}{}
+		switch {
+		case !exists:
+			if !seen {
+				report.MissingFiles = append(report.MissingFiles, fileNum)
+			}
+			str := fmt.Sprintf("block file %d does not exist", fileNum)
+			verifyErr = makeDbErr(database.ErrDriverSpecific, str)
+
+		case loc.blockLen < 12:
+			str := fmt.Sprintf("block length %d is too short for a block "+
+				"record", loc.blockLen)
+			verifyErr = makeDbErr(database.ErrCorruption, str)
+
+		case uint64(loc.fileOffset)+uint64(loc.blockLen) > fileSize:
+			str := fmt.Sprintf("block record at offset %d with length %d "+
+				"extends past the end of block file %d (size %d)",
+				loc.fileOffset, loc.blockLen, fileNum, fileSize)
+			verifyErr = makeDbErr(database.ErrCorruption, str)
+
+		default:
+			_, verifyErr = tx.db.store.readBlock(&block.hash, loc)
+		}
+		if verifyErr != nil {
+			report.BadBlocks = append(report.BadBlocks, database.BadBlock{
+				Hash:   block.hash,
+				File:   fileNum,
+				Offset: loc.fileOffset,
+				Err:    verifyErr,
+			})
+		}
+
+		if progress != nil && (i+1)%1000 == 0 {
+			progress(i+1, len(blocks))
+		}
+	}
+	if progress != nil && len(blocks)%1000 != 0 {
+		progress(len(blocks), len(blocks))
+	}
+
+	// Any block files that are not referenced by the block index other than
+	// the current write file are orphaned.
+	wc := tx.db.store.writeCursor
+	wc.RLock()
+	curFileNum := wc.curFileNum
+	wc.RUnlock()
+	for _, file := range files {
+		if _, ok := referenced[file.num]; ok || file.num == curFileNum {
+			continue
+		}
+		report.OrphanedFiles = append(report.OrphanedFiles, file.num)
+	}
+
+	return report, nil
+}
+
 // close marks the transaction closed then releases any pending data, the
 // underlying snapshot, the transaction read lock, and the write lock when the
 // transaction is writable.
@@ -1643,6 +1937,9 @@ func (tx *transaction) close() {
 	tx.pendingBlocks = nil
 	tx.pendingBlockData = nil
 
+	// Clear pending block files that would have been deleted on commit.
+	tx.pendingPruneFiles = nil
+
 	// Clear pending keys that would have been written or deleted on commit.
 	tx.pendingKeys = nil
 	tx.pendingRemove = nil
@@ -1730,7 +2027,23 @@ func (tx *transaction) writePendingAndCommit() error {
 
 	// Atomically update the database cache.  The cache automatically
 	// handles flushing to the underlying persistent storage database.
-	return tx.db.cache.commitTx(tx)
+	if err := tx.db.cache.commitTx(tx); err != nil {
+		return err
+	}
+
+	// Remove any block files that were pruned by the transaction.  The cache
+	// is flushed first so the removal of the pruned blocks from the block
+	// index is persisted before their data is deleted.  Otherwise, an
+	// unexpected shutdown could result in a block index with entries that
+	// reference block files which no longer exist.
+	if len(tx.pendingPruneFiles) > 0 {
+		if err := tx.db.cache.flush(); err != nil {
+			return err
+		}
+		tx.db.store.removeFiles(tx.pendingPruneFiles)
+	}
+
+	return nil
 }
 
 // Commit commits all changes that have been made to the root metadata bucket
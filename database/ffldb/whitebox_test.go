@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/database/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/wire"
@@ -676,3 +677,278 @@ func TestFailureScenarios(t *testing.T) {
 	// Test various corruption scenarios.
 	testCorruption(tc)
 }
+
+// TestPruneBlocks ensures pruning block data removes the oldest block files and
+// their block index entries, respects the blocks that must be kept, and that
+// the database can be reopened after the oldest block files were removed.
+func TestPruneBlocks(t *testing.T) {
+	// Create a new database to run tests against.
+	dbPath := t.TempDir()
+	idb, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer func() {
+		if idb != nil {
+			idb.Close()
+		}
+	}()
+
+	// Change the maximum file size to a small value to force multiple flat
+	// files with the test data set.
+	idb.(*db).store.maxBlockFileSize = 1024 // 1KiB
+
+	// Store the first several test blocks.
+	blocks, err := loadBlocks(t, blockDataFile, blockDataNet)
+	if err != nil {
+		t.Fatalf("loadBlocks: Unexpected error: %v", err)
+	}
+	const numBlocks = 20
+	blocks = blocks[:numBlocks]
+	for i, block := range blocks {
+		err := idb.Update(func(tx database.Tx) error {
+			return tx.StoreBlock(block)
+		})
+		if err != nil {
+			t.Fatalf("StoreBlock #%d: unexpected error: %v", i, err)
+		}
+	}
+	files, err := scanBlockFileSizes(dbPath)
+	if err != nil {
+		t.Fatalf("scanBlockFileSizes: unexpected error: %v", err)
+	}
+	if len(files) < 3 {
+		t.Fatalf("expected at least 3 block files, got %d", len(files))
+	}
+
+	// pruneBlocks is a helper that prunes with the provided target size and
+	// keep function and returns the pruned block hashes.
+	pruneBlocks := func(targetSize uint64, keep func(*chainhash.Hash) bool) []chainhash.Hash {
+		t.Helper()
+		var pruned []chainhash.Hash
+		err := idb.Update(func(tx database.Tx) error {
+			var err error
+			pruned, err = tx.(database.BlockPruner).PruneBlocks(targetSize,
+				keep)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("PruneBlocks: unexpected error: %v", err)
+		}
+		return pruned
+	}
+
+	// Ensure attempting to prune in a read-only transaction fails.
+	err = idb.View(func(tx database.Tx) error {
+		_, err := tx.(database.BlockPruner).PruneBlocks(0, nil)
+		return err
+	})
+	if !errors.Is(err, database.ErrTxNotWritable) {
+		t.Fatalf("PruneBlocks: unexpected error -- got %v, want %v", err,
+			database.ErrTxNotWritable)
+	}
+
+	// Ensure nothing is pruned when the target is not exceeded or the oldest
+	// file contains a block that must be kept.
+	if pruned := pruneBlocks(1<<40, nil); len(pruned) != 0 {
+		t.Fatalf("unexpected pruned blocks with large target: %v", pruned)
+	}
+	keepFirst := func(hash *chainhash.Hash) bool {
+		return *hash == *blocks[0].Hash()
+	}
+	if pruned := pruneBlocks(0, keepFirst); len(pruned) != 0 {
+		t.Fatalf("unexpected pruned blocks when keeping first: %v", pruned)
+	}
+	var beenPruned bool
+	err = idb.View(func(tx database.Tx) error {
+		var err error
+		beenPruned, err = tx.(database.BlockPruner).BeenPruned()
+		return err
+	})
+	if err != nil || beenPruned {
+		t.Fatalf("BeenPruned: unexpected result %v (err %v)", beenPruned, err)
+	}
+
+	// Prune everything possible and ensure only the current write file
+	// remains along with the blocks it contains.
+	pruned := pruneBlocks(0, nil)
+	if len(pruned) == 0 {
+		t.Fatal("no blocks were pruned")
+	}
+	remaining, err := scanBlockFileSizes(dbPath)
+	if err != nil {
+		t.Fatalf("scanBlockFileSizes: unexpected error: %v", err)
+	}
+	lastFile := files[len(files)-1]
+	if len(remaining) != 1 || remaining[0] != lastFile {
+		t.Fatalf("unexpected remaining block files -- got %v, want %v",
+			remaining, []blockFileInfo{lastFile})
+	}
+	prunedSet := make(map[chainhash.Hash]struct{}, len(pruned))
+	for _, hash := range pruned {
+		prunedSet[hash] = struct{}{}
+	}
+	checkBlocks := func(idb database.DB) {
+		t.Helper()
+		err := idb.View(func(tx database.Tx) error {
+			beenPruned, err := tx.(database.BlockPruner).BeenPruned()
+			if err != nil {
+				return err
+			}
+			if !beenPruned {
+				return errors.New("database not marked as pruned")
+			}
+			for i, block := range blocks {
+				_, wantPruned := prunedSet[*block.Hash()]
+				hasBlock, err := tx.HasBlock(block.Hash())
+				if err != nil {
+					return err
+				}
+				if hasBlock == wantPruned {
+					return fmt.Errorf("block #%d: unexpected has block "+
+						"result %v", i, hasBlock)
+				}
+				_, err = tx.FetchBlock(block.Hash())
+				if wantPruned && !errors.Is(err, database.ErrBlockNotFound) {
+					return fmt.Errorf("block #%d: unexpected fetch error "+
+						"-- got %v, want %v", i, err,
+						database.ErrBlockNotFound)
+				}
+				if !wantPruned && err != nil {
+					return fmt.Errorf("block #%d: unexpected fetch "+
+						"error: %v", i, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkBlocks(idb)
+
+	// Ensure the database can be reopened with the oldest block files missing
+	// and that the write cursor is positioned at the end of the latest file.
+	if err := idb.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+	idb, err = database.Open(dbType, dbPath, blockDataNet)
+	if err != nil {
+		idb = nil
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	wc := idb.(*db).store.writeCursor
+	if wc.curFileNum != lastFile.num || uint64(wc.curOffset) != lastFile.size {
+		t.Fatalf("unexpected write cursor -- got file %d offset %d, want "+
+			"file %d offset %d", wc.curFileNum, wc.curOffset, lastFile.num,
+			lastFile.size)
+	}
+	checkBlocks(idb)
+}
+
+// TestVerifyBlockStore ensures verifying the block store detects corrupt block
+// data as well as missing and orphaned block files.
+func TestVerifyBlockStore(t *testing.T) {
+	// Create a new database to run tests against.
+	dbPath := t.TempDir()
+	idb, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer func() {
+		if idb != nil {
+			idb.Close()
+		}
+	}()
+
+	// Change the maximum file size to a small value to force multiple flat
+	// files with the test data set.
+	idb.(*db).store.maxBlockFileSize = 1024 // 1KiB
+
+	// Store the first several test blocks.
+	blocks, err := loadBlocks(t, blockDataFile, blockDataNet)
+	if err != nil {
+		t.Fatalf("loadBlocks: Unexpected error: %v", err)
+	}
+	const numBlocks = 20
+	blocks = blocks[:numBlocks]
+	for i, block := range blocks {
+		err := idb.Update(func(tx database.Tx) error {
+			return tx.StoreBlock(block)
+		})
+		if err != nil {
+			t.Fatalf("StoreBlock #%d: unexpected error: %v", i, err)
+		}
+	}
+
+	// verify is a helper that verifies the block store and returns the
+	// resulting report.
+	verify := func(idb database.DB) *database.BlockStoreReport {
+		t.Helper()
+		var report *database.BlockStoreReport
+		err := idb.View(func(tx database.Tx) error {
+			var err error
+			verifier := tx.(database.BlockStoreVerifier)
+			report, err = verifier.VerifyBlockStore(nil)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("VerifyBlockStore: unexpected error: %v", err)
+		}
+		return report
+	}
+
+	// Ensure the report for an intact block store does not contain any
+	// problems.
+	report := verify(idb)
+	if report.NumBlocks != numBlocks || len(report.BadBlocks) != 0 ||
+		len(report.MissingFiles) != 0 || len(report.OrphanedFiles) != 0 {
+
+		t.Fatalf("unexpected report for intact block store: %+v", report)
+	}
+
+	// Corrupt the data for a block in the middle of the stored blocks and
+	// add a block file that is not referenced by the block index.
+	const corruptIdx = numBlocks / 2
+	var corruptLoc blockLocation
+	err = idb.View(func(tx database.Tx) error {
+		blockRow, err := tx.(*transaction).fetchBlockRow(blocks[corruptIdx].Hash())
+		if err != nil {
+			return err
+		}
+		corruptLoc = deserializeBlockLoc(blockRow)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fetchBlockRow: unexpected error: %v", err)
+	}
+	store := idb.(*db).store
+	f, err := os.OpenFile(blockFilePath(dbPath, corruptLoc.blockFileNum),
+		os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: unexpected error: %v", err)
+	}
+	_, err = f.WriteAt([]byte{0xff, 0xff}, int64(corruptLoc.fileOffset)+100)
+	f.Close()
+	if err != nil {
+		t.Fatalf("WriteAt: unexpected error: %v", err)
+	}
+	orphanNum := store.writeCursor.curFileNum + 2
+	err = os.WriteFile(blockFilePath(dbPath, orphanNum), nil, 0644)
+	if err != nil {
+		t.Fatalf("WriteFile: unexpected error: %v", err)
+	}
+
+	// Ensure the corrupt block and orphaned file are reported.
+	report = verify(idb)
+	if len(report.BadBlocks) != 1 ||
+		report.BadBlocks[0].Hash != *blocks[corruptIdx].Hash() ||
+		!errors.Is(report.BadBlocks[0].Err, database.ErrCorruption) {
+
+		t.Fatalf("unexpected bad blocks: %+v", report.BadBlocks)
+	}
+	if len(report.OrphanedFiles) != 1 || report.OrphanedFiles[0] != orphanNum {
+		t.Fatalf("unexpected orphaned files -- got %v, want %v",
+			report.OrphanedFiles, []uint32{orphanNum})
+	}
+}
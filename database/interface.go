@@ -420,6 +420,93 @@ type Tx interface {
 	Rollback() error
 }
 
+// BlockPruner is an optional interface that may be implemented by a Tx to
+// support removing old block data from the block storage.  Callers should use a
+// type assertion on the transaction to determine if the backend supports it.
+type BlockPruner interface {
+	// PruneBlocks deletes the oldest stored block data as needed to bring
+	// the total size of all stored blocks to at or below the provided
+	// target size in bytes.  Block data is removed oldest first in
+	// implementation-specific units, such as entire flat files, and pruning
+	// stops before removing any unit that contains a block for which the
+	// provided keep function returns true.  The most recently written block
+	// data is never removed.
+	//
+	// The hashes of all blocks that were removed are returned.  The blocks
+	// will no longer be reported as existing by HasBlock and attempting to
+	// fetch them will return ErrBlockNotFound.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrTxNotWritable if attempted against a read-only transaction
+	//   - ErrTxClosed if the transaction has already been closed
+	PruneBlocks(targetSize uint64, keep func(hash *chainhash.Hash) bool) ([]chainhash.Hash, error)
+
+	// BeenPruned returns whether or not block data has ever been pruned from
+	// the database.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrTxClosed if the transaction has already been closed
+	BeenPruned() (bool, error)
+}
+
+// BadBlock describes a block in the block index for which the stored block
+// data is missing or fails integrity checks.
+type BadBlock struct {
+	// Hash is the hash of the block.
+	Hash chainhash.Hash
+
+	// File and Offset identify where the block data is stored.  Their exact
+	// meaning is implementation specific, such as the flat file number and
+	// the byte offset of the block record within it.
+	File   uint32
+	Offset uint32
+
+	// Err describes why the block is considered bad.
+	Err error
+}
+
+// BlockStoreReport houses the results of verifying the integrity of the block
+// storage.
+type BlockStoreReport struct {
+	// NumBlocks is the number of blocks in the block index and NumBytes is
+	// the total number of bytes used to store them.
+	NumBlocks int
+	NumBytes  uint64
+
+	// NumFiles is the number of units, such as flat files, the block
+	// storage is made of.
+	NumFiles int
+
+	// BadBlocks are the blocks for which the stored data is missing or
+	// corrupt in the order they were stored.
+	BadBlocks []BadBlock
+
+	// MissingFiles are the units referenced by the block index which do not
+	// exist and OrphanedFiles are the units which exist but are not
+	// referenced by any block in the block index.
+	MissingFiles  []uint32
+	OrphanedFiles []uint32
+}
+
+// BlockStoreVerifier is an optional interface that may be implemented by a Tx
+// to support offline verification of the block storage.  Callers
+// should use a type assertion on the transaction to determine if the backend
+// supports it.
+type BlockStoreVerifier interface {
+	// VerifyBlockStore checks the stored data of every block in the block
+	// index against the integrity information it was stored with and
+	// reports any storage that is missing or not referenced by the index.
+	// The optional progress function is periodically invoked with the
+	// number of blocks checked so far and the total number of blocks.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrTxClosed if the transaction has already been closed
+	VerifyBlockStore(progress func(checked, total int)) (*BlockStoreReport, error)
+}
+
 // DB provides a generic interface that is used to store blocks and related
 // metadata.  This interface is intended to be agnostic to the actual mechanism
 // used for backend data storage.  The RegisterDriver function can be used to
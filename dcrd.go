@@ -197,15 +197,45 @@ func dcrdMain() error {
 		return nil
 	}
 
+	// Initialize the databases from a chain state snapshot when requested.
+	if cfg.LoadSnapshot != "" {
+		err := loadUtxoSnapshot(ctx, db, utxoDb, cfg.params.Params)
+		if err != nil {
+			dcrdLog.Errorf("Unable to load chain state snapshot: %v", err)
+			return err
+		}
+
+		// Return now if a shutdown signal was triggered.
+		if shutdownRequested(ctx) {
+			return nil
+		}
+	}
+
 	// Always drop the legacy address index if needed and drop any other indexes
 	// and exit if requested.
 	//
 	// NOTE: The order is important here because dropping the tx index also
 	// drops the address index since it relies on it.
-	if err := indexers.DropAddrIndex(ctx, db); err != nil {
+	if err := indexers.DropLegacyAddrIndex(ctx, db); err != nil {
 		dcrdLog.Errorf("%v", err)
 		return err
 	}
+	if cfg.DropAddrIndex {
+		if err := indexers.DropAddrIndex(ctx, db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
+	if cfg.DropSpendIndex {
+		if err := indexers.DropSpendIndex(ctx, db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
 	if cfg.DropTxIndex {
 		if err := indexers.DropTxIndex(ctx, db); err != nil {
 			dcrdLog.Errorf("%v", err)
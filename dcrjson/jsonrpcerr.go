@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -82,6 +82,8 @@ const (
 	ErrRPCProfilerState     RPCErrorCode = -26
 	ErrRPCDuplicateTx       RPCErrorCode = -40
 	ErrRPCReconsiderFailure RPCErrorCode = -50
+	ErrRPCRescanRequired    RPCErrorCode = -51
+	ErrRPCRateLimited       RPCErrorCode = -52
 )
 
 // Errors that are specific to btcd.
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2022 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -38,6 +38,9 @@ Application Options:
 	                             NOTE: port must be between 1024 and 65536
 	    --cpuprofile=            Write CPU profile to the specified file
 	    --memprofile=            Write mem profile to the specified file
+	    --metricslisten=         Serve Prometheus metrics via HTTP at /metrics on
+	                             the given [addr:]port -- NOTE port must be
+	                             between 1024 and 65536
 	    --testnet                Use the test network
 	    --simnet                 Use the simulation test network
 	    --regnet                 Use the regression test network
@@ -51,12 +54,28 @@ Application Options:
 	                             verification cache (default: 100000)
 	    --utxocachemaxsize=      The maximum size in MiB of the utxo cache
 	                             (default: 150, minimum: 25, maximum: 32768)
+	    --prune=                 Delete old block data as needed to keep the
+	                             stored blocks under the specified target size in
+	                             MiB while retaining recent blocks; 0 disables
+	                             pruning (minimum: 1024) -- NOTE: Not compatible
+	                             with --txindex, --addrindex, or --spendindex
+	    --loadsnapshot=          Initialize a new data directory from the chain
+	                             state snapshot in the specified file and
+	                             validate the history prior to it in the
+	                             background -- NOTE: The snapshot hash must match
+	                             one defined by the network parameters and no
+	                             network defines any yet
 	    --norpc                  Disable built-in RPC server -- NOTE: The RPC
 	                             server is disabled by default if no
-	                             rpcuser/rpcpass or rpclimituser/rpclimitpass is
-	                             specified
+	                             rpcuser/rpcpass, rpclimituser/rpclimitpass, or
+	                             rpcauth is specified
 	    --rpclisten=             Add an interface/port to listen for RPC
 	                             connections (default port: 9109, testnet: 19109)
+	                             or a Unix domain socket in the form unix:<path>
+	    --rpcunixmode=           File permissions in octal for RPC Unix domain
+	                             sockets (default: 0600)
+	    --rpcunixowner=          Owner of RPC Unix domain sockets in the form
+	                             <user>[:<group>] by name or numeric ID
 	-u, --rpcuser=               Username for RPC connections
 	-P, --rpcpass=               Password for RPC connections
 	    --authtype=              Method for RPC client authentication
@@ -66,6 +85,18 @@ Application Options:
 	                             authtype=clientcert
 	    --rpclimituser=          Username for limited RPC connections
 	    --rpclimitpass=          Password for limited RPC connections
+	    --rpcauth=               Add an RPC user with a hashed credential and
+	                             permission group in the form
+	                             <username>:<salt>$<hash>:<group> -- NOTE: Use
+	                             the genrpcauth utility to generate the credential
+	    --rpcgroup=              Add an RPC permission group in the form
+	                             <group>:<method>[,<method>...] -- NOTE: The
+	                             builtin admin and limited groups may be included
+	                             as @admin and @limited
+	    --rpcclientcert=         Map the subject of TLS client certificates to an
+	                             RPC permission group in the form
+	                             <subject>:<group> (e.g. CN=dcrwallet:admin);
+	                             requires authtype=clientcert
 	    --rpccert=               File containing the certificate file
 	    --rpckey=                File containing the certificate key
 	    --tlscurve=              Curve to use when generating the TLS keypair
@@ -82,6 +113,21 @@ Application Options:
 	                             (default: 25)
 	    --rpcmaxconcurrentreqs=  Max number of concurrent RPC requests that may
 	                             be processed concurrently (default: 20)
+	    --rpcratelimit=          Sustained request weight per second permitted
+	                             for each RPC user and remote IP outside of the
+	                             admin permission group -- 0 to disable
+	                             (default: 20)
+	    --rpcrateburst=          Max request weight each rate limited RPC user
+	                             and remote IP may accumulate for bursts of
+	                             requests (default: 100)
+	    --restlisten=            Serve the unauthenticated read-only REST
+	                             interface via HTTP at /rest/ on the given
+	                             [addr:]port -- NOTE port must be between 1024
+	                             and 65536
+	    --zmqpub=                Add a ZeroMQ compatible endpoint to publish the
+	                             rawblock, hashblock, rawtx, hashtx, and
+	                             blockdisconnected topics on
+	                             (tcp://[addr]:port or ipc://path)
 	    --proxy=                 Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)
 	    --proxyuser=             Username for proxy server
 	    --proxypass=             Password for proxy server
@@ -92,6 +138,8 @@ Application Options:
 	    --noonion                Disable connecting to tor hidden services
 	    --torisolation           Enable Tor stream isolation by randomizing user
 	                             credentials for each connection
+	    --i2pproxy=              Connect to I2P destinations via SOCKS5 proxy
+	                             (eg. 127.0.0.1:4447)
 	-a, --addpeer=               Add a peer to connect with at startup
 	    --connect=               Connect only to the specified peers at startup
 	    --nolisten               Disable listening for incoming connections --
@@ -112,6 +160,14 @@ Application Options:
 	    --peeridletimeout        The duration of inactivity before a peer is
 	                             timed out.  Valid time units are {s,m,h}.
 	                             Minimum 15 seconds (default: 2m0s)
+	    --nop2pencryption        Disable opportunistic encryption of connections
+	                             with peers that support it
+	    --asmap=                 File containing a map of IP addresses to the
+	                             autonomous system numbers (ASNs) that announce
+	                             them in the compact binary trie format used by
+	                             Bitcoin Core -- When set, known addresses and
+	                             outbound peers are diversified by ASN instead
+	                             of by network prefix
 	    --noseeders              Disable seeding for peer discovery
 	    --nodnsseed              DEPRECATED: use --noseeders
 	    --externalip=            Add a public-facing IP to the list of local
@@ -147,7 +203,19 @@ Application Options:
 	                             version of the software
 	    --maxorphantx=           Max number of orphan transactions to keep in
 	                             memory (default: 100)
+	    --maxmempool=            The maximum size in MiB of the transactions to
+	                             keep in the memory pool; the lowest fee rate
+	                             transactions are evicted when it is exceeded
+	                             (default: 300, minimum: 5)
+	    --nopersistmempool       Do not save the memory pool to disk on shutdown
+	                             and reload it on startup
 	    --blocksonly             Do not accept transactions from remote peers
+	    --stemrelay              Relay transactions submitted via RPC and
+	                             transactions received in the stem phase from
+	                             other peers along a randomly selected path of
+	                             outbound peers before they are diffused to the
+	                             rest of the network in order to obscure their
+	                             origin
 	    --acceptnonstd           Accept and relay non-standard transactions to
 	                             the network regardless of the default settings
 	                             for the active network
@@ -185,6 +253,15 @@ Application Options:
 	                             getrawtransaction RPC
 	    --droptxindex            Deletes the hash-based transaction index from
 	                             the database on start up and then exits
+	    --addrindex              Maintain a full address-based transaction index
+	                             which makes the searchrawtransactions RPC
+	                             available (requires --txindex)
+	    --dropaddrindex          Deletes the address-based transaction index
+	                             from the database on start up and then exits
+	    --spendindex             Maintain a full spent output index which makes
+	                             the getspendinginfo RPC available
+	    --dropspendindex         Deletes the spent output index from the
+	                             database on start up and then exits
 	    --noexistsaddrindex      Disable the exists address index, which tracks
 	                             whether or not an address has even been used
 	    --dropexistsaddrindex    Deletes the exists address index from the
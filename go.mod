@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/davecgh/go-spew v1.1.1
+	github.com/dchest/siphash v1.2.3
 	github.com/decred/base58 v1.0.6
 	github.com/decred/dcrd/addrmgr/v3 v3.0.0
 	github.com/decred/dcrd/bech32 v1.1.4
@@ -12,7 +13,7 @@ require (
 	github.com/decred/dcrd/blockchain/v5 v5.1.0
 	github.com/decred/dcrd/certgen v1.2.0
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.5
-	github.com/decred/dcrd/chaincfg/v3 v3.3.0
+	github.com/decred/dcrd/chaincfg/v3 v3.4.0
 	github.com/decred/dcrd/connmgr/v3 v3.1.3
 	github.com/decred/dcrd/container/apbf v1.0.1
 	github.com/decred/dcrd/container/lru v1.0.0
@@ -22,16 +23,16 @@ require (
 	github.com/decred/dcrd/database/v3 v3.0.3
 	github.com/decred/dcrd/dcrec v1.0.1
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0
-	github.com/decred/dcrd/dcrjson/v4 v4.2.0
+	github.com/decred/dcrd/dcrjson/v4 v4.3.0
 	github.com/decred/dcrd/dcrutil/v4 v4.0.3
 	github.com/decred/dcrd/gcs/v4 v4.1.1
 	github.com/decred/dcrd/math/uint256 v1.0.2
 	github.com/decred/dcrd/mixing v0.6.0
 	github.com/decred/dcrd/peer/v3 v3.2.0
-	github.com/decred/dcrd/rpc/jsonrpc/types/v4 v4.4.0
+	github.com/decred/dcrd/rpc/jsonrpc/types/v4 v4.5.0
 	github.com/decred/dcrd/rpcclient/v8 v8.1.0
 	github.com/decred/dcrd/txscript/v4 v4.1.2
-	github.com/decred/dcrd/wire v1.7.1
+	github.com/decred/dcrd/wire v1.8.0
 	github.com/decred/dcrtest/dcrdtest v1.0.1-0.20240404170936-a2529e936df1
 	github.com/decred/go-socks v1.1.0
 	github.com/decred/slog v1.2.0
@@ -50,7 +51,6 @@ require (
 	decred.org/cspp/v2 v2.4.0 // indirect
 	github.com/agl/ed25519 v0.0.0-20170116200512-5312a6153412 // indirect
 	github.com/companyzero/sntrup4591761 v0.0.0-20220309191932-9e0f3af2f07a // indirect
-	github.com/dchest/siphash v1.2.3 // indirect
 	github.com/decred/dcrd/dcrec/edwards/v2 v2.0.4 // indirect
 	github.com/decred/dcrd/hdkeychain/v3 v3.1.3 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
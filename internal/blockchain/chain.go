@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -178,7 +178,9 @@ type BlockChain struct {
 	sigCache                 *txscript.SigCache
 	indexSubscriber          *indexers.IndexSubscriber
 	interrupt                <-chan struct{}
+	utxoBackend              UtxoBackend
 	utxoCache                UtxoCacher
+	pruneTarget              uint64
 
 	// subsidyCache is the cache that provides quick lookup of subsidy
 	// values.
@@ -262,6 +264,17 @@ type BlockChain struct {
 	// it is unlikely to be referenced in the future.
 	pruner *chainPruner
 
+	// These fields track the state of block data pruning.  They are protected
+	// by the chain lock.
+	//
+	// beenPruned indicates whether or not any block data has ever been pruned
+	// from the database.
+	//
+	// pruneHeight is the height of the oldest block in the main chain for
+	// which block data is available.
+	beenPruned  bool
+	pruneHeight int64
+
 	// The following maps are various caches for the stake version/voting
 	// system.  The goal of these is to reduce disk access to load blocks
 	// from disk.  Measurements indicate that it is slightly more expensive
@@ -2356,6 +2369,15 @@ type Config struct {
 	//
 	// This field is required.
 	UtxoCache UtxoCacher
+
+	// PruneTarget is the target size in bytes for the stored block data.  When
+	// it is non-zero, the oldest block data is deleted from the database as
+	// needed to remain under the target while always retaining the recent
+	// blocks that are needed for validation and reorganizations.
+	//
+	// The provided database must implement database.BlockPruner when this is
+	// set.
+	PruneTarget uint64
 }
 
 // newRecentBlocksCache returns a new LRU map for more efficient access to
@@ -2466,10 +2488,42 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 		calcPriorStakeVersionCache:    make(map[[chainhash.HashSize]byte]uint32),
 		calcVoterVersionIntervalCache: make(map[[chainhash.HashSize]byte]uint32),
 		calcStakeVersionCache:         make(map[[chainhash.HashSize]byte]uint32),
+		utxoBackend:                   config.UtxoBackend,
 		utxoCache:                     config.UtxoCache,
+		pruneTarget:                   config.PruneTarget,
 	}
 	b.pruner = newChainPruner(&b)
 
+	// Determine whether or not block data has been pruned from the database
+	// and ensure the database supports pruning when it is enabled.
+	err = b.db.View(func(dbTx database.Tx) error {
+		pruner, ok := dbTx.(database.BlockPruner)
+		if !ok {
+			if b.pruneTarget != 0 {
+				return AssertError("blockchain.New database does not " +
+					"support pruning")
+			}
+			return nil
+		}
+		var err error
+		b.beenPruned, err = pruner.BeenPruned()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Block data prior to the window included in a chain state snapshot is
+	// never available when the database was created from one, so treat it as
+	// pruned.
+	snapshotInfo, historyValidated, err := FetchImportedSnapshot(b.db)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotInfo != nil {
+		b.beenPruned = true
+	}
+
 	// Initialize the chain state from the passed database.  When the db
 	// does not yet contain any chain state, both it and the chain state
 	// will be initialized to contain only the genesis block.
@@ -2527,6 +2581,23 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 		b.notifications = curNtfnCallback
 	}
 
+	// Determine the oldest block in the main chain with available block data
+	// when it has been pruned.
+	if b.beenPruned {
+		b.pruneHeight, err = b.findPruneHeight()
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("Block data has been pruned prior to height %d",
+			b.pruneHeight)
+	}
+
+	if snapshotInfo != nil {
+		log.Infof("Chain state was created from the snapshot of block %s "+
+			"(height %d, history validated: %v)", snapshotInfo.BlockHash,
+			snapshotInfo.Height, historyValidated)
+	}
+
 	bestHdr := b.index.BestHeader()
 	log.Infof("Best known header: height %d, hash %v", bestHdr.height,
 		bestHdr.hash)
@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2023 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -618,6 +618,26 @@ const (
 	// performed.
 	ErrUtxoBackendTxClosed = ErrorKind("ErrUtxoBackendTxClosed")
 
+	// ------------------------------------------
+	// Errors related to chain state snapshots.
+	// ------------------------------------------
+
+	// ErrInvalidSnapshot indicates a chain state snapshot is malformed or
+	// otherwise inconsistent.
+	ErrInvalidSnapshot = ErrorKind("ErrInvalidSnapshot")
+
+	// ErrUnknownSnapshot indicates an attempt to import a chain state snapshot
+	// that is not one of the snapshots defined by the network parameters.
+	ErrUnknownSnapshot = ErrorKind("ErrUnknownSnapshot")
+
+	// ErrSnapshotHashMismatch indicates the hash of an imported chain state
+	// snapshot does not match the hash defined by the network parameters.
+	ErrSnapshotHashMismatch = ErrorKind("ErrSnapshotHashMismatch")
+
+	// ErrSnapshotExistingChain indicates an attempt to import a chain state
+	// snapshot into a database that already contains chain state.
+	ErrSnapshotExistingChain = ErrorKind("ErrSnapshotExistingChain")
+
 	// -----------------------------------------------------------------
 	// Errors related to the automatic ticket revocations agenda.
 	// -----------------------------------------------------------------
@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2023 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -156,6 +156,10 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrUtxoBackendCorruption, "ErrUtxoBackendCorruption"},
 		{ErrUtxoBackendNotOpen, "ErrUtxoBackendNotOpen"},
 		{ErrUtxoBackendTxClosed, "ErrUtxoBackendTxClosed"},
+		{ErrInvalidSnapshot, "ErrInvalidSnapshot"},
+		{ErrUnknownSnapshot, "ErrUnknownSnapshot"},
+		{ErrSnapshotHashMismatch, "ErrSnapshotHashMismatch"},
+		{ErrSnapshotExistingChain, "ErrSnapshotExistingChain"},
 		{ErrInvalidRevocationTxVersion, "ErrInvalidRevocationTxVersion"},
 		{ErrNoExpiredTicketRevocation, "ErrNoExpiredTicketRevocation"},
 		{ErrNoMissedTicketRevocation, "ErrNoMissedTicketRevocation"},
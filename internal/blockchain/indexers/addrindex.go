@@ -0,0 +1,1152 @@
+// Copyright (c) 2016 The btcsuite developers
+// Copyright (c) 2016-2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/txscript/v4/stdscript"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// addrIndexName is the human-readable name for the index.
+	addrIndexName = "address index"
+
+	// addrIndexVersion is the current version of the address index.
+	addrIndexVersion = 1
+
+	// level0MaxEntries is the maximum number of transactions that are
+	// stored in level 0 of an address index entry.  Subsequent levels store
+	// 2^n * level0MaxEntries entries, or in words, double the maximum of
+	// the previous level.
+	level0MaxEntries = 8
+
+	// addrKeyLevelSize is the number of bytes a level in the address index
+	// key consumes.  It consists of 1 byte for the level.
+	addrKeyLevelSize = 1
+
+	// levelKeySize is the number of bytes a key in the address index
+	// consumes.  It consists of the address key plus the level.
+	levelKeySize = addrKeySize + addrKeyLevelSize
+
+	// levelOffset is the offset in the level key which identifies the level.
+	levelOffset = levelKeySize - addrKeyLevelSize
+)
+
+var (
+	// addrIndexKey is the key of the address index and the db bucket used
+	// to house it.
+	addrIndexKey = []byte("txbyaddridxv2")
+)
+
+// -----------------------------------------------------------------------------
+// The address index maps addresses referenced in the blockchain to a list of
+// all the transactions involving that address.  Transactions are stored
+// according to their order of appearance in the blockchain.  That is to say
+// first by block height and then by offset inside the block.  It is also
+// important to note that this implementation requires the transaction index
+// since it is needed in order to catch up old blocks due to the fact the spent
+// outputs will already be pruned from the utxo set.
+//
+// The approach used to store the index is similar to a log-structured merge
+// tree (LSM tree) and is thus similar to how leveldb works internally.
+//
+// Every address consists of one or more entries identified by a level starting
+// from 0 where each level holds a maximum number of entries such that each
+// subsequent level holds double the maximum of the previous one.  In equation
+// form, the number of entries each level holds is 2^n * firstLevelMaxSize.
+//
+// New transactions are appended to level 0 until it becomes full at which
+// point the entire level 0 entry is appended to the level 1 entry and level 0
+// is cleared.  This process continues until level 1 becomes full at which
+// point it will be appended to level 2 and cleared and so on.
+//
+// The result of this is the lower levels contain newer transactions and the
+// transactions within each level are ordered from oldest to newest.
+//
+// The intent of this approach is to provide a balance between space efficiency
+// and indexing cost.  Storing one entry per transaction would have the lowest
+// indexing cost, but would waste a lot of space because the same address hash
+// would be duplicated for every transaction key.  On the other hand, storing a
+// single entry with all transactions would be the most space efficient, but
+// would cause indexing cost to grow quadratically with the number of
+// transactions involving the same address.  The approach used here provides
+// logarithmic insertion and retrieval.
+//
+// The serialized key format is:
+//
+//   <addr type><addr hash><level>
+//
+//   Field           Type      Size
+//   addr type       uint8     1 byte
+//   addr hash       hash160   20 bytes
+//   level           uint8     1 byte
+//   -----
+//   Total: 22 bytes
+//
+// The serialized value format is:
+//
+//   [<block id><start offset><tx length><block index>,...]
+//
+//   Field           Type      Size
+//   block id        uint32    4 bytes
+//   start offset    uint32    4 bytes
+//   tx length       uint32    4 bytes
+//   block index     uint32    4 bytes
+//   -----
+//   Total: 16 bytes per indexed tx
+//
+// The block id is the internal block ID assigned by the transaction index which
+// is why the transaction index is a prerequisite of this index.
+// -----------------------------------------------------------------------------
+
+// fetchBlockHashFunc defines a callback function to use in order to convert a
+// serialized block ID to an associated block hash.
+type fetchBlockHashFunc func(serializedID []byte) (*chainhash.Hash, error)
+
+// deserializeAddrIndexEntry decodes the passed serialized byte slice into the
+// provided transaction index entry according to the format described in
+// detail above and uses the passed function to convert the serialized block
+// ID into the associated block hash.
+func deserializeAddrIndexEntry(serialized []byte, entry *TxIndexEntry, fetchBlockHash fetchBlockHashFunc) error {
+	// Ensure there are enough bytes to decode.
+	if len(serialized) < txEntrySize {
+		return makeDbErr(database.ErrCorruption, "unexpected end of data")
+	}
+
+	hash, err := fetchBlockHash(serialized[0:4])
+	if err != nil {
+		return err
+	}
+	region := &entry.BlockRegion
+	region.Hash = hash
+	region.Offset = byteOrder.Uint32(serialized[4:8])
+	region.Len = byteOrder.Uint32(serialized[8:12])
+	entry.BlockIndex = byteOrder.Uint32(serialized[12:16])
+	return nil
+}
+
+// keyForLevel returns the key for a specific address and level in the address
+// index entry.
+func keyForLevel(addrKey [addrKeySize]byte, level uint8) [levelKeySize]byte {
+	var key [levelKeySize]byte
+	copy(key[:], addrKey[:])
+	key[levelOffset] = level
+	return key
+}
+
+// dbPutAddrIndexEntry updates the address index to include the provided entry
+// according to the level-based scheme described in detail above.
+func dbPutAddrIndexEntry(bucket internalBucket, addrKey [addrKeySize]byte, blockID uint32, txLoc wire.TxLoc, blockIndex uint32) error {
+	// Start with level 0 and its initial max number of entries.
+	curLevel := uint8(0)
+	maxLevelBytes := level0MaxEntries * txEntrySize
+
+	// Simply append the new entry to level 0 and return now when it will
+	// fit.  This is the most common path.
+	newData := make([]byte, txEntrySize)
+	putTxIndexEntry(newData, blockID, txLoc, blockIndex)
+	level0Key := keyForLevel(addrKey, 0)
+	level0Data := bucket.Get(level0Key[:])
+	if len(level0Data)+len(newData) <= maxLevelBytes {
+		mergedData := newData
+		if len(level0Data) > 0 {
+			mergedData = make([]byte, len(level0Data)+len(newData))
+			copy(mergedData, level0Data)
+			copy(mergedData[len(level0Data):], newData)
+		}
+		return bucket.Put(level0Key[:], mergedData)
+	}
+
+	// At this point, level 0 is full, so merge each level into higher
+	// levels as many times as needed to free up level 0.
+	prevLevelData := level0Data
+	for {
+		// Each new level holds twice as much as the previous one.
+		curLevel++
+		maxLevelBytes *= 2
+
+		// Move to the next level as long as the current level is full.
+		curLevelKey := keyForLevel(addrKey, curLevel)
+		curLevelData := bucket.Get(curLevelKey[:])
+		if len(curLevelData) == maxLevelBytes {
+			prevLevelData = curLevelData
+			continue
+		}
+
+		// The current level has room for the data in the previous one,
+		// so merge the data from previous level into it.
+		mergedData := prevLevelData
+		if len(curLevelData) > 0 {
+			mergedData = make([]byte, len(curLevelData)+
+				len(prevLevelData))
+			copy(mergedData, curLevelData)
+			copy(mergedData[len(curLevelData):], prevLevelData)
+		}
+		err := bucket.Put(curLevelKey[:], mergedData)
+		if err != nil {
+			return err
+		}
+
+		// Move all of the levels before the previous one up a level.
+		for mergeLevel := curLevel - 1; mergeLevel > 0; mergeLevel-- {
+			mergeLevelKey := keyForLevel(addrKey, mergeLevel)
+			prevLevelKey := keyForLevel(addrKey, mergeLevel-1)
+			prevData := bucket.Get(prevLevelKey[:])
+			err := bucket.Put(mergeLevelKey[:], prevData)
+			if err != nil {
+				return err
+			}
+		}
+		break
+	}
+
+	// Finally, insert the new entry into level 0 now that it is empty.
+	return bucket.Put(level0Key[:], newData)
+}
+
+// dbFetchAddrIndexEntries returns block regions for transactions referenced by
+// the given address key and the number of entries skipped since it could have
+// been less in the case where there are less total entries than the requested
+// number of entries to skip.
+func dbFetchAddrIndexEntries(bucket internalBucket, addrKey [addrKeySize]byte, numToSkip, numRequested uint32, reverse bool, fetchBlockHash fetchBlockHashFunc) ([]TxIndexEntry, uint32, error) {
+	// When the reverse flag is not set, all levels need to be fetched
+	// because numToSkip and numRequested are counted from the oldest
+	// transactions (highest level) and thus the total count is needed.
+	// However, when the reverse flag is set, only enough records to satisfy
+	// the requested amount are needed.
+	var level uint8
+	var serialized []byte
+	numNeeded := uint64(numToSkip) + uint64(numRequested)
+	for !reverse || uint64(len(serialized)) < numNeeded*txEntrySize {
+		curLevelKey := keyForLevel(addrKey, level)
+		levelData := bucket.Get(curLevelKey[:])
+		if levelData == nil {
+			// Stop when there are no more levels.
+			break
+		}
+
+		// Higher levels contain older transactions, so prepend them.
+		prepended := make([]byte, len(serialized)+len(levelData))
+		copy(prepended, levelData)
+		copy(prepended[len(levelData):], serialized)
+		serialized = prepended
+		level++
+	}
+
+	// When the requested number of entries to skip is larger than the
+	// number available, skip them all and return now with the actual number
+	// skipped.
+	numEntries := uint32(len(serialized) / txEntrySize)
+	if numToSkip >= numEntries {
+		return nil, numEntries, nil
+	}
+
+	// Nothing more to do when there are no requested entries.
+	if numRequested == 0 {
+		return nil, numToSkip, nil
+	}
+
+	// Limit the number to load based on the number of available entries,
+	// the number to skip, and the number requested.
+	numToLoad := numEntries - numToSkip
+	if numToLoad > numRequested {
+		numToLoad = numRequested
+	}
+
+	// Start the offset after all skipped entries and load the calculated
+	// number.
+	results := make([]TxIndexEntry, numToLoad)
+	for i := uint32(0); i < numToLoad; i++ {
+		// Calculate the read offset according to the reverse flag.
+		var offset uint32
+		if reverse {
+			offset = (numEntries - numToSkip - i - 1) * txEntrySize
+		} else {
+			offset = (numToSkip + i) * txEntrySize
+		}
+
+		// Deserialize and populate the result.
+		err := deserializeAddrIndexEntry(serialized[offset:], &results[i],
+			fetchBlockHash)
+		if err != nil {
+			// Ensure any deserialization errors are returned as
+			// database corruption errors.
+			var dbErr database.Error
+			if !errors.As(err, &dbErr) {
+				str := fmt.Sprintf("failed to deserialize address "+
+					"index for key %x: %v", addrKey, err)
+				err = makeDbErr(database.ErrCorruption, str)
+			}
+
+			return nil, 0, err
+		}
+	}
+
+	return results, numToSkip, nil
+}
+
+// minEntriesToReachLevel returns the minimum number of entries that are
+// required to reach the given address index level.
+func minEntriesToReachLevel(level uint8) int {
+	maxEntriesForLevel := level0MaxEntries
+	minRequired := 1
+	for l := uint8(1); l <= level; l++ {
+		minRequired += maxEntriesForLevel
+		maxEntriesForLevel *= 2
+	}
+	return minRequired
+}
+
+// maxEntriesForLevel returns the maximum number of entries allowed for the
+// given address index level.
+func maxEntriesForLevel(level uint8) int {
+	numEntries := level0MaxEntries
+	for l := level; l > 0; l-- {
+		numEntries *= 2
+	}
+	return numEntries
+}
+
+// dbRemoveAddrIndexEntries removes the specified number of entries from from
+// the address index for the provided key.  An assertion error will be returned
+// if the count exceeds the total number of entries in the index.
+func dbRemoveAddrIndexEntries(bucket internalBucket, addrKey [addrKeySize]byte, count int) error {
+	// Nothing to do if no entries are being deleted.
+	if count <= 0 {
+		return nil
+	}
+
+	// Make use of a local map to track pending updates and define a closure
+	// to apply it to the database.  This is done in order to reduce the
+	// number of database reads and because there is more than one exit
+	// path that needs to apply the updates.
+	pendingUpdates := make(map[uint8][]byte)
+	applyPending := func() error {
+		for level, data := range pendingUpdates {
+			curLevelKey := keyForLevel(addrKey, level)
+			if len(data) == 0 {
+				err := bucket.Delete(curLevelKey[:])
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			err := bucket.Put(curLevelKey[:], data)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Loop forwards through the levels while removing entries until the
+	// specified number has been removed.  This will potentially result in
+	// entirely empty lower levels which will be backfilled below.
+	var highestLoadedLevel uint8
+	numRemaining := count
+	for level := uint8(0); numRemaining > 0; level++ {
+		// Load the data for the level from the database.
+		curLevelKey := keyForLevel(addrKey, level)
+		curLevelData := bucket.Get(curLevelKey[:])
+		if len(curLevelData) == 0 && numRemaining > 0 {
+			return AssertError(fmt.Sprintf("dbRemoveAddrIndexEntries "+
+				"not enough entries for address key %x to "+
+				"delete %d entries", addrKey, count))
+		}
+		pendingUpdates[level] = curLevelData
+		highestLoadedLevel = level
+
+		// Delete the entire level as needed.
+		numEntries := len(curLevelData) / txEntrySize
+		if numRemaining >= numEntries {
+			pendingUpdates[level] = nil
+			numRemaining -= numEntries
+			continue
+		}
+
+		// Remove remaining entries to delete from the level.
+		offsetEnd := len(curLevelData) - (numRemaining * txEntrySize)
+		pendingUpdates[level] = curLevelData[:offsetEnd]
+		break
+	}
+
+	// When all elements in level 0 were not removed there is nothing left
+	// to do other than updating the database.
+	if len(pendingUpdates[0]) != 0 {
+		return applyPending()
+	}
+
+	// At this point there are one or more empty levels before the current
+	// level which need to be backfilled and the current level might have
+	// had some entries deleted from it as well.  Since all levels after
+	// level 0 are required to either be empty, half full, or completely
+	// full, the current level must be adjusted accordingly by backfilling
+	// each previous levels in a way which satisfies the requirements.  Any
+	// entries that are left are assigned to level 0 after the loop as they
+	// are guaranteed to fit by the logic in the loop.  In other words, this
+	// effectively squashes all remaining entries in the current level into
+	// the lowest possible levels while following the level rules.
+	//
+	// Note that the level after the current level might also have entries
+	// and gaps are not allowed, so this also keeps track of the lowest
+	// empty level so the code below knows how far to backfill in case it is
+	// required.
+	lowestEmptyLevel := uint8(255)
+	curLevelData := pendingUpdates[highestLoadedLevel]
+	curLevelMaxEntries := maxEntriesForLevel(highestLoadedLevel)
+	for level := highestLoadedLevel; level > 0; level-- {
+		// When there are not enough entries left in the current level
+		// for the number that would be required to reach it, clear the
+		// the current level which effectively moves them all up to the
+		// previous level on the next iteration.  Otherwise, there are
+		// are sufficient entries, so update the current level to
+		// contain as many entries as possible while still leaving
+		// enough remaining entries required to reach the level.
+		numEntries := len(curLevelData) / txEntrySize
+		prevLevelMaxEntries := curLevelMaxEntries / 2
+		minPrevRequired := minEntriesToReachLevel(level - 1)
+		if numEntries < prevLevelMaxEntries+minPrevRequired {
+			lowestEmptyLevel = level
+			pendingUpdates[level] = nil
+		} else {
+			// This level can only be completely full or half full,
+			// so choose the appropriate offset to ensure enough
+			// entries remain to reach the level.
+			var offset int
+			if numEntries-curLevelMaxEntries >= minPrevRequired {
+				offset = curLevelMaxEntries * txEntrySize
+			} else {
+				offset = prevLevelMaxEntries * txEntrySize
+			}
+			pendingUpdates[level] = curLevelData[:offset]
+			curLevelData = curLevelData[offset:]
+		}
+
+		curLevelMaxEntries = prevLevelMaxEntries
+	}
+	pendingUpdates[0] = curLevelData
+	if len(curLevelData) == 0 {
+		lowestEmptyLevel = 0
+	}
+
+	// When the highest loaded level is empty, it's possible the level after
+	// it still has data and thus that data needs to be backfilled as well.
+	for len(pendingUpdates[highestLoadedLevel]) == 0 {
+		// When the next level is empty too, the is no data left to
+		// continue backfilling, so there is nothing left to do.
+		// Otherwise, populate the pending updates map with the newly
+		// loaded data and update the highest loaded level accordingly.
+		level := highestLoadedLevel + 1
+		curLevelKey := keyForLevel(addrKey, level)
+		levelData := bucket.Get(curLevelKey[:])
+		if len(levelData) == 0 {
+			break
+		}
+		pendingUpdates[level] = levelData
+		highestLoadedLevel = level
+
+		// At this point the highest level is not empty, but it might
+		// be half full.  When that is the case, move it up a level to
+		// simplify the code below which backfills all lower levels that
+		// are still empty.  This also means the current level will be
+		// empty, so the loop will perform another another iteration to
+		// potentially backfill this level with data from the next one.
+		curLevelMaxEntries := maxEntriesForLevel(level)
+		if len(levelData)/txEntrySize != curLevelMaxEntries {
+			pendingUpdates[level] = nil
+			pendingUpdates[level-1] = levelData
+			level--
+			curLevelMaxEntries /= 2
+		}
+
+		// Backfill all lower levels that are still empty by iteratively
+		// halfing the data until the lowest empty level is filled.
+		for level > lowestEmptyLevel {
+			offset := (curLevelMaxEntries / 2) * txEntrySize
+			pendingUpdates[level] = levelData[:offset]
+			levelData = levelData[offset:]
+			pendingUpdates[level-1] = levelData
+			level--
+			curLevelMaxEntries /= 2
+		}
+
+		// The lowest possible empty level is now the highest loaded
+		// level.
+		lowestEmptyLevel = highestLoadedLevel
+	}
+
+	// Apply the pending updates.
+	return applyPending()
+}
+
+// PrevScripter defines an interface that provides access to scripts and their
+// associated version keyed by an outpoint.  The boolean return indicates
+// whether or not the script and version for the provided outpoint was found.
+type PrevScripter interface {
+	PrevScript(*wire.OutPoint) (uint16, []byte, bool)
+}
+
+// prevScriptFetcher defines a callback function used to load the script and
+// associated version of the previous output referenced by a transaction input.
+type prevScriptFetcher func(prevOut *wire.OutPoint) (uint16, []byte, error)
+
+// isNullOutpoint determines whether or not a previous transaction outpoint is
+// set to the special null value used by coinbases, stakebases, treasurybases
+// and treasury spends which do not reference any previous outputs.
+func isNullOutpoint(prevOut *wire.OutPoint) bool {
+	return prevOut.Index == wire.MaxPrevOutIndex &&
+		prevOut.Hash == (chainhash.Hash{})
+}
+
+// AddrIndex implements a transaction by address index.  That is to say, it
+// supports querying all transactions that reference a given address because
+// they are either crediting or debiting the address.  The returned
+// transactions are ordered according to their order of appearance in the
+// blockchain.  In other words, first by block height and then by offset inside
+// the block.
+//
+// In addition, support is provided for a memory-only index of unconfirmed
+// transactions such as those which are kept in the memory pool before inclusion
+// in a block.
+type AddrIndex struct {
+	// The following fields are set when the instance is created and can't
+	// be changed afterwards, so there is no need to protect them with a
+	// separate mutex.
+	db          database.DB
+	chain       ChainQueryer
+	chainParams *chaincfg.Params
+	sub         *IndexSubscription
+
+	// The following fields are used to quickly link transactions and
+	// addresses that have not been included into a block yet when an
+	// address index is being maintained.  The are protected by the
+	// unconfirmedLock field.
+	//
+	// The txnsByAddr field is used to keep an index of all transactions
+	// which either create an output to a given address or spend from a
+	// previous output to it keyed by the address.
+	//
+	// The addrsByTx field is essentially the reverse and is used to
+	// keep an index of all addresses which a given transaction involves.
+	// This allows fairly efficient updates when transactions are removed
+	// once they are included into a block.
+	unconfirmedLock sync.RWMutex
+	txnsByAddr      map[[addrKeySize]byte]map[chainhash.Hash]*dcrutil.Tx
+	addrsByTx       map[chainhash.Hash]map[[addrKeySize]byte]struct{}
+
+	subscribers map[chan bool]struct{}
+	mtx         sync.Mutex
+	cancel      context.CancelFunc
+}
+
+// Ensure the AddrIndex type implements the Indexer interface.
+var _ Indexer = (*AddrIndex)(nil)
+
+// Ensure the AddrIndex type implements the IndexDropper interface.
+var _ IndexDropper = (*AddrIndex)(nil)
+
+// NewAddrIndex returns a new instance of an indexer that is used to create a
+// mapping of all addresses in the blockchain to their respective transactions
+// that involve them.
+//
+// The address index depends on the transaction index which must be subscribed
+// to the provided subscriber prior to calling this function.
+func NewAddrIndex(subscriber *IndexSubscriber, db database.DB, chain ChainQueryer) (*AddrIndex, error) {
+	idx := &AddrIndex{
+		db:          db,
+		chain:       chain,
+		chainParams: chain.ChainParams(),
+		txnsByAddr:  make(map[[addrKeySize]byte]map[chainhash.Hash]*dcrutil.Tx),
+		addrsByTx:   make(map[chainhash.Hash]map[[addrKeySize]byte]struct{}),
+		subscribers: make(map[chan bool]struct{}),
+		cancel:      subscriber.cancel,
+	}
+
+	// The address index is an optional index.  It relies on the internal
+	// block IDs and transaction locations maintained by the transaction index,
+	// so it is a dependent of it and is updated asynchronously.
+	sub, err := subscriber.Subscribe(idx, txIndexName)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.sub = sub
+
+	err = idx.Init(subscriber.ctx, chain.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// Init initializes the address index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Init(ctx context.Context, chainParams *chaincfg.Params) error {
+	if interruptRequested(ctx) {
+		return indexerError(ErrInterruptRequested, interruptMsg)
+	}
+
+	// Finish any drops that were previously interrupted.
+	if err := finishDrop(ctx, idx); err != nil {
+		return err
+	}
+
+	// Create the initial state for the index as needed.
+	if err := createIndex(idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Upgrade the index as needed.
+	if err := upgradeIndex(ctx, idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Recover the address index to the main chain if needed.
+	return recoverIndex(ctx, idx)
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Key() []byte {
+	return addrIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Name() string {
+	return addrIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Version() uint32 {
+	return addrIndexVersion
+}
+
+// DB returns the database of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) DB() database.DB {
+	return idx.db
+}
+
+// Queryer returns the chain queryer.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Queryer() ChainQueryer {
+	return idx.chain
+}
+
+// Tip returns the current tip of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Tip() (int64, *chainhash.Hash, error) {
+	return tip(idx.db, idx.Key())
+}
+
+// Create is invoked when the index is created for the first time.  It creates
+// the bucket for the address index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(addrIndexKey)
+	return err
+}
+
+// IndexSubscription returns the subscription for index updates.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) IndexSubscription() *IndexSubscription {
+	return idx.sub
+}
+
+// NotifySyncSubscribers signals subscribers of an index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) NotifySyncSubscribers() {
+	idx.mtx.Lock()
+	notifySyncSubscribers(idx.subscribers)
+	idx.mtx.Unlock()
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) WaitForSync() chan bool {
+	c := make(chan bool)
+
+	idx.mtx.Lock()
+	idx.subscribers[c] = struct{}{}
+	idx.mtx.Unlock()
+
+	return c
+}
+
+// txAddrKeys returns the unique address keys for all addresses the provided
+// transaction involves in the order they are first encountered.  That includes
+// the addresses of the previous outputs spent by the inputs, which are loaded
+// via the provided fetcher, as well as the addresses paid by the outputs along
+// with any ticket commitment addresses.
+//
+// Unsupported address types and non-standard scripts are ignored.
+func (idx *AddrIndex) txAddrKeys(tx *wire.MsgTx, fetchPrevScript prevScriptFetcher) ([][addrKeySize]byte, error) {
+	var addrKeys [][addrKeySize]byte
+	seen := make(map[[addrKeySize]byte]struct{})
+	addAddrs := func(addrs []stdaddr.Address) {
+		for _, addr := range addrs {
+			k, err := addrToKey(addr)
+			if err != nil {
+				// Ignore unsupported address types.
+				continue
+			}
+			if _, ok := seen[k]; ok {
+				continue
+			}
+
+			seen[k] = struct{}{}
+			addrKeys = append(addrKeys, k)
+		}
+	}
+
+	for _, txIn := range tx.TxIn {
+		// Inputs that do not reference a previous output such as those of
+		// coinbases, stakebases, treasurybases, and treasury spends do not
+		// involve any addresses.
+		prevOut := &txIn.PreviousOutPoint
+		if isNullOutpoint(prevOut) {
+			continue
+		}
+
+		version, pkScript, err := fetchPrevScript(prevOut)
+		if err != nil {
+			return nil, err
+		}
+		_, addrs := stdscript.ExtractAddrs(version, pkScript, idx.chainParams)
+		addAddrs(addrs)
+	}
+
+	isSStx := stake.IsSStx(tx)
+	for _, txOut := range tx.TxOut {
+		scriptType, addrs := stdscript.ExtractAddrs(txOut.Version,
+			txOut.PkScript, idx.chainParams)
+		if scriptType == stdscript.STNonStandard {
+			// Non-standard outputs are skipped.
+			continue
+		}
+
+		if isSStx && scriptType == stdscript.STNullData {
+			addr, err := stake.AddrFromSStxPkScrCommitment(txOut.PkScript,
+				idx.chainParams)
+			if err != nil {
+				// Ignore unsupported address types.
+				continue
+			}
+
+			addrs = append(addrs, addr)
+		}
+
+		addAddrs(addrs)
+	}
+
+	return addrKeys, nil
+}
+
+// blockPrevScriptFetcher returns a function that loads the scripts of the
+// previous outputs referenced by the transactions in the provided block.
+//
+// The previous outputs are first looked up in the block itself and otherwise
+// loaded from the transaction index.  Note that the spend journal can't be used
+// here since it is removed by the chain when a block is disconnected which
+// might happen before the index processes the disconnect.
+//
+// When a chain queryer is provided, transactions that are no longer in the
+// transaction index are additionally searched for in the ancestors of the block
+// that are not part of the main chain.  This is necessary when disconnecting
+// blocks during recovery since the transaction index is recovered first and
+// therefore will have already removed the transactions from those blocks.
+func blockPrevScriptFetcher(dbTx database.Tx, block *dcrutil.Block, queryer ChainQueryer) prevScriptFetcher {
+	txns := make(map[chainhash.Hash]*wire.MsgTx, len(block.Transactions())+
+		len(block.STransactions()))
+	addBlockTxns := func(block *dcrutil.Block) {
+		for _, tx := range block.Transactions() {
+			txns[*tx.Hash()] = tx.MsgTx()
+		}
+		for _, tx := range block.STransactions() {
+			txns[*tx.Hash()] = tx.MsgTx()
+		}
+	}
+	addBlockTxns(block)
+
+	// searchSideChain attempts to find the provided transaction in the side
+	// chain ancestors of the block and adds the transactions of every block
+	// it loads along the way to the known transactions.
+	searched := false
+	searchSideChain := func(txHash *chainhash.Hash) (*wire.MsgTx, error) {
+		if queryer == nil || searched {
+			return nil, nil
+		}
+		searched = true
+
+		hash := &block.MsgBlock().Header.PrevBlock
+		for !queryer.MainChainHasBlock(hash) {
+			ancestor, err := queryer.BlockByHash(hash)
+			if err != nil {
+				return nil, err
+			}
+			addBlockTxns(ancestor)
+			hash = &ancestor.MsgBlock().Header.PrevBlock
+		}
+		return txns[*txHash], nil
+	}
+
+	return func(prevOut *wire.OutPoint) (uint16, []byte, error) {
+		tx, ok := txns[prevOut.Hash]
+		if !ok {
+			entry, err := dbFetchTxIndexEntry(dbTx, &prevOut.Hash)
+			if err != nil {
+				return 0, nil, err
+			}
+			if entry != nil {
+				serializedTx, err := dbTx.FetchBlockRegion(&entry.BlockRegion)
+				if err != nil {
+					return 0, nil, err
+				}
+				tx = new(wire.MsgTx)
+				if err := tx.FromBytes(serializedTx); err != nil {
+					return 0, nil, err
+				}
+			} else {
+				tx, err = searchSideChain(&prevOut.Hash)
+				if err != nil {
+					return 0, nil, err
+				}
+			}
+			if tx == nil {
+				str := fmt.Sprintf("transaction %v referenced by input "+
+					"does not exist in the %s", prevOut.Hash, txIndexName)
+				return 0, nil, AssertError(str)
+			}
+
+			// Cache the transaction since it is common for multiple
+			// outputs of the same transaction to be spent in a block.
+			txns[prevOut.Hash] = tx
+		}
+
+		if prevOut.Index >= uint32(len(tx.TxOut)) {
+			str := fmt.Sprintf("output %v referenced by input does not "+
+				"exist", prevOut)
+			return 0, nil, AssertError(str)
+		}
+		txOut := tx.TxOut[prevOut.Index]
+		return txOut.Version, txOut.PkScript, nil
+	}
+}
+
+// blockAddrEntries returns a mapping of each address key involved in the
+// provided block to the positions of the transactions involving it, where the
+// positions index the regular transactions followed by the stake transactions.
+// The positions for each address key are in the order the transactions appear
+// in the block.
+//
+// See blockPrevScriptFetcher for details regarding the optional chain queryer.
+func (idx *AddrIndex) blockAddrEntries(dbTx database.Tx, block *dcrutil.Block, queryer ChainQueryer) (map[[addrKeySize]byte][]int, error) {
+	fetchPrevScript := blockPrevScriptFetcher(dbTx, block, queryer)
+	entries := make(map[[addrKeySize]byte][]int)
+	blockTxns := make([]*dcrutil.Tx, 0, len(block.Transactions())+
+		len(block.STransactions()))
+	blockTxns = append(blockTxns, block.Transactions()...)
+	blockTxns = append(blockTxns, block.STransactions()...)
+	for txIdx, tx := range blockTxns {
+		addrKeys, err := idx.txAddrKeys(tx.MsgTx(), fetchPrevScript)
+		if err != nil {
+			return nil, err
+		}
+		for _, addrKey := range addrKeys {
+			entries[addrKey] = append(entries[addrKey], txIdx)
+		}
+	}
+
+	return entries, nil
+}
+
+// connectBlock adds a mapping for each address involved in the transactions of
+// the passed block to the respective transactions.
+func (idx *AddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored for this index because even though the
+	// disapproved transactions no longer apply spend semantics, they still
+	// exist within the block and thus have to be processed before the next
+	// block disapproves them.  This mirrors the behavior of the transaction
+	// index which this index relies on.
+
+	// Look up the internal block ID the transaction index assigned to the
+	// block.
+	blockID, err := dbFetchBlockIDByHash(dbTx, block.Hash())
+	if err != nil {
+		return err
+	}
+
+	// The offset and length of the transactions within the serialized block.
+	txLocs, stakeTxLocs, err := block.TxLoc()
+	if err != nil {
+		return err
+	}
+
+	entries, err := idx.blockAddrEntries(dbTx, block, nil)
+	if err != nil {
+		return err
+	}
+
+	// Add all of the index entries for each address.
+	numRegularTxns := len(txLocs)
+	addrIdxBucket := dbTx.Metadata().Bucket(addrIndexKey)
+	for addrKey, txIdxs := range entries {
+		for _, txIdx := range txIdxs {
+			txLoc, blockIndex := txLocs, txIdx
+			if txIdx >= numRegularTxns {
+				txLoc, blockIndex = stakeTxLocs, txIdx-numRegularTxns
+			}
+			err := dbPutAddrIndexEntry(addrIdxBucket, addrKey, blockID,
+				txLoc[blockIndex], uint32(blockIndex))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), block.Hash(), int32(block.Height()))
+}
+
+// disconnectBlock removes the mappings for each address involved in the
+// transactions of the passed block.
+func (idx *AddrIndex) disconnectBlock(dbTx database.Tx, block *dcrutil.Block) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored when disconnecting blocks because it is also
+	// ignored when connecting the block.  See the comments in connectBlock for
+	// the specifics.
+
+	entries, err := idx.blockAddrEntries(dbTx, block, idx.chain)
+	if err != nil {
+		return err
+	}
+
+	// Remove all of the index entries for each address.  Since the entries
+	// for the block being disconnected are always the most recent ones, this
+	// only requires removing the same number that were added.
+	addrIdxBucket := dbTx.Metadata().Bucket(addrIndexKey)
+	for addrKey, txIdxs := range entries {
+		err := dbRemoveAddrIndexEntries(addrIdxBucket, addrKey, len(txIdxs))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), &block.MsgBlock().Header.PrevBlock,
+		int32(block.Height()-1))
+}
+
+// EntriesForAddress returns the transaction index entries for transactions
+// that involve the provided address along with the number of entries skipped.
+// The number skipped could be less than the requested number when there are
+// fewer total entries than the requested number of entries to skip.
+//
+// The entries are ordered from oldest to newest unless the reverse flag is
+// set, in which case they are ordered from newest to oldest and the skip
+// applies to the newest entries.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) EntriesForAddress(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]TxIndexEntry, uint32, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []TxIndexEntry
+	var skipped uint32
+	err = idx.db.View(func(dbTx database.Tx) error {
+		// Create closure to lookup the block hash given the ID using the
+		// database transaction.
+		fetchBlockHash := func(id []byte) (*chainhash.Hash, error) {
+			// Deserialize and populate the result.
+			return dbFetchBlockHashBySerializedID(dbTx, id)
+		}
+
+		var err error
+		addrIdxBucket := dbTx.Metadata().Bucket(addrIndexKey)
+		entries, skipped, err = dbFetchAddrIndexEntries(addrIdxBucket,
+			addrKey, numToSkip, numRequested, reverse, fetchBlockHash)
+		return err
+	})
+
+	return entries, skipped, err
+}
+
+// indexUnconfirmedAddress adds the transaction to the unconfirmed (memory-only)
+// address index for the provided address key.
+//
+// This function MUST be called with the unconfirmed lock held (for writes).
+func (idx *AddrIndex) indexUnconfirmedAddress(addrKey [addrKeySize]byte, tx *dcrutil.Tx) {
+	// Add a mapping from the address to the transaction.
+	addrIndexEntry := idx.txnsByAddr[addrKey]
+	if addrIndexEntry == nil {
+		addrIndexEntry = make(map[chainhash.Hash]*dcrutil.Tx)
+		idx.txnsByAddr[addrKey] = addrIndexEntry
+	}
+	addrIndexEntry[*tx.Hash()] = tx
+
+	// Add a mapping from the transaction to the address.
+	addrsByTxEntry := idx.addrsByTx[*tx.Hash()]
+	if addrsByTxEntry == nil {
+		addrsByTxEntry = make(map[[addrKeySize]byte]struct{})
+		idx.addrsByTx[*tx.Hash()] = addrsByTxEntry
+	}
+	addrsByTxEntry[addrKey] = struct{}{}
+}
+
+// AddUnconfirmedTx adds all addresses related to the transaction to the
+// unconfirmed (memory-only) address index.
+//
+// NOTE: This transaction MUST have already been validated by the memory pool
+// before calling this function with it and have all of the inputs available
+// via the provided previous scripter interface.  Inputs for which the previous
+// script is not available are ignored.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) AddUnconfirmedTx(tx *dcrutil.Tx, prevScripts PrevScripter) {
+	// Index addresses of all referenced previous output tx outputs.  The
+	// previous outputs for unconfirmed transactions are either in the utxo
+	// set or in the memory pool, both of which the provided view covers, so
+	// any that are not available are skipped rather than treated as an error.
+	fetchPrevScript := func(prevOut *wire.OutPoint) (uint16, []byte, error) {
+		version, pkScript, _ := prevScripts.PrevScript(prevOut)
+		return version, pkScript, nil
+	}
+	addrKeys, err := idx.txAddrKeys(tx.MsgTx(), fetchPrevScript)
+	if err != nil {
+		// This should never happen since the fetcher above never returns
+		// an error, but be paranoid.
+		log.Errorf("Unable to index unconfirmed transaction %v: %v",
+			tx.Hash(), err)
+		return
+	}
+
+	idx.unconfirmedLock.Lock()
+	for _, addrKey := range addrKeys {
+		idx.indexUnconfirmedAddress(addrKey, tx)
+	}
+	idx.unconfirmedLock.Unlock()
+}
+
+// RemoveUnconfirmedTx removes the passed transaction from the unconfirmed
+// (memory-only) address index.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) RemoveUnconfirmedTx(hash *chainhash.Hash) {
+	idx.unconfirmedLock.Lock()
+	defer idx.unconfirmedLock.Unlock()
+
+	// Remove all address references to the transaction from the address
+	// index and remove the entry for the address altogether if it no longer
+	// references any transactions.
+	for addrKey := range idx.addrsByTx[*hash] {
+		delete(idx.txnsByAddr[addrKey], *hash)
+		if len(idx.txnsByAddr[addrKey]) == 0 {
+			delete(idx.txnsByAddr, addrKey)
+		}
+	}
+
+	// Remove the entry from the transaction to address lookup map as well.
+	delete(idx.addrsByTx, *hash)
+}
+
+// UnconfirmedTxnsForAddress returns all transactions currently in the
+// unconfirmed (memory-only) address index that involve the passed address.
+// Unsupported address types are ignored and will result in no results.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) UnconfirmedTxnsForAddress(addr stdaddr.Address) []*dcrutil.Tx {
+	// Ignore unsupported address types.
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil
+	}
+
+	// Protect concurrent access.
+	idx.unconfirmedLock.RLock()
+	defer idx.unconfirmedLock.RUnlock()
+
+	// Return a new slice with the results if there are any.  This ensures
+	// safe concurrency.
+	if txns, exists := idx.txnsByAddr[addrKey]; exists {
+		addressTxns := make([]*dcrutil.Tx, 0, len(txns))
+		for _, tx := range txns {
+			addressTxns = append(addressTxns, tx)
+		}
+		return addressTxns
+	}
+
+	return nil
+}
+
+// DropAddrIndex drops the address index from the provided database if it
+// exists.
+func DropAddrIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, addrIndexKey, addrIndexName)
+}
+
+// DropIndex drops the address index from the provided database if it exists.
+func (*AddrIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropAddrIndex(ctx, db)
+}
+
+// ProcessNotification indexes the provided notification based on its
+// notification type.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) ProcessNotification(dbTx database.Tx, ntfn *IndexNtfn) error {
+	switch ntfn.NtfnType {
+	case ConnectNtfn:
+		err := idx.connectBlock(dbTx, ntfn.Block)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to connect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrConnectBlock, msg)
+		}
+
+	case DisconnectNtfn:
+		err := idx.disconnectBlock(dbTx, ntfn.Block)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to disconnect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrDisconnectBlock, msg)
+		}
+
+	default:
+		msg := fmt.Sprintf("%s: unknown notification type received: %d",
+			idx.Name(), ntfn.NtfnType)
+		return indexerError(ErrInvalidNotificationType, msg)
+	}
+
+	return nil
+}
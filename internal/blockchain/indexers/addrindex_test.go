@@ -0,0 +1,614 @@
+// Copyright (c) 2016 The btcsuite developers
+// Copyright (c) 2016-2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/v5/chaingen"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/txscript/v4/stdscript"
+	"github.com/decred/dcrd/wire"
+)
+
+// addrIndexBucket provides a mock address index database bucket by
+// implementing the internalBucket interface.
+type addrIndexBucket struct {
+	levels map[[levelKeySize]byte][]byte
+}
+
+// Clone returns a deep copy of the mock address index bucket.
+func (b *addrIndexBucket) Clone() *addrIndexBucket {
+	levels := make(map[[levelKeySize]byte][]byte)
+	for k, v := range b.levels {
+		vCopy := make([]byte, len(v))
+		copy(vCopy, v)
+		levels[k] = vCopy
+	}
+	return &addrIndexBucket{levels: levels}
+}
+
+// Get returns the value associated with the key from the mock address index
+// bucket.
+//
+// This is part of the internalBucket interface.
+func (b *addrIndexBucket) Get(key []byte) []byte {
+	var levelKey [levelKeySize]byte
+	copy(levelKey[:], key)
+	return b.levels[levelKey]
+}
+
+// Put stores the provided key/value pair to the mock address index bucket.
+//
+// This is part of the internalBucket interface.
+func (b *addrIndexBucket) Put(key []byte, value []byte) error {
+	var levelKey [levelKeySize]byte
+	copy(levelKey[:], key)
+	valueCopy := make([]byte, len(value))
+	copy(valueCopy, value)
+	b.levels[levelKey] = valueCopy
+	return nil
+}
+
+// Delete removes the provided key from the mock address index bucket.
+//
+// This is part of the internalBucket interface.
+func (b *addrIndexBucket) Delete(key []byte) error {
+	var levelKey [levelKeySize]byte
+	copy(levelKey[:], key)
+	delete(b.levels, levelKey)
+	return nil
+}
+
+// serializedData returns the serialized data for all of the levels of the
+// provided address key ordered from the highest level to level 0.  This is the
+// same order entries are fetched and thus from oldest to newest.
+func (b *addrIndexBucket) serializedData(addrKey [addrKeySize]byte) []byte {
+	var data []byte
+	var levels [][]byte
+	for level := uint8(0); ; level++ {
+		levelKey := keyForLevel(addrKey, level)
+		levelData, ok := b.levels[levelKey]
+		if !ok {
+			break
+		}
+		levels = append(levels, levelData)
+	}
+	for i := len(levels) - 1; i >= 0; i-- {
+		data = append(data, levels[i]...)
+	}
+	return data
+}
+
+// sanityCheck ensures that all data stored in the bucket for the given address
+// adheres to the level-based rules described by the address index
+// documentation.
+func (b *addrIndexBucket) sanityCheck(addrKey [addrKeySize]byte, expectedTotal int) error {
+	// Find the highest level for the key and ensure there are no gaps.
+	highestLevel := uint8(0)
+	for k := range b.levels {
+		if !bytes.Equal(k[:levelOffset], addrKey[:]) {
+			return fmt.Errorf("unexpected key %x", k)
+		}
+		if k[levelOffset] > highestLevel {
+			highestLevel = k[levelOffset]
+		}
+	}
+	if expectedTotal == 0 {
+		if len(b.levels) != 0 {
+			return fmt.Errorf("expected no levels, got %d", len(b.levels))
+		}
+		return nil
+	}
+	if len(b.levels) != int(highestLevel)+1 {
+		return fmt.Errorf("found gap in levels: highest level %d, number "+
+			"of levels %d", highestLevel, len(b.levels))
+	}
+
+	// Ensure level 0 is not empty and does not exceed the max number of
+	// entries and that all other levels are either half full or full.
+	var totalEntries int
+	for level := uint8(0); level <= highestLevel; level++ {
+		levelKey := keyForLevel(addrKey, level)
+		levelData := b.levels[levelKey]
+		if len(levelData)%txEntrySize != 0 {
+			return fmt.Errorf("level %d has a partial entry", level)
+		}
+		numEntries := len(levelData) / txEntrySize
+		totalEntries += numEntries
+
+		maxEntries := maxEntriesForLevel(level)
+		if level == 0 {
+			if numEntries == 0 || numEntries > maxEntries {
+				return fmt.Errorf("level 0 has %d entries (max %d)",
+					numEntries, maxEntries)
+			}
+			continue
+		}
+		if numEntries != maxEntries && numEntries != maxEntries/2 {
+			return fmt.Errorf("level %d has %d entries (max %d)", level,
+				numEntries, maxEntries)
+		}
+	}
+	if totalEntries != expectedTotal {
+		return fmt.Errorf("expected %d total entries, got %d", expectedTotal,
+			totalEntries)
+	}
+
+	return nil
+}
+
+// TestAddrIndexLevels ensures that adding and removing entries to the address
+// index creates multiple levels as described by the address index
+// documentation.
+func TestAddrIndexLevels(t *testing.T) {
+	const maxEntries = 130
+
+	// expectedData returns the expected serialized data for the first n
+	// entries added by the test.
+	expectedData := func(n int) []byte {
+		data := make([]byte, n*txEntrySize)
+		for i := 0; i < n; i++ {
+			txLoc := wire.TxLoc{TxStart: i * 2, TxLen: i*2 + 1}
+			putTxIndexEntry(data[i*txEntrySize:], uint32(i), txLoc,
+				uint32(i%3))
+		}
+		return data
+	}
+
+	var addrKey [addrKeySize]byte
+	addrKey[0] = addrKeyTypePubKeyHash
+	copy(addrKey[1:], bytes.Repeat([]byte{0x01}, 20))
+
+	for numEntries := 1; numEntries <= maxEntries; numEntries++ {
+		// Add the entries one at a time while ensuring the levels adhere
+		// to the rules and all entries remain in the expected order after
+		// each addition.
+		bucket := &addrIndexBucket{
+			levels: make(map[[levelKeySize]byte][]byte),
+		}
+		for i := 0; i < numEntries; i++ {
+			txLoc := wire.TxLoc{TxStart: i * 2, TxLen: i*2 + 1}
+			err := dbPutAddrIndexEntry(bucket, addrKey, uint32(i), txLoc,
+				uint32(i%3))
+			if err != nil {
+				t.Fatalf("%d: unexpected error adding entry %d: %v",
+					numEntries, i, err)
+			}
+			if err := bucket.sanityCheck(addrKey, i+1); err != nil {
+				t.Fatalf("%d: sanity check failed after adding entry "+
+					"%d: %v", numEntries, i, err)
+			}
+			if !bytes.Equal(bucket.serializedData(addrKey),
+				expectedData(i+1)) {
+
+				t.Fatalf("%d: unexpected serialized data after adding "+
+					"entry %d", numEntries, i)
+			}
+		}
+
+		// Ensure removing any number of the entries from the populated
+		// bucket results in levels that adhere to the rules and the
+		// expected remaining entries.
+		for numToRemove := 1; numToRemove <= numEntries; numToRemove++ {
+			clone := bucket.Clone()
+			err := dbRemoveAddrIndexEntries(clone, addrKey, numToRemove)
+			if err != nil {
+				t.Fatalf("%d: unexpected error removing %d entries: %v",
+					numEntries, numToRemove, err)
+			}
+			remaining := numEntries - numToRemove
+			if err := clone.sanityCheck(addrKey, remaining); err != nil {
+				t.Fatalf("%d: sanity check failed after removing %d "+
+					"entries: %v", numEntries, numToRemove, err)
+			}
+			if !bytes.Equal(clone.serializedData(addrKey),
+				expectedData(remaining)) {
+
+				t.Fatalf("%d: unexpected serialized data after removing "+
+					"%d entries", numEntries, numToRemove)
+			}
+		}
+
+		// Ensure attempting to remove more entries than exist returns an
+		// assertion error.
+		err := dbRemoveAddrIndexEntries(bucket.Clone(), addrKey, numEntries+1)
+		if _, ok := err.(AssertError); !ok {
+			t.Fatalf("%d: did not receive expected assertion error when "+
+				"removing too many entries -- got %v", numEntries, err)
+		}
+	}
+}
+
+// TestAddrIndexFetchEntries ensures fetching entries from the address index
+// with various combinations of skip, count, and reverse returns the expected
+// results.
+func TestAddrIndexFetchEntries(t *testing.T) {
+	const numEntries = 50
+
+	var addrKey [addrKeySize]byte
+	addrKey[0] = addrKeyTypeScriptHash
+	bucket := &addrIndexBucket{levels: make(map[[levelKeySize]byte][]byte)}
+	for i := 0; i < numEntries; i++ {
+		txLoc := wire.TxLoc{TxStart: i, TxLen: 1}
+		err := dbPutAddrIndexEntry(bucket, addrKey, uint32(i), txLoc, 0)
+		if err != nil {
+			t.Fatalf("unexpected error adding entry %d: %v", i, err)
+		}
+	}
+
+	// fetchBlockHash mocks the block ID lookup by encoding the ID in the
+	// first bytes of the returned hash.
+	fetchBlockHash := func(serializedID []byte) (*chainhash.Hash, error) {
+		var hash chainhash.Hash
+		copy(hash[:], serializedID)
+		return &hash, nil
+	}
+
+	tests := []struct {
+		name        string
+		skip        uint32
+		count       uint32
+		reverse     bool
+		wantSkipped uint32
+		wantIDs     []uint32
+	}{{
+		name:        "first three",
+		skip:        0,
+		count:       3,
+		wantSkipped: 0,
+		wantIDs:     []uint32{0, 1, 2},
+	}, {
+		name:        "skip ten take two",
+		skip:        10,
+		count:       2,
+		wantSkipped: 10,
+		wantIDs:     []uint32{10, 11},
+	}, {
+		name:        "reverse first three",
+		skip:        0,
+		count:       3,
+		reverse:     true,
+		wantSkipped: 0,
+		wantIDs:     []uint32{49, 48, 47},
+	}, {
+		name:        "reverse skip ten take two",
+		skip:        10,
+		count:       2,
+		reverse:     true,
+		wantSkipped: 10,
+		wantIDs:     []uint32{39, 38},
+	}, {
+		name:        "count past end",
+		skip:        48,
+		count:       10,
+		wantSkipped: 48,
+		wantIDs:     []uint32{48, 49},
+	}, {
+		name:        "reverse max count",
+		skip:        48,
+		count:       math.MaxUint32,
+		reverse:     true,
+		wantSkipped: 48,
+		wantIDs:     []uint32{1, 0},
+	}, {
+		name:        "skip past end",
+		skip:        60,
+		count:       10,
+		wantSkipped: numEntries,
+		wantIDs:     nil,
+	}, {
+		name:        "reverse skip past end",
+		skip:        60,
+		count:       10,
+		reverse:     true,
+		wantSkipped: numEntries,
+		wantIDs:     nil,
+	}, {
+		name:        "zero requested",
+		skip:        5,
+		count:       0,
+		wantSkipped: 5,
+		wantIDs:     nil,
+	}}
+
+	for _, test := range tests {
+		entries, skipped, err := dbFetchAddrIndexEntries(bucket, addrKey,
+			test.skip, test.count, test.reverse, fetchBlockHash)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if skipped != test.wantSkipped {
+			t.Errorf("%q: unexpected skipped count -- got %d, want %d",
+				test.name, skipped, test.wantSkipped)
+			continue
+		}
+		if len(entries) != len(test.wantIDs) {
+			t.Errorf("%q: unexpected number of entries -- got %d, want %d",
+				test.name, len(entries), len(test.wantIDs))
+			continue
+		}
+		for i, entry := range entries {
+			gotID := binary.LittleEndian.Uint32(entry.BlockRegion.Hash[:])
+			if gotID != test.wantIDs[i] {
+				t.Errorf("%q: unexpected id for entry %d -- got %d, "+
+					"want %d", test.name, i, gotID, test.wantIDs[i])
+			}
+			if entry.BlockRegion.Offset != test.wantIDs[i] {
+				t.Errorf("%q: unexpected offset for entry %d -- got %d, "+
+					"want %d", test.name, i, entry.BlockRegion.Offset,
+					test.wantIDs[i])
+			}
+		}
+	}
+}
+
+// coinbasePayAddr returns the first address paid by the coinbase of the
+// provided block.
+func coinbasePayAddr(t *testing.T, blk *dcrutil.Block) stdaddr.Address {
+	t.Helper()
+
+	params := chaincfg.SimNetParams()
+	for _, txOut := range blk.Transactions()[0].MsgTx().TxOut {
+		_, addrs := stdscript.ExtractAddrs(txOut.Version, txOut.PkScript,
+			params)
+		if len(addrs) > 0 {
+			return addrs[0]
+		}
+	}
+	t.Fatalf("no coinbase payment address found in block %s", blk.Hash())
+	return nil
+}
+
+// assertAddrIndexEntries ensures the address index entries for the provided
+// address are in the provided blocks in order.
+func assertAddrIndexEntries(t *testing.T, idx *AddrIndex, addr stdaddr.Address, blocks []*dcrutil.Block) {
+	t.Helper()
+
+	entries, _, err := idx.EntriesForAddress(addr, 0, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(blocks) {
+		t.Fatalf("expected %d entries, got %d", len(blocks), len(entries))
+	}
+	for i, entry := range entries {
+		if *entry.BlockRegion.Hash != *blocks[i].Hash() {
+			t.Fatalf("expected entry %d to be in block %s, got %s", i,
+				blocks[i].Hash(), entry.BlockRegion.Hash)
+		}
+	}
+
+	// Ensure the reverse order is also correct.
+	entries, _, err = idx.EntriesForAddress(addr, 0, 100, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, entry := range entries {
+		want := blocks[len(blocks)-1-i]
+		if *entry.BlockRegion.Hash != *want.Hash() {
+			t.Fatalf("expected reversed entry %d to be in block %s, got %s",
+				i, want.Hash(), entry.BlockRegion.Hash)
+		}
+	}
+}
+
+// TestAddrIndexAsync ensures the address index behaves as expected when
+// receiving updates asynchronously.
+func TestAddrIndexAsync(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain.
+	addBlock(t, chain, &g, "bk1")
+	bk2 := addBlock(t, chain, &g, "bk2")
+	bk3 := addBlock(t, chain, &g, "bk3")
+
+	// Initialize the address index along with the transaction index it
+	// depends on.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	// Ensure the address index can't be created without the transaction
+	// index.
+	_, err = NewAddrIndex(subber, db, chain)
+	if err == nil {
+		t.Fatal("expected error creating address index without the " +
+			"transaction index")
+	}
+
+	txIdx, err := NewTxIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := NewAddrIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the index got synced to bk3 on initialization.
+	tipHeight, tipHash, err := idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk3.Height() {
+		t.Fatalf("expected tip height to be %d, got %d",
+			bk3.Height(), tipHeight)
+	}
+	if *tipHash != *bk3.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk3.Hash(), tipHash)
+	}
+
+	// The generator pays every coinbase after block one to the same address,
+	// so ensure the coinbases of bk2 and bk3 are indexed for it.
+	payAddr := coinbasePayAddr(t, bk2)
+	assertAddrIndexEntries(t, idx, payAddr, []*dcrutil.Block{bk2, bk3})
+
+	// Ensure the index remains in sync with the main chain when new
+	// blocks are connected.
+	bk4 := addBlock(t, chain, &g, "bk4")
+	ntfn := &IndexNtfn{
+		NtfnType: ConnectNtfn,
+		Block:    bk4,
+		Parent:   bk3,
+	}
+	notifyAndWait(t, subber, ntfn)
+
+	bk5 := addBlock(t, chain, &g, "bk5")
+	ntfn = &IndexNtfn{
+		NtfnType: ConnectNtfn,
+		Block:    bk5,
+		Parent:   bk4,
+	}
+	notifyAndWait(t, subber, ntfn)
+
+	tipHeight, tipHash, err = idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk5.Height() {
+		t.Fatalf("expected tip height to be %d, got %d",
+			bk5.Height(), tipHeight)
+	}
+	if *tipHash != *bk5.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk5.Hash(), tipHash)
+	}
+	assertAddrIndexEntries(t, idx, payAddr,
+		[]*dcrutil.Block{bk2, bk3, bk4, bk5})
+
+	// Simulate a reorg by setting bk4 as the main chain tip.  bk5 is now
+	// an orphan block.
+	g.SetTip("bk4")
+	err = chain.RemoveBlock(bk5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add bk5a to the main chain.
+	bk5a := addBlock(t, chain, &g, "bk5a")
+
+	// Resubscribe the indexes.
+	subber.mtx.Lock()
+	err = idx.sub.stop()
+	if err == nil {
+		err = txIdx.sub.stop()
+	}
+	subber.mtx.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txIdx, err = NewTxIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err = NewAddrIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the index recovered to bk4 and synced back to the main chain tip
+	// bk5a.
+	tipHeight, tipHash, err = idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk5a.Height() {
+		t.Fatalf("expected tip height to be %d, got %d",
+			bk5a.Height(), tipHeight)
+	}
+	if *tipHash != *bk5a.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk5a.Hash(), tipHash)
+	}
+	assertAddrIndexEntries(t, idx, payAddr,
+		[]*dcrutil.Block{bk2, bk3, bk4, bk5a})
+
+	// Ensure the index remains in sync when blocks are disconnected.
+	err = chain.RemoveBlock(bk5a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetTip("bk4")
+	ntfn = &IndexNtfn{
+		NtfnType: DisconnectNtfn,
+		Block:    bk5a,
+		Parent:   bk4,
+	}
+	notifyAndWait(t, subber, ntfn)
+
+	tipHeight, tipHash, err = idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk4.Height() {
+		t.Fatalf("expected tip height to be %d, got %d",
+			bk4.Height(), tipHeight)
+	}
+	if *tipHash != *bk4.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk4.Hash(), tipHash)
+	}
+	assertAddrIndexEntries(t, idx, payAddr,
+		[]*dcrutil.Block{bk2, bk3, bk4})
+
+	// Ensure unconfirmed transactions are tracked for the addresses they
+	// involve and removed as expected.
+	unconfirmedTx := dcrutil.NewTx(bk5a.Transactions()[0].MsgTx())
+	idx.AddUnconfirmedTx(unconfirmedTx, nil)
+	txns := idx.UnconfirmedTxnsForAddress(payAddr)
+	if len(txns) != 1 || *txns[0].Hash() != *unconfirmedTx.Hash() {
+		t.Fatalf("expected unconfirmed transaction %s for address",
+			unconfirmedTx.Hash())
+	}
+	idx.RemoveUnconfirmedTx(unconfirmedTx.Hash())
+	if txns := idx.UnconfirmedTxnsForAddress(payAddr); len(txns) != 0 {
+		t.Fatalf("expected no unconfirmed transactions for address, got %d",
+			len(txns))
+	}
+
+	// Ensure dropping the transaction index also drops the address index.
+	err = txIdx.DropIndex(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exists, err := existsIndex(db, addrIndexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatal("expected address index to be dropped along with the " +
+			"transaction index")
+	}
+}
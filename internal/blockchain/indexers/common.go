@@ -69,6 +69,37 @@ type ChainQueryer interface {
 	IsTreasuryAgendaActive(*chainhash.Hash) (bool, error)
 }
 
+// prunedChainQueryer is implemented by chain queryers for chains that might
+// not have the block data prior to a given block, such as when it has been
+// pruned or the chain was created from a chain state snapshot.
+type prunedChainQueryer interface {
+	// PruneHeight returns the height of the oldest block in the main chain
+	// for which block data is available.  It is zero when no block data has
+	// been pruned.
+	PruneHeight() int64
+}
+
+// indexStartBlock returns the height and hash of the block that a newly
+// created index which only requires the blocks after its tip starts from.  It
+// is the genesis block unless the block data prior to the oldest block with
+// available block data is not available, in which case it is that block since
+// it is the parent of the first block that can be indexed.
+func indexStartBlock(queryer ChainQueryer, genesisHash *chainhash.Hash) (int64, *chainhash.Hash, error) {
+	pq, ok := queryer.(prunedChainQueryer)
+	if !ok {
+		return 0, genesisHash, nil
+	}
+	pruneHeight := pq.PruneHeight()
+	if pruneHeight == 0 {
+		return 0, genesisHash, nil
+	}
+	hash, err := queryer.BlockHashByHeight(pruneHeight)
+	if err != nil {
+		return 0, nil, err
+	}
+	return pruneHeight, hash, nil
+}
+
 // Indexer defines a generic interface for an indexer.
 type Indexer interface {
 	// Key returns the key of the index as a byte slice.
@@ -546,6 +577,12 @@ func finishDrop(ctx context.Context, indexer Indexer) error {
 // createIndex determines if each of the provided index has already
 // been created and creates it if not.
 func createIndex(indexer Indexer, genesisHash *chainhash.Hash) error {
+	return createIndexAt(indexer, genesisHash, 0)
+}
+
+// createIndexAt is identical to createIndex except the tip of a newly created
+// index is set to the provided block instead of the genesis block.
+func createIndexAt(indexer Indexer, hash *chainhash.Hash, height int64) error {
 	return indexer.DB().Update(func(dbTx database.Tx) error {
 		// Create the bucket for the current tips as needed.
 		meta := dbTx.Metadata()
@@ -573,9 +610,9 @@ func createIndex(indexer Indexer, genesisHash *chainhash.Hash) error {
 			return err
 		}
 
-		// Set the tip for the index to values which represent an
-		// uninitialized index (the genesis block hash and height).
-		err = dbPutIndexerTip(dbTx, idxKey, genesisHash, 0)
+		// Set the tip for the index to the provided block.  The genesis
+		// block hash and height represent an uninitialized index.
+		err = dbPutIndexerTip(dbTx, idxKey, hash, int32(height))
 		if err != nil {
 			return err
 		}
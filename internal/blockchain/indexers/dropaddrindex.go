@@ -1,5 +1,5 @@
 // Copyright (c) 2016 The btcsuite developers
-// Copyright (c) 2016-2022 The Decred developers
+// Copyright (c) 2016-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -11,22 +11,17 @@ import (
 	"github.com/decred/dcrd/database/v3"
 )
 
-const (
-	// addrIndexName is the human-readable name for the index.
-	addrIndexName = "address index"
-)
-
 var (
-	// addrIndexKey is the key of the address index and the db bucket used
-	// to house it.
-	addrIndexKey = []byte("txbyaddridx")
+	// legacyAddrIndexKey is the key of the legacy address index and the db
+	// bucket used to house it.
+	legacyAddrIndexKey = []byte("txbyaddridx")
 )
 
-// DropAddrIndex drops the address index from the provided database if it
-// exists.
-func DropAddrIndex(ctx context.Context, db database.DB) error {
+// DropLegacyAddrIndex drops the legacy address index from the provided
+// database if it exists.
+func DropLegacyAddrIndex(ctx context.Context, db database.DB) error {
 	// Nothing to do if the index doesn't already exist.
-	exists, err := existsIndex(db, addrIndexKey)
+	exists, err := existsIndex(db, legacyAddrIndexKey)
 	if err != nil {
 		return err
 	}
@@ -41,18 +36,18 @@ func DropAddrIndex(ctx context.Context, db database.DB) error {
 	// in a single database transaction would result in massive memory usage and
 	// likely crash many systems due to ulimits.  In order to avoid this, use a
 	// cursor to delete a maximum number of entries out of the bucket at a time.
-	err = incrementalFlatDrop(ctx, db, addrIndexKey, addrIndexName)
+	err = incrementalFlatDrop(ctx, db, legacyAddrIndexKey, addrIndexName)
 	if err != nil {
 		return err
 	}
 
 	// Remove the index tip, version, bucket, and in-progress drop flag now that
 	// all index entries have been removed.
-	err = dropIndexMetadata(db, addrIndexKey)
+	err = dropIndexMetadata(db, legacyAddrIndexKey)
 	if err != nil {
 		return err
 	}
 
-	log.Infof("Dropped %s", addrIndexName)
+	log.Infof("Dropped legacy %s", addrIndexName)
 	return nil
 }
@@ -42,6 +42,11 @@ const (
 	// ErrBlockNotOnMainChain indicates the provided block is not on the
 	// main chain.
 	ErrBlockNotOnMainChain = ErrorKind("ErrBlockNotOnMainChain")
+
+	// ErrMissingBlockData indicates an index tip is prior to the oldest
+	// block with available block data, such as when it has been pruned, so
+	// the index can not be caught up.
+	ErrMissingBlockData = ErrorKind("ErrMissingBlockData")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
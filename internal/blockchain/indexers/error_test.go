@@ -26,6 +26,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrFetchTip, "ErrFetchTip"},
 		{ErrMissingNotification, "ErrMissingNotification"},
 		{ErrBlockNotOnMainChain, "ErrBlockNotOnMainChain"},
+		{ErrMissingBlockData, "ErrMissingBlockData"},
 	}
 
 	for i, test := range tests {
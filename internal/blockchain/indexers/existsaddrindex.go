@@ -181,8 +181,17 @@ func (idx *ExistsAddrIndex) Init(ctx context.Context, chainParams *chaincfg.Para
 		return err
 	}
 
-	// Create the initial state for the index as needed.
-	if err := createIndex(idx, &chainParams.GenesisHash); err != nil {
+	// Create the initial state for the index as needed.  It starts from the
+	// oldest block with available block data instead of the genesis block
+	// when the prior block data has been pruned or the chain was created from
+	// a chain state snapshot, so the addresses only used in the blocks up to
+	// and including that block are not indexed in that case.
+	startHeight, startHash, err := indexStartBlock(idx.chain,
+		&chainParams.GenesisHash)
+	if err != nil {
+		return err
+	}
+	if err := createIndexAt(idx, startHash, startHeight); err != nil {
 		return err
 	}
 
@@ -193,7 +202,23 @@ func (idx *ExistsAddrIndex) Init(ctx context.Context, chainParams *chaincfg.Para
 
 	// Recover the exists address index and its dependents to the main
 	// chain if needed.
-	return recoverIndex(ctx, idx)
+	if err := recoverIndex(ctx, idx); err != nil {
+		return err
+	}
+
+	// The index can't be caught up when its tip is prior to the oldest block
+	// with available block data.
+	tipHeight, _, err := idx.Tip()
+	if err != nil {
+		return err
+	}
+	if tipHeight < startHeight {
+		msg := fmt.Sprintf("%s: index tip %d is prior to the oldest "+
+			"available block data at height %d, so it must be dropped and "+
+			"recreated", idx.Name(), tipHeight, startHeight)
+		return indexerError(ErrMissingBlockData, msg)
+	}
+	return nil
 }
 
 // Key returns the database key to use for the index as a byte slice.
@@ -0,0 +1,481 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// spendIndexName is the human-readable name for the index.
+	spendIndexName = "spend index"
+
+	// spendIndexVersion is the current version of the spend index.
+	spendIndexVersion = 1
+
+	// spendKeySize is the size of a spend index key.  It consists of the
+	// 32 byte hash of the transaction that created the output + 4 bytes
+	// output index.
+	spendKeySize = chainhash.HashSize + 4
+
+	// spendEntrySize is the size of a spend index entry.  It consists of the
+	// 32 byte hash of the spending transaction + 4 bytes input index + 32
+	// byte hash of the block that contains it + 4 bytes block height.
+	spendEntrySize = chainhash.HashSize + 4 + chainhash.HashSize + 4
+)
+
+var (
+	// spendIndexKey is the key of the spend index and the db bucket used to
+	// house it.
+	spendIndexKey = []byte("spendbyoutpointidx")
+)
+
+// -----------------------------------------------------------------------------
+// The spend index consists of an entry for every output spent by a transaction
+// in the main chain that maps the output to the transaction that spent it.
+// Transactions in the regular, stake, and treasury trees are all covered.
+//
+// Since the regular transaction tree of a block may be disapproved by the
+// stakeholders via the votes in the next block, in which case the disapproved
+// transactions no longer apply spend semantics, the entries created by a
+// disapproved regular tree are removed when the disapproving block is connected
+// and restored when it is disconnected.  This ensures the index only reflects
+// the spends that are actually in effect as of the current index tip.
+//
+// The serialized format for the keys and values in the spend index bucket is:
+//
+//   <prev tx hash><prev output index> =
+//     <spender hash><input index><block hash><height>
+//
+//   Field              Type              Size
+//   prev tx hash       chainhash.Hash    32 bytes
+//   prev output index  uint32            4 bytes
+//   spender hash       chainhash.Hash    32 bytes
+//   input index        uint32            4 bytes
+//   block hash         chainhash.Hash    32 bytes
+//   height             uint32            4 bytes
+//   -----
+//   Total: 108 bytes
+//
+// The hash of the block that contains the spending transaction is stored
+// directly rather than being looked up from the height since the main chain
+// might have changed since the index was last updated.
+// -----------------------------------------------------------------------------
+
+// SpendIndexEntry houses information about the transaction that spent an
+// output as recorded in the spend index.
+type SpendIndexEntry struct {
+	// SpenderHash is the hash of the transaction that spent the output.
+	SpenderHash chainhash.Hash
+
+	// InputIndex is the index of the input within the spending transaction
+	// that spent the output.
+	InputIndex uint32
+
+	// BlockHash is the hash of the block that contains the spending
+	// transaction.
+	BlockHash chainhash.Hash
+
+	// BlockHeight is the height of the block that contains the spending
+	// transaction.
+	BlockHeight int64
+}
+
+// spendIndexKeyFor returns the spend index key for the provided outpoint.
+func spendIndexKeyFor(outpoint *wire.OutPoint) [spendKeySize]byte {
+	var key [spendKeySize]byte
+	copy(key[:], outpoint.Hash[:])
+	byteOrder.PutUint32(key[chainhash.HashSize:], outpoint.Index)
+	return key
+}
+
+// putSpendIndexEntry serializes the provided values according to the format
+// described above for a spend index entry.  The target byte slice must be at
+// least large enough to handle the number of bytes defined by the
+// spendEntrySize constant or it will panic.
+func putSpendIndexEntry(target []byte, spenderHash *chainhash.Hash, inputIndex uint32, blockHash *chainhash.Hash, blockHeight uint32) {
+	const blockHashOffset = chainhash.HashSize + 4
+	copy(target, spenderHash[:])
+	byteOrder.PutUint32(target[chainhash.HashSize:], inputIndex)
+	copy(target[blockHashOffset:], blockHash[:])
+	byteOrder.PutUint32(target[blockHashOffset+chainhash.HashSize:],
+		blockHeight)
+}
+
+// dbFetchSpendIndexEntry uses an existing database transaction to fetch the
+// spend index entry for the provided outpoint.  When there is no entry for the
+// provided outpoint, nil will be returned for both the entry and the error.
+func dbFetchSpendIndexEntry(dbTx database.Tx, outpoint *wire.OutPoint) (*SpendIndexEntry, error) {
+	// Load the record from the database and return now if it doesn't exist.
+	key := spendIndexKeyFor(outpoint)
+	spendIndex := dbTx.Metadata().Bucket(spendIndexKey)
+	serializedData := spendIndex.Get(key[:])
+	if len(serializedData) == 0 {
+		return nil, nil
+	}
+
+	// Ensure the serialized data has enough bytes to properly deserialize.
+	if len(serializedData) < spendEntrySize {
+		str := fmt.Sprintf("corrupt spend index entry for %s", outpoint)
+		return nil, makeDbErr(database.ErrCorruption, str)
+	}
+
+	// Deserialize the final entry.
+	const blockHashOffset = chainhash.HashSize + 4
+	var entry SpendIndexEntry
+	copy(entry.SpenderHash[:], serializedData[:chainhash.HashSize])
+	entry.InputIndex = byteOrder.Uint32(serializedData[chainhash.HashSize:])
+	copy(entry.BlockHash[:], serializedData[blockHashOffset:])
+	entry.BlockHeight = int64(byteOrder.Uint32(
+		serializedData[blockHashOffset+chainhash.HashSize:]))
+	return &entry, nil
+}
+
+// dbAddSpendIndexEntries uses an existing database transaction to add a spend
+// index entry for every output spent by the provided transactions which are
+// contained in the provided block.  Inputs that do not reference a previous
+// output, such as those of coinbases, stakebases, treasurybases, and treasury
+// spends, are skipped.
+func dbAddSpendIndexEntries(dbTx database.Tx, txns []*dcrutil.Tx, block *dcrutil.Block) error {
+	blockHash := block.Hash()
+	blockHeight := uint32(block.Height())
+	spendIndex := dbTx.Metadata().Bucket(spendIndexKey)
+	for _, tx := range txns {
+		// As an optimization, allocate a single slice big enough to hold all
+		// of the serialized entries for the transaction and serialize them
+		// directly into the slice.  This approach significantly cuts down on
+		// the number of required allocations.
+		msgTx := tx.MsgTx()
+		offset := 0
+		serializedValues := make([]byte, len(msgTx.TxIn)*spendEntrySize)
+		for txInIdx, txIn := range msgTx.TxIn {
+			if isNullOutpoint(&txIn.PreviousOutPoint) {
+				continue
+			}
+
+			putSpendIndexEntry(serializedValues[offset:], tx.Hash(),
+				uint32(txInIdx), blockHash, blockHeight)
+			endOffset := offset + spendEntrySize
+			key := spendIndexKeyFor(&txIn.PreviousOutPoint)
+			err := spendIndex.Put(key[:],
+				serializedValues[offset:endOffset:endOffset])
+			if err != nil {
+				return err
+			}
+			offset += spendEntrySize
+		}
+	}
+	return nil
+}
+
+// dbRemoveSpendIndexEntries uses an existing database transaction to remove the
+// spend index entry for every output spent by the provided transactions.
+func dbRemoveSpendIndexEntries(dbTx database.Tx, txns []*dcrutil.Tx) error {
+	spendIndex := dbTx.Metadata().Bucket(spendIndexKey)
+	for _, tx := range txns {
+		for _, txIn := range tx.MsgTx().TxIn {
+			if isNullOutpoint(&txIn.PreviousOutPoint) {
+				continue
+			}
+
+			key := spendIndexKeyFor(&txIn.PreviousOutPoint)
+			if err := spendIndex.Delete(key[:]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// headerApprovesParent returns whether or not the vote bits in the passed
+// header indicate the regular transaction tree of the parent block should be
+// considered valid.
+func headerApprovesParent(header *wire.BlockHeader) bool {
+	return dcrutil.IsFlagSet16(header.VoteBits, dcrutil.BlockValid)
+}
+
+// SpendIndex implements a spent output index.  That is to say, it supports
+// querying the transaction that spent a given output in the main chain along
+// with the input index and height at which it was spent.
+type SpendIndex struct {
+	// These fields provide access to the chain queryer and the
+	// database of the index.
+	db    database.DB
+	chain ChainQueryer
+
+	// These fields track the notification subscription for the index
+	// and its subscribers.
+	sub         *IndexSubscription
+	subscribers map[chan bool]struct{}
+
+	mtx    sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Ensure the SpendIndex type implements the Indexer interface.
+var _ Indexer = (*SpendIndex)(nil)
+
+// Ensure the SpendIndex type implements the IndexDropper interface.
+var _ IndexDropper = (*SpendIndex)(nil)
+
+// NewSpendIndex returns a new instance of an indexer that is used to create a
+// mapping of all outputs spent in the main chain to the transactions that
+// spent them.
+func NewSpendIndex(subscriber *IndexSubscriber, db database.DB, chain ChainQueryer) (*SpendIndex, error) {
+	idx := &SpendIndex{
+		db:          db,
+		chain:       chain,
+		subscribers: make(map[chan bool]struct{}),
+		cancel:      subscriber.cancel,
+	}
+
+	// The spend index is an optional index.  It has no prerequisite and is
+	// updated asynchronously.
+	sub, err := subscriber.Subscribe(idx, noPrereqs)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.sub = sub
+
+	err = idx.Init(subscriber.ctx, chain.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// Init initializes the spend index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Init(ctx context.Context, chainParams *chaincfg.Params) error {
+	if interruptRequested(ctx) {
+		return indexerError(ErrInterruptRequested, interruptMsg)
+	}
+
+	// Finish any drops that were previously interrupted.
+	if err := finishDrop(ctx, idx); err != nil {
+		return err
+	}
+
+	// Create the initial state for the index as needed.
+	if err := createIndex(idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Upgrade the index as needed.
+	if err := upgradeIndex(ctx, idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Recover the spend index to the main chain if needed.
+	return recoverIndex(ctx, idx)
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Key() []byte {
+	return spendIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Name() string {
+	return spendIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Version() uint32 {
+	return spendIndexVersion
+}
+
+// DB returns the database of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) DB() database.DB {
+	return idx.db
+}
+
+// Queryer returns the chain queryer.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Queryer() ChainQueryer {
+	return idx.chain
+}
+
+// Tip returns the current tip of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Tip() (int64, *chainhash.Hash, error) {
+	return tip(idx.db, idx.Key())
+}
+
+// IndexSubscription returns the subscription for index updates.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) IndexSubscription() *IndexSubscription {
+	return idx.sub
+}
+
+// NotifySyncSubscribers signals subscribers of an index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) NotifySyncSubscribers() {
+	idx.mtx.Lock()
+	notifySyncSubscribers(idx.subscribers)
+	idx.mtx.Unlock()
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) WaitForSync() chan bool {
+	c := make(chan bool)
+
+	idx.mtx.Lock()
+	idx.subscribers[c] = struct{}{}
+	idx.mtx.Unlock()
+
+	return c
+}
+
+// Create is invoked when the index is created for the first time.  It creates
+// the bucket for the spend index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(spendIndexKey)
+	return err
+}
+
+// connectBlock adds a spend index entry for every output spent by the
+// transactions in the passed block and removes the entries for the regular
+// tree of the parent block when the passed block disapproves it.
+func (idx *SpendIndex) connectBlock(dbTx database.Tx, block, parent *dcrutil.Block) error {
+	// Remove the spends of the regular tree of the parent block when the
+	// block being connected disapproves it since those transactions no longer
+	// apply spend semantics.  Note that this must be done prior to adding the
+	// spends of the block being connected since it is common for disapproved
+	// transactions to be mined again.
+	if !headerApprovesParent(&block.MsgBlock().Header) && parent != nil {
+		err := dbRemoveSpendIndexEntries(dbTx, parent.Transactions())
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add the spends of both the regular and stake trees of the block.
+	err := dbAddSpendIndexEntries(dbTx, block.Transactions(), block)
+	if err != nil {
+		return err
+	}
+	err = dbAddSpendIndexEntries(dbTx, block.STransactions(), block)
+	if err != nil {
+		return err
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), block.Hash(), int32(block.Height()))
+}
+
+// disconnectBlock removes the spend index entry for every output spent by the
+// transactions in the passed block and restores the entries for the regular
+// tree of the parent block when the passed block disapproved it.
+func (idx *SpendIndex) disconnectBlock(dbTx database.Tx, block, parent *dcrutil.Block) error {
+	// Remove the spends of both the regular and stake trees of the block.
+	err := dbRemoveSpendIndexEntries(dbTx, block.Transactions())
+	if err != nil {
+		return err
+	}
+	err = dbRemoveSpendIndexEntries(dbTx, block.STransactions())
+	if err != nil {
+		return err
+	}
+
+	// Restore the spends of the regular tree of the parent block when the
+	// block being disconnected disapproved it since those transactions apply
+	// spend semantics again.
+	if !headerApprovesParent(&block.MsgBlock().Header) && parent != nil {
+		err := dbAddSpendIndexEntries(dbTx, parent.Transactions(), parent)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), &block.MsgBlock().Header.PrevBlock,
+		int32(block.Height()-1))
+}
+
+// Entry returns details about the transaction that spent the provided outpoint
+// in the main chain as of the current index tip.  When there is no entry for
+// the provided outpoint, which is the case when the output is either unspent
+// or does not exist, nil will be returned for both the entry and the error.
+//
+// This function is safe for concurrent access.
+func (idx *SpendIndex) Entry(outpoint *wire.OutPoint) (*SpendIndexEntry, error) {
+	var entry *SpendIndexEntry
+	err := idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		entry, err = dbFetchSpendIndexEntry(dbTx, outpoint)
+		return err
+	})
+	return entry, err
+}
+
+// DropSpendIndex drops the spend index from the provided database if it
+// exists.
+func DropSpendIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, spendIndexKey, spendIndexName)
+}
+
+// DropIndex drops the spend index from the provided database if it exists.
+func (*SpendIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropSpendIndex(ctx, db)
+}
+
+// ProcessNotification indexes the provided notification based on its
+// notification type.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) ProcessNotification(dbTx database.Tx, ntfn *IndexNtfn) error {
+	switch ntfn.NtfnType {
+	case ConnectNtfn:
+		err := idx.connectBlock(dbTx, ntfn.Block, ntfn.Parent)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to connect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrConnectBlock, msg)
+		}
+
+	case DisconnectNtfn:
+		err := idx.disconnectBlock(dbTx, ntfn.Block, ntfn.Parent)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to disconnect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrDisconnectBlock, msg)
+		}
+
+	default:
+		msg := fmt.Sprintf("%s: unknown notification type received: %d",
+			idx.Name(), ntfn.NtfnType)
+		return indexerError(ErrInvalidNotificationType, msg)
+	}
+
+	return nil
+}
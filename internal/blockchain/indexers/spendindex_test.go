@@ -0,0 +1,200 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/v5/chaingen"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+// assertSpendIndexEntry ensures the spend index entry for the provided outpoint
+// matches the provided expected spender and the block that contains it.  A nil
+// spender indicates that no entry is expected.
+func assertSpendIndexEntry(t *testing.T, idx *SpendIndex, outpoint *wire.OutPoint, spender *dcrutil.Tx, block *dcrutil.Block) {
+	t.Helper()
+
+	entry, err := idx.Entry(outpoint)
+	if err != nil {
+		t.Fatalf("unexpected error fetching spend entry for %v: %v",
+			outpoint, err)
+	}
+	if spender == nil {
+		if entry != nil {
+			t.Fatalf("unexpected spend entry for %v: %+v", outpoint, entry)
+		}
+		return
+	}
+	if entry == nil {
+		t.Fatalf("missing spend entry for %v", outpoint)
+	}
+	if entry.SpenderHash != *spender.Hash() {
+		t.Fatalf("mismatched spender hash for %v: got %v, want %v",
+			outpoint, entry.SpenderHash, spender.Hash())
+	}
+	if entry.InputIndex != 0 {
+		t.Fatalf("mismatched input index for %v: got %d, want 0", outpoint,
+			entry.InputIndex)
+	}
+	if entry.BlockHash != *block.Hash() {
+		t.Fatalf("mismatched block hash for %v: got %v, want %v", outpoint,
+			entry.BlockHash, block.Hash())
+	}
+	if entry.BlockHeight != block.Height() {
+		t.Fatalf("mismatched block height for %v: got %d, want %d", outpoint,
+			entry.BlockHeight, block.Height())
+	}
+}
+
+// TestSpendIndexAsync ensures the spend index behaves as expected receiving
+// async notifications.
+func TestSpendIndexAsync(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain followed by one that spends an output of
+	// the coinbase of the second one.  Note that the spend is not valid per
+	// consensus since the coinbase is not mature, however, the index does not
+	// care.
+	addBlock(t, chain, &g, "bk1")
+	bk2 := addBlock(t, chain, &g, "bk2")
+	addBlock(t, chain, &g, "bk3")
+	spend := chaingen.MakeSpendableOutForTx(bk2.MsgBlock().Transactions[0],
+		uint32(bk2.Height()), 0, 2)
+	bk4 := dcrutil.NewBlock(g.NextBlock("bk4", &spend, nil))
+	if err := chain.AddBlock(bk4); err != nil {
+		t.Fatal(err)
+	}
+	spendTx := bk4.Transactions()[1]
+	outpoint := spend.PrevOut()
+
+	// Initialize the spend index.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	idx, err := NewSpendIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the index got synced to bk4 on initialization and the spend is
+	// indexed.
+	tipHeight, tipHash, err := idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk4.Height() {
+		t.Fatalf("expected tip height to be %d, got %d", bk4.Height(),
+			tipHeight)
+	}
+	if *tipHash != *bk4.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk4.Hash(), tipHash)
+	}
+	assertSpendIndexEntry(t, idx, &outpoint, spendTx, bk4)
+
+	// Ensure the null inputs of coinbases are not indexed.
+	nullOutpoint := bk4.MsgBlock().Transactions[0].TxIn[0].PreviousOutPoint
+	assertSpendIndexEntry(t, idx, &nullOutpoint, nil, nil)
+
+	// Connect a block that disapproves the regular tree of bk4 and ensure the
+	// spend is no longer indexed since it no longer applies.
+	bk5 := dcrutil.NewBlock(g.NextBlock("bk5", nil, nil,
+		func(b *wire.MsgBlock) {
+			b.Header.VoteBits &^= dcrutil.BlockValid
+		}))
+	if err := chain.AddBlock(bk5); err != nil {
+		t.Fatal(err)
+	}
+	notifyAndWait(t, subber, &IndexNtfn{
+		NtfnType: ConnectNtfn,
+		Block:    bk5,
+		Parent:   bk4,
+	})
+	assertSpendIndexEntry(t, idx, &outpoint, nil, nil)
+
+	// Disconnect the disapproving block and ensure the spend is restored.
+	if err := chain.RemoveBlock(bk5); err != nil {
+		t.Fatal(err)
+	}
+	g.SetTip("bk4")
+	notifyAndWait(t, subber, &IndexNtfn{
+		NtfnType: DisconnectNtfn,
+		Block:    bk5,
+		Parent:   bk4,
+	})
+	assertSpendIndexEntry(t, idx, &outpoint, spendTx, bk4)
+
+	// Simulate a reorg that replaces bk4 with a block that does not contain
+	// the spend and resubscribe the index so it recovers to the main chain.
+	if err := chain.RemoveBlock(bk4); err != nil {
+		t.Fatal(err)
+	}
+	g.SetTip("bk3")
+	addBlock(t, chain, &g, "bk4a")
+	bk5a := addBlock(t, chain, &g, "bk5a")
+
+	subber.mtx.Lock()
+	err = idx.sub.stop()
+	subber.mtx.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err = NewSpendIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tipHeight, tipHash, err = idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk5a.Height() {
+		t.Fatalf("expected tip height to be %d, got %d", bk5a.Height(),
+			tipHeight)
+	}
+	if *tipHash != *bk5a.Hash() {
+		t.Fatalf("expected tip hash to be %s, got %s", bk5a.Hash(), tipHash)
+	}
+	assertSpendIndexEntry(t, idx, &outpoint, nil, nil)
+
+	// Ensure the index can be dropped.
+	err = idx.DropIndex(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exists, err := existsIndex(db, spendIndexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatal("expected spend index to not exist after drop")
+	}
+}
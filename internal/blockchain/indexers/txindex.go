@@ -152,6 +152,18 @@ func dbRemoveBlockIDIndexEntry(dbTx database.Tx, hash *chainhash.Hash) error {
 	return idIndex.Delete(serializedID)
 }
 
+// dbFetchBlockIDByHash uses an existing database transaction to retrieve the
+// block id for the provided hash from the index.
+func dbFetchBlockIDByHash(dbTx database.Tx, hash *chainhash.Hash) (uint32, error) {
+	hashIndex := dbTx.Metadata().Bucket(idByHashIndexBucketName)
+	serializedID := hashIndex.Get(hash[:])
+	if serializedID == nil {
+		return 0, errNoBlockIDEntry
+	}
+
+	return byteOrder.Uint32(serializedID), nil
+}
+
 // dbFetchBlockHashBySerializedID uses an existing database transaction to
 // retrieve the hash for the provided serialized block id from the index.
 func dbFetchBlockHashBySerializedID(dbTx database.Tx, serializedID []byte) (*chainhash.Hash, error) {
@@ -640,6 +652,11 @@ func DropTxIndex(ctx context.Context, db database.DB) error {
 		return nil
 	}
 
+	// Drop the address index first since it relies on the transaction index.
+	if err := DropAddrIndex(ctx, db); err != nil {
+		return err
+	}
+
 	// Mark that the index is in the process of being dropped so that it
 	// can be resumed on the next start if interrupted before the process is
 	// complete.
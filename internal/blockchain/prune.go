@@ -1,4 +1,4 @@
-// Copyright (c) 2015-2021 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -6,9 +6,43 @@ package blockchain
 
 import (
 	"math"
+	"sort"
 	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
 )
 
+const (
+	// blockDataPruneInterval is the minimum amount of time between attempts
+	// to prune old block data when block data pruning is enabled.
+	blockDataPruneInterval = time.Minute
+
+	// pruneReorgDepth is the number of blocks beyond those required for
+	// validation to retain when pruning block data so that reasonably deep
+	// chain reorganizations are still possible.
+	pruneReorgDepth = 288
+)
+
+// minPruneRetainBlocks returns the minimum number of the most recent main chain
+// blocks for which block data must be retained when pruning block data for the
+// provided network parameters.
+//
+// Block data is needed to determine the tickets that mature in new blocks and to
+// tally the votes for treasury spends over the current voting window, so the
+// result covers whichever of those is deeper along with an additional margin to
+// support reorganizations.
+func minPruneRetainBlocks(params *chaincfg.Params) int64 {
+	retain := int64(params.TicketMaturity)
+	tspendWindow := int64(params.TreasuryVoteInterval *
+		params.TreasuryVoteIntervalMultiplier)
+	if tspendWindow > retain {
+		retain = tspendWindow
+	}
+	return retain + pruneReorgDepth
+}
+
 // poissonConfidenceSecs returns the number of seconds it will take to produce
 // an event at the provided confidence level given a Poisson distribution with 1
 // event occurring in the given target interval.
@@ -40,6 +74,10 @@ type chainPruner struct {
 	lastPruneTime   time.Time
 	pruningInterval time.Duration
 
+	// lastBlockDataPruneTime is the last time an attempt was made to prune old
+	// block data from the database.
+	lastBlockDataPruneTime time.Time
+
 	// prunedPerIntervalHint is the maximum expected number of nodes that will
 	// be pruned per pruning interval with a high degree of confidence.
 	prunedPerIntervalHint int64
@@ -75,11 +113,22 @@ func newChainPruner(chain *BlockChain) *chainPruner {
 }
 
 // pruneChainIfNeeded removes references to old information that should no
-// longer be held in memory if the pruning interval has elapsed.
+// longer be held in memory if the pruning interval has elapsed.  It also
+// removes old block data from the database when block data pruning is enabled
+// and the block data pruning interval has elapsed.
 //
 // This function MUST be called with the chain lock held (for writes).
 func (c *chainPruner) pruneChainIfNeeded() {
 	now := time.Now()
+	if c.chain.pruneTarget != 0 &&
+		now.Sub(c.lastBlockDataPruneTime) >= blockDataPruneInterval {
+
+		c.lastBlockDataPruneTime = now
+		if err := c.chain.pruneBlockData(); err != nil {
+			log.Errorf("Unable to prune block data: %v", err)
+		}
+	}
+
 	duration := now.Sub(c.lastPruneTime)
 	if duration < c.pruningInterval {
 		return
@@ -88,3 +137,111 @@ func (c *chainPruner) pruneChainIfNeeded() {
 	c.lastPruneTime = now
 	c.chain.pruneStakeNodes()
 }
+
+// findPruneHeight returns the height of the oldest block in the main chain for
+// which block data is available in the database.  It relies on block data
+// always being pruned oldest first.
+//
+// This function MUST be called with the chain lock held (for reads).
+func (b *BlockChain) findPruneHeight() (int64, error) {
+	tip := b.bestChain.Tip()
+	var pruneHeight int64
+	err := b.db.View(func(dbTx database.Tx) error {
+		var searchErr error
+		pruneHeight = int64(sort.Search(int(tip.height+1), func(i int) bool {
+			if searchErr != nil {
+				return true
+			}
+			node := b.bestChain.NodeByHeight(int64(i))
+			hasBlock, err := dbTx.HasBlock(&node.hash)
+			if err != nil {
+				searchErr = err
+				return true
+			}
+			return hasBlock
+		}))
+		return searchErr
+	})
+	return pruneHeight, err
+}
+
+// pruneBlockData removes the oldest block data from the database as needed to
+// remain under the configured prune target.  The data for the most recent
+// blocks needed for validation and reorganizations is always retained as well
+// as the data for all blocks after the last time the utxo set was flushed since
+// they are needed to initialize the utxo cache on startup.
+//
+// This function MUST be called with the chain lock held (for writes).
+func (b *BlockChain) pruneBlockData() error {
+	tip := b.bestChain.Tip()
+	keepHeight := tip.height - minPruneRetainBlocks(b.chainParams)
+	utxoState, err := b.utxoCache.FetchBackendState()
+	if err != nil {
+		return err
+	}
+	if utxoState != nil && int64(utxoState.lastFlushHeight) < keepHeight {
+		keepHeight = int64(utxoState.lastFlushHeight)
+	}
+	if keepHeight <= 0 {
+		return nil
+	}
+
+	// Keep the data for all blocks at or after the calculated height along
+	// with any that are unexpectedly not in the block index.
+	keep := func(hash *chainhash.Hash) bool {
+		node := b.index.LookupNode(hash)
+		return node == nil || node.height >= keepHeight
+	}
+	var pruned []chainhash.Hash
+	err = b.db.Update(func(dbTx database.Tx) error {
+		var err error
+		pruner := dbTx.(database.BlockPruner)
+		pruned, err = pruner.PruneBlocks(b.pruneTarget, keep)
+		return err
+	})
+	if err != nil || len(pruned) == 0 {
+		return err
+	}
+
+	pruneHeight, err := b.findPruneHeight()
+	if err != nil {
+		return err
+	}
+	b.beenPruned = true
+	b.pruneHeight = pruneHeight
+	log.Infof("Pruned data for %d blocks (block data is now available from "+
+		"height %d)", len(pruned), pruneHeight)
+	return nil
+}
+
+// IsPruned returns whether or not block data pruning is enabled or old block
+// data has previously been pruned from the database.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) IsPruned() bool {
+	b.chainLock.RLock()
+	pruned := b.pruneTarget != 0 || b.beenPruned
+	b.chainLock.RUnlock()
+	return pruned
+}
+
+// PruneHeight returns the height of the oldest block in the main chain for
+// which block data is available.  It will be zero when no block data has been
+// pruned.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) PruneHeight() int64 {
+	b.chainLock.RLock()
+	pruneHeight := b.pruneHeight
+	b.chainLock.RUnlock()
+	return pruneHeight
+}
+
+// PruneTarget returns the target size in bytes for the stored block data as
+// configured when the chain instance was created.  It will be zero when block
+// data pruning is disabled.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) PruneTarget() uint64 {
+	return b.pruneTarget
+}
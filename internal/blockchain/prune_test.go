@@ -0,0 +1,62 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+// TestMinPruneRetainBlocks ensures the minimum number of blocks to retain when
+// pruning block data covers the ticket maturity and treasury spend vote window
+// along with the reorg margin for all networks.
+func TestMinPruneRetainBlocks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		params *chaincfg.Params
+		want   int64
+	}{{
+		name:   "mainnet",
+		params: chaincfg.MainNetParams(),
+		want:   288*12 + pruneReorgDepth,
+	}, {
+		name:   "testnet",
+		params: chaincfg.TestNet3Params(),
+		want:   60*4 + pruneReorgDepth,
+	}, {
+		name:   "simnet",
+		params: chaincfg.SimNetParams(),
+		want:   48*3 + pruneReorgDepth,
+	}, {
+		name:   "regnet",
+		params: chaincfg.RegNetParams(),
+		want:   16 + pruneReorgDepth,
+	}}
+
+	for _, test := range tests {
+		got := minPruneRetainBlocks(test.params)
+		if got != test.want {
+			t.Errorf("%s: unexpected retained blocks -- got %d, want %d",
+				test.name, got, test.want)
+			continue
+		}
+
+		// Ensure the result always covers both the ticket maturity and the
+		// treasury spend voting window.
+		params := test.params
+		tspendWindow := int64(params.TreasuryVoteInterval *
+			params.TreasuryVoteIntervalMultiplier)
+		if got < int64(params.TicketMaturity)+pruneReorgDepth ||
+			got < tspendWindow+pruneReorgDepth {
+
+			t.Errorf("%s: retained blocks %d do not cover ticket maturity "+
+				"%d and tspend window %d", test.name, got,
+				params.TicketMaturity, tspendWindow)
+		}
+	}
+}
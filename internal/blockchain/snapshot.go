@@ -0,0 +1,1043 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/staging/primitives"
+	"github.com/decred/dcrd/math/uint256"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// snapshotVersion is the current version of the chain state snapshot
+	// serialization format.
+	snapshotVersion = 1
+
+	// snapshotHeaderSize is the size of the serialized header of a chain state
+	// snapshot.
+	snapshotHeaderSize = 4 + 4 + 4 + chainhash.HashSize + 4 + 8 + 8 + 4 + 5*4
+
+	// maxSnapshotRecordSize is the maximum size of the key or value of an
+	// individual record in a chain state snapshot.
+	maxSnapshotRecordSize = wire.MaxMessagePayload
+
+	// snapshotImportBatchSize is the approximate number of bytes of records
+	// that are written to the databases in a single transaction when
+	// importing a chain state snapshot.
+	snapshotImportBatchSize = 32 * 1024 * 1024
+
+	// snapshotCheckCtxInterval is the number of records between checks for
+	// cancellation when exporting and importing chain state snapshots.
+	snapshotCheckCtxInterval = 1000
+)
+
+var (
+	// snapshotMagic is the magic value that identifies a chain state snapshot.
+	snapshotMagic = [4]byte{'d', 'c', 'r', 's'}
+
+	// snapshotInfoKeyName is the name of the database key used to house the
+	// information about the chain state snapshot the database was created
+	// from, if any.
+	snapshotInfoKeyName = []byte("snapshotinfo")
+)
+
+// snapshotSection identifies the type of data that a record of a chain state
+// snapshot contains.  The records of a snapshot are ordered by their section.
+type snapshotSection uint8
+
+// These constants define the sections of a chain state snapshot in the order
+// they appear.
+const (
+	snapshotSectionBlockIndex snapshotSection = iota + 1
+	snapshotSectionBlocks
+	snapshotSectionSpendJournal
+	snapshotSectionGCSFilters
+	snapshotSectionHeaderCmts
+	snapshotSectionTreasury
+	snapshotSectionTSpends
+	snapshotSectionStake
+	snapshotSectionUtxoSet
+	snapshotSectionEnd
+)
+
+// SnapshotInfo identifies a chain state snapshot.
+type SnapshotInfo struct {
+	// Height and BlockHash identify the main chain block the snapshot
+	// represents the chain state of.
+	Height    int64
+	BlockHash chainhash.Hash
+
+	// SnapshotHash is the hash of the entire serialized snapshot.
+	SnapshotHash chainhash.Hash
+}
+
+// -----------------------------------------------------------------------------
+// A chain state snapshot contains everything needed to start a node at a
+// specific main chain block without having the history prior to it.
+//
+// The serialized snapshot consists of a fixed size header followed by a series
+// of records ordered by section, where all integers are little endian:
+//
+//   Field            Type     Size
+//   magic            [4]byte  4
+//   version          uint32   4
+//   network          uint32   4
+//   block hash       [32]byte 32
+//   block height     uint32   4
+//   total txns       uint64   8
+//   total subsidy    uint64   8
+//   window start     uint32   4
+//   db version       uint32   4
+//   compression ver  uint32   4
+//   block index ver  uint32   4
+//   spend journal    uint32   4
+//   utxo set ver     uint32   4
+//
+// Each record is serialized as:
+//
+//   <section><key len><key><value len><value>
+//
+//   Field            Type     Size
+//   section          uint8    1
+//   key len          VLQ      variable
+//   key              []byte   key len
+//   value len        VLQ      variable
+//   value            []byte   value len
+//
+// The snapshot contains the block index entries for all main chain blocks,
+// the treasury state of all main chain blocks, the treasury spends included in
+// main chain blocks, the ticket database, and the full UTXO set.  The block
+// data, spend journal entries, filters, header commitments, and per-block
+// ticket data are only included for the blocks at or after the window start,
+// which covers the most recent blocks that are required for validation and
+// reorganizations in the same way as when pruning block data.
+//
+// The snapshot is terminated by a record in the end section with an empty key
+// and value.  The hash of a snapshot is the BLAKE-256 hash of its entire
+// serialization.
+// -----------------------------------------------------------------------------
+
+// snapshotHeader houses the fields of the header of a chain state snapshot.
+type snapshotHeader struct {
+	version      uint32
+	net          wire.CurrencyNet
+	hash         chainhash.Hash
+	height       uint32
+	totalTxns    uint64
+	totalSubsidy int64
+	windowStart  uint32
+	dbVersion    uint32
+	compVer      uint32
+	bidxVer      uint32
+	stxoVer      uint32
+	utxoVer      uint32
+}
+
+// serialize returns the serialized snapshot header.
+func (h *snapshotHeader) serialize() []byte {
+	b := make([]byte, snapshotHeaderSize)
+	copy(b, snapshotMagic[:])
+	offset := len(snapshotMagic)
+	byteOrder.PutUint32(b[offset:], h.version)
+	offset += 4
+	byteOrder.PutUint32(b[offset:], uint32(h.net))
+	offset += 4
+	copy(b[offset:], h.hash[:])
+	offset += chainhash.HashSize
+	byteOrder.PutUint32(b[offset:], h.height)
+	offset += 4
+	byteOrder.PutUint64(b[offset:], h.totalTxns)
+	offset += 8
+	byteOrder.PutUint64(b[offset:], uint64(h.totalSubsidy))
+	offset += 8
+	for _, v := range []uint32{h.windowStart, h.dbVersion, h.compVer,
+		h.bidxVer, h.stxoVer, h.utxoVer} {
+
+		byteOrder.PutUint32(b[offset:], v)
+		offset += 4
+	}
+	return b
+}
+
+// deserializeSnapshotHeader deserializes the passed serialized snapshot header.
+func deserializeSnapshotHeader(b []byte) (*snapshotHeader, error) {
+	if len(b) != snapshotHeaderSize || !bytes.Equal(b[:4], snapshotMagic[:]) {
+		return nil, contextError(ErrInvalidSnapshot, "not a chain state "+
+			"snapshot")
+	}
+
+	var h snapshotHeader
+	offset := len(snapshotMagic)
+	h.version = byteOrder.Uint32(b[offset:])
+	offset += 4
+	h.net = wire.CurrencyNet(byteOrder.Uint32(b[offset:]))
+	offset += 4
+	copy(h.hash[:], b[offset:])
+	offset += chainhash.HashSize
+	h.height = byteOrder.Uint32(b[offset:])
+	offset += 4
+	h.totalTxns = byteOrder.Uint64(b[offset:])
+	offset += 8
+	h.totalSubsidy = int64(byteOrder.Uint64(b[offset:]))
+	offset += 8
+	for _, v := range []*uint32{&h.windowStart, &h.dbVersion, &h.compVer,
+		&h.bidxVer, &h.stxoVer, &h.utxoVer} {
+
+		*v = byteOrder.Uint32(b[offset:])
+		offset += 4
+	}
+	return &h, nil
+}
+
+// snapshotWindowStart returns the height of the oldest block for which block
+// data is included in a chain state snapshot of the main chain block at the
+// provided height.
+func snapshotWindowStart(height int64, params *chaincfg.Params) int64 {
+	windowStart := height - minPruneRetainBlocks(params) + 1
+	if windowStart < 0 {
+		windowStart = 0
+	}
+	return windowStart
+}
+
+// snapshotWriter writes the records of a chain state snapshot to an underlying
+// writer.
+type snapshotWriter struct {
+	ctx        context.Context
+	w          *bufio.Writer
+	numRecords uint64
+	scratch    [binary.MaxVarintLen64]byte
+}
+
+// writeRecord writes a record in the provided section to the snapshot.
+func (w *snapshotWriter) writeRecord(section snapshotSection, key, value []byte) error {
+	w.numRecords++
+	if w.numRecords%snapshotCheckCtxInterval == 0 {
+		if err := w.ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.w.WriteByte(byte(section)); err != nil {
+		return err
+	}
+	for _, data := range [][]byte{key, value} {
+		n := binary.PutUvarint(w.scratch[:], uint64(len(data)))
+		if _, err := w.w.Write(w.scratch[:n]); err != nil {
+			return err
+		}
+		if _, err := w.w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpUtxoSnapshot writes a chain state snapshot of the current main chain tip
+// to the provided writer and returns the information that identifies it.
+//
+// The snapshot includes the UTXO set, the ticket database, and the treasury
+// state along with the block index and the recent block data that is required
+// to resume from the snapshot.  The same chain state always results in the
+// same snapshot so that its hash may be independently verified.
+//
+// The chain is only locked while the UTXO cache is flushed and a consistent
+// view of the databases is acquired, so blocks may continue to be processed
+// while the snapshot is written.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) DumpUtxoSnapshot(ctx context.Context, w io.Writer) (*SnapshotInfo, error) {
+	// Flush the UTXO cache so the backend reflects the current tip and obtain
+	// consistent views of the databases along with the main chain as of the
+	// current tip.
+	b.chainLock.Lock()
+	tip := b.bestChain.Tip()
+	err := b.utxoCache.MaybeFlush(&tip.hash, uint32(tip.height), true, false)
+	if err != nil {
+		b.chainLock.Unlock()
+		return nil, err
+	}
+	utxoInfo, err := b.utxoBackend.FetchInfo()
+	if err != nil {
+		b.chainLock.Unlock()
+		return nil, err
+	}
+	nodes := make([]*blockNode, tip.height+1)
+	for node := tip; node != nil; node = node.parent {
+		nodes[node.height] = node
+	}
+	state := b.BestSnapshot()
+	dbTx, err := b.db.Begin(false)
+	if err != nil {
+		b.chainLock.Unlock()
+		return nil, err
+	}
+	defer dbTx.Rollback()
+	utxoIter := b.utxoBackend.NewIterator(utxoPrefixUtxoSet)
+	defer utxoIter.Release()
+	b.chainLock.Unlock()
+
+	windowStart := snapshotWindowStart(tip.height, b.chainParams)
+	header := snapshotHeader{
+		version:      snapshotVersion,
+		net:          b.chainParams.Net,
+		hash:         tip.hash,
+		height:       uint32(tip.height),
+		totalTxns:    state.TotalTxns,
+		totalSubsidy: state.TotalSubsidy,
+		windowStart:  uint32(windowStart),
+		dbVersion:    b.dbInfo.version,
+		compVer:      b.dbInfo.compVer,
+		bidxVer:      b.dbInfo.bidxVer,
+		stxoVer:      b.dbInfo.stxoVer,
+		utxoVer:      utxoInfo.utxoVer,
+	}
+
+	hasher := blake256.NewHasher256()
+	sw := &snapshotWriter{
+		ctx: ctx,
+		w:   bufio.NewWriterSize(io.MultiWriter(w, hasher), 1<<20),
+	}
+	if _, err := sw.w.Write(header.serialize()); err != nil {
+		return nil, err
+	}
+
+	// Write the block index entries for all main chain blocks.  The status is
+	// normalized since it is only relevant to the local node.
+	for _, node := range nodes {
+		serialized, err := serializeBlockIndexEntry(&blockIndexEntry{
+			header:   node.Header(),
+			status:   statusDataStored | statusValidated,
+			voteInfo: node.votes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		key := blockIndexKey(&node.hash, uint32(node.height))
+		err = sw.writeRecord(snapshotSectionBlockIndex, key, serialized)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Write the block data for the blocks in the window.
+	window := nodes[windowStart:]
+	for _, node := range window {
+		blockBytes, err := dbTx.FetchBlock(&node.hash)
+		if err != nil {
+			return nil, err
+		}
+		err = sw.writeRecord(snapshotSectionBlocks, node.hash[:], blockBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Write the spend journal entries, filters, and header commitments for the
+	// blocks in the window.
+	meta := dbTx.Metadata()
+	windowBuckets := []struct {
+		section snapshotSection
+		bucket  database.Bucket
+	}{
+		{snapshotSectionSpendJournal, meta.Bucket(spendJournalBucketName)},
+		{snapshotSectionGCSFilters, meta.Bucket(gcsFilterBucketName)},
+		{snapshotSectionHeaderCmts, meta.Bucket(headerCmtsBucketName)},
+	}
+	for _, wb := range windowBuckets {
+		for _, node := range window {
+			value := wb.bucket.Get(node.hash[:])
+			if value == nil {
+				continue
+			}
+			if err := sw.writeRecord(wb.section, node.hash[:], value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Write the treasury state of all main chain blocks.
+	treasuryBucket := meta.Bucket(treasuryBucketName)
+	for _, node := range nodes {
+		value := treasuryBucket.Get(node.hash[:])
+		if value == nil {
+			continue
+		}
+		err := sw.writeRecord(snapshotSectionTreasury, node.hash[:], value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Write the treasury spends limited to the main chain blocks that include
+	// them.
+	isMainChainBlock := func(hash *chainhash.Hash) bool {
+		node := b.index.LookupNode(hash)
+		return node != nil && node.height <= tip.height &&
+			nodes[node.height] == node
+	}
+	tspendBucket := meta.Bucket(treasuryTSpendBucketName)
+	err = tspendBucket.ForEach(func(k, v []byte) error {
+		blocks, err := deserializeTSpend(v)
+		if err != nil {
+			return err
+		}
+		mainChainBlocks := blocks[:0]
+		for i := range blocks {
+			if isMainChainBlock(&blocks[i]) {
+				mainChainBlocks = append(mainChainBlocks, blocks[i])
+			}
+		}
+		if len(mainChainBlocks) == 0 {
+			return nil
+		}
+		serialized, err := serializeTSpend(mainChainBlocks)
+		if err != nil {
+			return err
+		}
+		return sw.writeRecord(snapshotSectionTSpends, k, serialized)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the ticket database.
+	var stakeKey []byte
+	err = stake.ForEachSnapshotRecord(dbTx, uint32(windowStart),
+		func(bucket stake.SnapshotBucket, k, v []byte) error {
+			stakeKey = append(append(stakeKey[:0], byte(bucket)), k...)
+			return sw.writeRecord(snapshotSectionStake, stakeKey, v)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the UTXO set.
+	for utxoIter.Next() {
+		err := sw.writeRecord(snapshotSectionUtxoSet, utxoIter.Key(),
+			utxoIter.Value())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := utxoIter.Error(); err != nil {
+		return nil, convertLdbErr(err, "failed to iterate utxo set")
+	}
+
+	// Terminate the snapshot.
+	if err := sw.writeRecord(snapshotSectionEnd, nil, nil); err != nil {
+		return nil, err
+	}
+	if err := sw.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotInfo{
+		Height:       tip.height,
+		BlockHash:    tip.hash,
+		SnapshotHash: chainhash.Hash(hasher.Sum256()),
+	}, nil
+}
+
+// snapshotReader reads the records of a chain state snapshot from an
+// underlying reader.
+type snapshotReader struct {
+	ctx         context.Context
+	r           *bufio.Reader
+	numRecords  uint64
+	lastSection snapshotSection
+}
+
+// readRecord reads the next record from the snapshot.  The returned key and
+// value are newly allocated.
+func (r *snapshotReader) readRecord() (snapshotSection, []byte, []byte, error) {
+	r.numRecords++
+	if r.numRecords%snapshotCheckCtxInterval == 0 {
+		if err := r.ctx.Err(); err != nil {
+			return 0, nil, nil, err
+		}
+	}
+
+	sectionByte, err := r.r.ReadByte()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = contextError(ErrInvalidSnapshot, "snapshot ends "+
+				"unexpectedly")
+		}
+		return 0, nil, nil, err
+	}
+	section := snapshotSection(sectionByte)
+	if section < r.lastSection || section > snapshotSectionEnd {
+		str := fmt.Sprintf("unexpected record in section %d after section "+
+			"%d", section, r.lastSection)
+		return 0, nil, nil, contextError(ErrInvalidSnapshot, str)
+	}
+	r.lastSection = section
+
+	var data [2][]byte
+	for i := range data {
+		size, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				err = contextError(ErrInvalidSnapshot, "snapshot ends "+
+					"unexpectedly")
+			}
+			return 0, nil, nil, err
+		}
+		if size > maxSnapshotRecordSize {
+			str := fmt.Sprintf("snapshot record size %d exceeds the max "+
+				"allowed size of %d", size, maxSnapshotRecordSize)
+			return 0, nil, nil, contextError(ErrInvalidSnapshot, str)
+		}
+		data[i] = make([]byte, size)
+		if _, err := io.ReadFull(r.r, data[i]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				err = contextError(ErrInvalidSnapshot, "snapshot ends "+
+					"unexpectedly")
+			}
+			return 0, nil, nil, err
+		}
+	}
+	return section, data[0], data[1], nil
+}
+
+// snapshotImporter houses the state used when importing a chain state
+// snapshot.
+type snapshotImporter struct {
+	db          database.DB
+	utxoBackend UtxoBackend
+	params      *chaincfg.Params
+	header      *snapshotHeader
+
+	// dbTx is the current block database transaction and dbTxSize is the
+	// approximate number of bytes written in it.
+	dbTx     database.Tx
+	dbTxSize int
+
+	// utxos are the pending UTXO set entries to write to the backend and
+	// utxosSize is their approximate number of bytes.
+	utxos     [][2][]byte
+	utxosSize int
+
+	// hashes are the main chain block hashes by height and workSum is the
+	// cumulative work of the main chain as loaded from the block index.
+	hashes  []chainhash.Hash
+	workSum uint256.Uint256
+
+	// windowBlocks are the main chain blocks in the window of block data
+	// included in the snapshot.  It is populated once the block index has
+	// been loaded.
+	windowBlocks map[chainhash.Hash]struct{}
+}
+
+// invalidf returns an error of kind ErrInvalidSnapshot with a description
+// formatted according to the provided format specifier.
+func invalidf(format string, args ...interface{}) error {
+	return contextError(ErrInvalidSnapshot, fmt.Sprintf(format, args...))
+}
+
+// maybeCommit commits the current block database transaction and starts a new
+// one once the amount of data written in it reaches the batch size.
+func (si *snapshotImporter) maybeCommit(force bool) error {
+	if !force && si.dbTxSize < snapshotImportBatchSize {
+		return nil
+	}
+	if err := si.dbTx.Commit(); err != nil {
+		si.dbTx = nil
+		return err
+	}
+	dbTx, err := si.db.Begin(true)
+	if err != nil {
+		si.dbTx = nil
+		return err
+	}
+	si.dbTx = dbTx
+	si.dbTxSize = 0
+	return nil
+}
+
+// flushUtxos writes the pending UTXO set entries to the backend once their
+// size reaches the batch size.
+func (si *snapshotImporter) flushUtxos(force bool) error {
+	if !force && si.utxosSize < snapshotImportBatchSize {
+		return nil
+	}
+	err := si.utxoBackend.Update(func(tx UtxoBackendTx) error {
+		for _, kv := range si.utxos {
+			if err := tx.Put(kv[0], kv[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	si.utxos = si.utxos[:0]
+	si.utxosSize = 0
+	return nil
+}
+
+// isWindowBlock returns whether or not the provided hash is a main chain block
+// that is in the window of block data included in the snapshot.
+func (si *snapshotImporter) isWindowBlock(hash []byte) bool {
+	if len(hash) != chainhash.HashSize {
+		return false
+	}
+	if si.windowBlocks == nil {
+		window := si.hashes[si.header.windowStart:]
+		si.windowBlocks = make(map[chainhash.Hash]struct{}, len(window))
+		for i := range window {
+			si.windowBlocks[window[i]] = struct{}{}
+		}
+	}
+	_, ok := si.windowBlocks[chainhash.Hash(hash)]
+	return ok
+}
+
+// putRecord validates and stores the provided snapshot record.
+func (si *snapshotImporter) putRecord(section snapshotSection, key, value []byte) error {
+	si.dbTxSize += len(key) + len(value)
+	meta := si.dbTx.Metadata()
+	switch section {
+	case snapshotSectionBlockIndex:
+		height := uint32(len(si.hashes))
+		if len(key) != 4+chainhash.HashSize {
+			return invalidf("malformed block index key %x", key)
+		}
+		keyHeight := binary.BigEndian.Uint32(key)
+		if keyHeight != height || height > si.header.height {
+			return invalidf("unexpected block index entry for height %d",
+				keyHeight)
+		}
+		entry, err := deserializeBlockIndexEntry(value)
+		if err != nil {
+			return invalidf("malformed block index entry at height %d: %v",
+				height, err)
+		}
+		header := &entry.header
+		hash := header.BlockHash()
+		switch {
+		case !bytes.Equal(key[4:], hash[:]) || header.Height != height:
+			return invalidf("block index entry for block %s does not "+
+				"match its key", hash)
+		case height == 0 && hash != si.params.GenesisHash:
+			return invalidf("block index does not start with the genesis " +
+				"block")
+		case height > 0 && header.PrevBlock != si.hashes[height-1]:
+			return invalidf("block index entry for block %s does not "+
+				"connect to the previous entry", hash)
+		}
+		si.hashes = append(si.hashes, hash)
+		work := primitives.CalcWork(header.Bits)
+		si.workSum.Add(&work)
+		return meta.Bucket(blockIndexBucketName).Put(key, value)
+
+	case snapshotSectionBlocks:
+		block, err := dcrutil.NewBlockFromBytes(value)
+		if err != nil {
+			return invalidf("malformed block: %v", err)
+		}
+		if !bytes.Equal(key, block.Hash()[:]) || !si.isWindowBlock(key) {
+			return invalidf("unexpected block %s", block.Hash())
+		}
+		return si.dbTx.StoreBlock(block)
+
+	case snapshotSectionSpendJournal, snapshotSectionGCSFilters,
+		snapshotSectionHeaderCmts:
+
+		if !si.isWindowBlock(key) {
+			return invalidf("unexpected block data for key %x", key)
+		}
+		bucketName := map[snapshotSection][]byte{
+			snapshotSectionSpendJournal: spendJournalBucketName,
+			snapshotSectionGCSFilters:   gcsFilterBucketName,
+			snapshotSectionHeaderCmts:   headerCmtsBucketName,
+		}[section]
+		return meta.Bucket(bucketName).Put(key, value)
+
+	case snapshotSectionTreasury:
+		if len(key) != chainhash.HashSize {
+			return invalidf("malformed treasury state key %x", key)
+		}
+		if _, err := deserializeTreasuryState(value); err != nil {
+			return invalidf("malformed treasury state: %v", err)
+		}
+		return meta.Bucket(treasuryBucketName).Put(key, value)
+
+	case snapshotSectionTSpends:
+		if len(key) != chainhash.HashSize || len(value) < 8 ||
+			(len(value)-8)%chainhash.HashSize != 0 ||
+			byteOrder.Uint64(value) != uint64(len(value)-8)/chainhash.HashSize {
+
+			return invalidf("malformed treasury spend record for key %x",
+				key)
+		}
+		return meta.Bucket(treasuryTSpendBucketName).Put(key, value)
+
+	case snapshotSectionStake:
+		if len(key) == 0 {
+			return invalidf("malformed ticket database record")
+		}
+		err := stake.PutSnapshotRecord(si.dbTx, stake.SnapshotBucket(key[0]),
+			key[1:], value)
+		if errors.Is(err, stake.ErrInvalidSnapshotRecord) {
+			return invalidf("%v", err)
+		}
+		return err
+
+	case snapshotSectionUtxoSet:
+		si.dbTxSize -= len(key) + len(value)
+		if len(key) <= len(utxoPrefixUtxoSet) ||
+			!bytes.HasPrefix(key, utxoPrefixUtxoSet) || len(value) == 0 {
+
+			return invalidf("malformed utxo set record for key %x", key)
+		}
+		si.utxos = append(si.utxos, [2][]byte{key, value})
+		si.utxosSize += len(key) + len(value)
+		return si.flushUtxos(false)
+	}
+
+	return invalidf("unknown snapshot section %d", section)
+}
+
+// ImportUtxoSnapshot populates the provided empty block database and UTXO
+// backend with the chain state snapshot read from the provided reader and
+// returns the information that identifies it.
+//
+// The snapshot must be for one of the blocks defined by the assumed UTXO
+// snapshots in the provided network parameters and its hash must match the one
+// defined for that block.  Note that the hash can only be verified once the
+// entire snapshot has been read, so the databases will contain partial data
+// when an error is returned and callers must discard them.
+//
+// The resulting databases are suitable for creating a new chain instance that
+// resumes from the block the snapshot represents.  The block data prior to the
+// window included in the snapshot is treated as pruned.
+func ImportUtxoSnapshot(ctx context.Context, r io.Reader, db database.DB,
+	utxoBackend UtxoBackend, params *chaincfg.Params) (*SnapshotInfo, error) {
+
+	hasher := blake256.NewHasher256()
+	sr := &snapshotReader{
+		ctx: ctx,
+		r:   bufio.NewReaderSize(io.TeeReader(r, hasher), 1<<20),
+	}
+
+	// Read and validate the header.
+	serializedHeader := make([]byte, snapshotHeaderSize)
+	if _, err := io.ReadFull(sr.r, serializedHeader); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, contextError(ErrInvalidSnapshot, "not a chain state "+
+				"snapshot")
+		}
+		return nil, err
+	}
+	header, err := deserializeSnapshotHeader(serializedHeader)
+	if err != nil {
+		return nil, err
+	}
+	if header.version != snapshotVersion {
+		return nil, invalidf("unsupported snapshot version %d",
+			header.version)
+	}
+	if header.net != params.Net {
+		return nil, invalidf("snapshot is for network %v instead of %v",
+			header.net, params.Net)
+	}
+	if header.dbVersion != currentDatabaseVersion ||
+		header.compVer != currentCompressionVersion ||
+		header.bidxVer != currentBlockIndexVersion ||
+		header.stxoVer != currentSpendJournalVersion ||
+		header.utxoVer != uint32(utxoKeySetVersions[utxoKeySetUtxoSet]) {
+
+		return nil, invalidf("unsupported snapshot database versions "+
+			"(chain: %d, compression: %d, block index: %d, spend journal: "+
+			"%d, utxo set: %d)", header.dbVersion, header.compVer,
+			header.bidxVer, header.stxoVer, header.utxoVer)
+	}
+	wantWindowStart := snapshotWindowStart(int64(header.height), params)
+	if int64(header.windowStart) != wantWindowStart {
+		return nil, invalidf("unexpected snapshot window start %d",
+			header.windowStart)
+	}
+
+	// Ensure the snapshot is one of the snapshots defined by the network
+	// parameters.
+	var assumeUtxo *chaincfg.AssumeUtxo
+	for i := range params.AssumeUtxo {
+		au := &params.AssumeUtxo[i]
+		if au.Height == int64(header.height) && au.BlockHash == header.hash {
+			assumeUtxo = au
+			break
+		}
+	}
+	if assumeUtxo == nil {
+		str := fmt.Sprintf("snapshot for block %s (height %d) is not a "+
+			"known snapshot", header.hash, header.height)
+		return nil, contextError(ErrUnknownSnapshot, str)
+	}
+
+	// Ensure the databases do not already contain any chain state.
+	err = db.View(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		if meta.Bucket(bcdbInfoBucketName) != nil ||
+			meta.Bucket(blockIndexBucketName) != nil {
+
+			return contextError(ErrSnapshotExistingChain, "the block "+
+				"database already contains chain state")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	utxoInfo, err := utxoBackend.FetchInfo()
+	if err != nil {
+		return nil, err
+	}
+	if utxoInfo != nil {
+		return nil, contextError(ErrSnapshotExistingChain, "the utxo "+
+			"database already contains chain state")
+	}
+
+	// Create the buckets and initialize the ticket database.
+	dbTx, err := db.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+	si := &snapshotImporter{
+		db:          db,
+		utxoBackend: utxoBackend,
+		params:      params,
+		header:      header,
+		dbTx:        dbTx,
+		hashes:      make([]chainhash.Hash, 0, header.height+1),
+	}
+	defer func() {
+		if si.dbTx != nil {
+			si.dbTx.Rollback()
+		}
+	}()
+	meta := dbTx.Metadata()
+	for _, bucketName := range [][]byte{blockIndexBucketName,
+		spendJournalBucketName, gcsFilterBucketName, treasuryBucketName,
+		treasuryTSpendBucketName, headerCmtsBucketName} {
+
+		if _, err := meta.CreateBucket(bucketName); err != nil {
+			return nil, err
+		}
+	}
+	_, err = stake.InitDatabaseState(dbTx, params, &params.GenesisHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store all of the records until the end of the snapshot.
+	log.Infof("Importing chain state snapshot for block %s (height %d)...",
+		header.hash, header.height)
+	importStart := time.Now()
+	for {
+		section, key, value, err := sr.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if section == snapshotSectionEnd {
+			if len(key) != 0 || len(value) != 0 {
+				return nil, invalidf("malformed end of snapshot")
+			}
+			break
+		}
+		if section > snapshotSectionBlockIndex &&
+			len(si.hashes) != int(header.height)+1 {
+
+			return nil, invalidf("snapshot contains %d block index entries "+
+				"instead of %d", len(si.hashes), header.height+1)
+		}
+		if err := si.putRecord(section, key, value); err != nil {
+			return nil, err
+		}
+		if err := si.maybeCommit(false); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := sr.r.ReadByte(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, invalidf("unexpected data after the end of the snapshot")
+	}
+	if len(si.hashes) == 0 || si.hashes[len(si.hashes)-1] != header.hash {
+		return nil, invalidf("snapshot block index does not end with block "+
+			"%s", header.hash)
+	}
+
+	// Ensure the snapshot is the one defined by the network parameters now
+	// that all of it has been read.
+	info := &SnapshotInfo{
+		Height:       int64(header.height),
+		BlockHash:    header.hash,
+		SnapshotHash: chainhash.Hash(hasher.Sum256()),
+	}
+	if info.SnapshotHash != assumeUtxo.SnapshotHash {
+		str := fmt.Sprintf("snapshot hash %s does not match the expected "+
+			"hash %s", info.SnapshotHash, assumeUtxo.SnapshotHash)
+		return nil, contextError(ErrSnapshotHashMismatch, str)
+	}
+
+	// Finish initializing the UTXO backend with the state as of the block the
+	// snapshot represents.
+	if err := si.flushUtxos(true); err != nil {
+		return nil, err
+	}
+	if err := utxoBackend.InitInfo(currentDatabaseVersion); err != nil {
+		return nil, err
+	}
+	err = utxoBackend.PutUtxos(nil, &UtxoSetState{
+		lastFlushHeight: header.height,
+		lastFlushHash:   header.hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Finish initializing the block database.  The database info is stored
+	// last since it marks the database as initialized.
+	meta = si.dbTx.Metadata()
+	if _, err := meta.CreateBucket(bcdbInfoBucketName); err != nil {
+		return nil, err
+	}
+	err = dbPutDatabaseInfo(si.dbTx, &databaseInfo{
+		version: currentDatabaseVersion,
+		compVer: currentCompressionVersion,
+		bidxVer: currentBlockIndexVersion,
+		created: time.Now(),
+		stxoVer: currentSpendJournalVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	serializedState := serializeBestChainState(bestChainState{
+		hash:         header.hash,
+		height:       header.height,
+		totalTxns:    header.totalTxns,
+		totalSubsidy: header.totalSubsidy,
+		workSum:      si.workSum,
+	})
+	if err := meta.Put(chainStateKeyName, serializedState); err != nil {
+		return nil, err
+	}
+	if err := dbPutDeploymentVer(si.dbTx, currentDeploymentVersion(params)); err != nil {
+		return nil, err
+	}
+	if err := dbPutSnapshotInfo(si.dbTx, info, false); err != nil {
+		return nil, err
+	}
+	err = si.dbTx.Commit()
+	si.dbTx = nil
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Imported chain state snapshot in %v",
+		time.Since(importStart).Round(time.Second))
+	return info, nil
+}
+
+// -----------------------------------------------------------------------------
+// The snapshot info contains information about the chain state snapshot that
+// the database was created from along with whether or not the history prior to
+// it has been validated.
+//
+// The serialized format is:
+//
+//   <block height><block hash><snapshot hash><validated>
+//
+//   Field            Type             Size
+//   block height     uint32           4 bytes
+//   block hash       chainhash.Hash   chainhash.HashSize
+//   snapshot hash    chainhash.Hash   chainhash.HashSize
+//   validated        bool             1 byte
+// -----------------------------------------------------------------------------
+
+// dbPutSnapshotInfo uses an existing database transaction to store the
+// provided snapshot info.
+func dbPutSnapshotInfo(dbTx database.Tx, info *SnapshotInfo, validated bool) error {
+	serialized := make([]byte, 4+2*chainhash.HashSize+1)
+	byteOrder.PutUint32(serialized, uint32(info.Height))
+	copy(serialized[4:], info.BlockHash[:])
+	copy(serialized[4+chainhash.HashSize:], info.SnapshotHash[:])
+	if validated {
+		serialized[len(serialized)-1] = 1
+	}
+	return dbTx.Metadata().Put(snapshotInfoKeyName, serialized)
+}
+
+// dbFetchSnapshotInfo uses an existing database transaction to fetch the
+// snapshot info.  It returns nil when the database was not created from a
+// snapshot.
+func dbFetchSnapshotInfo(dbTx database.Tx) (*SnapshotInfo, bool, error) {
+	serialized := dbTx.Metadata().Get(snapshotInfoKeyName)
+	if serialized == nil {
+		return nil, false, nil
+	}
+	if len(serialized) != 4+2*chainhash.HashSize+1 {
+		str := fmt.Sprintf("corrupt snapshot info size %d", len(serialized))
+		return nil, false, makeDbErr(database.ErrCorruption, str)
+	}
+
+	var info SnapshotInfo
+	info.Height = int64(byteOrder.Uint32(serialized))
+	copy(info.BlockHash[:], serialized[4:])
+	copy(info.SnapshotHash[:], serialized[4+chainhash.HashSize:])
+	return &info, serialized[len(serialized)-1] != 0, nil
+}
+
+// FetchImportedSnapshot returns the information about the chain state snapshot
+// that the provided block database was created from along with whether or not
+// the history prior to it has been validated.  It returns nil when the database
+// was not created from a snapshot.
+func FetchImportedSnapshot(db database.DB) (*SnapshotInfo, bool, error) {
+	var info *SnapshotInfo
+	var validated bool
+	err := db.View(func(dbTx database.Tx) error {
+		var err error
+		info, validated, err = dbFetchSnapshotInfo(dbTx)
+		return err
+	})
+	return info, validated, err
+}
+
+// MarkSnapshotValidated records that the history prior to the chain state
+// snapshot the chain was created from has been validated.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) MarkSnapshotValidated() error {
+	return b.db.Update(func(dbTx database.Tx) error {
+		info, _, err := dbFetchSnapshotInfo(dbTx)
+		if err != nil {
+			return err
+		}
+		if info == nil {
+			return AssertError("MarkSnapshotValidated called on a chain that " +
+				"was not created from a snapshot")
+		}
+		return dbPutSnapshotInfo(dbTx, info, true)
+	})
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/stdscript"
+)
+
+// createSnapshotTestDatabases creates a new empty block database and UTXO
+// backend for use when importing snapshots in the tests.
+func createSnapshotTestDatabases(t *testing.T, params *chaincfg.Params) (database.DB, UtxoBackend) {
+	t.Helper()
+
+	db, err := createTestDatabase(t, testDbType, params.Net)
+	if err != nil {
+		t.Fatalf("failed to create block database: %v", err)
+	}
+	utxoDb, teardownUtxoDb, err := createTestUtxoDatabase(t)
+	if err != nil {
+		t.Fatalf("failed to create utxo database: %v", err)
+	}
+	t.Cleanup(teardownUtxoDb)
+	return db, NewLevelDbUtxoBackend(utxoDb)
+}
+
+// TestUtxoSnapshot ensures chain state snapshots are deterministic, that they
+// can be imported to create a new chain instance with the same state that is
+// able to continue processing blocks, and that snapshots that are unknown or do
+// not match the expected hash are rejected.
+func TestUtxoSnapshot(t *testing.T) {
+	t.Parallel()
+
+	// Create a chain that is long enough for the snapshot to exclude the
+	// oldest block data.  The chain is advanced in multiple steps since each
+	// one only purchases enough tickets to fill the ticket pool once.
+	params := chaincfg.RegNetParams()
+	g := newChaingenHarness(t, params)
+	g.AdvanceToStakeValidationHeight()
+	tipHeight := params.StakeValidationHeight + minPruneRetainBlocks(params)
+	for height := params.StakeValidationHeight; height < tipHeight; {
+		height += int64(params.TicketPoolSize)
+		if height > tipHeight {
+			height = tipHeight
+		}
+		g.AdvanceToHeight(uint32(height), uint32(params.TicketsPerBlock))
+	}
+
+	// Ensure dumping the same chain state results in identical snapshots.
+	ctx := context.Background()
+	var snapshot, snapshot2 bytes.Buffer
+	info, err := g.chain.DumpUtxoSnapshot(ctx, &snapshot)
+	if err != nil {
+		t.Fatalf("failed to dump snapshot: %v", err)
+	}
+	if _, err := g.chain.DumpUtxoSnapshot(ctx, &snapshot2); err != nil {
+		t.Fatalf("failed to dump snapshot: %v", err)
+	}
+	if !bytes.Equal(snapshot.Bytes(), snapshot2.Bytes()) {
+		t.Fatal("snapshots of the same chain state differ")
+	}
+	tip := g.chain.BestSnapshot()
+	if info.Height != tip.Height || info.BlockHash != tip.Hash {
+		t.Fatalf("unexpected snapshot block: got %s (height %d), want %s "+
+			"(height %d)", info.BlockHash, info.Height, tip.Hash, tip.Height)
+	}
+
+	// Ensure importing a snapshot that is not defined by the network params
+	// is rejected.
+	db, utxoBackend := createSnapshotTestDatabases(t, params)
+	_, err = ImportUtxoSnapshot(ctx, bytes.NewReader(snapshot.Bytes()), db,
+		utxoBackend, params)
+	if !errors.Is(err, ErrUnknownSnapshot) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrUnknownSnapshot)
+	}
+
+	// Ensure importing a snapshot with a hash that differs from the one
+	// defined by the network params is rejected.
+	badParams := *params
+	badParams.AssumeUtxo = []chaincfg.AssumeUtxo{{
+		Height:       info.Height,
+		BlockHash:    info.BlockHash,
+		SnapshotHash: info.BlockHash,
+	}}
+	db, utxoBackend = createSnapshotTestDatabases(t, params)
+	_, err = ImportUtxoSnapshot(ctx, bytes.NewReader(snapshot.Bytes()), db,
+		utxoBackend, &badParams)
+	if !errors.Is(err, ErrSnapshotHashMismatch) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrSnapshotHashMismatch)
+	}
+
+	// Ensure importing a truncated snapshot is rejected.
+	snapParams := *params
+	snapParams.AssumeUtxo = []chaincfg.AssumeUtxo{{
+		Height:       info.Height,
+		BlockHash:    info.BlockHash,
+		SnapshotHash: info.SnapshotHash,
+	}}
+	truncated := snapshot.Bytes()[:snapshot.Len()-1]
+	db, utxoBackend = createSnapshotTestDatabases(t, params)
+	_, err = ImportUtxoSnapshot(ctx, bytes.NewReader(truncated), db,
+		utxoBackend, &snapParams)
+	if !errors.Is(err, ErrInvalidSnapshot) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrInvalidSnapshot)
+	}
+
+	// Import the snapshot and ensure importing it again into the same
+	// databases is rejected.
+	db, utxoBackend = createSnapshotTestDatabases(t, params)
+	gotInfo, err := ImportUtxoSnapshot(ctx, bytes.NewReader(snapshot.Bytes()),
+		db, utxoBackend, &snapParams)
+	if err != nil {
+		t.Fatalf("failed to import snapshot: %v", err)
+	}
+	if *gotInfo != *info {
+		t.Fatalf("unexpected imported snapshot info: got %+v, want %+v",
+			gotInfo, info)
+	}
+	_, err = ImportUtxoSnapshot(ctx, bytes.NewReader(snapshot.Bytes()), db,
+		utxoBackend, &snapParams)
+	if !errors.Is(err, ErrSnapshotExistingChain) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrSnapshotExistingChain)
+	}
+
+	// Create a chain instance from the imported databases.
+	sigCache, err := txscript.NewSigCache(1000)
+	if err != nil {
+		t.Fatalf("failed to create sig cache: %v", err)
+	}
+	chain, err := New(ctx, &Config{
+		DB:          db,
+		UtxoBackend: utxoBackend,
+		ChainParams: &snapParams,
+		TimeSource:  NewMedianTime(),
+		SigCache:    sigCache,
+		UtxoCache: NewUtxoCache(&UtxoCacheConfig{
+			Backend:      utxoBackend,
+			FlushBlockDB: func() error { return nil },
+			MaxSize:      100 * 1024 * 1024, // 100 MiB
+		}),
+	})
+	if err != nil {
+		t.Fatalf("failed to create chain from snapshot: %v", err)
+	}
+
+	// Ensure the chain state and UTXO set match the original chain and that
+	// the block data prior to the snapshot window is treated as pruned.
+	gotTip := chain.BestSnapshot()
+	if gotTip.Hash != tip.Hash || gotTip.Height != tip.Height ||
+		gotTip.TotalTxns != tip.TotalTxns ||
+		gotTip.TotalSubsidy != tip.TotalSubsidy ||
+		gotTip.NextPoolSize != tip.NextPoolSize {
+
+		t.Fatalf("unexpected chain state: got %+v, want %+v", gotTip, tip)
+	}
+	wantStats, err := g.chain.FetchUtxoStats()
+	if err != nil {
+		t.Fatalf("failed to fetch utxo stats: %v", err)
+	}
+	gotStats, err := chain.FetchUtxoStats()
+	if err != nil {
+		t.Fatalf("failed to fetch utxo stats: %v", err)
+	}
+	if *gotStats != *wantStats {
+		t.Fatalf("unexpected utxo stats: got %+v, want %+v", gotStats,
+			wantStats)
+	}
+	wantPruneHeight := snapshotWindowStart(tip.Height, params)
+	if !chain.IsPruned() || chain.PruneHeight() != wantPruneHeight {
+		t.Fatalf("unexpected prune state: got %v/%d, want true/%d",
+			chain.IsPruned(), chain.PruneHeight(), wantPruneHeight)
+	}
+
+	// Ensure a snapshot of the imported chain is identical to the original.
+	var snapshot3 bytes.Buffer
+	info3, err := chain.DumpUtxoSnapshot(ctx, &snapshot3)
+	if err != nil {
+		t.Fatalf("failed to dump snapshot: %v", err)
+	}
+	if *info3 != *info {
+		t.Fatalf("unexpected snapshot info of imported chain: got %+v, want "+
+			"%+v", info3, info)
+	}
+
+	// Ensure marking the history as validated is persisted.
+	gotInfo, validated, err := FetchImportedSnapshot(db)
+	if err != nil || *gotInfo != *info || validated {
+		t.Fatalf("unexpected imported snapshot: got %+v/%v/%v, want %+v/"+
+			"false/nil", gotInfo, validated, err, info)
+	}
+	if err := chain.MarkSnapshotValidated(); err != nil {
+		t.Fatalf("failed to mark snapshot validated: %v", err)
+	}
+	_, validated, err = FetchImportedSnapshot(db)
+	if err != nil || !validated {
+		t.Fatalf("unexpected validated state: got %v/%v, want true/nil",
+			validated, err)
+	}
+
+	// Ensure the imported chain is able to process new blocks.
+	imported := &chaingenHarness{Generator: g.Generator, t: t, chain: chain}
+	outs := g.OldestCoinbaseOuts()
+	g.NextBlock("bsnap0", &outs[0], outs[1:])
+	g.SaveTipCoinbaseOuts()
+	imported.AcceptTipBlock()
+	g.AcceptTipBlock()
+
+	// Ensure the exists address index can be enabled on the imported chain
+	// and caught up even though the block data prior to the snapshot window
+	// is not available and that it indexes the blocks after the snapshot.
+	idxCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	subber := indexers.NewIndexSubscriber(idxCtx)
+	go subber.Run(idxCtx)
+	queryer := &ChainQueryerAdapter{BlockChain: chain}
+	idx, err := indexers.NewExistsAddrIndex(subber, db, queryer)
+	if err != nil {
+		t.Fatalf("failed to create exists address index: %v", err)
+	}
+	if err := subber.CatchUp(idxCtx, db, queryer); err != nil {
+		t.Fatalf("failed to catch up exists address index: %v", err)
+	}
+	idxHeight, idxHash, err := idx.Tip()
+	if err != nil {
+		t.Fatalf("failed to fetch exists address index tip: %v", err)
+	}
+	best := chain.BestSnapshot()
+	if idxHeight != best.Height || *idxHash != best.Hash {
+		t.Fatalf("unexpected exists address index tip: got %s (height %d), "+
+			"want %s (height %d)", idxHash, idxHeight, best.Hash, best.Height)
+	}
+	coinbaseOut := g.Tip().Transactions[0].TxOut[2]
+	_, addrs := stdscript.ExtractAddrs(coinbaseOut.Version,
+		coinbaseOut.PkScript, params)
+	if len(addrs) == 0 {
+		t.Fatal("unable to extract coinbase address")
+	}
+	exists, err := idx.ExistsAddress(addrs[0])
+	if err != nil || !exists {
+		t.Fatalf("coinbase address %s is not indexed: %v", addrs[0], err)
+	}
+}
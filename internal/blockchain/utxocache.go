@@ -1,4 +1,4 @@
-// Copyright (c) 2022 The Decred developers
+// Copyright (c) 2022-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -520,6 +520,44 @@ func (c *UtxoCache) FetchEntries(filteredSet ViewFilteredSet, view *UtxoViewpoin
 	return nil
 }
 
+// UtxoCacheStats houses statistics about the current state of the utxo cache.
+type UtxoCacheStats struct {
+	// Entries is the number of utxo entries in the cache.
+	Entries uint64
+
+	// Size is the total size of the cache, in bytes.
+	Size uint64
+
+	// MaxSize is the maximum allowed size of the cache, in bytes.
+	MaxSize uint64
+
+	// Hits and Misses are the total number of cache lookups that resulted in
+	// a cache hit and miss, respectively.
+	Hits   uint64
+	Misses uint64
+
+	// HitRatio is the percentage of cache lookups that resulted in a cache
+	// hit.
+	HitRatio float64
+}
+
+// Stats returns statistics about the current state of the cache.
+//
+// This function is safe for concurrent access.
+func (c *UtxoCache) Stats() UtxoCacheStats {
+	c.cacheLock.Lock()
+	stats := UtxoCacheStats{
+		Entries:  uint64(len(c.entries)),
+		Size:     c.totalSize(),
+		MaxSize:  c.maxSize,
+		Hits:     c.hits,
+		Misses:   c.misses,
+		HitRatio: c.hitRatio(),
+	}
+	c.cacheLock.Unlock()
+	return stats
+}
+
 // FetchBackendState returns the current state of the UTXO set in the backend.
 func (c *UtxoCache) FetchBackendState() (*UtxoSetState, error) {
 	return c.backend.FetchState()
@@ -1,4 +1,4 @@
-// Copyright (c) 2022 The Decred developers
+// Copyright (c) 2022-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -353,6 +353,32 @@ func TestHitRatio(t *testing.T) {
 	}
 }
 
+// TestStats validates that the statistics returned for the utxo cache match
+// its current state.
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	utxoCache := createTestUtxoCache(t, map[wire.OutPoint]*UtxoEntry{
+		outpoint1200(): entry1200(),
+	})
+	utxoCache.maxSize = 1000
+	utxoCache.hits = 197
+	utxoCache.misses = 3
+
+	got := utxoCache.Stats()
+	want := UtxoCacheStats{
+		Entries:  1,
+		Size:     utxoCache.totalSize(),
+		MaxSize:  1000,
+		Hits:     197,
+		Misses:   3,
+		HitRatio: 98.5,
+	}
+	if got != want {
+		t.Fatalf("unexpected result -- got %+v, want %+v", got, want)
+	}
+}
+
 // TestAddEntry validates that entries are added to the cache properly under a
 // variety of conditions.
 func TestAddEntry(t *testing.T) {
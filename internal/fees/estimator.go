@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2023 The Decred developers
+// Copyright (c) 2018-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -580,9 +580,12 @@ func (stats *Estimator) updateMovingAverages(newHeight int64) {
 // newMemPoolTx records a new memPool transaction into the stats. A brand new
 // mempool transaction has a minimum confirmation range of 1, so it is inserted
 // into the very first confirmation range bucket of the appropriate fee rate
-// bucket.
-func (stats *Estimator) newMemPoolTx(bucketIdx int32, fees feeRate) {
-	conf := &stats.memPool[bucketIdx].confirmed[0]
+// bucket.  Transactions that have already been in the mempool for some number
+// of blocks, such as those restored from a previous run, are inserted into the
+// confirmation range for that number of blocks instead.
+func (stats *Estimator) newMemPoolTx(bucketIdx, blocksInMemPool int32, fees feeRate) {
+	confirmIdx := stats.confirmRange(blocksInMemPool + 1)
+	conf := &stats.memPool[bucketIdx].confirmed[confirmIdx]
 	conf.feeSum += float64(fees)
 	conf.txCount++
 }
@@ -632,6 +635,77 @@ func (stats *Estimator) removeFromMemPool(blocksInMemPool int32, rate feeRate) {
 	}
 }
 
+// BucketRangeStats houses the decay-adjusted statistics of a contiguous range
+// of fee rate buckets for a given confirmation target.
+type BucketRangeStats struct {
+	// StartRange and EndRange are the bounds of the fee rates covered by the
+	// range in atoms/kB.  EndRange is +Inf when the range includes the highest
+	// fee rate bucket.
+	StartRange float64
+	EndRange   float64
+
+	// WithinTarget is the number of transactions in the range that were
+	// mined within the confirmation target.
+	WithinTarget float64
+
+	// TotalConfirmed is the total number of transactions in the range that
+	// were mined regardless of how long it took.
+	TotalConfirmed float64
+
+	// InMempool is the number of transactions in the range that have been in
+	// the mempool for the confirmation range of the target without being
+	// mined.
+	InMempool float64
+}
+
+// SuccessRatio returns the ratio of transactions in the range that were mined
+// within the confirmation target to the total number of transactions in the
+// range, which is what is compared against the required success percentage
+// when estimating fees.
+func (r *BucketRangeStats) SuccessRatio() float64 {
+	total := r.TotalConfirmed + r.InMempool
+	if total == 0 {
+		return 0
+	}
+	return r.WithinTarget / total
+}
+
+// RawFeeEstimate houses the details of a fee estimate for a given confirmation
+// target.
+type RawFeeEstimate struct {
+	// TargetConfs is the confirmation target and SuccessPct is the minimum
+	// ratio of transactions that must have been mined within it.
+	TargetConfs int32
+	SuccessPct  float64
+
+	// Decay is the exponential decay applied to the statistics for every new
+	// block.
+	Decay float64
+
+	// FeeRate is the estimated fee rate in atoms/kB.  It is zero when no
+	// estimate could be made.
+	FeeRate dcrutil.Amount
+
+	// Pass is the range of fee rate buckets with the lowest fee rates that
+	// met the required success percentage.  It is nil when no range met it.
+	Pass *BucketRangeStats
+
+	// Fail is the range of fee rate buckets immediately below the passing
+	// range that did not meet the required success percentage.  It is nil
+	// when no range failed.
+	Fail *BucketRangeStats
+}
+
+// bucketRange returns the bounds of the fee rates covered by the provided
+// range of bucket indices.
+func (stats *Estimator) bucketRange(startIdx, endIdx int) (float64, float64) {
+	var startRange float64
+	if startIdx > 0 {
+		startRange = float64(stats.bucketFeeBounds[startIdx-1])
+	}
+	return startRange, float64(stats.bucketFeeBounds[endIdx])
+}
+
 // estimateMedianFee estimates the median fee rate for the current recorded
 // statistics such that at least successPct transactions have been mined on all
 // tracked fee rate buckets with fee >= to the median.
@@ -643,7 +717,10 @@ func (stats *Estimator) removeFromMemPool(blocksInMemPool int32, rate feeRate) {
 // or there are not enough recorded statistics to derive a successful estimate
 // (eg: confirmation tracking has only started or there was a period of very few
 // transactions). In those situations, the appropriate error is returned.
-func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64) (feeRate, error) {
+//
+// The statistics of the ranges of buckets that passed and failed the success
+// percentage are stored in the provided raw estimate when it is not nil.
+func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64, raw *RawFeeEstimate) (feeRate, error) {
 	if targetConfs <= 0 {
 		return 0, errors.New("target confirmation range cannot be <= 0")
 	}
@@ -661,33 +738,55 @@ func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64)
 	startIdx := len(stats.buckets) - 1
 	confirmRangeIdx := stats.confirmRange(targetConfs)
 
-	var totalTxs, confirmedTxs float64
+	var totalTxs, confirmedTxs, minedTxs, memPoolTxs float64
 	bestBucketsStt := startIdx
 	bestBucketsEnd := startIdx
 	curBucketsEnd := startIdx
 
+	// rangeStats returns the statistics of the current range of buckets that
+	// starts at the provided index.
+	rangeStats := func(b int) *BucketRangeStats {
+		startRange, endRange := stats.bucketRange(b, curBucketsEnd)
+		return &BucketRangeStats{
+			StartRange:     startRange,
+			EndRange:       endRange,
+			WithinTarget:   confirmedTxs,
+			TotalConfirmed: minedTxs,
+			InMempool:      memPoolTxs,
+		}
+	}
+
 	for b := startIdx; b >= 0; b-- {
-		totalTxs += stats.buckets[b].confirmCount
+		minedTxs += stats.buckets[b].confirmCount
 		confirmedTxs += stats.buckets[b].confirmed[confirmRangeIdx].txCount
 
 		// Add the mempool (unconfirmed) transactions to the total tx count
 		// since a very large mempool for the given bucket might mean that
 		// miners are reluctant to include these in their mined blocks.
-		totalTxs += stats.memPool[b].confirmed[confirmRangeIdx].txCount
+		memPoolTxs += stats.memPool[b].confirmed[confirmRangeIdx].txCount
+		totalTxs = minedTxs + memPoolTxs
 
 		if totalTxs > minTxCount {
 			if confirmedTxs/totalTxs < successPct {
+				if raw != nil {
+					raw.Fail = rangeStats(b)
+				}
 				if curBucketsEnd == startIdx {
 					return 0, ErrNoSuccessPctBucketFound
 				}
 				break
 			}
 
+			if raw != nil {
+				raw.Pass = rangeStats(b)
+			}
 			bestBucketsStt = b
 			bestBucketsEnd = curBucketsEnd
 			curBucketsEnd = b - 1
 			totalTxs = 0
 			confirmedTxs = 0
+			minedTxs = 0
+			memPoolTxs = 0
 		}
 	}
 
@@ -719,7 +818,7 @@ func (stats *Estimator) estimateMedianFee(targetConfs int32, successPct float64)
 // until concurrent modifications to the internal database state are complete.
 func (stats *Estimator) EstimateFee(targetConfs int32) (dcrutil.Amount, error) {
 	stats.lock.RLock()
-	rate, err := stats.estimateMedianFee(targetConfs, 0.95)
+	rate, err := stats.estimateMedianFee(targetConfs, 0.95, nil)
 	stats.lock.RUnlock()
 
 	if err != nil {
@@ -736,6 +835,127 @@ func (stats *Estimator) EstimateFee(targetConfs int32) (dcrutil.Amount, error) {
 	return dcrutil.Amount(rate), nil
 }
 
+// EstimateRawFee is similar to EstimateFee except it calculates the suggested
+// fee using the provided success percentage and returns the details of the
+// estimate, including the statistics of the ranges of fee rate buckets that
+// passed and failed the success percentage.
+//
+// The details are returned along with the error when the estimate could not be
+// made due to a lack of statistics that meet the success percentage so that
+// callers are able to determine why.  Otherwise, a nil estimate is returned
+// with any errors.
+//
+// This function is safe to be called from multiple goroutines but might block
+// until concurrent modifications to the internal database state are complete.
+func (stats *Estimator) EstimateRawFee(targetConfs int32, successPct float64) (*RawFeeEstimate, error) {
+	if successPct <= 0 || successPct > 1 {
+		return nil, fmt.Errorf("success percentage %v is not in the range "+
+			"(0, 1]", successPct)
+	}
+
+	raw := &RawFeeEstimate{
+		TargetConfs: targetConfs,
+		SuccessPct:  successPct,
+		Decay:       stats.decay,
+	}
+	stats.lock.RLock()
+	rate, err := stats.estimateMedianFee(targetConfs, successPct, raw)
+	stats.lock.RUnlock()
+	if err != nil {
+		if errors.Is(err, ErrNoSuccessPctBucketFound) ||
+			errors.Is(err, ErrNotEnoughTxsForEstimate) {
+
+			return raw, err
+		}
+		return nil, err
+	}
+
+	rate = feeRate(math.Round(float64(rate)))
+	if rate < stats.bucketFeeBounds[0] {
+		rate = stats.bucketFeeBounds[0]
+	}
+	raw.FeeRate = dcrutil.Amount(rate)
+	return raw, nil
+}
+
+// BucketStats houses the decay-adjusted statistics of a single fee rate
+// bucket.
+type BucketStats struct {
+	// StartRange and EndRange are the bounds of the fee rates covered by the
+	// bucket in atoms/kB.  EndRange is +Inf for the highest fee rate bucket.
+	StartRange float64
+	EndRange   float64
+
+	// TotalConfirmed is the total number of transactions in the bucket that
+	// were mined and FeeSum is the sum of their fee rates.
+	TotalConfirmed float64
+	FeeSum         float64
+
+	// Confirmed is the number of transactions in the bucket that were mined
+	// within each confirmation range.  The counts are cumulative, so the
+	// entry at index i is the number of transactions that were mined within
+	// i+1 blocks.  The final entry also includes all transactions that took
+	// longer to be mined.
+	Confirmed []float64
+
+	// InMempool is the number of transactions in the bucket that are in the
+	// mempool by the confirmation range they have been in it for.
+	InMempool []float64
+}
+
+// EstimatorStats houses the internal state of the estimator.
+type EstimatorStats struct {
+	// BestHeight is the height of the most recently processed block.  It is
+	// -1 when the estimator is not enabled yet.
+	BestHeight int64
+
+	// MaxConfirms is the number of tracked confirmation ranges and Decay is
+	// the exponential decay applied to the statistics for every new block.
+	MaxConfirms int32
+	Decay       float64
+
+	// Buckets are the statistics of the fee rate buckets ordered from the
+	// lowest to the highest fee rate.
+	Buckets []BucketStats
+}
+
+// Stats returns a copy of the internal estimator state.  It is the structured
+// equivalent of DumpBuckets.
+//
+// This function is safe to be called from multiple goroutines.
+func (stats *Estimator) Stats() *EstimatorStats {
+	stats.lock.RLock()
+	defer stats.lock.RUnlock()
+
+	res := &EstimatorStats{
+		BestHeight:  stats.bestHeight,
+		MaxConfirms: stats.maxConfirms,
+		Decay:       stats.decay,
+		Buckets:     make([]BucketStats, len(stats.buckets)),
+	}
+	for i := range stats.buckets {
+		bucket := &stats.buckets[i]
+		memPool := &stats.memPool[i]
+		startRange, endRange := stats.bucketRange(i, i)
+		bucketStats := BucketStats{
+			StartRange:     startRange,
+			EndRange:       endRange,
+			TotalConfirmed: bucket.confirmCount,
+			FeeSum:         bucket.feeSum,
+			Confirmed:      make([]float64, len(bucket.confirmed)),
+			InMempool:      make([]float64, len(memPool.confirmed)),
+		}
+		for c := range bucket.confirmed {
+			bucketStats.Confirmed[c] = bucket.confirmed[c].txCount
+		}
+		for c := range memPool.confirmed {
+			bucketStats.InMempool[c] = memPool.confirmed[c].txCount
+		}
+		res.Buckets[i] = bucketStats
+	}
+	return res
+}
+
 // Enable establishes the current best height of the blockchain after
 // initializing the chain. All new mempool transactions will be added at this
 // block height.
@@ -756,18 +976,26 @@ func (stats *Estimator) IsEnabled() bool {
 }
 
 // AddMemPoolTransaction adds a mempool transaction to the estimator in order to
-// account for it in the estimations. It assumes that this transaction is
-// entering the mempool at the currently recorded best chain hash, using the
-// total fee amount (in atoms) and with the provided size (in bytes).
+// account for it in the estimations, using the total fee amount (in atoms) and
+// with the provided size (in bytes).
+//
+// The added height is the height of the best chain when the transaction first
+// entered the mempool.  This allows transactions that are restored to the
+// mempool, such as after a restart, to retain their original position for the
+// purposes of confirmation tracking.  Heights after the currently recorded best
+// chain height are treated as the best chain height.
 //
 // This is safe to be called from multiple goroutines.
-func (stats *Estimator) AddMemPoolTransaction(txHash *chainhash.Hash, fee, size int64, txType stake.TxType) {
+func (stats *Estimator) AddMemPoolTransaction(txHash *chainhash.Hash, fee, size int64, txType stake.TxType, addedHeight int64) {
 	stats.lock.Lock()
 	defer stats.lock.Unlock()
 
 	if stats.bestHeight < 0 {
 		return
 	}
+	if addedHeight > stats.bestHeight || addedHeight < 0 {
+		addedHeight = stats.bestHeight
+	}
 
 	if _, exists := stats.memPoolTxs[*txHash]; exists {
 		// we should not double count transactions
@@ -804,12 +1032,13 @@ func (stats *Estimator) AddMemPoolTransaction(txHash *chainhash.Hash, fee, size
 	log.Debugf("Adding mempool tx %s using fee rate %.8f", txHash, rate/1e8)
 
 	tx := memPoolTxDesc{
-		addedHeight: stats.bestHeight,
+		addedHeight: addedHeight,
 		bucketIndex: stats.lowerBucket(rate),
 		fees:        rate,
 	}
 	stats.memPoolTxs[*txHash] = tx
-	stats.newMemPoolTx(tx.bucketIndex, rate)
+	stats.newMemPoolTx(tx.bucketIndex, int32(stats.bestHeight-addedHeight),
+		rate)
 }
 
 // RemoveMemPoolTransaction removes a mempool transaction from statistics
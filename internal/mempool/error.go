@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2023 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -95,6 +95,11 @@ const (
 
 	// ErrTSpendInvalidExpiry indicates a treasury spend expiry is invalid.
 	ErrTSpendInvalidExpiry = ErrorKind("ErrTSpendInvalidExpiry")
+
+	// ErrMempoolFull indicates a transaction was not accepted because the
+	// mempool is at its maximum allowed size and the transaction does not pay
+	// a high enough fee to displace any existing transactions.
+	ErrMempoolFull = ErrorKind("ErrMempoolFull")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
@@ -37,6 +37,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrTooManyTSpends, "ErrTooManyTSpends"},
 		{ErrTSpendMinedOnAncestor, "ErrTSpendMinedOnAncestor"},
 		{ErrTSpendInvalidExpiry, "ErrTSpendInvalidExpiry"},
+		{ErrMempoolFull, "ErrMempoolFull"},
 	}
 
 	t.Logf("Running %d tests", len(tests))
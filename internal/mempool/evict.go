@@ -0,0 +1,235 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"container/heap"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/internal/mining"
+)
+
+// evictionEntry houses a transaction in the main pool along with the total
+// fees and serialized size of its package.  A package consists of the
+// transaction along with all transactions in the pool that depend on it, since
+// they can not remain in the pool without it and therefore must be evicted
+// together.
+type evictionEntry struct {
+	txDesc *TxDesc
+	fees   int64
+	size   int64
+
+	// index is the index of the entry in the eviction heap or -1 when it is
+	// not in the heap because the transaction is not evictable.
+	index int
+}
+
+// feeRate returns the fee rate of the package in atoms/kB.
+func (e *evictionEntry) feeRate() float64 {
+	if e.size <= 0 {
+		return 0
+	}
+	return float64(e.fees) * 1000 / float64(e.size)
+}
+
+// evictionHeap implements a min-heap of eviction entries ordered by their
+// package fee rates so the package with the lowest fee rate is always
+// available without having to scan the entire pool.
+type evictionHeap []*evictionEntry
+
+// Len returns the number of entries in the heap.  It is part of the
+// heap.Interface implementation.
+func (h evictionHeap) Len() int {
+	return len(h)
+}
+
+// Less returns whether the entry with index i has a lower package fee rate
+// than the entry with index j.  It is part of the heap.Interface
+// implementation.
+func (h evictionHeap) Less(i, j int) bool {
+	return h[i].feeRate() < h[j].feeRate()
+}
+
+// Swap swaps the entries at the passed indices in the heap.  It is part of the
+// heap.Interface implementation.
+func (h evictionHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push pushes the passed entry onto the heap.  It is part of the
+// heap.Interface implementation.
+func (h *evictionHeap) Push(x interface{}) {
+	entry := x.(*evictionEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+// Pop removes the last entry from the heap and returns it.  It is part of the
+// heap.Interface implementation.
+func (h *evictionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*h = old[0 : n-1]
+	return entry
+}
+
+// adjustEvictionPackage adds the provided fees and size to the package of the
+// provided transaction and updates its position in the eviction heap
+// accordingly.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) adjustEvictionPackage(txHash *chainhash.Hash, fees, size int64) {
+	entry, ok := mp.evictionEntries[*txHash]
+	if !ok {
+		return
+	}
+	entry.fees += fees
+	entry.size += size
+	if entry.index >= 0 {
+		heap.Fix(&mp.evictionHeap, entry.index)
+	}
+}
+
+// recalcEvictionPackage recalculates the package of the provided transaction
+// from its descendants in the mining view and updates its position in the
+// eviction heap accordingly.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) recalcEvictionPackage(txHash *chainhash.Hash) {
+	entry, ok := mp.evictionEntries[*txHash]
+	if !ok {
+		return
+	}
+	entry.fees, entry.size = entry.txDesc.Fee, entry.txDesc.TxSize
+	mp.miningView.ForEachDescendant(txHash, func(desc *mining.TxDesc) {
+		entry.fees += desc.Fee
+		entry.size += desc.TxSize
+	})
+	if entry.index >= 0 {
+		heap.Fix(&mp.evictionHeap, entry.index)
+	}
+}
+
+// trackEvictionPackage starts tracking the package of the provided transaction
+// and adds it to the packages of all of its ancestors.  The transaction MUST
+// already have been added to the mining view.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) trackEvictionPackage(txDesc *TxDesc) {
+	txHash := txDesc.Tx.Hash()
+	entry := &evictionEntry{
+		txDesc: txDesc,
+		fees:   txDesc.Fee,
+		size:   txDesc.TxSize,
+		index:  -1,
+	}
+	var hasDescendants bool
+	mp.miningView.ForEachDescendant(txHash, func(desc *mining.TxDesc) {
+		hasDescendants = true
+		entry.fees += desc.Fee
+		entry.size += desc.TxSize
+	})
+	mp.evictionEntries[*txHash] = entry
+	if isEvictableTxType(txDesc.Type) {
+		heap.Push(&mp.evictionHeap, entry)
+	}
+
+	// The package of every ancestor grows by exactly the new transaction
+	// unless it already has descendants in the pool, such as when the
+	// transactions of a disconnected block are added back, since they might
+	// already be part of the package of an ancestor.
+	mp.miningView.ForEachAncestor(txHash, func(ancestor *mining.TxDesc) {
+		if hasDescendants {
+			mp.recalcEvictionPackage(ancestor.Tx.Hash())
+			return
+		}
+		mp.adjustEvictionPackage(ancestor.Tx.Hash(), txDesc.Fee,
+			txDesc.TxSize)
+	})
+}
+
+// untrackEvictionPackage stops tracking the package of the provided
+// transaction and removes it from the packages of all of its ancestors.  It
+// MUST be called before the transaction is removed from the mining view.
+//
+// When the transaction still has descendants in the pool, the packages of its
+// ancestors can only be determined once it has been removed from the mining
+// view, so the ancestors are returned instead and the caller MUST recalculate
+// their packages via recalcEvictionPackage after removing it.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) untrackEvictionPackage(txDesc *TxDesc) []*chainhash.Hash {
+	txHash := txDesc.Tx.Hash()
+	entry, ok := mp.evictionEntries[*txHash]
+	if !ok {
+		return nil
+	}
+	delete(mp.evictionEntries, *txHash)
+	if entry.index >= 0 {
+		heap.Remove(&mp.evictionHeap, entry.index)
+	}
+
+	hasDescendants := entry.size != txDesc.TxSize
+	var staleAncestors []*chainhash.Hash
+	mp.miningView.ForEachAncestor(txHash, func(ancestor *mining.TxDesc) {
+		if hasDescendants {
+			staleAncestors = append(staleAncestors, ancestor.Tx.Hash())
+			return
+		}
+		mp.adjustEvictionPackage(ancestor.Tx.Hash(), -txDesc.Fee,
+			-txDesc.TxSize)
+	})
+	return staleAncestors
+}
+
+// isProtectedPackage returns whether or not the package of the provided entry
+// contains any transactions that must never be evicted, such as a ticket, or
+// that are spent by a transaction in the stage pool.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) isProtectedPackage(entry *evictionEntry) bool {
+	if mp.hasStagedRedeemer(entry.txDesc.Tx) {
+		return true
+	}
+
+	var protected bool
+	txHash := entry.txDesc.Tx.Hash()
+	mp.miningView.ForEachDescendant(txHash, func(desc *mining.TxDesc) {
+		if protected {
+			return
+		}
+		protected = !isEvictableTxType(desc.Type) ||
+			mp.hasStagedRedeemer(desc.Tx)
+	})
+	return protected
+}
+
+// nextEvictionCandidate returns the package with the lowest fee rate that may
+// be evicted from the main pool or nil when there are none.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) nextEvictionCandidate() *evictionEntry {
+	// Protected packages are temporarily removed from the heap while looking
+	// for a candidate so the next lowest fee rate package is available.
+	var candidate *evictionEntry
+	var protected []*evictionEntry
+	for len(mp.evictionHeap) > 0 {
+		entry := mp.evictionHeap[0]
+		if !mp.isProtectedPackage(entry) {
+			candidate = entry
+			break
+		}
+		protected = append(protected, heap.Pop(&mp.evictionHeap).(*evictionEntry))
+	}
+	for _, entry := range protected {
+		heap.Push(&mp.evictionHeap, entry)
+	}
+	return candidate
+}
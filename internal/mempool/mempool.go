@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,6 +8,7 @@ package mempool
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -60,6 +61,12 @@ const (
 	// are allowed in the mempool. The number 7 is also the amount of
 	// physical space available for TSpend votes and thus is a hard limit.
 	MempoolMaxConcurrentTSpends = 7
+
+	// rollingFeeHalfLife is the amount of time it takes for the dynamic
+	// minimum relay fee that results from evicting transactions due to the
+	// pool size limit to decay by half.  It decays faster when the pool has
+	// drained well below the limit.
+	rollingFeeHalfLife = time.Hour * 12
 )
 
 // Tag represents an identifier to use for tagging orphan transactions.  The
@@ -67,6 +74,62 @@ const (
 // so that orphans can be identified by which peer first relayed them.
 type Tag uint64
 
+// RemovalReason describes the reason a transaction was removed from the pool.
+type RemovalReason uint8
+
+// These constants define the reasons a transaction may be removed from the
+// pool.
+const (
+	// RemovalReasonMined indicates the transaction was included in a block
+	// connected to the main chain.
+	RemovalReasonMined RemovalReason = iota
+
+	// RemovalReasonExpired indicates the transaction is no longer able to be
+	// included in a block due to its expiry or age.  This includes stake
+	// transactions that are pruned once they can no longer be mined and
+	// orphans that were not resolved before their time to live elapsed.
+	RemovalReasonExpired
+
+	// RemovalReasonDoubleSpend indicates the transaction, or one of its
+	// ancestors, spends an output that is also spent by another transaction
+	// which took precedence.
+	RemovalReasonDoubleSpend
+
+	// RemovalReasonEvicted indicates the transaction was evicted in order to
+	// respect the limits on the size of the pool or the number of orphans.
+	RemovalReasonEvicted
+
+	// RemovalReasonDisapproved indicates the transaction, or one of its
+	// ancestors, spends from a transaction that was returned to the pool due
+	// to the regular transaction tree of its block being disapproved or
+	// disconnected.
+	RemovalReasonDisapproved
+
+	// RemovalReasonInvalid indicates the transaction was found to be invalid
+	// for some other reason, such as an orphan that fails validation once its
+	// parents are known.
+	RemovalReasonInvalid
+)
+
+// removalReasonStrings is a map of removal reasons back to their constant
+// names for pretty printing.
+var removalReasonStrings = map[RemovalReason]string{
+	RemovalReasonMined:       "mined",
+	RemovalReasonExpired:     "expired",
+	RemovalReasonDoubleSpend: "doublespend",
+	RemovalReasonEvicted:     "evicted",
+	RemovalReasonDisapproved: "disapproved",
+	RemovalReasonInvalid:     "invalid",
+}
+
+// String returns the RemovalReason in human-readable form.
+func (r RemovalReason) String() string {
+	if s, ok := removalReasonStrings[r]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown RemovalReason (%d)", uint8(r))
+}
+
 // Config is a descriptor containing the memory pool configuration.
 type Config struct {
 	// Policy defines the various mempool configuration options related
@@ -125,16 +188,31 @@ type Config struct {
 	// This can be nil if the address index is not enabled.
 	ExistsAddrIndex *indexers.ExistsAddrIndex
 
+	// AddrIndex defines the optional address index instance to use for
+	// indexing the unconfirmed transactions in the memory pool.
+	// This can be nil if the address index is not enabled.
+	AddrIndex *indexers.AddrIndex
+
 	// AddTxToFeeEstimation defines an optional function to be called whenever a
 	// new transaction is added to the mempool, which can be used to track fees
-	// for the purposes of smart fee estimation.
-	AddTxToFeeEstimation func(txHash *chainhash.Hash, fee, size int64, txType stake.TxType)
+	// for the purposes of smart fee estimation.  The added height is the height
+	// of the best chain when the transaction originally entered the mempool.
+	AddTxToFeeEstimation func(txHash *chainhash.Hash, fee, size int64, txType stake.TxType, addedHeight int64)
 
 	// RemoveTxFromFeeEstimation defines an optional function to be called
 	// whenever a transaction is removed from the mempool in order to track fee
 	// estimation.
 	RemoveTxFromFeeEstimation func(txHash *chainhash.Hash)
 
+	// OnTxRemoved defines an optional function to be called whenever a
+	// transaction is removed from the main pool or the orphan pool along with
+	// the reason it was removed.  Transactions that are moved from the orphan
+	// pool to the main pool are not considered removed.
+	//
+	// This function is called with the mempool lock held, so it must not call
+	// back into the mempool.
+	OnTxRemoved func(tx *dcrutil.Tx, reason RemovalReason)
+
 	// OnVoteReceived defines the function used to signal receiving a new
 	// vote in the mempool.
 	OnVoteReceived func(voteTx *dcrutil.Tx)
@@ -186,6 +264,13 @@ type Policy struct {
 	// orphans.
 	MaxOrphanTxSize int
 
+	// MaxPoolSize is the maximum total serialized size in bytes of all of the
+	// transactions in the main pool.  Once it is exceeded, the regular
+	// transaction packages that pay the lowest fee rates are evicted and the
+	// minimum relay fee is dynamically raised.  A value of zero disables the
+	// limit.
+	MaxPoolSize int64
+
 	// MaxSigOpsPerTx is the maximum number of signature operations
 	// in a single transaction we will relay or mine.  It is a fraction
 	// of the max signature operations for a block.
@@ -233,6 +318,45 @@ type VerboseTxDesc struct {
 	// Depends enumerates any unconfirmed transactions in the pool used as
 	// inputs for the transaction.
 	Depends []*TxDesc
+
+	// Ancestors houses aggregate statistics for all unconfirmed transactions
+	// in the pool the transaction depends on, either directly or indirectly.
+	//
+	// It, along with Descendants and PackageFeeRate, is only populated for
+	// the descriptors returned by VerboseTxDesc, AncestorTxDescs, and
+	// DescendantTxDescs since they require walking the transaction graph.
+	Ancestors PackageStats
+
+	// Descendants houses aggregate statistics for all transactions in the
+	// pool that depend on the transaction, either directly or indirectly.
+	Descendants PackageStats
+
+	// PackageFeeRate is the fee per kilobyte, in atoms, of the transaction
+	// together with its ancestors as used by the block template generator to
+	// prioritize the transaction.  It only accounts for ancestors when
+	// ancestor tracking is enabled and the transaction is within the
+	// ancestor tracking limit.
+	PackageFeeRate float64
+}
+
+// PackageStats houses aggregate statistics about a set of related transactions
+// in the pool.
+type PackageStats struct {
+	// Count is the number of transactions.
+	Count int
+
+	// Size is the total serialized size of the transactions in bytes.
+	Size int64
+
+	// Fees is the total fees paid by the transactions in atoms.
+	Fees int64
+}
+
+// add updates the package statistics to account for the provided transaction.
+func (s *PackageStats) add(txDesc *mining.TxDesc) {
+	s.Count++
+	s.Size += txDesc.TxSize
+	s.Fees += txDesc.Fee
 }
 
 // orphanTx is a normal transaction that references an ancestor transaction
@@ -264,6 +388,34 @@ type TxPool struct {
 
 	transient map[chainhash.Hash]*dcrutil.Tx
 
+	// restoredTxns tracks the original time and height transactions that are
+	// in the process of being restored from a mempool dump were added to the
+	// pool.
+	restoredTxns map[chainhash.Hash]restoredTx
+
+	// stemTxns tracks the transactions in the main pool that are still in the
+	// stem phase of stem/fluff relay.  They are not included in the results of
+	// the methods that are used to advertise the contents of the pool until
+	// they are fluffed so they can't be used to determine the origin of the
+	// transactions.
+	stemTxns map[chainhash.Hash]struct{}
+
+	// poolSize is the total serialized size of all transactions in the main
+	// pool.
+	poolSize int64
+
+	// evictionEntries and evictionHeap track the packages of the transactions
+	// in the main pool so the package with the lowest fee rate can be evicted
+	// without scanning the entire pool when it exceeds the maximum size.
+	evictionEntries map[chainhash.Hash]*evictionEntry
+	evictionHeap    evictionHeap
+
+	// rollingMinFeeRate is the dynamic minimum relay fee rate in atoms/kB that
+	// results from evicting transactions due to the pool size limit.  It
+	// decays exponentially from the time specified by lastRollingFeeUpdate.
+	rollingMinFeeRate    float64
+	lastRollingFeeUpdate time.Time
+
 	// Votes on blocks.
 	votesMtx sync.RWMutex
 	votes    map[chainhash.Hash][]mining.VoteDesc
@@ -375,16 +527,17 @@ func (mp *TxPool) TSpendHashes() []chainhash.Hash {
 // Ensure the TxPool type implements the mining.TxSource interface.
 var _ mining.TxSource = (*TxPool)(nil)
 
-// removeOrphan is the internal function which implements the public
-// RemoveOrphan.  See the comment for RemoveOrphan for more details.
+// unindexOrphan removes the passed transaction from the orphan pool and
+// previous orphan index without removing any orphans that redeem it.  It
+// returns whether or not the transaction existed in the orphan pool.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
+func (mp *TxPool) unindexOrphan(tx *dcrutil.Tx) bool {
 	// Nothing to do if the passed tx does not exist in the orphan pool.
 	txHash := tx.Hash()
 	otx, exists := mp.orphans[*txHash]
 	if !exists {
-		return
+		return false
 	}
 
 	log.Tracef("Removing orphan transaction %v", txHash)
@@ -403,6 +556,21 @@ func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
 		}
 	}
 
+	// Remove the transaction from the orphan pool.
+	delete(mp.orphans, *txHash)
+	return true
+}
+
+// removeOrphan is the internal function which implements the public
+// RemoveOrphan.  See the comment for RemoveOrphan for more details.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool, reason RemovalReason) {
+	// Nothing to do if the passed tx does not exist in the orphan pool.
+	if !mp.unindexOrphan(tx) {
+		return
+	}
+
 	// Remove any orphans that redeem outputs from this one if requested.
 	if removeRedeemers {
 		txType := stake.DetermineTxType(tx.MsgTx())
@@ -411,26 +579,28 @@ func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
 			tree = wire.TxTreeStake
 		}
 
-		outpoint := wire.OutPoint{Hash: *txHash, Tree: tree}
+		outpoint := wire.OutPoint{Hash: *tx.Hash(), Tree: tree}
 		for txOutIdx := range tx.MsgTx().TxOut {
 			outpoint.Index = uint32(txOutIdx)
 			for _, orphan := range mp.orphansByPrev[outpoint] {
-				mp.removeOrphan(orphan, true)
+				mp.removeOrphan(orphan, true, reason)
 			}
 		}
 	}
 
-	// Remove the transaction from the orphan pool.
-	delete(mp.orphans, *txHash)
+	if mp.cfg.OnTxRemoved != nil {
+		mp.cfg.OnTxRemoved(tx, reason)
+	}
 }
 
 // RemoveOrphan removes the passed orphan transaction from the orphan pool and
-// previous orphan index.
+// previous orphan index.  The provided reason is reported to the OnTxRemoved
+// callback, if any.
 //
 // This function is safe for concurrent access.
-func (mp *TxPool) RemoveOrphan(tx *dcrutil.Tx) {
+func (mp *TxPool) RemoveOrphan(tx *dcrutil.Tx, reason RemovalReason) {
 	mp.mtx.Lock()
-	mp.removeOrphan(tx, false)
+	mp.removeOrphan(tx, false, reason)
 	mp.mtx.Unlock()
 }
 
@@ -444,7 +614,7 @@ func (mp *TxPool) RemoveOrphansByTag(tag Tag) uint64 {
 	mp.mtx.Lock()
 	for _, otx := range mp.orphans {
 		if otx.tag == tag {
-			mp.removeOrphan(otx.tx, true)
+			mp.removeOrphan(otx.tx, true, RemovalReasonEvicted)
 			numEvicted++
 		}
 	}
@@ -467,7 +637,7 @@ func (mp *TxPool) limitNumOrphans() {
 				// Remove redeemers too because the missing parents are very
 				// unlikely to ever materialize since the orphan has already
 				// been around more than long enough for them to be delivered.
-				mp.removeOrphan(otx.tx, true)
+				mp.removeOrphan(otx.tx, true, RemovalReasonExpired)
 			}
 		}
 
@@ -496,7 +666,7 @@ func (mp *TxPool) limitNumOrphans() {
 	for _, otx := range mp.orphans {
 		// Don't remove redeemers in the case of a random eviction since
 		// it is quite possible it might be needed again shortly.
-		mp.removeOrphan(otx.tx, false)
+		mp.removeOrphan(otx.tx, false, RemovalReasonEvicted)
 		break
 	}
 }
@@ -571,7 +741,7 @@ func (mp *TxPool) removeOrphanDoubleSpends(tx *dcrutil.Tx) {
 	msgTx := tx.MsgTx()
 	for _, txIn := range msgTx.TxIn {
 		for _, orphan := range mp.orphansByPrev[txIn.PreviousOutPoint] {
-			mp.removeOrphan(orphan, true)
+			mp.removeOrphan(orphan, true, RemovalReasonDoubleSpend)
 		}
 	}
 }
@@ -721,6 +891,25 @@ func (mp *TxPool) forEachStagedRedeemer(tx *dcrutil.Tx, f func(redeemer *TxDesc)
 	forEachRedeemer(tx, mp.stagedOutpoints, f)
 }
 
+// hasStagedRedeemer returns whether or not any transactions in the stage pool
+// have an input referencing the provided regular transaction tx.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) hasStagedRedeemer(tx *dcrutil.Tx) bool {
+	if len(mp.stagedOutpoints) == 0 {
+		return false
+	}
+
+	outpoint := wire.OutPoint{Hash: *tx.Hash(), Tree: wire.TxTreeRegular}
+	for i := range tx.MsgTx().TxOut {
+		outpoint.Index = uint32(i)
+		if _, exists := mp.stagedOutpoints[outpoint]; exists {
+			return true
+		}
+	}
+	return false
+}
+
 // haveTransaction returns whether or not the passed transaction already exists
 // in the main pool or in the orphan pool.
 //
@@ -788,7 +977,7 @@ func (mp *TxPool) HaveAllTransactions(hashes []chainhash.Hash) bool {
 // RemoveTransaction.  See the comment for RemoveTransaction for more details.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
+func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool, reason RemovalReason) {
 	txHash := tx.Hash()
 	if removeRedeemers {
 		// Remove any transactions which rely on this one.
@@ -802,7 +991,7 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 		for i := uint32(0); i < uint32(len(tx.MsgTx().TxOut)); i++ {
 			outpoint.Index = i
 			if txRedeemerDesc, exists := mp.outpoints[outpoint]; exists {
-				mp.removeTransaction(txRedeemerDesc.Tx, true)
+				mp.removeTransaction(txRedeemerDesc.Tx, true, reason)
 				continue
 			}
 			if txRedeemerDesc, exists := mp.stagedOutpoints[outpoint]; exists {
@@ -825,9 +1014,20 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 		// If redeeming transactions are going to be removed from the
 		// graph, then do not update their stats.
 		updateDescendantStats := !removeRedeemers
+		staleAncestors := mp.untrackEvictionPackage(txDesc)
 		mp.miningView.RemoveTransaction(tx.Hash(), updateDescendantStats)
+		for _, ancestorHash := range staleAncestors {
+			mp.recalcEvictionPackage(ancestorHash)
+		}
 
 		delete(mp.pool, *txHash)
+		mp.poolSize -= txDesc.TxSize
+
+		// Remove unconfirmed address index entries associated with the
+		// transaction if enabled.
+		if mp.cfg.AddrIndex != nil {
+			mp.cfg.AddrIndex.RemoveUnconfirmedTx(txHash)
+		}
 
 		mp.lastUpdated.Store(time.Now().Unix())
 
@@ -839,19 +1039,27 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 
 		// Stop tracking if it's a tspend.
 		delete(mp.tspends, *txHash)
+
+		// Stop tracking if it's in the stem phase.
+		delete(mp.stemTxns, *txHash)
+
+		if mp.cfg.OnTxRemoved != nil {
+			mp.cfg.OnTxRemoved(tx, reason)
+		}
 	}
 }
 
 // RemoveTransaction removes the passed transaction from the mempool. When the
 // removeRedeemers flag is set, any transactions that redeem outputs from the
 // removed transaction will also be removed recursively from the mempool, as
-// they would otherwise become orphans.
+// they would otherwise become orphans.  The provided reason is reported to the
+// OnTxRemoved callback, if any, for all removed transactions.
 //
 // This function is safe for concurrent access.
-func (mp *TxPool) RemoveTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
+func (mp *TxPool) RemoveTransaction(tx *dcrutil.Tx, removeRedeemers bool, reason RemovalReason) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
-	mp.removeTransaction(tx, removeRedeemers)
+	mp.removeTransaction(tx, removeRedeemers, reason)
 	mp.mtx.Unlock()
 }
 
@@ -868,7 +1076,8 @@ func (mp *TxPool) RemoveDoubleSpends(tx *dcrutil.Tx) {
 	for _, txIn := range tx.MsgTx().TxIn {
 		if txRedeemerDesc, ok := mp.outpoints[txIn.PreviousOutPoint]; ok {
 			if txRedeemerDesc.Tx.Hash() != tx.Hash() {
-				mp.removeTransaction(txRedeemerDesc.Tx, true)
+				mp.removeTransaction(txRedeemerDesc.Tx, true,
+					RemovalReasonDoubleSpend)
 			}
 		}
 		if txRedeemerDesc, ok := mp.stagedOutpoints[txIn.PreviousOutPoint]; ok {
@@ -882,6 +1091,142 @@ func (mp *TxPool) RemoveDoubleSpends(tx *dcrutil.Tx) {
 	mp.mtx.Unlock()
 }
 
+// isEvictableTxType returns whether or not transactions of the provided type
+// may be evicted from the main pool in order to respect the pool size limit.
+//
+// Votes, tickets, revocations, and treasury spends are never evicted since
+// they are an integral part of block production and governance and the number
+// of them in the pool is already constrained by other means.
+func isEvictableTxType(txType stake.TxType) bool {
+	return txType == stake.TxTypeRegular || txType == stake.TxTypeTAdd
+}
+
+// decayedRollingMinFeeRate returns the dynamic minimum relay fee rate in
+// atoms/kB that results from evicting transactions due to the pool size limit
+// after applying the exponential decay since it was last updated as of the
+// provided time.  It returns zero once it has decayed well below the
+// configured minimum relay fee.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) decayedRollingMinFeeRate(now time.Time) float64 {
+	if mp.rollingMinFeeRate == 0 {
+		return 0
+	}
+
+	// Decay the dynamic minimum based on the time since it was last updated.
+	// The decay is faster when the pool is mostly empty since there is little
+	// risk of needing to evict again soon.
+	halfLife := rollingFeeHalfLife
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	switch {
+	case mp.poolSize < maxSize/4:
+		halfLife /= 4
+	case mp.poolSize < maxSize/2:
+		halfLife /= 2
+	}
+	elapsed := now.Sub(mp.lastRollingFeeUpdate)
+	feeRate := mp.rollingMinFeeRate /
+		math.Pow(2, elapsed.Seconds()/halfLife.Seconds())
+
+	// Stop tracking the dynamic minimum once it has decayed well below the
+	// static minimum.
+	staticMinFee := mp.cfg.Policy.MinRelayTxFee
+	if feeRate < float64(staticMinFee)/2 || feeRate < 1 {
+		return 0
+	}
+	return feeRate
+}
+
+// minRelayTxFee returns the minimum fee in atoms/kB that evictable
+// transactions must pay to be accepted into the main pool.  It is the greater
+// of the configured minimum relay fee and the decaying dynamic minimum that
+// results from evicting transactions due to the pool size limit.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) minRelayTxFee() dcrutil.Amount {
+	staticMinFee := mp.cfg.Policy.MinRelayTxFee
+	rollingMinFeeRate := mp.decayedRollingMinFeeRate(time.Now())
+	rollingMinFee := dcrutil.Amount(math.Ceil(rollingMinFeeRate))
+	if rollingMinFee > staticMinFee {
+		return rollingMinFee
+	}
+	return staticMinFee
+}
+
+// MinRelayTxFee returns the minimum fee in atoms/kB that regular transactions
+// must currently pay to be accepted into the mempool.  It will be higher than
+// the configured minimum relay fee when transactions have recently been
+// evicted due to the pool size limit.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) MinRelayTxFee() dcrutil.Amount {
+	mp.mtx.RLock()
+	minFee := mp.minRelayTxFee()
+	mp.mtx.RUnlock()
+	return minFee
+}
+
+// limitPoolSize evicts the regular transaction packages that pay the lowest fee
+// rates from the main pool until its total size no longer exceeds the maximum
+// allowed.  A package consists of a transaction along with all transactions in
+// the pool that depend on it, since they can not remain in the pool without
+// it.  Packages that contain any transactions that are not evictable, such as
+// a ticket that spends a regular transaction output, are never evicted.
+//
+// The packages are tracked by an eviction heap ordered by fee rate, so finding
+// the package to evict does not require scanning the entire pool.
+//
+// The dynamic minimum relay fee is raised above the fee rate of every evicted
+// package so that transactions which pay less than those that were evicted are
+// not accepted again until it decays.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) limitPoolSize() {
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	if maxSize <= 0 {
+		return
+	}
+
+	var numEvicted int
+	for mp.poolSize > maxSize {
+		// Find the evictable package with the lowest fee rate.
+		candidate := mp.nextEvictionCandidate()
+		if candidate == nil {
+			log.Warnf("Unable to evict any transactions to reduce the "+
+				"mempool size of %d bytes below the limit of %d bytes",
+				mp.poolSize, maxSize)
+			break
+		}
+		evictTx := candidate.txDesc.Tx
+		evictFeeRate := candidate.feeRate()
+
+		// Raise the dynamic minimum relay fee above the fee rate of the
+		// evicted package by the static minimum relay fee.  Apply any
+		// outstanding decay first so it is not applied to the new value.
+		newMinFeeRate := evictFeeRate + float64(mp.cfg.Policy.MinRelayTxFee)
+		now := time.Now()
+		mp.rollingMinFeeRate = mp.decayedRollingMinFeeRate(now)
+		mp.lastRollingFeeUpdate = now
+		if newMinFeeRate > mp.rollingMinFeeRate {
+			mp.rollingMinFeeRate = newMinFeeRate
+		}
+
+		log.Debugf("Evicting transaction %v and its descendants with a "+
+			"package fee rate of %.0f atoms/kB due to the mempool size "+
+			"limit", evictTx.Hash(), evictFeeRate)
+		numBefore := len(mp.pool)
+		mp.removeTransaction(evictTx, true, RemovalReasonEvicted)
+		numEvicted += numBefore - len(mp.pool)
+	}
+
+	if numEvicted > 0 {
+		log.Debugf("Evicted %d %s to limit the mempool size (size: %d "+
+			"bytes, min relay fee: %v/kB)", numEvicted,
+			pickNoun(numEvicted, "transaction", "transactions"),
+			mp.poolSize, mp.minRelayTxFee())
+	}
+}
+
 // findTx returns a transaction from the mempool by hash.  If it does not exist
 // in the mempool, a nil pointer is returned.
 func (mp *TxPool) findTx(txHash *chainhash.Hash) *mining.TxDesc {
@@ -911,7 +1256,9 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, txDesc *TxD
 	// Add the transaction to the pool and mark the referenced outpoints
 	// as spent by the pool.
 	mp.pool[*txHash] = txDesc
+	mp.poolSize += txDesc.TxSize
 	mp.miningView.AddTransaction(&txDesc.TxDesc, mp.findTx)
+	mp.trackEvictionPackage(txDesc)
 
 	msgTx := tx.MsgTx()
 	for _, txIn := range msgTx.TxIn {
@@ -925,10 +1272,17 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, txDesc *TxD
 		mp.cfg.ExistsAddrIndex.AddUnconfirmedTx(msgTx)
 	}
 
+	// Add unconfirmed address index entries associated with the transaction
+	// if enabled.
+	if mp.cfg.AddrIndex != nil {
+		mp.cfg.AddrIndex.AddUnconfirmedTx(tx, utxoView)
+	}
+
 	// Inform the associated fee estimator that a new transaction has been added
 	// to the mempool.
 	if mp.cfg.AddTxToFeeEstimation != nil {
-		mp.cfg.AddTxToFeeEstimation(txHash, txDesc.Fee, txDesc.TxSize, txType)
+		mp.cfg.AddTxToFeeEstimation(txHash, txDesc.Fee, txDesc.TxSize, txType,
+			txDesc.Height)
 	}
 }
 
@@ -1132,11 +1486,18 @@ func (mp *TxPool) FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx, error)
 func (mp *TxPool) newTxDesc(utxoView *blockchain.UtxoViewpoint, tx *dcrutil.Tx,
 	txType stake.TxType, height int64, fee int64, totalSigOps int, txSize int64) *TxDesc {
 
+	// Retain the original time and height the transaction was added to the
+	// pool when it is being restored from a mempool dump.
+	added := time.Now()
+	if restored, ok := mp.restoredTxns[*tx.Hash()]; ok {
+		added, height = restored.added, restored.height
+	}
+
 	return &TxDesc{
 		TxDesc: mining.TxDesc{
 			Tx:          tx,
 			Type:        txType,
-			Added:       time.Now(),
+			Added:       added,
 			Height:      height,
 			Fee:         fee,
 			TotalSigOps: totalSigOps,
@@ -1198,23 +1559,25 @@ func (mp *TxPool) MaybeAcceptDependents(tx *dcrutil.Tx, isTreasuryEnabled bool)
 	return acceptedTxns
 }
 
-// maybeAcceptTransaction is the internal function which implements the public
-// MaybeAcceptTransaction.  See the comment for MaybeAcceptTransaction for
-// more details.
+// validateTransaction performs all of the policy and consensus checks needed
+// to determine whether or not the passed transaction is allowed into the
+// memory pool without modifying the pool.
 //
-// This function MUST be called with the mempool lock held (for writes).
+// It returns a descriptor for the transaction along with the view of the
+// utxos it spends when it passes all checks.  When the transaction is an
+// orphan, the descriptor is nil and the referenced outputs that are unknown or
+// already spent are returned instead.  Note that the transaction in the
+// returned descriptor might differ from the passed one since it is copied
+// when its fraud proof data needs to be updated.
 //
-// DECRED - TODO
-// We need to make sure thing also assigns the TxType after it evaluates the tx,
-// so that we can easily pick different stake tx types from the mempool later.
-// This should probably be done at the bottom using "IsSStx" etc functions.
-// It should also set the dcrutil tree type for the tx as well.
-func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
-	rejectDupOrphans bool,
-	checkTxFlags blockchain.AgendaFlags) ([]wire.OutPoint, error) {
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) validateTransaction(tx *dcrutil.Tx, allowHighFees,
+	rejectDupOrphans bool, checkTxFlags blockchain.AgendaFlags) (*TxDesc,
+	*blockchain.UtxoViewpoint, []wire.OutPoint, error) {
 
 	msgTx := tx.MsgTx()
 	txHash := tx.Hash()
+
 	// Don't accept the transaction if it already exists in the pool.  This
 	// applies to orphan transactions as well when the reject duplicate
 	// orphans flag is set.  This check is intended to be a quick check to
@@ -1222,7 +1585,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if mp.isTransactionInPool(txHash) || mp.isTransactionStaged(txHash) ||
 		(rejectDupOrphans && mp.isOrphanInPool(txHash)) {
 		str := fmt.Sprintf("already have transaction %v", txHash)
-		return nil, txRuleError(ErrDuplicate, str)
+		return nil, nil, nil, txRuleError(ErrDuplicate, str)
 	}
 
 	// Perform preliminary validation checks on the transaction.  This makes use
@@ -1232,9 +1595,9 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if err != nil {
 		var cerr blockchain.RuleError
 		if errors.As(err, &cerr) {
-			return nil, chainRuleError(cerr)
+			return nil, nil, nil, chainRuleError(cerr)
 		}
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Determine active agendas based on flags.
@@ -1268,14 +1631,14 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if isTreasurybase {
 		str := fmt.Sprintf("transaction %v is an individual treasurybase",
 			txHash)
-		return nil, txRuleError(ErrTreasurybase, str)
+		return nil, nil, nil, txRuleError(ErrTreasurybase, str)
 	}
 
 	// A standalone transaction must not be a coinbase transaction.
 	if standalone.IsCoinBaseTx(msgTx, isTreasuryEnabled) {
 		str := fmt.Sprintf("transaction %v is an individual coinbase",
 			txHash)
-		return nil, txRuleError(ErrCoinbase, str)
+		return nil, nil, nil, txRuleError(ErrCoinbase, str)
 	}
 
 	// Get the current height of the main chain.  A standalone transaction
@@ -1288,7 +1651,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if blockchain.IsExpired(tx, nextBlockHeight) {
 		str := fmt.Sprintf("transaction %v expired at height %d",
 			txHash, msgTx.Expiry)
-		return nil, txRuleError(ErrExpired, str)
+		return nil, nil, nil, txRuleError(ErrExpired, str)
 	}
 
 	// Reject votes and treasury spends before stake validation height.
@@ -1302,7 +1665,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		}
 		str := fmt.Sprintf("%s are not valid until block height %d (next "+
 			"block height %d)", strType, stakeValidationHeight, nextBlockHeight)
-		return nil, txRuleError(ErrInvalid, str)
+		return nil, nil, nil, txRuleError(ErrInvalid, str)
 	}
 
 	// Reject revocations before they can possibly be valid.  A vote must be
@@ -1313,7 +1676,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if isRevocation && nextBlockHeight < stakeValidationHeight+1 {
 		str := fmt.Sprintf("revocations are not valid until block height %d "+
 			"(next block height %d)", stakeValidationHeight+1, nextBlockHeight)
-		return nil, txRuleError(ErrInvalid, str)
+		return nil, nil, nil, txRuleError(ErrInvalid, str)
 	}
 
 	// Don't allow non-standard transactions if the mempool config forbids
@@ -1325,7 +1688,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		if err != nil {
 			str := fmt.Sprintf("transaction %v is not standard: %v",
 				txHash, err)
-			return nil, wrapTxRuleError(ErrNonStandard, str, err)
+			return nil, nil, nil, wrapTxRuleError(ErrNonStandard, str, err)
 		}
 	}
 
@@ -1337,14 +1700,14 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		if err != nil {
 			// This is an unexpected error so don't turn it into a
 			// rule error.
-			return nil, err
+			return nil, nil, nil, err
 		}
 
 		if msgTx.TxOut[0].Value < sDiff {
 			str := fmt.Sprintf("transaction %v has not enough funds "+
 				"to meet stake difficulty (ticket diff %v < next diff %v)",
 				txHash, msgTx.TxOut[0].Value, sDiff)
-			return nil, txRuleError(ErrInsufficientFee, str)
+			return nil, nil, nil, txRuleError(ErrInsufficientFee, str)
 		}
 	}
 
@@ -1353,7 +1716,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if mp.cfg.NonMixSpendsPairRequest != nil && mp.cfg.NonMixSpendsPairRequest(tx) {
 		str := fmt.Sprintf("non-mix transaction %v spends current mixpool "+
 			"pair request UTXOs", txHash)
-		return nil, txRuleError(ErrMixpoolDoubleSpend, str)
+		return nil, nil, nil, txRuleError(ErrMixpoolDoubleSpend, str)
 	}
 
 	// Aside from a few exceptions for votes and revocations, the transaction
@@ -1368,7 +1731,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if !isVote && !isRevocation {
 		err = mp.checkPoolDoubleSpend(tx, txType, isTreasuryEnabled)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 
 	} else if isVote {
@@ -1378,7 +1741,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		// check to merely reject double spends of tickets is not possible.
 		err := mp.checkVoteDoubleSpend(tx)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 
 		voteAlreadyFound := 0
@@ -1393,7 +1756,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 				str := fmt.Sprintf("transaction %v in the pool with more than "+
 					"%v votes", msgTx.TxIn[1].PreviousOutPoint,
 					maxVoteDoubleSpends)
-				return nil, txRuleError(ErrTooManyVotes, str)
+				return nil, nil, nil, txRuleError(ErrTooManyVotes, str)
 			}
 		}
 
@@ -1405,7 +1768,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 					str := fmt.Sprintf("transaction %v in the pool as a "+
 						"revocation. Only one revocation is allowed.",
 						msgTx.TxIn[0].PreviousOutPoint)
-					return nil, txRuleError(ErrDuplicateRevocation, str)
+					return nil, nil, nil, txRuleError(ErrDuplicateRevocation, str)
 				}
 			}
 		}
@@ -1420,7 +1783,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 				"block height of %d which is before the "+
 				"current cutoff height of %v", tx.Hash(),
 				voteHeight, nextBlockHeight-int64(mp.cfg.Policy.MaxVoteAge))
-			return nil, txRuleError(ErrOldVote, str)
+			return nil, nil, nil, txRuleError(ErrOldVote, str)
 		}
 	}
 
@@ -1430,7 +1793,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	// without needing to do a separate lookup.
 	utxoView, err := mp.fetchInputUtxos(tx, isTreasuryEnabled)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Don't allow the transaction if it exists in the main chain and is not
@@ -1440,7 +1803,8 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		outpoint.Index = uint32(txOutIdx)
 		entry := utxoView.LookupEntry(outpoint)
 		if entry != nil && !entry.IsSpent() {
-			return nil, txRuleError(ErrAlreadyExists, "transaction already exists")
+			str := "transaction already exists"
+			return nil, nil, nil, txRuleError(ErrAlreadyExists, str)
 		}
 		utxoView.RemoveEntry(outpoint)
 	}
@@ -1487,7 +1851,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	}
 
 	if len(missingParents) > 0 {
-		return missingParents, nil
+		return nil, nil, missingParents, nil
 	}
 
 	// Update the fraud proof data on the transaction inputs as necessary.  The
@@ -1536,13 +1900,13 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		if err != nil {
 			var cerr blockchain.RuleError
 			if errors.As(err, &cerr) {
-				return nil, chainRuleError(cerr)
+				return nil, nil, nil, chainRuleError(cerr)
 			}
-			return nil, err
+			return nil, nil, nil, err
 		}
 		if !blockchain.SequenceLockActive(seqLock, nextBlockHeight, medianTime) {
 			str := "transaction sequence locks on inputs not met"
-			return nil, txRuleError(ErrSeqLockUnmet, str)
+			return nil, nil, nil, txRuleError(ErrSeqLockUnmet, str)
 		}
 	}
 
@@ -1554,7 +1918,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	bestHash := mp.cfg.BestHash()
 	bestHeader, err := mp.cfg.HeaderByHash(bestHash)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	txFee, err := blockchain.CheckTransactionInputs(mp.cfg.SubsidyCache, tx,
 		nextBlockHeight, utxoView, true, mp.cfg.ChainParams, &bestHeader,
@@ -1562,9 +1926,9 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if err != nil {
 		var cerr blockchain.RuleError
 		if errors.As(err, &cerr) {
-			return nil, chainRuleError(cerr)
+			return nil, nil, nil, chainRuleError(cerr)
 		}
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Don't allow transactions with non-standard inputs if the mempool config
@@ -1574,7 +1938,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		if err != nil {
 			str := fmt.Sprintf("transaction %v has a non-standard "+
 				"input: %v", txHash, err)
-			return nil, wrapTxRuleError(ErrNonStandard, str, err)
+			return nil, nil, nil, wrapTxRuleError(ErrNonStandard, str, err)
 		}
 	}
 
@@ -1592,9 +1956,9 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if err != nil {
 		var cerr blockchain.RuleError
 		if errors.As(err, &cerr) {
-			return nil, chainRuleError(cerr)
+			return nil, nil, nil, chainRuleError(cerr)
 		}
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	numSigOps := blockchain.CountSigOps(tx, false, isVote, isTreasuryEnabled)
@@ -1602,7 +1966,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	if totalSigOps > mp.cfg.Policy.MaxSigOpsPerTx {
 		str := fmt.Sprintf("transaction %v has too many sigops: %d > %d",
 			txHash, totalSigOps, mp.cfg.Policy.MaxSigOpsPerTx)
-		return nil, txRuleError(ErrNonStandard, str)
+		return nil, nil, nil, txRuleError(ErrNonStandard, str)
 	}
 
 	// Don't allow transactions with fees too low to get into a mined block.
@@ -1620,10 +1984,17 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	// - Treasurybases (rejected from the mempool anyway)
 	// - Revocations (automatic revocations never in the mempool anyway)
 	// - Votes
+	//
+	// The transactions that are subject to eviction when the pool is full must
+	// also pay the dynamic minimum relay fee, which is higher than the static
+	// one when transactions have recently been evicted.
 	isTreasuryAdd := isTreasuryEnabled && txType == stake.TxTypeTAdd
 	serializedSize := int64(msgTx.SerializeSize())
-	minFee := calcMinRequiredTxRelayFee(serializedSize,
-		mp.cfg.Policy.MinRelayTxFee)
+	minRelayTxFee := mp.cfg.Policy.MinRelayTxFee
+	if isEvictableTxType(txType) {
+		minRelayTxFee = mp.minRelayTxFee()
+	}
+	minFee := calcMinRequiredTxRelayFee(serializedSize, minRelayTxFee)
 	if txFee < minFee && (txType == stake.TxTypeRegular || isTicket ||
 		isTreasuryAdd || isTSpend) {
 
@@ -1641,7 +2012,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		str := fmt.Sprintf("%stransaction %s pays a fee of %d atoms which is "+
 			"under the required fee of %d atoms for a %d-byte transaction",
 			txTypeStr, txHash, txFee, minFee, serializedSize)
-		return nil, txRuleError(ErrInsufficientFee, str)
+		return nil, nil, nil, txRuleError(ErrInsufficientFee, str)
 	}
 
 	// Check whether allowHighFees is set to false (default), if so, then make
@@ -1654,7 +2025,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 			str := fmt.Sprintf("transaction %v has %v fee which is above the "+
 				"allowHighFee check threshold amount of %v", txHash,
 				txFee, maxFee)
-			return nil, txRuleError(ErrFeeTooHigh, str)
+			return nil, nil, nil, txRuleError(ErrFeeTooHigh, str)
 		}
 	}
 
@@ -1662,16 +2033,16 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	// any don't verify.
 	flags, err := mp.cfg.Policy.StandardVerifyFlags()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	err = blockchain.ValidateTransactionScripts(tx, utxoView, flags,
 		mp.cfg.SigCache, isAutoRevocationsEnabled)
 	if err != nil {
 		var cerr blockchain.RuleError
 		if errors.As(err, &cerr) {
-			return nil, chainRuleError(cerr)
+			return nil, nil, nil, chainRuleError(cerr)
 		}
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Only allow TSpends that have a valid Expiry.
@@ -1688,7 +2059,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		if err != nil {
 			str := fmt.Sprintf("Invalid tspend expiry %d: %v ",
 				msgTx.Expiry, err)
-			return nil, txRuleError(ErrTSpendInvalidExpiry, str)
+			return nil, nil, nil, txRuleError(ErrTSpendInvalidExpiry, str)
 		}
 		voteStartThresh := int64(2 * tvi * mul)
 		blocksToVoteStart := int64(voteStart) - nextBlockHeight
@@ -1699,7 +2070,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 				"future: voting starts in %d blocks while the "+
 				"voting threshold is %d blocks",
 				blocksToVoteStart, voteStartThresh)
-			return nil, txRuleError(ErrTSpendInvalidExpiry, str)
+			return nil, nil, nil, txRuleError(ErrTSpendInvalidExpiry, str)
 		}
 
 		// Only allow up to MempoolMaxConcurrentTSpends TSpends in the
@@ -1709,7 +2080,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 			str := fmt.Sprintf("Mempool can only hold %v "+
 				"concurrent TSpend transactions",
 				MempoolMaxConcurrentTSpends)
-			return nil, txRuleError(ErrTooManyTSpends, str)
+			return nil, nil, nil, txRuleError(ErrTooManyTSpends, str)
 		}
 
 		// Verify that this TSpend uses a well-known Pi key and that
@@ -1717,29 +2088,25 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		signature, pubKey, err := stake.CheckTSpend(msgTx)
 		if err != nil {
 			str := fmt.Sprintf("Mempool invalid TSpend: %v", err)
-			return nil, txRuleError(ErrInvalid, str)
+			return nil, nil, nil, txRuleError(ErrInvalid, str)
 		}
 		if !mp.cfg.ChainParams.PiKeyExists(pubKey) {
 			str := fmt.Sprintf("Unknown Pi Key: %x", pubKey)
-			return nil, txRuleError(ErrInvalid, str)
+			return nil, nil, nil, txRuleError(ErrInvalid, str)
 		}
 		err = blockchain.VerifyTSpendSignature(msgTx, signature, pubKey)
 		if err != nil {
 			str := fmt.Sprintf("Mempool invalid TSpend signature: "+
 				"%v", err)
-			return nil, txRuleError(ErrInvalid, str)
+			return nil, nil, nil, txRuleError(ErrInvalid, str)
 		}
 
 		// Verify that this tspend hash has not been included in an
 		// ancestor block yet.
 		if err := mp.cfg.TSpendMinedOnAncestor(*txHash); err != nil {
 			// err is descriptive and only needs to be wrapped.
-			return nil, txRuleError(ErrTSpendMinedOnAncestor, err.Error())
-		}
-
-		// Notify that we accepted a TSpend.
-		if mp.cfg.OnTSpendReceived != nil {
-			mp.cfg.OnTSpendReceived(tx)
+			return nil, nil, nil, txRuleError(ErrTSpendMinedOnAncestor,
+				err.Error())
 		}
 
 		log.Tracef("TSpend allowed in mempool: nbh %v expiry %v "+
@@ -1750,6 +2117,62 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 	txDesc := mp.newTxDesc(utxoView, tx, txType, bestHeight, txFee, totalSigOps,
 		serializedSize)
 
+	return txDesc, utxoView, nil, nil
+}
+
+// maybeAcceptTransaction is the internal function which implements the public
+// MaybeAcceptTransaction.  See the comment for MaybeAcceptTransaction for
+// more details.
+//
+// This function MUST be called with the mempool lock held (for writes).
+//
+// DECRED - TODO
+// We need to make sure thing also assigns the TxType after it evaluates the tx,
+// so that we can easily pick different stake tx types from the mempool later.
+// This should probably be done at the bottom using "IsSStx" etc functions.
+// It should also set the dcrutil tree type for the tx as well.
+func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
+	rejectDupOrphans bool,
+	checkTxFlags blockchain.AgendaFlags) ([]wire.OutPoint, error) {
+
+	txDesc, utxoView, missingParents, err := mp.validateTransaction(tx,
+		allowHighFees, rejectDupOrphans, checkTxFlags)
+	if err != nil || len(missingParents) > 0 {
+		return missingParents, err
+	}
+
+	// Use the transaction from the descriptor since it might have been copied
+	// to update its fraud proof data.
+	tx = txDesc.Tx
+	txHash := tx.Hash()
+	txType := txDesc.Type
+	isVote := txType == stake.TxTypeSSGen
+	isTSpend := checkTxFlags.IsTreasuryEnabled() && txType == stake.TxTypeTSpend
+
+	// Reject evictable transactions without adding them to the pool when it
+	// is full and they do not pay a higher fee rate than the lowest fee rate
+	// package that could be evicted to make room for them, since they would
+	// otherwise immediately be evicted again.
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	if maxSize > 0 && isEvictableTxType(txType) &&
+		mp.poolSize+txDesc.TxSize > maxSize {
+
+		feeRate := float64(txDesc.Fee) * 1000 / float64(txDesc.TxSize)
+		candidate := mp.nextEvictionCandidate()
+		if candidate == nil || feeRate <= candidate.feeRate() {
+			str := fmt.Sprintf("transaction %v with a fee of %d atoms for "+
+				"a %d-byte transaction does not pay a high enough fee to "+
+				"be accepted into the full mempool", txHash, txDesc.Fee,
+				txDesc.TxSize)
+			return nil, txRuleError(ErrMempoolFull, str)
+		}
+	}
+
+	// Notify that we accepted a TSpend.
+	if isTSpend && mp.cfg.OnTSpendReceived != nil {
+		mp.cfg.OnTSpendReceived(tx)
+	}
+
 	// Tickets cannot be included in a block until all inputs have
 	// been approved by stakeholders. Consensus rules dictate that stake
 	// transactions must precede regular transactions, and that inputs for any
@@ -1776,7 +2199,8 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		mp.forEachRedeemer(tx, func(redeemerTxDesc *TxDesc) {
 			if redeemerTxDesc.Type == stake.TxTypeSStx {
 				redeemerTx := redeemerTxDesc.Tx
-				mp.removeTransaction(redeemerTx, true)
+				mp.removeTransaction(redeemerTx, true,
+					RemovalReasonDisapproved)
 				mp.stageTransaction(redeemerTxDesc)
 				log.Debugf("Moved ticket %v dependent on %v into stage pool",
 					redeemerTx.Hash(), txHash)
@@ -1796,6 +2220,19 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		mp.tspends[*txHash] = tx
 	}
 
+	// Evict the lowest fee rate transactions as needed to remain within the
+	// maximum allowed pool size.  The transaction was already checked against
+	// the lowest fee rate package above, but it might still be evicted along
+	// with an ancestor, so reject it when it ends up being one of them.
+	mp.limitPoolSize()
+	if !mp.isTransactionInPool(txHash) {
+		str := fmt.Sprintf("transaction %v with a fee of %d atoms for a "+
+			"%d-byte transaction does not pay a high enough fee to be "+
+			"accepted into the full mempool", txHash, txDesc.Fee,
+			txDesc.TxSize)
+		return nil, txRuleError(ErrMempoolFull, str)
+	}
+
 	log.Debugf("Accepted transaction %v (pool size: %v)", txHash,
 		len(mp.pool))
 
@@ -1915,7 +2352,7 @@ func (mp *TxPool) MaybeAcceptTransactions(txns []*dcrutil.Tx) error {
 		delete(transientPool, *tx.Hash())
 		_, err := mp.maybeAcceptTransaction(tx, false, true, true, checkTxFlags)
 		if err != nil && !isDoubleSpendOrDuplicateError(err) {
-			mp.removeTransaction(tx, true)
+			mp.removeTransaction(tx, true, RemovalReasonDisapproved)
 			continue
 		}
 		if err != nil {
@@ -1934,6 +2371,128 @@ func (mp *TxPool) MaybeAcceptTransactions(txns []*dcrutil.Tx) error {
 	return finalErr
 }
 
+// TestAcceptResult houses the result of testing whether or not a transaction
+// would be accepted to the memory pool via TestAcceptTransactions.
+type TestAcceptResult struct {
+	// Tx is the transaction that was tested.
+	Tx *dcrutil.Tx
+
+	// Fee is the fee paid by the transaction in atoms.  It is only set when
+	// the transaction would be accepted.
+	Fee int64
+
+	// Size is the serialized size of the transaction in bytes.
+	Size int64
+
+	// Err is the reason the transaction would be rejected or nil when it would
+	// be accepted.  It is typically a RuleError.
+	Err error
+}
+
+// TestAcceptTransactions runs the passed transactions through all of the
+// policy and consensus checks that are performed when accepting transactions
+// to the memory pool without adding them to the pool, relaying them, or
+// otherwise modifying the pool.
+//
+// The transactions may spend the outputs of earlier transactions in the
+// provided slice, so any transaction that depends on another one in the slice
+// MUST come after it.  Transactions that spend outputs of transactions in the
+// slice that would be rejected are themselves rejected as orphans.
+//
+// Note that whether or not accepting a transaction would result in it being
+// evicted due to the maximum pool size is not tested beyond ensuring it pays
+// the current dynamic minimum relay fee.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) TestAcceptTransactions(txns []*dcrutil.Tx, allowHighFees bool) ([]*TestAcceptResult, error) {
+	// Create agenda flags for checking transactions based on which ones are
+	// active or should otherwise always be enforced.
+	checkTxFlags, err := mp.determineCheckTxFlags()
+	if err != nil {
+		return nil, err
+	}
+	isTreasuryEnabled := checkTxFlags.IsTreasuryEnabled()
+
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	// The outputs of the transactions that would be accepted are made
+	// available to the transactions that follow them via the transient pool
+	// and removed once all of the transactions have been tested.
+	transientPool := mp.transient
+	var transientHashes []chainhash.Hash
+	defer func() {
+		for i := range transientHashes {
+			delete(transientPool, transientHashes[i])
+		}
+	}()
+
+	spentBy := make(map[wire.OutPoint]*chainhash.Hash)
+	results := make([]*TestAcceptResult, 0, len(txns))
+	for _, tx := range txns {
+		txHash := tx.Hash()
+		msgTx := tx.MsgTx()
+		result := &TestAcceptResult{
+			Tx:   tx,
+			Size: int64(msgTx.SerializeSize()),
+		}
+		results = append(results, result)
+
+		// Reject duplicates and double spends of transactions that would be
+		// accepted earlier in the set since they are not in the pool and
+		// therefore not detected by the pool checks.  Stakebase and treasury
+		// spend inputs are ignored as they do not spend any outputs.
+		if _, ok := transientPool[*txHash]; ok {
+			str := fmt.Sprintf("transaction %v is a duplicate of an earlier "+
+				"transaction in the set", txHash)
+			result.Err = txRuleError(ErrDuplicate, str)
+			continue
+		}
+		txType := stake.DetermineTxType(msgTx)
+		skipFirstInput := txType == stake.TxTypeSSGen ||
+			txType == stake.TxTypeSSRtx ||
+			(isTreasuryEnabled && txType == stake.TxTypeTSpend)
+		for i, txIn := range msgTx.TxIn {
+			if i == 0 && skipFirstInput {
+				continue
+			}
+			if spender, ok := spentBy[txIn.PreviousOutPoint]; ok {
+				str := fmt.Sprintf("transaction %v earlier in the set "+
+					"already spends the same coins", spender)
+				result.Err = txRuleError(ErrMempoolDoubleSpend, str)
+				break
+			}
+		}
+		if result.Err != nil {
+			continue
+		}
+
+		txDesc, _, missingParents, err := mp.validateTransaction(tx,
+			allowHighFees, true, checkTxFlags)
+		if err == nil && len(missingParents) > 0 {
+			str := fmt.Sprintf("transaction %v spends unknown or already "+
+				"spent output %v", txHash, missingParents[0])
+			err = txRuleError(ErrOrphan, str)
+		}
+		if err != nil {
+			result.Err = err
+			continue
+		}
+		result.Fee = txDesc.Fee
+
+		transientPool[*txHash] = txDesc.Tx
+		transientHashes = append(transientHashes, *txHash)
+		for i, txIn := range msgTx.TxIn {
+			if i == 0 && skipFirstInput {
+				continue
+			}
+			spentBy[txIn.PreviousOutPoint] = txHash
+		}
+	}
+
+	return results, nil
+}
+
 // processOrphans is the internal function which implements the public
 // ProcessOrphans.  See the comment for ProcessOrphans for more details.
 //
@@ -1983,7 +2542,7 @@ func (mp *TxPool) processOrphans(acceptedTx *dcrutil.Tx, checkTxFlags blockchain
 					// is no way any other orphans which
 					// redeem any of its outputs can be
 					// accepted.  Remove them.
-					mp.removeOrphan(tx, true)
+					mp.removeOrphan(tx, true, RemovalReasonInvalid)
 					break
 				}
 
@@ -2001,7 +2560,7 @@ func (mp *TxPool) processOrphans(acceptedTx *dcrutil.Tx, checkTxFlags blockchain
 				// transactions to process so any orphans that
 				// depend on it are handled too.
 				acceptedTxns = append(acceptedTxns, tx)
-				mp.removeOrphan(tx, false)
+				mp.unindexOrphan(tx)
 				processList = append(processList, tx)
 
 				// Only one transaction for this outpoint can be
@@ -2032,17 +2591,17 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64, isAutoRevo
 		txType := txDesc.Type
 		if txType == stake.TxTypeSStx &&
 			txDesc.Height+int64(heightDiffToPruneTicket) < height {
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonExpired)
 			continue
 		}
 		if txType == stake.TxTypeSStx &&
 			txDesc.Tx.MsgTx().TxOut[0].Value < requiredStakeDifficulty {
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonExpired)
 			continue
 		}
 		if (txType == stake.TxTypeSSRtx || txType == stake.TxTypeSSGen) &&
 			txDesc.Height+int64(heightDiffToPruneVotes) < height {
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonExpired)
 			continue
 		}
 		if isAutoRevocationsEnabled && txType == stake.TxTypeSSRtx {
@@ -2051,7 +2610,7 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64, isAutoRevo
 			// longer valid and should be removed since they require using the header
 			// of the previous block in order to properly calculate the return
 			// amounts.
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonExpired)
 			continue
 		}
 	}
@@ -2077,7 +2636,7 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64, isAutoRevo
 			// longer valid and should be removed since they require using the header
 			// of the previous block in order to properly calculate the return
 			// amounts.
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonExpired)
 			continue
 		}
 	}
@@ -2114,7 +2673,7 @@ func (mp *TxPool) pruneExpiredTx(height int64) {
 		if blockchain.IsExpired(tx, nextBlockHeight) {
 			log.Debugf("Pruning expired transaction %v from the mempool",
 				tx.Hash())
-			mp.removeTransaction(tx, true)
+			mp.removeTransaction(tx, true, RemovalReasonExpired)
 		}
 	}
 
@@ -2169,6 +2728,28 @@ func (mp *TxPool) ProcessOrphans(acceptedTx *dcrutil.Tx, checkTxFlags blockchain
 //
 // This function is safe for concurrent access.
 func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees bool, tag Tag) ([]*dcrutil.Tx, error) {
+	return mp.processTransaction(tx, allowOrphan, allowHighFees, false, tag)
+}
+
+// ProcessStemTransaction is the same as ProcessTransaction except the passed
+// transaction is marked as being in the stem phase of stem/fluff relay when it
+// is accepted and orphans are never allowed.
+//
+// Stem transactions are excluded from TxHashes, TxDescs, and VerboseTxDescs
+// until they are fluffed via FluffTransaction or removed from the pool.  Any
+// orphans that are accepted as a result of the transaction being accepted are
+// not marked since they were not received in the stem phase.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ProcessStemTransaction(tx *dcrutil.Tx, allowHighFees bool, tag Tag) ([]*dcrutil.Tx, error) {
+	return mp.processTransaction(tx, false, allowHighFees, true, tag)
+}
+
+// processTransaction is the main workhorse for ProcessTransaction and
+// ProcessStemTransaction.  See their comments for more details.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) processTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees, stem bool, tag Tag) ([]*dcrutil.Tx, error) {
 	// Create agenda flags for checking transactions based on which ones are
 	// active or should otherwise always be enforced.
 	checkTxFlags, err := mp.determineCheckTxFlags()
@@ -2194,6 +2775,13 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees
 
 	// If len(missingParents) == 0 then we know the tx is NOT an orphan.
 	if len(missingParents) == 0 {
+		// Mark the transaction as being in the stem phase as needed.  Note
+		// that transactions that are staged rather than added to the main
+		// pool are not advertised either way until they are unstaged.
+		if stem && mp.pool[*tx.Hash()] != nil {
+			mp.stemTxns[*tx.Hash()] = struct{}{}
+		}
+
 		// Accept any orphan transactions that depend on this
 		// transaction (they may no longer be orphans if all inputs
 		// are now available) and repeat for those accepted
@@ -2244,35 +2832,65 @@ func (mp *TxPool) Count() int {
 	return count
 }
 
+// FluffTransaction transitions the transaction with the passed hash from the
+// stem phase to the fluff phase of stem/fluff relay such that it is no longer
+// excluded from the methods that are used to advertise the contents of the
+// pool.  It returns whether or not the transaction was in the stem phase.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) FluffTransaction(hash *chainhash.Hash) bool {
+	mp.mtx.Lock()
+	_, isStem := mp.stemTxns[*hash]
+	delete(mp.stemTxns, *hash)
+	mp.mtx.Unlock()
+
+	return isStem
+}
+
+// IsStemTransaction returns whether or not the transaction with the passed hash
+// is in the main pool and still in the stem phase of stem/fluff relay.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) IsStemTransaction(hash *chainhash.Hash) bool {
+	mp.mtx.RLock()
+	_, isStem := mp.stemTxns[*hash]
+	mp.mtx.RUnlock()
+
+	return isStem
+}
+
 // TxHashes returns a slice of hashes for all of the transactions in the memory
-// pool.
+// pool excluding those in the stem phase of stem/fluff relay.
 //
 // This function is safe for concurrent access.
 func (mp *TxPool) TxHashes() []*chainhash.Hash {
 	mp.mtx.RLock()
-	hashes := make([]*chainhash.Hash, len(mp.pool))
-	i := 0
+	hashes := make([]*chainhash.Hash, 0, len(mp.pool)-len(mp.stemTxns))
 	for hash := range mp.pool {
+		if _, isStem := mp.stemTxns[hash]; isStem {
+			continue
+		}
 		hashCopy := hash
-		hashes[i] = &hashCopy
-		i++
+		hashes = append(hashes, &hashCopy)
 	}
 	mp.mtx.RUnlock()
 
 	return hashes
 }
 
-// TxDescs returns a slice of descriptors for all the transactions in the pool.
-// The descriptors must be treated as read only.
+// TxDescs returns a slice of descriptors for all the transactions in the pool
+// excluding those in the stem phase of stem/fluff relay.  The descriptors must
+// be treated as read only.
 //
 // This function is safe for concurrent access.
 func (mp *TxPool) TxDescs() []*TxDesc {
 	mp.mtx.RLock()
-	descs := make([]*TxDesc, len(mp.pool))
-	i := 0
-	for _, desc := range mp.pool {
-		descs[i] = desc
-		i++
+	descs := make([]*TxDesc, 0, len(mp.pool)-len(mp.stemTxns))
+	for hash, desc := range mp.pool {
+		if _, isStem := mp.stemTxns[hash]; isStem {
+			continue
+		}
+		descs = append(descs, desc)
 	}
 	mp.mtx.RUnlock()
 
@@ -2280,7 +2898,12 @@ func (mp *TxPool) TxDescs() []*TxDesc {
 }
 
 // VerboseTxDescs returns a slice of verbose descriptors for all the
-// transactions in the pool.  The descriptors must be treated as read only.
+// transactions in the pool excluding those in the stem phase of stem/fluff
+// relay.  The descriptors must be treated as read only.
+//
+// The descriptors do not include the ancestor and descendant package details
+// since calculating them for every transaction in the pool is expensive.  Use
+// VerboseTxDesc for the details of a specific transaction instead.
 //
 // Callers should prefer working with the more efficient TxDescs unless they
 // specifically need access to the additional details provided.
@@ -2290,25 +2913,125 @@ func (mp *TxPool) VerboseTxDescs() []*VerboseTxDesc {
 	mp.mtx.RLock()
 	defer mp.mtx.RUnlock()
 
-	result := make([]*VerboseTxDesc, 0, len(mp.pool))
-	for _, desc := range mp.pool {
-		// Create the descriptor and add dependencies as needed.
-		vtxd := &VerboseTxDesc{
-			TxDesc: *desc,
-		}
-		for _, txIn := range desc.Tx.MsgTx().TxIn {
-			hash := &txIn.PreviousOutPoint.Hash
-			if depDesc, ok := mp.pool[*hash]; ok {
-				vtxd.Depends = append(vtxd.Depends, depDesc)
-			}
+	result := make([]*VerboseTxDesc, 0, len(mp.pool)-len(mp.stemTxns))
+	for hash, desc := range mp.pool {
+		if _, isStem := mp.stemTxns[hash]; isStem {
+			continue
 		}
-
-		result = append(result, vtxd)
+		result = append(result, mp.verboseTxDesc(desc))
 	}
 
 	return result
 }
 
+// verboseTxDesc returns a verbose descriptor for the provided transaction in
+// the main pool along with its dependencies.  It does not include the package
+// details.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) verboseTxDesc(desc *TxDesc) *VerboseTxDesc {
+	// Create the descriptor and add dependencies as needed.
+	vtxd := &VerboseTxDesc{
+		TxDesc: *desc,
+	}
+	for _, txIn := range desc.Tx.MsgTx().TxIn {
+		hash := &txIn.PreviousOutPoint.Hash
+		if depDesc, ok := mp.pool[*hash]; ok {
+			vtxd.Depends = append(vtxd.Depends, depDesc)
+		}
+	}
+
+	return vtxd
+}
+
+// verboseTxDescWithPackages returns a verbose descriptor for the provided
+// transaction in the main pool along with the dependency and package details.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) verboseTxDescWithPackages(desc *TxDesc) *VerboseTxDesc {
+	vtxd := mp.verboseTxDesc(desc)
+
+	// Aggregate the details of the full set of ancestors and descendants in
+	// the mining view.
+	txHash := desc.Tx.Hash()
+	mp.miningView.ForEachAncestor(txHash, vtxd.Ancestors.add)
+	mp.miningView.ForEachDescendant(txHash, vtxd.Descendants.add)
+
+	ancestorStats, _ := mp.miningView.AncestorStats(txHash)
+	vtxd.PackageFeeRate = mining.CalcPackageFeeRate(&desc.TxDesc,
+		ancestorStats)
+
+	return vtxd
+}
+
+// VerboseTxDesc returns a verbose descriptor for the transaction with the
+// provided hash in the main pool.  It does not include the orphan or stage
+// pools.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) VerboseTxDesc(txHash *chainhash.Hash) (*VerboseTxDesc, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	desc, exists := mp.pool[*txHash]
+	if !exists {
+		return nil, fmt.Errorf("transaction is not in the pool")
+	}
+	return mp.verboseTxDescWithPackages(desc), nil
+}
+
+// AncestorTxDescs returns verbose descriptors for all transactions in the main
+// pool that the transaction with the provided hash depends on, either directly
+// or indirectly.  Ancestors are ordered such that every transaction comes after
+// any ancestors it depends on.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) AncestorTxDescs(txHash *chainhash.Hash) ([]*VerboseTxDesc, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	if _, exists := mp.pool[*txHash]; !exists {
+		return nil, fmt.Errorf("transaction is not in the pool")
+	}
+
+	var result []*VerboseTxDesc
+	mp.miningView.ForEachAncestor(txHash, func(txDesc *mining.TxDesc) {
+		if desc, ok := mp.pool[*txDesc.Tx.Hash()]; ok {
+			result = append(result, mp.verboseTxDescWithPackages(desc))
+		}
+	})
+	return result, nil
+}
+
+// DescendantTxDescs returns verbose descriptors for all transactions in the
+// main pool that depend on the transaction with the provided hash, either
+// directly or indirectly.  Descendants are ordered such that every transaction
+// comes before any descendants that depend on it.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) DescendantTxDescs(txHash *chainhash.Hash) ([]*VerboseTxDesc, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	if _, exists := mp.pool[*txHash]; !exists {
+		return nil, fmt.Errorf("transaction is not in the pool")
+	}
+
+	var result []*VerboseTxDesc
+	mp.miningView.ForEachDescendant(txHash, func(txDesc *mining.TxDesc) {
+		if desc, ok := mp.pool[*txDesc.Tx.Hash()]; ok {
+			result = append(result, mp.verboseTxDescWithPackages(desc))
+		}
+	})
+
+	// The descendants are visited in post-order, so reverse them to ensure
+	// every transaction comes before the transactions that depend on it.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
 // miningDescs returns a slice of mining descriptors for all transactions
 // in the pool.
 //
@@ -2360,6 +3083,9 @@ func New(cfg *Config) *TxPool {
 		staged:          make(map[chainhash.Hash]*TxDesc),
 		stagedOutpoints: make(map[wire.OutPoint]*TxDesc),
 		transient:       make(map[chainhash.Hash]*dcrutil.Tx),
+		restoredTxns:    make(map[chainhash.Hash]restoredTx),
+		stemTxns:        make(map[chainhash.Hash]struct{}),
+		evictionEntries: make(map[chainhash.Hash]*evictionEntry),
 	}
 
 	// for a given transaction, scan the mempool to find which transactions
@@ -1,5 +1,5 @@
 // Copyright (c) 2016 The btcsuite developers
-// Copyright (c) 2017-2023 The Decred developers
+// Copyright (c) 2017-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"runtime"
 	"sync"
 	"testing"
@@ -1059,7 +1060,7 @@ func TestTicketPurchaseOrphan(t *testing.T) {
 	// in the stage pool to enter the mempool.
 	harness.AddFakeUTXO(tx, int64(ticket.MsgTx().TxIn[0].BlockHeight),
 		wire.NullBlockIndex)
-	harness.txPool.RemoveTransaction(tx, false)
+	harness.txPool.RemoveTransaction(tx, false, RemovalReasonMined)
 	harness.txPool.MaybeAcceptDependents(tx, noTreasury)
 
 	testPoolMembership(tc, tx, false, false)
@@ -1467,7 +1468,7 @@ func TestBasicOrphanRemoval(t *testing.T) {
 		t.Fatalf("unable to create signed tx: %v", err)
 	}
 
-	harness.txPool.RemoveOrphan(nonChainedOrphanTx)
+	harness.txPool.RemoveOrphan(nonChainedOrphanTx, RemovalReasonMined)
 	testPoolMembership(tc, nonChainedOrphanTx, false, false)
 	for _, tx := range chainedTxns[1 : maxOrphans+1] {
 		testPoolMembership(tc, tx, true, false)
@@ -1476,7 +1477,7 @@ func TestBasicOrphanRemoval(t *testing.T) {
 	// Attempt to remove an orphan that has an existing redeemer but itself
 	// is not present and ensure the state of all other orphans (including
 	// the one that redeems it) are unaffected.
-	harness.txPool.RemoveOrphan(chainedTxns[0])
+	harness.txPool.RemoveOrphan(chainedTxns[0], RemovalReasonMined)
 	testPoolMembership(tc, chainedTxns[0], false, false)
 	for _, tx := range chainedTxns[1 : maxOrphans+1] {
 		testPoolMembership(tc, tx, true, false)
@@ -1485,7 +1486,7 @@ func TestBasicOrphanRemoval(t *testing.T) {
 	// Remove each orphan one-by-one and ensure they are removed as
 	// expected.
 	for _, tx := range chainedTxns[1 : maxOrphans+1] {
-		harness.txPool.RemoveOrphan(tx)
+		harness.txPool.RemoveOrphan(tx, RemovalReasonMined)
 		testPoolMembership(tc, tx, false, false)
 	}
 }
@@ -1536,7 +1537,7 @@ func TestOrphanChainRemoval(t *testing.T) {
 	// remove redeemer flag set and ensure that only the first orphan was
 	// removed.
 	harness.txPool.mtx.Lock()
-	harness.txPool.removeOrphan(chainedTxns[1], false)
+	harness.txPool.removeOrphan(chainedTxns[1], false, RemovalReasonMined)
 	harness.txPool.mtx.Unlock()
 	testPoolMembership(tc, chainedTxns[1], false, false)
 	for _, tx := range chainedTxns[2 : maxOrphans+1] {
@@ -1546,7 +1547,7 @@ func TestOrphanChainRemoval(t *testing.T) {
 	// Remove the first remaining orphan that starts the orphan chain with
 	// the remove redeemer flag set and ensure they are all removed.
 	harness.txPool.mtx.Lock()
-	harness.txPool.removeOrphan(chainedTxns[2], true)
+	harness.txPool.removeOrphan(chainedTxns[2], true, RemovalReasonMined)
 	harness.txPool.mtx.Unlock()
 	for _, tx := range chainedTxns[2 : maxOrphans+1] {
 		testPoolMembership(tc, tx, false, false)
@@ -1997,7 +1998,7 @@ func TestMaxVoteDoubleSpendRejection(t *testing.T) {
 	// Remove one of the votes from the pool and ensure it is not in the orphan
 	// pool, not in the transaction pool, and not reported as available.
 	vote := votes[2]
-	harness.txPool.RemoveTransaction(vote, true)
+	harness.txPool.RemoveTransaction(vote, true, RemovalReasonMined)
 	testPoolMembership(tc, vote, false, false)
 
 	// Add one of the votes that was rejected above due to the pool being at the
@@ -2099,7 +2100,7 @@ func TestDuplicateVoteRejection(t *testing.T) {
 
 	// Remove the original vote from the pool and ensure it is not in the orphan
 	// pool, not in the transaction pool, and not reported as available.
-	harness.txPool.RemoveTransaction(vote, true)
+	harness.txPool.RemoveTransaction(vote, true, RemovalReasonMined)
 	testPoolMembership(tc, vote, false, false)
 
 	// Add the duplicate vote which should now be accepted.  Also, ensure it is
@@ -2335,6 +2336,149 @@ func TestRemoveDoubleSpends(t *testing.T) {
 	}
 }
 
+// TestRemovalNotifications ensures the OnTxRemoved callback is invoked with the
+// expected reasons as transactions are removed from the main and orphan pools
+// via the various removal paths.
+func TestRemovalNotifications(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	type removal struct {
+		hash   chainhash.Hash
+		reason RemovalReason
+	}
+	var removals []removal
+	harness.txPool.cfg.OnTxRemoved = func(tx *dcrutil.Tx, reason RemovalReason) {
+		removals = append(removals, removal{*tx.Hash(), reason})
+	}
+	assertRemovals := func(want ...removal) {
+		t.Helper()
+		if len(want) == 0 {
+			want = nil
+		}
+		if !reflect.DeepEqual(removals, want) {
+			t.Fatalf("unexpected removals -- got %v, want %v", removals, want)
+		}
+		removals = nil
+	}
+
+	// Create and add a transaction with several outputs that spends the first
+	// spendable output provided by the harness.  These outputs are used as
+	// the inputs to the transactions that are removed below.
+	multiOutputTx, err := harness.CreateSignedTx([]spendableOutput{
+		spendableOuts[0],
+	}, 3)
+	if err != nil {
+		t.Fatalf("unable to create signed tx: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(multiOutputTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	spendableOuts = []spendableOutput{
+		txOutToSpendableOut(multiOutputTx, 0, wire.TxTreeRegular),
+		txOutToSpendableOut(multiOutputTx, 1, wire.TxTreeRegular),
+		txOutToSpendableOut(multiOutputTx, 2, wire.TxTreeRegular),
+	}
+
+	// Create a chain of two transactions rooted with the first output and add
+	// them to the pool.
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := harness.txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+		}
+		testPoolMembership(tc, tx, false, true)
+	}
+
+	// Create a transaction that spends the same output as the second
+	// transaction in the chain and ensure removing its double spends reports
+	// the second transaction as a double spend.
+	doubleSpendTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(chainedTxns[0], 0, wire.TxTreeRegular),
+	}, 2)
+	if err != nil {
+		t.Fatalf("unable to create signed tx: %v", err)
+	}
+	harness.txPool.RemoveDoubleSpends(doubleSpendTx)
+	testPoolMembership(tc, chainedTxns[1], false, false)
+	assertRemovals(removal{*chainedTxns[1].Hash(), RemovalReasonDoubleSpend})
+
+	// Ensure removing the first transaction in the chain reports the provided
+	// reason.
+	harness.txPool.RemoveTransaction(chainedTxns[0], false, RemovalReasonMined)
+	testPoolMembership(tc, chainedTxns[0], false, false)
+	assertRemovals(removal{*chainedTxns[0].Hash(), RemovalReasonMined})
+
+	// Create and add a transaction that expires in the next block and ensure
+	// pruning expired transactions reports it as expired.
+	nextBlockHeight := harness.chain.BestHeight() + 1
+	expiringTx, err := harness.CreateSignedTx([]spendableOutput{
+		spendableOuts[1],
+	}, 1, func(tx *wire.MsgTx) {
+		tx.Expiry = uint32(nextBlockHeight + 1)
+	})
+	if err != nil {
+		t.Fatalf("unable to create signed tx: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(expiringTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	harness.chain.SetHeight(nextBlockHeight)
+	harness.txPool.PruneExpiredTx(nextBlockHeight)
+	testPoolMembership(tc, expiringTx, false, false)
+	assertRemovals(removal{*expiringTx.Hash(), RemovalReasonExpired})
+
+	// Create a chain of three transactions rooted with the final output and
+	// add the final two as orphans with different tags.
+	orphanChain, err := harness.CreateTxChain(spendableOuts[2], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for i, tx := range orphanChain[1:] {
+		_, err := harness.txPool.ProcessTransaction(tx, true, true, Tag(i+1))
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid orphan: %v",
+				err)
+		}
+		testPoolMembership(tc, tx, true, false)
+	}
+
+	// Ensure removing the orphans by the tag of the first one reports both of
+	// them as evicted since the redeemers are removed as well.
+	harness.txPool.RemoveOrphansByTag(1)
+	testPoolMembership(tc, orphanChain[1], false, false)
+	testPoolMembership(tc, orphanChain[2], false, false)
+	assertRemovals(removal{*orphanChain[2].Hash(), RemovalReasonEvicted},
+		removal{*orphanChain[1].Hash(), RemovalReasonEvicted})
+
+	// Add the final transaction as an orphan again and ensure it is not
+	// reported as removed when it is moved to the main pool once its parents
+	// are added.
+	_, err = harness.txPool.ProcessTransaction(orphanChain[2], true, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid orphan: %v", err)
+	}
+	for _, tx := range orphanChain[:2] {
+		_, err := harness.txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+		}
+	}
+	testPoolMembership(tc, orphanChain[2], false, true)
+	assertRemovals()
+}
+
 // createTSpend creates a treasury spend transaction given the specified
 // parameters. A single output is created that pays to a test OP_TRUE P2SH
 // script.
@@ -2494,7 +2638,7 @@ func TestHandlesTSpends(t *testing.T) {
 
 	// Remove the first tspend from the mempool and assert TSpendHashes()
 	// is working as intended.
-	harness.txPool.RemoveTransaction(tspends[0], true)
+	harness.txPool.RemoveTransaction(tspends[0], true, RemovalReasonMined)
 	testPoolMembership(tc, tspends[0], false, false)
 	assertTSpendHashes(tspends[1:maxTSpends])
 
@@ -2505,7 +2649,7 @@ func TestHandlesTSpends(t *testing.T) {
 	// Remove all tspends from the mempool and ensure TSpendHashes() is
 	// empty again.
 	for _, tx := range tspends[1 : maxTSpends+1] {
-		harness.txPool.RemoveTransaction(tx, true)
+		harness.txPool.RemoveTransaction(tx, true, RemovalReasonMined)
 		testPoolMembership(tc, tx, false, false)
 	}
 	assertTSpendHashes(nil)
@@ -2692,7 +2836,7 @@ func TestHandlesTAdds(t *testing.T) {
 			t.Fatalf("ProcessTransaction: failed to accept valid tadd %v", err)
 		}
 		testPoolMembership(tc, tx, false, true)
-		harness.txPool.RemoveTransaction(tx, true)
+		harness.txPool.RemoveTransaction(tx, true, RemovalReasonMined)
 	}
 
 	// Create a few valid tadds that can enter the mempool. Generate a TAdd
@@ -2839,7 +2983,7 @@ func TestStagedTransactionHeight(t *testing.T) {
 	newBlockHeight := initialBlockHeight + 1
 	harness.AddFakeUTXO(txA, newBlockHeight, wire.NullBlockIndex)
 	harness.chain.SetHeight(newBlockHeight)
-	harness.txPool.RemoveTransaction(txA, false)
+	harness.txPool.RemoveTransaction(txA, false, RemovalReasonMined)
 	harness.txPool.MaybeAcceptDependents(txA, noTreasury)
 
 	poolTxDescs = harness.txPool.TxDescs()
@@ -3297,7 +3441,7 @@ func TestSubsidySplitSemantics(t *testing.T) {
 
 	// Remove the vote from the pool and ensure it is not in the orphan pool,
 	// not in the transaction pool, and not reported as available.
-	harness.txPool.RemoveTransaction(preDCP0010Vote, true)
+	harness.txPool.RemoveTransaction(preDCP0010Vote, true, RemovalReasonMined)
 	testPoolMembership(tc, preDCP0010Vote, false, false)
 
 	// Attempt to add the vote with the original subsidy when the agenda is
@@ -3402,7 +3546,7 @@ func TestSubsidySplitR2Semantics(t *testing.T) {
 
 	// Remove the vote from the pool and ensure it is not in the orphan pool,
 	// not in the transaction pool, and not reported as available.
-	harness.txPool.RemoveTransaction(preDCP0012Vote, true)
+	harness.txPool.RemoveTransaction(preDCP0012Vote, true, RemovalReasonMined)
 	testPoolMembership(tc, preDCP0012Vote, false, false)
 
 	// Attempt to add the vote with the original subsidy when the agenda is
@@ -3487,8 +3631,8 @@ func TestMaybeAcceptTransactions(t *testing.T) {
 	testExpectedAncestorFee(txC, txAFee+txBFee)
 
 	// Remove leading transactions from mempool.
-	txPool.RemoveTransaction(txA, false)
-	txPool.RemoveTransaction(txB, false)
+	txPool.RemoveTransaction(txA, false, RemovalReasonMined)
+	txPool.RemoveTransaction(txB, false, RemovalReasonMined)
 
 	testExpectedAncestorFee(txC, 0)
 
@@ -3501,3 +3645,579 @@ func TestMaybeAcceptTransactions(t *testing.T) {
 
 	testExpectedAncestorFee(txC, txAFee+txBFee)
 }
+
+// testEvictionPackages ensures the incrementally maintained package of every
+// transaction in the main pool that is tracked for eviction matches the package
+// calculated from scratch and that the eviction heap is consistent.
+func testEvictionPackages(t *testing.T, txPool *TxPool) {
+	t.Helper()
+
+	txPool.mtx.Lock()
+	defer txPool.mtx.Unlock()
+
+	if len(txPool.evictionEntries) != len(txPool.pool) {
+		t.Fatalf("unexpected number of eviction entries -- got %d, want %d",
+			len(txPool.evictionEntries), len(txPool.pool))
+	}
+	for txHash, txDesc := range txPool.pool {
+		entry, ok := txPool.evictionEntries[txHash]
+		if !ok {
+			t.Fatalf("transaction %v is not tracked for eviction", txHash)
+		}
+		wantFees, wantSize := txDesc.Fee, txDesc.TxSize
+		txPool.miningView.ForEachDescendant(&txHash, func(desc *mining.TxDesc) {
+			wantFees += desc.Fee
+			wantSize += desc.TxSize
+		})
+		if entry.fees != wantFees || entry.size != wantSize {
+			t.Fatalf("unexpected package for %v -- got fees %d size %d, "+
+				"want fees %d size %d", txHash, entry.fees, entry.size,
+				wantFees, wantSize)
+		}
+		inHeap := entry.index >= 0 && entry.index < len(txPool.evictionHeap) &&
+			txPool.evictionHeap[entry.index] == entry
+		if inHeap != isEvictableTxType(txDesc.Type) {
+			t.Fatalf("unexpected eviction heap membership for %v -- got %v",
+				txHash, inHeap)
+		}
+	}
+}
+
+// TestPoolSizeLimit ensures that the lowest fee rate transaction packages are
+// evicted when the mempool exceeds its maximum size, that transactions which
+// are depended on by protected transactions are never evicted, and that the
+// dynamic minimum relay fee is raised and decays as intended.
+func TestPoolSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	txPool := harness.txPool
+	staticMinFee := txPool.cfg.Policy.MinRelayTxFee
+
+	// addFee returns a function that reduces the value of the first output of
+	// a transaction by the provided amount in order to increase its fee.
+	addFee := func(amount int64) func(*wire.MsgTx) {
+		return func(tx *wire.MsgTx) {
+			tx.TxOut[0].Value -= amount
+		}
+	}
+
+	// Create and accept a transaction that splits the spendable output
+	// provided by the harness into several outputs.
+	splitTx, err := harness.CreateSignedTx(spendableOuts, 5)
+	if err != nil {
+		t.Fatalf("unable to create split transaction: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(splitTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept split tx: %v", err)
+	}
+
+	// Create and accept a low fee transaction that is spent by a ticket, which
+	// is placed in the stage pool.  The low fee transaction must never be
+	// evicted since that would also evict the ticket.
+	protectedTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 4, wire.TxTreeRegular),
+	}, 1)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(protectedTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+	}
+	ticket, err := harness.CreateTicketPurchaseFromTx(protectedTx, 40000)
+	if err != nil {
+		t.Fatalf("unable to create ticket purchase transaction: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(ticket, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept ticket: %v", err)
+	}
+	if !txPool.isTransactionStaged(ticket.Hash()) {
+		t.Fatal("ticket is not in the stage pool")
+	}
+
+	// Create and accept transactions that spend the split outputs with
+	// increasing fees.
+	spendTxns := make([]*dcrutil.Tx, 0, 4)
+	for i := uint32(0); i < 3; i++ {
+		tx, err := harness.CreateSignedTx([]spendableOutput{
+			txOutToSpendableOut(splitTx, i, wire.TxTreeRegular),
+		}, 1, addFee(int64(i)*10000))
+		if err != nil {
+			t.Fatalf("unable to create transaction: %v", err)
+		}
+		_, err = txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+		}
+		spendTxns = append(spendTxns, tx)
+	}
+	if got := txPool.MinRelayTxFee(); got != staticMinFee {
+		t.Fatalf("unexpected min relay fee -- got %v, want %v", got,
+			staticMinFee)
+	}
+
+	// Limit the pool to its current size and add another transaction with a
+	// higher fee.  The lowest fee rate transaction that is not protected must
+	// be evicted and the dynamic minimum relay fee raised.
+	txPool.cfg.Policy.MaxPoolSize = txPool.poolSize
+	highFeeTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 3, wire.TxTreeRegular),
+	}, 1, addFee(30000))
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(highFeeTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept high fee tx: %v", err)
+	}
+	testEvictionPackages(t, txPool)
+	testPoolMembership(tc, highFeeTx, false, true)
+	testPoolMembership(tc, spendTxns[0], false, false)
+	testPoolMembership(tc, spendTxns[1], false, true)
+	testPoolMembership(tc, spendTxns[2], false, true)
+	testPoolMembership(tc, protectedTx, false, true)
+	testPoolMembership(tc, splitTx, false, true)
+	if !txPool.isTransactionStaged(ticket.Hash()) {
+		t.Fatal("ticket is no longer in the stage pool")
+	}
+	if txPool.poolSize > txPool.cfg.Policy.MaxPoolSize {
+		t.Fatalf("pool size %d exceeds the max of %d", txPool.poolSize,
+			txPool.cfg.Policy.MaxPoolSize)
+	}
+	//
+	// Since the evicted transaction paid at least the static minimum relay fee
+	// rate, the dynamic minimum must be at least double the static minimum.
+	dynamicMinFee := txPool.MinRelayTxFee()
+	if dynamicMinFee < staticMinFee*2 {
+		t.Fatalf("min relay fee was not raised -- got %v", dynamicMinFee)
+	}
+
+	// Ensure a transaction that only pays the static minimum relay fee is
+	// rejected due to the raised dynamic minimum relay fee.
+	lowFeeTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 0, wire.TxTreeRegular),
+	}, 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(lowFeeTx, false, true, 0)
+	if !errors.Is(err, ErrInsufficientFee) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrInsufficientFee)
+	}
+	testPoolMembership(tc, lowFeeTx, false, false)
+
+	// Ensure a transaction that pays the dynamic minimum relay fee, but still
+	// pays a lower fee rate than everything else that could be evicted, is
+	// rejected because the pool is full before it is ever added to the pool
+	// as opposed to being added and then evicted again.
+	midFeeTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 0, wire.TxTreeRegular),
+	}, 2, addFee(1000))
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	var numEvicted int
+	txPool.cfg.OnTxRemoved = func(tx *dcrutil.Tx, reason RemovalReason) {
+		if reason == RemovalReasonEvicted {
+			numEvicted++
+		}
+	}
+	_, err = txPool.ProcessTransaction(midFeeTx, false, true, 0)
+	if !errors.Is(err, ErrMempoolFull) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrMempoolFull)
+	}
+	if numEvicted != 0 {
+		t.Fatal("rejected transaction was added to the pool and evicted")
+	}
+	txPool.cfg.OnTxRemoved = nil
+	testPoolMembership(tc, midFeeTx, false, false)
+	testPoolMembership(tc, spendTxns[1], false, true)
+	testPoolMembership(tc, spendTxns[2], false, true)
+	testPoolMembership(tc, highFeeTx, false, true)
+	testEvictionPackages(t, txPool)
+
+	// Ensure the dynamic minimum relay fee decays back to the static minimum
+	// relay fee over time and that querying it does not modify the state that
+	// tracks it since it may be done with only the read lock held.
+	txPool.mtx.Lock()
+	lastUpdate := time.Now().Add(-rollingFeeHalfLife * 4)
+	txPool.lastRollingFeeUpdate = lastUpdate
+	rollingMinFeeRate := txPool.rollingMinFeeRate
+	txPool.mtx.Unlock()
+	if got := txPool.MinRelayTxFee(); got != staticMinFee {
+		t.Fatalf("min relay fee did not decay -- got %v, want %v", got,
+			staticMinFee)
+	}
+	if txPool.rollingMinFeeRate != rollingMinFeeRate ||
+		!txPool.lastRollingFeeUpdate.Equal(lastUpdate) {
+
+		t.Fatal("querying the min relay fee modified the dynamic minimum")
+	}
+}
+
+// TestTestAcceptTransactions ensures that testing whether or not transactions
+// would be accepted to the pool reports the expected results, including for
+// transactions that depend on earlier ones in the set, without modifying the
+// pool.
+func TestTestAcceptTransactions(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// txFee returns the fee paid by the provided transaction.
+	txFee := func(tx *dcrutil.Tx) int64 {
+		var fee int64
+		for _, txIn := range tx.MsgTx().TxIn {
+			fee += txIn.ValueIn
+		}
+		for _, txOut := range tx.MsgTx().TxOut {
+			fee -= txOut.Value
+		}
+		return fee
+	}
+
+	// Create a transaction that splits the spendable output provided by the
+	// harness, a chain of transactions that spends one of the split outputs,
+	// a transaction that double spends the first transaction in the chain, a
+	// transaction that does not pay the minimum required fee, and a
+	// transaction that spends an unknown output.
+	splitTx, err := harness.CreateSignedTx(spendableOuts, 3)
+	if err != nil {
+		t.Fatalf("unable to create split transaction: %v", err)
+	}
+	chainedTxns, err := harness.CreateTxChain(txOutToSpendableOut(splitTx, 0,
+		wire.TxTreeRegular), 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	doubleSpendTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 0, wire.TxTreeRegular),
+	}, 2)
+	if err != nil {
+		t.Fatalf("unable to create double spend transaction: %v", err)
+	}
+	lowFeeTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 1, wire.TxTreeRegular),
+	}, 1, func(tx *wire.MsgTx) { tx.TxOut[0].Value = tx.TxIn[0].ValueIn })
+	if err != nil {
+		t.Fatalf("unable to create low fee transaction: %v", err)
+	}
+	orphanTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(lowFeeTx, 0, wire.TxTreeRegular),
+	}, 1)
+	if err != nil {
+		t.Fatalf("unable to create orphan transaction: %v", err)
+	}
+
+	txns := []*dcrutil.Tx{splitTx, chainedTxns[0], chainedTxns[1],
+		doubleSpendTx, lowFeeTx, orphanTx, chainedTxns[0]}
+	results, err := txPool.TestAcceptTransactions(txns, false)
+	if err != nil {
+		t.Fatalf("TestAcceptTransactions: unexpected error: %v", err)
+	}
+	wantErrs := []error{nil, nil, nil, ErrMempoolDoubleSpend,
+		ErrInsufficientFee, ErrOrphan, ErrDuplicate}
+	if len(results) != len(wantErrs) {
+		t.Fatalf("unexpected number of results -- got %d, want %d",
+			len(results), len(wantErrs))
+	}
+	for i, result := range results {
+		if !errors.Is(result.Err, wantErrs[i]) {
+			t.Fatalf("result %d: unexpected error -- got %v, want %v", i,
+				result.Err, wantErrs[i])
+		}
+		if result.Tx != txns[i] {
+			t.Fatalf("result %d: unexpected transaction %v", i,
+				result.Tx.Hash())
+		}
+		wantSize := int64(txns[i].MsgTx().SerializeSize())
+		if result.Size != wantSize {
+			t.Fatalf("result %d: unexpected size -- got %d, want %d", i,
+				result.Size, wantSize)
+		}
+		var wantFee int64
+		if wantErrs[i] == nil {
+			wantFee = txFee(txns[i])
+		}
+		if result.Fee != wantFee {
+			t.Fatalf("result %d: unexpected fee -- got %d, want %d", i,
+				result.Fee, wantFee)
+		}
+	}
+
+	// Ensure the pool was not modified.
+	if count := txPool.Count(); count != 0 {
+		t.Fatalf("unexpected pool count -- got %d, want 0", count)
+	}
+	if len(txPool.transient) != 0 {
+		t.Fatalf("transient pool was not cleared -- %d entries remain",
+			len(txPool.transient))
+	}
+
+	// Ensure a transaction that is already in the pool is reported as a
+	// duplicate.
+	_, err = txPool.ProcessTransaction(splitTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept split tx: %v", err)
+	}
+	results, err = txPool.TestAcceptTransactions(txns[:2], false)
+	if err != nil {
+		t.Fatalf("TestAcceptTransactions: unexpected error: %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrDuplicate) || results[1].Err != nil {
+		t.Fatalf("unexpected results after adding split tx -- got %v, %v",
+			results[0].Err, results[1].Err)
+	}
+}
+
+// TestVerboseTxDescPackages ensures the verbose descriptors for transactions
+// in the pool report the expected ancestors, descendants, and package fee
+// rates.
+func TestVerboseTxDescPackages(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// Create a transaction that splits the spendable output provided by the
+	// harness along with a chain of transactions that spends one of the split
+	// outputs and add them all to the pool.
+	splitTx, err := harness.CreateSignedTx(spendableOuts, 2)
+	if err != nil {
+		t.Fatalf("unable to create split transaction: %v", err)
+	}
+	chainedTxns, err := harness.CreateTxChain(txOutToSpendableOut(splitTx, 0,
+		wire.TxTreeRegular), 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	txns := append([]*dcrutil.Tx{splitTx}, chainedTxns...)
+	for _, tx := range txns {
+		_, err := txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx %v: %v",
+				tx.Hash(), err)
+		}
+	}
+
+	// descHashes returns the hashes of the transactions in the provided
+	// verbose descriptors.
+	descHashes := func(descs []*VerboseTxDesc) []chainhash.Hash {
+		hashes := make([]chainhash.Hash, 0, len(descs))
+		for _, desc := range descs {
+			hashes = append(hashes, *desc.Tx.Hash())
+		}
+		return hashes
+	}
+
+	// Fetch the verbose descriptors for all of the transactions.
+	descs := make([]*VerboseTxDesc, 0, len(txns))
+	for _, tx := range txns {
+		desc, err := txPool.VerboseTxDesc(tx.Hash())
+		if err != nil {
+			t.Fatalf("VerboseTxDesc: unexpected error: %v", err)
+		}
+		descs = append(descs, desc)
+	}
+
+	// sumStats returns the aggregate statistics of the provided descriptors.
+	sumStats := func(descs []*VerboseTxDesc) PackageStats {
+		var stats PackageStats
+		for _, desc := range descs {
+			stats.add(&desc.TxDesc.TxDesc)
+		}
+		return stats
+	}
+
+	// Ensure the ancestor and descendant statistics and package fee rate of
+	// every transaction account for the rest of the chain.
+	for i, desc := range descs {
+		wantAncestors := sumStats(descs[:i])
+		if desc.Ancestors != wantAncestors {
+			t.Fatalf("tx %d: unexpected ancestor stats -- got %+v, want %+v",
+				i, desc.Ancestors, wantAncestors)
+		}
+		wantDescendants := sumStats(descs[i+1:])
+		if desc.Descendants != wantDescendants {
+			t.Fatalf("tx %d: unexpected descendant stats -- got %+v, want "+
+				"%+v", i, desc.Descendants, wantDescendants)
+		}
+		wantRate := float64(desc.Fee+wantAncestors.Fees) * 1000 /
+			float64(desc.TxSize+wantAncestors.Size)
+		if desc.PackageFeeRate != wantRate {
+			t.Fatalf("tx %d: unexpected package fee rate -- got %v, want %v",
+				i, desc.PackageFeeRate, wantRate)
+		}
+	}
+
+	// Ensure the descriptors for all transactions in the pool only include the
+	// dependencies and not the package details, which are expensive to
+	// calculate for every transaction.
+	for _, desc := range txPool.VerboseTxDescs() {
+		if desc.Ancestors != (PackageStats{}) ||
+			desc.Descendants != (PackageStats{}) || desc.PackageFeeRate != 0 {
+
+			t.Fatalf("unexpected package details for %v", desc.Tx.Hash())
+		}
+	}
+
+	// Ensure the ancestors are returned such that every transaction comes
+	// after the transactions it depends on.
+	ancestors, err := txPool.AncestorTxDescs(chainedTxns[2].Hash())
+	if err != nil {
+		t.Fatalf("AncestorTxDescs: unexpected error: %v", err)
+	}
+	wantHashes := []chainhash.Hash{*splitTx.Hash(), *chainedTxns[0].Hash(),
+		*chainedTxns[1].Hash()}
+	if got := descHashes(ancestors); !reflect.DeepEqual(got, wantHashes) {
+		t.Fatalf("unexpected ancestors -- got %v, want %v", got, wantHashes)
+	}
+
+	// Ensure the descendants are returned such that every transaction comes
+	// before the transactions that depend on it.
+	descendants, err := txPool.DescendantTxDescs(splitTx.Hash())
+	if err != nil {
+		t.Fatalf("DescendantTxDescs: unexpected error: %v", err)
+	}
+	wantHashes = []chainhash.Hash{*chainedTxns[0].Hash(),
+		*chainedTxns[1].Hash(), *chainedTxns[2].Hash()}
+	if got := descHashes(descendants); !reflect.DeepEqual(got, wantHashes) {
+		t.Fatalf("unexpected descendants -- got %v, want %v", got,
+			wantHashes)
+	}
+
+	// Ensure transactions that are not in the pool are rejected.
+	unknownHash := chainhash.Hash{0x01}
+	if _, err := txPool.VerboseTxDesc(&unknownHash); err == nil {
+		t.Fatal("VerboseTxDesc: did not fail for unknown transaction")
+	}
+	if _, err := txPool.AncestorTxDescs(&unknownHash); err == nil {
+		t.Fatal("AncestorTxDescs: did not fail for unknown transaction")
+	}
+	if _, err := txPool.DescendantTxDescs(&unknownHash); err == nil {
+		t.Fatal("DescendantTxDescs: did not fail for unknown transaction")
+	}
+}
+
+// TestStemTransactions ensures transactions in the stem phase of stem/fluff
+// relay are excluded from the advertised pool contents until they are fluffed
+// and that they stop being tracked when they are removed from the pool.
+func TestStemTransactions(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// Create a chain of transactions.  The first is added normally, the
+	// second is added in the stem phase, and the third is used as an orphan.
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(chainedTxns[0], false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+	}
+
+	// Ensure stem transactions are never accepted as orphans.
+	_, err = txPool.ProcessStemTransaction(chainedTxns[2], true, 0)
+	if !errors.Is(err, ErrOrphan) {
+		t.Fatalf("ProcessStemTransaction: unexpected error -- got %v, want %v",
+			err, ErrOrphan)
+	}
+	if txPool.IsOrphanInPool(chainedTxns[2].Hash()) {
+		t.Fatal("ProcessStemTransaction: stem tx added to orphan pool")
+	}
+
+	stemTx := chainedTxns[1]
+	_, err = txPool.ProcessStemTransaction(stemTx, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessStemTransaction: failed to accept tx: %v", err)
+	}
+
+	// advertised returns whether or not the passed transaction is included in
+	// each of the methods that are used to advertise the pool contents.
+	advertised := func(tx *dcrutil.Tx) (bool, bool, bool) {
+		var inHashes, inDescs, inVerbose bool
+		for _, hash := range txPool.TxHashes() {
+			inHashes = inHashes || *hash == *tx.Hash()
+		}
+		for _, desc := range txPool.TxDescs() {
+			inDescs = inDescs || *desc.Tx.Hash() == *tx.Hash()
+		}
+		for _, desc := range txPool.VerboseTxDescs() {
+			inVerbose = inVerbose || *desc.Tx.Hash() == *tx.Hash()
+		}
+		return inHashes, inDescs, inVerbose
+	}
+
+	// Ensure the stem transaction is in the pool, but not advertised, while
+	// the normal transaction is advertised.
+	if !txPool.HaveTransaction(stemTx.Hash()) {
+		t.Fatal("HaveTransaction: stem tx not in pool")
+	}
+	if !txPool.IsStemTransaction(stemTx.Hash()) {
+		t.Fatal("IsStemTransaction: stem tx not marked as stem")
+	}
+	if txPool.IsStemTransaction(chainedTxns[0].Hash()) {
+		t.Fatal("IsStemTransaction: normal tx marked as stem")
+	}
+	h, d, v := advertised(stemTx)
+	if h || d || v {
+		t.Fatalf("stem tx advertised (hashes %v, descs %v, verbose %v)", h,
+			d, v)
+	}
+	h, d, v = advertised(chainedTxns[0])
+	if !h || !d || !v {
+		t.Fatalf("normal tx not advertised (hashes %v, descs %v, verbose %v)",
+			h, d, v)
+	}
+
+	// Ensure the stem transaction is advertised once it is fluffed and that
+	// fluffing it again reports it was not in the stem phase.
+	if !txPool.FluffTransaction(stemTx.Hash()) {
+		t.Fatal("FluffTransaction: stem tx not reported as stem")
+	}
+	if txPool.IsStemTransaction(stemTx.Hash()) {
+		t.Fatal("IsStemTransaction: fluffed tx still marked as stem")
+	}
+	h, d, v = advertised(stemTx)
+	if !h || !d || !v {
+		t.Fatalf("fluffed tx not advertised (hashes %v, descs %v, verbose %v)",
+			h, d, v)
+	}
+	if txPool.FluffTransaction(stemTx.Hash()) {
+		t.Fatal("FluffTransaction: fluffed tx reported as stem")
+	}
+
+	// Ensure stem transactions are no longer tracked once they are removed
+	// from the pool.
+	txPool.RemoveTransaction(stemTx, true, RemovalReasonMined)
+	_, err = txPool.ProcessStemTransaction(stemTx, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessStemTransaction: failed to accept tx: %v", err)
+	}
+	txPool.RemoveTransaction(stemTx, true, RemovalReasonMined)
+	if txPool.IsStemTransaction(stemTx.Hash()) {
+		t.Fatal("IsStemTransaction: removed tx still marked as stem")
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// dumpVersion is the current version of the serialized mempool dump
+	// format.
+	dumpVersion = 1
+
+	// maxDumpEntries is the maximum number of transactions a mempool dump is
+	// allowed to contain.  It is only a sanity check to avoid reading
+	// excessively large corrupted dumps.
+	maxDumpEntries = 10000000
+)
+
+// ErrUnsupportedDumpVersion indicates a mempool dump was written with a format
+// version that is not supported.
+var ErrUnsupportedDumpVersion = errors.New("unsupported mempool dump version")
+
+// restoredTx houses the details about when a transaction that is being
+// restored from a mempool dump was originally added to the mempool.
+type restoredTx struct {
+	added  time.Time
+	height int64
+}
+
+// LoadDumpStats houses statistics about the transactions processed while
+// loading a mempool dump.
+type LoadDumpStats struct {
+	// Accepted is the number of transactions that were accepted to the
+	// mempool.
+	Accepted int
+
+	// Expired is the number of transactions that were skipped because they
+	// have expired.
+	Expired int
+
+	// Stale is the number of transactions that were skipped because they are
+	// already in the pool, have already been confirmed, or spend outputs that
+	// are no longer available.
+	Stale int
+
+	// Rejected is the number of transactions that were rejected for any other
+	// reason.
+	Rejected int
+}
+
+// WriteDump serializes all transactions in the main and stage pools along with
+// the time and block height at which each was added to the pool to the
+// provided writer.  Transactions are written after any transactions in the
+// dump that they depend on so they may be restored in order via LoadDump.
+//
+// Transactions that are still in the stem phase of stem/fluff relay, along
+// with any transactions that spend their outputs, are not written since
+// restoring them would result in them being advertised before they are
+// fluffed.
+//
+// The serialized format is:
+//
+//	<version><num entries><entry 1>...<entry n>
+//
+//	Field          Type      Size
+//	version        uint32    4 bytes
+//	num entries    uint64    8 bytes
+//	entries        []entry   variable
+//
+//	Entry:
+//	added          int64     8 bytes (unix seconds)
+//	height         int64     8 bytes
+//	transaction    MsgTx     variable
+//
+// All integers are encoded in little endian.  It returns the number of
+// transactions written.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) WriteDump(w io.Writer) (int, error) {
+	mp.mtx.RLock()
+	descs := make(map[chainhash.Hash]*TxDesc, len(mp.pool)+len(mp.staged))
+	for hash, desc := range mp.pool {
+		if _, isStem := mp.stemTxns[hash]; isStem {
+			continue
+		}
+		descs[hash] = desc
+	}
+	for hash, desc := range mp.staged {
+		descs[hash] = desc
+	}
+	stemTxns := make(map[chainhash.Hash]struct{}, len(mp.stemTxns))
+	for hash := range mp.stemTxns {
+		stemTxns[hash] = struct{}{}
+	}
+	mp.mtx.RUnlock()
+
+	// Order the transactions by the time they were added and then ensure any
+	// parents in the dump precede the transactions that spend them.
+	byAdded := make([]*TxDesc, 0, len(descs))
+	for _, desc := range descs {
+		byAdded = append(byAdded, desc)
+	}
+	sort.Slice(byAdded, func(i, j int) bool {
+		return byAdded[i].Added.Before(byAdded[j].Added)
+	})
+	//
+	// Transactions that spend the outputs of stem transactions, either
+	// directly or through other transactions that are skipped, are skipped
+	// as well.  Since parents are always visited first, this only requires
+	// checking the immediate parents.
+	ordered := make([]*TxDesc, 0, len(descs))
+	visited := make(map[chainhash.Hash]struct{}, len(descs))
+	var visit func(desc *TxDesc)
+	visit = func(desc *TxDesc) {
+		txHash := *desc.Tx.Hash()
+		if _, ok := visited[txHash]; ok {
+			return
+		}
+		visited[txHash] = struct{}{}
+		for _, txIn := range desc.Tx.MsgTx().TxIn {
+			if parent, ok := descs[txIn.PreviousOutPoint.Hash]; ok {
+				visit(parent)
+			}
+		}
+		for _, txIn := range desc.Tx.MsgTx().TxIn {
+			if _, ok := stemTxns[txIn.PreviousOutPoint.Hash]; ok {
+				stemTxns[txHash] = struct{}{}
+				return
+			}
+		}
+		ordered = append(ordered, desc)
+	}
+	for _, desc := range byAdded {
+		visit(desc)
+	}
+
+	bw := bufio.NewWriter(w)
+	var buf [16]byte
+	binary.LittleEndian.PutUint32(buf[:4], dumpVersion)
+	binary.LittleEndian.PutUint64(buf[4:12], uint64(len(ordered)))
+	if _, err := bw.Write(buf[:12]); err != nil {
+		return 0, err
+	}
+	for _, desc := range ordered {
+		binary.LittleEndian.PutUint64(buf[:8], uint64(desc.Added.Unix()))
+		binary.LittleEndian.PutUint64(buf[8:16], uint64(desc.Height))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return 0, err
+		}
+		if err := desc.Tx.MsgTx().Serialize(bw); err != nil {
+			return 0, err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+
+	return len(ordered), nil
+}
+
+// LoadDump reads a mempool dump that was previously written via WriteDump from
+// the provided reader and processes each transaction it contains through
+// ProcessTransaction.  Transactions that have expired as of the next block or
+// are otherwise stale are skipped.
+//
+// Each accepted transaction retains the original time and block height at
+// which it was added to the pool, which, in turn, allows fee estimation to
+// account for the entire amount of time the transaction has been waiting to
+// be mined.
+//
+// The provided interrupt channel may be used to stop loading early, in which
+// case the statistics for the transactions processed so far are returned.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) LoadDump(r io.Reader, interrupt <-chan struct{}) (*LoadDumpStats, error) {
+	br := bufio.NewReader(r)
+	var buf [16]byte
+	if _, err := io.ReadFull(br, buf[:12]); err != nil {
+		return nil, fmt.Errorf("unable to read mempool dump header: %w", err)
+	}
+	version := binary.LittleEndian.Uint32(buf[:4])
+	if version != dumpVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedDumpVersion, version)
+	}
+	numEntries := binary.LittleEndian.Uint64(buf[4:12])
+	if numEntries > maxDumpEntries {
+		return nil, fmt.Errorf("mempool dump has %d entries which exceeds "+
+			"the max allowed of %d", numEntries, maxDumpEntries)
+	}
+
+	var stats LoadDumpStats
+	for i := uint64(0); i < numEntries; i++ {
+		select {
+		case <-interrupt:
+			return &stats, nil
+		default:
+		}
+
+		if _, err := io.ReadFull(br, buf[:]); err != nil {
+			return &stats, fmt.Errorf("unable to read mempool dump entry "+
+				"%d: %w", i, err)
+		}
+		added := time.Unix(int64(binary.LittleEndian.Uint64(buf[:8])), 0)
+		height := int64(binary.LittleEndian.Uint64(buf[8:16]))
+		var msgTx wire.MsgTx
+		if err := msgTx.Deserialize(br); err != nil {
+			return &stats, fmt.Errorf("unable to read mempool dump "+
+				"transaction %d: %w", i, err)
+		}
+		tx := dcrutil.NewTx(&msgTx)
+
+		// Skip transactions that have expired since the dump was written.
+		nextBlockHeight := mp.cfg.BestHeight() + 1
+		if blockchain.IsExpired(tx, nextBlockHeight) {
+			stats.Expired++
+			continue
+		}
+
+		// Process the transaction while retaining the original time and
+		// height it was added to the pool.
+		txHash := *tx.Hash()
+		mp.mtx.Lock()
+		mp.restoredTxns[txHash] = restoredTx{added: added, height: height}
+		mp.mtx.Unlock()
+		_, err := mp.ProcessTransaction(tx, false, true, 0)
+		mp.mtx.Lock()
+		delete(mp.restoredTxns, txHash)
+		mp.mtx.Unlock()
+		switch {
+		case err == nil:
+			stats.Accepted++
+
+		case errors.Is(err, ErrDuplicate), errors.Is(err, ErrAlreadyExists),
+			errors.Is(err, ErrOrphan):
+
+			stats.Stale++
+
+		default:
+			log.Debugf("Unable to restore transaction %v from mempool "+
+				"dump: %v", txHash, err)
+			stats.Rejected++
+		}
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestDumpRoundTrip ensures that transactions written to a mempool dump are
+// restored with their original added time and height, that dependent
+// transactions are restored regardless of the order they were added, and that
+// expired and already known transactions are skipped.
+func TestDumpRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// Create and accept a transaction that splits the spendable output
+	// provided by the harness, a chain of transactions that spends one of the
+	// split outputs, and a transaction that expires in the next block.
+	splitTx, err := harness.CreateSignedTx(spendableOuts, 2)
+	if err != nil {
+		t.Fatalf("unable to create split transaction: %v", err)
+	}
+	chainedTxns, err := harness.CreateTxChain(txOutToSpendableOut(splitTx, 0,
+		wire.TxTreeRegular), 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	expiry := uint32(harness.chain.BestHeight() + 2)
+	expiringTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 1, wire.TxTreeRegular),
+	}, 1, func(tx *wire.MsgTx) { tx.Expiry = expiry })
+	if err != nil {
+		t.Fatalf("unable to create expiring transaction: %v", err)
+	}
+	allTxns := append([]*dcrutil.Tx{splitTx}, chainedTxns...)
+	allTxns = append(allTxns, expiringTx)
+	for _, tx := range allTxns {
+		_, err := txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx %v: %v",
+				tx.Hash(), err)
+		}
+	}
+
+	// Modify the added times so the descendants in the chain appear to have
+	// been added before their ancestors to ensure the dump is written in
+	// dependency order.
+	baseTime := time.Unix(time.Now().Unix()-3600, 0)
+	origHeight := harness.chain.BestHeight()
+	for i, tx := range chainedTxns {
+		desc := txPool.pool[*tx.Hash()]
+		desc.Added = baseTime.Add(-time.Duration(i) * time.Minute)
+	}
+
+	var buf bytes.Buffer
+	numWritten, err := txPool.WriteDump(&buf)
+	if err != nil {
+		t.Fatalf("WriteDump: unexpected error: %v", err)
+	}
+	if numWritten != len(allTxns) {
+		t.Fatalf("WriteDump: unexpected number of txns written -- got %d, "+
+			"want %d", numWritten, len(allTxns))
+	}
+	dump := buf.Bytes()
+
+	// Remove everything but the split transaction from the pool and advance
+	// the chain so the expiring transaction is expired.
+	txPool.RemoveTransaction(chainedTxns[0], true, RemovalReasonMined)
+	txPool.RemoveTransaction(expiringTx, true, RemovalReasonMined)
+	harness.chain.SetHeight(origHeight + 1)
+
+	stats, err := txPool.LoadDump(bytes.NewReader(dump), nil)
+	if err != nil {
+		t.Fatalf("LoadDump: unexpected error: %v", err)
+	}
+	wantStats := LoadDumpStats{
+		Accepted: len(chainedTxns),
+		Expired:  1,
+		Stale:    1,
+	}
+	if *stats != wantStats {
+		t.Fatalf("LoadDump: unexpected stats -- got %+v, want %+v", *stats,
+			wantStats)
+	}
+	for i, tx := range chainedTxns {
+		desc, ok := txPool.pool[*tx.Hash()]
+		if !ok {
+			t.Fatalf("tx %d was not restored to the pool", i)
+		}
+		wantAdded := baseTime.Add(-time.Duration(i) * time.Minute)
+		if !desc.Added.Equal(wantAdded) {
+			t.Fatalf("tx %d: unexpected added time -- got %v, want %v", i,
+				desc.Added, wantAdded)
+		}
+		if desc.Height != origHeight {
+			t.Fatalf("tx %d: unexpected height -- got %d, want %d", i,
+				desc.Height, origHeight)
+		}
+	}
+	if txPool.HaveTransaction(expiringTx.Hash()) {
+		t.Fatal("expired transaction was restored to the pool")
+	}
+	if len(txPool.restoredTxns) != 0 {
+		t.Fatalf("restored transaction tracking was not cleared -- %d "+
+			"entries remain", len(txPool.restoredTxns))
+	}
+
+	// Ensure dumps with an unsupported version are rejected.
+	dump[0]++
+	_, err = txPool.LoadDump(bytes.NewReader(dump), nil)
+	if !errors.Is(err, ErrUnsupportedDumpVersion) {
+		t.Fatalf("LoadDump: unexpected error -- got %v, want %v", err,
+			ErrUnsupportedDumpVersion)
+	}
+}
+
+// TestDumpSkipsStemTransactions ensures that transactions in the stem phase of
+// stem/fluff relay and the transactions that spend their outputs are not
+// written to a mempool dump so they are not restored as normal transactions.
+func TestDumpSkipsStemTransactions(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// Create and accept a transaction that splits the spendable output
+	// provided by the harness, a chain of transactions that spends one of the
+	// split outputs where the first is in the stem phase, and an unrelated
+	// transaction that spends the other split output.
+	splitTx, err := harness.CreateSignedTx(spendableOuts, 2)
+	if err != nil {
+		t.Fatalf("unable to create split transaction: %v", err)
+	}
+	chainedTxns, err := harness.CreateTxChain(txOutToSpendableOut(splitTx, 0,
+		wire.TxTreeRegular), 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	otherTx, err := harness.CreateSignedTx([]spendableOutput{
+		txOutToSpendableOut(splitTx, 1, wire.TxTreeRegular),
+	}, 1)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	stemTx, stemChildTx := chainedTxns[0], chainedTxns[1]
+	for _, tx := range []*dcrutil.Tx{splitTx, stemTx, stemChildTx, otherTx} {
+		if tx == stemTx {
+			_, err = txPool.ProcessStemTransaction(tx, true, 0)
+		} else {
+			_, err = txPool.ProcessTransaction(tx, false, true, 0)
+		}
+		if err != nil {
+			t.Fatalf("failed to accept tx %v: %v", tx.Hash(), err)
+		}
+	}
+
+	var buf bytes.Buffer
+	numWritten, err := txPool.WriteDump(&buf)
+	if err != nil {
+		t.Fatalf("WriteDump: unexpected error: %v", err)
+	}
+	if numWritten != 2 {
+		t.Fatalf("WriteDump: unexpected number of txns written -- got %d, "+
+			"want 2", numWritten)
+	}
+
+	// Remove everything but the split transaction from the pool and ensure
+	// only the unrelated transaction is restored.
+	txPool.RemoveTransaction(stemTx, true, RemovalReasonMined)
+	txPool.RemoveTransaction(otherTx, true, RemovalReasonMined)
+	stats, err := txPool.LoadDump(&buf, nil)
+	if err != nil {
+		t.Fatalf("LoadDump: unexpected error: %v", err)
+	}
+	wantStats := LoadDumpStats{Accepted: 1, Stale: 1}
+	if *stats != wantStats {
+		t.Fatalf("LoadDump: unexpected stats -- got %+v, want %+v", *stats,
+			wantStats)
+	}
+	if !txPool.HaveTransaction(otherTx.Hash()) {
+		t.Fatal("unrelated transaction was not restored to the pool")
+	}
+	for _, tx := range []*dcrutil.Tx{stemTx, stemChildTx} {
+		if txPool.HaveTransaction(tx.Hash()) {
+			t.Fatalf("tx %v was restored to the pool", tx.Hash())
+		}
+	}
+}
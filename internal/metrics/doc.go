@@ -0,0 +1,22 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package metrics provides a minimal set of metric types that are exposed via
+HTTP using the Prometheus text exposition format.
+
+Tests are included to ensure proper functionality.
+
+# Feature Overview
+
+The following are the primary features provided:
+
+  - Counters, gauges, and histograms that are safe for concurrent access
+  - Collections of counters and histograms distinguished by label values
+  - Collectors that calculate metrics on demand when they are requested
+  - A registry that gathers the metrics from all registered collectors
+  - An HTTP handler that serves the gathered metrics using the Prometheus text
+    exposition format
+*/
+package metrics
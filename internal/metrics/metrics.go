@@ -0,0 +1,507 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MetricType identifies the type of the samples in a metric family.
+type MetricType string
+
+// These constants define the supported metric types.
+const (
+	// CounterType identifies a cumulative value that only ever increases.
+	CounterType MetricType = "counter"
+
+	// GaugeType identifies a value that may arbitrarily go up and down.
+	GaugeType MetricType = "gauge"
+
+	// HistogramType identifies a distribution of observed values that are
+	// counted in configurable buckets.
+	HistogramType MetricType = "histogram"
+)
+
+// DefaultDurationBuckets are the default histogram buckets, in seconds, that
+// are suitable for measuring the duration of typical operations such as
+// network requests.
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1,
+	2.5, 5, 10}
+
+// Label is a name and value pair that identifies a sample within a metric
+// family.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric family.
+type Sample struct {
+	// Suffix is appended to the family name to form the name of the sample.
+	// It is only used by histograms for the bucket, sum, and count samples.
+	Suffix string
+
+	// Labels are the labels that identify the sample within the family.
+	Labels []Label
+
+	// Value is the current value of the sample.
+	Value float64
+}
+
+// Family is a group of samples that share a name, help text, and type.
+type Family struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Collector is the interface implemented by anything that provides metric
+// families when they are requested.
+type Collector interface {
+	// Collect returns the current state of all metric families provided by
+	// the collector.
+	Collect() []*Family
+}
+
+// CollectorFunc is an adapter that allows an ordinary function to be used as a
+// Collector.  This is useful for metrics that are calculated on demand from
+// the state of other subsystems.
+type CollectorFunc func() []*Family
+
+// Collect calls f() and returns the result.
+//
+// This is part of the Collector interface implementation.
+func (f CollectorFunc) Collect() []*Family {
+	return f()
+}
+
+// atomicFloat64 provides an atomic float64.
+type atomicFloat64 struct {
+	bits atomic.Uint64
+}
+
+// Load atomically loads and returns the value.
+func (f *atomicFloat64) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// Store atomically stores the provided value.
+func (f *atomicFloat64) Store(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+// Add atomically adds the provided delta to the value.
+func (f *atomicFloat64) Add(delta float64) {
+	for {
+		oldBits := f.bits.Load()
+		newBits := math.Float64bits(math.Float64frombits(oldBits) + delta)
+		if f.bits.CompareAndSwap(oldBits, newBits) {
+			return
+		}
+	}
+}
+
+// Counter is a metric whose value only ever increases.
+//
+// It is safe for concurrent access.
+type Counter struct {
+	name   string
+	help   string
+	labels []Label
+	value  atomicFloat64
+}
+
+// NewCounter returns a new counter with the provided name and help text.
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help}
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add increases the counter by the provided value.  Negative values are
+// ignored since counters only ever increase.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	c.value.Add(v)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() float64 {
+	return c.value.Load()
+}
+
+// sample returns the current sample of the counter.
+func (c *Counter) sample() Sample {
+	return Sample{Labels: c.labels, Value: c.value.Load()}
+}
+
+// Collect returns the metric family for the counter.
+//
+// This is part of the Collector interface implementation.
+func (c *Counter) Collect() []*Family {
+	return []*Family{{
+		Name:    c.name,
+		Help:    c.help,
+		Type:    CounterType,
+		Samples: []Sample{c.sample()},
+	}}
+}
+
+// Gauge is a metric whose value may arbitrarily go up and down.
+//
+// It is safe for concurrent access.
+type Gauge struct {
+	name  string
+	help  string
+	value atomicFloat64
+}
+
+// NewGauge returns a new gauge with the provided name and help text.
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{name: name, help: help}
+}
+
+// Set sets the gauge to the provided value.
+func (g *Gauge) Set(v float64) {
+	g.value.Store(v)
+}
+
+// Add adds the provided value, which may be negative, to the gauge.
+func (g *Gauge) Add(v float64) {
+	g.value.Add(v)
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	return g.value.Load()
+}
+
+// Collect returns the metric family for the gauge.
+//
+// This is part of the Collector interface implementation.
+func (g *Gauge) Collect() []*Family {
+	return []*Family{{
+		Name:    g.name,
+		Help:    g.help,
+		Type:    GaugeType,
+		Samples: []Sample{{Value: g.value.Load()}},
+	}}
+}
+
+// Histogram is a metric that counts observed values in buckets along with
+// their total sum and count.
+//
+// It is safe for concurrent access.
+type Histogram struct {
+	name    string
+	help    string
+	labels  []Label
+	buckets []float64
+
+	mtx    sync.Mutex
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram returns a new histogram with the provided name, help text, and
+// bucket upper bounds.  The buckets are sorted and an implicit bucket for
+// positive infinity is always included.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return newHistogram(name, help, nil, buckets)
+}
+
+// newHistogram returns a new histogram with the provided name, help text,
+// labels, and bucket upper bounds.
+func newHistogram(name, help string, labels []Label, buckets []float64) *Histogram {
+	sorted := slices.Clone(buckets)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	if n := len(sorted); n > 0 && math.IsInf(sorted[n-1], 1) {
+		sorted = sorted[:n-1]
+	}
+	return &Histogram{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: sorted,
+		counts:  make([]uint64, len(sorted)),
+	}
+}
+
+// Observe adds the provided value to the histogram.
+func (h *Histogram) Observe(v float64) {
+	idx, _ := slices.BinarySearch(h.buckets, v)
+
+	h.mtx.Lock()
+	if idx < len(h.counts) {
+		h.counts[idx]++
+	}
+	h.sum += v
+	h.count++
+	h.mtx.Unlock()
+}
+
+// Count returns the total number of values observed by the histogram.
+func (h *Histogram) Count() uint64 {
+	h.mtx.Lock()
+	count := h.count
+	h.mtx.Unlock()
+	return count
+}
+
+// samples returns the current bucket, sum, and count samples of the
+// histogram.
+func (h *Histogram) samples() []Sample {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	samples := make([]Sample, 0, len(h.buckets)+3)
+	var cumulative uint64
+	for i, upperBound := range h.buckets {
+		cumulative += h.counts[i]
+		samples = append(samples, Sample{
+			Suffix: "_bucket",
+			Labels: withLabel(h.labels, "le", formatFloat(upperBound)),
+			Value:  float64(cumulative),
+		})
+	}
+	samples = append(samples, Sample{
+		Suffix: "_bucket",
+		Labels: withLabel(h.labels, "le", "+Inf"),
+		Value:  float64(h.count),
+	}, Sample{
+		Suffix: "_sum",
+		Labels: h.labels,
+		Value:  h.sum,
+	}, Sample{
+		Suffix: "_count",
+		Labels: h.labels,
+		Value:  float64(h.count),
+	})
+	return samples
+}
+
+// Collect returns the metric family for the histogram.
+//
+// This is part of the Collector interface implementation.
+func (h *Histogram) Collect() []*Family {
+	return []*Family{{
+		Name:    h.name,
+		Help:    h.help,
+		Type:    HistogramType,
+		Samples: h.samples(),
+	}}
+}
+
+// withLabel returns a new slice of labels that consists of the provided labels
+// with an additional label appended.
+func withLabel(labels []Label, name, value string) []Label {
+	result := make([]Label, len(labels), len(labels)+1)
+	copy(result, labels)
+	return append(result, Label{Name: name, Value: value})
+}
+
+// vec houses a collection of metrics of the same kind that share a name and
+// are distinguished by the values of a fixed set of labels.
+type vec[T any] struct {
+	labelNames []string
+	newMetric  func(labels []Label) T
+
+	mtx     sync.Mutex
+	metrics map[string]T
+	keys    []string
+}
+
+// with returns the metric for the provided label values, creating it when it
+// does not already exist.  It panics when the number of label values does not
+// match the number of label names since that is a programming error.
+func (v *vec[T]) with(labelValues ...string) T {
+	if len(labelValues) != len(v.labelNames) {
+		panic("metrics: mismatched number of label values")
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if metric, ok := v.metrics[key]; ok {
+		return metric
+	}
+	labels := make([]Label, len(labelValues))
+	for i, value := range labelValues {
+		labels[i] = Label{Name: v.labelNames[i], Value: value}
+	}
+	metric := v.newMetric(labels)
+	v.metrics[key] = metric
+	v.keys = append(v.keys, key)
+	return metric
+}
+
+// all returns all metrics in the collection in the order they were created.
+func (v *vec[T]) all() []T {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	metrics := make([]T, 0, len(v.keys))
+	for _, key := range v.keys {
+		metrics = append(metrics, v.metrics[key])
+	}
+	return metrics
+}
+
+// CounterVec is a collection of counters that share a name and are
+// distinguished by the values of a fixed set of labels.
+//
+// It is safe for concurrent access.
+type CounterVec struct {
+	name string
+	help string
+	vec  vec[*Counter]
+}
+
+// NewCounterVec returns a new collection of counters with the provided name,
+// help text, and label names.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name: name,
+		help: help,
+		vec: vec[*Counter]{
+			labelNames: labelNames,
+			newMetric: func(labels []Label) *Counter {
+				return &Counter{name: name, help: help, labels: labels}
+			},
+			metrics: make(map[string]*Counter),
+		},
+	}
+}
+
+// With returns the counter for the provided label values, creating it when it
+// does not already exist.  The values must be in the same order as the label
+// names provided when creating the collection.
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return v.vec.with(labelValues...)
+}
+
+// Collect returns the metric family for all counters in the collection.
+//
+// This is part of the Collector interface implementation.
+func (v *CounterVec) Collect() []*Family {
+	counters := v.vec.all()
+	samples := make([]Sample, 0, len(counters))
+	for _, counter := range counters {
+		samples = append(samples, counter.sample())
+	}
+	return []*Family{{
+		Name:    v.name,
+		Help:    v.help,
+		Type:    CounterType,
+		Samples: samples,
+	}}
+}
+
+// HistogramVec is a collection of histograms that share a name and buckets and
+// are distinguished by the values of a fixed set of labels.
+//
+// It is safe for concurrent access.
+type HistogramVec struct {
+	name string
+	help string
+	vec  vec[*Histogram]
+}
+
+// NewHistogramVec returns a new collection of histograms with the provided
+// name, help text, bucket upper bounds, and label names.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return &HistogramVec{
+		name: name,
+		help: help,
+		vec: vec[*Histogram]{
+			labelNames: labelNames,
+			newMetric: func(labels []Label) *Histogram {
+				return newHistogram(name, help, labels, buckets)
+			},
+			metrics: make(map[string]*Histogram),
+		},
+	}
+}
+
+// With returns the histogram for the provided label values, creating it when
+// it does not already exist.  The values must be in the same order as the
+// label names provided when creating the collection.
+func (v *HistogramVec) With(labelValues ...string) *Histogram {
+	return v.vec.with(labelValues...)
+}
+
+// Collect returns the metric family for all histograms in the collection.
+//
+// This is part of the Collector interface implementation.
+func (v *HistogramVec) Collect() []*Family {
+	var samples []Sample
+	for _, histogram := range v.vec.all() {
+		samples = append(samples, histogram.samples()...)
+	}
+	return []*Family{{
+		Name:    v.name,
+		Help:    v.help,
+		Type:    HistogramType,
+		Samples: samples,
+	}}
+}
+
+// Registry houses a collection of collectors and provides the means to gather
+// and expose all of their metrics.
+//
+// It is safe for concurrent access.
+type Registry struct {
+	mtx        sync.Mutex
+	collectors []Collector
+}
+
+// NewRegistry returns a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the provided collectors to the registry.
+func (r *Registry) Register(collectors ...Collector) {
+	r.mtx.Lock()
+	r.collectors = append(r.collectors, collectors...)
+	r.mtx.Unlock()
+}
+
+// Gather collects the metric families from all registered collectors and
+// returns them sorted by name.  Families with the same name are merged.
+func (r *Registry) Gather() []*Family {
+	r.mtx.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mtx.Unlock()
+
+	byName := make(map[string]*Family)
+	var families []*Family
+	for _, collector := range collectors {
+		for _, family := range collector.Collect() {
+			if existing, ok := byName[family.Name]; ok {
+				existing.Samples = append(existing.Samples,
+					family.Samples...)
+				continue
+			}
+			byName[family.Name] = family
+			families = append(families, family)
+		}
+	}
+	slices.SortStableFunc(families, func(a, b *Family) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return families
+}
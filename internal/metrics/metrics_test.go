@@ -0,0 +1,167 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestCounterAndGauge ensures counters and gauges track their values as
+// expected including under concurrent access.
+func TestCounterAndGauge(t *testing.T) {
+	counter := NewCounter("test_counter", "A test counter.")
+	gauge := NewGauge("test_gauge", "A test gauge.")
+
+	const numGoroutines = 10
+	const numIncrements = 1000
+	var wg sync.WaitGroup
+	wg.Add(numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < numIncrements; j++ {
+				counter.Inc()
+				gauge.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	const want = numGoroutines * numIncrements
+	if got := counter.Value(); got != want {
+		t.Fatalf("unexpected counter value -- got %v, want %v", got, want)
+	}
+	if got := gauge.Value(); got != want {
+		t.Fatalf("unexpected gauge value -- got %v, want %v", got, want)
+	}
+
+	// Ensure counters ignore negative values while gauges do not.
+	counter.Add(-5)
+	gauge.Add(-5)
+	if got := counter.Value(); got != want {
+		t.Fatalf("unexpected counter value -- got %v, want %v", got, want)
+	}
+	if got := gauge.Value(); got != want-5 {
+		t.Fatalf("unexpected gauge value -- got %v, want %v", got, want-5)
+	}
+	gauge.Set(1.5)
+	if got := gauge.Value(); got != 1.5 {
+		t.Fatalf("unexpected gauge value -- got %v, want %v", got, 1.5)
+	}
+}
+
+// TestWriteText ensures the text exposition format produced for all metric
+// types is as expected.
+func TestWriteText(t *testing.T) {
+	counter := NewCounter("test_counter_total", "A test counter.")
+	counter.Add(3)
+
+	gauge := NewGauge("test_gauge", "A gauge with a\nmulti-line help \\ text.")
+	gauge.Set(math.Inf(1))
+
+	histogram := NewHistogram("test_histogram", "A test histogram.",
+		[]float64{1, 0.5, math.Inf(1), 0.5})
+	for _, v := range []float64{0.25, 0.5, 0.75, 2} {
+		histogram.Observe(v)
+	}
+
+	counterVec := NewCounterVec("test_vec_total", "A test counter vec.",
+		"method", "result")
+	counterVec.With("getinfo", "success").Inc()
+	counterVec.With("getinfo", "error").Add(2)
+	counterVec.With("getinfo", "success").Inc()
+	counterVec.With(`we"ird\`, "success").Inc()
+
+	histogramVec := NewHistogramVec("test_hist_vec", "A test histogram vec.",
+		[]float64{1}, "method")
+	histogramVec.With("getinfo").Observe(0.5)
+
+	collectorFn := CollectorFunc(func() []*Family {
+		return []*Family{{
+			Name:    "test_counter_total",
+			Type:    CounterType,
+			Samples: []Sample{{Labels: []Label{{"id", "1"}}, Value: 7}},
+		}}
+	})
+
+	registry := NewRegistry()
+	registry.Register(histogramVec, gauge, counter, histogram, counterVec,
+		collectorFn)
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, registry.Gather()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.Join([]string{
+		"# HELP test_counter_total A test counter.",
+		"# TYPE test_counter_total counter",
+		"test_counter_total 3",
+		`test_counter_total{id="1"} 7`,
+		`# HELP test_gauge A gauge with a\nmulti-line help \\ text.`,
+		"# TYPE test_gauge gauge",
+		"test_gauge +Inf",
+		"# HELP test_hist_vec A test histogram vec.",
+		"# TYPE test_hist_vec histogram",
+		`test_hist_vec_bucket{method="getinfo",le="1"} 1`,
+		`test_hist_vec_bucket{method="getinfo",le="+Inf"} 1`,
+		`test_hist_vec_sum{method="getinfo"} 0.5`,
+		`test_hist_vec_count{method="getinfo"} 1`,
+		"# HELP test_histogram A test histogram.",
+		"# TYPE test_histogram histogram",
+		`test_histogram_bucket{le="0.5"} 2`,
+		`test_histogram_bucket{le="1"} 3`,
+		`test_histogram_bucket{le="+Inf"} 4`,
+		"test_histogram_sum 3.5",
+		"test_histogram_count 4",
+		"# HELP test_vec_total A test counter vec.",
+		"# TYPE test_vec_total counter",
+		`test_vec_total{method="getinfo",result="success"} 2`,
+		`test_vec_total{method="getinfo",result="error"} 2`,
+		`test_vec_total{method="we\"ird\\",result="success"} 1`,
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output -- got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestHandler ensures the HTTP handler serves the gathered metrics with the
+// expected content type and rejects unsupported methods.
+func TestHandler(t *testing.T) {
+	gauge := NewGauge("test_gauge", "A test gauge.")
+	gauge.Set(42)
+	registry := NewRegistry()
+	registry.Register(gauge)
+	handler := Handler(registry)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code -- got %d, want %d", rec.Code,
+			http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != TextContentType {
+		t.Fatalf("unexpected content type -- got %q, want %q", ct,
+			TextContentType)
+	}
+	if !strings.Contains(rec.Body.String(), "test_gauge 42\n") {
+		t.Fatalf("unexpected body:\n%s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/metrics", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status code -- got %d, want %d", rec.Code,
+			http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TextContentType is the HTTP content type of the Prometheus text exposition
+// format.
+const TextContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	// helpEscaper escapes the help text of metric families as required by
+	// the text exposition format.
+	helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+	// labelValueEscaper escapes label values as required by the text
+	// exposition format.
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// formatFloat returns the text exposition format representation of the
+// provided value.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WriteText writes the provided metric families to w using the Prometheus text
+// exposition format.
+func WriteText(w io.Writer, families []*Family) error {
+	bw := bufio.NewWriter(w)
+	for _, family := range families {
+		if family.Help != "" {
+			bw.WriteString("# HELP ")
+			bw.WriteString(family.Name)
+			bw.WriteByte(' ')
+			bw.WriteString(helpEscaper.Replace(family.Help))
+			bw.WriteByte('\n')
+		}
+		bw.WriteString("# TYPE ")
+		bw.WriteString(family.Name)
+		bw.WriteByte(' ')
+		bw.WriteString(string(family.Type))
+		bw.WriteByte('\n')
+
+		for _, sample := range family.Samples {
+			bw.WriteString(family.Name)
+			bw.WriteString(sample.Suffix)
+			if len(sample.Labels) > 0 {
+				bw.WriteByte('{')
+				for i, label := range sample.Labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					bw.WriteString(label.Name)
+					bw.WriteString(`="`)
+					bw.WriteString(labelValueEscaper.Replace(label.Value))
+					bw.WriteByte('"')
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteByte(' ')
+			bw.WriteString(formatFloat(sample.Value))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler returns an HTTP handler that serves the metrics gathered from the
+// provided registry using the Prometheus text exposition format.
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed),
+				http.StatusMethodNotAllowed)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := WriteText(&buf, r.Gather()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", TextContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		if req.Method == http.MethodHead {
+			return
+		}
+		w.Write(buf.Bytes())
+	})
+}
@@ -1100,6 +1100,14 @@ func calcFeePerKb(txDesc *TxDesc, ancestorStats *TxAncestorStats) float64 {
 		float64(int64(txSize)+ancestorStats.SizeBytes)
 }
 
+// CalcPackageFeeRate returns the fee per kilobyte, in atoms, of the provided
+// transaction together with the ancestors described by the provided statistics.
+// This is the fee rate the block template generator uses to prioritize the
+// transaction.
+func CalcPackageFeeRate(txDesc *TxDesc, ancestorStats *TxAncestorStats) float64 {
+	return calcFeePerKb(txDesc, ancestorStats)
+}
+
 // NewBlockTemplate returns a new block template that is ready to be solved
 // using the transactions from the passed transaction source pool and a coinbase
 // that either pays to the passed address if it is not nil, or a coinbase that
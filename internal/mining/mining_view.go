@@ -1,4 +1,4 @@
-// Copyright (c) 2020 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -114,6 +114,24 @@ func (mv *TxMiningView) ancestors(txHash *chainhash.Hash) []*TxDesc {
 	return ancestors
 }
 
+// ForEachAncestor invokes the provided function for each transaction in the
+// view that the provided transaction hash depends on, either directly or
+// indirectly.  Each ancestor is visited once, after all of the ancestors that
+// it depends on.
+//
+// Unlike the cached statistics returned by AncestorStats, the walk is not
+// subject to the ancestor tracking limit.
+//
+// This function is NOT safe for concurrent access.
+func (mv *TxMiningView) ForEachAncestor(txHash *chainhash.Hash, f func(txDesc *TxDesc)) {
+	if len(mv.txGraph.parentsOf[*txHash]) == 0 {
+		return
+	}
+
+	seen := make(map[chainhash.Hash]struct{})
+	mv.txGraph.forEachAncestor(txHash, seen, f)
+}
+
 // AncestorStats returns the view's cached statistics for all of the provided
 // transaction's ancestors.
 //
@@ -189,6 +207,21 @@ func (mv *TxMiningView) descendants(txHash *chainhash.Hash) []*chainhash.Hash {
 	return descendants
 }
 
+// ForEachDescendant invokes the provided function for each transaction in the
+// view that depends on the provided transaction hash, either directly or
+// indirectly.  Each descendant is visited once, after all of the descendants
+// that depend on it.
+//
+// This function is NOT safe for concurrent access.
+func (mv *TxMiningView) ForEachDescendant(txHash *chainhash.Hash, f func(txDesc *TxDesc)) {
+	if len(mv.txGraph.childrenOf[*txHash]) == 0 {
+		return
+	}
+
+	seen := make(map[chainhash.Hash]struct{})
+	mv.txGraph.forEachDescendant(txHash, seen, f)
+}
+
 // hasParents returns true if the provided transaction hash spends from another
 // transaction in the mining view.
 //
@@ -0,0 +1,241 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dchest/siphash"
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/wire"
+)
+
+// maxCmpctBlockDepth is the maximum number of blocks a block may be behind the
+// current best chain tip in order to be served as a compact block.  Deeper
+// blocks are served in full since it is unlikely the requesting peer has the
+// transactions they contain in its transaction pool.
+const maxCmpctBlockDepth = 10
+
+var (
+	// errCmpctShortIDCollision indicates a compact block contains the same
+	// short transaction id more than once which means it is impossible to
+	// determine which transaction belongs in which position.
+	errCmpctShortIDCollision = errors.New("duplicate short transaction id")
+
+	// errCmpctBadMerkleRoot indicates a block reconstructed from a compact
+	// block does not commit to the transactions it contains which typically
+	// means one of the short transaction ids matched the wrong transaction.
+	errCmpctBadMerkleRoot = errors.New("reconstructed block merkle root " +
+		"mismatch")
+)
+
+// cmpctShortIDKeys returns the siphash keys used to calculate the short
+// transaction ids of a compact block with the given header and nonce.  The keys
+// are the first two little-endian uint64s of the BLAKE-256 hash of the
+// serialized header followed by the nonce.
+func cmpctShortIDKeys(header *wire.BlockHeader, nonce uint64) (uint64, uint64) {
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		// Serializing a header to memory can't fail.
+		panic(err)
+	}
+	var buf [wire.MaxBlockHeaderPayload + 8]byte
+	n := copy(buf[:], headerBytes)
+	binary.LittleEndian.PutUint64(buf[n:], nonce)
+	hash := chainhash.HashB(buf[:n+8])
+	k0 := binary.LittleEndian.Uint64(hash[0:8])
+	k1 := binary.LittleEndian.Uint64(hash[8:16])
+	return k0, k1
+}
+
+// cmpctShortID returns the short transaction id for the transaction with the
+// given hash using the provided siphash keys.
+func cmpctShortID(k0, k1 uint64, txHash *chainhash.Hash) uint64 {
+	return siphash.Hash(k0, k1, txHash[:]) & wire.MaxCmpctShortID
+}
+
+// NewCmpctBlock returns a compact block for the provided block using the given
+// nonce to calculate the short transaction ids.
+//
+// The coinbase is always prefilled since the receiver can't possibly already
+// have it, the remaining regular transactions are represented by their short
+// ids, and the stake transactions are included in full since they are few in
+// number and frequently not relayed before the block.
+func NewCmpctBlock(block *dcrutil.Block, nonce uint64) *wire.MsgCmpctBlock {
+	msgBlock := block.MsgBlock()
+	msg := wire.NewMsgCmpctBlock(&msgBlock.Header, nonce)
+	txns := msgBlock.Transactions
+	if len(txns) == 0 {
+		return msg
+	}
+	msg.PrefilledTxs = []wire.PrefilledTx{{Index: 0, Tx: txns[0]}}
+	if len(txns) > 1 {
+		k0, k1 := cmpctShortIDKeys(&msgBlock.Header, nonce)
+		msg.ShortIDs = make([]uint64, 0, len(txns)-1)
+		for _, tx := range block.Transactions()[1:] {
+			msg.ShortIDs = append(msg.ShortIDs, cmpctShortID(k0, k1, tx.Hash()))
+		}
+	}
+	msg.STransactions = msgBlock.STransactions
+	return msg
+}
+
+// CmpctBlockDepthOK returns whether or not a block at the given height is
+// recent enough relative to the provided best chain height to be served as a
+// compact block.
+func CmpctBlockDepthOK(blockHeight, bestHeight int64) bool {
+	return bestHeight-blockHeight <= maxCmpctBlockDepth
+}
+
+// partialBlock houses the state of a block that is being reconstructed from a
+// compact block.
+type partialBlock struct {
+	hash      chainhash.Hash
+	header    wire.BlockHeader
+	txns      []*wire.MsgTx
+	stakeTxns []*wire.MsgTx
+
+	// missing houses the indexes of the regular transactions that could not
+	// be found in the transaction pool and therefore need to be requested.
+	missing []uint32
+}
+
+// newPartialBlock attempts to reconstruct the block described by the provided
+// compact block using the prefilled transactions it contains along with the
+// passed transaction pool entries.  The indexes of any transactions that are
+// not available are tracked so they can be requested.
+//
+// An error is returned when the compact block contains duplicate short ids
+// since the block can't be reliably reconstructed in that case.
+func newPartialBlock(msg *wire.MsgCmpctBlock, poolTxns []*mempool.TxDesc) (*partialBlock, error) {
+	numTxns := msg.NumTransactions()
+	partial := &partialBlock{
+		hash:      msg.BlockHash(),
+		header:    msg.Header,
+		txns:      make([]*wire.MsgTx, numTxns),
+		stakeTxns: msg.STransactions,
+	}
+	for _, prefilled := range msg.PrefilledTxs {
+		partial.txns[prefilled.Index] = prefilled.Tx
+	}
+
+	// Map the short ids to the positions of the transactions they represent
+	// which are the positions not already filled by prefilled transactions.
+	shortIDIndexes := make(map[uint64]int, len(msg.ShortIDs))
+	var shortIDIdx int
+	for i := range partial.txns {
+		if partial.txns[i] != nil {
+			continue
+		}
+		shortID := msg.ShortIDs[shortIDIdx]
+		shortIDIdx++
+		if _, ok := shortIDIndexes[shortID]; ok {
+			return nil, errCmpctShortIDCollision
+		}
+		shortIDIndexes[shortID] = i
+	}
+
+	// Fill in the transactions that are available in the transaction pool.
+	// Positions matched by more than one pool transaction are ambiguous, so
+	// they are cleared and requested instead.
+	if len(shortIDIndexes) > 0 {
+		k0, k1 := cmpctShortIDKeys(&msg.Header, msg.Nonce)
+		ambiguous := make(map[int]struct{})
+		for _, desc := range poolTxns {
+			shortID := cmpctShortID(k0, k1, desc.Tx.Hash())
+			idx, ok := shortIDIndexes[shortID]
+			if !ok {
+				continue
+			}
+			if partial.txns[idx] != nil {
+				ambiguous[idx] = struct{}{}
+				continue
+			}
+			partial.txns[idx] = desc.Tx.MsgTx()
+		}
+		for idx := range ambiguous {
+			partial.txns[idx] = nil
+		}
+	}
+
+	for i, tx := range partial.txns {
+		if tx == nil {
+			partial.missing = append(partial.missing, uint32(i))
+		}
+	}
+	return partial, nil
+}
+
+// fill populates the missing transactions of the partial block with the
+// provided transactions which must be in the same order the missing
+// transactions were requested.
+func (partial *partialBlock) fill(txns []*wire.MsgTx) error {
+	if len(txns) != len(partial.missing) {
+		return fmt.Errorf("received %d transactions for block %s when %d "+
+			"were requested", len(txns), partial.hash, len(partial.missing))
+	}
+	for i, idx := range partial.missing {
+		partial.txns[idx] = txns[i]
+	}
+	partial.missing = nil
+	return nil
+}
+
+// txTreesMatchHeader returns whether or not the provided regular and stake
+// transaction trees match the merkle root commitments in the provided header.
+//
+// Both the combined merkle root used by the header commitments agenda and the
+// separate regular and stake tree roots that preceded it are accepted since
+// the applicable rules depend on the chain state and the chain itself performs
+// the authoritative check.  The purpose here is only to detect transactions
+// that differ from the ones the header commits to without marking a valid
+// block as invalid.
+//
+// Trees that contain the same transaction more than once are rejected as well
+// since duplicating transactions at the end of a tree with an odd number of
+// leaves does not change its merkle root.
+func txTreesMatchHeader(header *wire.BlockHeader, txns, stakeTxns []*wire.MsgTx) bool {
+	for _, tree := range [][]*wire.MsgTx{txns, stakeTxns} {
+		seen := make(map[chainhash.Hash]struct{}, len(tree))
+		for _, tx := range tree {
+			hash := tx.TxHashFull()
+			if _, ok := seen[hash]; ok {
+				return false
+			}
+			seen[hash] = struct{}{}
+		}
+	}
+
+	combinedRoot := standalone.CalcCombinedTxTreeMerkleRoot(txns, stakeTxns)
+	if header.MerkleRoot == combinedRoot {
+		return true
+	}
+	regularRoot := standalone.CalcTxTreeMerkleRoot(txns)
+	stakeRoot := standalone.CalcTxTreeMerkleRoot(stakeTxns)
+	return header.MerkleRoot == regularRoot && header.StakeRoot == stakeRoot
+}
+
+// block returns the fully reconstructed block after ensuring the transactions
+// it contains match the merkle root commitments in the header.  The purpose is
+// only to detect incorrectly matched short ids as described by
+// txTreesMatchHeader.
+func (partial *partialBlock) block() (*dcrutil.Block, error) {
+	header := &partial.header
+	if !txTreesMatchHeader(header, partial.txns, partial.stakeTxns) {
+		return nil, errCmpctBadMerkleRoot
+	}
+
+	msgBlock := &wire.MsgBlock{
+		Header:        *header,
+		Transactions:  partial.txns,
+		STransactions: partial.stakeTxns,
+	}
+	return dcrutil.NewBlock(msgBlock), nil
+}
@@ -0,0 +1,255 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/wire"
+)
+
+// makeCmpctTestTx returns a unique transaction for the given seed.
+func makeCmpctTestTx(seed uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	prevOut := wire.NewOutPoint(&chainhash.Hash{byte(seed)}, seed,
+		wire.TxTreeRegular)
+	tx.AddTxIn(wire.NewTxIn(prevOut, int64(seed), nil))
+	tx.AddTxOut(wire.NewTxOut(int64(seed), []byte{0x51}))
+	return tx
+}
+
+// makeCmpctTestBlock returns a block with the given number of regular and
+// stake transactions that commits to them via the combined merkle root.
+func makeCmpctTestBlock(numTxns, numStakeTxns int) *dcrutil.Block {
+	var msgBlock wire.MsgBlock
+	msgBlock.Header.Height = 100
+	for i := 0; i < numTxns; i++ {
+		msgBlock.AddTransaction(makeCmpctTestTx(uint32(i)))
+	}
+	for i := 0; i < numStakeTxns; i++ {
+		msgBlock.AddSTransaction(makeCmpctTestTx(uint32(1000 + i)))
+	}
+	msgBlock.Header.MerkleRoot = standalone.CalcCombinedTxTreeMerkleRoot(
+		msgBlock.Transactions, msgBlock.STransactions)
+	return dcrutil.NewBlock(&msgBlock)
+}
+
+// poolDescs returns transaction pool descriptors for the provided
+// transactions.
+func poolDescs(txns []*dcrutil.Tx) []*mempool.TxDesc {
+	descs := make([]*mempool.TxDesc, 0, len(txns))
+	for _, tx := range txns {
+		descs = append(descs, &mempool.TxDesc{TxDesc: mining.TxDesc{Tx: tx}})
+	}
+	return descs
+}
+
+// TestCmpctBlockReconstruction ensures blocks are correctly reconstructed from
+// compact blocks and the transaction pool including when transactions are
+// missing, short ids collide, and transactions are matched incorrectly.
+func TestCmpctBlockReconstruction(t *testing.T) {
+	t.Parallel()
+
+	block := makeCmpctTestBlock(6, 2)
+	const nonce = 0x0123456789abcdef
+	msg := NewCmpctBlock(block, nonce)
+
+	// Ensure the compact block has the coinbase prefilled, short ids for the
+	// remaining regular transactions, and the full stake tree.
+	if len(msg.PrefilledTxs) != 1 || msg.PrefilledTxs[0].Index != 0 {
+		t.Fatalf("unexpected prefilled txns: %+v", msg.PrefilledTxs)
+	}
+	if len(msg.ShortIDs) != 5 {
+		t.Fatalf("unexpected number of short ids: got %d, want 5",
+			len(msg.ShortIDs))
+	}
+	if len(msg.STransactions) != 2 {
+		t.Fatalf("unexpected number of stake txns: got %d, want 2",
+			len(msg.STransactions))
+	}
+	k0, k1 := cmpctShortIDKeys(&msg.Header, nonce)
+	for i, tx := range block.Transactions()[1:] {
+		want := cmpctShortID(k0, k1, tx.Hash())
+		if msg.ShortIDs[i] != want {
+			t.Fatalf("short id %d: got %x, want %x", i, msg.ShortIDs[i], want)
+		}
+		if want > wire.MaxCmpctShortID {
+			t.Fatalf("short id %d exceeds max: %x", i, want)
+		}
+	}
+
+	// Ensure a different nonce results in different short ids.
+	otherMsg := NewCmpctBlock(block, nonce+1)
+	if reflect.DeepEqual(otherMsg.ShortIDs, msg.ShortIDs) {
+		t.Fatal("short ids did not change with nonce")
+	}
+
+	// Ensure the block is fully reconstructed when all transactions are in the
+	// pool along with some unrelated ones.
+	pool := poolDescs(block.Transactions()[1:])
+	for i := uint32(0); i < 3; i++ {
+		unrelated := dcrutil.NewTx(makeCmpctTestTx(2000 + i))
+		pool = append(pool, poolDescs([]*dcrutil.Tx{unrelated})...)
+	}
+	partial, err := newPartialBlock(msg, pool)
+	if err != nil {
+		t.Fatalf("newPartialBlock: unexpected error: %v", err)
+	}
+	if len(partial.missing) != 0 {
+		t.Fatalf("unexpected missing txns: %v", partial.missing)
+	}
+	gotBlock, err := partial.block()
+	if err != nil {
+		t.Fatalf("block: unexpected error: %v", err)
+	}
+	if *gotBlock.Hash() != *block.Hash() {
+		t.Fatalf("mismatched block hash: got %v, want %v", gotBlock.Hash(),
+			block.Hash())
+	}
+	if !reflect.DeepEqual(gotBlock.MsgBlock(), block.MsgBlock()) {
+		t.Fatal("reconstructed block does not match original")
+	}
+
+	// Ensure missing transactions are tracked and the block is reconstructed
+	// once they are provided.
+	txns := block.MsgBlock().Transactions
+	pool = poolDescs([]*dcrutil.Tx{block.Transactions()[2]})
+	partial, err = newPartialBlock(msg, pool)
+	if err != nil {
+		t.Fatalf("newPartialBlock: unexpected error: %v", err)
+	}
+	wantMissing := []uint32{1, 3, 4, 5}
+	if !reflect.DeepEqual(partial.missing, wantMissing) {
+		t.Fatalf("unexpected missing txns: got %v, want %v", partial.missing,
+			wantMissing)
+	}
+	if err := partial.fill(txns[1:2]); err == nil {
+		t.Fatal("fill: did not fail with wrong number of txns")
+	}
+	err = partial.fill([]*wire.MsgTx{txns[1], txns[3], txns[4], txns[5]})
+	if err != nil {
+		t.Fatalf("fill: unexpected error: %v", err)
+	}
+	gotBlock, err = partial.block()
+	if err != nil {
+		t.Fatalf("block: unexpected error: %v", err)
+	}
+	if *gotBlock.Hash() != *block.Hash() {
+		t.Fatalf("mismatched block hash: got %v, want %v", gotBlock.Hash(),
+			block.Hash())
+	}
+
+	// Ensure incorrectly provided transactions are detected via the merkle
+	// root.
+	partial, err = newPartialBlock(msg, nil)
+	if err != nil {
+		t.Fatalf("newPartialBlock: unexpected error: %v", err)
+	}
+	err = partial.fill([]*wire.MsgTx{txns[1], txns[2], txns[3], txns[3],
+		txns[5]})
+	if err != nil {
+		t.Fatalf("fill: unexpected error: %v", err)
+	}
+	if _, err := partial.block(); !errors.Is(err, errCmpctBadMerkleRoot) {
+		t.Fatalf("block: unexpected error: got %v, want %v", err,
+			errCmpctBadMerkleRoot)
+	}
+
+	// Ensure duplicate short ids in the compact block are rejected.
+	dupMsg := NewCmpctBlock(block, nonce)
+	dupMsg.ShortIDs[1] = dupMsg.ShortIDs[0]
+	if _, err := newPartialBlock(dupMsg, nil); !errors.Is(err,
+		errCmpctShortIDCollision) {
+
+		t.Fatalf("newPartialBlock: unexpected error: got %v, want %v", err,
+			errCmpctShortIDCollision)
+	}
+
+	// Ensure positions matched by multiple pool transactions are requested.
+	// This is simulated by providing the same transaction twice via separate
+	// descriptors.
+	tx := block.Transactions()[3]
+	pool = poolDescs([]*dcrutil.Tx{tx, dcrutil.NewTx(tx.MsgTx().Copy())})
+	partial, err = newPartialBlock(msg, pool)
+	if err != nil {
+		t.Fatalf("newPartialBlock: unexpected error: %v", err)
+	}
+	wantMissing = []uint32{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(partial.missing, wantMissing) {
+		t.Fatalf("unexpected missing txns: got %v, want %v", partial.missing,
+			wantMissing)
+	}
+}
+
+// TestTxTreesMatchHeader ensures transaction trees are only considered to
+// match a header when they match either form of its merkle root commitments
+// and do not contain duplicate transactions.
+func TestTxTreesMatchHeader(t *testing.T) {
+	t.Parallel()
+
+	// Blocks that commit via the combined merkle root or the separate roots
+	// match.
+	block := makeCmpctTestBlock(3, 2).MsgBlock()
+	txns, stakeTxns := block.Transactions, block.STransactions
+	if !txTreesMatchHeader(&block.Header, txns, stakeTxns) {
+		t.Fatal("combined merkle root commitment did not match")
+	}
+	legacyHeader := block.Header
+	legacyHeader.MerkleRoot = standalone.CalcTxTreeMerkleRoot(txns)
+	legacyHeader.StakeRoot = standalone.CalcTxTreeMerkleRoot(stakeTxns)
+	if !txTreesMatchHeader(&legacyHeader, txns, stakeTxns) {
+		t.Fatal("separate merkle root commitments did not match")
+	}
+
+	// Modified transactions do not match.
+	modified := append([]*wire.MsgTx(nil), txns...)
+	modified[1] = makeCmpctTestTx(500)
+	if txTreesMatchHeader(&block.Header, modified, stakeTxns) {
+		t.Fatal("modified transactions matched")
+	}
+	if txTreesMatchHeader(&legacyHeader, txns, modified) {
+		t.Fatal("modified stake transactions matched")
+	}
+
+	// Duplicating the final transaction of a tree with an odd number of
+	// transactions does not change the merkle root, but does not match.
+	duplicated := append(append([]*wire.MsgTx(nil), txns...), txns[2])
+	if standalone.CalcTxTreeMerkleRoot(duplicated) != legacyHeader.MerkleRoot {
+		t.Fatal("duplicated transaction changed the merkle root")
+	}
+	if txTreesMatchHeader(&legacyHeader, duplicated, stakeTxns) {
+		t.Fatal("duplicated transactions matched")
+	}
+}
+
+// TestCmpctBlockDepthOK ensures the depth check for serving compact blocks
+// behaves as expected.
+func TestCmpctBlockDepthOK(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		blockHeight int64
+		bestHeight  int64
+		want        bool
+	}{
+		{"tip", 100, 100, true},
+		{"max depth", 90, 100, true},
+		{"too deep", 89, 100, false},
+	}
+	for _, test := range tests {
+		got := CmpctBlockDepthOK(test.blockHeight, test.bestHeight)
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
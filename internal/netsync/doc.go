@@ -10,5 +10,12 @@ perform an initial chain sync, keep the chain in sync, and announce new blocks
 connected to the chain.  Currently the sync manager selects a single sync peer
 that it downloads all blocks from until it is up to date with the longest chain
 the sync peer is aware of.
+
+Once the chain is current, new blocks are requested as compact blocks from
+peers that support them.  Compact blocks consist of the block header, short
+transaction ids for the regular transactions other than the coinbase, and the
+full coinbase and stake transactions.  The sync manager reconstructs the block
+from the transactions in the transaction pool and requests only the ones that
+are missing.
 */
 package netsync
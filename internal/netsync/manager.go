@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -37,6 +37,10 @@ const (
 	// peer request queue.
 	maxInFlightBlocks = 16
 
+	// maxInFlightBgBlocks is the maximum number of blocks needed by the
+	// background validator to allow in the request queue of a peer.
+	maxInFlightBgBlocks = 16
+
 	// maxRejectedTxns specifies the maximum number of recently rejected
 	// transactions to track.  This is primarily used to avoid wasting a bunch
 	// of bandwidth from requesting transactions that are already known to be
@@ -127,6 +131,17 @@ type Peer struct {
 	// longer useful or are otherwise being malicious.
 	numConsecutiveOrphanHeaders atomic.Int64
 
+	// cmpctBlocks indicates the peer announced support for compact blocks
+	// with an encoding version understood by the sync manager and therefore
+	// blocks near the tip may be requested from it as compact blocks.
+	cmpctBlocks atomic.Bool
+
+	// partialBlock houses the block the peer is currently providing via a
+	// compact block that still requires the transactions that were not
+	// available locally.  It is protected by the request mutex of the sync
+	// manager.
+	partialBlock *partialBlock
+
 	// These fields are used to track the best known block announced by the peer
 	// which in turn provides a means to discover which blocks are available to
 	// download from the peer.  They are protected by the associated best
@@ -161,6 +176,14 @@ func NewPeer(peer *peerpkg.Peer) *Peer {
 	}
 }
 
+// SupportsCmpctBlocks returns whether or not the peer announced support for
+// compact blocks with an encoding version understood by the sync manager.
+//
+// This function is safe for concurrent access.
+func (peer *Peer) SupportsCmpctBlocks() bool {
+	return peer.cmpctBlocks.Load()
+}
+
 // maybeRequestInitialState potentially requests initial state information from
 // the peer by sending it an appropriate initial state sync message dependending
 // on the protocol version.
@@ -313,10 +336,11 @@ type SyncManager struct {
 
 	// These fields track pending requests for data from all peers.  They are
 	// protected by the request mutex.
-	requestMtx       sync.Mutex
-	requestedTxns    map[chainhash.Hash]*Peer
-	requestedBlocks  map[chainhash.Hash]*Peer
-	requestedMixMsgs map[chainhash.Hash]*Peer
+	requestMtx        sync.Mutex
+	requestedTxns     map[chainhash.Hash]*Peer
+	requestedBlocks   map[chainhash.Hash]*Peer
+	requestedMixMsgs  map[chainhash.Hash]*Peer
+	requestedBgBlocks map[chainhash.Hash]*Peer
 
 	// The following fields are used to track the current sync peer.  The peer
 	// is protected by the associated mutex.
@@ -465,6 +489,16 @@ func (m *SyncManager) isRequestedBlockFromPeer(peer *Peer, hash *chainhash.Hash)
 	return ok && requestedFrom == peer
 }
 
+// isRequestedBgBlockFromPeer returns whether or not the given block hash has
+// been requested from the given remote peer on behalf of the background
+// validator.
+//
+// This function MUST be called with the request mutex held (reads).
+func (m *SyncManager) isRequestedBgBlockFromPeer(peer *Peer, hash *chainhash.Hash) bool {
+	requestedFrom, ok := m.requestedBgBlocks[*hash]
+	return ok && requestedFrom == peer
+}
+
 // isRequestedTxFromPeer returns whether or not the given transaction hash has
 // been requested from the given remote peer.
 //
@@ -545,6 +579,77 @@ func (m *SyncManager) fetchNextBlocks(peer *Peer) {
 	}
 }
 
+// fetchNextBgBlocks creates and sends a request to the provided peer for the
+// next blocks needed by the background validator when there is one and it has
+// not finished yet.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) fetchNextBgBlocks(peer *Peer) {
+	bgValidator := m.cfg.BackgroundValidator
+	if bgValidator == nil || bgValidator.Done() || !peer.servesData {
+		return
+	}
+
+	// Nothing to do if the target maximum number of blocks to request from the
+	// peer at the same time are already in flight.
+	var numInFlight int
+	m.requestMtx.Lock()
+	for _, requestedFrom := range m.requestedBgBlocks {
+		if requestedFrom != peer {
+			continue
+		}
+		numInFlight++
+	}
+	m.requestMtx.Unlock()
+	if numInFlight >= maxInFlightBgBlocks {
+		return
+	}
+
+	// Build and send a getdata request for the needed blocks while skipping
+	// any that are already in flight.
+	var buf [maxInFlightBgBlocks * 2]chainhash.Hash
+	neededBlocks := bgValidator.PutNextNeededBlocks(buf[:])
+	maxNeeded := maxInFlightBgBlocks - numInFlight
+	gdmsg := wire.NewMsgGetDataSizeHint(uint(maxNeeded))
+	m.requestMtx.Lock()
+	for i := range neededBlocks {
+		if len(gdmsg.InvList) >= maxNeeded {
+			break
+		}
+		hash := &neededBlocks[i]
+		if _, ok := m.requestedBgBlocks[*hash]; ok {
+			continue
+		}
+
+		iv := wire.NewInvVect(wire.InvTypeBlock, hash)
+		m.requestedBgBlocks[*hash] = peer
+		gdmsg.AddInvVect(iv)
+	}
+	m.requestMtx.Unlock()
+	if len(gdmsg.InvList) > 0 {
+		peer.QueueMessage(gdmsg, nil)
+	}
+}
+
+// maybeFetchNextBgBlocks requests the next blocks needed by the background
+// validator from the provided peer once the initial chain sync is done.  The
+// background validation is intentionally delayed until that point so it does
+// not compete with syncing the main chain.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) maybeFetchNextBgBlocks(peer *Peer) {
+	if peer == nil || m.cfg.BackgroundValidator == nil {
+		return
+	}
+
+	m.initialChainSyncDoneMtx.Lock()
+	isInitialChainSyncDone := m.isInitialChainSyncDone
+	m.initialChainSyncDoneMtx.Unlock()
+	if isInitialChainSyncDone {
+		m.fetchNextBgBlocks(peer)
+	}
+}
+
 // fetchNextHeaders requests headers from the provided peer starting from the
 // parent of the best known header for the local chain in order to discover any
 // blocks that are not already known as well as accurately discover the best
@@ -912,6 +1017,13 @@ MixHashes:
 		// No peers found that have announced this data.
 		delete(m.requestedMixMsgs, mixHash)
 	}
+	for blockHash, requestedFrom := range m.requestedBgBlocks {
+		// Blocks needed by the background validator are simply requested
+		// again from the sync peer later.
+		if requestedFrom == peer {
+			delete(m.requestedBgBlocks, blockHash)
+		}
+	}
 	m.requestMtx.Unlock()
 	for pp, requestQueue := range requestQueues {
 		var numRequested int32
@@ -943,7 +1055,12 @@ MixHashes:
 	if m.syncPeer == peer {
 		m.startChainSync()
 	}
+	syncPeer := m.syncPeer
 	m.syncPeerMtx.Unlock()
+
+	// Continue downloading any blocks needed by the background validator from
+	// the sync peer.
+	m.maybeFetchNextBgBlocks(syncPeer)
 }
 
 // OnTx should be invoked with transactions that are received from remote peers.
@@ -1180,10 +1297,14 @@ func (m *SyncManager) maybeResolveOrphanBlock(p *Peer) {
 // This function is safe for concurrent access.
 func (m *SyncManager) processBlock(block *dcrutil.Block) (int64, error) {
 	// Process the block to include validation, best chain selection, etc.
+	start := time.Now()
 	forkLen, err := m.cfg.Chain.ProcessBlock(block)
 	if err != nil {
 		return 0, err
 	}
+	if m.cfg.BlockProcessed != nil {
+		m.cfg.BlockProcessed(time.Since(start))
+	}
 
 	// Update the sync height when the block is higher than the currently best
 	// known value and it extends the main chain.
@@ -1220,7 +1341,12 @@ func (m *SyncManager) OnBlock(peer *Peer, block *dcrutil.Block) {
 	blockHash := block.Hash()
 	m.requestMtx.Lock()
 	requested := m.isRequestedBlockFromPeer(peer, blockHash)
+	bgRequested := m.isRequestedBgBlockFromPeer(peer, blockHash)
 	m.requestMtx.Unlock()
+	if !requested && bgRequested {
+		m.onBgBlock(peer, block)
+		return
+	}
 	if !requested {
 		log.Warnf("Got unrequested block %v from %s -- disconnecting",
 			blockHash, peer)
@@ -1367,7 +1493,64 @@ func (m *SyncManager) OnBlock(peer *Peer, block *dcrutil.Block) {
 		if numInFlight < minInFlightBlocks {
 			m.fetchNextBlocks(peer)
 		}
+
+		// Also request more blocks needed by the background validator.
+		m.maybeFetchNextBgBlocks(peer)
+	}
+}
+
+// onBgBlock handles blocks received from remote peers that were requested on
+// behalf of the background validator by passing them along to it and
+// requesting more as needed.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) onBgBlock(peer *Peer, block *dcrutil.Block) {
+	// The header of the block is committed to by the main chain, but the
+	// transactions are not, so the remote peer is misbehaving when they do not
+	// match the header.  Disconnect the peer in that case and request the
+	// block from the other peers instead of passing it to the background
+	// validator which would otherwise consider the history invalid.
+	blockHash := block.Hash()
+	msgBlock := block.MsgBlock()
+	if !txTreesMatchHeader(&msgBlock.Header, msgBlock.Transactions,
+		msgBlock.STransactions) {
+
+		log.Warnf("Got block %v for background validation from %s with "+
+			"transactions that do not match its header -- disconnecting",
+			blockHash, peer)
+		m.requestMtx.Lock()
+		delete(m.requestedBgBlocks, *blockHash)
+		m.requestMtx.Unlock()
+		peer.Disconnect()
+
+		m.peersMtx.Lock()
+		otherPeers := make([]*Peer, 0, len(m.peers))
+		for pp := range m.peers {
+			if pp != peer {
+				otherPeers = append(otherPeers, pp)
+			}
+		}
+		m.peersMtx.Unlock()
+		for _, pp := range otherPeers {
+			m.maybeFetchNextBgBlocks(pp)
+		}
+		return
+	}
+
+	// Process the block with the background validator and remove it from the
+	// request map once it has been processed to help prevent duplicate
+	// requests.
+	err := m.cfg.BackgroundValidator.ProcessBlock(block)
+	m.requestMtx.Lock()
+	delete(m.requestedBgBlocks, *blockHash)
+	m.requestMtx.Unlock()
+	if err != nil && !errors.Is(err, blockchain.ErrDuplicateBlock) {
+		log.Errorf("Failed to process block %v for background validation: %v",
+			blockHash, err)
+		return
 	}
+
+	m.fetchNextBgBlocks(peer)
 }
 
 // guessHeaderSyncProgress returns a percentage that is a guess of the progress
@@ -1726,7 +1909,14 @@ func (m *SyncManager) OnHeaders(peer *Peer, headersMsg *wire.MsgHeaders) {
 	// available headers once that code supports downloading from multiple peers
 	// and associated infrastructure to efficiently determine which peers have
 	// the associated block(s).
+	//
+	// Blocks are requested as compact blocks from peers that support them
+	// since the majority of their transactions are typically already known.
 	if isChainCurrent {
+		invType := wire.InvTypeBlock
+		if peer.cmpctBlocks.Load() {
+			invType = wire.InvTypeCmpctBlock
+		}
 		gdmsg := wire.NewMsgGetDataSizeHint(uint(len(headers)))
 		m.requestMtx.Lock()
 		for i := range headerHashes {
@@ -1744,7 +1934,7 @@ func (m *SyncManager) OnHeaders(peer *Peer, headersMsg *wire.MsgHeaders) {
 			}
 
 			m.requestedBlocks[*hash] = peer
-			iv := wire.NewInvVect(wire.InvTypeBlock, hash)
+			iv := wire.NewInvVect(invType, hash)
 			gdmsg.AddInvVect(iv)
 		}
 		m.requestMtx.Unlock()
@@ -1765,6 +1955,194 @@ func (m *SyncManager) OnHeaders(peer *Peer, headersMsg *wire.MsgHeaders) {
 	}
 }
 
+// OnSendCmpct should be invoked with sendcmpct messages that are received from
+// remote peers.  It records whether or not blocks may be requested from the
+// peer as compact blocks based on the announced encoding version.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) OnSendCmpct(peer *Peer, msg *wire.MsgSendCmpct) {
+	if msg.Version == wire.CmpctBlockEncodingVersion {
+		peer.cmpctBlocks.Store(true)
+	}
+}
+
+// requestFullBlock requests the full block with the given hash from the
+// provided peer.  It is used when a block that was being reconstructed from a
+// compact block can't be reconstructed for some reason.  The block must
+// already be marked as requested from the peer.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) requestFullBlock(peer *Peer, hash *chainhash.Hash) {
+	gdmsg := wire.NewMsgGetDataSizeHint(1)
+	gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, hash))
+	peer.QueueMessage(gdmsg, nil)
+}
+
+// processPartialBlock processes the block reconstructed from a compact block
+// sent by the given peer as if the full block had been received from it.  The
+// full block is requested instead when the reconstructed block does not match
+// the commitments in its header.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) processPartialBlock(peer *Peer, partial *partialBlock) {
+	block, err := partial.block()
+	if err != nil {
+		log.Debugf("Unable to reconstruct compact block %s from %s: %v -- "+
+			"requesting full block", partial.hash, peer, err)
+		m.requestFullBlock(peer, &partial.hash)
+		return
+	}
+	m.OnBlock(peer, block)
+}
+
+// OnCmpctBlock should be invoked with compact blocks that are received from
+// remote peers.
+//
+// Its primary purpose is reconstructing the full block from the transactions
+// in the transaction pool and either processing it when all of them are
+// available or requesting the missing ones from the peer.  The full block is
+// requested instead when the compact block can't be reliably reconstructed.
+//
+// Unsolicited compact blocks, which are sent by peers that were asked to
+// announce new blocks in high bandwidth mode, are only considered when the
+// chain is current and the block has not already been requested from another
+// peer.
+//
+// Ideally, this should be called from the same peer goroutine that received the
+// message so the bulk of the processing is done concurrently and further reads
+// from the peer are blocked until the message is processed.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) OnCmpctBlock(peer *Peer, msg *wire.MsgCmpctBlock) {
+	if m.shutdownRequested() {
+		return
+	}
+
+	// Nothing to do when the block is already known.
+	chain := m.cfg.Chain
+	blockHash := msg.BlockHash()
+	if chain.HaveBlock(&blockHash) {
+		m.requestMtx.Lock()
+		if m.isRequestedBlockFromPeer(peer, &blockHash) {
+			delete(m.requestedBlocks, blockHash)
+		}
+		m.requestMtx.Unlock()
+		return
+	}
+
+	// Ignore unsolicited compact blocks when the chain is not current or the
+	// block is already in flight from another peer.
+	m.requestMtx.Lock()
+	requested := m.isRequestedBlockFromPeer(peer, &blockHash)
+	if !requested && (!m.IsCurrent() || m.isRequestedBlock(&blockHash) ||
+		len(m.requestedBlocks)+1 > maxRequestedBlocks) {
+
+		m.requestMtx.Unlock()
+		return
+	}
+	m.requestMtx.Unlock()
+
+	// Request the headers leading up to unsolicited compact blocks that do not
+	// connect to any known headers.  The block itself will be requested once
+	// the headers are known.
+	if !requested && !chain.HaveHeader(&msg.Header.PrevBlock) {
+		bestHeaderHash, _ := chain.BestHeader()
+		blkLocator := chain.BlockLocatorFromHash(&bestHeaderHash)
+		locator := chainBlockLocatorToHashes(blkLocator)
+		peer.PushGetHeadersMsg(locator, &zeroHash)
+		return
+	}
+
+	// Validate the header prior to spending any effort on reconstructing the
+	// block.
+	if err := chain.ProcessBlockHeader(&msg.Header); err != nil {
+		log.Debugf("Failed to process compact block header %s from peer %s: "+
+			"%v -- disconnecting", blockHash, peer, err)
+		peer.Disconnect()
+		return
+	}
+	peer.bestAnnouncedMtx.Lock()
+	m.maybeUpdateBestAnnouncedBlock(peer, &blockHash, &msg.Header)
+	peer.bestAnnouncedMtx.Unlock()
+
+	// Mark the block as requested from the peer so it is not requested from
+	// others while it is being reconstructed.
+	m.requestMtx.Lock()
+	if !requested {
+		if m.isRequestedBlock(&blockHash) {
+			m.requestMtx.Unlock()
+			return
+		}
+		m.requestedBlocks[blockHash] = peer
+	}
+	hasPendingPartial := peer.partialBlock != nil
+	m.requestMtx.Unlock()
+
+	// Only a single block per peer is reconstructed at a time, so request the
+	// full block when there is already one pending.
+	if hasPendingPartial {
+		m.requestFullBlock(peer, &blockHash)
+		return
+	}
+
+	partial, err := newPartialBlock(msg, m.cfg.TxMemPool.TxDescs())
+	if err != nil {
+		log.Debugf("Unable to reconstruct compact block %s from %s: %v -- "+
+			"requesting full block", blockHash, peer, err)
+		m.requestFullBlock(peer, &blockHash)
+		return
+	}
+	if len(partial.missing) > 0 {
+		log.Debugf("Requesting %d of %d transactions for compact block %s "+
+			"from %s", len(partial.missing), len(partial.txns), blockHash, peer)
+		m.requestMtx.Lock()
+		peer.partialBlock = partial
+		m.requestMtx.Unlock()
+		peer.QueueMessage(wire.NewMsgGetBlockTxns(&blockHash, partial.missing),
+			nil)
+		return
+	}
+
+	m.processPartialBlock(peer, partial)
+}
+
+// OnBlockTxns should be invoked with blocktxns messages that are received from
+// remote peers in response to requests for the transactions of a compact block
+// that were not available locally.  It completes the reconstruction of the
+// associated block and processes it.
+//
+// Ideally, this should be called from the same peer goroutine that received the
+// message so the bulk of the processing is done concurrently and further reads
+// from the peer are blocked until the message is processed.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) OnBlockTxns(peer *Peer, msg *wire.MsgBlockTxns) {
+	if m.shutdownRequested() {
+		return
+	}
+
+	// The remote peer is misbehaving when the transactions were not requested.
+	m.requestMtx.Lock()
+	partial := peer.partialBlock
+	if partial == nil || partial.hash != msg.BlockHash {
+		m.requestMtx.Unlock()
+		log.Warnf("Got unrequested transactions for block %v from %s -- "+
+			"disconnecting", msg.BlockHash, peer)
+		peer.Disconnect()
+		return
+	}
+	peer.partialBlock = nil
+	m.requestMtx.Unlock()
+
+	if err := partial.fill(msg.Transactions); err != nil {
+		log.Warnf("Invalid block transactions from %s: %v -- disconnecting",
+			peer, err)
+		peer.Disconnect()
+		return
+	}
+	m.processPartialBlock(peer, partial)
+}
+
 // OnNotFound should be invoked from the sync manager with notfound messages
 // that are received from remote peers.
 //
@@ -1788,10 +2166,13 @@ func (m *SyncManager) OnNotFound(peer *Peer, notFound *wire.MsgNotFound) {
 		// Verify the hash was actually announced by the peer before deleting
 		// from the request maps.
 		switch inv.Type {
-		case wire.InvTypeBlock:
+		case wire.InvTypeBlock, wire.InvTypeCmpctBlock:
 			if m.isRequestedBlockFromPeer(peer, &inv.Hash) {
 				delete(m.requestedBlocks, inv.Hash)
 			}
+			if m.isRequestedBgBlockFromPeer(peer, &inv.Hash) {
+				delete(m.requestedBgBlocks, inv.Hash)
+			}
 		case wire.InvTypeTx:
 			if m.isRequestedTxFromPeer(peer, &inv.Hash) {
 				delete(m.requestedTxns, inv.Hash)
@@ -2208,6 +2589,26 @@ func (m *SyncManager) Run(ctx context.Context) {
 	log.Trace("Sync manager stopped")
 }
 
+// BackgroundValidator defines the interface for a validator that independently
+// downloads and validates blocks in the background, such as the blocks prior to
+// a snapshot the chain was created from.
+type BackgroundValidator interface {
+	// PutNextNeededBlocks populates the provided slice with the hashes of the
+	// next blocks the validator needs in the order they should be downloaded
+	// and returns a sub slice of it with the number of entries populated.
+	PutNextNeededBlocks(out []chainhash.Hash) []chainhash.Hash
+
+	// ProcessBlock validates the provided block that was requested on behalf
+	// of the validator.  The transactions of the block are ensured to match
+	// the merkle root commitments in its header before it is provided, so a
+	// block that violates the consensus rules is invalid as committed to by
+	// its header as opposed to having been modified by the remote peer.
+	ProcessBlock(block *dcrutil.Block) error
+
+	// Done returns whether or not the validator no longer needs any blocks.
+	Done() bool
+}
+
 // Config holds the configuration options related to the network chain
 // synchronization manager.
 type Config struct {
@@ -2252,6 +2653,15 @@ type Config struct {
 	// MixPool specifies the mixing pool to use for transient mixing
 	// messages broadcast across the network.
 	MixPool *mixpool.Pool
+
+	// BlockProcessed defines an optional function to invoke with the amount
+	// of time it took to validate and connect each block that is accepted by
+	// the chain.  It may be nil.
+	BlockProcessed func(elapsed time.Duration)
+
+	// BackgroundValidator specifies an optional validator to download blocks
+	// for once the initial chain sync is done.  It may be nil.
+	BackgroundValidator BackgroundValidator
 }
 
 // New returns a new network chain synchronization manager.  Use Run to begin
@@ -2268,18 +2678,19 @@ func New(config *Config) *SyncManager {
 	}
 
 	mgr := &SyncManager{
-		cfg:              *config,
-		rejectedTxns:     apbf.NewFilter(maxRejectedTxns, rejectedTxnsFPRate),
-		rejectedMixMsgs:  apbf.NewFilter(maxRejectedMixMsgs, rejectedMixMsgsFPRate),
-		requestedTxns:    make(map[chainhash.Hash]*Peer),
-		requestedBlocks:  make(map[chainhash.Hash]*Peer),
-		requestedMixMsgs: make(map[chainhash.Hash]*Peer),
-		warnOnNoSync:     true,
-		peers:            make(map[*Peer]struct{}),
-		minKnownWork:     minKnownWork,
-		hdrSyncState:     makeHeaderSyncState(),
-		progressLogger:   progresslog.New("Processed", log),
-		quit:             make(chan struct{}),
+		cfg:               *config,
+		rejectedTxns:      apbf.NewFilter(maxRejectedTxns, rejectedTxnsFPRate),
+		rejectedMixMsgs:   apbf.NewFilter(maxRejectedMixMsgs, rejectedMixMsgsFPRate),
+		requestedTxns:     make(map[chainhash.Hash]*Peer),
+		requestedBlocks:   make(map[chainhash.Hash]*Peer),
+		requestedMixMsgs:  make(map[chainhash.Hash]*Peer),
+		requestedBgBlocks: make(map[chainhash.Hash]*Peer),
+		warnOnNoSync:      true,
+		peers:             make(map[*Peer]struct{}),
+		minKnownWork:      minKnownWork,
+		hdrSyncState:      makeHeaderSyncState(),
+		progressLogger:    progresslog.New("Processed", log),
+		quit:              make(chan struct{}),
 	}
 	mgr.syncHeight.Store(config.Chain.BestSnapshot().Height)
 	mgr.isCurrent.Store(config.Chain.IsCurrent())
@@ -0,0 +1,245 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/crypto/rand"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+const (
+	// AdminGroupName is the name of the builtin permission group that permits
+	// all methods.  It is the group of the user configured with RPCUser and
+	// RPCPass.
+	AdminGroupName = "admin"
+
+	// LimitedGroupName is the name of the builtin permission group that only
+	// permits the methods that do not change the state of the server and
+	// can't be used to reveal sensitive information.  It is the group of the
+	// user configured with RPCLimitUser and RPCLimitPass.
+	LimitedGroupName = "limited"
+
+	// authSaltLen is the number of random bytes used for the salt of new
+	// hashed credentials.
+	authSaltLen = 16
+)
+
+// PermissionGroup is a named set of RPC methods that the users and client
+// certificates assigned to it are permitted to invoke.  Websocket
+// notifications are permitted by way of the methods that register for them
+// such as notifyblocks and loadtxfilter.
+type PermissionGroup struct {
+	name    string
+	all     bool
+	methods map[string]struct{}
+}
+
+var (
+	// adminGroup is the builtin permission group that permits all methods.
+	adminGroup = &PermissionGroup{name: AdminGroupName, all: true}
+
+	// limitedGroup is the builtin permission group that only permits the
+	// methods in the limited RPC methods map.
+	limitedGroup = &PermissionGroup{name: LimitedGroupName, methods: rpcLimited}
+)
+
+// BuiltinPermissionGroup returns the builtin permission group with the provided
+// name or nil when there is no builtin group with that name.
+func BuiltinPermissionGroup(name string) *PermissionGroup {
+	switch name {
+	case AdminGroupName:
+		return adminGroup
+	case LimitedGroupName:
+		return limitedGroup
+	}
+	return nil
+}
+
+// NewPermissionGroup returns a new permission group with the provided name that
+// permits the provided methods.  A method may also be the name of a builtin
+// permission group prefixed with an @ to permit all methods of that group, for
+// example, @limited.
+//
+// An error is returned when the name is empty or conflicts with a builtin
+// group, or when any of the methods are unknown.
+func NewPermissionGroup(name string, methods []string) (*PermissionGroup, error) {
+	if name == "" {
+		return nil, errors.New("permission group name must not be empty")
+	}
+	if BuiltinPermissionGroup(name) != nil {
+		return nil, fmt.Errorf("permission group name %q conflicts with a "+
+			"builtin group", name)
+	}
+
+	g := &PermissionGroup{
+		name:    name,
+		methods: make(map[string]struct{}, len(methods)),
+	}
+	for _, method := range methods {
+		if groupName, ok := strings.CutPrefix(method, "@"); ok {
+			builtin := BuiltinPermissionGroup(groupName)
+			if builtin == nil {
+				return nil, fmt.Errorf("unknown builtin permission group "+
+					"%q", groupName)
+			}
+			g.all = g.all || builtin.all
+			for m := range builtin.methods {
+				g.methods[m] = struct{}{}
+			}
+			continue
+		}
+
+		_, haveRegularHandler := rpcHandlers[types.Method(method)]
+		_, haveWebsocketHandler := wsHandlers[types.Method(method)]
+		if !haveRegularHandler && !haveWebsocketHandler {
+			return nil, fmt.Errorf("unknown RPC method %q", method)
+		}
+		g.methods[method] = struct{}{}
+	}
+	return g, nil
+}
+
+// Name returns the name of the permission group.
+func (g *PermissionGroup) Name() string {
+	return g.name
+}
+
+// allows returns whether or not the permission group permits the provided
+// method.  A nil group does not permit any methods.
+func (g *PermissionGroup) allows(method string) bool {
+	if g == nil {
+		return false
+	}
+	if g.all {
+		return true
+	}
+	_, ok := g.methods[method]
+	return ok
+}
+
+// unauthorizedMethodMsg returns the message of the error returned when a user
+// invokes a method that is not permitted by the provided permission group.
+func unauthorizedMethodMsg(g *PermissionGroup) string {
+	if g == limitedGroup {
+		return "limited user not authorized for this method"
+	}
+	return fmt.Sprintf("user in permission group %q not authorized for this "+
+		"method", g.name)
+}
+
+// AuthUser describes an RPC user that authenticates with a password that is
+// only known by its salted hash along with the permission group that
+// determines the methods the user may invoke.
+type AuthUser struct {
+	// Name is the username.
+	Name string
+
+	// Salt is the salt used to hash the password.
+	Salt string
+
+	// Hash is the HMAC-SHA256 of the password keyed by the salt.
+	Hash [sha256.Size]byte
+
+	// Group is the permission group of the user.
+	Group *PermissionGroup
+}
+
+// hashAuthPassword returns the HMAC-SHA256 of the provided password keyed by
+// the provided salt.
+func hashAuthPassword(salt, password string) [sha256.Size]byte {
+	var hash [sha256.Size]byte
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(password))
+	mac.Sum(hash[:0])
+	return hash
+}
+
+// GenerateAuthCredential returns the hashed credential for the provided
+// username and password in the form <username>:<salt>$<hash> that is accepted
+// by ParseAuthCredential.  The salt is randomly generated.
+func GenerateAuthCredential(username, password string) string {
+	var salt [authSaltLen]byte
+	rand.Read(salt[:])
+	saltStr := hex.EncodeToString(salt[:])
+	hash := hashAuthPassword(saltStr, password)
+	return username + ":" + saltStr + "$" + hex.EncodeToString(hash[:])
+}
+
+// ParseAuthCredential parses a hashed RPC credential in the form
+// <username>:<salt>$<hash>, where the hash is the hex-encoded HMAC-SHA256 of
+// the password keyed by the salt, and returns an RPC user with the provided
+// permission group.
+func ParseAuthCredential(credential string, group *PermissionGroup) (*AuthUser, error) {
+	username, saltHash, ok := strings.Cut(credential, ":")
+	if !ok || username == "" {
+		return nil, errors.New("credential must be in the form " +
+			"<username>:<salt>$<hash>")
+	}
+	salt, hashStr, ok := strings.Cut(saltHash, "$")
+	if !ok || salt == "" {
+		return nil, fmt.Errorf("credential for user %q must be in the form "+
+			"<username>:<salt>$<hash>", username)
+	}
+	hash, err := hex.DecodeString(hashStr)
+	if err != nil || len(hash) != sha256.Size {
+		return nil, fmt.Errorf("credential for user %q does not have a "+
+			"hex-encoded %d-byte hash", username, sha256.Size)
+	}
+
+	user := &AuthUser{
+		Name:  username,
+		Salt:  salt,
+		Group: group,
+	}
+	copy(user.Hash[:], hash)
+	return user, nil
+}
+
+// checkAuthUsers checks the HTTP Basic authentication string against the
+// configured hashed RPC user credentials and returns the permission group of
+// the matching user or nil when there is no match.
+func (s *Server) checkAuthUsers(auth string) *PermissionGroup {
+	encoded, ok := strings.CutPrefix(auth, "Basic ")
+	if !ok {
+		return nil
+	}
+	login, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+	username, password, ok := strings.Cut(string(login), ":")
+	if !ok {
+		return nil
+	}
+
+	// Hash the password even when the user does not exist to avoid revealing
+	// which usernames exist via timing.
+	user, ok := s.authUsers[username]
+	var salt string
+	if ok {
+		salt = user.Salt
+	}
+	hash := hashAuthPassword(salt, password)
+	if !ok || subtle.ConstantTimeCompare(hash[:], user.Hash[:]) != 1 {
+		return nil
+	}
+	return user.Group
+}
+
+// clientCertGroup returns the permission group for the subject of the provided
+// verified TLS client certificate or nil when it is not mapped to a group.
+func (s *Server) clientCertGroup(cert *x509.Certificate) *PermissionGroup {
+	return s.cfg.ClientCertGroups[cert.Subject.String()]
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestNewPermissionGroup ensures permission groups are created and validated
+// as expected.
+func TestNewPermissionGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string   // test description
+		group   string   // permission group name
+		methods []string // permitted methods
+		wantErr bool     // whether or not creating the group fails
+		allowed []string // methods that must be permitted
+		denied  []string // methods that must not be permitted
+	}{{
+		name:    "explicit methods",
+		group:   "monitor",
+		methods: []string{"getblockcount", "notifyblocks"},
+		allowed: []string{"getblockcount", "notifyblocks"},
+		denied:  []string{"getbestblock", "stop", "notifynewtransactions"},
+	}, {
+		name:    "builtin limited group plus extra method",
+		group:   "ops",
+		methods: []string{"@limited", "getpeerinfo"},
+		allowed: []string{"getbestblock", "getpeerinfo"},
+		denied:  []string{"stop", "addnode"},
+	}, {
+		name:    "builtin admin group",
+		group:   "everything",
+		methods: []string{"@admin"},
+		allowed: []string{"stop", "getpeerinfo"},
+	}, {
+		name:    "empty group name",
+		group:   "",
+		methods: []string{"getblockcount"},
+		wantErr: true,
+	}, {
+		name:    "builtin group name",
+		group:   LimitedGroupName,
+		methods: []string{"getblockcount"},
+		wantErr: true,
+	}, {
+		name:    "unknown method",
+		group:   "monitor",
+		methods: []string{"getblockcount", "notamethod"},
+		wantErr: true,
+	}, {
+		name:    "unknown builtin group",
+		group:   "monitor",
+		methods: []string{"@notagroup"},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		g, err := NewPermissionGroup(test.group, test.methods)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if g.Name() != test.group {
+			t.Errorf("%q: unexpected name -- got %q, want %q", test.name,
+				g.Name(), test.group)
+		}
+		for _, method := range test.allowed {
+			if !g.allows(method) {
+				t.Errorf("%q: method %q is not permitted", test.name, method)
+			}
+		}
+		for _, method := range test.denied {
+			if g.allows(method) {
+				t.Errorf("%q: method %q is permitted", test.name, method)
+			}
+		}
+	}
+}
+
+// TestParseAuthCredential ensures generated credentials round trip through the
+// parser and malformed credentials are rejected.
+func TestParseAuthCredential(t *testing.T) {
+	t.Parallel()
+
+	credential := GenerateAuthCredential("alice", "secret")
+	user, err := ParseAuthCredential(credential, limitedGroup)
+	if err != nil {
+		t.Fatalf("unexpected error parsing %q: %v", credential, err)
+	}
+	if user.Name != "alice" || user.Group != limitedGroup {
+		t.Fatalf("unexpected user: got %q in group %q", user.Name,
+			user.Group.Name())
+	}
+	if hashAuthPassword(user.Salt, "secret") != user.Hash {
+		t.Fatal("parsed hash does not match the password")
+	}
+
+	// Ensure the password is not part of the generated credential and that
+	// salts are random.
+	if strings.Contains(credential, "secret") {
+		t.Fatalf("credential %q contains the password", credential)
+	}
+	if GenerateAuthCredential("alice", "secret") == credential {
+		t.Fatal("generated credentials are not salted")
+	}
+
+	malformed := []string{
+		"",
+		"alice",
+		":salt$00",
+		"alice:salt",
+		"alice:$" + strings.Repeat("00", 32),
+		"alice:salt$nothex",
+		"alice:salt$" + strings.Repeat("00", 31),
+	}
+	for _, credential := range malformed {
+		if _, err := ParseAuthCredential(credential, adminGroup); err == nil {
+			t.Errorf("did not receive expected error parsing %q", credential)
+		}
+	}
+}
+
+// TestCheckAuthUsers ensures users with hashed credentials are authenticated
+// and assigned their configured permission groups.
+func TestCheckAuthUsers(t *testing.T) {
+	t.Parallel()
+
+	monitor, err := NewPermissionGroup("monitor", []string{"getblockcount"})
+	if err != nil {
+		t.Fatalf("unexpected error creating permission group: %v", err)
+	}
+	var users []*AuthUser
+	for _, credential := range []struct {
+		user, pass string
+		group      *PermissionGroup
+	}{
+		{"alice", "alicepass", monitor},
+		{"bob", "bobpass", adminGroup},
+	} {
+		user, err := ParseAuthCredential(GenerateAuthCredential(
+			credential.user, credential.pass), credential.group)
+		if err != nil {
+			t.Fatalf("unexpected error parsing credential: %v", err)
+		}
+		users = append(users, user)
+	}
+	s, err := New(&Config{
+		RPCLimitUser: "limit",
+		RPCLimitPass: "limit",
+		RPCAuthUsers: users,
+	})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		user       string
+		pass       string
+		wantAuthed bool
+		wantGroup  *PermissionGroup
+	}{{
+		name:       "custom group user",
+		user:       "alice",
+		pass:       "alicepass",
+		wantAuthed: true,
+		wantGroup:  monitor,
+	}, {
+		name:       "admin group user",
+		user:       "bob",
+		pass:       "bobpass",
+		wantAuthed: true,
+		wantGroup:  adminGroup,
+	}, {
+		name:       "limited user",
+		user:       "limit",
+		pass:       "limit",
+		wantAuthed: true,
+		wantGroup:  limitedGroup,
+	}, {
+		name: "wrong password",
+		user: "alice",
+		pass: "bobpass",
+	}, {
+		name: "unknown user",
+		user: "carol",
+		pass: "alicepass",
+	}}
+
+	for _, test := range tests {
+		authed, group := s.checkAuthUserPass(test.user, test.pass, "addr")
+		if authed != test.wantAuthed {
+			t.Errorf("%q: unexpected authed -- got %v, want %v", test.name,
+				authed, test.wantAuthed)
+		}
+		if group != test.wantGroup {
+			t.Errorf("%q: unexpected group -- got %v, want %v", test.name,
+				group, test.wantGroup)
+		}
+
+		login := test.user + ":" + test.pass
+		auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+		r := &http.Request{Header: http.Header{"Authorization": {auth}}}
+		authed, group, _ = s.checkAuth(r, true)
+		if authed != test.wantAuthed || group != test.wantGroup {
+			t.Errorf("%q: unexpected checkAuth result -- got %v/%v, want "+
+				"%v/%v", test.name, authed, group, test.wantAuthed,
+				test.wantGroup)
+		}
+	}
+}
+
+// TestCheckAuthClientCert ensures the subjects of verified TLS client
+// certificates are mapped to their configured permission groups.
+func TestCheckAuthClientCert(t *testing.T) {
+	t.Parallel()
+
+	certRequest := func(commonName string) *http.Request {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return &http.Request{TLS: &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}}
+	}
+
+	// Ensure all verified client certificates have full access when no
+	// subjects are mapped to permission groups.
+	s, err := New(&Config{})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	authed, group, err := s.checkAuth(certRequest("wallet"), true)
+	if !authed || group != adminGroup || err != nil {
+		t.Fatalf("unexpected result: got %v/%v/%v, want true/%v/nil",
+			authed, group, err, adminGroup)
+	}
+
+	// Ensure mapped subjects are assigned their group while unmapped subjects
+	// and requests without a verified certificate are rejected.
+	s, err = New(&Config{
+		ClientCertGroups: map[string]*PermissionGroup{
+			"CN=wallet":  adminGroup,
+			"CN=monitor": limitedGroup,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	tests := []struct {
+		name      string
+		r         *http.Request
+		wantGroup *PermissionGroup
+	}{
+		{"admin subject", certRequest("wallet"), adminGroup},
+		{"limited subject", certRequest("monitor"), limitedGroup},
+		{"unmapped subject", certRequest("other"), nil},
+		{"no certificate", &http.Request{}, nil},
+	}
+	for _, test := range tests {
+		authed, group, err := s.checkAuth(test.r, true)
+		wantAuthed := test.wantGroup != nil
+		if authed != wantAuthed || group != test.wantGroup {
+			t.Errorf("%q: unexpected result -- got %v/%v, want %v/%v",
+				test.name, authed, group, wantAuthed, test.wantGroup)
+		}
+		if (err != nil) == wantAuthed {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+		}
+	}
+}
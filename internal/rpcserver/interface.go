@@ -1,4 +1,4 @@
-// Copyright (c) 2019-2025 The Decred developers
+// Copyright (c) 2019-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -16,8 +16,10 @@ import (
 	"github.com/decred/dcrd/gcs/v4"
 	"github.com/decred/dcrd/internal/blockchain"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/decred/dcrd/internal/fees"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/internal/staging/banmanager"
 	"github.com/decred/dcrd/math/uint256"
 	"github.com/decred/dcrd/mixing"
 	"github.com/decred/dcrd/peer/v3"
@@ -92,6 +94,10 @@ type Peer interface {
 	// BanScore returns the current integer value that represents how close
 	// the peer is to being banned.
 	BanScore() uint32
+
+	// MappedAS returns the autonomous system number the peer address maps to
+	// or zero when it does not map to one or no map is in use.
+	MappedAS() uint32
 }
 
 // AddrManager represents an address manager for use with the RPC server.
@@ -168,6 +174,21 @@ type ConnManager interface {
 
 	// Lookup defines the DNS lookup function to be used.
 	Lookup(host string) ([]net.IP, error)
+
+	// BanSubnet bans the provided subnet until the provided expiration time
+	// for the provided reason and disconnects any connected peers within it.
+	// Attempting to ban a subnet that is already banned will return an error.
+	BanSubnet(subnet *net.IPNet, expires time.Time, reason string) error
+
+	// UnbanSubnet removes the ban for the provided subnet.  Attempting to
+	// unban a subnet that is not banned will return an error.
+	UnbanSubnet(subnet *net.IPNet) error
+
+	// BannedSubnets returns all currently banned subnets.
+	BannedSubnets() []banmanager.BanEntry
+
+	// ClearBans removes all bans.
+	ClearBans() error
 }
 
 // SyncManager represents a sync manager for use with the RPC server.
@@ -356,6 +377,10 @@ type Chain interface {
 	//  - Latest block has a timestamp newer than 24 hours ago
 	IsCurrent() bool
 
+	// IsPruned returns whether or not the chain is operating in pruned mode,
+	// meaning block data prior to PruneHeight might not be available.
+	IsPruned() bool
+
 	// LiveTickets returns all currently live tickets.
 	LiveTickets() ([]chainhash.Hash, error)
 
@@ -392,6 +417,14 @@ type Chain interface {
 	// given deployment ID for the block AFTER the provided block hash.
 	NextThresholdState(hash *chainhash.Hash, deploymentID string) (blockchain.ThresholdStateTuple, error)
 
+	// PruneHeight returns the height of the oldest block in the main chain for
+	// which block data is available.
+	PruneHeight() int64
+
+	// PruneTarget returns the configured target size in bytes for block data
+	// when pruning is enabled, or zero otherwise.
+	PruneTarget() uint64
+
 	// StateLastChangedHeight returns the height at which the provided consensus
 	// deployment agenda last changed state.  Note that, unlike the
 	// NextThresholdState function, this function returns the information as of
@@ -488,6 +521,17 @@ type FeeEstimator interface {
 	// confirmed in at most `targetConfs` blocks after publishing with a
 	// high degree of certainty.
 	EstimateFee(targetConfs int32) (dcrutil.Amount, error)
+
+	// EstimateRawFee calculates the suggested fee for a transaction to be
+	// confirmed in at most `targetConfs` blocks after publishing such that at
+	// least the provided success percentage of transactions are mined within
+	// it and returns the details of the estimate.  The details are also
+	// returned along with the error when there are not enough statistics
+	// that meet the success percentage.
+	EstimateRawFee(targetConfs int32, successPct float64) (*fees.RawFeeEstimate, error)
+
+	// Stats returns a copy of the internal state of the estimator.
+	Stats() *fees.EstimatorStats
 }
 
 // LogManager represents a log manager for use with the RPC server.
@@ -640,6 +684,21 @@ type TxMempooler interface {
 	// only.
 	VerboseTxDescs() []*mempool.VerboseTxDesc
 
+	// VerboseTxDesc returns a verbose descriptor for the transaction with the
+	// provided hash in the main pool.  The descriptor must be treated as read
+	// only.
+	VerboseTxDesc(txHash *chainhash.Hash) (*mempool.VerboseTxDesc, error)
+
+	// AncestorTxDescs returns verbose descriptors for all transactions in the
+	// main pool that the transaction with the provided hash depends on.  The
+	// descriptors must be treated as read only.
+	AncestorTxDescs(txHash *chainhash.Hash) ([]*mempool.VerboseTxDesc, error)
+
+	// DescendantTxDescs returns verbose descriptors for all transactions in
+	// the main pool that depend on the transaction with the provided hash.
+	// The descriptors must be treated as read only.
+	DescendantTxDescs(txHash *chainhash.Hash) ([]*mempool.VerboseTxDesc, error)
+
 	// Count returns the number of transactions in the main pool. It does
 	// not include the orphan pool.
 	Count() int
@@ -652,6 +711,43 @@ type TxMempooler interface {
 	// TSpendHashes returns the hashes of the treasury spend transactions
 	// currently in the mempool.
 	TSpendHashes() []chainhash.Hash
+
+	// MinRelayTxFee returns the minimum fee in atoms/kB that regular
+	// transactions must currently pay to be accepted into the mempool.  It
+	// is raised above the configured minimum relay fee when transactions are
+	// evicted due to the mempool size limit.
+	MinRelayTxFee() dcrutil.Amount
+
+	// TestAcceptTransactions runs the provided transactions through all of
+	// the checks performed when accepting transactions to the mempool without
+	// modifying it.  The transactions may depend on earlier transactions in
+	// the provided slice.
+	TestAcceptTransactions(txns []*dcrutil.Tx, allowHighFees bool) ([]*mempool.TestAcceptResult, error)
+}
+
+// MempoolSaver provides an interface for persisting the transactions in the
+// mempool to disk.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type MempoolSaver interface {
+	// SaveMempool writes all transactions in the mempool to the mempool dump
+	// file and returns the path to the file along with the number of
+	// transactions written.
+	SaveMempool() (string, int, error)
+}
+
+// UtxoSnapshotter provides an interface for writing chain state snapshots to
+// disk.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type UtxoSnapshotter interface {
+	// DumpUtxoSnapshot writes a chain state snapshot of the current main chain
+	// tip to the file at the provided path and returns the full path to the
+	// file along with the information that identifies the snapshot.  Relative
+	// paths are relative to the data directory.
+	DumpUtxoSnapshot(ctx context.Context, path string) (string, *blockchain.SnapshotInfo, error)
 }
 
 // MixPooler represents a source of mixpool message data for the RPC server.
@@ -688,6 +784,60 @@ type TxIndexer interface {
 	Entry(hash *chainhash.Hash) (*indexers.TxIndexEntry, error)
 }
 
+// AddrIndexer provides an interface for retrieving the transactions that
+// involve a given address.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+//
+// AddrIndexer may be nil. The RPC server must check for the presence of an
+// AddrIndexer before calling methods associated with it.
+type AddrIndexer interface {
+	// Name returns the human-readable name of the index.
+	Name() string
+
+	// Tip returns the current index tip.
+	Tip() (int64, *chainhash.Hash, error)
+
+	// WaitForSync subscribes clients for the next index sync update.
+	WaitForSync() chan bool
+
+	// EntriesForAddress returns the transaction index entries for
+	// transactions that involve the provided address along with the number
+	// of entries skipped.  The entries are ordered from oldest to newest
+	// unless the reverse flag is set.
+	EntriesForAddress(addr stdaddr.Address, numToSkip, numRequested uint32,
+		reverse bool) ([]indexers.TxIndexEntry, uint32, error)
+
+	// UnconfirmedTxnsForAddress returns all transactions currently in the
+	// memory pool that involve the provided address.
+	UnconfirmedTxnsForAddress(addr stdaddr.Address) []*dcrutil.Tx
+}
+
+// SpendIndexer provides an interface for retrieving the details of the
+// transaction that spent a given output.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+//
+// SpendIndexer may be nil. The RPC server must check for the presence of a
+// SpendIndexer before calling methods associated with it.
+type SpendIndexer interface {
+	// Name returns the human-readable name of the index.
+	Name() string
+
+	// Tip returns the current index tip.
+	Tip() (int64, *chainhash.Hash, error)
+
+	// WaitForSync subscribes clients for the next index sync update.
+	WaitForSync() chan bool
+
+	// Entry returns details about the transaction that spent the provided
+	// outpoint in the main chain.  When the output is unspent or does not
+	// exist, nil will be returned for both the entry and the error.
+	Entry(outpoint *wire.OutPoint) (*indexers.SpendIndexEntry, error)
+}
+
 // NtfnManager provides an interface for processing and sending chain
 // notifications.
 //
@@ -724,6 +874,10 @@ type NtfnManager interface {
 	// manager for processing.
 	NotifyMempoolTx(tx *dcrutil.Tx, isNew bool)
 
+	// NotifyMempoolRemoval passes a transaction removed from the mempool along
+	// with the reason it was removed to the manager for processing.
+	NotifyMempoolRemoval(tx *dcrutil.Tx, reason mempool.RemovalReason)
+
 	// NotifyMixMessage passes a mixing message accepted by the mixpool to the
 	// notification manager for message broadcasting.
 	NotifyMixMessage(msg mixing.Message)
@@ -787,13 +941,29 @@ type NtfnManager interface {
 	// of any newly-accepted mixing messages.
 	UnregisterMixMessages(wsc *wsClient)
 
+	// RegisterMempoolRemovals requests notifications to the passed websocket
+	// client when transactions are removed from the memory pool.
+	RegisterMempoolRemovals(wsc *wsClient)
+
+	// UnregisterMempoolRemovals removes notifications to the passed websocket
+	// client when transactions are removed from the memory pool.
+	UnregisterMempoolRemovals(wsc *wsClient)
+
 	// AddClient adds the passed websocket client to the notification manager.
 	AddClient(wsc *wsClient)
 
 	// RemoveClient removes the passed websocket client and all notifications
-	// registered for it.
+	// registered for it.  The session of a client that registered for block
+	// notifications or loaded a transaction filter is retained for a while so
+	// it may be resumed by a later connection.
 	RemoveClient(wsc *wsClient)
 
+	// ResumeSession transfers the session with the provided ID, along with
+	// its block notification and transaction filter registrations, to the
+	// passed websocket client and replays all retained notifications with a
+	// sequence number after the provided one.
+	ResumeSession(wsc *wsClient, sessionID, lastSeq uint64) (*types.ResumeSessionResult, error)
+
 	// Run starts the goroutines required for the manager to queue and process
 	// websocket client notifications. It blocks until the provided context is
 	// cancelled.
@@ -0,0 +1,314 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/container/lru"
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+const (
+	// rateLimitUser and rateLimitIP are the kinds of clients that requests
+	// are rate limited by.
+	rateLimitUser = "user"
+	rateLimitIP   = "ip"
+
+	// maxRateLimitBuckets is the maximum number of token buckets that are
+	// tracked.  The least recently used bucket is evicted to make room for a
+	// new one once the limit is reached, in which case the evicted client
+	// starts out with a full bucket on its next request.
+	maxRateLimitBuckets = 1000
+)
+
+// rpcMethodWeights specifies the number of tokens that are charged for the
+// methods that are considerably more expensive to process than a typical
+// request.  All other methods have a weight of one.  Methods whose cost
+// depends on their parameters are handled by requestWeight.
+var rpcMethodWeights = map[types.Method]float64{
+	"existsaddresses":       5,
+	"getcfilterv2":          2,
+	"getchaintips":          5,
+	"gettxoutsetinfo":       25,
+	"rescan":                25,
+	"searchrawtransactions": 10,
+	"ticketfeeinfo":         5,
+	"ticketsforaddress":     5,
+	"ticketvwap":            5,
+	"txfeeinfo":             5,
+	"verifychain":           25,
+}
+
+// requestWeight returns the number of tokens that are charged for processing
+// the provided parsed command.
+func requestWeight(method types.Method, params interface{}) float64 {
+	switch cmd := params.(type) {
+	case *types.GetBlockCmd:
+		switch {
+		case cmd.Verbose != nil && *cmd.Verbose &&
+			cmd.VerboseTx != nil && *cmd.VerboseTx:
+			return 10
+		case cmd.Verbose != nil && *cmd.Verbose:
+			return 4
+		}
+		return 1
+
+	case *types.GetRawTransactionCmd:
+		if cmd.Verbose != nil && *cmd.Verbose != 0 {
+			return 2
+		}
+		return 1
+	}
+
+	if weight, ok := rpcMethodWeights[method]; ok {
+		return weight
+	}
+	return 1
+}
+
+// rateLimitID identifies the clients a request is charged to for the purposes
+// of rate limiting.
+type rateLimitID struct {
+	// user is the name the client authenticated with.  It is empty when the
+	// client did not authenticate with a username.
+	user string
+
+	// host is the remote IP address of the client.  It is empty when the
+	// client is not connected over IP such as via a unix domain socket.
+	host string
+}
+
+// newRateLimitID returns the rate limit identity for the provided username and
+// remote address.
+func newRateLimitID(user, remoteAddr string) rateLimitID {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil || net.ParseIP(host) == nil {
+		host = ""
+	}
+	return rateLimitID{user: user, host: host}
+}
+
+// requestUser returns the name of the user of the provided HTTP request for the
+// purposes of rate limiting.  It is the username from the HTTP Basic
+// authentication header or the subject of the verified TLS client certificate
+// when there is no header.
+func requestUser(r *http.Request) string {
+	user, _, ok := r.BasicAuth()
+	if !ok && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		user = r.TLS.VerifiedChains[0][0].Subject.String()
+	}
+	return user
+}
+
+// requestRateLimitID returns the rate limit identity for the provided HTTP
+// request.
+func requestRateLimitID(r *http.Request) rateLimitID {
+	return newRateLimitID(requestUser(r), r.RemoteAddr)
+}
+
+// tokenBucket houses the state of the token bucket of a single client.  Tokens
+// are added at the rate of the limiter up to the burst size of the limiter and
+// requests consume tokens according to their weight.
+type tokenBucket struct {
+	tokens     float64
+	lastUpdate time.Time
+	requests   uint64
+	limited    uint64
+}
+
+// refill adds the tokens accumulated since the last update of the bucket.
+func (b *tokenBucket) refill(now time.Time, rate, burst float64) {
+	elapsed := now.Sub(b.lastUpdate).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*rate)
+		b.lastUpdate = now
+	}
+}
+
+// rateLimitKey is the key of a token bucket tracked by the rate limiter.
+type rateLimitKey struct {
+	kind string
+	name string
+}
+
+// rateLimiter limits the rate of RPC and REST requests of each authenticated
+// user and each remote IP address with independent token buckets.  A request is only
+// permitted when all buckets it is charged to have enough tokens for its
+// weight.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mtx     sync.Mutex
+	buckets *lru.Map[rateLimitKey, *tokenBucket]
+	nowFunc func() time.Time
+}
+
+// newRateLimiter returns a new rate limiter that refills the bucket of each
+// client with the provided number of tokens per second up to the provided
+// burst size.
+func newRateLimiter(rate, burst float64) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: lru.NewMap[rateLimitKey, *tokenBucket](maxRateLimitBuckets),
+		nowFunc: time.Now,
+	}
+}
+
+// bucket returns the token bucket for the provided key creating a full one if
+// needed.  Creating a bucket evicts the least recently used one when the
+// maximum number of buckets are already tracked.
+//
+// This function MUST be called with the limiter mutex held (for writes).
+func (l *rateLimiter) bucket(key rateLimitKey, now time.Time) *tokenBucket {
+	b, ok := l.buckets.Get(key)
+	if ok {
+		b.refill(now, l.rate, l.burst)
+		return b
+	}
+	b = &tokenBucket{tokens: l.burst, lastUpdate: now}
+	l.buckets.Put(key, b)
+	return b
+}
+
+// allow charges the provided weight to the buckets of the clients identified by
+// the provided id and returns nil when all of them have enough tokens.
+// Otherwise, no tokens are consumed and an error suitable for use in replies
+// that describes the client that is over its limit is returned.  Weights that
+// exceed the burst size are reduced to it so every request is possible once a
+// bucket is full.
+//
+// This function is safe for concurrent access.
+func (l *rateLimiter) allow(id rateLimitID, method types.Method, weight float64) error {
+	weight = math.Min(weight, l.burst)
+
+	keys := make([]rateLimitKey, 0, 2)
+	if id.user != "" {
+		keys = append(keys, rateLimitKey{kind: rateLimitUser, name: id.user})
+	}
+	if id.host != "" {
+		keys = append(keys, rateLimitKey{kind: rateLimitIP, name: id.host})
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := l.nowFunc()
+	buckets := make([]*tokenBucket, 0, len(keys))
+	for _, key := range keys {
+		buckets = append(buckets, l.bucket(key, now))
+	}
+	for i, b := range buckets {
+		if b.tokens < weight {
+			b.limited++
+			wait := time.Duration((weight - b.tokens) / l.rate *
+				float64(time.Second))
+			key := keys[i]
+			log.Debugf("RPC rate limit exceeded for %s %s calling %s",
+				key.kind, key.name, method)
+			return dcrjson.NewRPCError(dcrjson.ErrRPCRateLimited,
+				fmt.Sprintf("rate limit exceeded for %s %q: request "+
+					"weight %g exceeds the %.2f available tokens -- "+
+					"retry in %v", key.kind, key.name, weight, b.tokens,
+					wait.Round(time.Millisecond)))
+		}
+	}
+	for _, b := range buckets {
+		b.tokens -= weight
+		b.requests++
+	}
+	return nil
+}
+
+// info returns the current state of all tracked token buckets sorted by kind
+// and name.
+//
+// This function is safe for concurrent access.
+func (l *rateLimiter) info() []types.RateLimitClientResult {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := l.nowFunc()
+	keys := l.buckets.Keys()
+	clients := make([]types.RateLimitClientResult, 0, len(keys))
+	for _, key := range keys {
+		b, ok := l.buckets.Peek(key)
+		if !ok {
+			continue
+		}
+		b.refill(now, l.rate, l.burst)
+		clients = append(clients, types.RateLimitClientResult{
+			Type:     key.kind,
+			Name:     key.name,
+			Tokens:   b.tokens,
+			Requests: b.requests,
+			Limited:  b.limited,
+		})
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if clients[i].Type != clients[j].Type {
+			return clients[i].Type < clients[j].Type
+		}
+		return clients[i].Name < clients[j].Name
+	})
+	return clients
+}
+
+// checkRateLimit returns an error suitable for use in replies when the client
+// identified by the provided id is over its rate limit for the provided parsed
+// command.  Clients in the admin permission group are never rate limited.
+func (s *Server) checkRateLimit(group *PermissionGroup, id rateLimitID, cmd *parsedRPCCmd) error {
+	if s.rateLimiter == nil || group == adminGroup {
+		return nil
+	}
+	return s.rateLimiter.allow(id, cmd.method, requestWeight(cmd.method,
+		cmd.params))
+}
+
+// restRequestWeight returns the number of tokens that are charged for
+// processing the REST request with the provided name and format.  The weights
+// match those of the equivalent RPC methods.
+func restRequestWeight(name string, format restFormat) float64 {
+	if format != restFormatJSON {
+		return 1
+	}
+	switch name {
+	case "block":
+		return 10
+	case "tx":
+		return 2
+	}
+	return 1
+}
+
+// checkRESTRateLimit returns an error suitable for use in REST replies when the
+// client of the provided REST request is over its rate limit for the request
+// with the provided name and format.  REST requests are not authenticated, so
+// they are only charged to the bucket of the remote IP address.
+func (s *Server) checkRESTRateLimit(r *http.Request, name string, format restFormat) *restError {
+	if s.rateLimiter == nil {
+		return nil
+	}
+	id := newRateLimitID("", r.RemoteAddr)
+	method := types.Method("rest/" + name)
+	err := s.rateLimiter.allow(id, method, restRequestWeight(name, format))
+	if err != nil {
+		var rpcErr *dcrjson.RPCError
+		if errors.As(err, &rpcErr) {
+			return restErrorf(http.StatusTooManyRequests, "%s",
+				rpcErr.Message)
+		}
+		return restErrorf(http.StatusTooManyRequests, "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+// TestRequestWeight ensures requests are weighted according to their method
+// and parameters as expected.
+func TestRequestWeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string      // test description
+		method string      // request method
+		params interface{} // parsed request parameters
+		want   float64     // expected weight
+	}{{
+		name:   "regular method",
+		method: "getblockcount",
+		params: &types.GetBlockCountCmd{},
+		want:   1,
+	}, {
+		name:   "expensive method",
+		method: "rescan",
+		params: &types.RescanCmd{},
+		want:   25,
+	}, {
+		name:   "raw block",
+		method: "getblock",
+		params: types.NewGetBlockCmd("", dcrjson.Bool(false), nil),
+		want:   1,
+	}, {
+		name:   "verbose block",
+		method: "getblock",
+		params: types.NewGetBlockCmd("", dcrjson.Bool(true), nil),
+		want:   4,
+	}, {
+		name:   "verbose block with transactions",
+		method: "getblock",
+		params: types.NewGetBlockCmd("", dcrjson.Bool(true),
+			dcrjson.Bool(true)),
+		want: 10,
+	}, {
+		name:   "verbose transaction",
+		method: "getrawtransaction",
+		params: types.NewGetRawTransactionCmd("", dcrjson.Int(1)),
+		want:   2,
+	}}
+
+	for _, test := range tests {
+		got := requestWeight(types.Method(test.method), test.params)
+		if got != test.want {
+			t.Errorf("%q: unexpected weight -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestRateLimiter ensures the rate limiter charges requests to the buckets of
+// both the user and the remote IP, rejects requests that exceed the available
+// tokens of either without consuming any tokens, and refills the buckets over
+// time.
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	limiter := newRateLimiter(2, 10)
+	limiter.nowFunc = func() time.Time { return now }
+
+	alice := newRateLimitID("alice", "10.0.0.1:9109")
+	bob := newRateLimitID("bob", "10.0.0.1:9110")
+	carol := newRateLimitID("carol", "10.0.0.2:9109")
+	const method = "getblock"
+
+	// Alice consumes most of the tokens of her bucket and the bucket of the
+	// shared IP address.
+	if err := limiter.allow(alice, method, 8); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Bob is limited by the shared IP address even though his user bucket is
+	// full while carol is unaffected.
+	err := limiter.allow(bob, method, 4)
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != dcrjson.ErrRPCRateLimited {
+		t.Fatalf("unexpected error -- got %v, want code %v", err,
+			dcrjson.ErrRPCRateLimited)
+	}
+	if err := limiter.allow(carol, method, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Weights that exceed the burst size are reduced to it.
+	if err := limiter.allow(carol, method, 100); err == nil {
+		t.Fatal("request over the limit was permitted")
+	}
+	now = now.Add(2 * time.Second)
+	if err := limiter.allow(carol, method, 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The tokens refill over time.
+	if err := limiter.allow(bob, method, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []types.RateLimitClientResult{
+		{Type: "ip", Name: "10.0.0.1", Tokens: 2, Requests: 2, Limited: 1},
+		{Type: "ip", Name: "10.0.0.2", Tokens: 0, Requests: 2},
+		{Type: "user", Name: "alice", Tokens: 6, Requests: 1},
+		{Type: "user", Name: "bob", Tokens: 6, Requests: 1},
+		{Type: "user", Name: "carol", Tokens: 0, Requests: 2, Limited: 1},
+	}
+	if got := limiter.info(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected info -- got %+v, want %+v", got, want)
+	}
+}
+
+// TestRateLimiterEviction ensures the rate limiter does not track more than the
+// maximum number of buckets and evicts the least recently used bucket to make
+// room for new ones.
+func TestRateLimiterEviction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	limiter := newRateLimiter(1, 10)
+	limiter.nowFunc = func() time.Time { return now }
+	const method = "getblock"
+
+	// Drain the buckets of two clients and then create enough buckets for
+	// other clients to reach the limit while continuing to use the second
+	// client so it remains recently used.
+	first := newRateLimitID("", "10.0.0.1:9109")
+	second := newRateLimitID("", "10.0.0.2:9109")
+	for _, id := range []rateLimitID{first, second} {
+		if err := limiter.allow(id, method, 10); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for i := 0; i < maxRateLimitBuckets; i++ {
+		remoteAddr := fmt.Sprintf("10.1.%d.%d:9109", i/256, i%256)
+		id := newRateLimitID("", remoteAddr)
+		if err := limiter.allow(id, method, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i%100 == 0 {
+			if err := limiter.allow(second, method, 1); err == nil {
+				t.Fatal("request over the limit was permitted")
+			}
+		}
+	}
+	if n := limiter.buckets.Len(); n != maxRateLimitBuckets {
+		t.Fatalf("unexpected number of buckets -- got %d, want %d", n,
+			maxRateLimitBuckets)
+	}
+
+	// The least recently used bucket of the first client was evicted, so it
+	// starts out full again, while the second client is still limited.
+	if _, ok := limiter.buckets.Peek(rateLimitKey{rateLimitIP, first.host}); ok {
+		t.Fatal("least recently used bucket was not evicted")
+	}
+	if err := limiter.allow(first, method, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := limiter.allow(second, method, 1); err == nil {
+		t.Fatal("request over the limit was permitted")
+	}
+}
+
+// TestCheckRateLimit ensures the server only rate limits clients outside of
+// the admin permission group and reports the rate limiter state via the
+// getratelimitinfo handler.
+func TestCheckRateLimit(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{cfg: Config{RPCRateLimit: 1, RPCRateBurst: 25}}
+	s.rateLimiter = newRateLimiter(s.cfg.RPCRateLimit, s.cfg.RPCRateBurst)
+	cmd := &parsedRPCCmd{method: "rescan", params: &types.RescanCmd{}}
+	id := newRateLimitID("user", "127.0.0.1:9109")
+	for i := 0; i < 3; i++ {
+		if err := s.checkRateLimit(adminGroup, id, cmd); err != nil {
+			t.Fatalf("admin request #%d: unexpected error: %v", i, err)
+		}
+	}
+	if err := s.checkRateLimit(limitedGroup, id, cmd); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.checkRateLimit(limitedGroup, id, cmd); err == nil {
+		t.Fatal("request over the limit was permitted")
+	}
+
+	result, err := handleGetRateLimitInfo(context.Background(), s, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info := result.(*types.GetRateLimitInfoResult)
+	if !info.Enabled || info.Rate != 1 || info.Burst != 25 ||
+		len(info.Clients) != 2 || info.Weights["rescan"] != 25 {
+
+		t.Fatalf("unexpected rate limit info: %+v", info)
+	}
+
+	// Ensure rate limiting is reported as disabled without a limiter.
+	s = &Server{}
+	result, err = handleGetRateLimitInfo(context.Background(), s, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info = result.(*types.GetRateLimitInfoResult)
+	if info.Enabled || len(info.Clients) != 0 {
+		t.Fatalf("unexpected rate limit info: %+v", info)
+	}
+}
+
+// TestRESTRateLimit ensures REST requests are charged to the bucket of their
+// remote IP address according to their weight and are rejected once it is
+// over its limit.
+func TestRESTRateLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultMockConfig(defaultChainParams)
+	s := &Server{
+		cfg:         *cfg,
+		ntfnMgr:     new(testNtfnManager),
+		workState:   newWorkState(),
+		helpCacher:  &testHelpCacher{},
+		rateLimiter: newRateLimiter(1, 12),
+	}
+	serve := func(path, remoteAddr string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		s.serveREST(rec, req)
+		return rec.Code
+	}
+
+	// The JSON block request consumes 10 tokens and each hash request
+	// consumes one, so the third hash request exceeds the limit.
+	const addr = "10.0.0.1:9109"
+	const blockPath = "/rest/block/" +
+		"298e5cc3d985bfe7f81dc135f360abe089edd4396b86d2de66b0cef42b21d980" +
+		".json"
+	const hashPath = "/rest/blockhashbyheight/0.hex"
+	serve(blockPath, addr)
+	for i := 0; i < 2; i++ {
+		if code := serve(hashPath, addr); code != http.StatusOK {
+			t.Fatalf("request #%d: unexpected status code -- got %d, "+
+				"want %d", i, code, http.StatusOK)
+		}
+	}
+	if code := serve(hashPath, addr); code != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status code -- got %d, want %d", code,
+			http.StatusTooManyRequests)
+	}
+
+	// Other remote IP addresses are unaffected.
+	if code := serve(hashPath, "10.0.0.2:9109"); code != http.StatusOK {
+		t.Fatalf("unexpected status code -- got %d, want %d", code,
+			http.StatusOK)
+	}
+}
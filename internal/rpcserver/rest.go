@@ -0,0 +1,496 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	stdlog "log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// restPathPrefix is the URL path prefix for all REST requests.
+	restPathPrefix = "/rest/"
+
+	// restReadTimeout is the maximum amount of time allowed to read an
+	// entire REST request.
+	restReadTimeout = time.Second * 10
+
+	// restMaxHeaders is the maximum number of headers that may be requested
+	// by a single REST headers request.
+	restMaxHeaders = wire.MaxBlockHeadersPerMsg
+)
+
+// restFormat describes the output format of a REST response.
+type restFormat int
+
+// These constants define the supported REST response formats.
+const (
+	restFormatBinary restFormat = iota
+	restFormatHex
+	restFormatJSON
+)
+
+// restFormatsByExt maps the path extensions of REST requests to the associated
+// output format.
+var restFormatsByExt = map[string]restFormat{
+	"bin":  restFormatBinary,
+	"hex":  restFormatHex,
+	"json": restFormatJSON,
+}
+
+// restError describes a REST request that failed along with the HTTP status
+// code to respond with.
+type restError struct {
+	status  int
+	message string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *restError) Error() string {
+	return e.message
+}
+
+// restErrorf creates a restError given a set of arguments.
+func restErrorf(status int, format string, args ...interface{}) *restError {
+	return &restError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// restRPCError converts an error returned by an RPC handler to a restError
+// with an appropriate HTTP status code.
+func restRPCError(err error) *restError {
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) {
+		return restErrorf(http.StatusInternalServerError, "%v", err)
+	}
+
+	status := http.StatusInternalServerError
+	switch rpcErr.Code {
+	case dcrjson.ErrRPCBlockNotFound, dcrjson.ErrRPCOutOfRange:
+		status = http.StatusNotFound
+	case dcrjson.ErrRPCInvalidParameter, dcrjson.ErrRPCDecodeHexString:
+		status = http.StatusBadRequest
+	}
+	return restErrorf(status, "%s", rpcErr.Message)
+}
+
+// restResult houses the result of a REST request.  Handlers only populate the
+// fields required by the requested format, so the others are nil.  The binary
+// and hex formats are always populated together since they are both derived
+// from the same serialized data.
+type restResult struct {
+	bin  []byte
+	hex  string
+	json interface{}
+}
+
+// restHandler describes a callback function used to handle a specific REST
+// request.  The provided params are the components of the request path that
+// follow the name of the request with the format extension removed.  Handlers
+// only compute the representation required by the provided format since the
+// verbose JSON results are significantly more expensive to produce.
+type restHandler func(ctx context.Context, s *Server, params []string, format restFormat) (*restResult, error)
+
+// restHandlers maps the names of REST requests to their handler and the
+// number of path components they expect.
+var restHandlers = map[string]struct {
+	handler   restHandler
+	numParams int
+	formats   []restFormat
+}{
+	"block":             {restBlock, 1, nil},
+	"headers":           {restHeaders, 2, nil},
+	"tx":                {restTx, 1, nil},
+	"blockhashbyheight": {restBlockHashByHeight, 1, nil},
+	"cfilterv2":         {restCFilterV2, 1, nil},
+	"chaininfo":         {restChainInfo, 0, []restFormat{restFormatJSON}},
+}
+
+// restParseHash converts the provided string to a hash and returns a restError
+// when it is not valid.
+func restParseHash(s string) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil || len(s) != chainhash.MaxHashStringSize {
+		return nil, restErrorf(http.StatusBadRequest, "invalid hash: %s", s)
+	}
+	return hash, nil
+}
+
+// restHexResult returns a result for the provided hex-encoded string returned
+// by an RPC handler in all of the formats other than JSON.
+func restHexResult(result interface{}) (*restResult, error) {
+	hexStr, ok := result.(string)
+	if !ok {
+		return nil, restErrorf(http.StatusInternalServerError,
+			"unexpected result type %T", result)
+	}
+	bin, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, restErrorf(http.StatusInternalServerError,
+			"failed to decode result: %v", err)
+	}
+	return &restResult{bin: bin, hex: hexStr}, nil
+}
+
+// restBlock handles /rest/block/<hash> requests.  The JSON format is the same
+// as the result of the getblock RPC with both the verbose and verbose
+// transaction flags set.
+func restBlock(ctx context.Context, s *Server, params []string, format restFormat) (*restResult, error) {
+	hash, err := restParseHash(params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	verbose := format == restFormatJSON
+	result, err := handleGetBlock(ctx, s, &types.GetBlockCmd{
+		Hash:      hash.String(),
+		Verbose:   &verbose,
+		VerboseTx: &verbose,
+	})
+	if err != nil {
+		return nil, restRPCError(err)
+	}
+	if verbose {
+		return &restResult{json: result}, nil
+	}
+	return restHexResult(result)
+}
+
+// restHeaders handles /rest/headers/<count>/<hash> requests.  It returns up to
+// the requested number of headers in the main chain starting with the provided
+// block.  The JSON format is an array of the results of the getblockheader RPC
+// with the verbose flag set.
+func restHeaders(ctx context.Context, s *Server, params []string, format restFormat) (*restResult, error) {
+	count, err := strconv.ParseUint(params[0], 10, 32)
+	if err != nil || count < 1 || count > restMaxHeaders {
+		return nil, restErrorf(http.StatusBadRequest, "header count must "+
+			"be between 1 and %d: %s", restMaxHeaders, params[0])
+	}
+	hash, err := restParseHash(params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the requested headers by following the main chain from the
+	// provided block.
+	chain := s.cfg.Chain
+	if _, err := chain.HeaderByHash(hash); err != nil {
+		return nil, restErrorf(http.StatusNotFound, "Block not found: %v",
+			hash)
+	}
+	var hashes []chainhash.Hash
+	if chain.MainChainHasBlock(hash) {
+		height, err := chain.BlockHeightByHash(hash)
+		if err != nil {
+			return nil, restRPCError(rpcInternalErr(err,
+				"Failed to fetch block height"))
+		}
+		endHeight := height + int64(count)
+		if bestHeight := chain.BestSnapshot().Height; endHeight > bestHeight {
+			endHeight = bestHeight + 1
+		}
+		hashes, err = chain.HeightRange(height, endHeight)
+		if err != nil {
+			return nil, restRPCError(rpcInternalErr(err,
+				"Failed to fetch block hashes"))
+		}
+	}
+
+	verbose := format == restFormatJSON
+	var buf bytes.Buffer
+	var headers []interface{}
+	if verbose {
+		headers = make([]interface{}, 0, len(hashes))
+	}
+	for i := range hashes {
+		result, err := handleGetBlockHeader(ctx, s, &types.GetBlockHeaderCmd{
+			Hash:    hashes[i].String(),
+			Verbose: &verbose,
+		})
+		if err != nil {
+			return nil, restRPCError(err)
+		}
+		if verbose {
+			headers = append(headers, result)
+			continue
+		}
+		res, err := restHexResult(result)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(res.bin)
+	}
+
+	if verbose {
+		return &restResult{json: headers}, nil
+	}
+	return &restResult{
+		bin: buf.Bytes(),
+		hex: hex.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// restTx handles /rest/tx/<hash> requests.  The transaction is loaded from the
+// mempool or, when it is enabled, the transaction index.  The JSON format is
+// the same as the result of the getrawtransaction RPC with the verbose flag
+// set.
+func restTx(ctx context.Context, s *Server, params []string, format restFormat) (*restResult, error) {
+	hash, err := restParseHash(params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var verbose int
+	if format == restFormatJSON {
+		verbose = 1
+	}
+	result, err := handleGetRawTransaction(ctx, s, &types.GetRawTransactionCmd{
+		Txid:    hash.String(),
+		Verbose: &verbose,
+	})
+	if err != nil {
+		return nil, restRPCError(err)
+	}
+	if verbose != 0 {
+		return &restResult{json: result}, nil
+	}
+	return restHexResult(result)
+}
+
+// restBlockHashResult models the JSON result of /rest/blockhashbyheight
+// requests.
+type restBlockHashResult struct {
+	BlockHash string `json:"blockhash"`
+}
+
+// restBlockHashByHeight handles /rest/blockhashbyheight/<height> requests.  The
+// binary format is the hash in its internal byte order while the hex format is
+// the same byte-reversed string returned by the getblockhash RPC.
+func restBlockHashByHeight(ctx context.Context, s *Server, params []string, _ restFormat) (*restResult, error) {
+	height, err := strconv.ParseInt(params[0], 10, 64)
+	if err != nil || height < 0 {
+		return nil, restErrorf(http.StatusBadRequest, "invalid height: %s",
+			params[0])
+	}
+
+	result, err := handleGetBlockHash(ctx, s, &types.GetBlockHashCmd{
+		Index: height,
+	})
+	if err != nil {
+		return nil, restRPCError(err)
+	}
+	hashStr, ok := result.(string)
+	if !ok {
+		return nil, restErrorf(http.StatusInternalServerError,
+			"unexpected result type %T", result)
+	}
+	hash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		return nil, restErrorf(http.StatusInternalServerError,
+			"failed to decode result: %v", err)
+	}
+
+	return &restResult{
+		bin:  hash[:],
+		hex:  hashStr,
+		json: &restBlockHashResult{BlockHash: hashStr},
+	}, nil
+}
+
+// restCFilterV2 handles /rest/cfilterv2/<hash> requests.  The binary and hex
+// formats are the serialized version 2 committed filter while the JSON format
+// is the same as the result of the getcfilterv2 RPC.
+func restCFilterV2(ctx context.Context, s *Server, params []string, _ restFormat) (*restResult, error) {
+	hash, err := restParseHash(params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := handleGetCFilterV2(ctx, s, &types.GetCFilterV2Cmd{
+		BlockHash: hash.String(),
+	})
+	if err != nil {
+		return nil, restRPCError(err)
+	}
+	filterResult, ok := result.(*types.GetCFilterV2Result)
+	if !ok {
+		return nil, restErrorf(http.StatusInternalServerError,
+			"unexpected result type %T", result)
+	}
+	res, err := restHexResult(filterResult.Data)
+	if err != nil {
+		return nil, err
+	}
+	res.json = filterResult
+	return res, nil
+}
+
+// restChainInfo handles /rest/chaininfo requests.  Only the JSON format is
+// supported and it is the same as the result of the getblockchaininfo RPC.
+func restChainInfo(ctx context.Context, s *Server, _ []string, _ restFormat) (*restResult, error) {
+	result, err := handleGetBlockchainInfo(ctx, s, nil)
+	if err != nil {
+		return nil, restRPCError(err)
+	}
+	return &restResult{json: result}, nil
+}
+
+// restFormatNames returns a human-readable list of the provided formats.
+func restFormatNames(formats []restFormat) string {
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		for ext, f := range restFormatsByExt {
+			if f == format {
+				names = append(names, ext)
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// serveREST parses the provided REST request, invokes the associated handler,
+// and writes the result in the requested format.
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request) {
+	writeErr := func(err *restError) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(err.status)
+		fmt.Fprintln(w, err.message)
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeErr(restErrorf(http.StatusMethodNotAllowed,
+			"method not allowed: %s", r.Method))
+		return
+	}
+
+	// Split the path into the request name and its parameters and determine
+	// the requested format from the extension of the final component.
+	path := strings.TrimPrefix(r.URL.Path, restPathPrefix)
+	ext := ""
+	if idx := strings.LastIndexByte(path, '.'); idx != -1 {
+		path, ext = path[:idx], path[idx+1:]
+	}
+	components := strings.Split(path, "/")
+	name, params := components[0], components[1:]
+	if len(params) == 1 && params[0] == "" {
+		params = nil
+	}
+	entry, ok := restHandlers[name]
+	if !ok || len(params) != entry.numParams {
+		writeErr(restErrorf(http.StatusNotFound, "not found: %s",
+			r.URL.Path))
+		return
+	}
+	formats := entry.formats
+	if formats == nil {
+		formats = []restFormat{restFormatBinary, restFormatHex,
+			restFormatJSON}
+	}
+	format, ok := restFormatsByExt[ext]
+	if ok {
+		ok = false
+		for _, f := range formats {
+			if f == format {
+				ok = true
+				break
+			}
+		}
+	}
+	if !ok {
+		writeErr(restErrorf(http.StatusNotFound, "output format not "+
+			"found (available: %s)", restFormatNames(formats)))
+		return
+	}
+
+	if err := s.checkRESTRateLimit(r, name, format); err != nil {
+		writeErr(err)
+		return
+	}
+
+	res, err := entry.handler(r.Context(), s, params, format)
+	if err != nil {
+		var restErr *restError
+		if !errors.As(err, &restErr) {
+			restErr = restErrorf(http.StatusInternalServerError, "%v", err)
+		}
+		writeErr(restErr)
+		return
+	}
+
+	var body []byte
+	switch format {
+	case restFormatBinary:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		body = res.bin
+
+	case restFormatHex:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		body = []byte(res.hex + "\n")
+
+	case restFormatJSON:
+		w.Header().Set("Content-Type", "application/json")
+		body, err = json.Marshal(res.json)
+		if err != nil {
+			writeErr(restErrorf(http.StatusInternalServerError,
+				"failed to marshal result: %v", err))
+			return
+		}
+		body = append(body, '\n')
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+// restRoute sets up the endpoint for the REST interface.
+func (s *Server) restRoute(ctx context.Context) *http.Server {
+	restServeMux := http.NewServeMux()
+	httpServer := &http.Server{
+		Handler: restServeMux,
+
+		// Use the provided context as the parent context for all requests to
+		// ensure handlers are able to react to both client disconnects as well
+		// as shutdown via the provided context.
+		BaseContext: func(l net.Listener) context.Context {
+			return ctx
+		},
+
+		ReadTimeout: restReadTimeout,
+
+		// Reroute http server error logging through the rpcserver
+		// logger.
+		ErrorLog: stdlog.New(logForwarder{}, "", 0),
+	}
+	restServeMux.HandleFunc(restPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		// Limit the number of connections to max allowed.
+		if s.limitConnections(w, r.RemoteAddr) {
+			return
+		}
+
+		// Keep track of the number of connected clients.
+		s.incrementClients()
+		defer s.decrementClients()
+
+		s.serveREST(w, r)
+	})
+	return httpServer
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+// TestREST ensures the REST interface returns the expected status codes,
+// content types, and bodies for the supported requests and formats and that
+// the results match those of the associated RPC handlers.
+func TestREST(t *testing.T) {
+	t.Parallel()
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkHash := blk.Hash().String()
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize block: %v", err)
+	}
+	hdrBytes, err := block432100.Header.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize header: %v", err)
+	}
+	tx := blk.Transactions()[0]
+	txHash := tx.Hash().String()
+	txBytes, err := tx.MsgTx().Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize tx: %v", err)
+	}
+	filter := defaultMockFiltererV2().filterByBlockHash.Bytes()
+
+	// Create a test server with a mock chain that only knows about block
+	// 432100 and a mempool that contains its coinbase.
+	newTestServer := func() *Server {
+		cfg := defaultMockConfig(defaultChainParams)
+		chain := defaultMockRPCChain()
+		chain.heightRangeFn = func(startHeight, endHeight int64) ([]chainhash.Hash, error) {
+			if startHeight != int64(block432100.Header.Height) ||
+				endHeight != startHeight+1 {
+
+				return nil, errors.New("unexpected height range")
+			}
+			return []chainhash.Hash{*blk.Hash()}, nil
+		}
+		cfg.Chain = chain
+		mp := defaultMockTxMempooler()
+		mp.fetchTransaction = tx
+		mp.fetchTransactionErr = nil
+		cfg.TxMempooler = mp
+		return &Server{
+			cfg:        *cfg,
+			ntfnMgr:    new(testNtfnManager),
+			workState:  newWorkState(),
+			helpCacher: &testHelpCacher{},
+		}
+	}
+
+	// rpcJSON returns the JSON encoding of the result of the provided RPC
+	// handler for use as the expected JSON format body.
+	rpcJSON := func(handler commandHandler, cmd interface{}) string {
+		t.Helper()
+		result, err := handler(context.Background(), newTestServer(), cmd)
+		if err != nil {
+			t.Fatalf("unexpected RPC error: %v", err)
+		}
+		b, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("unable to marshal RPC result: %v", err)
+		}
+		return string(b) + "\n"
+	}
+	verbose := true
+	verboseTx := 1
+
+	const (
+		binType  = "application/octet-stream"
+		hexType  = "text/plain; charset=utf-8"
+		jsonType = "application/json"
+	)
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{{
+		name:       "block bin",
+		path:       "/rest/block/" + blkHash + ".bin",
+		wantStatus: http.StatusOK,
+		wantType:   binType,
+		wantBody:   string(blkBytes),
+	}, {
+		name:       "block hex",
+		path:       "/rest/block/" + blkHash + ".hex",
+		wantStatus: http.StatusOK,
+		wantType:   hexType,
+		wantBody:   hex.EncodeToString(blkBytes) + "\n",
+	}, {
+		name:       "block json",
+		path:       "/rest/block/" + blkHash + ".json",
+		wantStatus: http.StatusOK,
+		wantType:   jsonType,
+		wantBody: rpcJSON(handleGetBlock, &types.GetBlockCmd{
+			Hash:      blkHash,
+			Verbose:   &verbose,
+			VerboseTx: &verbose,
+		}),
+	}, {
+		name:       "block invalid hash",
+		path:       "/rest/block/zz.bin",
+		wantStatus: http.StatusBadRequest,
+		wantType:   hexType,
+		wantBody:   "invalid hash: zz\n",
+	}, {
+		name:       "headers bin",
+		path:       "/rest/headers/5/" + blkHash + ".bin",
+		wantStatus: http.StatusOK,
+		wantType:   binType,
+		wantBody:   string(hdrBytes),
+	}, {
+		name:       "headers hex",
+		path:       "/rest/headers/1/" + blkHash + ".hex",
+		wantStatus: http.StatusOK,
+		wantType:   hexType,
+		wantBody:   hex.EncodeToString(hdrBytes) + "\n",
+	}, {
+		name:       "headers json",
+		path:       "/rest/headers/1/" + blkHash + ".json",
+		wantStatus: http.StatusOK,
+		wantType:   jsonType,
+		wantBody: "[" + strings.TrimSuffix(rpcJSON(handleGetBlockHeader,
+			&types.GetBlockHeaderCmd{
+				Hash:    blkHash,
+				Verbose: &verbose,
+			}), "\n") + "]\n",
+	}, {
+		name:       "headers zero count",
+		path:       "/rest/headers/0/" + blkHash + ".bin",
+		wantStatus: http.StatusBadRequest,
+		wantType:   hexType,
+		wantBody:   "header count must be between 1 and 2000: 0\n",
+	}, {
+		name:       "headers count too large",
+		path:       "/rest/headers/2001/" + blkHash + ".bin",
+		wantStatus: http.StatusBadRequest,
+		wantType:   hexType,
+		wantBody:   "header count must be between 1 and 2000: 2001\n",
+	}, {
+		name:       "tx bin",
+		path:       "/rest/tx/" + txHash + ".bin",
+		wantStatus: http.StatusOK,
+		wantType:   binType,
+		wantBody:   string(txBytes),
+	}, {
+		name:       "tx hex",
+		path:       "/rest/tx/" + txHash + ".hex",
+		wantStatus: http.StatusOK,
+		wantType:   hexType,
+		wantBody:   hex.EncodeToString(txBytes) + "\n",
+	}, {
+		name:       "tx json",
+		path:       "/rest/tx/" + txHash + ".json",
+		wantStatus: http.StatusOK,
+		wantType:   jsonType,
+		wantBody: rpcJSON(handleGetRawTransaction, &types.GetRawTransactionCmd{
+			Txid:    txHash,
+			Verbose: &verboseTx,
+		}),
+	}, {
+		name:       "blockhashbyheight bin",
+		path:       "/rest/blockhashbyheight/432100.bin",
+		wantStatus: http.StatusOK,
+		wantType:   binType,
+		wantBody:   string(blk.Hash()[:]),
+	}, {
+		name:       "blockhashbyheight hex",
+		path:       "/rest/blockhashbyheight/432100.hex",
+		wantStatus: http.StatusOK,
+		wantType:   hexType,
+		wantBody:   blkHash + "\n",
+	}, {
+		name:       "blockhashbyheight json",
+		path:       "/rest/blockhashbyheight/432100.json",
+		wantStatus: http.StatusOK,
+		wantType:   jsonType,
+		wantBody:   `{"blockhash":"` + blkHash + `"}` + "\n",
+	}, {
+		name:       "blockhashbyheight invalid height",
+		path:       "/rest/blockhashbyheight/-1.json",
+		wantStatus: http.StatusBadRequest,
+		wantType:   hexType,
+		wantBody:   "invalid height: -1\n",
+	}, {
+		name:       "cfilterv2 bin",
+		path:       "/rest/cfilterv2/" + blkHash + ".bin",
+		wantStatus: http.StatusOK,
+		wantType:   binType,
+		wantBody:   string(filter),
+	}, {
+		name:       "cfilterv2 hex",
+		path:       "/rest/cfilterv2/" + blkHash + ".hex",
+		wantStatus: http.StatusOK,
+		wantType:   hexType,
+		wantBody:   hex.EncodeToString(filter) + "\n",
+	}, {
+		name:       "cfilterv2 json",
+		path:       "/rest/cfilterv2/" + blkHash + ".json",
+		wantStatus: http.StatusOK,
+		wantType:   jsonType,
+		wantBody: rpcJSON(handleGetCFilterV2, &types.GetCFilterV2Cmd{
+			BlockHash: blkHash,
+		}),
+	}, {
+		name:       "chaininfo json",
+		path:       "/rest/chaininfo.json",
+		wantStatus: http.StatusOK,
+		wantType:   jsonType,
+		wantBody:   rpcJSON(handleGetBlockchainInfo, nil),
+	}, {
+		name:       "chaininfo bin not supported",
+		path:       "/rest/chaininfo.bin",
+		wantStatus: http.StatusNotFound,
+		wantType:   hexType,
+		wantBody:   "output format not found (available: json)\n",
+	}, {
+		name:       "unknown format",
+		path:       "/rest/block/" + blkHash + ".xml",
+		wantStatus: http.StatusNotFound,
+		wantType:   hexType,
+		wantBody:   "output format not found (available: bin, hex, json)\n",
+	}, {
+		name:       "missing format",
+		path:       "/rest/block/" + blkHash,
+		wantStatus: http.StatusNotFound,
+		wantType:   hexType,
+		wantBody:   "output format not found (available: bin, hex, json)\n",
+	}, {
+		name:       "unknown request",
+		path:       "/rest/getinfo.json",
+		wantStatus: http.StatusNotFound,
+		wantType:   hexType,
+		wantBody:   "not found: /rest/getinfo.json\n",
+	}, {
+		name:       "wrong number of params",
+		path:       "/rest/headers/" + blkHash + ".json",
+		wantStatus: http.StatusNotFound,
+		wantType:   hexType,
+		wantBody:   "not found: /rest/headers/" + blkHash + ".json\n",
+	}, {
+		name:       "head request",
+		method:     http.MethodHead,
+		path:       "/rest/blockhashbyheight/432100.hex",
+		wantStatus: http.StatusOK,
+		wantType:   hexType,
+	}, {
+		name:       "post not allowed",
+		method:     http.MethodPost,
+		path:       "/rest/chaininfo.json",
+		wantStatus: http.StatusMethodNotAllowed,
+		wantType:   hexType,
+		wantBody:   "method not allowed: POST\n",
+	}}
+
+	for _, test := range tests {
+		method := test.method
+		if method == "" {
+			method = http.MethodGet
+		}
+		req := httptest.NewRequest(method, test.path, nil)
+		rec := httptest.NewRecorder()
+		newTestServer().serveREST(rec, req)
+		if rec.Code != test.wantStatus {
+			t.Errorf("%q: unexpected status code -- got %d, want %d (body: %s)",
+				test.name, rec.Code, test.wantStatus, rec.Body.String())
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != test.wantType {
+			t.Errorf("%q: unexpected content type -- got %q, want %q",
+				test.name, ct, test.wantType)
+			continue
+		}
+		if !bytes.Equal(rec.Body.Bytes(), []byte(test.wantBody)) {
+			t.Errorf("%q: unexpected body -- got %q, want %q", test.name,
+				rec.Body.String(), test.wantBody)
+			continue
+		}
+	}
+
+	// Ensure the handlers only produce the representation required by the
+	// requested format.
+	handlerTests := []struct {
+		name   string
+		params []string
+	}{
+		{name: "block", params: []string{blkHash}},
+		{name: "headers", params: []string{"1", blkHash}},
+		{name: "tx", params: []string{txHash}},
+	}
+	for _, test := range handlerTests {
+		handler := restHandlers[test.name].handler
+		for _, format := range []restFormat{restFormatBinary, restFormatHex,
+			restFormatJSON} {
+
+			res, err := handler(context.Background(), newTestServer(),
+				test.params, format)
+			if err != nil {
+				t.Errorf("%q: unexpected error for format %d: %v", test.name,
+					format, err)
+				continue
+			}
+			isJSON := format == restFormatJSON
+			if (res.json != nil) != isJSON || (res.bin != nil) == isJSON {
+				t.Errorf("%q: unexpected representations for format %d -- "+
+					"json: %v, bin: %v", test.name, format, res.json != nil,
+					res.bin != nil)
+			}
+		}
+	}
+}
+
+// TestRESTErrors ensures errors from the underlying chain are mapped to the
+// expected HTTP status codes.
+func TestRESTErrors(t *testing.T) {
+	t.Parallel()
+
+	blkHash := block432100.BlockHash().String()
+	tests := []struct {
+		name       string
+		path       string
+		chain      func() *testRPCChain
+		wantStatus int
+	}{{
+		name: "block not found",
+		path: "/rest/block/" + blkHash + ".hex",
+		chain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockByHashErr = errors.New("block not found")
+			return chain
+		},
+		wantStatus: http.StatusNotFound,
+	}, {
+		name: "headers block not found",
+		path: "/rest/headers/1/" + blkHash + ".hex",
+		chain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.headerByHashErr = errors.New("header not found")
+			return chain
+		},
+		wantStatus: http.StatusNotFound,
+	}, {
+		name: "headers height range error",
+		path: "/rest/headers/1/" + blkHash + ".hex",
+		chain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.heightRangeFn = func(_, _ int64) ([]chainhash.Hash, error) {
+				return nil, errors.New("unable to fetch hashes")
+			}
+			return chain
+		},
+		wantStatus: http.StatusInternalServerError,
+	}, {
+		name: "height out of range",
+		path: "/rest/blockhashbyheight/999999999.hex",
+		chain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockHashByHeightErr = errors.New("out of range")
+			return chain
+		},
+		wantStatus: http.StatusNotFound,
+	}}
+
+	for _, test := range tests {
+		cfg := defaultMockConfig(defaultChainParams)
+		cfg.Chain = test.chain()
+		s := &Server{
+			cfg:        *cfg,
+			ntfnMgr:    new(testNtfnManager),
+			workState:  newWorkState(),
+			helpCacher: &testHelpCacher{},
+		}
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		rec := httptest.NewRecorder()
+		s.serveREST(rec, req)
+		if rec.Code != test.wantStatus {
+			t.Errorf("%q: unexpected status code -- got %d, want %d (body: %s)",
+				test.name, rec.Code, test.wantStatus, rec.Body.String())
+		}
+	}
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -45,9 +45,12 @@ import (
 	"github.com/decred/dcrd/dcrjson/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/decred/dcrd/internal/fees"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/mining/cpuminer"
+	"github.com/decred/dcrd/internal/staging/banmanager"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/mixing"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
@@ -62,7 +65,7 @@ import (
 // API version constants.
 const (
 	jsonrpcSemverMajor = 8
-	jsonrpcSemverMinor = 3
+	jsonrpcSemverMinor = 4
 	jsonrpcSemverPatch = 0
 )
 
@@ -118,9 +121,18 @@ const (
 	// of a block.
 	merkleRootPairSize = 64
 
+	// maxTestMempoolAcceptTxns is the maximum number of transactions that may
+	// be tested with a single testmempoolaccept request.
+	maxTestMempoolAcceptTxns = 25
+
 	// syncWait is the maximum time in seconds to wait for an index
 	// to sync with the main chain.
 	syncWait = time.Second * 3
+
+	// maxSearchRawTxnsCount is the maximum number of transactions that may be
+	// requested in a single searchrawtransactions call.  Requests for more are
+	// limited to this value.
+	maxSearchRawTxnsCount = 10000
 )
 
 var (
@@ -172,13 +184,17 @@ type commandHandler func(context.Context, *Server, interface{}) (interface{}, er
 var rpcHandlers map[types.Method]commandHandler
 var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"addnode":               handleAddNode,
+	"clearbanned":           handleClearBanned,
 	"createrawsstx":         handleCreateRawSStx,
 	"createrawssrtx":        handleCreateRawSSRtx,
 	"createrawtransaction":  handleCreateRawTransaction,
 	"debuglevel":            handleDebugLevel,
 	"decoderawtransaction":  handleDecodeRawTransaction,
 	"decodescript":          handleDecodeScript,
+	"dumpfeebuckets":        handleDumpFeeBuckets,
+	"dumputxoset":           handleDumpUtxoSet,
 	"estimatefee":           handleEstimateFee,
+	"estimaterawfee":        handleEstimateRawFee,
 	"estimatesmartfee":      handleEstimateSmartFee,
 	"estimatestakediff":     handleEstimateStakeDiff,
 	"existsaddress":         handleExistsAddress,
@@ -206,6 +222,9 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"gethashespersec":       handleGetHashesPerSec,
 	"getheaders":            handleGetHeaders,
 	"getinfo":               handleGetInfo,
+	"getmempoolancestors":   handleGetMempoolAncestors,
+	"getmempooldescendants": handleGetMempoolDescendants,
+	"getmempoolentry":       handleGetMempoolEntry,
 	"getmempoolinfo":        handleGetMempoolInfo,
 	"getmininginfo":         handleGetMiningInfo,
 	"getmixmessage":         handleGetMixMessage,
@@ -214,8 +233,10 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"getnetworkhashps":      handleGetNetworkHashPS,
 	"getnetworkinfo":        handleGetNetworkInfo,
 	"getpeerinfo":           handleGetPeerInfo,
+	"getratelimitinfo":      handleGetRateLimitInfo,
 	"getrawmempool":         handleGetRawMempool,
 	"getrawtransaction":     handleGetRawTransaction,
+	"getspendinginfo":       handleGetSpendingInfo,
 	"getstakedifficulty":    handleGetStakeDifficulty,
 	"getstakeversioninfo":   handleGetStakeVersionInfo,
 	"getstakeversions":      handleGetStakeVersions,
@@ -228,18 +249,23 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"getwork":               handleGetWork,
 	"help":                  handleHelp,
 	"invalidateblock":       handleInvalidateBlock,
+	"listbanned":            handleListBanned,
 	"livetickets":           handleLiveTickets,
 	"node":                  handleNode,
 	"ping":                  handlePing,
 	"reconsiderblock":       handleReconsiderBlock,
 	"regentemplate":         handleRegenTemplate,
+	"savemempool":           handleSaveMempool,
+	"searchrawtransactions": handleSearchRawTransactions,
 	"sendrawmixmessage":     handleSendRawMixMessage,
 	"sendrawtransaction":    handleSendRawTransaction,
+	"setban":                handleSetBan,
 	"setgenerate":           handleSetGenerate,
 	"startprofiler":         handleStartProfiler,
 	"stop":                  handleStop,
 	"stopprofiler":          handleStopProfiler,
 	"submitblock":           handleSubmitBlock,
+	"testmempoolaccept":     handleTestMempoolAccept,
 	"ticketfeeinfo":         handleTicketFeeInfo,
 	"ticketsforaddress":     handleTicketsForAddress,
 	"ticketvwap":            handleTicketVWAP,
@@ -344,6 +370,7 @@ var rpcLimited = map[string]struct{}{
 	"notifymixmessages":     {},
 	"notifynewtransactions": {},
 	"rescan":                {},
+	"resumesession":         {},
 	"session":               {},
 	"rebroadcastwinners":    {},
 
@@ -351,59 +378,66 @@ var rpcLimited = map[string]struct{}{
 	"help": {},
 
 	// HTTP/S-only commands
-	"createrawsstx":        {},
-	"createrawssrtx":       {},
-	"createrawtransaction": {},
-	"decoderawtransaction": {},
-	"decodescript":         {},
-	"estimatefee":          {},
-	"estimatesmartfee":     {},
-	"estimatestakediff":    {},
-	"existsaddress":        {},
-	"existsaddresses":      {},
-	"existsliveticket":     {},
-	"existslivetickets":    {},
-	"existsmempooltxs":     {},
-	"getbestblock":         {},
-	"getbestblockhash":     {},
-	"getblock":             {},
-	"getblockchaininfo":    {},
-	"getblockcount":        {},
-	"getblockhash":         {},
-	"getblockheader":       {},
-	"getblocksubsidy":      {},
-	"getcfilterv2":         {},
-	"getchaintips":         {},
-	"getcoinsupply":        {},
-	"getcurrentnet":        {},
-	"getdifficulty":        {},
-	"getheaders":           {},
-	"getinfo":              {},
-	"getmixmessage":        {},
-	"getmixpairrequests":   {},
-	"getnettotals":         {},
-	"getnetworkhashps":     {},
-	"getnetworkinfo":       {},
-	"getrawmempool":        {},
-	"getstakedifficulty":   {},
-	"getstakeversioninfo":  {},
-	"getstakeversions":     {},
-	"getrawtransaction":    {},
-	"gettreasurybalance":   {},
-	"gettxout":             {},
-	"getvoteinfo":          {},
-	"livetickets":          {},
-	"regentemplate":        {},
-	"sendrawmixmessage":    {},
-	"sendrawtransaction":   {},
-	"submitblock":          {},
-	"ticketfeeinfo":        {},
-	"ticketsforaddress":    {},
-	"ticketvwap":           {},
-	"txfeeinfo":            {},
-	"validateaddress":      {},
-	"verifymessage":        {},
-	"version":              {},
+	"createrawsstx":         {},
+	"createrawssrtx":        {},
+	"createrawtransaction":  {},
+	"decoderawtransaction":  {},
+	"decodescript":          {},
+	"estimatefee":           {},
+	"estimaterawfee":        {},
+	"estimatesmartfee":      {},
+	"estimatestakediff":     {},
+	"existsaddress":         {},
+	"existsaddresses":       {},
+	"existsliveticket":      {},
+	"existslivetickets":     {},
+	"existsmempooltxs":      {},
+	"getbestblock":          {},
+	"getbestblockhash":      {},
+	"getblock":              {},
+	"getblockchaininfo":     {},
+	"getblockcount":         {},
+	"getblockhash":          {},
+	"getblockheader":        {},
+	"getblocksubsidy":       {},
+	"getcfilterv2":          {},
+	"getchaintips":          {},
+	"getcoinsupply":         {},
+	"getcurrentnet":         {},
+	"getdifficulty":         {},
+	"getheaders":            {},
+	"getinfo":               {},
+	"getmempoolancestors":   {},
+	"getmempooldescendants": {},
+	"getmempoolentry":       {},
+	"getmixmessage":         {},
+	"getmixpairrequests":    {},
+	"getnettotals":          {},
+	"getnetworkhashps":      {},
+	"getnetworkinfo":        {},
+	"getrawmempool":         {},
+	"getspendinginfo":       {},
+	"getstakedifficulty":    {},
+	"getstakeversioninfo":   {},
+	"getstakeversions":      {},
+	"getrawtransaction":     {},
+	"gettreasurybalance":    {},
+	"gettxout":              {},
+	"getvoteinfo":           {},
+	"livetickets":           {},
+	"regentemplate":         {},
+	"searchrawtransactions": {},
+	"sendrawmixmessage":     {},
+	"sendrawtransaction":    {},
+	"submitblock":           {},
+	"testmempoolaccept":     {},
+	"ticketfeeinfo":         {},
+	"ticketsforaddress":     {},
+	"ticketvwap":            {},
+	"txfeeinfo":             {},
+	"validateaddress":       {},
+	"verifymessage":         {},
+	"version":               {},
 }
 
 // rpcInternalErr is a convenience function to convert an internal error to an
@@ -494,6 +528,14 @@ func rpcMixMessageNotFoundError(hash *chainhash.Hash) *dcrjson.RPCError {
 			hash))
 }
 
+// rpcRescanRequiredError is a convenience function for returning a nicely
+// formatted RPC error which indicates that the notifications missed by a
+// websocket client can't be replayed and the client must rescan instead.
+func rpcRescanRequiredError(fmtStr string, args ...interface{}) *dcrjson.RPCError {
+	return dcrjson.NewRPCError(dcrjson.ErrRPCRescanRequired,
+		fmt.Sprintf(fmtStr, args...))
+}
+
 // rpcConnectionClosedError is a convenience function for returning an RPC error
 // which indicates the associated connection has been closed, most likely due to
 // context cancellation such as when the server is being shutdown.
@@ -737,6 +779,16 @@ func newTxOut(amount int64, pkScriptVer uint16, pkScript []byte) *wire.TxOut {
 	}
 }
 
+// handleClearBanned implements the clearbanned command.
+func handleClearBanned(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	if err := s.cfg.ConnMgr.ClearBans(); err != nil {
+		return nil, rpcInternalErr(err, "Unable to clear bans")
+	}
+
+	// no data returned unless an error.
+	return nil, nil
+}
+
 // handleCreateRawTransaction handles createrawtransaction commands.
 func handleCreateRawTransaction(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.CreateRawTransactionCmd)
@@ -1447,6 +1499,62 @@ func handleDecodeScript(_ context.Context, s *Server, cmd interface{}) (interfac
 	return reply, nil
 }
 
+// feeRateToCoin converts the provided fee rate in atoms/kB to DCR/kB.  The
+// unbounded fee rate of the highest fee rate bucket is converted to -1 since it
+// is not representable in JSON.
+func feeRateToCoin(rate float64) float64 {
+	if math.IsInf(rate, 1) {
+		return -1
+	}
+	return rate / dcrutil.AtomsPerCoin
+}
+
+// handleDumpFeeBuckets implements the dumpfeebuckets command.
+func handleDumpFeeBuckets(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	stats := s.cfg.FeeEstimator.Stats()
+	buckets := make([]types.FeeBucketResult, 0, len(stats.Buckets))
+	for i := range stats.Buckets {
+		bucket := &stats.Buckets[i]
+		var avgFeeRate float64
+		if bucket.TotalConfirmed > 0 {
+			avgFeeRate = bucket.FeeSum / bucket.TotalConfirmed
+		}
+		buckets = append(buckets, types.FeeBucketResult{
+			StartRange:     feeRateToCoin(bucket.StartRange),
+			EndRange:       feeRateToCoin(bucket.EndRange),
+			TotalConfirmed: bucket.TotalConfirmed,
+			AvgFeeRate:     feeRateToCoin(avgFeeRate),
+			Confirmed:      bucket.Confirmed,
+			InMempool:      bucket.InMempool,
+		})
+	}
+	return &types.DumpFeeBucketsResult{
+		BestHeight:  stats.BestHeight,
+		MaxConfirms: stats.MaxConfirms,
+		Decay:       stats.Decay,
+		Buckets:     buckets,
+	}, nil
+}
+
+// handleDumpUtxoSet implements the dumputxoset command.
+func handleDumpUtxoSet(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.DumpUtxoSetCmd)
+	if c.Path == "" {
+		return nil, rpcInvalidError("A path for the snapshot is required")
+	}
+
+	path, info, err := s.cfg.UtxoSnapshotter.DumpUtxoSnapshot(ctx, c.Path)
+	if err != nil {
+		return nil, rpcInternalErr(err, "Unable to dump the UTXO set")
+	}
+	return &types.DumpUtxoSetResult{
+		Hash:         info.BlockHash.String(),
+		Height:       info.Height,
+		SnapshotHash: info.SnapshotHash.String(),
+		Path:         path,
+	}, nil
+}
+
 // handleEstimateFee implements the estimatefee command.
 // TODO this is a very basic implementation.  It should be
 // modified to match the bitcoin-core one.
@@ -1454,6 +1562,64 @@ func handleEstimateFee(_ context.Context, s *Server, cmd interface{}) (interface
 	return s.cfg.MinRelayTxFee.ToCoin(), nil
 }
 
+// handleEstimateRawFee implements the estimaterawfee command.
+func handleEstimateRawFee(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.EstimateRawFeeCmd)
+
+	threshold := 0.95
+	if c.Threshold != nil {
+		threshold = *c.Threshold
+	}
+	if threshold <= 0 || threshold > 1 {
+		return nil, rpcInvalidError("Threshold must be greater than 0 and "+
+			"at most 1 (got %v)", threshold)
+	}
+	if c.Confirmations <= 0 || c.Confirmations > math.MaxInt32 {
+		return nil, rpcInvalidError("Invalid number of confirmations %d",
+			c.Confirmations)
+	}
+
+	est, err := s.cfg.FeeEstimator.EstimateRawFee(int32(c.Confirmations),
+		threshold)
+	if est == nil {
+		var errTooLarge fees.ErrTargetConfTooLarge
+		if errors.As(err, &errTooLarge) {
+			return nil, rpcInvalidError("%v", err)
+		}
+		return nil, rpcInternalErr(err, "Could not estimate fee")
+	}
+
+	// rawFeeBucket converts the provided statistics of a range of fee rate
+	// buckets to the result type.
+	rawFeeBucket := func(r *fees.BucketRangeStats) *types.EstimateRawFeeBucket {
+		if r == nil {
+			return nil
+		}
+		return &types.EstimateRawFeeBucket{
+			StartRange:     feeRateToCoin(r.StartRange),
+			EndRange:       feeRateToCoin(r.EndRange),
+			WithinTarget:   r.WithinTarget,
+			TotalConfirmed: r.TotalConfirmed,
+			InMempool:      r.InMempool,
+			SuccessRatio:   r.SuccessRatio(),
+		}
+	}
+
+	result := &types.EstimateRawFeeResult{
+		Decay:     est.Decay,
+		Threshold: threshold,
+		Blocks:    c.Confirmations,
+		Pass:      rawFeeBucket(est.Pass),
+		Fail:      rawFeeBucket(est.Fail),
+	}
+	if err != nil {
+		result.Errors = []string{err.Error()}
+	} else {
+		result.FeeRate = est.FeeRate.ToCoin()
+	}
+	return result, nil
+}
+
 // handleEstimateSmartFee implements the estimatesmartfee command.
 //
 // The default estimation mode when unset is assumed as "conservative". As of
@@ -1951,6 +2117,21 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 	chain := s.cfg.Chain
 	blk, err := chain.BlockByHash(hash)
 	if err != nil {
+		// Provide a more useful error when the block is known, but its data
+		// is no longer available due to pruning.
+		if chain.IsPruned() {
+			header, hdrErr := chain.HeaderByHash(hash)
+			pruneHeight := chain.PruneHeight()
+			if hdrErr == nil && int64(header.Height) < pruneHeight {
+				return nil, &dcrjson.RPCError{
+					Code: dcrjson.ErrRPCMisc,
+					Message: fmt.Sprintf("Block %v at height %d is not "+
+						"available because block data prior to height %d "+
+						"has been pruned", hash, header.Height, pruneHeight),
+				}
+			}
+		}
+
 		return nil, &dcrjson.RPCError{
 			Code:    dcrjson.ErrRPCBlockNotFound,
 			Message: fmt.Sprintf("Block not found: %v", hash),
@@ -2200,6 +2381,11 @@ func handleGetBlockchainInfo(_ context.Context, s *Server, _ interface{}) (inter
 		MaxBlockSize:         maxBlockSize,
 		Deployments:          dInfo,
 	}
+	if chain.IsPruned() {
+		response.Pruned = true
+		response.PruneHeight = chain.PruneHeight()
+		response.PruneTargetSize = chain.PruneTarget()
+	}
 
 	return response, nil
 }
@@ -2535,6 +2721,98 @@ func handleGetInfo(_ context.Context, s *Server, _ interface{}) (interface{}, er
 	return ret, nil
 }
 
+// mempoolEntryResult returns the getmempoolentry result for the provided
+// verbose mempool transaction descriptor.
+func mempoolEntryResult(desc *mempool.VerboseTxDesc) *types.GetMempoolEntryResult {
+	feeRate := dcrutil.Amount(desc.PackageFeeRate)
+	result := &types.GetMempoolEntryResult{
+		Size:            int32(desc.Tx.MsgTx().SerializeSize()),
+		Fee:             dcrutil.Amount(desc.Fee).ToCoin(),
+		Time:            desc.Added.Unix(),
+		Height:          desc.Height,
+		AncestorCount:   int64(desc.Ancestors.Count),
+		AncestorSize:    desc.Ancestors.Size,
+		AncestorFees:    dcrutil.Amount(desc.Ancestors.Fees).ToCoin(),
+		DescendantCount: int64(desc.Descendants.Count),
+		DescendantSize:  desc.Descendants.Size,
+		DescendantFees:  dcrutil.Amount(desc.Descendants.Fees).ToCoin(),
+		PackageFeeRate:  feeRate.ToCoin(),
+		Depends:         make([]string, len(desc.Depends)),
+	}
+	for i, depDesc := range desc.Depends {
+		result.Depends[i] = depDesc.Tx.Hash().String()
+	}
+	return result
+}
+
+// mempoolPackageResult returns the result for the getmempoolancestors and
+// getmempooldescendants commands for the provided verbose mempool transaction
+// descriptors.  It is an array of transaction hashes when the verbose flag is
+// not set.
+func mempoolPackageResult(descs []*mempool.VerboseTxDesc, verbose bool) interface{} {
+	if verbose {
+		result := make(map[string]*types.GetMempoolEntryResult, len(descs))
+		for _, desc := range descs {
+			result[desc.Tx.Hash().String()] = mempoolEntryResult(desc)
+		}
+		return result
+	}
+
+	hashStrings := make([]string, 0, len(descs))
+	for _, desc := range descs {
+		hashStrings = append(hashStrings, desc.Tx.Hash().String())
+	}
+	return hashStrings
+}
+
+// handleGetMempoolAncestors implements the getmempoolancestors command.
+func handleGetMempoolAncestors(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolAncestorsCmd)
+
+	txHash, err := chainhash.NewHashFromStr(c.TxID)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.TxID)
+	}
+	descs, err := s.cfg.TxMempooler.AncestorTxDescs(txHash)
+	if err != nil {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+
+	return mempoolPackageResult(descs, c.Verbose != nil && *c.Verbose), nil
+}
+
+// handleGetMempoolDescendants implements the getmempooldescendants command.
+func handleGetMempoolDescendants(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolDescendantsCmd)
+
+	txHash, err := chainhash.NewHashFromStr(c.TxID)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.TxID)
+	}
+	descs, err := s.cfg.TxMempooler.DescendantTxDescs(txHash)
+	if err != nil {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+
+	return mempoolPackageResult(descs, c.Verbose != nil && *c.Verbose), nil
+}
+
+// handleGetMempoolEntry implements the getmempoolentry command.
+func handleGetMempoolEntry(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolEntryCmd)
+
+	txHash, err := chainhash.NewHashFromStr(c.TxID)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.TxID)
+	}
+	desc, err := s.cfg.TxMempooler.VerboseTxDesc(txHash)
+	if err != nil {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+
+	return mempoolEntryResult(desc), nil
+}
+
 // handleGetMempoolInfo implements the getmempoolinfo command.
 func handleGetMempoolInfo(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	mempoolTxns := s.cfg.TxMempooler.TxDescs()
@@ -2544,9 +2822,13 @@ func handleGetMempoolInfo(_ context.Context, s *Server, _ interface{}) (interfac
 		numBytes += int64(txD.Tx.MsgTx().SerializeSize())
 	}
 
+	mempooler := s.cfg.TxMempooler
 	ret := &types.GetMempoolInfoResult{
-		Size:  int64(len(mempoolTxns)),
-		Bytes: numBytes,
+		Size:          int64(len(mempoolTxns)),
+		Bytes:         numBytes,
+		MaxMempool:    s.cfg.MaxMempoolSize,
+		MempoolMinFee: mempooler.MinRelayTxFee().ToCoin(),
+		MinRelayTxFee: s.cfg.MinRelayTxFee.ToCoin(),
 	}
 
 	return ret, nil
@@ -2792,6 +3074,8 @@ func handleGetPeerInfo(_ context.Context, s *Server, _ interface{}) (interface{}
 			CurrentHeight:  statsSnap.LastBlock,
 			BanScore:       int32(p.BanScore()),
 			SyncNode:       p.ID() == syncPeerID,
+			Transport:      statsSnap.Transport,
+			MappedAS:       p.MappedAS(),
 		}
 		if p.LastPingNonce() != 0 {
 			wait := float64(s.cfg.Clock.Since(statsSnap.LastPingTime).Nanoseconds())
@@ -2806,6 +3090,25 @@ func handleGetPeerInfo(_ context.Context, s *Server, _ interface{}) (interface{}
 	return infos, nil
 }
 
+// handleGetRateLimitInfo implements the getratelimitinfo command.
+func handleGetRateLimitInfo(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	weights := make(map[string]float64, len(rpcMethodWeights))
+	for method, weight := range rpcMethodWeights {
+		weights[string(method)] = weight
+	}
+	result := &types.GetRateLimitInfoResult{
+		Enabled: s.rateLimiter != nil,
+		Rate:    s.cfg.RPCRateLimit,
+		Burst:   s.cfg.RPCRateBurst,
+		Weights: weights,
+		Clients: []types.RateLimitClientResult{},
+	}
+	if s.rateLimiter != nil {
+		result.Clients = s.rateLimiter.info()
+	}
+	return result, nil
+}
+
 // handleGetRawMempool implements the getrawmempool command.
 func handleGetRawMempool(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetRawMempoolCmd)
@@ -3049,6 +3352,73 @@ func handleGetRawTransaction(_ context.Context, s *Server, cmd interface{}) (int
 	return *rawTxn, nil
 }
 
+// handleGetSpendingInfo implements the getspendinginfo command.
+func handleGetSpendingInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetSpendingInfoCmd)
+
+	// Respond with an error if the spend index is not enabled.
+	spendIndex := s.cfg.SpendIndexer
+	if spendIndex == nil {
+		err := errors.New("the spend index must be enabled to query " +
+			"spending information (specify --spendindex)")
+		return nil, rpcInternalErr(err, "Configuration")
+	}
+
+	// Convert the provided transaction hash hex to a Hash.
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	// Ensure the spend index is synced.
+	tHeight, tHash, err := spendIndex.Tip()
+	if err != nil {
+		return nil, rpcInternalErr(err, "Tip")
+	}
+
+	chain := s.cfg.Chain
+
+	// Return an out-of-sync error if index is lagging a
+	// maximum reorg depth (6) blocks or more from the chain tip.
+	if chain.BestSnapshot().Height > (tHeight + 5) {
+		err := fmt.Errorf("%s: index not synced", spendIndex.Name())
+		return nil, rpcInternalErr(err, "Sync")
+	}
+
+sync:
+	for !chain.BestSnapshot().Hash.IsEqual(tHash) {
+		select {
+		case <-time.After(syncWait):
+			err := fmt.Errorf("%s: index not synced", spendIndex.Name())
+			return nil, rpcInternalErr(err, "Sync")
+		case <-spendIndex.WaitForSync():
+			break sync
+		}
+	}
+
+	// Look up the details of the spending transaction.  Note that the tree
+	// is not part of the index key since the hash and output index alone
+	// uniquely identify an output.
+	outpoint := wire.OutPoint{Hash: *txHash, Index: c.Vout}
+	entry, err := spendIndex.Entry(&outpoint)
+	if err != nil {
+		const context = "Failed to retrieve spending information"
+		return nil, rpcInternalErr(err, context)
+	}
+	if entry == nil {
+		return nil, dcrjson.NewRPCError(dcrjson.ErrRPCNoTxInfo,
+			fmt.Sprintf("No spending information available for output "+
+				"%v:%d", txHash, c.Vout))
+	}
+
+	return &types.GetSpendingInfoResult{
+		SpendingTxid: entry.SpenderHash.String(),
+		Vin:          entry.InputIndex,
+		BlockHash:    entry.BlockHash.String(),
+		BlockHeight:  entry.BlockHeight,
+	}, nil
+}
+
 // handleGetStakeDifficulty implements the getstakedifficulty command.
 func handleGetStakeDifficulty(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	chain := s.cfg.Chain
@@ -4216,6 +4586,22 @@ func handleInvalidateBlock(_ context.Context, s *Server, cmd interface{}) (inter
 	return nil, nil
 }
 
+// handleListBanned implements the listbanned command.
+func handleListBanned(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	bans := s.cfg.ConnMgr.BannedSubnets()
+	result := make([]types.ListBannedResult, 0, len(bans))
+	for i := range bans {
+		ban := &bans[i]
+		result = append(result, types.ListBannedResult{
+			Address:     ban.Subnet.String(),
+			BanCreated:  ban.Created.Unix(),
+			BannedUntil: ban.Expires.Unix(),
+			BanReason:   ban.Reason,
+		})
+	}
+	return result, nil
+}
+
 // handleLiveTickets implements the livetickets command.
 func handleLiveTickets(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	lt, err := s.cfg.Chain.LiveTickets()
@@ -4303,6 +4689,279 @@ func handleRegenTemplate(_ context.Context, s *Server, _ interface{}) (interface
 	return nil, nil
 }
 
+// handleSaveMempool implements the savemempool command.
+func handleSaveMempool(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	filename, numTxns, err := s.cfg.MempoolSaver.SaveMempool()
+	if err != nil {
+		return nil, rpcInternalErr(err, "Unable to save mempool")
+	}
+	return &types.SaveMempoolResult{
+		Filename: filename,
+		Size:     numTxns,
+	}, nil
+}
+
+// fetchMempoolTxnsForAddress queries the address index for all unconfirmed
+// transactions that involve the provided address.  The results will be limited
+// by the number to skip and the number requested.  The transactions are sorted
+// by their hash in order to provide a stable order for pagination.
+func fetchMempoolTxnsForAddress(s *Server, addr stdaddr.Address, numToSkip, numRequested uint32) ([]*dcrutil.Tx, uint32) {
+	// There are no entries to return when there are less available than the
+	// number being skipped.
+	mpTxns := s.cfg.AddrIndexer.UnconfirmedTxnsForAddress(addr)
+	numAvailable := uint32(len(mpTxns))
+	if numToSkip > numAvailable {
+		return nil, numAvailable
+	}
+	sort.Slice(mpTxns, func(i, j int) bool {
+		return bytes.Compare(mpTxns[i].Hash()[:], mpTxns[j].Hash()[:]) < 0
+	})
+
+	// Filter the available entries based on the number to skip and number
+	// requested.
+	rangeEnd := uint64(numToSkip) + uint64(numRequested)
+	if rangeEnd > uint64(numAvailable) {
+		rangeEnd = uint64(numAvailable)
+	}
+	return mpTxns[numToSkip:rangeEnd], numToSkip
+}
+
+// handleSearchRawTransactions implements the searchrawtransactions command.
+func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	// Respond with an error if the address index is not enabled.
+	addrIndex := s.cfg.AddrIndexer
+	if addrIndex == nil {
+		err := errors.New("the address index must be enabled to search " +
+			"for transactions by address (specify --addrindex)")
+		return nil, rpcInternalErr(err, "Configuration")
+	}
+
+	c := cmd.(*types.SearchRawTransactionsCmd)
+
+	// Decode the provided address.  This also ensures the network encoded with
+	// the address matches the network the server is currently on.
+	addr, err := stdaddr.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	verbose := true
+	if c.Verbose != nil {
+		verbose = *c.Verbose != 0
+	}
+
+	// Override the default number of requested entries and number to skip
+	// as needed.
+	numRequested := uint32(100)
+	if c.Count != nil {
+		if *c.Count < 0 {
+			return nil, rpcInvalidError("Count must not be negative: %d",
+				*c.Count)
+		}
+		numRequested = uint32(*c.Count)
+		if numRequested > maxSearchRawTxnsCount {
+			numRequested = maxSearchRawTxnsCount
+		}
+	}
+	var numToSkip uint32
+	if c.Skip != nil {
+		if *c.Skip < 0 {
+			return nil, rpcInvalidError("Skip must not be negative: %d",
+				*c.Skip)
+		}
+		numToSkip = uint32(*c.Skip)
+	}
+	reverse := c.Reverse != nil && *c.Reverse
+	includeMempool := c.IncludeMempool == nil || *c.IncludeMempool
+
+	// Ensure the address index is synced.
+	tHeight, tHash, err := addrIndex.Tip()
+	if err != nil {
+		return nil, rpcInternalErr(err, "Address index tip")
+	}
+
+	chain := s.cfg.Chain
+
+	// Return an out-of-sync error if index is lagging a
+	// maximum reorg depth (6) blocks or more from the chain tip.
+	if chain.BestSnapshot().Height > (tHeight + 5) {
+		err := fmt.Errorf("%s: index not synced", addrIndex.Name())
+		return nil, rpcInternalErr(err, "Sync")
+	}
+
+sync:
+	for !chain.BestSnapshot().Hash.IsEqual(tHash) {
+		select {
+		case <-time.After(syncWait):
+			err := fmt.Errorf("%s: index not synced", addrIndex.Name())
+			return nil, rpcInternalErr(err, "Sync")
+		case <-addrIndex.WaitForSync():
+			break sync
+		}
+	}
+
+	// retrievedTx houses a transaction that involves the address along with
+	// its raw bytes when it was loaded from the database and the location
+	// details when it is in a block.
+	type retrievedTx struct {
+		txBytes []byte
+		tx      *dcrutil.Tx
+		entry   *indexers.TxIndexEntry
+	}
+
+	// Add transactions from the mempool first if the client asked for reverse
+	// order since they are the most recent.  Otherwise, they will be added
+	// last as needed depending on the requested counts.
+	var numSkipped uint32
+	var addressTxns []retrievedTx
+	if reverse && includeMempool {
+		mpTxns, mpSkipped := fetchMempoolTxnsForAddress(s, addr, numToSkip,
+			numRequested)
+		numSkipped += mpSkipped
+		for _, tx := range mpTxns {
+			addressTxns = append(addressTxns, retrievedTx{tx: tx})
+		}
+	}
+
+	// Fetch transactions from the database in the desired order if more are
+	// needed.
+	if uint32(len(addressTxns)) < numRequested {
+		numDbRequested := numRequested - uint32(len(addressTxns))
+		entries, dbSkipped, err := addrIndex.EntriesForAddress(addr,
+			numToSkip-numSkipped, numDbRequested, reverse)
+		if err != nil {
+			const context = "Failed to load address index entries"
+			return nil, rpcInternalErr(err, context)
+		}
+		numSkipped += dbSkipped
+
+		// Load the raw transaction bytes from the database.
+		if len(entries) > 0 {
+			regions := make([]database.BlockRegion, 0, len(entries))
+			for i := range entries {
+				regions = append(regions, entries[i].BlockRegion)
+			}
+			var serializedTxns [][]byte
+			err = s.cfg.DB.View(func(dbTx database.Tx) error {
+				var err error
+				serializedTxns, err = dbTx.FetchBlockRegions(regions)
+				return err
+			})
+			if err != nil {
+				const context = "Failed to load transactions from the database"
+				return nil, rpcInternalErr(err, context)
+			}
+			for i, txBytes := range serializedTxns {
+				addressTxns = append(addressTxns, retrievedTx{
+					txBytes: txBytes,
+					entry:   &entries[i],
+				})
+			}
+		}
+	}
+
+	// Add transactions from the mempool last if client did not request reverse
+	// order and the number of results is still under the number requested.
+	if !reverse && includeMempool && uint32(len(addressTxns)) < numRequested {
+		numMpRequested := numRequested - uint32(len(addressTxns))
+		mpTxns, mpSkipped := fetchMempoolTxnsForAddress(s, addr,
+			numToSkip-numSkipped, numMpRequested)
+		numSkipped += mpSkipped
+		for _, tx := range mpTxns {
+			addressTxns = append(addressTxns, retrievedTx{tx: tx})
+		}
+	}
+
+	// Serialize all of the transactions to hex when the verbose flag isn't
+	// set.
+	if !verbose {
+		hexTxns := make([]string, len(addressTxns))
+		for i := range addressTxns {
+			// Simply encode the raw bytes if they were loaded from the
+			// database.
+			rtx := &addressTxns[i]
+			if rtx.txBytes != nil {
+				hexTxns[i] = hex.EncodeToString(rtx.txBytes)
+				continue
+			}
+
+			mtxHex, err := s.messageToHex(rtx.tx.MsgTx())
+			if err != nil {
+				return nil, err
+			}
+			hexTxns[i] = mtxHex
+		}
+		return hexTxns, nil
+	}
+
+	// The verbose flag is set, so generate the JSON object for each
+	// transaction.
+	best := chain.BestSnapshot()
+	headers := make(map[chainhash.Hash]*wire.BlockHeader)
+	srtList := make([]types.TxRawResult, len(addressTxns))
+	for i := range addressTxns {
+		rtx := &addressTxns[i]
+		mtx := new(wire.MsgTx)
+		if rtx.tx != nil {
+			mtx = rtx.tx.MsgTx()
+		} else {
+			err := mtx.Deserialize(bytes.NewReader(rtx.txBytes))
+			if err != nil {
+				return nil, rpcInternalErr(err,
+					"Failed to deserialize transaction")
+			}
+		}
+
+		// Fetch the header for transactions in a block.  The previous block
+		// hash is the current best chain tip for transactions in the mempool.
+		var (
+			blkHeader     *wire.BlockHeader
+			prevBlkHash   = best.Hash
+			blkHashStr    string
+			blkHeight     int64
+			blkIndex      uint32
+			confirmations int64
+		)
+		if rtx.entry != nil {
+			blkHash := rtx.entry.BlockRegion.Hash
+			blkHeader = headers[*blkHash]
+			if blkHeader == nil {
+				header, err := chain.HeaderByHash(blkHash)
+				if err != nil {
+					return nil, rpcInternalErr(err,
+						"Failed to fetch block header")
+				}
+				blkHeader = &header
+				headers[*blkHash] = blkHeader
+			}
+			prevBlkHash = blkHeader.PrevBlock
+			blkHashStr = blkHash.String()
+			blkHeight = int64(blkHeader.Height)
+			blkIndex = rtx.entry.BlockIndex
+			confirmations = 1 + best.Height - blkHeight
+		}
+
+		// Determine if the treasury rules are active as of either the block
+		// that contains the transaction or the current best tip when it is in
+		// the mempool.
+		isTreasuryEnabled, err := s.isTreasuryAgendaActive(&prevBlkHash)
+		if err != nil {
+			return nil, rpcInternalErr(err, "Treasury Status")
+		}
+
+		rawTxn, err := s.createTxRawResult(s.cfg.ChainParams, mtx,
+			mtx.TxHash().String(), blkIndex, blkHeader, blkHashStr,
+			blkHeight, confirmations, isTreasuryEnabled)
+		if err != nil {
+			return nil, err
+		}
+		srtList[i] = *rawTxn
+	}
+
+	return srtList, nil
+}
+
 // handleSendRawMixMessage implements the sendrawmixmessage command.
 func handleSendRawMixMessage(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.SendRawMixMessageCmd)
@@ -4445,6 +5104,80 @@ func handleSendRawTransaction(_ context.Context, s *Server, cmd interface{}) (in
 	return tx.Hash().String(), nil
 }
 
+// parseSubnet parses the provided string as either a subnet in CIDR notation
+// or an individual IP address, in which case the returned subnet only contains
+// that address.
+func parseSubnet(subnet string) (*net.IPNet, error) {
+	if strings.Contains(subnet, "/") {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		return ipNet, err
+	}
+
+	ip := net.ParseIP(subnet)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", subnet)
+	}
+	return banmanager.HostSubnet(ip), nil
+}
+
+// handleSetBan implements the setban command.
+func handleSetBan(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SetBanCmd)
+
+	subnet, err := parseSubnet(c.Subnet)
+	if err != nil {
+		return nil, rpcInvalidError("Invalid IP address or subnet: %v",
+			c.Subnet)
+	}
+
+	connMgr := s.cfg.ConnMgr
+	switch c.SubCmd {
+	case types.SBAdd:
+		// Ban for the default duration unless a ban time is provided.  The
+		// ban time is either a number of seconds from now or a unix
+		// timestamp when the absolute flag is set.
+		now := time.Now()
+		expires := now.Add(s.cfg.BanDuration)
+		if c.BanTime != nil && *c.BanTime != 0 {
+			if *c.BanTime < 0 {
+				return nil, rpcInvalidError("Ban time must not be negative")
+			}
+			switch {
+			case c.Absolute != nil && *c.Absolute:
+				expires = time.Unix(*c.BanTime, 0)
+			case *c.BanTime > int64(math.MaxInt64/time.Second):
+				return nil, rpcInvalidError("Ban time is too large")
+			default:
+				expires = now.Add(time.Duration(*c.BanTime) * time.Second)
+			}
+		}
+		if !now.Before(expires) {
+			return nil, rpcInvalidError("Ban expiration time %v is in the "+
+				"past", expires)
+		}
+
+		reason := "manually banned"
+		if c.Reason != nil && *c.Reason != "" {
+			reason = *c.Reason
+		}
+		err = connMgr.BanSubnet(subnet, expires, reason)
+
+	case types.SBRemove:
+		err = connMgr.UnbanSubnet(subnet)
+
+	default:
+		return nil, rpcInvalidError("%v: invalid subcommand for setban",
+			c.SubCmd)
+	}
+
+	if err != nil {
+		return nil, rpcMiscError(fmt.Sprintf("%v: %v", c.SubCmd, err))
+	}
+
+	// no data returned unless an error.
+	return nil, nil
+}
+
 // handleSetGenerate implements the setgenerate command.
 func handleSetGenerate(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.SetGenerateCmd)
@@ -4780,6 +5513,79 @@ func ticketFeeInfoForRange(s *Server, start int64, end int64, txType stake.TxTyp
 	}, nil
 }
 
+// handleTestMempoolAccept implements the testmempoolaccept command.
+func handleTestMempoolAccept(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.TestMempoolAcceptCmd)
+
+	numTxns := len(c.RawTxns)
+	if numTxns == 0 || numTxns > maxTestMempoolAcceptTxns {
+		return nil, rpcInvalidError("Number of transactions must be between "+
+			"1 and %d -- got %d", maxTestMempoolAcceptTxns, numTxns)
+	}
+
+	// Deserialize all of the transactions.
+	txns := make([]*dcrutil.Tx, 0, numTxns)
+	for _, hexStr := range c.RawTxns {
+		if len(hexStr)%2 != 0 {
+			hexStr = "0" + hexStr
+		}
+		serializedTx, err := hex.DecodeString(hexStr)
+		if err != nil {
+			return nil, rpcDecodeHexError(hexStr)
+		}
+		msgTx := wire.NewMsgTx()
+		err = msgTx.Deserialize(bytes.NewReader(serializedTx))
+		if err != nil {
+			return nil, rpcDeserializationError("Could not decode Tx: %v",
+				err)
+		}
+		txns = append(txns, dcrutil.NewTx(msgTx))
+	}
+
+	results, err := s.cfg.TxMempooler.TestAcceptTransactions(txns,
+		*c.AllowHighFees)
+	if err != nil {
+		return nil, rpcInternalErr(err, "Unable to test transactions")
+	}
+
+	// Report the reason any transactions would be rejected by the error kind
+	// of the underlying rule violation.  Any errors that are not rule errors
+	// mean something actually went wrong as opposed to the transaction
+	// simply being rejected.
+	reply := make([]types.TestMempoolAcceptResult, 0, len(results))
+	for _, result := range results {
+		r := types.TestMempoolAcceptResult{
+			TxID:    result.Tx.Hash().String(),
+			Allowed: result.Err == nil,
+			Size:    result.Size,
+		}
+		if result.Err == nil {
+			fee := dcrutil.Amount(result.Fee).ToCoin()
+			r.Fee = &fee
+			reply = append(reply, r)
+			continue
+		}
+
+		var rErr mempool.RuleError
+		if !errors.As(result.Err, &rErr) {
+			context := fmt.Sprintf("Unable to test transaction %v", r.TxID)
+			return nil, rpcInternalErr(result.Err, context)
+		}
+		var kind mempool.ErrorKind
+		var chainKind blockchain.ErrorKind
+		switch {
+		case errors.As(rErr, &kind):
+			r.RejectReason = string(kind)
+		case errors.As(rErr, &chainKind):
+			r.RejectReason = string(chainKind)
+		}
+		r.RejectMessage = rErr.Description
+		reply = append(reply, r)
+	}
+
+	return reply, nil
+}
+
 // handleTicketFeeInfo implements the ticketfeeinfo command.
 func handleTicketFeeInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.TicketFeeInfoCmd)
@@ -5197,11 +6003,13 @@ type Server struct {
 	hmacMu                 sync.Mutex
 	authsha                [sha256.Size]byte
 	limitauthsha           [sha256.Size]byte
+	authUsers              map[string]*AuthUser
 	ntfnMgr                NtfnManager
 	statusLines            map[int]string
 	statusLock             sync.RWMutex
 	workState              *workState
 	helpCacher             RPCHelpCacher
+	rateLimiter            *rateLimiter
 	requestProcessShutdown chan struct{}
 
 	// blake256Hasher is the hash.Hash object that is used after
@@ -5349,6 +6157,13 @@ func (s *Server) NotifyNewTransactions(txns []*dcrutil.Tx) {
 	}
 }
 
+// NotifyMempoolRemoval notifies websocket clients that have registered to
+// receive mempool removal notifications that the passed transaction was removed
+// from the mempool for the provided reason.
+func (s *Server) NotifyMempoolRemoval(tx *dcrutil.Tx, reason mempool.RemovalReason) {
+	s.ntfnMgr.NotifyMempoolRemoval(tx, reason)
+}
+
 // NotifyTSpend notifies websocket clients that have registered to receive new
 // tspends in the mempool.
 func (s *Server) NotifyTSpend(tx *dcrutil.Tx) {
@@ -5440,33 +6255,41 @@ func (s *Server) authMAC(dst, auth []byte) []byte {
 }
 
 // checkAuthMAC checks the HTTP Basic authentication string by comparing
-// it with the already generated hash.
+// it with the already generated hashes and the hashed credentials of the
+// configured RPC users.
 //
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false).
-func (s *Server) checkAuthMAC(auth, remoteAddr string) (bool, bool) {
+// The bool return value signifies auth success (true if successful) and the
+// permission group return value specifies the methods the user may invoke.
+// The group is nil when authentication fails.
+func (s *Server) checkAuthMAC(auth, remoteAddr string) (bool, *PermissionGroup) {
 	mac := make([]byte, 0, sha256.Size)
 	mac = s.authMAC(mac, []byte(auth))
 
 	cmp := subtle.ConstantTimeCompare(mac, s.authsha[:])
 	limitcmp := subtle.ConstantTimeCompare(mac, s.limitauthsha[:])
-	if cmp|limitcmp == 0 {
-		// Request's auth doesn't match either user
-		log.Warnf("RPC authentication failure from %s", remoteAddr)
-		return false, false
+	switch {
+	case cmp == 1:
+		return true, adminGroup
+	case limitcmp == 1:
+		return true, limitedGroup
 	}
-	return true, cmp == 1
+	if group := s.checkAuthUsers(auth); group != nil {
+		return true, group
+	}
+
+	// Request's auth doesn't match any user
+	log.Warnf("RPC authentication failure from %s", remoteAddr)
+	return false, nil
 }
 
 // checkAuthUserPass checks the correctness of username and password by
 // generating the corresponding HTTP Basic authentication string then
 // compare the string with the already generated hash.
 //
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false).
-func (s *Server) checkAuthUserPass(user, pass, remoteAddr string) (bool, bool) {
+// The bool return value signifies auth success (true if successful) and the
+// permission group return value specifies the methods the user may invoke.
+// The group is nil when authentication fails.
+func (s *Server) checkAuthUserPass(user, pass, remoteAddr string) (bool, *PermissionGroup) {
 	login := user + ":" + pass
 	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
 	return s.checkAuthMAC(auth, remoteAddr)
@@ -5476,18 +6299,40 @@ func (s *Server) checkAuthUserPass(user, pass, remoteAddr string) (bool, bool) {
 // client in the HTTP request r.  If the supplied authentication does not match
 // the username and password expected, a non-nil error is returned.
 //
+// When no RPC users are configured, TLS client certificates are being used for
+// authentication instead and the permission group is determined by the subject
+// of the verified client certificate when any are mapped to groups.
+//
 // This check is time-constant.
 //
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false). The second is
-// always false if the first is.
-func (s *Server) checkAuth(r *http.Request, require bool) (bool, bool, error) {
-	// If admin-level RPC user and pass options are not set, this always
-	// succeeds.  This will be the case when TLS client certificates are
-	// being used for authentication.
-	if s.authsha == ([32]byte{}) {
-		return true, true, nil
+// The bool return value signifies auth success (true if successful) and the
+// permission group return value specifies the methods the user may invoke.
+// The group is always nil if authentication is not successful.
+func (s *Server) checkAuth(r *http.Request, require bool) (bool, *PermissionGroup, error) {
+	// If no RPC user and pass options are set, this always succeeds with
+	// full access unless client certificate subjects are mapped to
+	// permission groups.  This will be the case when TLS client
+	// certificates are being used for authentication.
+	if s.authsha == ([32]byte{}) && s.limitauthsha == ([32]byte{}) &&
+		len(s.authUsers) == 0 {
+
+		if len(s.cfg.ClientCertGroups) == 0 {
+			return true, adminGroup, nil
+		}
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			log.Warnf("RPC authentication failure from %s: no verified "+
+				"client certificate", r.RemoteAddr)
+			return false, nil, errors.New("auth failure")
+		}
+		cert := r.TLS.VerifiedChains[0][0]
+		group := s.clientCertGroup(cert)
+		if group == nil {
+			log.Warnf("RPC authentication failure from %s: client "+
+				"certificate subject %q is not mapped to a permission "+
+				"group", r.RemoteAddr, cert.Subject.String())
+			return false, nil, errors.New("auth failure")
+		}
+		return true, group, nil
 	}
 
 	authhdr := r.Header["Authorization"]
@@ -5495,17 +6340,17 @@ func (s *Server) checkAuth(r *http.Request, require bool) (bool, bool, error) {
 		if require {
 			log.Warnf("RPC authentication failure from %s",
 				r.RemoteAddr)
-			return false, false, errors.New("auth failure")
+			return false, nil, errors.New("auth failure")
 		}
 
-		return false, false, nil
+		return false, nil, nil
 	}
 
-	authed, isAdmin := s.checkAuthMAC(authhdr[0], r.RemoteAddr)
+	authed, group := s.checkAuthMAC(authhdr[0], r.RemoteAddr)
 	if !authed {
-		return false, false, errors.New("auth failure")
+		return false, nil, errors.New("auth failure")
 	}
-	return authed, isAdmin, nil
+	return authed, group, nil
 }
 
 // parsedRPCCmd represents a JSON-RPC request object that has been parsed into
@@ -5529,7 +6374,19 @@ func (s *Server) standardCmdResult(ctx context.Context, cmd *parsedRPCCmd) (inte
 		return nil, dcrjson.ErrRPCMethodNotFound
 	}
 
-	return handler(ctx, s, cmd.params)
+	start := time.Now()
+	result, err := handler(ctx, s, cmd.params)
+	s.requestProcessed(cmd.method, start, err)
+	return result, err
+}
+
+// requestProcessed invokes the optional function the server was configured
+// with to observe processed requests with the provided method, the amount of
+// time since the provided start time, and the result error.
+func (s *Server) requestProcessed(method types.Method, start time.Time, err error) {
+	if s.cfg.RequestProcessed != nil {
+		s.cfg.RequestProcessed(method, time.Since(start), err)
+	}
 }
 
 // parseCmd parses a JSON-RPC request object into known concrete command.  The
@@ -5584,15 +6441,12 @@ func createMarshalledReply(rpcVersion string, id interface{}, result interface{}
 
 // processRequest determines the incoming request type (single or batched),
 // parses it and returns a marshalled response.
-func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, isAdmin bool) []byte {
+func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, group *PermissionGroup, id rateLimitID) []byte {
 	var result interface{}
 	var jsonErr error
 
-	if !isAdmin {
-		if _, ok := rpcLimited[request.Method]; !ok {
-			jsonErr = rpcInvalidError("limited user not " +
-				"authorized for this method")
-		}
+	if !group.allows(request.Method) {
+		jsonErr = rpcInvalidError("%s", unauthorizedMethodMsg(group))
 	}
 
 	if jsonErr == nil {
@@ -5620,6 +6474,8 @@ func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, i
 		parsedCmd := parseCmd(request)
 		if parsedCmd.err != nil {
 			jsonErr = parsedCmd.err
+		} else if err := s.checkRateLimit(group, id, parsedCmd); err != nil {
+			jsonErr = err
 		} else {
 			result, jsonErr = s.standardCmdResult(ctx, parsedCmd)
 		}
@@ -5635,7 +6491,7 @@ func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, i
 }
 
 // jsonRPCRead handles reading and responding to RPC messages.
-func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *http.Request, isAdmin bool) {
+func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *http.Request, group *PermissionGroup) {
 	select {
 	case <-sCtx.Done():
 		return
@@ -5643,6 +6499,7 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 	}
 
 	// Read and close the JSON-RPC request body from the caller.
+	rateID := requestRateLimitID(r)
 	bodyReader := io.LimitReader(r.Body, rpcReadLimitAuthenticated)
 	body, err := io.ReadAll(bodyReader)
 	r.Body.Close()
@@ -5718,7 +6575,7 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 				log.Errorf("Failed to create reply: %v", err)
 			}
 		} else {
-			resp = s.processRequest(ctx, &req, isAdmin)
+			resp = s.processRequest(ctx, &req, group, rateID)
 		}
 
 		if resp != nil {
@@ -5787,7 +6644,7 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 						continue
 					}
 
-					resp = s.processRequest(ctx, &req, isAdmin)
+					resp = s.processRequest(ctx, &req, group, rateID)
 					if resp != nil {
 						results = append(results, resp)
 					}
@@ -5914,19 +6771,19 @@ func (s *Server) route(ctx context.Context) *http.Server {
 		// Keep track of the number of connected clients.
 		s.incrementClients()
 		defer s.decrementClients()
-		_, isAdmin, err := s.checkAuth(r, true)
+		_, group, err := s.checkAuth(r, true)
 		if err != nil {
 			jsonAuthFail(w)
 			return
 		}
 
 		// Read and respond to the request.
-		s.jsonRPCRead(r.Context(), w, r, isAdmin)
+		s.jsonRPCRead(r.Context(), w, r, group)
 	})
 
 	// Websocket endpoint.
 	rpcServeMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		authenticated, isAdmin, err := s.checkAuth(r, false)
+		authenticated, group, err := s.checkAuth(r, false)
 		if err != nil {
 			jsonAuthFail(w)
 			return
@@ -6004,8 +6861,8 @@ func (s *Server) route(ctx context.Context) *http.Server {
 		} else {
 			ws.SetReadLimit(websocketReadLimitAuthenticated)
 		}
-		s.WebsocketHandler(r.Context(), ws, r.RemoteAddr, authenticated,
-			isAdmin)
+		s.WebsocketHandler(r.Context(), ws, r.RemoteAddr, requestUser(r),
+			authenticated, group)
 	})
 	return httpServer
 }
@@ -6025,6 +6882,16 @@ func (s *Server) Run(ctx context.Context) {
 			wg.Done()
 		}(listener)
 	}
+	restServer := s.restRoute(ctx)
+	for _, listener := range s.cfg.RESTListeners {
+		wg.Add(1)
+		go func(listener net.Listener) {
+			log.Infof("REST server listening on %s", listener.Addr())
+			restServer.Serve(listener)
+			log.Tracef("REST listener done for %s", listener.Addr())
+			wg.Done()
+		}(listener)
+	}
 
 	// Subscribe for async work notifications when background template
 	// generation is enabled.
@@ -6053,7 +6920,11 @@ func (s *Server) Run(ctx context.Context) {
 	// Close all listeners and wait for all goroutines to terminate.
 	log.Warnf("RPC server shutting down")
 	var hasCloseErr bool
-	for _, listener := range s.cfg.Listeners {
+	listeners := make([]net.Listener, 0, len(s.cfg.Listeners)+
+		len(s.cfg.RESTListeners))
+	listeners = append(listeners, s.cfg.Listeners...)
+	listeners = append(listeners, s.cfg.RESTListeners...)
+	for _, listener := range listeners {
 		err := listener.Close()
 		if err != nil {
 			log.Errorf("Failed to close listener %s: %v", listener.Addr(), err)
@@ -6074,6 +6945,11 @@ type Config struct {
 	// is stopped.
 	Listeners []net.Listener
 
+	// RESTListeners defines a slice of listeners for which the RPC server
+	// will take ownership of and serve the unauthenticated read-only REST
+	// interface.  They are closed when the RPC server is stopped.
+	RESTListeners []net.Listener
+
 	// StartupTime is the unix timestamp for when the server that is hosting
 	// the RPC server started.
 	StartupTime int64
@@ -6120,6 +6996,14 @@ type Config struct {
 	// TxMempooler defines the transaction memory pool to interact with.
 	TxMempooler TxMempooler
 
+	// MempoolSaver defines the means to persist the transaction memory pool
+	// to disk.
+	MempoolSaver MempoolSaver
+
+	// UtxoSnapshotter defines the means to write chain state snapshots to
+	// disk.
+	UtxoSnapshotter UtxoSnapshotter
+
 	// These fields allow the RPC server to interface with mining.
 	//
 	// BlockTemplater generates block templates, CPUMiner solves
@@ -6132,6 +7016,14 @@ type Config struct {
 	// use.
 	TxIndexer TxIndexer
 
+	// AddrIndexer defines the optional address indexer for the RPC server to
+	// use.
+	AddrIndexer AddrIndexer
+
+	// SpendIndexer defines the optional spend indexer for the RPC server to
+	// use.
+	SpendIndexer SpendIndexer
+
 	// NetInfo defines a slice of the available networks.
 	NetInfo []types.NetworksResult
 
@@ -6139,6 +7031,14 @@ type Config struct {
 	// considered a non-zero fee.
 	MinRelayTxFee dcrutil.Amount
 
+	// MaxMempoolSize defines the maximum total size in bytes of the
+	// transactions in the mempool.
+	MaxMempoolSize int64
+
+	// BanDuration defines the default duration subnets are banned for via
+	// the setban command.
+	BanDuration time.Duration
+
 	// Proxy defines the proxy that is being used for connections.
 	Proxy string
 
@@ -6149,6 +7049,18 @@ type Config struct {
 	RPCLimitUser string
 	RPCLimitPass string
 
+	// RPCAuthUsers defines additional RPC users that authenticate with salted
+	// and hashed credentials along with the permission group of each.
+	RPCAuthUsers []*AuthUser
+
+	// ClientCertGroups maps the subjects of TLS client certificates to the
+	// permission group that determines the methods they may invoke when
+	// client certificates are used for authentication.  The subjects are in
+	// the string form of a distinguished name, for example, CN=dcrwallet.
+	// All clients with a valid certificate may invoke any method when it is
+	// empty.
+	ClientCertGroups map[string]*PermissionGroup
+
 	// RPCMaxClients defines the max number of RPC clients for standard
 	// connections.
 	RPCMaxClients int
@@ -6160,6 +7072,16 @@ type Config struct {
 	// RPCMaxWebsockets defines the max number of RPC websocket connections.
 	RPCMaxWebsockets int
 
+	// RPCRateLimit defines the sustained number of request weight tokens per
+	// second that each authenticated user and each remote IP address outside
+	// of the admin permission group may consume.  Rate limiting is disabled
+	// when it is zero.
+	RPCRateLimit float64
+
+	// RPCRateBurst defines the max number of request weight tokens each rate
+	// limited client may accumulate.
+	RPCRateBurst float64
+
 	// TestNet represents whether or not the server is using testnet.
 	TestNet bool
 
@@ -6185,6 +7107,11 @@ type Config struct {
 
 	// MixPooler defines the mixpool for the RPC server to use.
 	MixPooler MixPooler
+
+	// RequestProcessed defines an optional function to invoke with the
+	// method, processing time, and resulting error of each request handled by
+	// the RPC server.  It may be nil.
+	RequestProcessed func(method types.Method, elapsed time.Duration, err error)
 }
 
 // New returns a new instance of the Server struct.
@@ -6212,6 +7139,16 @@ func New(config *Config) (*Server, error) {
 			base64.StdEncoding.EncodeToString([]byte(login))
 		rpc.authMAC(rpc.limitauthsha[:0], []byte(auth))
 	}
+	if len(config.RPCAuthUsers) > 0 {
+		rpc.authUsers = make(map[string]*AuthUser, len(config.RPCAuthUsers))
+		for _, user := range config.RPCAuthUsers {
+			rpc.authUsers[user.Name] = user
+		}
+	}
+	if config.RPCRateLimit > 0 {
+		rpc.rateLimiter = newRateLimiter(config.RPCRateLimit,
+			config.RPCRateBurst)
+	}
 	rpc.ntfnMgr = newWsNotificationManager(&rpc)
 
 	return &rpc, nil
@@ -1,5 +1,5 @@
 // Copyright (c) 2016 The btcsuite developers
-// Copyright (c) 2017-2022 The Decred developers
+// Copyright (c) 2017-2026 The Decred developers
 
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
@@ -28,53 +28,53 @@ func TestCheckAuthUserPass(t *testing.T) {
 		user       string
 		pass       string
 		wantAuthed bool
-		wantAdmin  bool
+		wantGroup  *PermissionGroup
 	}{
 		{
 			name:       "correct admin",
 			user:       "user",
 			pass:       "pass",
 			wantAuthed: true,
-			wantAdmin:  true,
+			wantGroup:  adminGroup,
 		},
 		{
 			name:       "correct limited user",
 			user:       "limit",
 			pass:       "limit",
 			wantAuthed: true,
-			wantAdmin:  false,
+			wantGroup:  limitedGroup,
 		},
 		{
 			name:       "invalid admin",
 			user:       "user",
 			pass:       "p",
 			wantAuthed: false,
-			wantAdmin:  false,
+			wantGroup:  nil,
 		},
 		{
 			name:       "invalid limited user",
 			user:       "limit",
 			pass:       "",
 			wantAuthed: false,
-			wantAdmin:  false,
+			wantGroup:  nil,
 		},
 		{
 			name:       "invalid empty user",
 			user:       "",
 			pass:       "",
 			wantAuthed: false,
-			wantAdmin:  false,
+			wantGroup:  nil,
 		},
 	}
 	for _, test := range tests {
-		authed, isAdmin := s.checkAuthUserPass(test.user, test.pass, "addr")
+		authed, group := s.checkAuthUserPass(test.user, test.pass, "addr")
 		if authed != test.wantAuthed {
 			t.Errorf("%q: unexpected authed -- got %v, want %v", test.name, authed,
 				test.wantAuthed)
 		}
-		if isAdmin != test.wantAdmin {
-			t.Errorf("%q: unexpected isAdmin -- got %v, want %v", test.name, isAdmin,
-				test.wantAdmin)
+		if group != test.wantGroup {
+			t.Errorf("%q: unexpected group -- got %v, want %v", test.name, group,
+				test.wantGroup)
 		}
 	}
 }
@@ -86,12 +86,12 @@ func TestCheckAuth(t *testing.T) {
 			t.Fatalf("unable to create RPC server: %v", err)
 		}
 		for i := 0; i <= 1; i++ {
-			authed, isAdmin, err := s.checkAuth(&http.Request{}, i == 0)
+			authed, group, err := s.checkAuth(&http.Request{}, i == 0)
 			if !authed {
 				t.Errorf(" unexpected authed -- got %v, want %v", authed, true)
 			}
-			if !isAdmin {
-				t.Errorf("unexpected isAdmin -- got %v, want %v", isAdmin, true)
+			if group != adminGroup {
+				t.Errorf("unexpected group -- got %v, want %v", group, adminGroup)
 			}
 			if err != nil {
 				t.Errorf("unexpected err -- got %v, want %v", err, nil)
@@ -109,12 +109,12 @@ func TestCheckAuth(t *testing.T) {
 			t.Fatalf("unable to create RPC server: %v", err)
 		}
 		for i := 0; i <= 1; i++ {
-			authed, isAdmin, err := s.checkAuth(&http.Request{}, i == 0)
+			authed, group, err := s.checkAuth(&http.Request{}, i == 0)
 			if authed {
 				t.Errorf(" unexpected authed -- got %v, want %v", authed, false)
 			}
-			if isAdmin {
-				t.Errorf("unexpected isAdmin -- got %v, want %v", isAdmin, false)
+			if group != nil {
+				t.Errorf("unexpected group -- got %v, want %v", group, nil)
 			}
 			if i == 0 && err == nil {
 				t.Errorf("unexpected err -- got %v, want auth failure", err)
@@ -125,12 +125,12 @@ func TestCheckAuth(t *testing.T) {
 		for i := 0; i <= 1; i++ {
 			r := &http.Request{Header: make(map[string][]string, 1)}
 			r.Header["Authorization"] = []string{"Basic Nothing"}
-			authed, isAdmin, err := s.checkAuth(r, i == 0)
+			authed, group, err := s.checkAuth(r, i == 0)
 			if authed {
 				t.Errorf(" unexpected authed -- got %v, want %v", authed, false)
 			}
-			if isAdmin {
-				t.Errorf("unexpected isAdmin -- got %v, want %v", isAdmin, false)
+			if group != nil {
+				t.Errorf("unexpected group -- got %v, want %v", group, nil)
 			}
 			if err == nil {
 				t.Errorf("unexpected err -- got %v, want auth failure", err)
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2025 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -38,9 +38,11 @@ import (
 	"github.com/decred/dcrd/gcs/v4/blockcf2"
 	"github.com/decred/dcrd/internal/blockchain"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/decred/dcrd/internal/fees"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/mining/cpuminer"
+	"github.com/decred/dcrd/internal/staging/banmanager"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/math/uint256"
 	"github.com/decred/dcrd/mixing"
@@ -171,6 +173,7 @@ type testRPCChain struct {
 	heightRangeFn                 func(startHeight, endHeight int64) ([]chainhash.Hash, error)
 	invalidateBlockErr            error
 	isCurrent                     bool
+	isPruned                      bool
 	liveTickets                   []chainhash.Hash
 	liveTicketsErr                error
 	locateHeaders                 []wire.BlockHeader
@@ -185,6 +188,8 @@ type testRPCChain struct {
 	missedTicketsErr              error
 	nextThresholdState            blockchain.ThresholdStateTuple
 	nextThresholdStateErr         error
+	pruneHeight                   int64
+	pruneTarget                   uint64
 	reconsiderBlockErr            error
 	stateLastChangedHeight        int64
 	stateLastChangedHeightErr     error
@@ -332,6 +337,12 @@ func (c *testRPCChain) IsCurrent() bool {
 	return c.isCurrent
 }
 
+// IsPruned returns a mocked bool representing whether or not the chain is
+// operating in pruned mode.
+func (c *testRPCChain) IsPruned() bool {
+	return c.isPruned
+}
+
 // LiveTickets returns a mocked slice of all currently live tickets.
 func (c *testRPCChain) LiveTickets() ([]chainhash.Hash, error) {
 	return c.liveTickets, c.liveTicketsErr
@@ -379,6 +390,17 @@ func (c *testRPCChain) NextThresholdState(hash *chainhash.Hash, deploymentID str
 	return c.nextThresholdState, c.nextThresholdStateErr
 }
 
+// PruneHeight returns a mocked height of the oldest block in the main chain for
+// which block data is available.
+func (c *testRPCChain) PruneHeight() int64 {
+	return c.pruneHeight
+}
+
+// PruneTarget returns a mocked target size in bytes for the stored block data.
+func (c *testRPCChain) PruneTarget() uint64 {
+	return c.pruneTarget
+}
+
 // ReconsiderBlock returns a mocked error from manually reconsidering a given
 // block.
 func (c *testRPCChain) ReconsiderBlock(hash *chainhash.Hash) error {
@@ -465,6 +487,7 @@ type testPeer struct {
 	lastPingNonce     uint64
 	isTxRelayDisabled bool
 	banScore          uint32
+	mappedAS          uint32
 	statsSnapshot     *peer.StatsSnap
 }
 
@@ -517,6 +540,11 @@ func (p *testPeer) BanScore() uint32 {
 	return p.banScore
 }
 
+// MappedAS returns a mocked autonomous system number the peer address maps to.
+func (p *testPeer) MappedAS() uint32 {
+	return p.mappedAS
+}
+
 // testProfManager provides a mock profiler manager by implementing the
 // ProfilerManager interface.
 type testProfManager struct {
@@ -698,6 +726,86 @@ func (t *testTxIndexer) Entry(hash *chainhash.Hash) (*indexers.TxIndexEntry, err
 	return t.entry(hash)
 }
 
+// testAddrIndexer provides a mock address indexer by implementing the
+// AddrIndexer interface.
+type testAddrIndexer struct {
+	entries      func(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error)
+	unconfirmed  []*dcrutil.Tx
+	tipHeight    int64
+	tipHash      *chainhash.Hash
+	tipErr       error
+	signalOnWait bool
+}
+
+// Name returns the human-readable name of the index.
+func (a *testAddrIndexer) Name() string {
+	return "testAddrIndexer"
+}
+
+// Tip returns the current index tip.
+func (a *testAddrIndexer) Tip() (int64, *chainhash.Hash, error) {
+	return a.tipHeight, a.tipHash, a.tipErr
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+func (a *testAddrIndexer) WaitForSync() chan bool {
+	c := make(chan bool)
+	if a.signalOnWait {
+		close(c)
+	}
+	return c
+}
+
+// EntriesForAddress returns mocked transaction index entries for the provided
+// address.
+func (a *testAddrIndexer) EntriesForAddress(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error) {
+	return a.entries(addr, numToSkip, numRequested, reverse)
+}
+
+// UnconfirmedTxnsForAddress returns mocked unconfirmed transactions that
+// involve the provided address.
+func (a *testAddrIndexer) UnconfirmedTxnsForAddress(addr stdaddr.Address) []*dcrutil.Tx {
+	txns := make([]*dcrutil.Tx, len(a.unconfirmed))
+	copy(txns, a.unconfirmed)
+	return txns
+}
+
+// testSpendIndexer provides a mock spend indexer by implementing the
+// SpendIndexer interface.
+type testSpendIndexer struct {
+	entry        *indexers.SpendIndexEntry
+	entryErr     error
+	tipHeight    int64
+	tipHash      *chainhash.Hash
+	tipErr       error
+	signalOnWait bool
+}
+
+// Name returns the human-readable name of the index.
+func (i *testSpendIndexer) Name() string {
+	return "testSpendIndexer"
+}
+
+// Tip returns the current index tip.
+func (i *testSpendIndexer) Tip() (int64, *chainhash.Hash, error) {
+	return i.tipHeight, i.tipHash, i.tipErr
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+func (i *testSpendIndexer) WaitForSync() chan bool {
+	c := make(chan bool)
+	if i.signalOnWait {
+		close(c)
+	}
+	return c
+}
+
+// Entry returns mocked details about the transaction that spent the provided
+// outpoint.
+func (i *testSpendIndexer) Entry(outpoint *wire.OutPoint) (*indexers.SpendIndexEntry, error) {
+	return i.entry, i.entryErr
+}
+
 // testDB provides a mock database by implementing the database.DB interface.
 type testDB struct {
 	dbType   string
@@ -849,6 +957,10 @@ type testConnManager struct {
 	connectedPeers      []Peer
 	persistentPeers     []Peer
 	lookup              func(host string) ([]net.IP, error)
+	banSubnetErr        error
+	unbanSubnetErr      error
+	bannedSubnets       []banmanager.BanEntry
+	clearBansErr        error
 }
 
 // Connect provides a mock implementation for adding the provided address as a
@@ -924,6 +1036,27 @@ func (c *testConnManager) Lookup(host string) ([]net.IP, error) {
 	return c.lookup(host)
 }
 
+// BanSubnet provides a mock implementation for banning the provided subnet.
+func (c *testConnManager) BanSubnet(subnet *net.IPNet, expires time.Time, reason string) error {
+	return c.banSubnetErr
+}
+
+// UnbanSubnet provides a mock implementation for removing the ban for the
+// provided subnet.
+func (c *testConnManager) UnbanSubnet(subnet *net.IPNet) error {
+	return c.unbanSubnetErr
+}
+
+// BannedSubnets returns a mocked list of banned subnets.
+func (c *testConnManager) BannedSubnets() []banmanager.BanEntry {
+	return c.bannedSubnets
+}
+
+// ClearBans provides a mock implementation for removing all bans.
+func (c *testConnManager) ClearBans() error {
+	return c.clearBansErr
+}
+
 // testCPUMiner provides a mock CPU miner by implementing the CPUMiner
 // interface.
 type testCPUMiner struct {
@@ -995,8 +1128,11 @@ func (c *testClock) Since(t time.Time) time.Duration {
 // testFeeEstimator provides a mock fee estimator by implementing the
 // FeeEstimator interface.
 type testFeeEstimator struct {
-	estimateFeeAmt dcrutil.Amount
-	estimateFeeErr error
+	estimateFeeAmt    dcrutil.Amount
+	estimateFeeErr    error
+	rawFeeEstimate    *fees.RawFeeEstimate
+	estimateRawFeeErr error
+	stats             *fees.EstimatorStats
 }
 
 // EstimateFee provides a mock implementation that calculates the
@@ -1005,6 +1141,48 @@ func (e *testFeeEstimator) EstimateFee(targetConfs int32) (dcrutil.Amount, error
 	return e.estimateFeeAmt, e.estimateFeeErr
 }
 
+// EstimateRawFee provides a mock implementation that calculates the suggested
+// fee for a transaction along with the details of the estimate.
+func (e *testFeeEstimator) EstimateRawFee(targetConfs int32, successPct float64) (*fees.RawFeeEstimate, error) {
+	return e.rawFeeEstimate, e.estimateRawFeeErr
+}
+
+// Stats provides a mock implementation that returns the internal state of the
+// fee estimator.
+func (e *testFeeEstimator) Stats() *fees.EstimatorStats {
+	return e.stats
+}
+
+// testMempoolSaver provides a mock mempool saver by implementing the
+// MempoolSaver interface.
+type testMempoolSaver struct {
+	filename       string
+	numTxns        int
+	saveMempoolErr error
+}
+
+// SaveMempool provides a mock implementation for persisting the mempool.
+func (m *testMempoolSaver) SaveMempool() (string, int, error) {
+	return m.filename, m.numTxns, m.saveMempoolErr
+}
+
+// testUtxoSnapshotter provides a mock utxo snapshotter by implementing the
+// UtxoSnapshotter interface.
+type testUtxoSnapshotter struct {
+	dataDir         string
+	info            *blockchain.SnapshotInfo
+	dumpSnapshotErr error
+}
+
+// DumpUtxoSnapshot provides a mock implementation for writing chain state
+// snapshots.
+func (u *testUtxoSnapshotter) DumpUtxoSnapshot(_ context.Context, path string) (string, *blockchain.SnapshotInfo, error) {
+	if u.dumpSnapshotErr != nil {
+		return "", nil, u.dumpSnapshotErr
+	}
+	return filepath.Join(u.dataDir, path), u.info, nil
+}
+
 // testLogManager provides a mock log manager by implementing the LogManager
 // interface.
 type testLogManager struct {
@@ -1133,10 +1311,18 @@ type testTxMempooler struct {
 	haveTransactions    []bool
 	txDescs             []*mempool.TxDesc
 	verboseTxDescs      []*mempool.VerboseTxDesc
+	verboseTxDesc       *mempool.VerboseTxDesc
+	ancestorTxDescs     []*mempool.VerboseTxDesc
+	descendantTxDescs   []*mempool.VerboseTxDesc
+	txDescErr           error
 	count               int
 	fetchTransaction    *dcrutil.Tx
 	fetchTransactionErr error
 	tspendHashes        []chainhash.Hash
+	minRelayTxFee       dcrutil.Amount
+	testAcceptFees      []int64
+	testAcceptErrs      []error
+	testAcceptErr       error
 }
 
 // HaveTransactions returns a mocked bool slice representing whether or not the
@@ -1157,6 +1343,24 @@ func (mp *testTxMempooler) VerboseTxDescs() []*mempool.VerboseTxDesc {
 	return mp.verboseTxDescs
 }
 
+// VerboseTxDesc returns a mock verbose descriptor for the transaction with the
+// provided hash.
+func (mp *testTxMempooler) VerboseTxDesc(txHash *chainhash.Hash) (*mempool.VerboseTxDesc, error) {
+	return mp.verboseTxDesc, mp.txDescErr
+}
+
+// AncestorTxDescs returns a mock slice of verbose descriptors for the ancestors
+// of the transaction with the provided hash.
+func (mp *testTxMempooler) AncestorTxDescs(txHash *chainhash.Hash) ([]*mempool.VerboseTxDesc, error) {
+	return mp.ancestorTxDescs, mp.txDescErr
+}
+
+// DescendantTxDescs returns a mock slice of verbose descriptors for the
+// descendants of the transaction with the provided hash.
+func (mp *testTxMempooler) DescendantTxDescs(txHash *chainhash.Hash) ([]*mempool.VerboseTxDesc, error) {
+	return mp.descendantTxDescs, mp.txDescErr
+}
+
 // Count returns a mock number of transactions in the main pool.
 func (mp *testTxMempooler) Count() int {
 	return mp.count
@@ -1174,6 +1378,35 @@ func (mp *testTxMempooler) TSpendHashes() []chainhash.Hash {
 	return mp.tspendHashes
 }
 
+// MinRelayTxFee returns a mocked minimum fee in atoms/kB that regular
+// transactions must currently pay to be accepted into the mempool.
+func (mp *testTxMempooler) MinRelayTxFee() dcrutil.Amount {
+	return mp.minRelayTxFee
+}
+
+// TestAcceptTransactions returns mocked results for testing whether or not the
+// provided transactions would be accepted to the mempool.
+func (mp *testTxMempooler) TestAcceptTransactions(txns []*dcrutil.Tx, allowHighFees bool) ([]*mempool.TestAcceptResult, error) {
+	if mp.testAcceptErr != nil {
+		return nil, mp.testAcceptErr
+	}
+	results := make([]*mempool.TestAcceptResult, 0, len(txns))
+	for i, tx := range txns {
+		result := &mempool.TestAcceptResult{
+			Tx:   tx,
+			Size: int64(tx.MsgTx().SerializeSize()),
+		}
+		if i < len(mp.testAcceptErrs) {
+			result.Err = mp.testAcceptErrs[i]
+		}
+		if result.Err == nil && i < len(mp.testAcceptFees) {
+			result.Fee = mp.testAcceptFees[i]
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // testNtfnManager provides a mock notification manager by implementing the
 // NtfnManager interface.
 type testNtfnManager struct {
@@ -1211,6 +1444,10 @@ func (mgr *testNtfnManager) NotifyNewTickets(tnd *blockchain.TicketNotifications
 // manager for processing.
 func (mgr *testNtfnManager) NotifyMempoolTx(tx *dcrutil.Tx, isNew bool) {}
 
+// NotifyMempoolRemoval passes a transaction removed from the mempool along with
+// the reason it was removed to the manager for processing.
+func (mgr *testNtfnManager) NotifyMempoolRemoval(tx *dcrutil.Tx, reason mempool.RemovalReason) {}
+
 // NotifyMixMessage passes a mixing message accepted by the mixpool to the
 // notification manager for message broadcasting.
 func (mgr *testNtfnManager) NotifyMixMessage(msg mixing.Message) {}
@@ -1284,6 +1521,14 @@ func (mgr *testNtfnManager) RegisterMixMessages(wsc *wsClient) {}
 // of any newly-accepted mixing messages.
 func (mgr *testNtfnManager) UnregisterMixMessages(wsc *wsClient) {}
 
+// RegisterMempoolRemovals requests notifications to the passed websocket
+// client when transactions are removed from the memory pool.
+func (mgr *testNtfnManager) RegisterMempoolRemovals(wsc *wsClient) {}
+
+// UnregisterMempoolRemovals removes notifications to the passed websocket
+// client when transactions are removed from the memory pool.
+func (mgr *testNtfnManager) UnregisterMempoolRemovals(wsc *wsClient) {}
+
 // AddClient adds the passed websocket client to the notification manager.
 func (mgr *testNtfnManager) AddClient(wsc *wsClient) {}
 
@@ -1291,6 +1536,13 @@ func (mgr *testNtfnManager) AddClient(wsc *wsClient) {}
 // registered for it.
 func (mgr *testNtfnManager) RemoveClient(wsc *wsClient) {}
 
+// ResumeSession transfers the session with the provided ID to the passed
+// websocket client and replays all retained notifications with a sequence
+// number after the provided one.
+func (mgr *testNtfnManager) ResumeSession(wsc *wsClient, sessionID, lastSeq uint64) (*types.ResumeSessionResult, error) {
+	return nil, nil
+}
+
 // Run starts the goroutines required for the manager to queue and process
 // websocket client notifications. It blocks until the provided context is
 // cancelled.
@@ -1431,12 +1683,18 @@ type rpcTest struct {
 	setExistsAddresserNil bool
 	mockTxIndexer         *testTxIndexer
 	setTxIndexerNil       bool
+	mockAddrIndexer       *testAddrIndexer
+	setAddrIndexerNil     bool
+	mockSpendIndexer      *testSpendIndexer
+	setSpendIndexerNil    bool
 	mockDB                *testDB
 	mockConnManager       *testConnManager
 	mockClock             *testClock
 	mockLogManager        *testLogManager
 	mockFiltererV2        *testFiltererV2
 	mockTxMempooler       *testTxMempooler
+	mockMempoolSaver      *testMempoolSaver
+	mockUtxoSnapshotter   *testUtxoSnapshotter
 	mockHelpCacher        *testHelpCacher
 	result                interface{}
 	wantErr               bool
@@ -1693,6 +1951,39 @@ func defaultMockTxIndexer() *testTxIndexer {
 	}
 }
 
+// defaultMockAddrIndexer provides a default mock address indexer to be
+// used throughout the tests. Tests can override these defaults by calling
+// defaultMockAddrIndexer, updating fields as necessary on the returned
+// *testAddrIndexer, and then setting rpcTest.mockAddrIndexer as that
+// *testAddrIndexer.
+func defaultMockAddrIndexer() *testAddrIndexer {
+	bestHeight := int64(block432100.Header.Height)
+	bestHash := block432100.Header.BlockHash()
+	return &testAddrIndexer{
+		tipHeight:    bestHeight,
+		tipHash:      &bestHash,
+		signalOnWait: true,
+		entries: func(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error) {
+			return nil, 0, nil
+		},
+	}
+}
+
+// defaultMockSpendIndexer provides a default mock spend indexer to be used
+// throughout the tests. Tests can override these defaults by calling
+// defaultMockSpendIndexer, updating fields as necessary on the returned
+// *testSpendIndexer, and then setting rpcTest.mockSpendIndexer as that
+// *testSpendIndexer.
+func defaultMockSpendIndexer() *testSpendIndexer {
+	bestHeight := int64(block432100.Header.Height)
+	bestHash := block432100.Header.BlockHash()
+	return &testSpendIndexer{
+		tipHeight:    bestHeight,
+		tipHash:      &bestHash,
+		signalOnWait: true,
+	}
+}
+
 // defaultMockDB provides a default mock database to be used throughout the
 // tests. Tests can override these defaults by calling defaultMockDB, updating
 // fields as necessary on the returned *testDB, and then setting rpcTest.mockDB
@@ -1785,7 +2076,74 @@ func defaultMockConnManager() *testConnManager {
 // *testFeeEstimator, and then setting rpcTest.mockFeeEstimator as that
 // *testFeeEstimator.
 func defaultMockFeeEstimator() *testFeeEstimator {
-	return &testFeeEstimator{}
+	return &testFeeEstimator{
+		rawFeeEstimate: &fees.RawFeeEstimate{
+			TargetConfs: 2,
+			SuccessPct:  0.95,
+			Decay:       0.998,
+			FeeRate:     10000,
+			Pass: &fees.BucketRangeStats{
+				StartRange:     9090,
+				EndRange:       math.Inf(1),
+				WithinTarget:   19,
+				TotalConfirmed: 19.5,
+				InMempool:      0.5,
+			},
+			Fail: &fees.BucketRangeStats{
+				StartRange:     0,
+				EndRange:       9090,
+				WithinTarget:   1,
+				TotalConfirmed: 3,
+				InMempool:      1,
+			},
+		},
+		stats: &fees.EstimatorStats{
+			BestHeight:  431782,
+			MaxConfirms: 2,
+			Decay:       0.998,
+			Buckets: []fees.BucketStats{{
+				StartRange:     0,
+				EndRange:       10000,
+				TotalConfirmed: 4,
+				FeeSum:         40000,
+				Confirmed:      []float64{2, 4},
+				InMempool:      []float64{1, 0},
+			}, {
+				StartRange: 10000,
+				EndRange:   math.Inf(1),
+				Confirmed:  []float64{0, 0},
+				InMempool:  []float64{0, 0},
+			}},
+		},
+	}
+}
+
+// defaultMockMempoolSaver provides a default mock mempool saver to be used
+// throughout the tests. Tests can override these defaults by calling
+// defaultMockMempoolSaver, updating fields as necessary on the returned
+// *testMempoolSaver, and then setting rpcTest.mockMempoolSaver as that
+// *testMempoolSaver.
+func defaultMockMempoolSaver() *testMempoolSaver {
+	return &testMempoolSaver{
+		filename: "/home/user/.dcrd/data/mainnet/mempool.dat",
+		numTxns:  5,
+	}
+}
+
+// defaultMockUtxoSnapshotter provides a default mock utxo snapshotter to be
+// used throughout the tests. Tests can override these defaults by calling
+// defaultMockUtxoSnapshotter, updating fields as necessary on the returned
+// *testUtxoSnapshotter, and then setting rpcTest.mockUtxoSnapshotter as that
+// *testUtxoSnapshotter.
+func defaultMockUtxoSnapshotter() *testUtxoSnapshotter {
+	return &testUtxoSnapshotter{
+		dataDir: "/home/user/.dcrd/data/mainnet",
+		info: &blockchain.SnapshotInfo{
+			Height:       431782,
+			BlockHash:    *mustParseHash("000000000000000023455b4328635d8e014dbeea99c6140aa715836cc7e55981"),
+			SnapshotHash: *mustParseHash("2c4b5b1d4a3e4f9d7c6b5a4938271605f4e3d2c1b0a99887766554433221100f"),
+		},
+	}
 }
 
 // defaultMockLogManager provides a default mock log manager to be used
@@ -1840,6 +2198,7 @@ func defaultMockCPUMiner() *testCPUMiner {
 func defaultMockTxMempooler() *testTxMempooler {
 	return &testTxMempooler{
 		fetchTransactionErr: errors.New("transaction is not in the pool"),
+		minRelayTxFee:       dcrutil.Amount(10000),
 	}
 }
 
@@ -1856,10 +2215,14 @@ func defaultMockConfig(chainParams *chaincfg.Params) *Config {
 		SyncMgr:         defaultMockSyncManager(),
 		ExistsAddresser: defaultMockExistsAddresser(),
 		TxIndexer:       defaultMockTxIndexer(),
+		AddrIndexer:     defaultMockAddrIndexer(),
+		SpendIndexer:    defaultMockSpendIndexer(),
 		DB:              defaultMockDB(),
 		ConnMgr:         defaultMockConnManager(),
 		CPUMiner:        defaultMockCPUMiner(),
 		TxMempooler:     defaultMockTxMempooler(),
+		MempoolSaver:    defaultMockMempoolSaver(),
+		UtxoSnapshotter: defaultMockUtxoSnapshotter(),
 		Clock:           &testClock{},
 		LogManager:      defaultMockLogManager(),
 		FiltererV2:      defaultMockFiltererV2(),
@@ -1886,6 +2249,8 @@ func defaultMockConfig(chainParams *chaincfg.Params) *Config {
 			ProxyRandomizeCredentials: false,
 		}},
 		MinRelayTxFee:      dcrutil.Amount(10000),
+		MaxMempoolSize:     300 * 1024 * 1024,
+		BanDuration:        24 * time.Hour,
 		MaxProtocolVersion: wire.CFilterV2Version,
 		UserAgentVersion: fmt.Sprintf("%d.%d.%d", version.Major, version.Minor,
 			version.Patch),
@@ -2553,6 +2918,28 @@ func TestHandleCreateRawSSRtx(t *testing.T) {
 	}})
 }
 
+func TestHandleClearBanned(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleClearBanned: ok",
+		handler: handleClearBanned,
+		cmd:     &types.ClearBannedCmd{},
+		result:  nil,
+	}, {
+		name:    "handleClearBanned: unable to persist bans",
+		handler: handleClearBanned,
+		cmd:     &types.ClearBannedCmd{},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.clearBansErr = errors.New("unable to write file")
+			return connManager
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleCreateRawTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -2921,6 +3308,39 @@ func TestHandleDecodeScript(t *testing.T) {
 	}})
 }
 
+func TestHandleDumpUtxoSet(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleDumpUtxoSet: ok",
+		handler: handleDumpUtxoSet,
+		cmd:     &types.DumpUtxoSetCmd{Path: "utxos.dat"},
+		result: &types.DumpUtxoSetResult{
+			Hash:         "000000000000000023455b4328635d8e014dbeea99c6140aa715836cc7e55981",
+			Height:       431782,
+			SnapshotHash: "2c4b5b1d4a3e4f9d7c6b5a4938271605f4e3d2c1b0a99887766554433221100f",
+			Path:         filepath.Join("/home/user/.dcrd/data/mainnet", "utxos.dat"),
+		},
+	}, {
+		name:    "handleDumpUtxoSet: empty path",
+		handler: handleDumpUtxoSet,
+		cmd:     &types.DumpUtxoSetCmd{},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleDumpUtxoSet: unable to dump snapshot",
+		handler: handleDumpUtxoSet,
+		cmd:     &types.DumpUtxoSetCmd{Path: "utxos.dat"},
+		mockUtxoSnapshotter: func() *testUtxoSnapshotter {
+			u := defaultMockUtxoSnapshotter()
+			u.dumpSnapshotErr = errors.New("file already exists")
+			return u
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleEstimateFee(t *testing.T) {
 	t.Parallel()
 
@@ -2932,6 +3352,146 @@ func TestHandleEstimateFee(t *testing.T) {
 	}})
 }
 
+func TestHandleDumpFeeBuckets(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleDumpFeeBuckets: ok",
+		handler: handleDumpFeeBuckets,
+		cmd:     &types.DumpFeeBucketsCmd{},
+		result: &types.DumpFeeBucketsResult{
+			BestHeight:  431782,
+			MaxConfirms: 2,
+			Decay:       0.998,
+			Buckets: []types.FeeBucketResult{{
+				StartRange:     0,
+				EndRange:       0.0001,
+				TotalConfirmed: 4,
+				AvgFeeRate:     0.0001,
+				Confirmed:      []float64{2, 4},
+				InMempool:      []float64{1, 0},
+			}, {
+				StartRange:     0.0001,
+				EndRange:       -1,
+				TotalConfirmed: 0,
+				AvgFeeRate:     0,
+				Confirmed:      []float64{0, 0},
+				InMempool:      []float64{0, 0},
+			}},
+		},
+	}})
+}
+
+func TestHandleEstimateRawFee(t *testing.T) {
+	t.Parallel()
+
+	threshold := 0.95
+	pass := &types.EstimateRawFeeBucket{
+		StartRange:     0.0000909,
+		EndRange:       -1,
+		WithinTarget:   19,
+		TotalConfirmed: 19.5,
+		InMempool:      0.5,
+		SuccessRatio:   0.95,
+	}
+	fail := &types.EstimateRawFeeBucket{
+		StartRange:     0,
+		EndRange:       0.0000909,
+		WithinTarget:   1,
+		TotalConfirmed: 3,
+		InMempool:      1,
+		SuccessRatio:   0.25,
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleEstimateRawFee: ok",
+		handler: handleEstimateRawFee,
+		cmd: &types.EstimateRawFeeCmd{
+			Confirmations: 2,
+			Threshold:     &threshold,
+		},
+		result: &types.EstimateRawFeeResult{
+			FeeRate:   0.0001,
+			Decay:     0.998,
+			Threshold: 0.95,
+			Blocks:    2,
+			Pass:      pass,
+			Fail:      fail,
+		},
+	}, {
+		name:    "handleEstimateRawFee: ok no threshold",
+		handler: handleEstimateRawFee,
+		cmd:     &types.EstimateRawFeeCmd{Confirmations: 2},
+		result: &types.EstimateRawFeeResult{
+			FeeRate:   0.0001,
+			Decay:     0.998,
+			Threshold: 0.95,
+			Blocks:    2,
+			Pass:      pass,
+			Fail:      fail,
+		},
+	}, {
+		name:    "handleEstimateRawFee: no estimate with details",
+		handler: handleEstimateRawFee,
+		cmd:     &types.EstimateRawFeeCmd{Confirmations: 2},
+		mockFeeEstimator: func() *testFeeEstimator {
+			feeEstimator := defaultMockFeeEstimator()
+			feeEstimator.rawFeeEstimate.FeeRate = 0
+			feeEstimator.rawFeeEstimate.Pass = nil
+			feeEstimator.estimateRawFeeErr = fees.ErrNoSuccessPctBucketFound
+			return feeEstimator
+		}(),
+		result: &types.EstimateRawFeeResult{
+			Decay:     0.998,
+			Threshold: 0.95,
+			Blocks:    2,
+			Fail:      fail,
+			Errors:    []string{fees.ErrNoSuccessPctBucketFound.Error()},
+		},
+	}, {
+		name:    "handleEstimateRawFee: invalid threshold",
+		handler: handleEstimateRawFee,
+		cmd: &types.EstimateRawFeeCmd{
+			Confirmations: 2,
+			Threshold:     dcrjson.Float64(1.5),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleEstimateRawFee: invalid confirmations",
+		handler: handleEstimateRawFee,
+		cmd:     &types.EstimateRawFeeCmd{Confirmations: 0},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleEstimateRawFee: target too large",
+		handler: handleEstimateRawFee,
+		cmd:     &types.EstimateRawFeeCmd{Confirmations: 100},
+		mockFeeEstimator: func() *testFeeEstimator {
+			feeEstimator := defaultMockFeeEstimator()
+			feeEstimator.rawFeeEstimate = nil
+			feeEstimator.estimateRawFeeErr = fees.ErrTargetConfTooLarge{
+				MaxConfirms: 32,
+				ReqConfirms: 100,
+			}
+			return feeEstimator
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleEstimateRawFee: estimate fee error",
+		handler: handleEstimateRawFee,
+		cmd:     &types.EstimateRawFeeCmd{Confirmations: 2},
+		mockFeeEstimator: func() *testFeeEstimator {
+			feeEstimator := defaultMockFeeEstimator()
+			feeEstimator.rawFeeEstimate = nil
+			feeEstimator.estimateRawFeeErr = errors.New("")
+			return feeEstimator
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleEstimateSmartFee(t *testing.T) {
 	t.Parallel()
 
@@ -3708,6 +4268,53 @@ func TestHandleGetBlockchainInfo(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name:    "handleGetBlockchainInfo: ok pruned",
+		handler: handleGetBlockchainInfo,
+		cmd:     &types.GetBlockChainInfoCmd{},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.bestSnapshot = &blockchain.BestState{
+				Height:   463073,
+				Bits:     404696953,
+				Hash:     *hash,
+				PrevHash: *prevHash,
+			}
+			chain.bestHeaderHash = *hash
+			chain.bestHeaderHeight = 463073
+			chain.chainWork = hexToUint256("115d2833849090b0026506")
+			chain.isCurrent = true
+			chain.isPruned = true
+			chain.pruneHeight = 459000
+			chain.pruneTarget = 2 * 1024 * 1024 * 1024
+			chain.maxBlockSize = 393216
+			chain.stateLastChangedHeight = int64(149248)
+			return chain
+		}(),
+		result: types.GetBlockChainInfoResult{
+			Chain:                "mainnet",
+			Blocks:               int64(463073),
+			Headers:              int64(463073),
+			SyncHeight:           int64(463074),
+			ChainWork:            "000000000000000000000000000000000000000000115d2833849090b0026506",
+			InitialBlockDownload: false,
+			VerificationProgress: float64(1),
+			BestBlockHash:        "00000000000000001e6ec1501c858506de1de4703d1be8bab4061126e8f61480",
+			Difficulty:           uint32(404696953),
+			DifficultyRatio:      float64(35256672611.3862),
+			MaxBlockSize:         int64(393216),
+			Pruned:               true,
+			PruneHeight:          459000,
+			PruneTargetSize:      2 * 1024 * 1024 * 1024,
+			Deployments: map[string]types.AgendaInfo{
+				"headercommitments": {
+					Status:     "started",
+					Since:      int64(149248),
+					StartTime:  uint64(1567641600),
+					ExpireTime: uint64(1599264000),
+				},
+			},
+		},
 	}, {
 		name:    "handleGetBlockchainInfo: ok with empty blockchain",
 		handler: handleGetBlockchainInfo,
@@ -4012,38 +4619,72 @@ func TestHandleGetBlock(t *testing.T) {
 		wantErr: true,
 		errCode: dcrjson.ErrRPCBlockNotFound,
 	}, {
-		name:    "handleGetBlock: could not fetch chain work",
+		name:    "handleGetBlock: block data pruned",
 		handler: handleGetBlock,
 		cmd: &types.GetBlockCmd{
 			Hash:      blkHashString,
-			Verbose:   dcrjson.Bool(true),
+			Verbose:   dcrjson.Bool(false),
 			VerboseTx: dcrjson.Bool(false),
 		},
 		mockChain: func() *testRPCChain {
 			chain := defaultMockRPCChain()
-			chain.chainWorkErr = errors.New("could not fetch chain work")
+			chain.blockByHashErr = errors.New("block not found")
+			chain.isPruned = true
+			chain.pruneHeight = 432101
 			return chain
 		}(),
 		wantErr: true,
-		errCode: dcrjson.ErrRPCInternal.Code,
+		errCode: dcrjson.ErrRPCMisc,
 	}, {
-		name:    "handleGetBlock: no next block",
+		name:    "handleGetBlock: pruned node block not found",
 		handler: handleGetBlock,
 		cmd: &types.GetBlockCmd{
 			Hash:      blkHashString,
-			Verbose:   dcrjson.Bool(true),
+			Verbose:   dcrjson.Bool(false),
 			VerboseTx: dcrjson.Bool(false),
 		},
 		mockChain: func() *testRPCChain {
 			chain := defaultMockRPCChain()
-			chain.bestSnapshot = &blockchain.BestState{
-				Height: bestHeight,
-			}
-			chain.blockHashByHeightErr = errors.New("no next block")
+			chain.blockByHashErr = errors.New("block not found")
+			chain.isPruned = true
+			chain.pruneHeight = 432100
 			return chain
 		}(),
 		wantErr: true,
-		errCode: dcrjson.ErrRPCInternal.Code,
+		errCode: dcrjson.ErrRPCBlockNotFound,
+	}, {
+		name:    "handleGetBlock: could not fetch chain work",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:      blkHashString,
+			Verbose:   dcrjson.Bool(true),
+			VerboseTx: dcrjson.Bool(false),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.chainWorkErr = errors.New("could not fetch chain work")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlock: no next block",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:      blkHashString,
+			Verbose:   dcrjson.Bool(true),
+			VerboseTx: dcrjson.Bool(false),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.bestSnapshot = &blockchain.BestState{
+				Height: bestHeight,
+			}
+			chain.blockHashByHeightErr = errors.New("no next block")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}, {
 		name:    "handleGetBlock: could not fetch median time",
 		handler: handleGetBlock,
@@ -4540,6 +5181,230 @@ func TestHandleGetInfo(t *testing.T) {
 	}})
 }
 
+// mockMempoolPackage returns verbose descriptors for a mocked mempool package
+// that consists of a parent transaction and a child transaction that spends
+// it along with the expected getmempoolentry results for each of them.
+func mockMempoolPackage() ([]*mempool.VerboseTxDesc, []*types.GetMempoolEntryResult) {
+	parent := &mempool.TxDesc{
+		TxDesc: mining.TxDesc{
+			Tx:     dcrutil.NewTx(block432100.Transactions[1]),
+			Type:   stake.TxTypeRegular,
+			Added:  time.Unix(1600000000, 0),
+			Height: 432099,
+			Fee:    300000,
+			TxSize: int64(block432100.Transactions[1].SerializeSize()),
+		},
+	}
+	child := &mempool.TxDesc{
+		TxDesc: mining.TxDesc{
+			Tx:     dcrutil.NewTx(block432100.STransactions[0]),
+			Type:   stake.TxTypeRegular,
+			Added:  time.Unix(1600000300, 0),
+			Height: 432100,
+			Fee:    100000,
+			TxSize: int64(block432100.STransactions[0].SerializeSize()),
+		},
+	}
+	parentStats := mempool.PackageStats{
+		Count: 1,
+		Size:  parent.TxSize,
+		Fees:  parent.Fee,
+	}
+	childStats := mempool.PackageStats{
+		Count: 1,
+		Size:  child.TxSize,
+		Fees:  child.Fee,
+	}
+	descs := []*mempool.VerboseTxDesc{{
+		TxDesc:         *parent,
+		Descendants:    childStats,
+		PackageFeeRate: float64(parent.Fee) * 1000 / float64(parent.TxSize),
+	}, {
+		TxDesc:    *child,
+		Depends:   []*mempool.TxDesc{parent},
+		Ancestors: parentStats,
+		PackageFeeRate: float64(parent.Fee+child.Fee) * 1000 /
+			float64(parent.TxSize+child.TxSize),
+	}}
+	results := []*types.GetMempoolEntryResult{{
+		Size:            int32(parent.TxSize),
+		Fee:             0.003,
+		Time:            1600000000,
+		Height:          432099,
+		DescendantCount: 1,
+		DescendantSize:  child.TxSize,
+		DescendantFees:  0.001,
+		PackageFeeRate:  dcrutil.Amount(descs[0].PackageFeeRate).ToCoin(),
+		Depends:         []string{},
+	}, {
+		Size:           int32(child.TxSize),
+		Fee:            0.001,
+		Time:           1600000300,
+		Height:         432100,
+		AncestorCount:  1,
+		AncestorSize:   parent.TxSize,
+		AncestorFees:   0.003,
+		PackageFeeRate: dcrutil.Amount(descs[1].PackageFeeRate).ToCoin(),
+		Depends:        []string{parent.Tx.Hash().String()},
+	}}
+	return descs, results
+}
+
+func TestHandleGetMempoolAncestors(t *testing.T) {
+	t.Parallel()
+
+	descs, results := mockMempoolPackage()
+	parentHash := descs[0].Tx.Hash().String()
+	childHash := descs[1].Tx.Hash().String()
+	verbose := true
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetMempoolAncestors: ok",
+		handler: handleGetMempoolAncestors,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.ancestorTxDescs = descs[:1]
+			return mp
+		}(),
+		cmd:    &types.GetMempoolAncestorsCmd{TxID: childHash},
+		result: []string{parentHash},
+	}, {
+		name:    "handleGetMempoolAncestors: ok verbose",
+		handler: handleGetMempoolAncestors,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.ancestorTxDescs = descs[:1]
+			return mp
+		}(),
+		cmd: &types.GetMempoolAncestorsCmd{
+			TxID:    childHash,
+			Verbose: &verbose,
+		},
+		result: map[string]*types.GetMempoolEntryResult{
+			parentHash: results[0],
+		},
+	}, {
+		name:    "handleGetMempoolAncestors: no ancestors",
+		handler: handleGetMempoolAncestors,
+		cmd:     &types.GetMempoolAncestorsCmd{TxID: parentHash},
+		result:  []string{},
+	}, {
+		name:    "handleGetMempoolAncestors: invalid hash",
+		handler: handleGetMempoolAncestors,
+		cmd:     &types.GetMempoolAncestorsCmd{TxID: "invalid"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetMempoolAncestors: not in pool",
+		handler: handleGetMempoolAncestors,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.txDescErr = errors.New("transaction is not in the pool")
+			return mp
+		}(),
+		cmd:     &types.GetMempoolAncestorsCmd{TxID: childHash},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCNoTxInfo,
+	}})
+}
+
+func TestHandleGetMempoolDescendants(t *testing.T) {
+	t.Parallel()
+
+	descs, results := mockMempoolPackage()
+	parentHash := descs[0].Tx.Hash().String()
+	childHash := descs[1].Tx.Hash().String()
+	verbose := true
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetMempoolDescendants: ok",
+		handler: handleGetMempoolDescendants,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.descendantTxDescs = descs[1:]
+			return mp
+		}(),
+		cmd:    &types.GetMempoolDescendantsCmd{TxID: parentHash},
+		result: []string{childHash},
+	}, {
+		name:    "handleGetMempoolDescendants: ok verbose",
+		handler: handleGetMempoolDescendants,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.descendantTxDescs = descs[1:]
+			return mp
+		}(),
+		cmd: &types.GetMempoolDescendantsCmd{
+			TxID:    parentHash,
+			Verbose: &verbose,
+		},
+		result: map[string]*types.GetMempoolEntryResult{
+			childHash: results[1],
+		},
+	}, {
+		name:    "handleGetMempoolDescendants: invalid hash",
+		handler: handleGetMempoolDescendants,
+		cmd:     &types.GetMempoolDescendantsCmd{TxID: "invalid"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetMempoolDescendants: not in pool",
+		handler: handleGetMempoolDescendants,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.txDescErr = errors.New("transaction is not in the pool")
+			return mp
+		}(),
+		cmd:     &types.GetMempoolDescendantsCmd{TxID: parentHash},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCNoTxInfo,
+	}})
+}
+
+func TestHandleGetMempoolEntry(t *testing.T) {
+	t.Parallel()
+
+	descs, results := mockMempoolPackage()
+	parentHash := descs[0].Tx.Hash().String()
+	childHash := descs[1].Tx.Hash().String()
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetMempoolEntry: ok parent",
+		handler: handleGetMempoolEntry,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.verboseTxDesc = descs[0]
+			return mp
+		}(),
+		cmd:    &types.GetMempoolEntryCmd{TxID: parentHash},
+		result: results[0],
+	}, {
+		name:    "handleGetMempoolEntry: ok child",
+		handler: handleGetMempoolEntry,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.verboseTxDesc = descs[1]
+			return mp
+		}(),
+		cmd:    &types.GetMempoolEntryCmd{TxID: childHash},
+		result: results[1],
+	}, {
+		name:    "handleGetMempoolEntry: invalid hash",
+		handler: handleGetMempoolEntry,
+		cmd:     &types.GetMempoolEntryCmd{TxID: "invalid"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetMempoolEntry: not in pool",
+		handler: handleGetMempoolEntry,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.txDescErr = errors.New("transaction is not in the pool")
+			return mp
+		}(),
+		cmd:     &types.GetMempoolEntryCmd{TxID: childHash},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCNoTxInfo,
+	}})
+}
+
 func TestHandleGetMempoolInfo(t *testing.T) {
 	t.Parallel()
 
@@ -4568,8 +5433,28 @@ func TestHandleGetMempoolInfo(t *testing.T) {
 		}(),
 		cmd: &types.GetMempoolInfoCmd{},
 		result: &types.GetMempoolInfoResult{
-			Size:  2,
-			Bytes: 627,
+			Size:          2,
+			Bytes:         627,
+			MaxMempool:    300 * 1024 * 1024,
+			MempoolMinFee: 0.0001,
+			MinRelayTxFee: 0.0001,
+		},
+	}, {
+		name:    "handleGetMempoolInfo: ok with raised min fee",
+		handler: handleGetMempoolInfo,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.txDescs = []*mempool.TxDesc{txDescOne, txDescTwo}
+			mp.minRelayTxFee = dcrutil.Amount(25000)
+			return mp
+		}(),
+		cmd: &types.GetMempoolInfoCmd{},
+		result: &types.GetMempoolInfoResult{
+			Size:          2,
+			Bytes:         627,
+			MaxMempool:    300 * 1024 * 1024,
+			MempoolMinFee: 0.00025,
+			MinRelayTxFee: 0.0001,
 		},
 	}})
 }
@@ -4762,6 +5647,7 @@ func TestHandleGetPeerInfo(t *testing.T) {
 					},
 					isTxRelayDisabled: false,
 					banScore:          uint32(0),
+					mappedAS:          uint32(37963),
 					id:                int32(5),
 					addr:              "106.14.238.184:19108",
 					lastPingNonce:     uint64(10),
@@ -4783,6 +5669,7 @@ func TestHandleGetPeerInfo(t *testing.T) {
 						LastPingNonce:  uint64(10),
 						LastPingTime:   time.Unix(1592918788, 0),
 						LastPingMicros: int64(0),
+						Transport:      peer.TransportEncrypted,
 					},
 				},
 			}
@@ -4812,10 +5699,123 @@ func TestHandleGetPeerInfo(t *testing.T) {
 			CurrentHeight:  int64(323327),
 			BanScore:       int32(0),
 			SyncNode:       false,
+			Transport:      "encrypted",
+			MappedAS:       uint32(37963),
 		}},
 	}})
 }
 
+func TestHandleGetSpendingInfo(t *testing.T) {
+	t.Parallel()
+
+	txid := "c720b8991e3345e13858607cdbbaf8fc535a15cd36f22d42623dba56586c94d5"
+	spenderHash := block432100.Transactions[1].TxHash()
+	blkHash := block432100.BlockHash()
+	entry := &indexers.SpendIndexEntry{
+		SpenderHash: spenderHash,
+		InputIndex:  2,
+		BlockHash:   blkHash,
+		BlockHeight: int64(block432100.Header.Height),
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetSpendingInfo: spend index not enabled",
+		handler: handleGetSpendingInfo,
+		cmd: &types.GetSpendingInfoCmd{
+			Txid: txid,
+			Vout: 1,
+		},
+		setSpendIndexerNil: true,
+		wantErr:            true,
+		errCode:            dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpendingInfo: invalid txid",
+		handler: handleGetSpendingInfo,
+		cmd: &types.GetSpendingInfoCmd{
+			Txid: "invalid",
+			Vout: 1,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetSpendingInfo: unable to fetch index tip",
+		handler: handleGetSpendingInfo,
+		cmd: &types.GetSpendingInfoCmd{
+			Txid: txid,
+			Vout: 1,
+		},
+		mockSpendIndexer: func() *testSpendIndexer {
+			idx := defaultMockSpendIndexer()
+			idx.tipErr = errors.New("unable to fetch index tip")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpendingInfo: index not synced",
+		handler: handleGetSpendingInfo,
+		cmd: &types.GetSpendingInfoCmd{
+			Txid: txid,
+			Vout: 1,
+		},
+		mockSpendIndexer: func() *testSpendIndexer {
+			idx := defaultMockSpendIndexer()
+			idx.tipHeight = 0
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpendingInfo: unable to fetch entry",
+		handler: handleGetSpendingInfo,
+		cmd: &types.GetSpendingInfoCmd{
+			Txid: txid,
+			Vout: 1,
+		},
+		mockSpendIndexer: func() *testSpendIndexer {
+			idx := defaultMockSpendIndexer()
+			idx.entryErr = errors.New("unable to fetch entry")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpendingInfo: output not spent",
+		handler: handleGetSpendingInfo,
+		cmd: &types.GetSpendingInfoCmd{
+			Txid: txid,
+			Vout: 1,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCNoTxInfo,
+	}, {
+		name:    "handleGetSpendingInfo: ok",
+		handler: handleGetSpendingInfo,
+		cmd: &types.GetSpendingInfoCmd{
+			Txid: txid,
+			Vout: 1,
+		},
+		mockSpendIndexer: func() *testSpendIndexer {
+			idx := defaultMockSpendIndexer()
+			idx.entry = entry
+			return idx
+		}(),
+		mockChain: func() *testRPCChain {
+			// The block hash must come from the index entry rather than the
+			// main chain at the recorded height since they can differ when the
+			// index has not caught up to a reorg.
+			chain := defaultMockRPCChain()
+			chain.blockHashByHeight = &zeroHash
+			return chain
+		}(),
+		result: &types.GetSpendingInfoResult{
+			SpendingTxid: spenderHash.String(),
+			Vin:          2,
+			BlockHash:    blkHash.String(),
+			BlockHeight:  int64(block432100.Header.Height),
+		},
+	}})
+}
+
 func TestHandleGetStakeVersionInfo(t *testing.T) {
 	t.Parallel()
 
@@ -5358,6 +6358,49 @@ func TestHandleInvalidateBlock(t *testing.T) {
 	}})
 }
 
+func TestHandleListBanned(t *testing.T) {
+	t.Parallel()
+
+	_, subnet, _ := net.ParseCIDR("10.0.0.0/24")
+	host := banmanager.HostSubnet(net.ParseIP("2001:db8::1"))
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleListBanned: ok",
+		handler: handleListBanned,
+		cmd:     &types.ListBannedCmd{},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.bannedSubnets = []banmanager.BanEntry{{
+				Subnet:  *subnet,
+				Created: time.Unix(1700000000, 0),
+				Expires: time.Unix(1700086400, 0),
+				Reason:  "manually banned",
+			}, {
+				Subnet:  *host,
+				Created: time.Unix(1700000100, 0),
+				Expires: time.Unix(1700086500, 0),
+				Reason:  "invalid block",
+			}}
+			return connManager
+		}(),
+		result: []types.ListBannedResult{{
+			Address:     "10.0.0.0/24",
+			BanCreated:  1700000000,
+			BannedUntil: 1700086400,
+			BanReason:   "manually banned",
+		}, {
+			Address:     "2001:db8::1/128",
+			BanCreated:  1700000100,
+			BannedUntil: 1700086500,
+			BanReason:   "invalid block",
+		}},
+	}, {
+		name:    "handleListBanned: no bans",
+		handler: handleListBanned,
+		cmd:     &types.ListBannedCmd{},
+		result:  []types.ListBannedResult{},
+	}})
+}
+
 func TestHandleLiveTickets(t *testing.T) {
 	t.Parallel()
 
@@ -6099,6 +7142,110 @@ func TestHandleGetWork(t *testing.T) {
 	}})
 }
 
+func TestHandleSetBan(t *testing.T) {
+	t.Parallel()
+
+	banTime := int64(3600)
+	negativeBanTime := int64(-1)
+	pastBanTime := int64(1)
+	absolute := true
+	reason := "spam"
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleSetBan: ok add address",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: types.SBAdd,
+		},
+		result: nil,
+	}, {
+		name:    "handleSetBan: ok add subnet with ban time and reason",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet:  "10.0.0.0/24",
+			SubCmd:  types.SBAdd,
+			BanTime: &banTime,
+			Reason:  &reason,
+		},
+		result: nil,
+	}, {
+		name:    "handleSetBan: invalid subnet",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.256",
+			SubCmd: types.SBAdd,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetBan: negative ban time",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet:  "10.0.0.1",
+			SubCmd:  types.SBAdd,
+			BanTime: &negativeBanTime,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetBan: absolute ban time in the past",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet:   "10.0.0.1",
+			SubCmd:   types.SBAdd,
+			BanTime:  &pastBanTime,
+			Absolute: &absolute,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetBan: already banned",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: types.SBAdd,
+		},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.banSubnetErr = banmanager.ErrAlreadyBanned
+			return connManager
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleSetBan: ok remove",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.0/24",
+			SubCmd: types.SBRemove,
+		},
+		result: nil,
+	}, {
+		name:    "handleSetBan: remove not banned",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.0/24",
+			SubCmd: types.SBRemove,
+		},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.unbanSubnetErr = banmanager.ErrNotBanned
+			return connManager
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleSetBan: invalid subcommand",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
 func TestHandleSetGenerate(t *testing.T) {
 	t.Parallel()
 
@@ -6217,6 +7364,31 @@ func TestHandleRegenTemplate(t *testing.T) {
 	}})
 }
 
+func TestHandleSaveMempool(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleSaveMempool: ok",
+		handler: handleSaveMempool,
+		cmd:     &types.SaveMempoolCmd{},
+		result: &types.SaveMempoolResult{
+			Filename: "/home/user/.dcrd/data/mainnet/mempool.dat",
+			Size:     5,
+		},
+	}, {
+		name:    "handleSaveMempool: unable to save mempool",
+		handler: handleSaveMempool,
+		cmd:     &types.SaveMempoolCmd{},
+		mockMempoolSaver: func() *testMempoolSaver {
+			m := defaultMockMempoolSaver()
+			m.saveMempoolErr = errors.New("the mempool has not finished loading")
+			return m
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleTSpendVotes(t *testing.T) {
 	t.Parallel()
 
@@ -6698,6 +7870,147 @@ func TestHandleTxFeeInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleTestMempoolAccept(t *testing.T) {
+	t.Parallel()
+
+	txOne := block432100.Transactions[1]
+	txTwo := block432100.STransactions[0]
+	txOneBytes, err := txOne.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected tx serialization error: %v", err)
+	}
+	txTwoBytes, err := txTwo.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected tx serialization error: %v", err)
+	}
+	hexTxns := []string{hex.EncodeToString(txOneBytes),
+		hex.EncodeToString(txTwoBytes)}
+	tooManyTxns := make([]string, maxTestMempoolAcceptTxns+1)
+	for i := range tooManyTxns {
+		tooManyTxns[i] = hexTxns[0]
+	}
+	noHighFees := false
+	fee := 0.0001
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleTestMempoolAccept: no transactions",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			AllowHighFees: &noHighFees,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleTestMempoolAccept: too many transactions",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			RawTxns:       tooManyTxns,
+			AllowHighFees: &noHighFees,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleTestMempoolAccept: invalid tx hex",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			RawTxns:       []string{hexTxns[0], "invalid"},
+			AllowHighFees: &noHighFees,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleTestMempoolAccept: invalid tx",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			RawTxns:       []string{"fefefefefefe"},
+			AllowHighFees: &noHighFees,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDeserialization,
+	}, {
+		name:    "handleTestMempoolAccept: unable to test transactions",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			RawTxns:       hexTxns,
+			AllowHighFees: &noHighFees,
+		},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.testAcceptErr = errors.New("unable to test transactions")
+			return mp
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleTestMempoolAccept: unexpected transaction error",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			RawTxns:       hexTxns,
+			AllowHighFees: &noHighFees,
+		},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.testAcceptErrs = []error{nil, errors.New("db failure")}
+			return mp
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleTestMempoolAccept: ok",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			RawTxns:       hexTxns,
+			AllowHighFees: &noHighFees,
+		},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.testAcceptFees = []int64{10000}
+			mp.testAcceptErrs = []error{nil, mempool.RuleError{
+				Err:         mempool.ErrInsufficientFee,
+				Description: "insufficient fee",
+			}}
+			return mp
+		}(),
+		result: []types.TestMempoolAcceptResult{{
+			TxID:    txOne.TxHash().String(),
+			Allowed: true,
+			Fee:     &fee,
+			Size:    int64(txOne.SerializeSize()),
+		}, {
+			TxID:          txTwo.TxHash().String(),
+			Allowed:       false,
+			RejectReason:  "ErrInsufficientFee",
+			RejectMessage: "insufficient fee",
+			Size:          int64(txTwo.SerializeSize()),
+		}},
+	}, {
+		name:    "handleTestMempoolAccept: ok with consensus rule violation",
+		handler: handleTestMempoolAccept,
+		cmd: &types.TestMempoolAcceptCmd{
+			RawTxns:       hexTxns[:1],
+			AllowHighFees: &noHighFees,
+		},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.testAcceptErrs = []error{mempool.RuleError{
+				Err: blockchain.RuleError{
+					Err:         blockchain.ErrMissingTxOut,
+					Description: "missing output",
+				},
+				Description: "missing output",
+			}}
+			return mp
+		}(),
+		result: []types.TestMempoolAcceptResult{{
+			TxID:          txOne.TxHash().String(),
+			Allowed:       false,
+			RejectReason:  "ErrMissingTxOut",
+			RejectMessage: "missing output",
+			Size:          int64(txOne.SerializeSize()),
+		}},
+	}})
+}
+
 func TestHandleTicketFeeInfo(t *testing.T) {
 	t.Parallel()
 
@@ -7917,6 +9230,284 @@ func TestHandleGetRawTransaction(t *testing.T) {
 	}})
 }
 
+func TestHandleSearchRawTransactions(t *testing.T) {
+	t.Parallel()
+
+	nonVerbose := 0
+	verbose := 1
+	negative := -1
+	one := 1
+	huge := math.MaxUint32
+	reverse := true
+	noMempool := false
+	addr := "Dsi8CRt85xYyempXs7ZPL1rBxvDdAGZmgsg"
+
+	// Load a transaction that pays to the address above to serve as the
+	// confirmed transaction and use the coinbase of a test block for the
+	// unconfirmed one.
+	dbTxHex := hexFromFile("tx432098-11.hex")
+	dbEntry := indexers.TxIndexEntry{
+		BlockRegion: database.BlockRegion{
+			Hash: mustParseHash("00000000000000001fc4c4c7a3f2ec6d552dda16a3a928f27bd6" +
+				"bd16d8f1e9b3"),
+			Offset: 52508,
+			Len:    453,
+		},
+		BlockIndex: 11,
+	}
+	mpTx := dcrutil.NewTx(block432100.Transactions[0])
+	mpTxBytes, err := mpTx.MsgTx().Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize tx: %v", err)
+	}
+	mpTxHex := hex.EncodeToString(mpTxBytes)
+
+	addrIndex := func(unconfirmed ...*dcrutil.Tx) *testAddrIndexer {
+		idx := defaultMockAddrIndexer()
+		idx.entries = func(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error) {
+			if numToSkip > 0 {
+				return nil, 1, nil
+			}
+			return []indexers.TxIndexEntry{dbEntry}, 0, nil
+		}
+		idx.unconfirmed = unconfirmed
+		return idx
+	}
+	db := func() *testDB {
+		db := defaultMockDB()
+		db.viewTx = &testDatabaseTx{
+			fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+				return [][]byte{hexToBytes(dbTxHex)}, nil
+			},
+		}
+		return db
+	}
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleSearchRawTransactions: addr index not enabled",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+		},
+		setAddrIndexerNil: true,
+		wantErr:           true,
+		errCode:           dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: invalid address",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: "invalid",
+			Verbose: &nonVerbose,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleSearchRawTransactions: negative count",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+			Count:   &negative,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSearchRawTransactions: negative skip",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+			Skip:    &negative,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSearchRawTransactions: unable to fetch index tip",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.tipErr = errors.New("unable to fetch index tip")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: index not synced",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.tipHeight = 0
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: unable to fetch index entries",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.entries = func(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error) {
+				return nil, 0, errors.New("unable to fetch entries")
+			}
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: unable to fetch block regions",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+		},
+		mockAddrIndexer: addrIndex(),
+		mockDB: func() *testDB {
+			db := defaultMockDB()
+			db.viewTx = &testDatabaseTx{
+				fetchBlockRegions: func(regions []database.BlockRegion) ([][]byte, error) {
+					return nil, errors.New("unable to fetch block regions")
+				},
+			}
+			return db
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: ok, no results",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+		},
+		result: []string{},
+	}, {
+		name:    "handleSearchRawTransactions: ok, confirmed then unconfirmed",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+		},
+		mockAddrIndexer: addrIndex(mpTx),
+		mockDB:          db(),
+		result:          []string{dbTxHex, mpTxHex},
+	}, {
+		name:    "handleSearchRawTransactions: ok, reverse",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+			Reverse: &reverse,
+		},
+		mockAddrIndexer: addrIndex(mpTx),
+		mockDB:          db(),
+		result:          []string{mpTxHex, dbTxHex},
+	}, {
+		name:    "handleSearchRawTransactions: ok, count limits results",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+			Count:   &one,
+			Reverse: &reverse,
+		},
+		mockAddrIndexer: addrIndex(mpTx),
+		mockDB:          db(),
+		result:          []string{mpTxHex},
+	}, {
+		name:    "handleSearchRawTransactions: ok, huge count is limited",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+			Skip:    &one,
+			Count:   &huge,
+			Reverse: &reverse,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := addrIndex(mpTx)
+			idx.entries = func(addr stdaddr.Address, numToSkip, numRequested uint32, reverse bool) ([]indexers.TxIndexEntry, uint32, error) {
+				if numRequested > maxSearchRawTxnsCount {
+					return nil, 0, fmt.Errorf("requested %d entries",
+						numRequested)
+				}
+				return []indexers.TxIndexEntry{dbEntry}, 0, nil
+			}
+			return idx
+		}(),
+		mockDB: db(),
+		result: []string{dbTxHex},
+	}, {
+		name:    "handleSearchRawTransactions: ok, skip confirmed",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &nonVerbose,
+			Skip:    &one,
+		},
+		mockAddrIndexer: addrIndex(mpTx),
+		mockDB:          db(),
+		result:          []string{mpTxHex},
+	}, {
+		name:    "handleSearchRawTransactions: ok, exclude mempool",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address:        addr,
+			Verbose:        &nonVerbose,
+			IncludeMempool: &noMempool,
+		},
+		mockAddrIndexer: addrIndex(mpTx),
+		mockDB:          db(),
+		result:          []string{dbTxHex},
+	}, {
+		name:    "handleSearchRawTransactions: verbose, unable to fetch header",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &verbose,
+		},
+		mockAddrIndexer: addrIndex(),
+		mockDB:          db(),
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.headerByHashErr = errors.New("unable to fetch header by hash")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: verbose, unable to fetch treasury status",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: addr,
+			Verbose: &verbose,
+		},
+		mockAddrIndexer: addrIndex(mpTx),
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.treasuryActive = false
+			chain.treasuryActiveErr =
+				errors.New("unable to fetch treasury agenda status")
+			return chain
+		}(),
+		mockDB:  db(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleVersion(t *testing.T) {
 	t.Parallel()
 
@@ -8205,6 +9796,18 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 			if test.setTxIndexerNil {
 				rpcserverConfig.TxIndexer = nil
 			}
+			if test.mockAddrIndexer != nil {
+				rpcserverConfig.AddrIndexer = test.mockAddrIndexer
+			}
+			if test.setAddrIndexerNil {
+				rpcserverConfig.AddrIndexer = nil
+			}
+			if test.mockSpendIndexer != nil {
+				rpcserverConfig.SpendIndexer = test.mockSpendIndexer
+			}
+			if test.setSpendIndexerNil {
+				rpcserverConfig.SpendIndexer = nil
+			}
 			if test.mockDB != nil {
 				rpcserverConfig.DB = test.mockDB
 			}
@@ -8220,6 +9823,12 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 			if test.mockLogManager != nil {
 				rpcserverConfig.LogManager = test.mockLogManager
 			}
+			if test.mockMempoolSaver != nil {
+				rpcserverConfig.MempoolSaver = test.mockMempoolSaver
+			}
+			if test.mockUtxoSnapshotter != nil {
+				rpcserverConfig.UtxoSnapshotter = test.mockUtxoSnapshotter
+			}
 			if test.mockSanityChecker != nil {
 				rpcserverConfig.SanityChecker = test.mockSanityChecker
 			}
@@ -1,5 +1,5 @@
 // Copyright (c) 2015 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -41,6 +41,26 @@ var helpDescsEnUS = map[string]string{
 	"node-target":        "Either the IP address and port of the peer to operate on, or a valid peer ID.",
 	"node-connectsubcmd": "'perm' to make the connected peer a permanent one, 'temp' to try a single connect to a peer",
 
+	// SetBanCmd help.
+	"setban--synopsis": "Attempts to add or remove a ban for an IP address or subnet.  Peers within a newly banned subnet are disconnected and bans persist across restarts.",
+	"setban-subnet":    "IP address or subnet in CIDR notation (e.g. 192.168.0.1 or 192.168.0.0/24) to operate on",
+	"setban-subcmd":    "'add' to ban the subnet or 'remove' to remove an existing ban",
+	"setban-bantime":   "Number of seconds to ban the subnet for, or a unix timestamp when absolute is true (0 uses the default ban duration)",
+	"setban-absolute":  "Whether or not bantime is an absolute unix timestamp",
+	"setban-reason":    "Reason for the ban (default: 'manually banned')",
+
+	// ListBannedCmd help.
+	"listbanned--synopsis": "Returns all banned IP addresses and subnets.",
+
+	// ListBannedResult help.
+	"listbannedresult-address":     "The banned IP address or subnet in CIDR notation",
+	"listbannedresult-bancreated":  "The time the ban was created in seconds since 1 Jan 1970 GMT",
+	"listbannedresult-banneduntil": "The time the ban expires in seconds since 1 Jan 1970 GMT",
+	"listbannedresult-banreason":   "The reason for the ban",
+
+	// ClearBannedCmd help.
+	"clearbanned--synopsis": "Removes all bans.",
+
 	// TransactionInput help.
 	"transactioninput-amount": "The previous output amount in coins",
 	"transactioninput-txid":   "The hash of the input transaction",
@@ -164,6 +184,36 @@ var helpDescsEnUS = map[string]string{
 	"decodescript-hexscript": "Hex-encoded script",
 	"decodescript-version":   "The script version, defaults to version 0 if not set.",
 
+	// DumpFeeBucketsCmd help.
+	"dumpfeebuckets--synopsis": "Returns the internal state of the fee estimator.\n" +
+		"The statistics are decay-adjusted, so older transactions contribute less to them than recent ones.",
+
+	// DumpFeeBucketsResult help.
+	"dumpfeebucketsresult-bestheight":  "The height of the most recent block processed by the fee estimator or -1 when it is not enabled yet",
+	"dumpfeebucketsresult-maxconfirms": "The number of tracked confirmation ranges",
+	"dumpfeebucketsresult-decay":       "The exponential decay applied to the statistics for every new block",
+	"dumpfeebucketsresult-buckets":     "The statistics of the fee rate buckets ordered from the lowest to the highest fee rate",
+
+	// FeeBucketResult help.
+	"feebucketresult-startrange":     "The lower bound of the fee rates covered by the bucket (in DCR/KB)",
+	"feebucketresult-endrange":       "The upper bound of the fee rates covered by the bucket (in DCR/KB) or -1 when it is unbounded",
+	"feebucketresult-totalconfirmed": "The number of transactions in the bucket that were mined",
+	"feebucketresult-avgfeerate":     "The average fee rate of the transactions in the bucket that were mined (in DCR/KB)",
+	"feebucketresult-confirmed":      "The number of transactions in the bucket that were mined within each number of blocks starting from 1, where the final entry also includes all transactions that took longer",
+	"feebucketresult-inmempool":      "The number of transactions in the bucket that are in the mempool by the number of blocks they have been in it for starting from 0, where the final entry also includes all transactions that have been in it for longer",
+
+	// DumpUtxoSetCmd help.
+	"dumputxoset--synopsis": "Writes a snapshot of the chain state as of the current best block to a file.\n" +
+		"The snapshot contains the UTXO set, the ticket database, and the treasury state along with the block index and the recent block data needed to resume from it.\n" +
+		"It may be used to start a new node with --loadsnapshot when its snapshot hash is one of the snapshots defined by the network parameters.",
+	"dumputxoset-path": "The path to write the snapshot to, relative to the data directory when not absolute (the file must not already exist)",
+
+	// DumpUtxoSetResult help.
+	"dumputxosetresult-hash":         "The hash of the block the snapshot was created at",
+	"dumputxosetresult-height":       "The height of the block the snapshot was created at",
+	"dumputxosetresult-snapshothash": "The hash of the serialized snapshot",
+	"dumputxosetresult-path":         "The full path to the file the snapshot was written to",
+
 	// ExistsAddressCmd help.
 	"existsaddress--synopsis": "Test for the existence of the provided address",
 	"existsaddress-address":   "The address to check",
@@ -251,6 +301,9 @@ var helpDescsEnUS = map[string]string{
 	"getblockchaininforesult-chainwork":            "Hex encoded total work done for the chain.",
 	"getblockchaininforesult-initialblockdownload": "Best guess of whether this node is in the initial chain sync mode used to catch up the chain when it is far behind",
 	"getblockchaininforesult-maxblocksize":         "The maximum allowed block size.",
+	"getblockchaininforesult-pruned":               "Whether or not the node is operating in pruned mode.",
+	"getblockchaininforesult-pruneheight":          "The height of the oldest main chain block for which block data is available (only present when pruned).",
+	"getblockchaininforesult-prunetargetsize":      "The target size in bytes for stored block data (only present when pruning is enabled).",
 	"getblockchaininforesult-deployments":          "Network consensus deployments.",
 	"getblockchaininforesult-deployments--desc":    "Consensus deployment agendas.",
 	"getblockchaininforesult-deployments--key":     "The consensus deployment agenda id.",
@@ -489,12 +542,49 @@ var helpDescsEnUS = map[string]string{
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
+	// GetMempoolAncestorsCmd help.
+	"getmempoolancestors--synopsis":   "Returns all in-mempool ancestors of a transaction in the memory pool.",
+	"getmempoolancestors-txid":        "The hash of the transaction",
+	"getmempoolancestors-verbose":     "Returns JSON object when true or an array of transaction hashes when false",
+	"getmempoolancestors--condition0": "verbose=false",
+	"getmempoolancestors--condition1": "verbose=true",
+	"getmempoolancestors--result0":    "Array of transaction hashes ordered such that each transaction follows the transactions it depends on",
+
+	// GetMempoolDescendantsCmd help.
+	"getmempooldescendants--synopsis":   "Returns all in-mempool descendants of a transaction in the memory pool.",
+	"getmempooldescendants-txid":        "The hash of the transaction",
+	"getmempooldescendants-verbose":     "Returns JSON object when true or an array of transaction hashes when false",
+	"getmempooldescendants--condition0": "verbose=false",
+	"getmempooldescendants--condition1": "verbose=true",
+	"getmempooldescendants--result0":    "Array of transaction hashes ordered such that each transaction precedes the transactions that depend on it",
+
+	// GetMempoolEntryCmd help.
+	"getmempoolentry--synopsis": "Returns information about a transaction in the memory pool, including its in-mempool ancestors and descendants.",
+	"getmempoolentry-txid":      "The hash of the transaction",
+
+	// GetMempoolEntryResult help.
+	"getmempoolentryresult-size":            "Transaction size in bytes",
+	"getmempoolentryresult-fee":             "Transaction fee in decred",
+	"getmempoolentryresult-time":            "Local time transaction entered pool in seconds since 1 Jan 1970 GMT",
+	"getmempoolentryresult-height":          "Block height when transaction entered the pool",
+	"getmempoolentryresult-ancestorcount":   "Number of in-mempool ancestor transactions, not including this one",
+	"getmempoolentryresult-ancestorsize":    "Total size in bytes of all in-mempool ancestors, not including this one",
+	"getmempoolentryresult-ancestorfees":    "Total fees in decred of all in-mempool ancestors, not including this one",
+	"getmempoolentryresult-descendantcount": "Number of in-mempool descendant transactions, not including this one",
+	"getmempoolentryresult-descendantsize":  "Total size in bytes of all in-mempool descendants, not including this one",
+	"getmempoolentryresult-descendantfees":  "Total fees in decred of all in-mempool descendants, not including this one",
+	"getmempoolentryresult-packagefeerate":  "Fee rate in DCR/kB of the transaction together with its ancestors that is used to prioritize it when generating block templates",
+	"getmempoolentryresult-depends":         "Unconfirmed transactions used as inputs for this transaction",
+
 	// GetMempoolInfoCmd help.
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
 	// GetMempoolInfoResult help.
-	"getmempoolinforesult-bytes": "Size in bytes of the mempool",
-	"getmempoolinforesult-size":  "Number of transactions in the mempool",
+	"getmempoolinforesult-bytes":         "Size in bytes of the mempool",
+	"getmempoolinforesult-size":          "Number of transactions in the mempool",
+	"getmempoolinforesult-maxmempool":    "Maximum size in bytes of the mempool",
+	"getmempoolinforesult-mempoolminfee": "Minimum fee rate in DCR/kB for regular transactions to be accepted into the mempool, which is raised above the minimum relay fee when transactions are evicted because the mempool is full",
+	"getmempoolinforesult-minrelaytxfee": "Configured minimum relay fee rate in DCR/kB for transactions",
 
 	// GetMiningInfoResult help.
 	"getmininginforesult-blocks":           "Height of the latest best block",
@@ -587,6 +677,8 @@ var helpDescsEnUS = map[string]string{
 	"getpeerinforesult-currentheight":  "The current height of the peer",
 	"getpeerinforesult-banscore":       "The ban score",
 	"getpeerinforesult-syncnode":       "Whether or not the peer is the sync peer",
+	"getpeerinforesult-transport":      "The transport used by the connection (plaintext or encrypted)",
+	"getpeerinforesult-mappedas":       "The autonomous system number the peer address maps to (only present when an IP to ASN map is in use and the address maps to one)",
 
 	// GetPeerInfoCmd help.
 	"getpeerinfo--synopsis": "Returns data about each connected network peer as an array of json objects.",
@@ -600,6 +692,28 @@ var helpDescsEnUS = map[string]string{
 	"getrawmempoolverboseresult-currentpriority":  "(DEPRECATED) This field is always 0 and will be removed in a future version of the software",
 	"getrawmempoolverboseresult-depends":          "Unconfirmed transactions used as inputs for this transaction",
 
+	// GetRateLimitInfoCmd help.
+	"getratelimitinfo--synopsis": "Returns the configuration of the RPC rate limiter along with the token bucket state of all tracked clients.\n" +
+		"Each authenticated user and each remote IP address outside of the admin permission group is limited independently.\n" +
+		"Requests consume tokens according to their weight and are rejected when any of the buckets they are charged to does not have enough tokens.",
+
+	// GetRateLimitInfoResult help.
+	"getratelimitinforesult-enabled":        "Whether or not rate limiting is enabled",
+	"getratelimitinforesult-rate":           "The number of tokens added to the bucket of each client per second",
+	"getratelimitinforesult-burst":          "The max number of tokens the bucket of each client may hold",
+	"getratelimitinforesult-weights":        "The weights of the methods that consume more than one token",
+	"getratelimitinforesult-weights--desc":  "The weight of the methods that consume more than one token keyed by method name (getblock and getrawtransaction are weighted by their verbosity)",
+	"getratelimitinforesult-weights--key":   "method",
+	"getratelimitinforesult-weights--value": "n.nnn",
+	"getratelimitinforesult-clients":        "The token buckets of all tracked clients (clients with full buckets may be discarded)",
+
+	// RateLimitClientResult help.
+	"ratelimitclientresult-type":     "The kind of client the bucket is for (user or ip)",
+	"ratelimitclientresult-name":     "The name of the user or the IP address of the client",
+	"ratelimitclientresult-tokens":   "The number of tokens currently available",
+	"ratelimitclientresult-requests": "The number of requests that were permitted",
+	"ratelimitclientresult-limited":  "The number of requests that were rejected for exceeding the rate limit",
+
 	// GetRawMempoolCmd help.
 	"getrawmempool--synopsis":   "Returns information about all of the transactions currently in the memory pool.",
 	"getrawmempool-verbose":     "Returns JSON object when true or an array of transaction hashes when false",
@@ -616,6 +730,18 @@ var helpDescsEnUS = map[string]string{
 	"getrawtransaction--condition1": "verbose=true",
 	"getrawtransaction--result0":    "Hex-encoded bytes of the serialized transaction",
 
+	// GetSpendingInfoCmd help.
+	"getspendinginfo--synopsis": "Returns information about the transaction that spent an output in the main chain.\n" +
+		"Usage of this RPC requires the optional --spendindex flag to be activated, otherwise all responses will simply return with an error stating the spend index has not yet been built.",
+	"getspendinginfo-txid": "The hash of the transaction that created the output",
+	"getspendinginfo-vout": "The index of the output",
+
+	// GetSpendingInfoResult help.
+	"getspendinginforesult-spendingtxid": "The hash of the transaction that spent the output",
+	"getspendinginforesult-vin":          "The index of the input within the spending transaction that spent the output",
+	"getspendinginforesult-blockhash":    "The hash of the block that contains the spending transaction",
+	"getspendinginforesult-blockheight":  "The height of the block that contains the spending transaction",
+
 	// GetTicketPoolValue help.
 	"getticketpoolvalue--synopsis": "Return the current value of all locked funds in the ticket pool",
 	"getticketpoolvalue--result0":  "Total value of ticket pool",
@@ -717,6 +843,30 @@ var helpDescsEnUS = map[string]string{
 		"Any descendants that are neither themselves marked as having failed validation, nor descendants of another such block, are also made eligibile for best chain selection.",
 	"reconsiderblock-blockhash": "The hash of the block to reconsider",
 
+	// SaveMempoolCmd help.
+	"savemempool--synopsis": "Writes all transactions in the memory pool to the mempool dump file in the data directory.\n" +
+		"The file is used to restore the memory pool when the node is restarted unless mempool persistence is disabled.",
+
+	// SaveMempoolResult help.
+	"savemempoolresult-filename": "The path to the file the memory pool was written to",
+	"savemempoolresult-size":     "The number of transactions written",
+
+	// SearchRawTransactionsCmd help.
+	"searchrawtransactions--synopsis": "Returns raw data for transactions involving the passed address.\n" +
+		"Returned transactions are pulled from both the database, and transactions currently in the mempool.\n" +
+		"Transactions pulled from the mempool will have the 'confirmations' field set to 0.\n" +
+		"Usage of this RPC requires the optional --addrindex flag to be activated, otherwise all responses will simply return with an error stating the address index has not yet been built.\n" +
+		"Similarly, until the address index has caught up with the current best height, all requests will return an error response in order to avoid serving stale data.",
+	"searchrawtransactions-address":        "The Decred address to search for",
+	"searchrawtransactions-verbose":        "Specifies the transaction is returned as a JSON object instead of hex-encoded string",
+	"searchrawtransactions-skip":           "The number of leading transactions to leave out of the final response",
+	"searchrawtransactions-count":          "The maximum number of transactions to return (limited to 10000)",
+	"searchrawtransactions-reverse":        "Specifies that the transactions should be returned in reverse chronological order",
+	"searchrawtransactions-includemempool": "Specifies whether or not transactions in the memory pool are included in the results",
+	"searchrawtransactions--condition0":    "verbose=0",
+	"searchrawtransactions--condition1":    "verbose=1",
+	"searchrawtransactions--result0":       "Hex-encoded serialized transactions",
+
 	// SendRawTransactionCmd help.
 	"sendrawtransaction--synopsis":     "Submits the serialized, hex-encoded transaction to the local peer and relays it to the network.",
 	"sendrawtransaction-hextx":         "Serialized, hex-encoded signed transaction",
@@ -753,6 +903,21 @@ var helpDescsEnUS = map[string]string{
 	"submitblock--condition1": "Block rejected",
 	"submitblock--result1":    "The reason the block was rejected",
 
+	// TestMempoolAcceptCmd help.
+	"testmempoolaccept--synopsis": "Returns whether or not the provided raw transactions would be accepted to the memory pool without adding them to it or relaying them.\n" +
+		"The transactions may spend outputs of earlier transactions in the list, so any transaction that depends on another one in the list must come after it.\n" +
+		"Note that whether or not a transaction would cause other transactions to be evicted due to the maximum mempool size is not tested beyond ensuring it pays the current minimum relay fee.",
+	"testmempoolaccept-rawtxns":       "The serialized, hex-encoded transactions to test (max 25)",
+	"testmempoolaccept-allowhighfees": "Whether or not to allow insanely high fees",
+
+	// TestMempoolAcceptResult help.
+	"testmempoolacceptresult-txid":          "The hash of the transaction",
+	"testmempoolacceptresult-allowed":       "Whether or not the transaction would be accepted to the memory pool",
+	"testmempoolacceptresult-rejectreason":  "The kind of rule violation that would cause the transaction to be rejected (only present when not allowed)",
+	"testmempoolacceptresult-rejectmessage": "The detailed reason the transaction would be rejected (only present when not allowed)",
+	"testmempoolacceptresult-fee":           "The fee paid by the transaction in DCR (only present when allowed)",
+	"testmempoolacceptresult-size":          "The serialized size of the transaction in bytes",
+
 	// ValidateAddressResult help.
 	"validateaddresschainresult-isvalid": "Whether or not the address is valid",
 	"validateaddresschainresult-address": "The Decred address (only when isvalid is true)",
@@ -784,6 +949,15 @@ var helpDescsEnUS = map[string]string{
 	"session--synopsis":       "Return details regarding a websocket client's current connection session.",
 	"sessionresult-sessionid": "The unique session ID for a client's websocket connection.",
 
+	// ResumeSessionCmd help.
+	"resumesession--synopsis": "Resume the session of a previous websocket connection and replay the blockconnected, blockdisconnected, and relevanttxaccepted notifications it missed.\n" +
+		"The block notification and transaction filter registrations of the session are transferred to the current connection and the previous connection is closed if it is still open.\n" +
+		"A rescan required error (code -51) is returned when the session is unknown, has expired, or the missed notifications are no longer available.",
+	"resumesession-sessionid":      "The session ID of the previous websocket connection as returned by the session command",
+	"resumesession-lastseq":        "The sequence number of the most recent notification received on the previous connection",
+	"resumesessionresult-replayed": "The number of notifications that were replayed",
+	"resumesessionresult-lastseq":  "The sequence number of the most recent notification of the session",
+
 	// NotifyNewTicketsCmd help
 	"notifynewtickets--synopsis": "Request notifications for whenever new tickets are found.",
 
@@ -819,6 +993,12 @@ var helpDescsEnUS = map[string]string{
 
 	"stopnotifymixmessages--synopsis": "Cancel registered notifications for whenever mixing messages are accepted to the mixpool.",
 
+	// NotifyMempoolRemovalsCmd help.
+	"notifymempoolremovals--synopsis": "Send a txremoved notification with the hash of the transaction and the reason it was removed (mined, expired, doublespend, evicted, disapproved, or invalid) whenever a transaction is removed from the mempool.",
+
+	// StopNotifyMempoolRemovalsCmd help.
+	"stopnotifymempoolremovals--synopsis": "Stop sending txremoved notifications when transactions are removed from the mempool.",
+
 	"sendrawmixmessage--synopsis": "Submit a mixing message to the mixpool and broadcast it to the network and all peers",
 	"sendrawmixmessage-message":   "Mixing message serialized and encoded as hex",
 	"sendrawmixmessage-command":   "The wire command name of the message type",
@@ -846,6 +1026,29 @@ var helpDescsEnUS = map[string]string{
 	"estimatefee-numblocks": "(unused)",
 	"estimatefee--result0":  "Estimated fee.",
 
+	// EstimateRawFeeCmd help.
+	"estimaterawfee--synopsis": "Returns the details of the fee estimate for a confirmation target using the historical fee data.\n" +
+		"The estimate is the median fee rate of the range of fee rate buckets with the lowest fee rates such that it and all higher ranges have at least the threshold ratio of transactions mined within the target.",
+	"estimaterawfee-confirmations": "Estimate the fee rate a transaction requires so that it is mined in up to this number of blocks",
+	"estimaterawfee-threshold":     "The minimum ratio of transactions in a range of fee rate buckets that must have been mined within the target",
+
+	// EstimateRawFeeResult help.
+	"estimaterawfeeresult-feerate":   "The estimated fee rate (in DCR/KB), omitted when no estimate could be made",
+	"estimaterawfeeresult-decay":     "The exponential decay applied to the statistics for every new block",
+	"estimaterawfeeresult-threshold": "The threshold used for the estimate",
+	"estimaterawfeeresult-blocks":    "The confirmation target used for the estimate",
+	"estimaterawfeeresult-pass":      "The statistics of the range of fee rate buckets with the lowest fee rates that met the threshold, omitted when none met it",
+	"estimaterawfeeresult-fail":      "The statistics of the range of fee rate buckets immediately below the passing range that did not meet the threshold, omitted when none failed",
+	"estimaterawfeeresult-errors":    "The reasons no estimate could be made, omitted when an estimate was made",
+
+	// EstimateRawFeeBucket help.
+	"estimaterawfeebucket-startrange":     "The lower bound of the fee rates covered by the range (in DCR/KB)",
+	"estimaterawfeebucket-endrange":       "The upper bound of the fee rates covered by the range (in DCR/KB) or -1 when it is unbounded",
+	"estimaterawfeebucket-withintarget":   "The decay-adjusted number of transactions in the range that were mined within the target",
+	"estimaterawfeebucket-totalconfirmed": "The decay-adjusted number of transactions in the range that were mined",
+	"estimaterawfeebucket-inmempool":      "The decay-adjusted number of transactions in the range that have been in the mempool for the target without being mined",
+	"estimaterawfeebucket-successratio":   "The ratio of transactions in the range that were mined within the target to all transactions in the range",
+
 	// EstimateSmartFee help.
 	"estimatesmartfee--synopsis":     "Returns the estimated fee using the historical fee data in dcr/kb.",
 	"estimatesmartfee-confirmations": "Estimate the fee rate a transaction requires so that it is mined in up to this number of blocks.",
@@ -956,13 +1159,17 @@ var helpDescsEnUS = map[string]string{
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[types.Method][]interface{}{
 	"addnode":               nil,
+	"clearbanned":           nil,
 	"createrawssrtx":        {(*string)(nil)},
 	"createrawsstx":         {(*string)(nil)},
 	"createrawtransaction":  {(*string)(nil)},
 	"debuglevel":            {(*string)(nil), (*string)(nil)},
 	"decoderawtransaction":  {(*types.TxRawDecodeResult)(nil)},
 	"decodescript":          {(*types.DecodeScriptResult)(nil)},
+	"dumpfeebuckets":        {(*types.DumpFeeBucketsResult)(nil)},
+	"dumputxoset":           {(*types.DumpUtxoSetResult)(nil)},
 	"estimatefee":           {(*float64)(nil)},
+	"estimaterawfee":        {(*types.EstimateRawFeeResult)(nil)},
 	"estimatesmartfee":      {(*types.EstimateSmartFeeResult)(nil)},
 	"estimatestakediff":     {(*types.EstimateStakeDiffResult)(nil)},
 	"existsaddress":         {(*bool)(nil)},
@@ -990,6 +1197,9 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"gethashespersec":       {(*float64)(nil)},
 	"getheaders":            {(*types.GetHeadersResult)(nil)},
 	"getinfo":               {(*types.InfoChainResult)(nil)},
+	"getmempoolancestors":   {(*[]string)(nil), (*types.GetMempoolEntryResult)(nil)},
+	"getmempooldescendants": {(*[]string)(nil), (*types.GetMempoolEntryResult)(nil)},
+	"getmempoolentry":       {(*types.GetMempoolEntryResult)(nil)},
 	"getmempoolinfo":        {(*types.GetMempoolInfoResult)(nil)},
 	"getmininginfo":         {(*types.GetMiningInfoResult)(nil)},
 	"getmixmessage":         {(*types.GetMixMessageResult)(nil)},
@@ -998,8 +1208,10 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"getnetworkhashps":      {(*int64)(nil)},
 	"getnetworkinfo":        {(*[]types.GetNetworkInfoResult)(nil)},
 	"getpeerinfo":           {(*[]types.GetPeerInfoResult)(nil)},
+	"getratelimitinfo":      {(*types.GetRateLimitInfoResult)(nil)},
 	"getrawmempool":         {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
 	"getrawtransaction":     {(*string)(nil), (*types.TxRawResult)(nil)},
+	"getspendinginfo":       {(*types.GetSpendingInfoResult)(nil)},
 	"getstakedifficulty":    {(*types.GetStakeDifficultyResult)(nil)},
 	"getstakeversioninfo":   {(*types.GetStakeVersionInfoResult)(nil)},
 	"getstakeversions":      {(*types.GetStakeVersionsResult)(nil)},
@@ -1012,18 +1224,23 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"getwork":               {(*types.GetWorkResult)(nil), (*bool)(nil)},
 	"help":                  {(*string)(nil), (*string)(nil)},
 	"invalidateblock":       nil,
+	"listbanned":            {(*[]types.ListBannedResult)(nil)},
 	"livetickets":           {(*types.LiveTicketsResult)(nil)},
 	"node":                  nil,
 	"ping":                  nil,
 	"reconsiderblock":       nil,
 	"regentemplate":         nil,
+	"savemempool":           {(*types.SaveMempoolResult)(nil)},
+	"searchrawtransactions": {(*[]string)(nil), (*[]types.TxRawResult)(nil)},
 	"sendrawmixmessage":     nil,
 	"sendrawtransaction":    {(*string)(nil)},
+	"setban":                nil,
 	"setgenerate":           nil,
 	"startprofiler":         {(*types.StartProfilerResult)(nil)},
 	"stop":                  {(*string)(nil)},
 	"stopprofiler":          {(*string)(nil)},
 	"submitblock":           {nil, (*string)(nil)},
+	"testmempoolaccept":     {(*[]types.TestMempoolAcceptResult)(nil)},
 	"ticketfeeinfo":         {(*types.TicketFeeInfoResult)(nil)},
 	"ticketsforaddress":     {(*types.TicketsForAddressResult)(nil)},
 	"ticketvwap":            {(*float64)(nil)},
@@ -1036,6 +1253,7 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	// Websocket commands.
 	"loadtxfilter":              nil,
 	"notifyblocks":              nil,
+	"notifymempoolremovals":     nil,
 	"notifymixmessages":         nil,
 	"notifynewtickets":          nil,
 	"notifynewtransactions":     nil,
@@ -1044,8 +1262,10 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"notifywork":                nil,
 	"rebroadcastwinners":        nil,
 	"rescan":                    {(*types.RescanResult)(nil)},
+	"resumesession":             {(*types.ResumeSessionResult)(nil)},
 	"session":                   {(*types.SessionResult)(nil)},
 	"stopnotifyblocks":          nil,
+	"stopnotifymempoolremovals": nil,
 	"stopnotifymixmessages":     nil,
 	"stopnotifynewtransactions": nil,
 	"stopnotifytspend":          nil,
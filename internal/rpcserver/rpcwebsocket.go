@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -27,6 +27,7 @@ import (
 	"github.com/decred/dcrd/dcrjson/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/mixing"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
@@ -55,6 +56,19 @@ const (
 	// websocketPongTimeout is the maximum amount of time attempts to respond to
 	// websocket ping messages with a pong will wait before giving up.
 	websocketPongTimeout = time.Second * 5
+
+	// sessionReplayBufferSize is the maximum number of block and relevant
+	// transaction notifications retained for each websocket client session so
+	// they may be replayed when the session is resumed.
+	sessionReplayBufferSize = 1000
+
+	// sessionResumeTimeout is the amount of time the session of a
+	// disconnected websocket client is retained so it may be resumed.
+	sessionResumeTimeout = time.Minute * 10
+
+	// sessionPruneInterval is the interval at which expired sessions of
+	// disconnected websocket clients are removed.
+	sessionPruneInterval = time.Minute
 )
 
 type semaphore chan struct{}
@@ -88,14 +102,17 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 	"notifynewtickets":          handleNewTickets,
 	"notifynewtransactions":     handleNotifyNewTransactions,
 	"notifymixmessages":         handleNotifyMixMessages,
+	"notifymempoolremovals":     handleNotifyMempoolRemovals,
 	"rebroadcastwinners":        handleRebroadcastWinners,
 	"rescan":                    handleRescan,
+	"resumesession":             handleResumeSession,
 	"session":                   handleSession,
 	"stopnotifyblocks":          handleStopNotifyBlocks,
 	"stopnotifywork":            handleStopNotifyWork,
 	"stopnotifytspend":          handleStopNotifyTSpend,
 	"stopnotifynewtransactions": handleStopNotifyNewTransactions,
 	"stopnotifymixmessages":     handleStopNotifyMixMessages,
+	"stopnotifymempoolremovals": handleStopNotifyMempoolRemovals,
 }
 
 // WebsocketHandler handles a new websocket client by creating a new wsClient,
@@ -103,7 +120,11 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 // must be run in a separate goroutine.  It should be invoked from the websocket
 // server handler which runs each new connection in a new goroutine thereby
 // satisfying the requirement.
-func (s *Server) WebsocketHandler(ctx context.Context, conn *websocket.Conn, remoteAddr string, authenticated bool, isAdmin bool) {
+//
+// The user is the name the client authenticated with via HTTP Basic access
+// authentication or client certificate, if any, and is used along with the
+// remote address to rate limit the requests of the client.
+func (s *Server) WebsocketHandler(ctx context.Context, conn *websocket.Conn, remoteAddr, user string, authenticated bool, group *PermissionGroup) {
 	// Clear the read deadline that was set before the websocket hijacked
 	// the connection.
 	conn.SetReadDeadline(timeZeroVal)
@@ -121,7 +142,8 @@ func (s *Server) WebsocketHandler(ctx context.Context, conn *websocket.Conn, rem
 	// Create a new websocket client to handle the new websocket connection
 	// and wait for it to shutdown.  Once it has shutdown (and hence
 	// disconnected), remove it and any notifications it registered for.
-	client, err := newWebsocketClient(s, conn, remoteAddr, authenticated, isAdmin)
+	client, err := newWebsocketClient(s, conn, remoteAddr, user, authenticated,
+		group)
 	if err != nil {
 		log.Errorf("Failed to serve client %s: %v", remoteAddr, err)
 		conn.Close()
@@ -281,6 +303,21 @@ func (m *wsNotificationManager) NotifyMempoolTx(tx *dcrutil.Tx, isNew bool) {
 	}
 }
 
+// NotifyMempoolRemoval passes a transaction removed from the mempool along with
+// the reason it was removed to the notification manager for removal
+// notification processing.
+func (m *wsNotificationManager) NotifyMempoolRemoval(tx *dcrutil.Tx, reason mempool.RemovalReason) {
+	n := &notificationTxRemovedFromMempool{
+		reason: reason,
+		tx:     tx,
+	}
+
+	select {
+	case m.queueNotification <- n:
+	case <-m.quit:
+	}
+}
+
 // NotifyMixMessage passes a mixing message accepted by the mixpool to the
 // notification manager for message broadcasting.
 func (m *wsNotificationManager) NotifyMixMessage(msg mixing.Message) {
@@ -420,9 +457,21 @@ type notificationTxAcceptedByMempool struct {
 	isNew bool
 	tx    *dcrutil.Tx
 }
+type notificationTxRemovedFromMempool struct {
+	reason mempool.RemovalReason
+	tx     *dcrutil.Tx
+}
 type notificationMixMessage mixing.Message
 
 // Notification control requests.
+type notificationResumeSession struct {
+	wsc       *wsClient
+	user      string
+	group     *PermissionGroup
+	sessionID uint64
+	lastSeq   uint64
+	reply     chan resumeSessionReply
+}
 type notificationRegisterClient wsClient
 type notificationUnregisterClient wsClient
 type notificationRegisterBlocks wsClient
@@ -439,6 +488,8 @@ type notificationRegisterNewMempoolTxs wsClient
 type notificationUnregisterNewMempoolTxs wsClient
 type notificationRegisterMixMessages wsClient
 type notificationUnregisterMixMessages wsClient
+type notificationRegisterMempoolRemovals wsClient
+type notificationUnregisterMempoolRemovals wsClient
 
 // notificationHandler reads notifications and control messages from the queue
 // handler and processes one at a time.
@@ -460,6 +511,22 @@ func (m *wsNotificationManager) notificationHandler(ctx context.Context) {
 	ticketNewNotifications := make(map[chan struct{}]*wsClient)
 	txNotifications := make(map[chan struct{}]*wsClient)
 	mixNotifications := make(map[chan struct{}]*wsClient)
+	txRemovedNotifications := make(map[chan struct{}]*wsClient)
+
+	// detachedClients houses disconnected websocket clients keyed by their
+	// session ID.  They remain in the clients and block notifications maps
+	// so their sessions continue to record the notifications they miss
+	// until they are resumed or expire.
+	detachedClients := make(map[uint64]*wsDetachedClient)
+	removeDetachedClient := func(sessionID uint64) {
+		dc := detachedClients[sessionID]
+		delete(blockNotifications, dc.client.quit)
+		delete(clients, dc.client.quit)
+		delete(detachedClients, sessionID)
+	}
+
+	pruneTicker := time.NewTicker(sessionPruneInterval)
+	defer pruneTicker.Stop()
 
 out:
 	for {
@@ -468,6 +535,13 @@ out:
 			// RPC server shutdown.
 			break out
 
+		case now := <-pruneTicker.C:
+			for sessionID, dc := range detachedClients {
+				if now.After(dc.expires) {
+					removeDetachedClient(sessionID)
+				}
+			}
+
 		case n, ok := <-m.notificationMsgs:
 			if !ok {
 				// queueHandler quit.
@@ -505,6 +579,9 @@ out:
 				}
 				m.notifyRelevantTxAccepted(n.tx, clients)
 
+			case *notificationTxRemovedFromMempool:
+				m.notifyTxRemoved(txRemovedNotifications, n.tx, n.reason)
+
 			case notificationMixMessage:
 				m.notifyMixMessage(mixNotifications, (mixing.Message)(n))
 
@@ -516,6 +593,14 @@ out:
 				wsc := (*wsClient)(n)
 				delete(mixNotifications, wsc.quit)
 
+			case *notificationRegisterMempoolRemovals:
+				wsc := (*wsClient)(n)
+				txRemovedNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterMempoolRemovals:
+				wsc := (*wsClient)(n)
+				delete(txRemovedNotifications, wsc.quit)
+
 			case *notificationRegisterBlocks:
 				wsc := (*wsClient)(n)
 				blockNotifications[wsc.quit] = wsc
@@ -562,15 +647,52 @@ out:
 
 			case *notificationUnregisterClient:
 				wsc := (*wsClient)(n)
-				// Remove any requests made by the client as well as
-				// the client itself.
-				delete(blockNotifications, wsc.quit)
+				// Remove any requests made by the client that are not
+				// replayed when its session is resumed.
 				delete(workNotifications, wsc.quit)
 				delete(tspendNotifications, wsc.quit)
 				delete(txNotifications, wsc.quit)
+				delete(txRemovedNotifications, wsc.quit)
 				delete(winningTicketNotifications, wsc.quit)
 				delete(ticketNewNotifications, wsc.quit)
-				delete(clients, wsc.quit)
+
+				// Retain the client along with its session when it
+				// registered for block notifications or loaded a
+				// transaction filter so the session may be resumed by a
+				// later connection.  Otherwise, remove the client itself
+				// as well.
+				wsc.Lock()
+				session, hasFilter := wsc.session, wsc.filterData != nil
+				wsc.Unlock()
+				_, hasBlocks := blockNotifications[wsc.quit]
+				if session == nil || (!hasBlocks && !hasFilter) {
+					delete(blockNotifications, wsc.quit)
+					delete(clients, wsc.quit)
+					break
+				}
+
+				// Evict the session that is closest to expiring when the
+				// maximum number of detached clients is reached.
+				numDetached := len(detachedClients)
+				if numDetached > 0 && numDetached >= m.server.cfg.RPCMaxWebsockets {
+					var oldest *wsDetachedClient
+					for _, dc := range detachedClients {
+						if oldest == nil || dc.expires.Before(oldest.expires) {
+							oldest = dc
+						}
+					}
+					removeDetachedClient(oldest.sessionID)
+				}
+				detachedClients[session.id] = &wsDetachedClient{
+					client:    wsc,
+					sessionID: session.id,
+					expires:   time.Now().Add(sessionResumeTimeout),
+				}
+
+			case *notificationResumeSession:
+				result, err := m.resumeSession(n, clients,
+					blockNotifications, detachedClients)
+				n.reply <- resumeSessionReply{result: result, err: err}
 
 			case *notificationRegisterNewMempoolTxs:
 				wsc := (*wsClient)(n)
@@ -584,7 +706,7 @@ out:
 				log.Warnf("Unhandled notification type: %T", n)
 			}
 
-		case m.numClients <- len(clients):
+		case m.numClients <- len(clients) - len(detachedClients):
 		}
 	}
 
@@ -796,7 +918,7 @@ func (m *wsNotificationManager) notifyBlockConnected(clients map[chan struct{}]*
 				"notification: %v", err)
 			continue
 		}
-		client.QueueNotification(marshalledJSON)
+		client.queueReplayableNotification(marshalledJSON)
 	}
 }
 
@@ -829,7 +951,7 @@ func (*wsNotificationManager) notifyBlockDisconnected(clients map[chan struct{}]
 		return
 	}
 	for _, wsc := range clients {
-		wsc.QueueNotification(marshalledJSON)
+		wsc.queueReplayableNotification(marshalledJSON)
 	}
 }
 
@@ -1199,7 +1321,7 @@ func (m *wsNotificationManager) notifyRelevantTxAccepted(tx *dcrutil.Tx,
 			return
 		}
 		for _, c := range clientsToNotify {
-			c.QueueNotification(marshalled)
+			c.queueReplayableNotification(marshalled)
 		}
 	}
 }
@@ -1256,6 +1378,46 @@ func (m *wsNotificationManager) notifyMixMessage(clients map[chan struct{}]*wsCl
 	}
 }
 
+// RegisterMempoolRemovals requests notifications to the passed websocket client
+// when transactions are removed from the memory pool.
+func (m *wsNotificationManager) RegisterMempoolRemovals(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationRegisterMempoolRemovals)(wsc):
+	case <-m.quit:
+	}
+}
+
+// UnregisterMempoolRemovals removes notifications to the passed websocket
+// client when transactions are removed from the memory pool.
+func (m *wsNotificationManager) UnregisterMempoolRemovals(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationUnregisterMempoolRemovals)(wsc):
+	case <-m.quit:
+	}
+}
+
+// notifyTxRemoved notifies all clients subscribed to mempool removals with the
+// hash of the removed transaction and the reason it was removed.
+func (m *wsNotificationManager) notifyTxRemoved(clients map[chan struct{}]*wsClient,
+	tx *dcrutil.Tx, reason mempool.RemovalReason) {
+
+	// Skip notification creation if no clients have requested mempool
+	// removal notifications.
+	if len(clients) == 0 {
+		return
+	}
+
+	ntfn := types.NewTxRemovedNtfn(tx.Hash().String(), reason.String())
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		log.Errorf("Failed to marshal tx removed notification: %v", err)
+		return
+	}
+	for _, client := range clients {
+		client.QueueNotification(marshalledJSON)
+	}
+}
+
 // AddClient adds the passed websocket client to the notification manager.
 func (m *wsNotificationManager) AddClient(wsc *wsClient) {
 	select {
@@ -1265,7 +1427,9 @@ func (m *wsNotificationManager) AddClient(wsc *wsClient) {
 }
 
 // RemoveClient removes the passed websocket client and all notifications
-// registered for it.
+// registered for it.  The session of a client that registered for block
+// notifications or loaded a transaction filter is retained for a while so it
+// may be resumed by a later connection.
 func (m *wsNotificationManager) RemoveClient(wsc *wsClient) {
 	select {
 	case m.queueNotification <- (*notificationUnregisterClient)(wsc):
@@ -1273,6 +1437,145 @@ func (m *wsNotificationManager) RemoveClient(wsc *wsClient) {
 	}
 }
 
+// resumeSessionReply houses the result of a request to resume a websocket
+// client session.
+type resumeSessionReply struct {
+	result *types.ResumeSessionResult
+	err    error
+}
+
+// wsDetachedClient houses a disconnected websocket client along with the time
+// its session may no longer be resumed.
+type wsDetachedClient struct {
+	client    *wsClient
+	sessionID uint64
+	expires   time.Time
+}
+
+// ResumeSession transfers the session with the provided ID, along with its
+// block notification and transaction filter registrations, to the passed
+// websocket client and replays all retained notifications with a sequence
+// number after the provided one.
+//
+// Only the identity that created the session, meaning the same username and
+// permission group, may resume it.
+//
+// An error with the rescan required code is returned when the session is
+// unknown, has expired, belongs to a different identity, or no longer retains
+// all of the notifications after the provided sequence number.
+func (m *wsNotificationManager) ResumeSession(wsc *wsClient, sessionID, lastSeq uint64) (*types.ResumeSessionResult, error) {
+	n := &notificationResumeSession{
+		wsc:       wsc,
+		user:      wsc.rateID.user,
+		group:     wsc.group,
+		sessionID: sessionID,
+		lastSeq:   lastSeq,
+		reply:     make(chan resumeSessionReply, 1),
+	}
+	select {
+	case m.queueNotification <- n:
+	case <-m.quit:
+		return nil, rpcConnectionClosedError()
+	}
+	select {
+	case r := <-n.reply:
+		return r.result, r.err
+	case <-m.quit:
+		return nil, rpcConnectionClosedError()
+	}
+}
+
+// resumeSession implements the logic for resuming a websocket client session
+// on behalf of the notification handler.  The provided maps are those of the
+// notification handler and are updated accordingly.
+//
+// This function MUST only be called from the notification handler goroutine.
+func (m *wsNotificationManager) resumeSession(n *notificationResumeSession,
+	clients, blockNotifications map[chan struct{}]*wsClient,
+	detachedClients map[uint64]*wsDetachedClient) (*types.ResumeSessionResult, error) {
+
+	// Find the client the session belongs to.  This is typically a detached
+	// client, however, it may also be a client that is still connected when
+	// the loss of its connection has not yet been noticed.
+	var prev *wsClient
+	dc, detached := detachedClients[n.sessionID]
+	if detached {
+		prev = dc.client
+	} else {
+		for _, c := range clients {
+			c.Lock()
+			match := c.session != nil && c.session.id == n.sessionID
+			c.Unlock()
+			if match {
+				prev = c
+				break
+			}
+		}
+	}
+
+	// Only allow the identity that created a session to resume it so
+	// resuming a session can't be used to take over the registrations of
+	// another user or to receive notifications that are not otherwise
+	// permitted.  Such sessions are treated as unknown to avoid revealing
+	// their existence.
+	var session *wsSession
+	var filter *wsClientFilter
+	var owned bool
+	if prev != nil {
+		prev.Lock()
+		session, filter = prev.session, prev.filterData
+		owned = session != nil && session.isOwner(n.user, n.group)
+		prev.Unlock()
+	}
+	if !owned {
+		return nil, rpcRescanRequiredError("Session %d is unknown or has "+
+			"expired", n.sessionID)
+	}
+	ntfns, err := session.replaySince(n.lastSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transfer the session along with the block notification and transaction
+	// filter registrations to the new client and disconnect the previous one
+	// when it is still connected.
+	wsc := n.wsc
+	if prev != wsc {
+		prev.Lock()
+		prev.session = nil
+		prev.Unlock()
+		prev.Disconnect()
+
+		wsc.Lock()
+		wsc.session = session
+		if wsc.filterData == nil {
+			wsc.filterData = filter
+		}
+		wsc.Unlock()
+
+		if _, ok := blockNotifications[prev.quit]; ok {
+			delete(blockNotifications, prev.quit)
+			blockNotifications[wsc.quit] = wsc
+		}
+		if detached {
+			delete(clients, prev.quit)
+			delete(detachedClients, n.sessionID)
+		}
+	}
+
+	// Replay the notifications missed by the client.
+	for _, ntfn := range ntfns {
+		if err := wsc.enqueueNotification(ntfn); err != nil {
+			break
+		}
+	}
+
+	return &types.ResumeSessionResult{
+		Replayed: uint32(len(ntfns)),
+		LastSeq:  session.latestSeq(),
+	}, nil
+}
+
 // Run starts the goroutines required for the manager to queue and process
 // websocket client notifications.  It blocks until the provided context is
 // cancelled.
@@ -1313,6 +1616,143 @@ type wsResponse struct {
 	doneChan chan bool
 }
 
+// wsSessionNtfn houses a marshalled notification retained by a websocket client
+// session along with the sequence number it was assigned.
+type wsSessionNtfn struct {
+	seq            uint64
+	marshalledJSON []byte
+}
+
+// wsSession houses the notification state of a websocket client session.
+// Every notification sent to the client is assigned the next sequence number
+// of the session and the most recent block and relevant transaction
+// notifications are retained so they may be replayed when a client that lost
+// its connection resumes the session.
+type wsSession struct {
+	// id is a random ID generated for each session.  These IDs may be
+	// queried by a client using the session RPC.  A change to the session ID
+	// indicates that the client reconnected without resuming its previous
+	// session.
+	id uint64
+
+	// user and group identify the client that created the session.  Only a
+	// client with the same identity may resume it.  They are set when the
+	// session is created or when the client that created it authenticates
+	// via the authenticate command and are protected by the mutex of that
+	// client.
+	user  string
+	group *PermissionGroup
+
+	// mtx protects the following fields.
+	mtx sync.Mutex
+
+	// lastSeq is the sequence number assigned to the most recent
+	// notification.
+	lastSeq uint64
+
+	// replay houses the most recent replayable notifications in order of
+	// their sequence numbers.  It is limited to sessionReplayBufferSize
+	// entries.
+	replay []wsSessionNtfn
+
+	// evictedSeq is the sequence number of the most recent notification that
+	// was evicted from the replay buffer.  Resuming the session after any
+	// sequence number before it is not possible since that notification can
+	// no longer be replayed.
+	evictedSeq uint64
+}
+
+// newWsSession returns a new websocket client session with a random ID that is
+// owned by the provided user and permission group.
+func newWsSession(user string, group *PermissionGroup) *wsSession {
+	return &wsSession{id: rand.Uint64(), user: user, group: group}
+}
+
+// isOwner returns whether or not the provided user and permission group are
+// the identity that created the session.
+func (s *wsSession) isOwner(user string, group *PermissionGroup) bool {
+	return s.group != nil && s.user == user && s.group == group
+}
+
+// appendNtfnSeq returns a copy of the passed marshalled JSON-RPC notification
+// with an additional seq field set to the provided sequence number.
+func appendNtfnSeq(marshalledJSON []byte, seq uint64) []byte {
+	end := bytes.LastIndexByte(marshalledJSON, '}')
+	if end == -1 {
+		return marshalledJSON
+	}
+	const seqField = `,"seq":`
+	b := make([]byte, 0, len(marshalledJSON)+len(seqField)+20)
+	b = append(b, marshalledJSON[:end]...)
+	b = append(b, seqField...)
+	b = strconv.AppendUint(b, seq, 10)
+	return append(b, marshalledJSON[end:]...)
+}
+
+// stamp assigns the next sequence number of the session to the passed
+// marshalled notification and returns the notification with the sequence
+// number added.  Replayable notifications are also retained in the replay
+// buffer, evicting the oldest one when it is full.
+//
+// This function is safe for concurrent access.
+func (s *wsSession) stamp(marshalledJSON []byte, replayable bool) []byte {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.lastSeq++
+	stamped := appendNtfnSeq(marshalledJSON, s.lastSeq)
+	if replayable {
+		if len(s.replay) >= sessionReplayBufferSize {
+			s.evictedSeq = s.replay[0].seq
+			s.replay[0] = wsSessionNtfn{} // avoid leak
+			s.replay = s.replay[1:]
+		}
+		s.replay = append(s.replay, wsSessionNtfn{
+			seq:            s.lastSeq,
+			marshalledJSON: stamped,
+		})
+	}
+	return stamped
+}
+
+// replaySince returns all retained notifications with a sequence number after
+// the provided one.  An error with the rescan required code is returned when
+// any of those notifications are no longer retained.
+//
+// This function is safe for concurrent access.
+func (s *wsSession) replaySince(lastSeq uint64) ([][]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if lastSeq > s.lastSeq {
+		return nil, rpcInvalidError("Sequence number %d is after the most "+
+			"recent notification sequence number %d", lastSeq, s.lastSeq)
+	}
+	if lastSeq < s.evictedSeq {
+		return nil, rpcRescanRequiredError("Notifications after sequence "+
+			"number %d are no longer available", lastSeq)
+	}
+
+	var ntfns [][]byte
+	for i := range s.replay {
+		if s.replay[i].seq > lastSeq {
+			ntfns = append(ntfns, s.replay[i].marshalledJSON)
+		}
+	}
+	return ntfns, nil
+}
+
+// latestSeq returns the sequence number assigned to the most recent
+// notification of the session.
+//
+// This function is safe for concurrent access.
+func (s *wsSession) latestSeq() uint64 {
+	s.mtx.Lock()
+	seq := s.lastSeq
+	s.mtx.Unlock()
+	return seq
+}
+
 // wsClient provides an abstraction for handling a websocket client. The overall
 // data flow is split into 3 main goroutines. A websocket manager is used to
 // allow things such as broadcasting requested notifications to all connected
@@ -1343,14 +1783,19 @@ type wsClient struct {
 	// and therefore is allowed to communicated over the websocket.
 	authenticated bool
 
-	// isAdmin specifies whether a client may change the state of the server;
-	// false means its access is only to the limited set of RPC calls.
-	isAdmin bool
+	// group is the permission group that determines the RPC calls the client
+	// may invoke.  It is nil until the client is authenticated.
+	group *PermissionGroup
 
-	// sessionID is a random ID generated for each client when connected.
-	// These IDs may be queried by a client using the session RPC.  A change
-	// to the session ID indicates that the client reconnected.
-	sessionID uint64
+	// rateID identifies the clients the requests of the client are charged
+	// to for the purposes of rate limiting.  The user is updated when the
+	// client authenticates via the authenticate command.
+	rateID rateLimitID
+
+	// session houses the notification sequence and replay state of the
+	// client.  It is replaced when the client resumes a previous session and
+	// is set to nil when another client resumes the session of this one.
+	session *wsSession
 
 	// verboseTxUpdates specifies whether a client has requested verbose
 	// information about all new transactions.
@@ -1487,11 +1932,13 @@ out:
 				break out
 			case !c.authenticated:
 				// Check credentials.
-				c.authenticated, c.isAdmin = c.rpcServer.checkAuthUserPass(
+				c.authenticated, c.group = c.rpcServer.checkAuthUserPass(
 					authCmd.Username, authCmd.Passphrase, c.addr)
 				if !c.authenticated {
 					break out
 				}
+				c.rateID.user = authCmd.Username
+				c.setSessionOwner(authCmd.Username, c.group)
 
 				// Increase the read limits for authenticated connections.
 				c.conn.SetReadLimit(websocketReadLimitAuthenticated)
@@ -1506,24 +1953,37 @@ out:
 				continue
 			}
 
-			// Check if the client is using limited RPC credentials and
-			// error when not authorized to call the supplied RPC.
-			if !c.isAdmin {
-				if _, ok := rpcLimited[req.Method]; !ok {
-					jsonErr := &dcrjson.RPCError{
-						Code:    dcrjson.ErrRPCInvalidParams.Code,
-						Message: "limited user not authorized for this method",
-					}
-					// Marshal and send response.
-					reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
-					if err != nil {
-						log.Errorf("Failed to marshal parse failure "+
-							"reply: %v", err)
-						continue
-					}
-					c.SendMessage(reply, nil)
+			// Check if the client is using RPC credentials that restrict the
+			// permitted methods and error when not authorized to call the
+			// supplied RPC.
+			if !c.group.allows(req.Method) {
+				jsonErr := &dcrjson.RPCError{
+					Code:    dcrjson.ErrRPCInvalidParams.Code,
+					Message: unauthorizedMethodMsg(c.group),
+				}
+				// Marshal and send response.
+				reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
+				if err != nil {
+					log.Errorf("Failed to marshal parse failure "+
+						"reply: %v", err)
+					continue
+				}
+				c.SendMessage(reply, nil)
+				continue
+			}
+
+			// Reject the request when the client is over its rate limit.
+			rateErr := c.rpcServer.checkRateLimit(c.group, c.rateID, cmd)
+			if rateErr != nil {
+				reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil,
+					rateErr)
+				if err != nil {
+					log.Errorf("Failed to marshal rate limit reply: %v",
+						err)
 					continue
 				}
+				c.SendMessage(reply, nil)
+				continue
 			}
 
 			// Asynchronously handle the request.  A semaphore is used to
@@ -1695,11 +2155,13 @@ out:
 							break out
 						case !c.authenticated:
 							// Check credentials.
-							c.authenticated, c.isAdmin = c.rpcServer.checkAuthUserPass(
+							c.authenticated, c.group = c.rpcServer.checkAuthUserPass(
 								authCmd.Username, authCmd.Passphrase, c.addr)
 							if !c.authenticated {
 								break out
 							}
+							c.rateID.user = authCmd.Username
+							c.setSessionOwner(authCmd.Username, c.group)
 
 							// Marshal and send response.
 							reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, nil)
@@ -1715,27 +2177,45 @@ out:
 							continue
 						}
 
-						// Check if the client is using limited RPC credentials and
-						// error when not authorized to call the supplied RPC.
-						if !c.isAdmin {
-							if _, ok := rpcLimited[req.Method]; !ok {
-								jsonErr := &dcrjson.RPCError{
-									Code:    dcrjson.ErrRPCInvalidParams.Code,
-									Message: "limited user not authorized for this method",
-								}
-								// Marshal and send response.
-								reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
-								if err != nil {
-									log.Errorf("Failed to marshal parse failure "+
-										"reply: %v", err)
-									continue
-								}
-
-								if reply != nil {
-									results = append(results, reply)
-								}
+						// Check if the client is using RPC credentials that restrict the
+						// permitted methods and error when not authorized to call the
+						// supplied RPC.
+						if !c.group.allows(req.Method) {
+							jsonErr := &dcrjson.RPCError{
+								Code:    dcrjson.ErrRPCInvalidParams.Code,
+								Message: unauthorizedMethodMsg(c.group),
+							}
+							// Marshal and send response.
+							reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
+							if err != nil {
+								log.Errorf("Failed to marshal parse failure "+
+									"reply: %v", err)
+								continue
+							}
+
+							if reply != nil {
+								results = append(results, reply)
+							}
+							continue
+						}
+
+						// Reject the request when the client is over its rate
+						// limit.
+						rateErr := c.rpcServer.checkRateLimit(c.group,
+							c.rateID, cmd)
+						if rateErr != nil {
+							reply, err = createMarshalledReply(cmd.jsonrpc,
+								cmd.id, nil, rateErr)
+							if err != nil {
+								log.Errorf("Failed to marshal rate limit "+
+									"reply: %v", err)
 								continue
 							}
+
+							if reply != nil {
+								results = append(results, reply)
+							}
+							continue
 						}
 
 						// Lookup the websocket extension for the command, if it doesn't
@@ -1743,7 +2223,10 @@ out:
 						var resp interface{}
 						wsHandler, ok := wsHandlers[cmd.method]
 						if ok {
+							start := time.Now()
 							resp, err = wsHandler(ctx, c, cmd.params)
+							c.rpcServer.requestProcessed(cmd.method, start,
+								err)
 						} else {
 							resp, err = c.rpcServer.standardCmdResult(ctx,
 								cmd)
@@ -1814,7 +2297,9 @@ func (c *wsClient) serviceRequest(ctx context.Context, r *parsedRPCCmd) {
 	// exist fallback to handling the command as a standard command.
 	wsHandler, ok := wsHandlers[r.method]
 	if ok {
+		start := time.Now()
 		result, err = wsHandler(ctx, c, r.params)
+		c.rpcServer.requestProcessed(r.method, start, err)
 	} else {
 		result, err = c.rpcServer.standardCmdResult(ctx, r)
 	}
@@ -1952,6 +2437,8 @@ var ErrClientQuit = errors.New("client quit")
 // as the memory pool and sync manager, from blocking even when the send channel
 // is full.
 //
+// The notification is assigned the next sequence number of the client session.
+//
 // If the client is in the process of shutting down, this function returns
 // ErrClientQuit.  This is intended to be checked by long-running notification
 // handlers to stop processing if there is no more work needed to be done.
@@ -1961,6 +2448,50 @@ func (c *wsClient) QueueNotification(marshalledJSON []byte) error {
 		return ErrClientQuit
 	}
 
+	return c.queueNotification(marshalledJSON, false)
+}
+
+// queueReplayableNotification is identical to QueueNotification except the
+// notification is also retained by the client session so it may be replayed
+// when the session is resumed.  Notice that this means the notification is
+// recorded even when the client is disconnected.
+func (c *wsClient) queueReplayableNotification(marshalledJSON []byte) error {
+	return c.queueNotification(marshalledJSON, true)
+}
+
+// setSessionOwner updates the identity that owns the session of the client
+// after it authenticates via the authenticate command.
+func (c *wsClient) setSessionOwner(user string, group *PermissionGroup) {
+	c.Lock()
+	if c.session != nil {
+		c.session.user, c.session.group = user, group
+	}
+	c.Unlock()
+}
+
+// queueNotification assigns the next sequence number of the client session to
+// the passed notification and queues it to be sent to the websocket client.
+func (c *wsClient) queueNotification(marshalledJSON []byte, replayable bool) error {
+	c.Lock()
+	session := c.session
+	c.Unlock()
+
+	// The session was resumed by another client.
+	if session == nil {
+		return ErrClientQuit
+	}
+
+	return c.enqueueNotification(session.stamp(marshalledJSON, replayable))
+}
+
+// enqueueNotification queues the passed notification, which must already have
+// its sequence number assigned, to be sent to the websocket client.
+func (c *wsClient) enqueueNotification(marshalledJSON []byte) error {
+	// Don't queue the message if disconnected.
+	if c.Disconnected() {
+		return ErrClientQuit
+	}
+
 	// Use select statement to unblock enqueuing the message once the client has
 	// begun shutting down.
 	select {
@@ -2032,16 +2563,15 @@ func (c *wsClient) Run(ctx context.Context) {
 // incoming and outgoing messages in separate goroutines complete with queuing
 // and asynchronous handling for long-running operations.
 func newWebsocketClient(server *Server, conn *websocket.Conn,
-	remoteAddr string, authenticated bool, isAdmin bool) (*wsClient, error) {
-
-	sessionID := rand.Uint64()
+	remoteAddr, user string, authenticated bool, group *PermissionGroup) (*wsClient, error) {
 
 	client := &wsClient{
 		conn:              conn,
 		addr:              remoteAddr,
+		rateID:            newRateLimitID(user, remoteAddr),
 		authenticated:     authenticated,
-		isAdmin:           isAdmin,
-		sessionID:         sessionID,
+		group:             group,
+		session:           newWsSession(user, group),
 		rpcServer:         server,
 		serviceRequestSem: makeSemaphore(server.cfg.RPCMaxConcurrentReqs),
 		ntfnChan:          make(chan []byte, 1), // nonblocking sync
@@ -2188,7 +2718,23 @@ func handleNotifyTSpend(_ context.Context, wsc *wsClient, _ interface{}) (interf
 // handleSession implements the session command extension for websocket
 // connections.
 func handleSession(_ context.Context, wsc *wsClient, icmd interface{}) (interface{}, error) {
-	return &types.SessionResult{SessionID: wsc.sessionID}, nil
+	wsc.Lock()
+	session := wsc.session
+	wsc.Unlock()
+	if session == nil {
+		return nil, rpcConnectionClosedError()
+	}
+	return &types.SessionResult{SessionID: session.id}, nil
+}
+
+// handleResumeSession implements the resumesession command extension for
+// websocket connections.
+func handleResumeSession(_ context.Context, wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.ResumeSessionCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+	return wsc.rpcServer.ntfnMgr.ResumeSession(wsc, cmd.SessionID, cmd.LastSeq)
 }
 
 // handleWinningTickets implements the notifywinningtickets command
@@ -2260,6 +2806,20 @@ func handleStopNotifyMixMessages(_ context.Context, wsc *wsClient, _ interface{}
 	return nil, nil
 }
 
+// handleNotifyMempoolRemovals implements the notifymempoolremovals command
+// extension for websocket connections.
+func handleNotifyMempoolRemovals(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.RegisterMempoolRemovals(wsc)
+	return nil, nil
+}
+
+// handleStopNotifyMempoolRemovals implements the stopnotifymempoolremovals
+// command extension for websocket connections.
+func handleStopNotifyMempoolRemovals(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.UnregisterMempoolRemovals(wsc)
+	return nil, nil
+}
+
 // rescanBlock rescans a block for any relevant transactions for the passed
 // lookup keys.  Any discovered transactions are returned hex encoded as a
 // string slice.
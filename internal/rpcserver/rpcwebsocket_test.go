@@ -0,0 +1,264 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+// ntfnSeq returns the sequence number of the passed marshalled notification.
+func ntfnSeq(t *testing.T, marshalledJSON []byte) uint64 {
+	t.Helper()
+
+	var ntfn struct {
+		Method string  `json:"method"`
+		Seq    *uint64 `json:"seq"`
+	}
+	if err := json.Unmarshal(marshalledJSON, &ntfn); err != nil {
+		t.Fatalf("failed to unmarshal notification %s: %v", marshalledJSON,
+			err)
+	}
+	if ntfn.Seq == nil {
+		t.Fatalf("notification %s does not have a sequence number",
+			marshalledJSON)
+	}
+	return *ntfn.Seq
+}
+
+// rpcErrorCode returns the code of the passed error when it is an RPC error
+// and fails the test otherwise.
+func rpcErrorCode(t *testing.T, err error) dcrjson.RPCErrorCode {
+	t.Helper()
+
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("unexpected error type: got %T (%v), want *RPCError", err,
+			err)
+	}
+	return rpcErr.Code
+}
+
+// TestWsSessionReplay ensures websocket client sessions assign sequence numbers
+// to notifications and replay the retained ones as expected.
+func TestWsSessionReplay(t *testing.T) {
+	t.Parallel()
+
+	session := newWsSession("alice", adminGroup)
+
+	// Ensure the sequence number is added to the notification and that the
+	// other fields are unchanged.
+	const ntfn = `{"jsonrpc":"1.0","method":"test","params":[],"id":null}`
+	stamped := session.stamp([]byte(ntfn), true)
+	const wantStamped = `{"jsonrpc":"1.0","method":"test","params":[],` +
+		`"id":null,"seq":1}`
+	if string(stamped) != wantStamped {
+		t.Fatalf("unexpected stamped notification: got %s, want %s",
+			stamped, wantStamped)
+	}
+
+	// Ensure notifications that are not replayable are assigned a sequence
+	// number but are not retained.
+	if seq := ntfnSeq(t, session.stamp([]byte(ntfn), false)); seq != 2 {
+		t.Fatalf("unexpected sequence number: got %d, want 2", seq)
+	}
+	ntfns, err := session.replaySince(0)
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if len(ntfns) != 1 || ntfnSeq(t, ntfns[0]) != 1 {
+		t.Fatalf("unexpected replayed notifications: %q", ntfns)
+	}
+
+	// Ensure resuming after a sequence number that has not been assigned yet
+	// is rejected.
+	_, err = session.replaySince(3)
+	if code := rpcErrorCode(t, err); code != dcrjson.ErrRPCInvalidParameter {
+		t.Fatalf("unexpected error code: got %d, want %d", code,
+			dcrjson.ErrRPCInvalidParameter)
+	}
+
+	// Overflow the replay buffer and ensure resuming after any evicted
+	// notification requires a rescan while resuming after the most recent
+	// evicted one replays all retained notifications.
+	for i := 0; i < sessionReplayBufferSize; i++ {
+		session.stamp([]byte(ntfn), true)
+	}
+	if seq := session.latestSeq(); seq != sessionReplayBufferSize+2 {
+		t.Fatalf("unexpected latest sequence number: got %d, want %d", seq,
+			sessionReplayBufferSize+2)
+	}
+	_, err = session.replaySince(0)
+	if code := rpcErrorCode(t, err); code != dcrjson.ErrRPCRescanRequired {
+		t.Fatalf("unexpected error code: got %d, want %d", code,
+			dcrjson.ErrRPCRescanRequired)
+	}
+	ntfns, err = session.replaySince(1)
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if len(ntfns) != sessionReplayBufferSize {
+		t.Fatalf("unexpected number of replayed notifications: got %d, "+
+			"want %d", len(ntfns), sessionReplayBufferSize)
+	}
+	if seq := ntfnSeq(t, ntfns[0]); seq != 3 {
+		t.Fatalf("unexpected first replayed sequence number: got %d, "+
+			"want 3", seq)
+	}
+
+	// Ensure resuming at the most recent sequence number replays nothing.
+	ntfns, err = session.replaySince(session.latestSeq())
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if len(ntfns) != 0 {
+		t.Fatalf("unexpected replayed notifications: %q", ntfns)
+	}
+}
+
+// TestResumeSession ensures the notification manager retains the sessions of
+// disconnected websocket clients and transfers them to the clients that resume
+// them.
+func TestResumeSession(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := &Server{cfg: Config{RPCMaxWebsockets: 2}}
+	mgr := newWsNotificationManager(s)
+	go mgr.Run(ctx)
+
+	// Mock clients do not have a websocket connection, so mark them as
+	// disconnected prior to shutting down the manager since it disconnects
+	// all remaining clients.
+	newClient := func(user string, group *PermissionGroup) *wsClient {
+		wsc := &wsClient{
+			rpcServer: s,
+			group:     group,
+			rateID:    rateLimitID{user: user},
+			session:   newWsSession(user, group),
+			ntfnChan:  make(chan []byte, 10),
+			quit:      make(chan struct{}),
+		}
+		t.Cleanup(func() { wsc.disconnected.Store(true) })
+		return wsc
+	}
+	disconnect := func(wsc *wsClient) {
+		wsc.disconnected.Store(true)
+		close(wsc.quit)
+		mgr.RemoveClient(wsc)
+	}
+	recvSeq := func(wsc *wsClient) uint64 {
+		t.Helper()
+		select {
+		case ntfn := <-wsc.ntfnChan:
+			return ntfnSeq(t, ntfn)
+		case <-time.After(time.Second * 5):
+			t.Fatal("timeout waiting for notification")
+		}
+		return 0
+	}
+	block := dcrutil.NewBlock(&wire.MsgBlock{})
+
+	// Register a client for block notifications and ensure the notifications
+	// it receives are assigned sequential numbers.
+	c1 := newClient("alice", adminGroup)
+	mgr.AddClient(c1)
+	mgr.RegisterBlockUpdates(c1)
+	mgr.NotifyBlockDisconnected(block)
+	mgr.NotifyBlockDisconnected(block)
+	for want := uint64(1); want <= 2; want++ {
+		if seq := recvSeq(c1); seq != want {
+			t.Fatalf("unexpected sequence number: got %d, want %d", seq,
+				want)
+		}
+	}
+
+	// Disconnect the client and ensure it is no longer counted while the
+	// notifications it misses are still recorded by its session.
+	disconnect(c1)
+	mgr.NotifyBlockDisconnected(block)
+	if n := mgr.NumClients(); n != 0 {
+		t.Fatalf("unexpected number of clients: got %d, want 0", n)
+	}
+
+	// Ensure resuming an unknown session requires a rescan.
+	c2 := newClient("alice", adminGroup)
+	mgr.AddClient(c2)
+	_, err := mgr.ResumeSession(c2, c1.session.id+1, 0)
+	if code := rpcErrorCode(t, err); code != dcrjson.ErrRPCRescanRequired {
+		t.Fatalf("unexpected error code: got %d, want %d", code,
+			dcrjson.ErrRPCRescanRequired)
+	}
+
+	// Ensure a client in a different permission group may not resume the
+	// session.
+	other := newClient("alice", limitedGroup)
+	mgr.AddClient(other)
+	_, err = mgr.ResumeSession(other, c1.session.id, 2)
+	if code := rpcErrorCode(t, err); code != dcrjson.ErrRPCRescanRequired {
+		t.Fatalf("unexpected error code: got %d, want %d", code,
+			dcrjson.ErrRPCRescanRequired)
+	}
+	disconnect(other)
+
+	// Ensure a different user in the same permission group may not resume
+	// the session either.
+	other = newClient("bob", adminGroup)
+	mgr.AddClient(other)
+	_, err = mgr.ResumeSession(other, c1.session.id, 2)
+	if code := rpcErrorCode(t, err); code != dcrjson.ErrRPCRescanRequired {
+		t.Fatalf("unexpected error code: got %d, want %d", code,
+			dcrjson.ErrRPCRescanRequired)
+	}
+	disconnect(other)
+
+	// Resume the session and ensure the missed notification is replayed and
+	// later notifications continue the sequence of the session.
+	sessionID := c1.session.id
+	result, err := mgr.ResumeSession(c2, sessionID, 2)
+	if err != nil {
+		t.Fatalf("unexpected resume error: %v", err)
+	}
+	if result.Replayed != 1 || result.LastSeq != 3 {
+		t.Fatalf("unexpected resume result: got %+v, want replayed 1, "+
+			"lastseq 3", result)
+	}
+	if seq := recvSeq(c2); seq != 3 {
+		t.Fatalf("unexpected replayed sequence number: got %d, want 3", seq)
+	}
+	if c1.session != nil || c2.session.id != sessionID {
+		t.Fatal("session was not transferred to the resuming client")
+	}
+	mgr.NotifyBlockDisconnected(block)
+	if seq := recvSeq(c2); seq != 4 {
+		t.Fatalf("unexpected sequence number: got %d, want 4", seq)
+	}
+
+	// Disconnect the resuming client as well and ensure the session may be
+	// resumed again by another client.
+	disconnect(c2)
+	c3 := newClient("alice", adminGroup)
+	mgr.AddClient(c3)
+	result, err = mgr.ResumeSession(c3, sessionID, 4)
+	if err != nil {
+		t.Fatalf("unexpected resume error: %v", err)
+	}
+	if result.Replayed != 0 || result.LastSeq != 4 {
+		t.Fatalf("unexpected resume result: got %+v, want replayed 0, "+
+			"lastseq 4", result)
+	}
+	if n := mgr.NumClients(); n != 1 {
+		t.Fatalf("unexpected number of clients: got %d, want 1", n)
+	}
+}
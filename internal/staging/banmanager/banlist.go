@@ -0,0 +1,294 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package banmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// banListFilename is the default filename to store the ban list.
+	banListFilename = "banlist.json"
+
+	// banListVersion is the current version of the serialized ban list.
+	banListVersion = 1
+)
+
+var (
+	// ErrAlreadyBanned indicates an attempt to ban a subnet that is already
+	// banned.
+	ErrAlreadyBanned = errors.New("subnet is already banned")
+
+	// ErrNotBanned indicates an attempt to unban a subnet that is not banned.
+	ErrNotBanned = errors.New("subnet is not banned")
+)
+
+// BanEntry describes a banned subnet.
+type BanEntry struct {
+	// Subnet is the banned subnet.  Bans of individual hosts use a subnet
+	// with a full mask.
+	Subnet net.IPNet
+
+	// Created is the time the ban was created.
+	Created time.Time
+
+	// Expires is the time the ban ends.
+	Expires time.Time
+
+	// Reason is a human-readable description of why the subnet was banned.
+	Reason string
+}
+
+// serializedBanEntry is the JSON representation of a ban entry.
+type serializedBanEntry struct {
+	Subnet  string `json:"subnet"`
+	Created int64  `json:"created"`
+	Expires int64  `json:"expires"`
+	Reason  string `json:"reason"`
+}
+
+// serializedBanList is the JSON representation of the ban list.
+type serializedBanList struct {
+	Version int                  `json:"version"`
+	Bans    []serializedBanEntry `json:"bans"`
+}
+
+// HostSubnet returns a subnet that only contains the provided IP address.
+func HostSubnet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(8*net.IPv4len,
+			8*net.IPv4len)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}
+}
+
+// BanList houses a set of banned subnets that is optionally persisted to disk
+// so the bans survive restarts.  It is safe for concurrent access.
+type BanList struct {
+	mtx      sync.Mutex
+	filePath string
+	bans     map[string]*BanEntry
+}
+
+// NewBanList returns a new ban list that is persisted to a file in the provided
+// data directory.  An empty data directory results in a ban list that is only
+// kept in memory.
+//
+// Load must be called to restore any previously persisted bans.
+func NewBanList(dataDir string) *BanList {
+	var filePath string
+	if dataDir != "" {
+		filePath = filepath.Join(dataDir, banListFilename)
+	}
+	return &BanList{
+		filePath: filePath,
+		bans:     make(map[string]*BanEntry),
+	}
+}
+
+// Load restores the bans persisted to disk.  Expired bans are discarded.  It is
+// not an error if the ban list has not been persisted yet.
+func (bl *BanList) Load() error {
+	if bl.filePath == "" {
+		return nil
+	}
+
+	serialized, err := os.ReadFile(bl.filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var sbl serializedBanList
+	if err := json.Unmarshal(serialized, &sbl); err != nil {
+		return fmt.Errorf("unable to decode ban list %s: %w", bl.filePath, err)
+	}
+	if sbl.Version != banListVersion {
+		return fmt.Errorf("unknown ban list version %d in %s", sbl.Version,
+			bl.filePath)
+	}
+
+	now := time.Now()
+	bans := make(map[string]*BanEntry, len(sbl.Bans))
+	for _, sbe := range sbl.Bans {
+		_, subnet, err := net.ParseCIDR(sbe.Subnet)
+		if err != nil {
+			return fmt.Errorf("invalid subnet %q in ban list %s: %w",
+				sbe.Subnet, bl.filePath, err)
+		}
+		expires := time.Unix(sbe.Expires, 0)
+		if !now.Before(expires) {
+			continue
+		}
+		bans[subnet.String()] = &BanEntry{
+			Subnet:  *subnet,
+			Created: time.Unix(sbe.Created, 0),
+			Expires: expires,
+			Reason:  sbe.Reason,
+		}
+	}
+
+	bl.mtx.Lock()
+	bl.bans = bans
+	bl.mtx.Unlock()
+
+	log.Debugf("Loaded %d bans from %s", len(bans), bl.filePath)
+	return nil
+}
+
+// save writes all unexpired bans to disk.  It does nothing when the ban list
+// is only kept in memory.
+//
+// This function MUST be called with the ban list mutex held.
+func (bl *BanList) save() error {
+	if bl.filePath == "" {
+		return nil
+	}
+
+	now := time.Now()
+	sbl := serializedBanList{
+		Version: banListVersion,
+		Bans:    make([]serializedBanEntry, 0, len(bl.bans)),
+	}
+	for _, entry := range bl.sortedEntries(now) {
+		sbl.Bans = append(sbl.Bans, serializedBanEntry{
+			Subnet:  entry.Subnet.String(),
+			Created: entry.Created.Unix(),
+			Expires: entry.Expires.Unix(),
+			Reason:  entry.Reason,
+		})
+	}
+	serialized, err := json.Marshal(&sbl)
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and then move it into place.
+	tmpFile := bl.filePath + ".new"
+	if err := os.WriteFile(tmpFile, serialized, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, bl.filePath)
+}
+
+// sortedEntries returns copies of all bans that have not expired as of the
+// provided time sorted by subnet.
+//
+// This function MUST be called with the ban list mutex held.
+func (bl *BanList) sortedEntries(now time.Time) []BanEntry {
+	entries := make([]BanEntry, 0, len(bl.bans))
+	for _, entry := range bl.bans {
+		if now.Before(entry.Expires) {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Subnet.String() < entries[j].Subnet.String()
+	})
+	return entries
+}
+
+// Ban bans the provided subnet until the provided expiration time and persists
+// the updated ban list.  ErrAlreadyBanned is returned when the subnet is
+// already banned and the existing ban is not replaced unless the replace flag
+// is set.
+func (bl *BanList) Ban(subnet *net.IPNet, expires time.Time, reason string, replace bool) error {
+	// Normalize the subnet so equivalent subnets map to the same key.
+	_, normalized, err := net.ParseCIDR(subnet.String())
+	if err != nil {
+		return err
+	}
+	key := normalized.String()
+
+	bl.mtx.Lock()
+	defer bl.mtx.Unlock()
+
+	if entry, ok := bl.bans[key]; ok && !replace &&
+		time.Now().Before(entry.Expires) {
+
+		return ErrAlreadyBanned
+	}
+	bl.bans[key] = &BanEntry{
+		Subnet:  *normalized,
+		Created: time.Now(),
+		Expires: expires,
+		Reason:  reason,
+	}
+	return bl.save()
+}
+
+// Unban removes the ban for the provided subnet and persists the updated ban
+// list.  ErrNotBanned is returned when the subnet is not banned.
+func (bl *BanList) Unban(subnet *net.IPNet) error {
+	_, normalized, err := net.ParseCIDR(subnet.String())
+	if err != nil {
+		return err
+	}
+	key := normalized.String()
+
+	bl.mtx.Lock()
+	defer bl.mtx.Unlock()
+
+	entry, ok := bl.bans[key]
+	if !ok || !time.Now().Before(entry.Expires) {
+		return ErrNotBanned
+	}
+	delete(bl.bans, key)
+	return bl.save()
+}
+
+// Clear removes all bans and persists the now empty ban list.
+func (bl *BanList) Clear() error {
+	bl.mtx.Lock()
+	defer bl.mtx.Unlock()
+
+	bl.bans = make(map[string]*BanEntry)
+	return bl.save()
+}
+
+// Lookup returns the ban that applies to the provided IP address, if any.
+// When multiple banned subnets contain the address, the ban that expires last
+// is returned.  Expired bans are removed.
+func (bl *BanList) Lookup(ip net.IP) (*BanEntry, bool) {
+	bl.mtx.Lock()
+	defer bl.mtx.Unlock()
+
+	now := time.Now()
+	var match *BanEntry
+	for key, entry := range bl.bans {
+		if !now.Before(entry.Expires) {
+			log.Infof("Ban for %s has expired", key)
+			delete(bl.bans, key)
+			continue
+		}
+		if entry.Subnet.Contains(ip) &&
+			(match == nil || entry.Expires.After(match.Expires)) {
+
+			match = entry
+		}
+	}
+	if match == nil {
+		return nil, false
+	}
+	entry := *match
+	return &entry, true
+}
+
+// Entries returns all bans that have not expired sorted by subnet.
+func (bl *BanList) Entries() []BanEntry {
+	bl.mtx.Lock()
+	entries := bl.sortedEntries(time.Now())
+	bl.mtx.Unlock()
+	return entries
+}
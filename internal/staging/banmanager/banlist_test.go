@@ -0,0 +1,131 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package banmanager
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestBanList ensures banning and unbanning subnets works as expected and that
+// the bans are persisted across instances.
+func TestBanList(t *testing.T) {
+	dataDir := t.TempDir()
+	bl := NewBanList(dataDir)
+	if err := bl.Load(); err != nil {
+		t.Fatalf("unexpected error loading missing ban list: %v", err)
+	}
+
+	// Ban a subnet and an individual host.
+	_, subnet, err := net.ParseCIDR("10.0.0.0/24")
+	if err != nil {
+		t.Fatalf("unable to parse subnet: %v", err)
+	}
+	expires := time.Now().Add(time.Hour)
+	if err := bl.Ban(subnet, expires, "subnet", false); err != nil {
+		t.Fatalf("unexpected error banning subnet: %v", err)
+	}
+	hostIP := net.ParseIP("2001:db8::1")
+	if err := bl.Ban(HostSubnet(hostIP), expires, "host", false); err != nil {
+		t.Fatalf("unexpected error banning host: %v", err)
+	}
+
+	// Ensure banning an already banned subnet fails unless it is replaced.
+	_, sameSubnet, _ := net.ParseCIDR("10.0.0.7/24")
+	err = bl.Ban(sameSubnet, expires, "again", false)
+	if !errors.Is(err, ErrAlreadyBanned) {
+		t.Fatalf("unexpected error banning subnet again -- got %v, want %v",
+			err, ErrAlreadyBanned)
+	}
+
+	// Ensure addresses are matched against the banned subnets.
+	tests := []struct {
+		ip     string
+		banned bool
+		reason string
+	}{
+		{ip: "10.0.0.1", banned: true, reason: "subnet"},
+		{ip: "10.0.0.255", banned: true, reason: "subnet"},
+		{ip: "10.0.1.1", banned: false},
+		{ip: "2001:db8::1", banned: true, reason: "host"},
+		{ip: "2001:db8::2", banned: false},
+	}
+	checkBans := func(bl *BanList) {
+		t.Helper()
+		for _, test := range tests {
+			entry, banned := bl.Lookup(net.ParseIP(test.ip))
+			if banned != test.banned {
+				t.Fatalf("%s: unexpected banned state -- got %v, want %v",
+					test.ip, banned, test.banned)
+			}
+			if banned && entry.Reason != test.reason {
+				t.Fatalf("%s: unexpected ban reason -- got %q, want %q",
+					test.ip, entry.Reason, test.reason)
+			}
+		}
+	}
+	checkBans(bl)
+
+	// Ensure the bans are restored by a new instance along with the same
+	// expiration time to the second.
+	bl2 := NewBanList(dataDir)
+	if err := bl2.Load(); err != nil {
+		t.Fatalf("unexpected error loading ban list: %v", err)
+	}
+	checkBans(bl2)
+	entries := bl2.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("unexpected number of restored bans -- got %d, want 2",
+			len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Expires.Unix() != expires.Unix() {
+			t.Fatalf("%s: unexpected expiration -- got %v, want %v",
+				&entry.Subnet, entry.Expires, expires)
+		}
+	}
+
+	// Ensure unbanning works and is persisted.
+	if err := bl2.Unban(subnet); err != nil {
+		t.Fatalf("unexpected error unbanning subnet: %v", err)
+	}
+	if err := bl2.Unban(subnet); !errors.Is(err, ErrNotBanned) {
+		t.Fatalf("unexpected error unbanning subnet again -- got %v, want "+
+			"%v", err, ErrNotBanned)
+	}
+	bl3 := NewBanList(dataDir)
+	if err := bl3.Load(); err != nil {
+		t.Fatalf("unexpected error loading ban list: %v", err)
+	}
+	if _, banned := bl3.Lookup(net.ParseIP("10.0.0.1")); banned {
+		t.Fatal("unbanned subnet was restored")
+	}
+
+	// Ensure expired bans are not reported.
+	_, expiredSubnet, _ := net.ParseCIDR("192.168.0.0/16")
+	err = bl3.Ban(expiredSubnet, time.Now().Add(-time.Second), "expired",
+		false)
+	if err != nil {
+		t.Fatalf("unexpected error banning subnet: %v", err)
+	}
+	if _, banned := bl3.Lookup(net.ParseIP("192.168.1.1")); banned {
+		t.Fatal("expired ban was reported")
+	}
+
+	// Ensure clearing the bans is persisted.
+	if err := bl3.Clear(); err != nil {
+		t.Fatalf("unexpected error clearing bans: %v", err)
+	}
+	bl4 := NewBanList(dataDir)
+	if err := bl4.Load(); err != nil {
+		t.Fatalf("unexpected error loading ban list: %v", err)
+	}
+	if entries := bl4.Entries(); len(entries) != 0 {
+		t.Fatalf("unexpected bans after clearing -- got %d, want 0",
+			len(entries))
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2023 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -34,6 +34,10 @@ type Config struct {
 
 	// Whitelist represents the whitelisted IPs of the server.
 	WhiteList []net.IPNet
+
+	// BanList houses the banned subnets.  A ban list that is only kept in
+	// memory is used when it is nil.
+	BanList *BanList
 }
 
 // banMgrPeer extends a peer to maintain additional state maintained by the
@@ -49,16 +53,20 @@ type banMgrPeer struct {
 type BanManager struct {
 	cfg    Config
 	peers  map[*peer.Peer]*banMgrPeer
-	banned map[string]time.Time
+	banned *BanList
 	mtx    sync.Mutex
 }
 
 // NewBanManager initializes a new peer banning manager.
 func NewBanManager(cfg *Config) *BanManager {
+	banned := cfg.BanList
+	if banned == nil {
+		banned = NewBanList("")
+	}
 	return &BanManager{
 		cfg:    *cfg,
 		peers:  make(map[*peer.Peer]*banMgrPeer, cfg.MaxPeers),
-		banned: make(map[string]time.Time, cfg.MaxPeers),
+		banned: banned,
 	}
 }
 
@@ -122,22 +130,12 @@ func (bm *BanManager) AddPeer(p *peer.Peer) error {
 		return fmt.Errorf("cannot split hostport %w", err)
 	}
 
-	bm.mtx.Lock()
-	banEnd, ok := bm.banned[host]
-	bm.mtx.Unlock()
-
-	if ok {
-		if time.Now().Before(banEnd) {
+	if ip := net.ParseIP(host); ip != nil {
+		if ban, ok := bm.banned.Lookup(ip); ok {
 			p.Disconnect()
-			return fmt.Errorf("peer %s is banned for another %v - disconnecting",
-				host, time.Until(banEnd))
+			return fmt.Errorf("peer %s is banned for another %v (%s) - "+
+				"disconnecting", host, time.Until(ban.Expires), ban.Reason)
 		}
-
-		log.Infof("Peer %s is no longer banned", host)
-
-		bm.mtx.Lock()
-		delete(bm.banned, host)
-		bm.mtx.Unlock()
 	}
 
 	bmp := &banMgrPeer{
@@ -159,8 +157,8 @@ func (bm *BanManager) RemovePeer(p *peer.Peer) {
 	bm.mtx.Unlock()
 }
 
-// BanPeer bans the provided peer.
-func (bm *BanManager) BanPeer(p *peer.Peer) {
+// BanPeer bans the provided peer for the provided reason.
+func (bm *BanManager) BanPeer(p *peer.Peer, reason string) {
 	// Return immediately if banning is disabled.
 	if bm.cfg.DisableBanning {
 		return
@@ -185,13 +183,21 @@ func (bm *BanManager) BanPeer(p *peer.Peer) {
 		return
 	}
 
+	ip := net.ParseIP(host)
+	if ip == nil {
+		log.Debugf("can't parse ban peer IP %s", host)
+		return
+	}
+
 	direction := directionString(p.Inbound())
 	log.Infof("Banned peer %s (%s) for %v", host, direction,
 		bm.cfg.BanDuration)
 
-	bm.mtx.Lock()
-	bm.banned[host] = time.Now().Add(bm.cfg.BanDuration)
-	bm.mtx.Unlock()
+	expires := time.Now().Add(bm.cfg.BanDuration)
+	err = bm.banned.Ban(HostSubnet(ip), expires, reason, true)
+	if err != nil {
+		log.Errorf("Unable to persist ban for peer %s: %v", host, err)
+	}
 
 	p.Disconnect()
 	bm.RemovePeer(p)
@@ -238,7 +244,7 @@ func (bm *BanManager) AddBanScore(p *peer.Peer, persistent, transient uint32, re
 			p, reason, banScore)
 		if banScore > bm.cfg.BanThreshold {
 			log.Warnf("Misbehaving peer %s -- banning and disconnecting", p)
-			bm.BanPeer(p)
+			bm.BanPeer(p, reason)
 			return true
 		}
 	}
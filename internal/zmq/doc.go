@@ -0,0 +1,38 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package zmq provides a publisher that pushes messages to subscribers using the
+ZeroMQ Message Transport Protocol (ZMTP) so that existing ZeroMQ SUB sockets
+are able to connect to it without any additional client software.
+
+Tests are included to ensure proper functionality.
+
+# Feature Overview
+
+The following are the primary features provided:
+
+  - A ZMTP 3 compatible PUB socket that serves subscribers over TCP and Unix
+    domain sockets using the NULL security mechanism
+  - Topic prefix subscriptions via both subscription messages and the
+    SUBSCRIBE and CANCEL commands
+  - Multipart messages made up of the topic, the body, and a per-topic
+    4-byte little-endian sequence number
+  - Bounded per-subscriber send queues that drop messages for subscribers that
+    are not keeping up instead of blocking the caller
+  - Parsing of tcp:// and ipc:// endpoints
+
+# Topics
+
+The following topics are defined for use by callers:
+
+  - rawblock: the serialized bytes of a block connected to the main chain
+  - hashblock: the hash of a block connected to the main chain
+  - rawtx: the serialized bytes of a transaction accepted to the mempool or
+    connected to the main chain
+  - hashtx: the hash of a transaction accepted to the mempool or connected to
+    the main chain
+  - blockdisconnected: the hash of a block disconnected from the main chain
+*/
+package zmq
@@ -0,0 +1,438 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package zmq
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// These constants define the topics that are published.
+const (
+	// TopicRawBlock is the topic for the serialized bytes of blocks that are
+	// connected to the main chain.
+	TopicRawBlock = "rawblock"
+
+	// TopicHashBlock is the topic for the hashes of blocks that are connected
+	// to the main chain.
+	TopicHashBlock = "hashblock"
+
+	// TopicRawTx is the topic for the serialized bytes of transactions that
+	// are accepted to the mempool or connected to the main chain.
+	TopicRawTx = "rawtx"
+
+	// TopicHashTx is the topic for the hashes of transactions that are
+	// accepted to the mempool or connected to the main chain.
+	TopicHashTx = "hashtx"
+
+	// TopicBlockDisconnected is the topic for the hashes of blocks that are
+	// disconnected from the main chain.
+	TopicBlockDisconnected = "blockdisconnected"
+)
+
+const (
+	// handshakeTimeout is the maximum amount of time allowed for a new
+	// subscriber to complete the protocol handshake.
+	handshakeTimeout = time.Second * 10
+
+	// sendQueueSize is the maximum number of messages that are queued for a
+	// subscriber before further messages are dropped.  This is the same as
+	// the default high water mark of ZeroMQ sockets.
+	sendQueueSize = 1000
+)
+
+// Config is a descriptor containing the publisher configuration.
+type Config struct {
+	// Listeners defines a slice of listeners for which the publisher will
+	// take ownership of and accept subscriber connections.  Since the
+	// publisher takes ownership of these listeners, they will be closed when
+	// the publisher is stopped.
+	Listeners []net.Listener
+}
+
+// subscriber houses the state of a connected subscriber.
+type subscriber struct {
+	conn      net.Conn
+	sendQueue chan []byte
+
+	// topics tracks the topic prefixes the subscriber is subscribed to.  ZMQ
+	// subscriptions are counted, so each prefix maps to the number of times
+	// it has been subscribed.
+	topicsMtx sync.Mutex
+	topics    map[string]int
+}
+
+// subscribe adds a subscription to all topics with the provided prefix.
+func (s *subscriber) subscribe(prefix []byte) {
+	s.topicsMtx.Lock()
+	s.topics[string(prefix)]++
+	s.topicsMtx.Unlock()
+	log.Debugf("Subscriber %s subscribed to %q", s.conn.RemoteAddr(), prefix)
+}
+
+// unsubscribe removes a subscription to all topics with the provided prefix.
+func (s *subscriber) unsubscribe(prefix []byte) {
+	s.topicsMtx.Lock()
+	if n := s.topics[string(prefix)]; n > 1 {
+		s.topics[string(prefix)] = n - 1
+	} else {
+		delete(s.topics, string(prefix))
+	}
+	s.topicsMtx.Unlock()
+	log.Debugf("Subscriber %s unsubscribed from %q", s.conn.RemoteAddr(),
+		prefix)
+}
+
+// isSubscribed returns whether or not the subscriber is subscribed to the
+// provided topic.
+func (s *subscriber) isSubscribed(topic string) bool {
+	s.topicsMtx.Lock()
+	defer s.topicsMtx.Unlock()
+	for prefix := range s.topics {
+		if strings.HasPrefix(topic, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// queue attempts to add the provided encoded data to the send queue of the
+// subscriber and returns whether or not it was added.  The data is dropped
+// when the queue is full.
+func (s *subscriber) queue(data []byte) bool {
+	select {
+	case s.sendQueue <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeHandler writes all queued data to the subscriber until the provided
+// quit channel is closed or writing fails.
+//
+// This must be run as a goroutine.
+func (s *subscriber) writeHandler(quit <-chan struct{}) {
+	for {
+		select {
+		case data := <-s.sendQueue:
+			if _, err := s.conn.Write(data); err != nil {
+				log.Debugf("Failed to write to subscriber %s: %v",
+					s.conn.RemoteAddr(), err)
+				s.conn.Close()
+				return
+			}
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// readHandler reads and handles the subscriptions and commands sent by the
+// subscriber until reading fails.
+func (s *subscriber) readHandler(r io.Reader) error {
+	var inMultipart bool
+	for {
+		f, err := readFrame(r)
+		if err != nil {
+			return err
+		}
+
+		if f.isCommand() {
+			name, data, err := parseCommand(f.body)
+			if err != nil {
+				return err
+			}
+			switch name {
+			case cmdSubscribe:
+				s.subscribe(data)
+
+			case cmdCancel:
+				s.unsubscribe(data)
+
+			case cmdPing:
+				// Reply with the context provided after the two byte TTL.
+				var pingCtx []byte
+				if len(data) > 2 {
+					pingCtx = data[2:]
+				}
+				s.queue(encodeCommand(cmdPong, pingCtx))
+			}
+			continue
+		}
+
+		// Subscriptions are single frame messages where the first byte
+		// indicates whether the remaining bytes are a prefix to subscribe to
+		// or unsubscribe from.  Any other messages are ignored.
+		if inMultipart || f.more() {
+			inMultipart = f.more()
+			continue
+		}
+		if len(f.body) == 0 {
+			continue
+		}
+		switch f.body[0] {
+		case 1:
+			s.subscribe(f.body[1:])
+		case 0:
+			s.unsubscribe(f.body[1:])
+		}
+	}
+}
+
+// Publisher provides a ZeroMQ PUB socket compatible server that publishes
+// messages to all subscribers of their topic.  Every message is a multipart
+// message made up of the topic, the body, and a 4-byte little-endian sequence
+// number that is incremented independently for each topic.
+type Publisher struct {
+	cfg Config
+	wg  sync.WaitGroup
+
+	// The following fields are protected by the mutex.
+	mtx         sync.Mutex
+	sequences   map[string]uint32
+	subscribers map[*subscriber]struct{}
+	quit        bool
+}
+
+// NewPublisher returns a new publisher that serves subscribers on the
+// listeners in the provided config.  Use Run to begin accepting subscribers.
+func NewPublisher(cfg *Config) *Publisher {
+	return &Publisher{
+		cfg:         *cfg,
+		sequences:   make(map[string]uint32),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish sends a message with the provided topic and body to all subscribers
+// of the topic.  The message is dropped for any subscribers that are not
+// keeping up.
+//
+// This function is safe for concurrent access.
+func (p *Publisher) Publish(topic string, body []byte) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	seq := p.sequences[topic]
+	p.sequences[topic] = seq + 1
+
+	var msg []byte
+	for sub := range p.subscribers {
+		if !sub.isSubscribed(topic) {
+			continue
+		}
+		if msg == nil {
+			var seqBytes [4]byte
+			binary.LittleEndian.PutUint32(seqBytes[:], seq)
+			msg = encodeMessage([]byte(topic), body, seqBytes[:])
+		}
+		if !sub.queue(msg) {
+			log.Debugf("Dropping %s message %d for slow subscriber %s",
+				topic, seq, sub.conn.RemoteAddr())
+		}
+	}
+}
+
+// handshake performs the protocol handshake with a new subscriber and returns
+// an error if it fails or the peer is not a subscriber.
+func handshake(conn net.Conn, r io.Reader) error {
+	if _, err := conn.Write(greeting()); err != nil {
+		return err
+	}
+	peerGreeting := make([]byte, greetingLen)
+	if _, err := io.ReadFull(r, peerGreeting); err != nil {
+		return err
+	}
+	if err := checkGreeting(peerGreeting); err != nil {
+		return err
+	}
+
+	ready := encodeReady(map[string]string{propSocketType: "PUB"})
+	if _, err := conn.Write(ready); err != nil {
+		return err
+	}
+	f, err := readFrame(r)
+	if err != nil {
+		return err
+	}
+	if !f.isCommand() {
+		return errors.New("expected READY command")
+	}
+	name, data, err := parseCommand(f.body)
+	if err != nil {
+		return err
+	}
+	if name != cmdReady {
+		return fmt.Errorf("expected READY command, got %q", name)
+	}
+	props, err := parseProperties(data)
+	if err != nil {
+		return err
+	}
+
+	// Property names are case insensitive.
+	var socketType string
+	for name, value := range props {
+		if strings.EqualFold(name, propSocketType) {
+			socketType = value
+		}
+	}
+	if socketType != "SUB" && socketType != "XSUB" {
+		reason := "invalid socket type"
+		errCmd := encodeCommand(cmdError, append([]byte{byte(len(reason))},
+			reason...))
+		conn.Write(errCmd)
+		return fmt.Errorf("incompatible socket type %q", socketType)
+	}
+	return nil
+}
+
+// handleConn performs the handshake with the provided connection and serves it
+// as a subscriber until it disconnects or the publisher is stopped.
+//
+// This must be run as a goroutine.
+func (p *Publisher) handleConn(conn net.Conn) {
+	defer p.wg.Done()
+	defer conn.Close()
+
+	// Track the subscriber before performing the handshake so it is
+	// disconnected when the publisher is stopped during the handshake.
+	sub := &subscriber{
+		conn:      conn,
+		sendQueue: make(chan []byte, sendQueueSize),
+		topics:    make(map[string]int),
+	}
+	p.mtx.Lock()
+	if p.quit {
+		p.mtx.Unlock()
+		return
+	}
+	p.subscribers[sub] = struct{}{}
+	p.mtx.Unlock()
+	defer func() {
+		p.mtx.Lock()
+		delete(p.subscribers, sub)
+		p.mtx.Unlock()
+	}()
+
+	r := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err := handshake(conn, r); err != nil {
+		log.Debugf("Handshake with %s failed: %v", conn.RemoteAddr(), err)
+		return
+	}
+	conn.SetDeadline(time.Time{})
+	log.Infof("New subscriber %s", conn.RemoteAddr())
+
+	quit := make(chan struct{})
+	writerDone := make(chan struct{})
+	go func() {
+		sub.writeHandler(quit)
+		close(writerDone)
+	}()
+	err := sub.readHandler(r)
+	close(quit)
+	conn.Close()
+	<-writerDone
+
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+		log.Infof("Subscriber %s disconnected", conn.RemoteAddr())
+	} else {
+		log.Infof("Subscriber %s disconnected: %v", conn.RemoteAddr(), err)
+	}
+}
+
+// acceptConns accepts subscriber connections on the provided listener until
+// it is closed.
+//
+// This must be run as a goroutine.
+func (p *Publisher) acceptConns(listener net.Listener) {
+	defer p.wg.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Errorf("Failed to accept subscriber on %s: %v",
+					listener.Addr(), err)
+			}
+			return
+		}
+
+		p.wg.Add(1)
+		go p.handleConn(conn)
+	}
+}
+
+// Run starts the publisher and its listeners.  It blocks until the provided
+// context is cancelled.
+func (p *Publisher) Run(ctx context.Context) {
+	for _, listener := range p.cfg.Listeners {
+		log.Infof("ZMQ publisher listening on %s", listener.Addr())
+		p.wg.Add(1)
+		go p.acceptConns(listener)
+	}
+
+	<-ctx.Done()
+
+	// Close all listeners and subscriber connections and wait for all
+	// goroutines to terminate.
+	for _, listener := range p.cfg.Listeners {
+		if err := listener.Close(); err != nil {
+			log.Errorf("Failed to close listener %s: %v", listener.Addr(),
+				err)
+		}
+	}
+	p.mtx.Lock()
+	p.quit = true
+	for sub := range p.subscribers {
+		sub.conn.Close()
+	}
+	p.mtx.Unlock()
+	p.wg.Wait()
+	log.Info("ZMQ publisher stopped")
+}
+
+// ParseEndpoint parses the provided ZeroMQ style endpoint and returns the
+// network and address to listen on.  Supported endpoints are of the form
+// tcp://host:port, where a host of * listens on all interfaces, and
+// ipc://path for Unix domain sockets.
+func ParseEndpoint(endpoint string) (string, string, error) {
+	transport, addr, ok := strings.Cut(endpoint, "://")
+	if !ok || addr == "" {
+		return "", "", fmt.Errorf("malformed endpoint %q", endpoint)
+	}
+	switch transport {
+	case "tcp":
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return "", "", fmt.Errorf("malformed endpoint %q: %w", endpoint,
+				err)
+		}
+		if port == "" {
+			return "", "", fmt.Errorf("endpoint %q is missing a port",
+				endpoint)
+		}
+		if host == "*" {
+			host = ""
+		}
+		return "tcp", net.JoinHostPort(host, port), nil
+
+	case "ipc":
+		return "unix", addr, nil
+	}
+	return "", "", fmt.Errorf("unsupported transport %q in endpoint %q "+
+		"(supported: tcp, ipc)", transport, endpoint)
+}
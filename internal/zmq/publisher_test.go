@@ -0,0 +1,360 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package zmq
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestParseEndpoint ensures endpoints are parsed into the expected network and
+// address or rejected as expected.
+func TestParseEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		network  string
+		addr     string
+		wantErr  bool
+	}{{
+		name:     "tcp ipv4",
+		endpoint: "tcp://127.0.0.1:28332",
+		network:  "tcp",
+		addr:     "127.0.0.1:28332",
+	}, {
+		name:     "tcp ipv6",
+		endpoint: "tcp://[::1]:28332",
+		network:  "tcp",
+		addr:     "[::1]:28332",
+	}, {
+		name:     "tcp all interfaces",
+		endpoint: "tcp://*:28332",
+		network:  "tcp",
+		addr:     ":28332",
+	}, {
+		name:     "ipc",
+		endpoint: "ipc:///tmp/dcrd.zmq",
+		network:  "unix",
+		addr:     "/tmp/dcrd.zmq",
+	}, {
+		name:     "tcp missing port",
+		endpoint: "tcp://127.0.0.1",
+		wantErr:  true,
+	}, {
+		name:     "tcp empty port",
+		endpoint: "tcp://127.0.0.1:",
+		wantErr:  true,
+	}, {
+		name:     "missing transport",
+		endpoint: "127.0.0.1:28332",
+		wantErr:  true,
+	}, {
+		name:     "missing address",
+		endpoint: "ipc://",
+		wantErr:  true,
+	}, {
+		name:     "unsupported transport",
+		endpoint: "udp://127.0.0.1:28332",
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		network, addr, err := ParseEndpoint(test.endpoint)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if network != test.network || addr != test.addr {
+			t.Errorf("%q: unexpected result -- got %s %s, want %s %s",
+				test.name, network, addr, test.network, test.addr)
+		}
+	}
+}
+
+// TestFrames ensures frames are encoded and decoded as expected for both the
+// short and long forms and that oversized inbound frames are rejected.
+func TestFrames(t *testing.T) {
+	t.Parallel()
+
+	short := bytes.Repeat([]byte{0x01}, 255)
+	long := bytes.Repeat([]byte{0x02}, 256)
+	msg := encodeMessage(short, long)
+	wantLen := 2 + len(short) + 9 + len(long)
+	if len(msg) != wantLen {
+		t.Fatalf("unexpected encoded length -- got %d, want %d", len(msg),
+			wantLen)
+	}
+
+	r := bytes.NewReader(msg)
+	f, err := readFrame(r)
+	if err != nil {
+		t.Fatalf("unexpected error reading first frame: %v", err)
+	}
+	if !f.more() || f.isCommand() || !bytes.Equal(f.body, short) {
+		t.Fatalf("unexpected first frame: flags %x, len %d", f.flags,
+			len(f.body))
+	}
+	f, err = readFrame(r)
+	if err != nil {
+		t.Fatalf("unexpected error reading second frame: %v", err)
+	}
+	if f.more() || f.flags&flagLong == 0 || !bytes.Equal(f.body, long) {
+		t.Fatalf("unexpected second frame: flags %x, len %d", f.flags,
+			len(f.body))
+	}
+
+	// Ensure oversized frames are rejected.
+	huge := appendFrame(nil, 0, make([]byte, maxInboundFrameLen+1))
+	_, err = readFrame(bytes.NewReader(huge))
+	if !errors.Is(err, errFrameTooLarge) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, errFrameTooLarge)
+	}
+
+	// Ensure commands and their properties round trip.
+	f, err = readFrame(bytes.NewReader(encodeReady(map[string]string{
+		propSocketType: "PUB",
+	})))
+	if err != nil {
+		t.Fatalf("unexpected error reading command: %v", err)
+	}
+	name, data, err := parseCommand(f.body)
+	if err != nil || !f.isCommand() || name != cmdReady {
+		t.Fatalf("unexpected command %q (err: %v)", name, err)
+	}
+	props, err := parseProperties(data)
+	if err != nil {
+		t.Fatalf("unexpected error parsing properties: %v", err)
+	}
+	if len(props) != 1 || props[propSocketType] != "PUB" {
+		t.Fatalf("unexpected properties: %v", props)
+	}
+	if _, err := parseProperties([]byte{0x05, 'a'}); err == nil {
+		t.Fatal("did not receive expected error for malformed properties")
+	}
+}
+
+// testSubscriber is a minimal subscriber used to test the publisher.
+type testSubscriber struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialTestSubscriber connects to the provided address and performs the
+// handshake with the provided socket type.
+func dialTestSubscriber(t *testing.T, addr net.Addr, socketType string) *testSubscriber {
+	t.Helper()
+
+	conn, err := net.Dial(addr.Network(), addr.String())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(time.Second * 5))
+	s := &testSubscriber{t: t, conn: conn, r: bufio.NewReader(conn)}
+
+	s.write(greeting())
+	peerGreeting := make([]byte, greetingLen)
+	if _, err := io.ReadFull(s.r, peerGreeting); err != nil {
+		t.Fatalf("failed to read greeting: %v", err)
+	}
+	if err := checkGreeting(peerGreeting); err != nil {
+		t.Fatalf("invalid greeting: %v", err)
+	}
+	s.write(encodeReady(map[string]string{"socket-type": socketType}))
+	name, data := s.readCommand()
+	if name != cmdReady {
+		t.Fatalf("unexpected command -- got %q, want %q", name, cmdReady)
+	}
+	props, err := parseProperties(data)
+	if err != nil {
+		t.Fatalf("failed to parse properties: %v", err)
+	}
+	if props[propSocketType] != "PUB" {
+		t.Fatalf("unexpected socket type %q", props[propSocketType])
+	}
+	return s
+}
+
+// write writes the provided data to the publisher.
+func (s *testSubscriber) write(data []byte) {
+	s.t.Helper()
+	if _, err := s.conn.Write(data); err != nil {
+		s.t.Fatalf("failed to write: %v", err)
+	}
+}
+
+// readCommand reads a command frame from the publisher.
+func (s *testSubscriber) readCommand() (string, []byte) {
+	s.t.Helper()
+	f, err := readFrame(s.r)
+	if err != nil {
+		s.t.Fatalf("failed to read frame: %v", err)
+	}
+	if !f.isCommand() {
+		s.t.Fatalf("expected command frame")
+	}
+	name, data, err := parseCommand(f.body)
+	if err != nil {
+		s.t.Fatalf("failed to parse command: %v", err)
+	}
+	return name, data
+}
+
+// readMessage reads a message from the publisher and ensures it has the
+// expected topic, body, and sequence number.
+func (s *testSubscriber) readMessage(topic string, body []byte, seq uint32) {
+	s.t.Helper()
+	var parts [][]byte
+	for {
+		f, err := readFrame(s.r)
+		if err != nil {
+			s.t.Fatalf("failed to read frame: %v", err)
+		}
+		parts = append(parts, f.body)
+		if !f.more() {
+			break
+		}
+	}
+	if len(parts) != 3 {
+		s.t.Fatalf("unexpected number of message parts %d", len(parts))
+	}
+	if string(parts[0]) != topic {
+		s.t.Fatalf("unexpected topic -- got %q, want %q", parts[0], topic)
+	}
+	if !bytes.Equal(parts[1], body) {
+		s.t.Fatalf("unexpected body -- got %x, want %x", parts[1], body)
+	}
+	if len(parts[2]) != 4 || binary.LittleEndian.Uint32(parts[2]) != seq {
+		s.t.Fatalf("unexpected sequence -- got %x, want %d", parts[2], seq)
+	}
+}
+
+// waitForSubscriptions waits until the publisher has a single subscriber that
+// is subscribed to exactly the provided prefixes.
+func waitForSubscriptions(t *testing.T, p *Publisher, prefixes ...string) {
+	t.Helper()
+	for i := 0; i < 500; i++ {
+		p.mtx.Lock()
+		done := len(p.subscribers) == 1
+		for sub := range p.subscribers {
+			sub.topicsMtx.Lock()
+			done = done && len(sub.topics) == len(prefixes)
+			for _, prefix := range prefixes {
+				done = done && sub.topics[prefix] > 0
+			}
+			sub.topicsMtx.Unlock()
+		}
+		p.mtx.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatalf("timeout waiting for subscriptions to %q", prefixes)
+}
+
+// TestPublisher ensures the publisher performs the handshake with subscribers,
+// delivers messages for subscribed topics with per-topic sequence numbers,
+// honors unsubscriptions, answers pings, and rejects incompatible sockets.
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	p := NewPublisher(&Config{Listeners: []net.Listener{listener}})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Subscribe to the hash topics with a ZMTP 3.0 style subscription message
+	// and to the raw block topic with a ZMTP 3.1 style command.
+	sub := dialTestSubscriber(t, listener.Addr(), "SUB")
+	sub.write(encodeMessage(append([]byte{1}, "hash"...)))
+	sub.write(encodeCommand(cmdSubscribe, []byte(TopicRawBlock)))
+	waitForSubscriptions(t, p, "hash", TopicRawBlock)
+
+	hash1 := bytes.Repeat([]byte{0x11}, 32)
+	hash2 := bytes.Repeat([]byte{0x22}, 32)
+	p.Publish(TopicRawTx, []byte{0x01})
+	p.Publish(TopicHashTx, hash1)
+	p.Publish(TopicRawTx, []byte{0x02})
+	p.Publish(TopicHashTx, hash2)
+	p.Publish(TopicHashBlock, hash1)
+	p.Publish(TopicRawBlock, []byte{0x03})
+	sub.readMessage(TopicHashTx, hash1, 0)
+	sub.readMessage(TopicHashTx, hash2, 1)
+	sub.readMessage(TopicHashBlock, hash1, 0)
+	sub.readMessage(TopicRawBlock, []byte{0x03}, 0)
+
+	// Ensure pings are answered with the provided context.
+	sub.write(encodeCommand(cmdPing, []byte{0x00, 0x0a, 'c', 't', 'x'}))
+	name, data := sub.readCommand()
+	if name != cmdPong || string(data) != "ctx" {
+		t.Fatalf("unexpected pong -- got %q %q", name, data)
+	}
+
+	// Ensure messages are no longer delivered for topics that are
+	// unsubscribed with both styles.
+	sub.write(encodeMessage(append([]byte{0}, "hash"...)))
+	sub.write(encodeCommand(cmdCancel, []byte(TopicRawBlock)))
+	sub.write(encodeMessage(append([]byte{1}, TopicBlockDisconnected...)))
+	waitForSubscriptions(t, p, TopicBlockDisconnected)
+	p.Publish(TopicHashTx, hash1)
+	p.Publish(TopicRawBlock, []byte{0x04})
+	p.Publish(TopicBlockDisconnected, hash2)
+	sub.readMessage(TopicBlockDisconnected, hash2, 0)
+
+	// Ensure sockets other than subscribers are rejected.
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second * 5))
+	r := bufio.NewReader(conn)
+	conn.Write(greeting())
+	conn.Write(encodeReady(map[string]string{propSocketType: "PUB"}))
+	if _, err := io.ReadFull(r, make([]byte, greetingLen)); err != nil {
+		t.Fatalf("failed to read greeting: %v", err)
+	}
+	for _, want := range []string{cmdReady, cmdError} {
+		f, err := readFrame(r)
+		if err != nil {
+			t.Fatalf("failed to read frame: %v", err)
+		}
+		name, _, _ := parseCommand(f.body)
+		if name != want {
+			t.Fatalf("unexpected command -- got %q, want %q", name, want)
+		}
+	}
+	if _, err := readFrame(r); !errors.Is(err, io.EOF) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, io.EOF)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package zmq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// This file implements the subset of the ZeroMQ Message Transport Protocol
+// (ZMTP) version 3 that is required to serve subscribers as a PUB socket with
+// the NULL security mechanism.  See https://rfc.zeromq.org/spec/23/ and
+// https://rfc.zeromq.org/spec/37/ for the full specifications.
+
+const (
+	// greetingLen is the length of the greeting each peer sends when a
+	// connection is established.
+	greetingLen = 64
+
+	// zmtpMajorVersion and zmtpMinorVersion are the version of the protocol
+	// advertised in the greeting.  Version 3.0 is advertised so peers that
+	// support newer revisions fall back to sending subscriptions as messages,
+	// although subscription commands are also accepted.
+	zmtpMajorVersion = 3
+	zmtpMinorVersion = 0
+
+	// mechanismNull is the name of the security mechanism that performs no
+	// authentication or encryption.
+	mechanismNull = "NULL"
+
+	// These flags are set in the first byte of every frame.
+	flagMore    = 0x01
+	flagLong    = 0x02
+	flagCommand = 0x04
+
+	// maxInboundFrameLen is the maximum size of a frame read from a
+	// subscriber.  Subscribers only send commands and subscriptions, so there
+	// is no reason for them to be large.
+	maxInboundFrameLen = 4096
+
+	// These are the names of the commands that are sent and received.
+	cmdReady     = "READY"
+	cmdError     = "ERROR"
+	cmdSubscribe = "SUBSCRIBE"
+	cmdCancel    = "CANCEL"
+	cmdPing      = "PING"
+	cmdPong      = "PONG"
+
+	// propSocketType is the name of the READY command metadata property that
+	// specifies the type of socket.
+	propSocketType = "Socket-Type"
+)
+
+var (
+	// errInvalidGreeting is returned when a peer sends a greeting that is not
+	// valid for this implementation.
+	errInvalidGreeting = errors.New("invalid greeting")
+
+	// errFrameTooLarge is returned when a peer sends a frame that exceeds the
+	// maximum allowed size.
+	errFrameTooLarge = errors.New("frame too large")
+)
+
+// greeting returns the greeting that is sent to subscribers.
+func greeting() []byte {
+	var g [greetingLen]byte
+	g[0] = 0xff
+	g[9] = 0x7f
+	g[10] = zmtpMajorVersion
+	g[11] = zmtpMinorVersion
+	copy(g[12:32], mechanismNull)
+	return g[:]
+}
+
+// checkGreeting ensures the provided greeting received from a peer is for a
+// compatible protocol version that uses the NULL security mechanism.
+func checkGreeting(g []byte) error {
+	if len(g) != greetingLen || g[0] != 0xff || g[9] != 0x7f {
+		return fmt.Errorf("%w: bad signature", errInvalidGreeting)
+	}
+	if g[10] < zmtpMajorVersion {
+		return fmt.Errorf("%w: unsupported version %d.%d", errInvalidGreeting,
+			g[10], g[11])
+	}
+	mechanism := string(bytes.TrimRight(g[12:32], "\x00"))
+	if mechanism != mechanismNull {
+		return fmt.Errorf("%w: unsupported mechanism %q", errInvalidGreeting,
+			mechanism)
+	}
+	return nil
+}
+
+// frame describes a single ZMTP frame.
+type frame struct {
+	flags byte
+	body  []byte
+}
+
+// isCommand returns whether or not the frame is a command frame.
+func (f *frame) isCommand() bool {
+	return f.flags&flagCommand != 0
+}
+
+// more returns whether or not more frames follow as part of the same message.
+func (f *frame) more() bool {
+	return f.flags&flagMore != 0
+}
+
+// appendFrame appends the encoding of a frame with the provided flags and body
+// to the provided buffer and returns the result.
+func appendFrame(buf []byte, flags byte, body []byte) []byte {
+	if len(body) > 255 {
+		buf = append(buf, flags|flagLong)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(len(body)))
+	} else {
+		buf = append(buf, flags, byte(len(body)))
+	}
+	return append(buf, body...)
+}
+
+// encodeMessage returns the encoding of a multipart message made up of the
+// provided parts.
+func encodeMessage(parts ...[]byte) []byte {
+	var size int
+	for _, part := range parts {
+		size += 9 + len(part)
+	}
+	buf := make([]byte, 0, size)
+	for i, part := range parts {
+		var flags byte
+		if i < len(parts)-1 {
+			flags = flagMore
+		}
+		buf = appendFrame(buf, flags, part)
+	}
+	return buf
+}
+
+// encodeCommand returns the encoding of a command frame with the provided name
+// and data.
+func encodeCommand(name string, data []byte) []byte {
+	body := make([]byte, 0, 1+len(name)+len(data))
+	body = append(body, byte(len(name)))
+	body = append(body, name...)
+	body = append(body, data...)
+	return appendFrame(nil, flagCommand, body)
+}
+
+// encodeReady returns the encoding of a READY command with the provided
+// metadata properties.
+func encodeReady(props map[string]string) []byte {
+	var data []byte
+	for name, value := range props {
+		data = append(data, byte(len(name)))
+		data = append(data, name...)
+		data = binary.BigEndian.AppendUint32(data, uint32(len(value)))
+		data = append(data, value...)
+	}
+	return encodeCommand(cmdReady, data)
+}
+
+// readFrame reads a single frame from the provided reader.  An error is
+// returned when the frame exceeds the maximum allowed inbound frame size.
+func readFrame(r io.Reader) (*frame, error) {
+	var hdr [9]byte
+	if _, err := io.ReadFull(r, hdr[:2]); err != nil {
+		return nil, err
+	}
+	flags := hdr[0]
+	size := uint64(hdr[1])
+	if flags&flagLong != 0 {
+		if _, err := io.ReadFull(r, hdr[2:9]); err != nil {
+			return nil, err
+		}
+		size = binary.BigEndian.Uint64(hdr[1:9])
+	}
+	if size > maxInboundFrameLen {
+		return nil, fmt.Errorf("%w: %d bytes", errFrameTooLarge, size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return &frame{flags: flags, body: body}, nil
+}
+
+// parseCommand splits the body of a command frame into the command name and
+// data.
+func parseCommand(body []byte) (string, []byte, error) {
+	if len(body) < 1 || int(body[0]) > len(body)-1 {
+		return "", nil, errors.New("malformed command")
+	}
+	nameLen := int(body[0])
+	return string(body[1 : 1+nameLen]), body[1+nameLen:], nil
+}
+
+// parseProperties parses the metadata properties of a READY command.
+func parseProperties(data []byte) (map[string]string, error) {
+	props := make(map[string]string)
+	for len(data) > 0 {
+		nameLen := int(data[0])
+		if len(data) < 1+nameLen+4 {
+			return nil, errors.New("malformed property name")
+		}
+		name := string(data[1 : 1+nameLen])
+		data = data[1+nameLen:]
+		valueLen := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if uint64(len(data)) < uint64(valueLen) {
+			return nil, errors.New("malformed property value")
+		}
+		props[name] = string(data[:valueLen])
+		data = data[valueLen:]
+	}
+	return props, nil
+}
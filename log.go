@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2017 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -22,6 +22,8 @@ import (
 	"github.com/decred/dcrd/internal/mining/cpuminer"
 	"github.com/decred/dcrd/internal/netsync"
 	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/internal/staging/banmanager"
+	"github.com/decred/dcrd/internal/zmq"
 	"github.com/decred/dcrd/mixing/mixpool"
 	"github.com/decred/dcrd/peer/v3"
 	"github.com/decred/dcrd/txscript/v4"
@@ -78,11 +80,13 @@ var (
 	syncLog = backendLog.Logger("SYNC")
 	txmpLog = backendLog.Logger("TXMP")
 	trsyLog = backendLog.Logger("TRSY")
+	zmqpLog = backendLog.Logger("ZMQP")
 )
 
 // Initialize package-global logger variables.
 func init() {
 	addrmgr.UseLogger(amgrLog)
+	banmanager.UseLogger(srvrLog)
 	blockchain.UseLogger(chanLog)
 	blockchain.UseTreasuryLogger(trsyLog)
 	connmgr.UseLogger(cmgrLog)
@@ -98,6 +102,7 @@ func init() {
 	stake.UseLogger(stkeLog)
 	netsync.UseLogger(syncLog)
 	txscript.UseLogger(scrpLog)
+	zmq.UseLogger(zmqpLog)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -121,6 +126,7 @@ var subsystemLoggers = map[string]slog.Logger{
 	"SYNC": syncLog,
 	"TXMP": txmpLog,
 	"TRSY": trsyLog,
+	"ZMQP": zmqpLog,
 }
 
 // initLogRotator initializes the logging rotater to write logs to logFile and
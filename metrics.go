@@ -0,0 +1,329 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/metrics"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+// mempoolFeeRateBuckets are the histogram buckets, in atoms/kB, used for the
+// fee rates of the transactions in the mempool.
+var mempoolFeeRateBuckets = []float64{1e4, 2e4, 5e4, 1e5, 2e5, 5e5, 1e6,
+	5e6, 1e7}
+
+// boolToFloat returns 1 for true and 0 for false.
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// gauge returns a metric family with a single gauge sample of the provided
+// value.
+func gauge(name, help string, value float64) *metrics.Family {
+	return &metrics.Family{
+		Name:    name,
+		Help:    help,
+		Type:    metrics.GaugeType,
+		Samples: []metrics.Sample{{Value: value}},
+	}
+}
+
+// counter returns a metric family with a single counter sample of the provided
+// value.
+func counter(name, help string, value float64) *metrics.Family {
+	return &metrics.Family{
+		Name:    name,
+		Help:    help,
+		Type:    metrics.CounterType,
+		Samples: []metrics.Sample{{Value: value}},
+	}
+}
+
+// serverMetrics houses the metrics about the server and its subsystems that
+// are served by the metrics server.
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	// These fields are updated as the associated events happen as opposed to
+	// the remaining metrics which are calculated on demand.
+	rpcRequests     *metrics.CounterVec
+	rpcDurations    *metrics.HistogramVec
+	blockValidation *metrics.Histogram
+}
+
+// newServerMetrics returns a new instance of the metrics about the server and
+// its subsystems.  The metrics that are calculated on demand are registered
+// separately via registerCollectors once the server is created.
+func newServerMetrics() *serverMetrics {
+	m := &serverMetrics{
+		registry: metrics.NewRegistry(),
+		rpcRequests: metrics.NewCounterVec("dcrd_rpc_requests_total",
+			"Total number of RPC requests processed by method and result.",
+			"method", "result"),
+		rpcDurations: metrics.NewHistogramVec(
+			"dcrd_rpc_request_duration_seconds",
+			"Time taken to process RPC requests by method.",
+			metrics.DefaultDurationBuckets, "method"),
+		blockValidation: metrics.NewHistogram(
+			"dcrd_block_validation_duration_seconds",
+			"Time taken to validate and connect blocks accepted by the chain.",
+			metrics.DefaultDurationBuckets),
+	}
+	m.registry.Register(m.rpcRequests, m.rpcDurations, m.blockValidation)
+	return m
+}
+
+// rpcRequestProcessed updates the RPC metrics for a request with the provided
+// method, processing time, and resulting error.
+//
+// This function is safe for concurrent access.
+func (m *serverMetrics) rpcRequestProcessed(method types.Method, elapsed time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.rpcRequests.With(string(method), result).Inc()
+	m.rpcDurations.With(string(method)).Observe(elapsed.Seconds())
+}
+
+// blockProcessed updates the block validation metrics for a block that took
+// the provided amount of time to validate and connect.
+//
+// This function is safe for concurrent access.
+func (m *serverMetrics) blockProcessed(elapsed time.Duration) {
+	m.blockValidation.Observe(elapsed.Seconds())
+}
+
+// registerCollectors registers the collectors that calculate the metrics about
+// the chain, mempool, utxo cache, and peers on demand from the provided server
+// and utxo cache.
+func (m *serverMetrics) registerCollectors(s *server, utxoCache *blockchain.UtxoCache) {
+	m.registry.Register(
+		metrics.CollectorFunc(func() []*metrics.Family {
+			return collectChainMetrics(s)
+		}),
+		metrics.CollectorFunc(func() []*metrics.Family {
+			return collectMempoolMetrics(s)
+		}),
+		metrics.CollectorFunc(func() []*metrics.Family {
+			return collectUtxoCacheMetrics(utxoCache.Stats())
+		}),
+		metrics.CollectorFunc(func() []*metrics.Family {
+			return collectPeerMetrics(s)
+		}),
+	)
+}
+
+// collectChainMetrics returns the metrics about the chain tip and sync state.
+func collectChainMetrics(s *server) []*metrics.Family {
+	best := s.chain.BestSnapshot()
+	return []*metrics.Family{
+		gauge("dcrd_chain_height", "Height of the current best chain tip.",
+			float64(best.Height)),
+		gauge("dcrd_chain_sync_height",
+			"Height of the best known chain tip announced by peers.",
+			float64(s.syncManager.SyncHeight())),
+		gauge("dcrd_chain_is_current",
+			"Whether or not the chain is believed to be synced with the "+
+				"network (1 when synced).",
+			boolToFloat(s.syncManager.IsCurrent())),
+	}
+}
+
+// collectMempoolMetrics returns the metrics about the transactions in the
+// mempool.
+func collectMempoolMetrics(s *server) []*metrics.Family {
+	feeRates := metrics.NewHistogram("dcrd_mempool_fee_rate_atoms_per_kb",
+		"Fee rates of the transactions in the mempool in atoms/kB.",
+		mempoolFeeRateBuckets)
+	txDescs := s.txMemPool.TxDescs()
+	var numBytes int64
+	for _, txDesc := range txDescs {
+		numBytes += txDesc.TxSize
+		if txDesc.TxSize > 0 {
+			feeRates.Observe(float64(txDesc.Fee) * 1000 /
+				float64(txDesc.TxSize))
+		}
+	}
+
+	families := []*metrics.Family{
+		gauge("dcrd_mempool_transactions",
+			"Number of transactions in the mempool.", float64(len(txDescs))),
+		gauge("dcrd_mempool_bytes",
+			"Total serialized size of the transactions in the mempool.",
+			float64(numBytes)),
+	}
+	return append(families, feeRates.Collect()...)
+}
+
+// collectUtxoCacheMetrics returns the metrics about the utxo cache from the
+// provided cache statistics.
+func collectUtxoCacheMetrics(stats blockchain.UtxoCacheStats) []*metrics.Family {
+	return []*metrics.Family{
+		gauge("dcrd_utxo_cache_entries",
+			"Number of utxo entries in the utxo cache.",
+			float64(stats.Entries)),
+		gauge("dcrd_utxo_cache_size_bytes",
+			"Total size of the utxo cache.", float64(stats.Size)),
+		gauge("dcrd_utxo_cache_max_size_bytes",
+			"Maximum allowed size of the utxo cache.", float64(stats.MaxSize)),
+		counter("dcrd_utxo_cache_hits_total",
+			"Total number of utxo cache lookups that resulted in a hit.",
+			float64(stats.Hits)),
+		counter("dcrd_utxo_cache_misses_total",
+			"Total number of utxo cache lookups that resulted in a miss.",
+			float64(stats.Misses)),
+		gauge("dcrd_utxo_cache_hit_ratio",
+			"Ratio of utxo cache lookups that resulted in a hit.",
+			stats.HitRatio/100),
+	}
+}
+
+// collectPeerMetrics returns the metrics about the connected peers along with
+// the total bytes sent and received.
+func collectPeerMetrics(s *server) []*metrics.Family {
+	bytesSent := &metrics.Family{
+		Name: "dcrd_peer_bytes_sent_total",
+		Help: "Total bytes sent to each connected peer.",
+		Type: metrics.CounterType,
+	}
+	bytesReceived := &metrics.Family{
+		Name: "dcrd_peer_bytes_received_total",
+		Help: "Total bytes received from each connected peer.",
+		Type: metrics.CounterType,
+	}
+	banScores := &metrics.Family{
+		Name: "dcrd_peer_ban_score",
+		Help: "Current ban score of each connected peer.",
+		Type: metrics.GaugeType,
+	}
+	var numInbound, numOutbound int
+	s.peerState.ForAllPeers(func(sp *serverPeer) {
+		if !sp.Connected() {
+			return
+		}
+		if sp.Inbound() {
+			numInbound++
+		} else {
+			numOutbound++
+		}
+		labels := []metrics.Label{
+			{Name: "id", Value: strconv.FormatInt(int64(sp.ID()), 10)},
+			{Name: "addr", Value: sp.Addr()},
+		}
+		bytesSent.Samples = append(bytesSent.Samples, metrics.Sample{
+			Labels: labels,
+			Value:  float64(sp.BytesSent()),
+		})
+		bytesReceived.Samples = append(bytesReceived.Samples, metrics.Sample{
+			Labels: labels,
+			Value:  float64(sp.BytesReceived()),
+		})
+		banScores.Samples = append(banScores.Samples, metrics.Sample{
+			Labels: labels,
+			Value:  float64(sp.banScore.Int()),
+		})
+	})
+
+	peers := &metrics.Family{
+		Name: "dcrd_peers",
+		Help: "Number of connected peers by direction.",
+		Type: metrics.GaugeType,
+		Samples: []metrics.Sample{{
+			Labels: []metrics.Label{{Name: "direction", Value: "inbound"}},
+			Value:  float64(numInbound),
+		}, {
+			Labels: []metrics.Label{{Name: "direction", Value: "outbound"}},
+			Value:  float64(numOutbound),
+		}},
+	}
+	return []*metrics.Family{peers, bytesSent, bytesReceived, banScores,
+		counter("dcrd_net_bytes_sent_total",
+			"Total bytes sent to all peers since start.",
+			float64(s.bytesSent.Load())),
+		counter("dcrd_net_bytes_received_total",
+			"Total bytes received from all peers since start.",
+			float64(s.bytesReceived.Load())),
+	}
+}
+
+// metricsServer provides an HTTP server that serves the metrics about the
+// server and its subsystems using the Prometheus text exposition format.
+type metricsServer struct {
+	listeners []net.Listener
+	server    *http.Server
+}
+
+// newMetricsServer returns a new metrics server that serves the provided
+// metrics on the provided listen address.  An error is returned when the
+// listeners fail to bind.
+func newMetricsServer(listenAddr string, m *serverMetrics) (*metricsServer, error) {
+	listenAddrs := normalizeAddresses([]string{listenAddr}, "",
+		normalizeInterfaceAddrs)
+	netAddrs, err := parseListeners(listenAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("unable to listen on %s: %w", listenAddr,
+				err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(m.registry))
+	return &metricsServer{
+		listeners: listeners,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: time.Second * 3,
+		},
+	}, nil
+}
+
+// Run serves the metrics on all listeners until the provided context is
+// cancelled.
+func (s *metricsServer) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, listener := range s.listeners {
+		srvrLog.Infof("Metrics server listening on %s", listener.Addr())
+		wg.Add(1)
+		go func(listener net.Listener) {
+			defer wg.Done()
+
+			err := s.server.Serve(listener)
+			if !errors.Is(err, http.ErrServerClosed) {
+				srvrLog.Errorf("Metrics server listening on %s exited with "+
+					"unexpected error: %v", listener.Addr(), err)
+			}
+		}(listener)
+	}
+
+	<-ctx.Done()
+	s.server.Close()
+	wg.Wait()
+	srvrLog.Info("Metrics server stopped")
+}
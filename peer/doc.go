@@ -20,6 +20,8 @@ A quick overview of the major features peer provides are as follows:
   - Full duplex reading and writing of Decred protocol messages
   - Automatic handling of the initial handshake process including protocol
     version negotiation
+  - Opportunistic encryption and authentication of messages with peers that
+    also support it
   - Asynchronous message queuing of outbound messages with optional channel for
     notification when the message is actually sent
   - Flexible peer configuration
@@ -68,6 +70,28 @@ Disconnect to disconnect from the peer and clean up all resources.
 WaitForDisconnect can be used to block until peer disconnection and resource
 cleanup has completed.
 
+# Encrypted Transport
+
+When both the local and remote peer advertise the SFNodeEncryptedTransport
+service flag in their version messages, the peers exchange ephemeral secp256k1
+public keys immediately after the version messages and all further messages are
+encrypted and authenticated with ChaCha20-Poly1305 using keys derived from the
+ECDH shared secret and both version messages.  Connections to peers that do not
+advertise the service continue to use the plaintext transport.  The Transport
+function and the peer statistics report which transport a connection uses.
+
+Since the keys commit to both version messages, modifying either of them in
+transit causes the handshake to fail.  Peers that advertised the service but do
+not complete the handshake, such as when the service flag was removed from the
+version message in only one direction, are disconnected instead of falling back
+to the plaintext transport.
+
+The encrypted transport is opportunistic and does not authenticate the identity
+of the remote peer.  It protects against passive observers and tampering with
+messages, but not against an active man-in-the-middle during the key exchange.
+Such an attacker is also able to remove the service flag from the version
+messages in both directions so that neither peer attempts the handshake.
+
 # Callbacks
 
 In order to do anything useful with a peer, it is necessary to react to decred
@@ -123,7 +147,8 @@ requests.
 
 A snapshot of the current peer statistics can be obtained with the StatsSnapshot
 function.  This includes statistics such as the total number of bytes read and
-written, the remote address, user agent, and negotiated protocol version.
+written, the remote address, user agent, negotiated protocol version, and
+transport.
 
 # Logging
 
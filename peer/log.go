@@ -66,8 +66,12 @@ func invSummary(invList []*wire.InvVect) string {
 			return fmt.Sprintf("block %s", iv.Hash)
 		case wire.InvTypeTx:
 			return fmt.Sprintf("tx %s", iv.Hash)
+		case wire.InvTypeStemTx:
+			return fmt.Sprintf("stem tx %s", iv.Hash)
 		case wire.InvTypeFilteredBlock:
 			return fmt.Sprintf("filtered block %s", iv.Hash)
+		case wire.InvTypeCmpctBlock:
+			return fmt.Sprintf("compact block %s", iv.Hash)
 		case wire.InvTypeMix:
 			return fmt.Sprintf("mix message %s", iv.Hash)
 		}
@@ -79,9 +83,9 @@ func invSummary(invList []*wire.InvVect) string {
 	var numTxns, numBlocks, numMixes uint64
 	for _, iv := range invList {
 		switch iv.Type {
-		case wire.InvTypeTx:
+		case wire.InvTypeTx, wire.InvTypeStemTx:
 			numTxns++
-		case wire.InvTypeBlock:
+		case wire.InvTypeBlock, wire.InvTypeCmpctBlock:
 			numBlocks++
 		case wire.InvTypeMix:
 			numMixes++
@@ -155,6 +159,19 @@ func messageSummary(msg wire.Message) string {
 		return fmt.Sprintf("hash %s, ver %d, %d tx, %s", msg.BlockHash(),
 			header.Version, len(msg.Transactions), header.Timestamp)
 
+	case *wire.MsgCmpctBlock:
+		header := &msg.Header
+		return fmt.Sprintf("hash %s, ver %d, %d tx, %d short ids, %s",
+			msg.BlockHash(), header.Version, msg.NumTransactions(),
+			len(msg.ShortIDs), header.Timestamp)
+
+	case *wire.MsgGetBlockTxns:
+		return fmt.Sprintf("hash %s, %d tx", msg.BlockHash, len(msg.Indexes))
+
+	case *wire.MsgBlockTxns:
+		return fmt.Sprintf("hash %s, %d tx", msg.BlockHash,
+			len(msg.Transactions))
+
 	case *wire.MsgMixPairReq:
 		return mixMessageSummary(msg)
 	case *wire.MsgMixKeyExchange:
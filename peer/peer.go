@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2016-2025 The Decred developers
+// Copyright (c) 2016-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -30,7 +30,7 @@ import (
 
 const (
 	// MaxProtocolVersion is the max protocol version the peer supports.
-	MaxProtocolVersion = wire.BatchedCFiltersV2Version
+	MaxProtocolVersion = wire.StemTxVersion
 
 	// outputBufferSize is the number of elements the output channels use.
 	outputBufferSize = 5000
@@ -108,6 +108,9 @@ type MessageListeners struct {
 	// OnAddr is invoked when a peer receives an addr wire message.
 	OnAddr func(p *Peer, msg *wire.MsgAddr)
 
+	// OnAddrV2 is invoked when a peer receives an addrv2 wire message.
+	OnAddrV2 func(p *Peer, msg *wire.MsgAddrV2)
+
 	// OnPing is invoked when a peer receives a ping wire message.
 	OnPing func(p *Peer, msg *wire.MsgPing)
 
@@ -131,6 +134,17 @@ type MessageListeners struct {
 	// OnBlock is invoked when a peer receives a block wire message.
 	OnBlock func(p *Peer, msg *wire.MsgBlock, buf []byte)
 
+	// OnCmpctBlock is invoked when a peer receives a cmpctblock wire
+	// message.
+	OnCmpctBlock func(p *Peer, msg *wire.MsgCmpctBlock)
+
+	// OnGetBlockTxns is invoked when a peer receives a getblocktxns wire
+	// message.
+	OnGetBlockTxns func(p *Peer, msg *wire.MsgGetBlockTxns)
+
+	// OnBlockTxns is invoked when a peer receives a blocktxns wire message.
+	OnBlockTxns func(p *Peer, msg *wire.MsgBlockTxns)
+
 	// OnCFilter is invoked when a peer receives a cfilter wire message.
 	OnCFilter func(p *Peer, msg *wire.MsgCFilter)
 
@@ -206,6 +220,9 @@ type MessageListeners struct {
 	// message.
 	OnSendHeaders func(p *Peer, msg *wire.MsgSendHeaders)
 
+	// OnSendCmpct is invoked when a peer receives a sendcmpct wire message.
+	OnSendCmpct func(p *Peer, msg *wire.MsgSendCmpct)
+
 	// OnGetInitState is invoked when a peer receives a getinitstate wire
 	// message.
 	OnGetInitState func(p *Peer, msg *wire.MsgGetInitState)
@@ -414,6 +431,7 @@ type StatsSnap struct {
 	LastPingNonce  uint64
 	LastPingTime   time.Time
 	LastPingMicros int64
+	Transport      string
 }
 
 // HashFunc is a function which returns a block hash, height and error
@@ -465,6 +483,20 @@ type Peer struct {
 	conn    net.Conn
 	connMtx sync.Mutex
 
+	// stream is the encrypted stream over conn that is used to read and write
+	// wire messages when an encrypted transport was negotiated.  It is only
+	// set during the version handshake before the input and output handlers
+	// are started and is nil for plaintext connections.
+	stream *encryptedStream
+
+	// localVersion and remoteVersion are the serialized payloads of the
+	// version messages sent to and received from the remote peer.  They are
+	// only set during the version handshake and are committed to by the keys
+	// of an encrypted transport so any modification of either version
+	// message in transit is detected.
+	localVersion  []byte
+	remoteVersion []byte
+
 	// blake256Hasher is the hash.Hash object that is used by readMessage
 	// to calculate the hash of read mixing messages.  Every peer's hasher
 	// is a distinct object and does not require locking.
@@ -487,6 +519,7 @@ type Peer struct {
 	protocolVersion      uint32 // negotiated protocol version
 	sendHeadersPreferred bool   // peer sent a sendheaders message
 	verAckReceived       bool
+	encrypted            bool // encrypted transport negotiated
 
 	knownInventory     *lru.Set[wire.InvVect]
 	prevGetBlocksMtx   sync.Mutex
@@ -569,6 +602,7 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 	userAgent := p.userAgent
 	services := p.services
 	protocolVersion := p.advertisedProtoVer
+	transport := p.transportLocked()
 	p.flagsMtx.Unlock()
 
 	// Get a copy of all relevant flags and stats.
@@ -590,6 +624,7 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 		LastPingNonce:  p.lastPingNonce,
 		LastPingMicros: p.lastPingMicros,
 		LastPingTime:   p.lastPingTime,
+		Transport:      transport,
 	}
 
 	p.statsMtx.RUnlock()
@@ -649,6 +684,29 @@ func (p *Peer) Services() wire.ServiceFlag {
 	return services
 }
 
+// transportLocked returns the name of the transport used by the connection.
+//
+// This function MUST be called with the flags mutex held.
+func (p *Peer) transportLocked() string {
+	if p.encrypted {
+		return TransportEncrypted
+	}
+	return TransportPlaintext
+}
+
+// Transport returns the name of the transport used by the connection.  It is
+// TransportEncrypted when an encrypted transport was negotiated during the
+// version handshake and TransportPlaintext otherwise.
+//
+// This function is safe for concurrent access.
+func (p *Peer) Transport() string {
+	p.flagsMtx.Lock()
+	transport := p.transportLocked()
+	p.flagsMtx.Unlock()
+
+	return transport
+}
+
 // UserAgent returns the user agent of the remote peer.
 //
 // This function is safe for concurrent access.
@@ -870,6 +928,46 @@ func (p *Peer) PushAddrMsg(addresses []*wire.NetAddress) ([]*wire.NetAddress, er
 	return msg.AddrList, nil
 }
 
+// PushAddrV2Msg sends an addrv2 message to the connected peer using the
+// provided addresses.  This function is useful over manually sending the
+// message via QueueMessage since it automatically limits the addresses to the
+// maximum number allowed by the message and randomizes the chosen addresses
+// when there are too many.  It returns the addresses that were actually sent
+// and no message will be sent if there are no entries in the provided addresses
+// slice.
+//
+// An error is returned if the negotiated protocol version does not support the
+// addrv2 message.
+//
+// This function is safe for concurrent access.
+func (p *Peer) PushAddrV2Msg(addresses []*wire.NetAddressV2) ([]*wire.NetAddressV2, error) {
+	if pver := p.ProtocolVersion(); pver < wire.AddrV2Version {
+		return nil, fmt.Errorf("addrv2 message invalid for protocol version "+
+			"%d", pver)
+	}
+
+	// Nothing to send.
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	msg := wire.NewMsgAddrV2()
+	msg.AddrList = make([]*wire.NetAddressV2, len(addresses))
+	copy(msg.AddrList, addresses)
+
+	// Randomize the addresses sent if there are more than the maximum allowed.
+	if len(msg.AddrList) > wire.MaxAddrPerV2Msg {
+		// Shuffle the address list.
+		rand.ShuffleSlice(msg.AddrList)
+
+		// Truncate it to the maximum size.
+		msg.AddrList = msg.AddrList[:wire.MaxAddrPerV2Msg]
+	}
+
+	p.QueueMessage(msg, nil)
+	return msg.AddrList, nil
+}
+
 // PushGetBlocksMsg sends a getblocks message for the provided block locator
 // and stop hash.  It will ignore back-to-back duplicate requests.
 //
@@ -1004,8 +1102,11 @@ func (p *Peer) readMessage() (wire.Message, []byte, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	n, msg, buf, err := wire.ReadMessageN(p.conn, p.ProtocolVersion(),
-		p.cfg.Net)
+	var r io.Reader = p.conn
+	if p.stream != nil {
+		r = p.stream
+	}
+	n, msg, buf, err := wire.ReadMessageN(r, p.ProtocolVersion(), p.cfg.Net)
 	atomic.AddUint64(&p.bytesReceived, uint64(n))
 
 	// Calculate and store the message hash of any mixing message
@@ -1066,8 +1167,21 @@ func (p *Peer) writeMessage(msg wire.Message) error {
 		}
 	}
 
-	// Write the message to the peer.
-	n, err := wire.WriteMessageN(p.conn, msg, p.ProtocolVersion(), p.cfg.Net)
+	// Write the message to the peer.  Messages written to an encrypted stream
+	// are buffered until it is flushed so they are sent in as few frames as
+	// possible.
+	var n int
+	var err error
+	if p.stream != nil {
+		n, err = wire.WriteMessageN(p.stream, msg, p.ProtocolVersion(),
+			p.cfg.Net)
+		if err == nil {
+			err = p.stream.Flush()
+		}
+	} else {
+		n, err = wire.WriteMessageN(p.conn, msg, p.ProtocolVersion(),
+			p.cfg.Net)
+	}
 	atomic.AddUint64(&p.bytesSent, uint64(n))
 	if p.cfg.Listeners.OnWrite != nil {
 		p.cfg.Listeners.OnWrite(p, n, msg, err)
@@ -1132,8 +1246,9 @@ func (p *Peer) maybeAddDeadline(pendingResponses map[string]time.Time, msgCmd st
 		addedDeadline = true
 
 	case wire.CmdGetData:
-		// Expects a block, tx, mix, or notfound message.
+		// Expects a block, cmpctblock, tx, mix, or notfound message.
 		pendingResponses[wire.CmdBlock] = deadline
+		pendingResponses[wire.CmdCmpctBlock] = deadline
 		pendingResponses[wire.CmdTx] = deadline
 		pendingResponses[wire.CmdMixPairReq] = deadline
 		pendingResponses[wire.CmdMixKeyExchange] = deadline
@@ -1153,6 +1268,11 @@ func (p *Peer) maybeAddDeadline(pendingResponses map[string]time.Time, msgCmd st
 	case wire.CmdGetInitState:
 		pendingResponses[wire.CmdInitState] = deadline
 		addedDeadline = true
+
+	case wire.CmdGetBlockTxns:
+		// Expects a blocktxns message.
+		pendingResponses[wire.CmdBlockTxns] = deadline
+		addedDeadline = true
 	}
 
 	if addedDeadline {
@@ -1204,6 +1324,8 @@ out:
 				switch msgCmd := msg.message.Command(); msgCmd {
 				case wire.CmdBlock:
 					fallthrough
+				case wire.CmdCmpctBlock:
+					fallthrough
 				case wire.CmdTx:
 					fallthrough
 				case wire.CmdMixPairReq:
@@ -1224,6 +1346,7 @@ out:
 					fallthrough
 				case wire.CmdNotFound:
 					delete(pendingResponses, wire.CmdBlock)
+					delete(pendingResponses, wire.CmdCmpctBlock)
 					delete(pendingResponses, wire.CmdTx)
 					delete(pendingResponses, wire.CmdMixPairReq)
 					delete(pendingResponses, wire.CmdMixKeyExchange)
@@ -1405,6 +1528,11 @@ out:
 				p.cfg.Listeners.OnAddr(p, msg)
 			}
 
+		case *wire.MsgAddrV2:
+			if p.cfg.Listeners.OnAddrV2 != nil {
+				p.cfg.Listeners.OnAddrV2(p, msg)
+			}
+
 		case *wire.MsgPing:
 			p.handlePingMsg(msg)
 			if p.cfg.Listeners.OnPing != nil {
@@ -1442,6 +1570,21 @@ out:
 				p.cfg.Listeners.OnBlock(p, msg, buf)
 			}
 
+		case *wire.MsgCmpctBlock:
+			if p.cfg.Listeners.OnCmpctBlock != nil {
+				p.cfg.Listeners.OnCmpctBlock(p, msg)
+			}
+
+		case *wire.MsgGetBlockTxns:
+			if p.cfg.Listeners.OnGetBlockTxns != nil {
+				p.cfg.Listeners.OnGetBlockTxns(p, msg)
+			}
+
+		case *wire.MsgBlockTxns:
+			if p.cfg.Listeners.OnBlockTxns != nil {
+				p.cfg.Listeners.OnBlockTxns(p, msg)
+			}
+
 		case *wire.MsgInv:
 			if p.cfg.Listeners.OnInv != nil {
 				p.cfg.Listeners.OnInv(p, msg)
@@ -1516,6 +1659,11 @@ out:
 				p.cfg.Listeners.OnSendHeaders(p, msg)
 			}
 
+		case *wire.MsgSendCmpct:
+			if p.cfg.Listeners.OnSendCmpct != nil {
+				p.cfg.Listeners.OnSendCmpct(p, msg)
+			}
+
 		case *wire.MsgGetCFilterV2:
 			if p.cfg.Listeners.OnGetCFilterV2 != nil {
 				p.cfg.Listeners.OnGetCFilterV2(p, msg)
@@ -1958,7 +2106,7 @@ func (p *Peer) Disconnect() {
 // acceptable then return an error.
 func (p *Peer) readRemoteVersionMsg() error {
 	// Read their version message.
-	remoteMsg, _, err := p.readMessage()
+	remoteMsg, buf, err := p.readMessage()
 	if err != nil {
 		return err
 	}
@@ -1968,6 +2116,7 @@ func (p *Peer) readRemoteVersionMsg() error {
 	if !ok {
 		return errors.New("a version message must precede all others")
 	}
+	p.remoteVersion = buf
 
 	// Detect self connections.
 	if !allowSelfConns && sentNonces.Contains(msg.Nonce) {
@@ -2085,6 +2234,13 @@ func (p *Peer) writeLocalVersionMsg() error {
 		return err
 	}
 
+	// Keep the serialized version message for the transport negotiation.
+	var buf bytes.Buffer
+	if err := localVerMsg.BtcEncode(&buf, p.ProtocolVersion()); err != nil {
+		return err
+	}
+	p.localVersion = buf.Bytes()
+
 	if err := p.writeMessage(localVerMsg); err != nil {
 		return err
 	}
@@ -2104,6 +2260,10 @@ func (p *Peer) negotiateInboundProtocol() error {
 		return err
 	}
 
+	if err := p.negotiateTransport(); err != nil {
+		return err
+	}
+
 	p.flagsMtx.Lock()
 	p.handshakeDone = true
 	p.flagsMtx.Unlock()
@@ -2123,6 +2283,10 @@ func (p *Peer) negotiateOutboundProtocol() error {
 		return err
 	}
 
+	if err := p.negotiateTransport(); err != nil {
+		return err
+	}
+
 	p.flagsMtx.Lock()
 	p.handshakeDone = true
 	p.flagsMtx.Unlock()
@@ -1,5 +1,5 @@
 // Copyright (c) 2015-2016 The btcsuite developers
-// Copyright (c) 2016-2024 The Decred developers
+// Copyright (c) 2016-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -336,6 +336,9 @@ func TestPeerListeners(t *testing.T) {
 			OnAddr: func(p *Peer, msg *wire.MsgAddr) {
 				ok <- msg
 			},
+			OnAddrV2: func(p *Peer, msg *wire.MsgAddrV2) {
+				ok <- msg
+			},
 			OnPing: func(p *Peer, msg *wire.MsgPing) {
 				ok <- msg
 			},
@@ -351,6 +354,15 @@ func TestPeerListeners(t *testing.T) {
 			OnBlock: func(p *Peer, msg *wire.MsgBlock, buf []byte) {
 				ok <- msg
 			},
+			OnCmpctBlock: func(p *Peer, msg *wire.MsgCmpctBlock) {
+				ok <- msg
+			},
+			OnGetBlockTxns: func(p *Peer, msg *wire.MsgGetBlockTxns) {
+				ok <- msg
+			},
+			OnBlockTxns: func(p *Peer, msg *wire.MsgBlockTxns) {
+				ok <- msg
+			},
 			OnInv: func(p *Peer, msg *wire.MsgInv) {
 				ok <- msg
 			},
@@ -399,6 +411,9 @@ func TestPeerListeners(t *testing.T) {
 			OnSendHeaders: func(p *Peer, msg *wire.MsgSendHeaders) {
 				ok <- msg
 			},
+			OnSendCmpct: func(p *Peer, msg *wire.MsgSendCmpct) {
+				ok <- msg
+			},
 			OnGetCFilterV2: func(p *Peer, msg *wire.MsgGetCFilterV2) {
 				ok <- msg
 			},
@@ -470,6 +485,10 @@ func TestPeerListeners(t *testing.T) {
 			"OnAddr",
 			wire.NewMsgAddr(),
 		},
+		{
+			"OnAddrV2",
+			wire.NewMsgAddrV2(),
+		},
 		{
 			"OnPing",
 			wire.NewMsgPing(42),
@@ -493,6 +512,21 @@ func TestPeerListeners(t *testing.T) {
 				1, 1, 1, 1, 1, 1, 1, 1, 1, [32]byte{},
 				binary.LittleEndian.Uint32([]byte{0xb0, 0x1d, 0xfa, 0xce}))),
 		},
+		{
+			"OnCmpctBlock",
+			wire.NewMsgCmpctBlock(wire.NewBlockHeader(0, &chainhash.Hash{},
+				&chainhash.Hash{}, &chainhash.Hash{}, 1, [6]byte{},
+				1, 1, 1, 1, 1, 1, 1, 1, 1, [32]byte{},
+				binary.LittleEndian.Uint32([]byte{0xb0, 0x1d, 0xfa, 0xce})), 42),
+		},
+		{
+			"OnGetBlockTxns",
+			wire.NewMsgGetBlockTxns(&chainhash.Hash{}, []uint32{1}),
+		},
+		{
+			"OnBlockTxns",
+			wire.NewMsgBlockTxns(&chainhash.Hash{}, nil),
+		},
 		{
 			"OnInv",
 			wire.NewMsgInv(),
@@ -563,6 +597,10 @@ func TestPeerListeners(t *testing.T) {
 			"OnSendHeaders",
 			wire.NewMsgSendHeaders(),
 		},
+		{
+			"OnSendCmpct",
+			wire.NewMsgSendCmpct(true, wire.CmpctBlockEncodingVersion),
+		},
 		{
 			"OnGetInitState",
 			wire.NewMsgGetInitState(),
@@ -760,6 +798,16 @@ func TestOutboundPeer(t *testing.T) {
 		t.Errorf("PushAddrMsg: unexpected err %v\n", err)
 		return
 	}
+	var addrsV2 []*wire.NetAddressV2
+	for i := 0; i < 5; i++ {
+		na := wire.NewNetAddressV2(time.Now(), wire.SFNodeNetwork,
+			wire.IPv4Address, []byte{10, 0, 0, byte(i)}, 9108)
+		addrsV2 = append(addrsV2, na)
+	}
+	if _, err := p2.PushAddrV2Msg(addrsV2); err != nil {
+		t.Errorf("PushAddrV2Msg: unexpected err %v\n", err)
+		return
+	}
 	if err := p2.PushGetBlocksMsg(nil, &chainhash.Hash{}); err != nil {
 		t.Errorf("PushGetBlocksMsg: unexpected err %v\n", err)
 		return
@@ -0,0 +1,362 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/wire"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// transportKeySize is the size of the serialized ephemeral public keys
+	// that are exchanged to negotiate an encrypted transport.
+	transportKeySize = secp256k1.PubKeyBytesLenCompressed
+
+	// maxFramePayload is the maximum number of plaintext bytes that are
+	// sealed in a single frame of an encrypted transport.  Larger writes are
+	// split across multiple frames.
+	maxFramePayload = 1 << 16
+
+	// frameHeaderSize is the size of the header that precedes the ciphertext
+	// of every frame of an encrypted transport.  It is the little-endian
+	// length of the ciphertext.
+	frameHeaderSize = 4
+
+	// maxFrameCiphertext is the maximum permitted ciphertext length of a frame
+	// read from the remote peer.
+	maxFrameCiphertext = maxFramePayload + chacha20poly1305.Overhead
+
+	// transportConfirmation is sent by both sides of a connection as the
+	// first frame of an encrypted transport to confirm they derived the same
+	// keys before any wire messages are exchanged.
+	transportConfirmation = "dcrd p2p transport confirmation"
+)
+
+const (
+	// TransportPlaintext is the name of the transport used by connections
+	// that exchange unencrypted wire messages.
+	TransportPlaintext = "plaintext"
+
+	// TransportEncrypted is the name of the transport used by connections
+	// that negotiated encryption during the version handshake.
+	TransportEncrypted = "encrypted"
+)
+
+var (
+	// initiatorKeyLabel and responderKeyLabel are used to derive the keys for
+	// the messages sent by the initiator (outbound side) and the responder
+	// (inbound side) of a connection, respectively.
+	initiatorKeyLabel = []byte("dcrd p2p transport initiator key")
+	responderKeyLabel = []byte("dcrd p2p transport responder key")
+
+	// errFrameAuth is returned when a frame read from the remote peer fails
+	// authentication.
+	errFrameAuth = errors.New("encrypted transport: message authentication " +
+		"failed")
+)
+
+// deriveTransportKey derives a symmetric key for one direction of an encrypted
+// transport from the ECDH shared secret, the ephemeral public keys of both sides
+// of the connection, and the serialized version message payloads of both sides.
+//
+// Committing to the version messages ensures both sides only derive the same
+// keys when they received the exact version messages the other side sent.
+func deriveTransportKey(label, secret, initiatorPub, responderPub, initiatorVersion, responderVersion []byte) []byte {
+	h := blake256.New()
+	h.Write(label)
+	h.Write(secret)
+	h.Write(initiatorPub)
+	h.Write(responderPub)
+	var lenBuf [4]byte
+	for _, version := range [][]byte{initiatorVersion, responderVersion} {
+		binary.LittleEndian.PutUint32(lenBuf[:], uint32(len(version)))
+		h.Write(lenBuf[:])
+		h.Write(version)
+	}
+	return h.Sum(nil)
+}
+
+// encryptedStream wraps the connection to a remote peer to encrypt and
+// authenticate all data written to it and decrypt and authenticate all data
+// read from it with ChaCha20-Poly1305.
+//
+// Data is sent in frames that consist of the length of the ciphertext followed
+// by the ciphertext.  The length is authenticated as additional data.  The
+// nonce of each frame is a counter that is independently maintained for each
+// direction, so frames that are dropped, reordered, or replayed fail
+// authentication.
+//
+// Writes are buffered until Flush is called so that a wire message is sent in
+// as few frames as possible.
+//
+// It is not safe for concurrent reads or concurrent writes, however reading and
+// writing concurrently is safe.
+type encryptedStream struct {
+	rw io.ReadWriter
+
+	recvAEAD  cipher.AEAD
+	recvNonce uint64
+	recvFrame []byte
+	plaintext []byte
+
+	sendAEAD  cipher.AEAD
+	sendNonce uint64
+	sendBuf   bytes.Buffer
+	sendFrame []byte
+}
+
+// newEncryptedStream returns an encrypted stream over the provided reader and
+// writer that uses the provided keys to encrypt sent and decrypt received
+// frames, respectively.
+func newEncryptedStream(rw io.ReadWriter, sendKey, recvKey []byte) (*encryptedStream, error) {
+	sendAEAD, err := chacha20poly1305.New(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	recvAEAD, err := chacha20poly1305.New(recvKey)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedStream{
+		rw:       rw,
+		recvAEAD: recvAEAD,
+		sendAEAD: sendAEAD,
+	}, nil
+}
+
+// frameNonce returns the AEAD nonce for the frame with the provided counter.
+func frameNonce(counter uint64) []byte {
+	var nonce [chacha20poly1305.NonceSize]byte
+	binary.LittleEndian.PutUint64(nonce[4:], counter)
+	return nonce[:]
+}
+
+// Read reads decrypted data from the stream.  It reads and authenticates the
+// next frame from the underlying reader as needed.
+func (s *encryptedStream) Read(b []byte) (int, error) {
+	for len(s.plaintext) == 0 {
+		if err := s.readFrame(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, s.plaintext)
+	s.plaintext = s.plaintext[n:]
+	return n, nil
+}
+
+// readFrame reads the next frame from the underlying reader and replaces the
+// buffered plaintext with its decrypted contents.
+func (s *encryptedStream) readFrame() error {
+	if s.recvNonce == math.MaxUint64 {
+		return errors.New("encrypted transport: receive nonce exhausted")
+	}
+
+	var hdr [frameHeaderSize]byte
+	if _, err := io.ReadFull(s.rw, hdr[:]); err != nil {
+		return err
+	}
+	frameLen := binary.LittleEndian.Uint32(hdr[:])
+	if frameLen < chacha20poly1305.Overhead || frameLen > maxFrameCiphertext {
+		return fmt.Errorf("encrypted transport: invalid frame length %d",
+			frameLen)
+	}
+
+	if s.recvFrame == nil {
+		s.recvFrame = make([]byte, maxFrameCiphertext)
+	}
+	ciphertext := s.recvFrame[:frameLen]
+	if _, err := io.ReadFull(s.rw, ciphertext); err != nil {
+		return err
+	}
+	plaintext, err := s.recvAEAD.Open(ciphertext[:0], frameNonce(s.recvNonce),
+		ciphertext, hdr[:])
+	if err != nil {
+		return errFrameAuth
+	}
+	s.recvNonce++
+	s.plaintext = plaintext
+	return nil
+}
+
+// Write buffers the provided data to be encrypted and sent by the next call to
+// Flush.  It never returns an error.
+func (s *encryptedStream) Write(b []byte) (int, error) {
+	return s.sendBuf.Write(b)
+}
+
+// Flush encrypts all buffered data and writes the resulting frames to the
+// underlying writer.
+func (s *encryptedStream) Flush() error {
+	defer s.sendBuf.Reset()
+	for s.sendBuf.Len() > 0 {
+		if s.sendNonce == math.MaxUint64 {
+			return errors.New("encrypted transport: send nonce exhausted")
+		}
+
+		payload := s.sendBuf.Next(maxFramePayload)
+		frameLen := len(payload) + chacha20poly1305.Overhead
+		if s.sendFrame == nil {
+			s.sendFrame = make([]byte, frameHeaderSize+maxFrameCiphertext)
+		}
+		var hdr [frameHeaderSize]byte
+		binary.LittleEndian.PutUint32(hdr[:], uint32(frameLen))
+		frame := append(s.sendFrame[:0], hdr[:]...)
+		frame = s.sendAEAD.Seal(frame, frameNonce(s.sendNonce), payload,
+			hdr[:])
+		s.sendNonce++
+		if _, err := s.rw.Write(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// negotiateTransport establishes an encrypted transport with the remote peer
+// when both the local and remote peer advertised support for it in their
+// version messages.  Otherwise, the connection continues to use the plaintext
+// transport so that peers which do not support encryption remain compatible.
+//
+// The outbound side sends its ephemeral public key first and the inbound side
+// responds with its own.  Each side then derives a distinct key for each
+// direction from the ECDH shared secret, both public keys, and both version
+// messages and sends a confirmation frame that the other side must be able to
+// authenticate.
+//
+// An error is returned when the remote peer advertised support but does not
+// complete the handshake, including when either version message was modified
+// in transit, so the connection is never silently downgraded to the plaintext
+// transport once both sides advertised support.
+//
+// The transport is opportunistic and unauthenticated.  It protects against
+// passive observers and tampering by anyone who was not an active
+// man-in-the-middle during the key exchange, but it does not authenticate the
+// identity of the remote peer.  In particular, an active man-in-the-middle is
+// still able to remove the service flag from the version messages in both
+// directions so that neither side attempts the handshake.
+//
+// This must only be called after the version messages are exchanged and
+// before the input and output handlers are started.
+func (p *Peer) negotiateTransport() error {
+	const flag = wire.SFNodeEncryptedTransport
+	if p.cfg.Services&flag != flag || p.Services()&flag != flag {
+		return nil
+	}
+
+	if err := p.encryptTransport(); err != nil {
+		return fmt.Errorf("peer advertised %v but did not complete the "+
+			"encrypted transport handshake: %w", flag, err)
+	}
+	p.flagsMtx.Lock()
+	p.encrypted = true
+	p.flagsMtx.Unlock()
+	log.Debugf("Negotiated encrypted transport with peer %s", p)
+	return nil
+}
+
+// encryptTransport performs the key exchange and key confirmation with the
+// remote peer as described by negotiateTransport and sets the encrypted stream
+// that is used for all further messages.
+func (p *Peer) encryptTransport() error {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return err
+	}
+	defer privKey.Zero()
+	ourPub := privKey.PubKey().SerializeCompressed()
+
+	var theirPub [transportKeySize]byte
+	readKey := func() error {
+		n, err := io.ReadFull(p.conn, theirPub[:])
+		atomic.AddUint64(&p.bytesReceived, uint64(n))
+		return err
+	}
+	writeKey := func() error {
+		n, err := p.conn.Write(ourPub)
+		atomic.AddUint64(&p.bytesSent, uint64(n))
+		return err
+	}
+	if err := p.conn.SetReadDeadline(time.Now().Add(p.cfg.IdleTimeout)); err != nil {
+		return err
+	}
+	first, second := writeKey, readKey
+	if p.inbound {
+		first, second = readKey, writeKey
+	}
+	if err := first(); err != nil {
+		return err
+	}
+	if err := second(); err != nil {
+		return err
+	}
+
+	pubKey, err := secp256k1.ParsePubKey(theirPub[:])
+	if err != nil {
+		return fmt.Errorf("invalid transport public key: %w", err)
+	}
+	secret := secp256k1.GenerateSharedSecret(privKey, pubKey)
+	initiatorPub, responderPub := ourPub, theirPub[:]
+	initiatorVersion, responderVersion := p.localVersion, p.remoteVersion
+	if p.inbound {
+		initiatorPub, responderPub = theirPub[:], ourPub
+		initiatorVersion, responderVersion = p.remoteVersion, p.localVersion
+	}
+	initiatorKey := deriveTransportKey(initiatorKeyLabel, secret, initiatorPub,
+		responderPub, initiatorVersion, responderVersion)
+	responderKey := deriveTransportKey(responderKeyLabel, secret, initiatorPub,
+		responderPub, initiatorVersion, responderVersion)
+	sendKey, recvKey := initiatorKey, responderKey
+	if p.inbound {
+		sendKey, recvKey = responderKey, initiatorKey
+	}
+	stream, err := newEncryptedStream(p.conn, sendKey, recvKey)
+	if err != nil {
+		return err
+	}
+
+	// Confirm both sides derived the same keys.  This fails authentication
+	// when the version messages either side received differ from the ones
+	// that were sent.
+	readConfirmation := func() error {
+		var confirmation [len(transportConfirmation)]byte
+		if _, err := io.ReadFull(stream, confirmation[:]); err != nil {
+			return err
+		}
+		if string(confirmation[:]) != transportConfirmation {
+			return errors.New("invalid transport confirmation")
+		}
+		return nil
+	}
+	writeConfirmation := func() error {
+		if _, err := stream.Write([]byte(transportConfirmation)); err != nil {
+			return err
+		}
+		return stream.Flush()
+	}
+	first, second = writeConfirmation, readConfirmation
+	if p.inbound {
+		first, second = readConfirmation, writeConfirmation
+	}
+	if err := first(); err != nil {
+		return err
+	}
+	if err := second(); err != nil {
+		return err
+	}
+
+	p.stream = stream
+	return nil
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestEncryptedStream ensures data written to an encrypted stream is split into
+// frames as expected, is read back intact by a stream with the corresponding
+// keys, and that frames which are modified or replayed fail authentication.
+func TestEncryptedStream(t *testing.T) {
+	t.Parallel()
+
+	keyA := bytes.Repeat([]byte{0x01}, 32)
+	keyB := bytes.Repeat([]byte{0x02}, 32)
+
+	var buf bytes.Buffer
+	sender, err := newEncryptedStream(&buf, keyA, keyB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	receiver, err := newEncryptedStream(&buf, keyB, keyA)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Write data that requires multiple frames and ensure nothing is written
+	// until the stream is flushed.
+	data := make([]byte, maxFramePayload*2+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if _, err := sender.Write(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected write before flush of %d bytes", buf.Len())
+	}
+	if err := sender.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const overhead = frameHeaderSize + maxFrameCiphertext - maxFramePayload
+	if want := len(data) + 3*overhead; buf.Len() != want {
+		t.Fatalf("unexpected written length -- got %d, want %d", buf.Len(),
+			want)
+	}
+	if bytes.Contains(buf.Bytes(), data[:64]) {
+		t.Fatal("plaintext found in encrypted data")
+	}
+
+	got := make([]byte, len(data))
+	if _, err := io.ReadFull(receiver, got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("mismatched data read from stream")
+	}
+
+	// Ensure a replayed frame fails authentication.
+	if _, err := sender.Write([]byte("replayed")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sender.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame := append([]byte(nil), buf.Bytes()...)
+	if _, err := receiver.Read(got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf.Write(frame)
+	if _, err := receiver.Read(got); !errors.Is(err, errFrameAuth) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, errFrameAuth)
+	}
+
+	// Ensure frames with an invalid length are rejected.
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	if _, err := receiver.Read(got); err == nil {
+		t.Fatal("frame with invalid length was accepted")
+	}
+
+	// Ensure a modified frame fails authentication.  New streams are used
+	// since a stream is no longer usable once a frame fails authentication.
+	buf.Reset()
+	sender, _ = newEncryptedStream(&buf, keyA, keyB)
+	receiver, _ = newEncryptedStream(&buf, keyB, keyA)
+	if _, err := sender.Write([]byte("tampered")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sender.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf.Bytes()[frameHeaderSize] ^= 0x01
+	if _, err := receiver.Read(got); !errors.Is(err, errFrameAuth) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, errFrameAuth)
+	}
+}
+
+// TestPeerTransport ensures peers negotiate an encrypted transport when both of
+// them advertise support for it, fall back to the plaintext transport
+// otherwise, and are able to exchange messages over the negotiated transport.
+func TestPeerTransport(t *testing.T) {
+	tests := []struct {
+		name        string           // test description
+		inServices  wire.ServiceFlag // services of the inbound peer
+		outServices wire.ServiceFlag // services of the outbound peer
+		want        string           // expected transport
+	}{{
+		name:        "both encrypted",
+		inServices:  wire.SFNodeNetwork | wire.SFNodeEncryptedTransport,
+		outServices: wire.SFNodeEncryptedTransport,
+		want:        TransportEncrypted,
+	}, {
+		name:        "inbound plaintext",
+		inServices:  wire.SFNodeNetwork,
+		outServices: wire.SFNodeEncryptedTransport,
+		want:        TransportPlaintext,
+	}, {
+		name:        "outbound plaintext",
+		inServices:  wire.SFNodeEncryptedTransport,
+		outServices: 0,
+		want:        TransportPlaintext,
+	}}
+
+	for _, test := range tests {
+		verack := make(chan struct{}, 2)
+		pong := make(chan struct{}, 1)
+		listeners := MessageListeners{
+			OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+				verack <- struct{}{}
+			},
+			OnPong: func(p *Peer, msg *wire.MsgPong) {
+				pong <- struct{}{}
+			},
+		}
+		inCfg := &Config{
+			Listeners: listeners,
+			Net:       wire.MainNet,
+			Services:  test.inServices,
+		}
+		outCfg := &Config{
+			Listeners: listeners,
+			Net:       wire.MainNet,
+			Services:  test.outServices,
+		}
+
+		inConn, outConn := pipe(
+			&conn{raddr: "10.0.0.1:8333"},
+			&conn{raddr: "10.0.0.2:8333"},
+		)
+		inPeer := NewInboundPeer(inCfg)
+		inPeer.AssociateConnection(inConn)
+		outPeer, err := NewOutboundPeer(outCfg, "10.0.0.2:8333")
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		outPeer.AssociateConnection(outConn)
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-verack:
+			case <-time.After(time.Second):
+				t.Fatalf("%q: verack timeout", test.name)
+			}
+		}
+
+		// Ensure messages are exchanged over the negotiated transport.
+		outPeer.QueueMessage(wire.NewMsgPing(1), nil)
+		select {
+		case <-pong:
+		case <-time.After(time.Second):
+			t.Fatalf("%q: pong timeout", test.name)
+		}
+
+		for _, p := range []*Peer{inPeer, outPeer} {
+			if got := p.Transport(); got != test.want {
+				t.Errorf("%q: unexpected transport for %s -- got %s, want %s",
+					test.name, p, got, test.want)
+			}
+			if got := p.StatsSnapshot().Transport; got != test.want {
+				t.Errorf("%q: unexpected stats transport for %s -- got %s, "+
+					"want %s", test.name, p, got, test.want)
+			}
+		}
+
+		inPeer.Disconnect()
+		outPeer.Disconnect()
+		inPeer.WaitForDisconnect()
+		outPeer.WaitForDisconnect()
+	}
+}
+
+// versionTamperer is a writer that modifies the first version message written
+// through it with the provided function to simulate a man-in-the-middle.
+type versionTamperer struct {
+	io.WriteCloser
+	tamper func(msg *wire.MsgVersion)
+	buf    []byte
+	done   bool
+}
+
+// Write buffers the data until a full message is available, modifies it when
+// it is a version message, and then writes it to the underlying writer.
+func (w *versionTamperer) Write(b []byte) (int, error) {
+	if w.done {
+		return w.WriteCloser.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < wire.MessageHeaderSize {
+		return len(b), nil
+	}
+	payloadLen := binary.LittleEndian.Uint32(w.buf[16:20])
+	if len(w.buf) < wire.MessageHeaderSize+int(payloadLen) {
+		return len(b), nil
+	}
+	w.done = true
+
+	msg, _, err := wire.ReadMessage(bytes.NewReader(w.buf),
+		wire.ProtocolVersion, wire.MainNet)
+	if err != nil {
+		return 0, err
+	}
+	if msg, ok := msg.(*wire.MsgVersion); ok {
+		w.tamper(msg)
+	}
+	err = wire.WriteMessage(w.WriteCloser, msg, wire.ProtocolVersion,
+		wire.MainNet)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// TestPeerTransportTampered ensures peers that both advertise support for the
+// encrypted transport disconnect instead of exchanging any messages when a
+// version message is modified in transit.
+func TestPeerTransportTampered(t *testing.T) {
+	tests := []struct {
+		name   string                 // test description
+		tamper func(*wire.MsgVersion) // modification of the version
+	}{{
+		name: "modified services",
+		tamper: func(msg *wire.MsgVersion) {
+			msg.Services &^= wire.SFNodeNetwork
+		},
+	}, {
+		name: "modified user agent",
+		tamper: func(msg *wire.MsgVersion) {
+			msg.UserAgent = "/tampered:0.0.1/"
+		},
+	}}
+
+	for _, test := range tests {
+		verack := make(chan struct{}, 2)
+		listeners := MessageListeners{
+			OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+				verack <- struct{}{}
+			},
+		}
+		const services = wire.SFNodeNetwork | wire.SFNodeEncryptedTransport
+		inCfg := &Config{
+			Listeners: listeners,
+			Net:       wire.MainNet,
+			Services:  services,
+		}
+		outCfg := &Config{
+			Listeners: listeners,
+			Net:       wire.MainNet,
+			Services:  services,
+		}
+
+		inConn, outConn := pipe(
+			&conn{raddr: "10.0.0.1:8333"},
+			&conn{raddr: "10.0.0.2:8333"},
+		)
+		outConn.WriteCloser = &versionTamperer{
+			WriteCloser: outConn.WriteCloser,
+			tamper:      test.tamper,
+		}
+		inPeer := NewInboundPeer(inCfg)
+		inPeer.AssociateConnection(inConn)
+		outPeer, err := NewOutboundPeer(outCfg, "10.0.0.2:8333")
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		outPeer.AssociateConnection(outConn)
+
+		for _, p := range []*Peer{inPeer, outPeer} {
+			disconnected := make(chan struct{})
+			go func() {
+				p.WaitForDisconnect()
+				close(disconnected)
+			}()
+			select {
+			case <-disconnected:
+			case <-time.After(time.Second):
+				t.Fatalf("%q: %s did not disconnect", test.name, p)
+			}
+		}
+		select {
+		case <-verack:
+			t.Fatalf("%q: unexpected verack", test.name)
+		default:
+		}
+		for _, p := range []*Peer{inPeer, outPeer} {
+			if got := p.Transport(); got != TransportPlaintext {
+				t.Errorf("%q: unexpected transport for %s -- got %s, want %s",
+					test.name, p, got, TransportPlaintext)
+			}
+		}
+	}
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -45,6 +45,18 @@ const (
 	NDisconnect NodeSubCmd = "disconnect"
 )
 
+// SetBanSubCmd defines the type used in the setban JSON-RPC command for the
+// sub command field.
+type SetBanSubCmd string
+
+const (
+	// SBAdd indicates the specified subnet should be banned.
+	SBAdd SetBanSubCmd = "add"
+
+	// SBRemove indicates the ban for the specified subnet should be removed.
+	SBRemove SetBanSubCmd = "remove"
+)
+
 // AddNodeCmd defines the addnode JSON-RPC command.
 type AddNodeCmd struct {
 	Addr   string
@@ -78,6 +90,15 @@ type SStxCommitOut struct {
 	ChangeAmt  int64  `json:"changeamt"`
 }
 
+// ClearBannedCmd defines the clearbanned JSON-RPC command.
+type ClearBannedCmd struct{}
+
+// NewClearBannedCmd returns a new instance which can be used to issue a
+// clearbanned JSON-RPC command.
+func NewClearBannedCmd() *ClearBannedCmd {
+	return &ClearBannedCmd{}
+}
+
 // CreateRawSStxCmd is a type handling custom marshaling and
 // unmarshaling of createrawsstx JSON RPC commands.
 type CreateRawSStxCmd struct {
@@ -185,6 +206,28 @@ func NewDecodeScriptCmd(hexScript string) *DecodeScriptCmd {
 	}
 }
 
+// DumpFeeBucketsCmd defines the dumpfeebuckets JSON-RPC command.
+type DumpFeeBucketsCmd struct{}
+
+// NewDumpFeeBucketsCmd returns a new instance which can be used to issue a
+// dumpfeebuckets JSON-RPC command.
+func NewDumpFeeBucketsCmd() *DumpFeeBucketsCmd {
+	return &DumpFeeBucketsCmd{}
+}
+
+// DumpUtxoSetCmd defines the dumputxoset JSON-RPC command.
+type DumpUtxoSetCmd struct {
+	Path string
+}
+
+// NewDumpUtxoSetCmd returns a new instance which can be used to issue a
+// dumputxoset JSON-RPC command.
+func NewDumpUtxoSetCmd(path string) *DumpUtxoSetCmd {
+	return &DumpUtxoSetCmd{
+		Path: path,
+	}
+}
+
 // EstimateFeeCmd defines the estimatefee JSON-RPC command.
 type EstimateFeeCmd struct {
 	NumBlocks int64
@@ -198,6 +241,21 @@ func NewEstimateFeeCmd(numBlocks int64) *EstimateFeeCmd {
 	}
 }
 
+// EstimateRawFeeCmd defines the estimaterawfee JSON-RPC command.
+type EstimateRawFeeCmd struct {
+	Confirmations int64
+	Threshold     *float64 `jsonrpcdefault:"0.95"`
+}
+
+// NewEstimateRawFeeCmd returns a new instance which can be used to issue an
+// estimaterawfee JSON-RPC command.
+func NewEstimateRawFeeCmd(confirmations int64, threshold *float64) *EstimateRawFeeCmd {
+	return &EstimateRawFeeCmd{
+		Confirmations: confirmations,
+		Threshold:     threshold,
+	}
+}
+
 // EstimateSmartFeeMode defines estimation mode to be used with
 // the estimatesmartfee command.
 type EstimateSmartFeeMode string
@@ -534,6 +592,55 @@ func NewGetHeadersCmd(blockLocators []string, hashStop string) *GetHeadersCmd {
 	}
 }
 
+// GetMempoolAncestorsCmd defines the getmempoolancestors JSON-RPC command.
+type GetMempoolAncestorsCmd struct {
+	TxID    string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolAncestorsCmd returns a new instance which can be used to issue
+// a getmempoolancestors JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolAncestorsCmd(txID string, verbose *bool) *GetMempoolAncestorsCmd {
+	return &GetMempoolAncestorsCmd{
+		TxID:    txID,
+		Verbose: verbose,
+	}
+}
+
+// GetMempoolDescendantsCmd defines the getmempooldescendants JSON-RPC command.
+type GetMempoolDescendantsCmd struct {
+	TxID    string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolDescendantsCmd returns a new instance which can be used to
+// issue a getmempooldescendants JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolDescendantsCmd(txID string, verbose *bool) *GetMempoolDescendantsCmd {
+	return &GetMempoolDescendantsCmd{
+		TxID:    txID,
+		Verbose: verbose,
+	}
+}
+
+// GetMempoolEntryCmd defines the getmempoolentry JSON-RPC command.
+type GetMempoolEntryCmd struct {
+	TxID string
+}
+
+// NewGetMempoolEntryCmd returns a new instance which can be used to issue a
+// getmempoolentry JSON-RPC command.
+func NewGetMempoolEntryCmd(txID string) *GetMempoolEntryCmd {
+	return &GetMempoolEntryCmd{
+		TxID: txID,
+	}
+}
+
 // GetMempoolInfoCmd defines the getmempoolinfo JSON-RPC command.
 type GetMempoolInfoCmd struct{}
 
@@ -619,6 +726,15 @@ func NewGetPeerInfoCmd() *GetPeerInfoCmd {
 	return &GetPeerInfoCmd{}
 }
 
+// GetRateLimitInfoCmd defines the getratelimitinfo JSON-RPC command.
+type GetRateLimitInfoCmd struct{}
+
+// NewGetRateLimitInfoCmd returns a new instance which can be used to issue a
+// getratelimitinfo JSON-RPC command.
+func NewGetRateLimitInfoCmd() *GetRateLimitInfoCmd {
+	return &GetRateLimitInfoCmd{}
+}
+
 // GetRawMempoolTxTypeCmd defines the type used in the getrawmempool JSON-RPC
 // command for the TxType command field.
 type GetRawMempoolTxTypeCmd string
@@ -685,6 +801,21 @@ func NewGetRawTransactionCmd(txHash string, verbose *int) *GetRawTransactionCmd
 	}
 }
 
+// GetSpendingInfoCmd defines the getspendinginfo JSON-RPC command.
+type GetSpendingInfoCmd struct {
+	Txid string
+	Vout uint32
+}
+
+// NewGetSpendingInfoCmd returns a new instance which can be used to issue a
+// getspendinginfo JSON-RPC command.
+func NewGetSpendingInfoCmd(txHash string, vout uint32) *GetSpendingInfoCmd {
+	return &GetSpendingInfoCmd{
+		Txid: txHash,
+		Vout: vout,
+	}
+}
+
 // GetStakeDifficultyCmd is a type handling custom marshaling and
 // unmarshaling of getstakedifficulty JSON RPC commands.
 type GetStakeDifficultyCmd struct{}
@@ -868,6 +999,15 @@ func NewInvalidateBlockCmd(hash string) *InvalidateBlockCmd {
 	}
 }
 
+// ListBannedCmd defines the listbanned JSON-RPC command.
+type ListBannedCmd struct{}
+
+// NewListBannedCmd returns a new instance which can be used to issue a
+// listbanned JSON-RPC command.
+func NewListBannedCmd() *ListBannedCmd {
+	return &ListBannedCmd{}
+}
+
 // LiveTicketsCmd is a type handling custom marshaling and
 // unmarshaling of livetickets JSON RPC commands.
 type LiveTicketsCmd struct{}
@@ -920,6 +1060,44 @@ func NewReconsiderBlockCmd(hash string) *ReconsiderBlockCmd {
 	}
 }
 
+// SaveMempoolCmd defines the savemempool JSON-RPC command.
+type SaveMempoolCmd struct{}
+
+// NewSaveMempoolCmd returns a new instance which can be used to issue a
+// savemempool JSON-RPC command.
+func NewSaveMempoolCmd() *SaveMempoolCmd {
+	return &SaveMempoolCmd{}
+}
+
+// SearchRawTransactionsCmd defines the searchrawtransactions JSON-RPC command.
+//
+// NOTE: The verbose field is an int versus a bool to remain consistent with
+// the getrawtransaction command.
+type SearchRawTransactionsCmd struct {
+	Address        string
+	Verbose        *int  `jsonrpcdefault:"1"`
+	Skip           *int  `jsonrpcdefault:"0"`
+	Count          *int  `jsonrpcdefault:"100"`
+	Reverse        *bool `jsonrpcdefault:"false"`
+	IncludeMempool *bool `jsonrpcdefault:"true"`
+}
+
+// NewSearchRawTransactionsCmd returns a new instance which can be used to
+// issue a searchrawtransactions JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSearchRawTransactionsCmd(address string, verbose, skip, count *int, reverse, includeMempool *bool) *SearchRawTransactionsCmd {
+	return &SearchRawTransactionsCmd{
+		Address:        address,
+		Verbose:        verbose,
+		Skip:           skip,
+		Count:          count,
+		Reverse:        reverse,
+		IncludeMempool: includeMempool,
+	}
+}
+
 // SendRawMixMessage defines the sendrawmixmessage JSON-RPC command.
 type SendRawMixMessageCmd struct {
 	Command string
@@ -953,6 +1131,30 @@ func NewSendRawTransactionCmd(hexTx string, allowHighFees *bool) *SendRawTransac
 	}
 }
 
+// SetBanCmd defines the setban JSON-RPC command.
+type SetBanCmd struct {
+	Subnet   string
+	SubCmd   SetBanSubCmd `jsonrpcusage:"\"add|remove\""`
+	BanTime  *int64       `jsonrpcdefault:"0"`
+	Absolute *bool        `jsonrpcdefault:"false"`
+	Reason   *string
+}
+
+// NewSetBanCmd returns a new instance which can be used to issue a setban
+// JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSetBanCmd(subnet string, subCmd SetBanSubCmd, banTime *int64, absolute *bool, reason *string) *SetBanCmd {
+	return &SetBanCmd{
+		Subnet:   subnet,
+		SubCmd:   subCmd,
+		BanTime:  banTime,
+		Absolute: absolute,
+		Reason:   reason,
+	}
+}
+
 // SetGenerateCmd defines the setgenerate JSON-RPC command.
 type SetGenerateCmd struct {
 	Generate     bool
@@ -1029,6 +1231,24 @@ func NewSubmitBlockCmd(hexBlock string, options *SubmitBlockOptions) *SubmitBloc
 	}
 }
 
+// TestMempoolAcceptCmd defines the testmempoolaccept JSON-RPC command.
+type TestMempoolAcceptCmd struct {
+	RawTxns       []string
+	AllowHighFees *bool `jsonrpcdefault:"false"`
+}
+
+// NewTestMempoolAcceptCmd returns a new instance which can be used to issue a
+// testmempoolaccept JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewTestMempoolAcceptCmd(rawTxns []string, allowHighFees *bool) *TestMempoolAcceptCmd {
+	return &TestMempoolAcceptCmd{
+		RawTxns:       rawTxns,
+		AllowHighFees: allowHighFees,
+	}
+}
+
 // TicketFeeInfoCmd defines the ticketfeeinfo JSON-RPC command.
 type TicketFeeInfoCmd struct {
 	Blocks  *uint32
@@ -1147,13 +1367,17 @@ func init() {
 	flags := dcrjson.UsageFlag(0)
 
 	dcrjson.MustRegister(Method("addnode"), (*AddNodeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("clearbanned"), (*ClearBannedCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawssrtx"), (*CreateRawSSRtxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawsstx"), (*CreateRawSStxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawtransaction"), (*CreateRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("debuglevel"), (*DebugLevelCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decoderawtransaction"), (*DecodeRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decodescript"), (*DecodeScriptCmd)(nil), flags)
+	dcrjson.MustRegister(Method("dumpfeebuckets"), (*DumpFeeBucketsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("dumputxoset"), (*DumpUtxoSetCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatefee"), (*EstimateFeeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("estimaterawfee"), (*EstimateRawFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatesmartfee"), (*EstimateSmartFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatestakediff"), (*EstimateStakeDiffCmd)(nil), flags)
 	dcrjson.MustRegister(Method("existsaddress"), (*ExistsAddressCmd)(nil), flags)
@@ -1181,6 +1405,9 @@ func init() {
 	dcrjson.MustRegister(Method("gethashespersec"), (*GetHashesPerSecCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getheaders"), (*GetHeadersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getinfo"), (*GetInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempoolancestors"), (*GetMempoolAncestorsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempooldescendants"), (*GetMempoolDescendantsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempoolentry"), (*GetMempoolEntryCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmempoolinfo"), (*GetMempoolInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmininginfo"), (*GetMiningInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmixmessage"), (*GetMixMessageCmd)(nil), flags)
@@ -1189,8 +1416,10 @@ func init() {
 	dcrjson.MustRegister(Method("getnettotals"), (*GetNetTotalsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnetworkhashps"), (*GetNetworkHashPSCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getpeerinfo"), (*GetPeerInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getratelimitinfo"), (*GetRateLimitInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawmempool"), (*GetRawMempoolCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawtransaction"), (*GetRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getspendinginfo"), (*GetSpendingInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakedifficulty"), (*GetStakeDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversioninfo"), (*GetStakeVersionInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversions"), (*GetStakeVersionsCmd)(nil), flags)
@@ -1203,18 +1432,23 @@ func init() {
 	dcrjson.MustRegister(Method("getwork"), (*GetWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("help"), (*HelpCmd)(nil), flags)
 	dcrjson.MustRegister(Method("invalidateblock"), (*InvalidateBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("listbanned"), (*ListBannedCmd)(nil), flags)
 	dcrjson.MustRegister(Method("livetickets"), (*LiveTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("node"), (*NodeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ping"), (*PingCmd)(nil), flags)
 	dcrjson.MustRegister(Method("reconsiderblock"), (*ReconsiderBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("regentemplate"), (*RegenTemplateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("savemempool"), (*SaveMempoolCmd)(nil), flags)
+	dcrjson.MustRegister(Method("searchrawtransactions"), (*SearchRawTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("sendrawmixmessage"), (*SendRawMixMessageCmd)(nil), flags)
 	dcrjson.MustRegister(Method("sendrawtransaction"), (*SendRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("setban"), (*SetBanCmd)(nil), flags)
 	dcrjson.MustRegister(Method("setgenerate"), (*SetGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("startprofiler"), (*StartProfilerCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stop"), (*StopCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopprofiler"), (*StopProfilerCmd)(nil), flags)
 	dcrjson.MustRegister(Method("submitblock"), (*SubmitBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("testmempoolaccept"), (*TestMempoolAcceptCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketfeeinfo"), (*TicketFeeInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketsforaddress"), (*TicketsForAddressCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketvwap"), (*TicketVWAPCmd)(nil), flags)
@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2016-2024 The Decred developers
+// Copyright (c) 2016-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -41,6 +41,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"addnode","params":["127.0.0.1","remove"],"id":1}`,
 			unmarshalled: &AddNodeCmd{Addr: "127.0.0.1", SubCmd: ANRemove},
 		},
+		{
+			name: "clearbanned",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("clearbanned"))
+			},
+			staticCmd: func() interface{} {
+				return NewClearBannedCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"clearbanned","params":[],"id":1}`,
+			unmarshalled: &ClearBannedCmd{},
+		},
 		{
 			name: "createrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -129,6 +140,30 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"decodescript","params":["00",1],"id":1}`,
 			unmarshalled: &DecodeScriptCmd{HexScript: "00", Version: dcrjson.Uint16(1)},
 		},
+		{
+			name: "dumpfeebuckets",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("dumpfeebuckets"))
+			},
+			staticCmd: func() interface{} {
+				return NewDumpFeeBucketsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"dumpfeebuckets","params":[],"id":1}`,
+			unmarshalled: &DumpFeeBucketsCmd{},
+		},
+		{
+			name: "dumputxoset",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("dumputxoset"), "utxos.dat")
+			},
+			staticCmd: func() interface{} {
+				return NewDumpUtxoSetCmd("utxos.dat")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"dumputxoset","params":["utxos.dat"],"id":1}`,
+			unmarshalled: &DumpUtxoSetCmd{
+				Path: "utxos.dat",
+			},
+		},
 		{
 			name: "estimatefee",
 			newCmd: func() (interface{}, error) {
@@ -142,6 +177,34 @@ func TestChainSvrCmds(t *testing.T) {
 				NumBlocks: 6,
 			},
 		},
+		{
+			name: "estimaterawfee",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("estimaterawfee"), 6)
+			},
+			staticCmd: func() interface{} {
+				return NewEstimateRawFeeCmd(6, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"estimaterawfee","params":[6],"id":1}`,
+			unmarshalled: &EstimateRawFeeCmd{
+				Confirmations: 6,
+				Threshold:     dcrjson.Float64(0.95),
+			},
+		},
+		{
+			name: "estimaterawfee optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("estimaterawfee"), 6, 0.85)
+			},
+			staticCmd: func() interface{} {
+				return NewEstimateRawFeeCmd(6, dcrjson.Float64(0.85))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"estimaterawfee","params":[6,0.85],"id":1}`,
+			unmarshalled: &EstimateRawFeeCmd{
+				Confirmations: 6,
+				Threshold:     dcrjson.Float64(0.85),
+			},
+		},
 		{
 			name: "estimatesmartfee",
 			newCmd: func() (interface{}, error) {
@@ -431,6 +494,73 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getinfo","params":[],"id":1}`,
 			unmarshalled: &GetInfoCmd{},
 		},
+		{
+			name: "getmempoolancestors",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolancestors"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolAncestorsCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolAncestorsCmd{
+				TxID:    "123",
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempoolancestors optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolancestors"), "123", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolAncestorsCmd("123", dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["123",true],"id":1}`,
+			unmarshalled: &GetMempoolAncestorsCmd{
+				TxID:    "123",
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getmempooldescendants",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempooldescendants"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolDescendantsCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolDescendantsCmd{
+				TxID:    "123",
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempooldescendants optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempooldescendants"), "123", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolDescendantsCmd("123", dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["123",true],"id":1}`,
+			unmarshalled: &GetMempoolDescendantsCmd{
+				TxID:    "123",
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getmempoolentry",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolentry"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolEntryCmd("123")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getmempoolentry","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolEntryCmd{TxID: "123"},
+		},
 		{
 			name: "getmempoolinfo",
 			newCmd: func() (interface{}, error) {
@@ -539,6 +669,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getpeerinfo","params":[],"id":1}`,
 			unmarshalled: &GetPeerInfoCmd{},
 		},
+		{
+			name: "getratelimitinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getratelimitinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetRateLimitInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getratelimitinfo","params":[],"id":1}`,
+			unmarshalled: &GetRateLimitInfoCmd{},
+		},
 		{
 			name: "getrawmempool",
 			newCmd: func() (interface{}, error) {
@@ -607,6 +748,20 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Int(1),
 			},
 		},
+		{
+			name: "getspendinginfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getspendinginfo"), "123", 1)
+			},
+			staticCmd: func() interface{} {
+				return NewGetSpendingInfoCmd("123", 1)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getspendinginfo","params":["123",1],"id":1}`,
+			unmarshalled: &GetSpendingInfoCmd{
+				Txid: "123",
+				Vout: 1,
+			},
+		},
 		{
 			name: "getstakeversions",
 			newCmd: func() (interface{}, error) {
@@ -771,6 +926,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Command: dcrjson.String("getblock"),
 			},
 		},
+		{
+			name: "listbanned",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("listbanned"))
+			},
+			staticCmd: func() interface{} {
+				return NewListBannedCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"listbanned","params":[],"id":1}`,
+			unmarshalled: &ListBannedCmd{},
+		},
 		{
 			name: "node option remove",
 			newCmd: func() (interface{}, error) {
@@ -825,6 +991,57 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"ping","params":[],"id":1}`,
 			unmarshalled: &PingCmd{},
 		},
+		{
+			name: "savemempool",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("savemempool"))
+			},
+			staticCmd: func() interface{} {
+				return NewSaveMempoolCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"savemempool","params":[],"id":1}`,
+			unmarshalled: &SaveMempoolCmd{},
+		},
+		{
+			name: "searchrawtransactions",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("searchrawtransactions"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsCmd("1Address", nil, nil, nil,
+					nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address"],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address:        "1Address",
+				Verbose:        dcrjson.Int(1),
+				Skip:           dcrjson.Int(0),
+				Count:          dcrjson.Int(100),
+				Reverse:        dcrjson.Bool(false),
+				IncludeMempool: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "searchrawtransactions optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("searchrawtransactions"),
+					"1Address", 0, 5, 10, true, false)
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsCmd("1Address",
+					dcrjson.Int(0), dcrjson.Int(5), dcrjson.Int(10),
+					dcrjson.Bool(true), dcrjson.Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["1Address",0,5,10,true,false],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address:        "1Address",
+				Verbose:        dcrjson.Int(0),
+				Skip:           dcrjson.Int(5),
+				Count:          dcrjson.Int(10),
+				Reverse:        dcrjson.Bool(true),
+				IncludeMempool: dcrjson.Bool(false),
+			},
+		},
 		{
 			name: "sendrawmixmessage",
 			newCmd: func() (interface{}, error) {
@@ -867,6 +1084,58 @@ func TestChainSvrCmds(t *testing.T) {
 				AllowHighFees: dcrjson.Bool(false),
 			},
 		},
+		{
+			name: "setban",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "10.0.0.0/24", SBAdd)
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("10.0.0.0/24", SBAdd, nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["10.0.0.0/24","add"],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Subnet:   "10.0.0.0/24",
+				SubCmd:   SBAdd,
+				BanTime:  dcrjson.Int64(0),
+				Absolute: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "setban optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "10.0.0.1", SBAdd,
+					1700000000, true, "spam")
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("10.0.0.1", SBAdd,
+					dcrjson.Int64(1700000000), dcrjson.Bool(true),
+					dcrjson.String("spam"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["10.0.0.1","add",1700000000,true,"spam"],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Subnet:   "10.0.0.1",
+				SubCmd:   SBAdd,
+				BanTime:  dcrjson.Int64(1700000000),
+				Absolute: dcrjson.Bool(true),
+				Reason:   dcrjson.String("spam"),
+			},
+		},
+		{
+			name: "setban remove",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "10.0.0.0/24", SBRemove)
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("10.0.0.0/24", SBRemove, nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["10.0.0.0/24","remove"],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Subnet:   "10.0.0.0/24",
+				SubCmd:   SBRemove,
+				BanTime:  dcrjson.Int64(0),
+				Absolute: dcrjson.Bool(false),
+			},
+		},
 		{
 			name: "setgenerate",
 			newCmd: func() (interface{}, error) {
@@ -978,6 +1247,34 @@ func TestChainSvrCmds(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "testmempoolaccept",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("testmempoolaccept"), []string{"1122", "3344"})
+			},
+			staticCmd: func() interface{} {
+				return NewTestMempoolAcceptCmd([]string{"1122", "3344"}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"testmempoolaccept","params":[["1122","3344"]],"id":1}`,
+			unmarshalled: &TestMempoolAcceptCmd{
+				RawTxns:       []string{"1122", "3344"},
+				AllowHighFees: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "testmempoolaccept optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("testmempoolaccept"), []string{"1122"}, true)
+			},
+			staticCmd: func() interface{} {
+				return NewTestMempoolAcceptCmd([]string{"1122"}, dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"testmempoolaccept","params":[["1122"],true],"id":1}`,
+			unmarshalled: &TestMempoolAcceptCmd{
+				RawTxns:       []string{"1122"},
+				AllowHighFees: dcrjson.Bool(true),
+			},
+		},
 		{
 			name: "validateaddress",
 			newCmd: func() (interface{}, error) {
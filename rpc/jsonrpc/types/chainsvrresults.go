@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -26,6 +26,57 @@ type DecodeScriptResult struct {
 	P2sh      string   `json:"p2sh,omitempty"`
 }
 
+// FeeBucketResult models the statistics of a fee rate bucket returned from the
+// dumpfeebuckets command.
+type FeeBucketResult struct {
+	StartRange     float64   `json:"startrange"`
+	EndRange       float64   `json:"endrange"`
+	TotalConfirmed float64   `json:"totalconfirmed"`
+	AvgFeeRate     float64   `json:"avgfeerate"`
+	Confirmed      []float64 `json:"confirmed"`
+	InMempool      []float64 `json:"inmempool"`
+}
+
+// DumpFeeBucketsResult models the data returned from the dumpfeebuckets
+// command.
+type DumpFeeBucketsResult struct {
+	BestHeight  int64             `json:"bestheight"`
+	MaxConfirms int32             `json:"maxconfirms"`
+	Decay       float64           `json:"decay"`
+	Buckets     []FeeBucketResult `json:"buckets"`
+}
+
+// DumpUtxoSetResult models the data returned from the dumputxoset command.
+type DumpUtxoSetResult struct {
+	Hash         string `json:"hash"`
+	Height       int64  `json:"height"`
+	SnapshotHash string `json:"snapshothash"`
+	Path         string `json:"path"`
+}
+
+// EstimateRawFeeBucket models the statistics of a range of fee rate buckets
+// returned from the estimaterawfee command.
+type EstimateRawFeeBucket struct {
+	StartRange     float64 `json:"startrange"`
+	EndRange       float64 `json:"endrange"`
+	WithinTarget   float64 `json:"withintarget"`
+	TotalConfirmed float64 `json:"totalconfirmed"`
+	InMempool      float64 `json:"inmempool"`
+	SuccessRatio   float64 `json:"successratio"`
+}
+
+// EstimateRawFeeResult models the data returned from the estimaterawfee
+// command.
+type EstimateRawFeeResult struct {
+	FeeRate   float64               `json:"feerate,omitempty"`
+	Decay     float64               `json:"decay"`
+	Threshold float64               `json:"threshold"`
+	Blocks    int64                 `json:"blocks"`
+	Pass      *EstimateRawFeeBucket `json:"pass,omitempty"`
+	Fail      *EstimateRawFeeBucket `json:"fail,omitempty"`
+	Errors    []string              `json:"errors,omitempty"`
+}
+
 // EstimateSmartFeeResult models the data returned from the estimatesmartfee
 // command.
 type EstimateSmartFeeResult struct {
@@ -130,6 +181,9 @@ type GetBlockChainInfoResult struct {
 	ChainWork            string                `json:"chainwork"`
 	InitialBlockDownload bool                  `json:"initialblockdownload"`
 	MaxBlockSize         int64                 `json:"maxblocksize"`
+	Pruned               bool                  `json:"pruned"`
+	PruneHeight          int64                 `json:"pruneheight,omitempty"`
+	PruneTargetSize      uint64                `json:"prunetargetsize,omitempty"`
 	Deployments          map[string]AgendaInfo `json:"deployments"`
 }
 
@@ -210,11 +264,32 @@ type InfoChainResult struct {
 	TxIndex         bool    `json:"txindex"`
 }
 
+// GetMempoolEntryResult models the data returned from the getmempoolentry
+// command as well as the getmempoolancestors and getmempooldescendants commands
+// when the verbose flag is set.
+type GetMempoolEntryResult struct {
+	Size            int32    `json:"size"`
+	Fee             float64  `json:"fee"`
+	Time            int64    `json:"time"`
+	Height          int64    `json:"height"`
+	AncestorCount   int64    `json:"ancestorcount"`
+	AncestorSize    int64    `json:"ancestorsize"`
+	AncestorFees    float64  `json:"ancestorfees"`
+	DescendantCount int64    `json:"descendantcount"`
+	DescendantSize  int64    `json:"descendantsize"`
+	DescendantFees  float64  `json:"descendantfees"`
+	PackageFeeRate  float64  `json:"packagefeerate"`
+	Depends         []string `json:"depends"`
+}
+
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
-	Size  int64 `json:"size"`
-	Bytes int64 `json:"bytes"`
+	Size          int64   `json:"size"`
+	Bytes         int64   `json:"bytes"`
+	MaxMempool    int64   `json:"maxmempool"`
+	MempoolMinFee float64 `json:"mempoolminfee"`
+	MinRelayTxFee float64 `json:"minrelaytxfee"`
 }
 
 // GetMiningInfoResult models the data from the getmininginfo command.
@@ -300,6 +375,28 @@ type GetPeerInfoResult struct {
 	CurrentHeight  int64   `json:"currentheight,omitempty"`
 	BanScore       int32   `json:"banscore"`
 	SyncNode       bool    `json:"syncnode"`
+	Transport      string  `json:"transport"`
+	MappedAS       uint32  `json:"mappedas,omitempty"`
+}
+
+// RateLimitClientResult models the token bucket state of a rate limited client
+// returned from the getratelimitinfo command.
+type RateLimitClientResult struct {
+	Type     string  `json:"type"`
+	Name     string  `json:"name"`
+	Tokens   float64 `json:"tokens"`
+	Requests uint64  `json:"requests"`
+	Limited  uint64  `json:"limited"`
+}
+
+// GetRateLimitInfoResult models the data returned from the getratelimitinfo
+// command.
+type GetRateLimitInfoResult struct {
+	Enabled bool                    `json:"enabled"`
+	Rate    float64                 `json:"rate"`
+	Burst   float64                 `json:"burst"`
+	Weights map[string]float64      `json:"weights"`
+	Clients []RateLimitClientResult `json:"clients"`
 }
 
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
@@ -334,6 +431,15 @@ type TxRawResult struct {
 	Blocktime     int64  `json:"blocktime,omitempty"`
 }
 
+// GetSpendingInfoResult models the data returned from the getspendinginfo
+// command.
+type GetSpendingInfoResult struct {
+	SpendingTxid string `json:"spendingtxid"`
+	Vin          uint32 `json:"vin"`
+	BlockHash    string `json:"blockhash"`
+	BlockHeight  int64  `json:"blockheight"`
+}
+
 // GetStakeDifficultyResult models the data returned from the
 // getstakedifficulty command.
 type GetStakeDifficultyResult struct {
@@ -479,12 +585,27 @@ type Ticket struct {
 	Owner string `json:"owner"`
 }
 
+// ListBannedResult models the data returned for each ban from the listbanned
+// command.
+type ListBannedResult struct {
+	Address     string `json:"address"`
+	BanCreated  int64  `json:"bancreated"`
+	BannedUntil int64  `json:"banneduntil"`
+	BanReason   string `json:"banreason"`
+}
+
 // LiveTicketsResult models the data returned from the livetickets
 // command.
 type LiveTicketsResult struct {
 	Tickets []string `json:"tickets"`
 }
 
+// SaveMempoolResult models the data returned from the savemempool command.
+type SaveMempoolResult struct {
+	Filename string `json:"filename"`
+	Size     int    `json:"size"`
+}
+
 // StartProfilerResult models the data returned from the startprofiler command.
 type StartProfilerResult struct {
 	Listeners []string `json:"listeners"`
@@ -533,6 +654,17 @@ type FeeInfoWindow struct {
 	StdDev      float64 `json:"stddev"`
 }
 
+// TestMempoolAcceptResult models the data returned from the testmempoolaccept
+// command for each transaction.
+type TestMempoolAcceptResult struct {
+	TxID          string   `json:"txid"`
+	Allowed       bool     `json:"allowed"`
+	RejectReason  string   `json:"rejectreason,omitempty"`
+	RejectMessage string   `json:"rejectmessage,omitempty"`
+	Fee           *float64 `json:"fee,omitempty"`
+	Size          int64    `json:"size"`
+}
+
 // TicketFeeInfoResult models the data returned from the ticketfeeinfo command.
 type TicketFeeInfoResult struct {
 	FeeInfoMempool FeeInfoMempool  `json:"feeinfomempool"`
@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2015 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -171,6 +171,25 @@ func NewStopNewNotifyMixMessagesCmd() *StopNotifyMixMessagesCmd {
 	return &StopNotifyMixMessagesCmd{}
 }
 
+// NotifyMempoolRemovalsCmd defines the notifymempoolremovals JSON-RPC command.
+type NotifyMempoolRemovalsCmd struct{}
+
+// NewNotifyMempoolRemovalsCmd returns a new instance which can be used to
+// issue a notifymempoolremovals JSON-RPC command.
+func NewNotifyMempoolRemovalsCmd() *NotifyMempoolRemovalsCmd {
+	return &NotifyMempoolRemovalsCmd{}
+}
+
+// StopNotifyMempoolRemovalsCmd defines the stopnotifymempoolremovals JSON-RPC
+// command.
+type StopNotifyMempoolRemovalsCmd struct{}
+
+// NewStopNotifyMempoolRemovalsCmd returns a new instance which can be used to
+// issue a stopnotifymempoolremovals JSON-RPC command.
+func NewStopNotifyMempoolRemovalsCmd() *StopNotifyMempoolRemovalsCmd {
+	return &StopNotifyMempoolRemovalsCmd{}
+}
+
 // SessionCmd defines the session JSON-RPC command.
 type SessionCmd struct{}
 
@@ -180,6 +199,21 @@ func NewSessionCmd() *SessionCmd {
 	return &SessionCmd{}
 }
 
+// ResumeSessionCmd defines the resumesession JSON-RPC command.
+type ResumeSessionCmd struct {
+	SessionID uint64
+	LastSeq   uint64
+}
+
+// NewResumeSessionCmd returns a new instance which can be used to issue a
+// resumesession JSON-RPC command.
+func NewResumeSessionCmd(sessionID, lastSeq uint64) *ResumeSessionCmd {
+	return &ResumeSessionCmd{
+		SessionID: sessionID,
+		LastSeq:   lastSeq,
+	}
+}
+
 // StopNotifyNewTransactionsCmd defines the stopnotifynewtransactions JSON-RPC command.
 type StopNotifyNewTransactionsCmd struct{}
 
@@ -216,12 +250,15 @@ func init() {
 	dcrjson.MustRegister(Method("notifynewtickets"), (*NotifyNewTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifywinningtickets"), (*NotifyWinningTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifymixmessages"), (*NotifyMixMessagesCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifymempoolremovals"), (*NotifyMempoolRemovalsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastwinners"), (*RebroadcastWinnersCmd)(nil), flags)
+	dcrjson.MustRegister(Method("resumesession"), (*ResumeSessionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("session"), (*SessionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifyblocks"), (*StopNotifyBlocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifywork"), (*StopNotifyWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifytspend"), (*StopNotifyTSpendCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifynewtransactions"), (*StopNotifyNewTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifymixmessages"), (*StopNotifyMixMessagesCmd)(nil), flags)
+	dcrjson.MustRegister(Method("stopnotifymempoolremovals"), (*StopNotifyMempoolRemovalsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rescan"), (*RescanCmd)(nil), flags)
 }
@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -151,6 +151,28 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifymixmessages","params":[],"id":1}`,
 			unmarshalled: &StopNotifyMixMessagesCmd{},
 		},
+		{
+			name: "notifymempoolremovals",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifymempoolremovals"))
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyMempoolRemovalsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifymempoolremovals","params":[],"id":1}`,
+			unmarshalled: &NotifyMempoolRemovalsCmd{},
+		},
+		{
+			name: "stopnotifymempoolremovals",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stopnotifymempoolremovals"))
+			},
+			staticCmd: func() interface{} {
+				return NewStopNotifyMempoolRemovalsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifymempoolremovals","params":[],"id":1}`,
+			unmarshalled: &StopNotifyMempoolRemovalsCmd{},
+		},
 		{
 			name: "notifynewtransactions",
 			newCmd: func() (interface{}, error) {
@@ -188,6 +210,20 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifynewtransactions","params":[],"id":1}`,
 			unmarshalled: &StopNotifyNewTransactionsCmd{},
 		},
+		{
+			name: "resumesession",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("resumesession"), uint64(12345), uint64(67))
+			},
+			staticCmd: func() interface{} {
+				return NewResumeSessionCmd(12345, 67)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"resumesession","params":[12345,67],"id":1}`,
+			unmarshalled: &ResumeSessionCmd{
+				SessionID: 12345,
+				LastSeq:   67,
+			},
+		},
 		{
 			name: "rescan",
 			newCmd: func() (interface{}, error) {
@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -49,6 +49,10 @@ const (
 	// transaction was accepted by the mempool.
 	RelevantTxAcceptedNtfnMethod Method = "relevanttxaccepted"
 
+	// TxRemovedNtfnMethod is the method used for notifications from the
+	// chain server that a transaction has been removed from the mempool.
+	TxRemovedNtfnMethod Method = "txremoved"
+
 	// WinningTicketsNtfnMethod is the method of the daemon winningtickets
 	// notification.
 	WinningTicketsNtfnMethod Method = "winningtickets"
@@ -193,6 +197,24 @@ func NewRelevantTxAcceptedNtfn(txHex string) *RelevantTxAcceptedNtfn {
 	return &RelevantTxAcceptedNtfn{Transaction: txHex}
 }
 
+// TxRemovedNtfn defines the txremoved JSON-RPC notification.
+//
+// The reason is one of "mined", "expired", "doublespend", "evicted",
+// "disapproved", or "invalid".
+type TxRemovedNtfn struct {
+	TxID   string `json:"txid"`
+	Reason string `json:"reason"`
+}
+
+// NewTxRemovedNtfn returns a new instance which can be used to issue a
+// txremoved JSON-RPC notification.
+func NewTxRemovedNtfn(txHash, reason string) *TxRemovedNtfn {
+	return &TxRemovedNtfn{
+		TxID:   txHash,
+		Reason: reason,
+	}
+}
+
 // WinningTicketsNtfn is a type handling custom marshaling and
 // unmarshaling of blockconnected JSON websocket notifications.
 type WinningTicketsNtfn struct {
@@ -238,6 +260,7 @@ func init() {
 	dcrjson.MustRegister(TxAcceptedNtfnMethod, (*TxAcceptedNtfn)(nil), flags)
 	dcrjson.MustRegister(TxAcceptedVerboseNtfnMethod, (*TxAcceptedVerboseNtfn)(nil), flags)
 	dcrjson.MustRegister(RelevantTxAcceptedNtfnMethod, (*RelevantTxAcceptedNtfn)(nil), flags)
+	dcrjson.MustRegister(TxRemovedNtfnMethod, (*TxRemovedNtfn)(nil), flags)
 	dcrjson.MustRegister(WinningTicketsNtfnMethod, (*WinningTicketsNtfn)(nil), flags)
 	dcrjson.MustRegister(MixMessageNtfnMethod, (*MixMessageNtfn)(nil), flags)
 }
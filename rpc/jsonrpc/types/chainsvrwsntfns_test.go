@@ -1,5 +1,5 @@
 // Copyright (c) 2014 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -129,6 +129,20 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "txremoved",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("txremoved"), "123", "mined")
+			},
+			staticNtfn: func() interface{} {
+				return NewTxRemovedNtfn("123", "mined")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"txremoved","params":["123","mined"],"id":null}`,
+			unmarshalled: &TxRemovedNtfn{
+				TxID:   "123",
+				Reason: "mined",
+			},
+		},
 		{
 			name: "winningtickets",
 			newNtfn: func() (interface{}, error) {
@@ -1,5 +1,5 @@
 // Copyright (c) 2015 The btcsuite developers
-// Copyright (c) 2015-2019 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -10,6 +10,12 @@ type SessionResult struct {
 	SessionID uint64 `json:"sessionid"`
 }
 
+// ResumeSessionResult models the data from the resumesession command.
+type ResumeSessionResult struct {
+	Replayed uint32 `json:"replayed"`
+	LastSeq  uint64 `json:"lastseq"`
+}
+
 // RescanResult models the result object returned by the rescan RPC.
 type RescanResult struct {
 	DiscoveredData []RescannedBlock `json:"discovereddata"`
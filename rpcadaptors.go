@@ -21,6 +21,7 @@ import (
 	"github.com/decred/dcrd/internal/mining/cpuminer"
 	"github.com/decred/dcrd/internal/netsync"
 	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/internal/staging/banmanager"
 	"github.com/decred/dcrd/mixing"
 	"github.com/decred/dcrd/peer/v3"
 	"github.com/decred/dcrd/wire"
@@ -108,6 +109,16 @@ func (p *rpcPeer) BanScore() uint32 {
 	return (*serverPeer)(p).banScore.Int()
 }
 
+// MappedAS returns the autonomous system number the peer address maps to or
+// zero when it does not map to one or no map is in use.
+//
+// This function is safe for concurrent access and is part of the rpcserver.Peer
+// interface implementation.
+func (p *rpcPeer) MappedAS() uint32 {
+	sp := (*serverPeer)(p)
+	return sp.server.addrManager.ASN(sp.remoteNetAddress())
+}
+
 // rpcConnManager provides a connection manager for use with the RPC server and
 // implements the rpcserver.ConnManager interface.
 type rpcConnManager struct {
@@ -179,7 +190,7 @@ func (cm *rpcConnManager) removeNode(cmp func(*serverPeer) bool) error {
 		// Update the group counts since the peer will be removed from the
 		// persistent peers just after this func returns.
 		remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-		state.outboundGroups[remoteAddr.GroupKey()]--
+		state.outboundGroups[cm.server.addrManager.GroupKey(remoteAddr)]--
 
 		connReq := sp.connReq.Load()
 		peerLog.Debugf("Removing persistent peer %s (reqid %d)", remoteAddr,
@@ -255,7 +266,7 @@ func (cm *rpcConnManager) disconnectNode(cmp func(sp *serverPeer) bool) error {
 			// Update the group counts since the peer will be removed from the
 			// persistent peers just after this func returns.
 			remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-			state.outboundGroups[remoteAddr.GroupKey()]--
+			state.outboundGroups[cm.server.addrManager.GroupKey(remoteAddr)]--
 		})
 		if !found {
 			break
@@ -386,6 +397,62 @@ func (*rpcConnManager) Lookup(host string) ([]net.IP, error) {
 	return dcrdLookup(host)
 }
 
+// BanSubnet bans the provided subnet until the provided expiration time for
+// the provided reason and disconnects any connected peers within it.
+// Attempting to ban a subnet that is already banned will return an error.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) BanSubnet(subnet *net.IPNet, expires time.Time, reason string) error {
+	err := cm.server.banList.Ban(subnet, expires, reason, false)
+	if err != nil {
+		return err
+	}
+	srvrLog.Infof("Banned subnet %s until %v: %s", subnet, expires, reason)
+
+	// Disconnect all peers within the banned subnet.
+	cm.server.peerState.ForAllPeers(func(sp *serverPeer) {
+		if subnet.Contains(sp.NA().IP) {
+			srvrLog.Infof("Disconnecting banned peer %s", sp)
+			sp.Disconnect()
+		}
+	})
+	return nil
+}
+
+// UnbanSubnet removes the ban for the provided subnet.  Attempting to unban a
+// subnet that is not banned will return an error.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) UnbanSubnet(subnet *net.IPNet) error {
+	if err := cm.server.banList.Unban(subnet); err != nil {
+		return err
+	}
+	srvrLog.Infof("Removed ban for subnet %s", subnet)
+	return nil
+}
+
+// BannedSubnets returns all currently banned subnets.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) BannedSubnets() []banmanager.BanEntry {
+	return cm.server.banList.Entries()
+}
+
+// ClearBans removes all bans.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) ClearBans() error {
+	if err := cm.server.banList.Clear(); err != nil {
+		return err
+	}
+	srvrLog.Info("Removed all bans")
+	return nil
+}
+
 // rpcSyncMgr provides an adaptor for use with the RPC server and implements the
 // rpcserver.SyncManager interface.
 type rpcSyncMgr struct {
@@ -432,6 +499,12 @@ func (b *rpcSyncMgr) SyncHeight() int64 {
 func (b *rpcSyncMgr) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
 	allowHighFees bool, tag mempool.Tag) ([]*dcrutil.Tx, error) {
 
+	// Start locally submitted transactions in the stem phase when stem relay
+	// is enabled.  They are relayed along the stem route when the server
+	// relays them.
+	if cfg.StemRelay && !allowOrphans {
+		return b.server.txMemPool.ProcessStemTransaction(tx, allowHighFees, tag)
+	}
 	return b.server.txMemPool.ProcessTransaction(tx, allowOrphans,
 		allowHighFees, tag)
 }
@@ -558,6 +631,50 @@ func (s *rpcSanityChecker) CheckBlockSanity(block *dcrutil.Block) error {
 	return blockchain.CheckBlockSanity(block, s.timeSource, s.chainParams)
 }
 
+// rpcMempoolSaver provides a means to persist the mempool to disk for use with
+// the RPC server and implements the rpcserver.MempoolSaver interface.
+type rpcMempoolSaver struct {
+	server *server
+}
+
+// Ensure rpcMempoolSaver implements the rpcserver.MempoolSaver interface.
+var _ rpcserver.MempoolSaver = (*rpcMempoolSaver)(nil)
+
+// SaveMempool writes all transactions in the mempool to the mempool dump file
+// and returns the path to the file along with the number of transactions
+// written.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.MempoolSaver interface implementation.
+func (m *rpcMempoolSaver) SaveMempool() (string, int, error) {
+	numTxns, err := m.server.saveMempool()
+	if err != nil {
+		return "", 0, err
+	}
+	return m.server.mempoolDumpFile, numTxns, nil
+}
+
+// rpcUtxoSnapshotter provides a means to write chain state snapshots to disk
+// for use with the RPC server and implements the rpcserver.UtxoSnapshotter
+// interface.
+type rpcUtxoSnapshotter struct {
+	server *server
+}
+
+// Ensure rpcUtxoSnapshotter implements the rpcserver.UtxoSnapshotter
+// interface.
+var _ rpcserver.UtxoSnapshotter = (*rpcUtxoSnapshotter)(nil)
+
+// DumpUtxoSnapshot writes a chain state snapshot of the current main chain tip
+// to the file at the provided path and returns the full path to the file along
+// with the information that identifies the snapshot.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.UtxoSnapshotter interface implementation.
+func (u *rpcUtxoSnapshotter) DumpUtxoSnapshot(ctx context.Context, path string) (string, *blockchain.SnapshotInfo, error) {
+	return u.server.dumpUtxoSnapshot(ctx, path)
+}
+
 // rpcBlockTemplater provides a block template generator for use with the
 // RPC server and implements the rpcserver.BlockTemplater interface.
 type rpcBlockTemplater struct {
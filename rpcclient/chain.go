@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -602,6 +602,188 @@ func (c *Client) GetRawMempoolVerbose(ctx context.Context, txType chainjson.GetR
 	return c.GetRawMempoolVerboseAsync(ctx, txType).Receive()
 }
 
+// FutureGetMempoolEntryResult is a future promise to deliver the result of a
+// GetMempoolEntryAsync RPC invocation (or an applicable error).
+type FutureGetMempoolEntryResult cmdRes
+
+// Receive waits for the response promised by the future and returns a data
+// structure with information about the transaction in the memory pool.
+func (r *FutureGetMempoolEntryResult) Receive() (*chainjson.GetMempoolEntryResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a getmempoolentry result object.
+	var entry chainjson.GetMempoolEntryResult
+	err = json.Unmarshal(res, &entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetMempoolEntryAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetMempoolEntry for the blocking version and more details.
+func (c *Client) GetMempoolEntryAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolEntryResult {
+	cmd := chainjson.NewGetMempoolEntryCmd(txHash.String())
+	return (*FutureGetMempoolEntryResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolEntry returns a data structure with information about the provided
+// transaction in the memory pool, including its in-mempool ancestors and
+// descendants.
+func (c *Client) GetMempoolEntry(ctx context.Context, txHash *chainhash.Hash) (*chainjson.GetMempoolEntryResult, error) {
+	return c.GetMempoolEntryAsync(ctx, txHash).Receive()
+}
+
+// FutureGetMempoolAncestorsResult is a future promise to deliver the result of
+// a GetMempoolAncestorsAsync RPC invocation (or an applicable error).
+type FutureGetMempoolAncestorsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the hashes
+// of all transactions in the memory pool that the provided transaction depends on.
+func (r *FutureGetMempoolAncestorsResult) Receive() ([]*chainhash.Hash, error) {
+	return (*FutureGetRawMempoolResult)(r).Receive()
+}
+
+// GetMempoolAncestorsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetMempoolAncestors for the blocking version and more details.
+func (c *Client) GetMempoolAncestorsAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolAncestorsResult {
+	cmd := chainjson.NewGetMempoolAncestorsCmd(txHash.String(), dcrjson.Bool(false))
+	return (*FutureGetMempoolAncestorsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolAncestors returns the hashes of all transactions in the memory pool
+// that the provided transaction depends on.
+//
+// See GetMempoolAncestorsVerbose to retrieve data structures with information
+// about the transactions instead.
+func (c *Client) GetMempoolAncestors(ctx context.Context, txHash *chainhash.Hash) ([]*chainhash.Hash, error) {
+	return c.GetMempoolAncestorsAsync(ctx, txHash).Receive()
+}
+
+// FutureGetMempoolAncestorsVerboseResult is a future promise to deliver the
+// result of a GetMempoolAncestorsVerboseAsync RPC invocation (or an applicable
+// error).
+type FutureGetMempoolAncestorsVerboseResult cmdRes
+
+// Receive waits for the response promised by the future and returns a map of
+// transaction hashes to an associated data structure with information about the
+// transaction for all transactions in the memory pool that the provided transaction depends on.
+func (r *FutureGetMempoolAncestorsVerboseResult) Receive() (map[string]chainjson.GetMempoolEntryResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a map of strings (tx hashes) to their detailed
+	// results.
+	var entries map[string]chainjson.GetMempoolEntryResult
+	err = json.Unmarshal(res, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetMempoolAncestorsVerboseAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetMempoolAncestorsVerbose for the blocking version and more details.
+func (c *Client) GetMempoolAncestorsVerboseAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolAncestorsVerboseResult {
+	cmd := chainjson.NewGetMempoolAncestorsCmd(txHash.String(), dcrjson.Bool(true))
+	return (*FutureGetMempoolAncestorsVerboseResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolAncestorsVerbose returns a map of transaction hashes to an
+// associated data structure with information about the transaction for all
+// transactions in the memory pool that the provided transaction depends on.
+//
+// See GetMempoolAncestors to retrieve only the transaction hashes instead.
+func (c *Client) GetMempoolAncestorsVerbose(ctx context.Context, txHash *chainhash.Hash) (map[string]chainjson.GetMempoolEntryResult, error) {
+	return c.GetMempoolAncestorsVerboseAsync(ctx, txHash).Receive()
+}
+
+// FutureGetMempoolDescendantsResult is a future promise to deliver the result of
+// a GetMempoolDescendantsAsync RPC invocation (or an applicable error).
+type FutureGetMempoolDescendantsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the hashes
+// of all transactions in the memory pool that depend on the provided transaction.
+func (r *FutureGetMempoolDescendantsResult) Receive() ([]*chainhash.Hash, error) {
+	return (*FutureGetRawMempoolResult)(r).Receive()
+}
+
+// GetMempoolDescendantsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetMempoolDescendants for the blocking version and more details.
+func (c *Client) GetMempoolDescendantsAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolDescendantsResult {
+	cmd := chainjson.NewGetMempoolDescendantsCmd(txHash.String(), dcrjson.Bool(false))
+	return (*FutureGetMempoolDescendantsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolDescendants returns the hashes of all transactions in the memory pool
+// that depend on the provided transaction.
+//
+// See GetMempoolDescendantsVerbose to retrieve data structures with information
+// about the transactions instead.
+func (c *Client) GetMempoolDescendants(ctx context.Context, txHash *chainhash.Hash) ([]*chainhash.Hash, error) {
+	return c.GetMempoolDescendantsAsync(ctx, txHash).Receive()
+}
+
+// FutureGetMempoolDescendantsVerboseResult is a future promise to deliver the
+// result of a GetMempoolDescendantsVerboseAsync RPC invocation (or an applicable
+// error).
+type FutureGetMempoolDescendantsVerboseResult cmdRes
+
+// Receive waits for the response promised by the future and returns a map of
+// transaction hashes to an associated data structure with information about the
+// transaction for all transactions in the memory pool that depend on the provided transaction.
+func (r *FutureGetMempoolDescendantsVerboseResult) Receive() (map[string]chainjson.GetMempoolEntryResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a map of strings (tx hashes) to their detailed
+	// results.
+	var entries map[string]chainjson.GetMempoolEntryResult
+	err = json.Unmarshal(res, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetMempoolDescendantsVerboseAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetMempoolDescendantsVerbose for the blocking version and more details.
+func (c *Client) GetMempoolDescendantsVerboseAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolDescendantsVerboseResult {
+	cmd := chainjson.NewGetMempoolDescendantsCmd(txHash.String(), dcrjson.Bool(true))
+	return (*FutureGetMempoolDescendantsVerboseResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolDescendantsVerbose returns a map of transaction hashes to an
+// associated data structure with information about the transaction for all
+// transactions in the memory pool that depend on the provided transaction.
+//
+// See GetMempoolDescendants to retrieve only the transaction hashes instead.
+func (c *Client) GetMempoolDescendantsVerbose(ctx context.Context, txHash *chainhash.Hash) (map[string]chainjson.GetMempoolEntryResult, error) {
+	return c.GetMempoolDescendantsVerboseAsync(ctx, txHash).Receive()
+}
+
 // FutureValidateAddressResult is a future promise to deliver the result of a
 // ValidateAddressAsync RPC invocation (or an applicable error).
 type FutureValidateAddressResult cmdRes
@@ -855,6 +1037,53 @@ func (c *Client) GetTxOut(ctx context.Context, txHash *chainhash.Hash, index uin
 	return c.GetTxOutAsync(ctx, txHash, index, tree, mempool).Receive()
 }
 
+// FutureGetSpendingInfoResult is a future promise to deliver the result of a
+// GetSpendingInfoAsync RPC invocation (or an applicable error).
+type FutureGetSpendingInfoResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// details of the transaction that spent the requested output.
+func (r *FutureGetSpendingInfoResult) Receive() (*chainjson.GetSpendingInfoResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getspendinginfo result object.
+	var spendingInfo chainjson.GetSpendingInfoResult
+	err = json.Unmarshal(res, &spendingInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spendingInfo, nil
+}
+
+// GetSpendingInfoAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetSpendingInfo for the blocking version and more details.
+func (c *Client) GetSpendingInfoAsync(ctx context.Context, txHash *chainhash.Hash, index uint32) *FutureGetSpendingInfoResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+
+	cmd := chainjson.NewGetSpendingInfoCmd(hash, index)
+	return (*FutureGetSpendingInfoResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetSpendingInfo returns the hash of the transaction that spent the provided
+// output in the main chain along with the index of the spending input and the
+// block that contains it.
+//
+// NOTE: Chain servers do not typically provide this capability unless the
+// spend index has specifically been enabled.
+func (c *Client) GetSpendingInfo(ctx context.Context, txHash *chainhash.Hash, index uint32) (*chainjson.GetSpendingInfoResult, error) {
+	return c.GetSpendingInfoAsync(ctx, txHash, index).Receive()
+}
+
 // FutureRescanResult is a future promise to deliver the result of a
 // RescanAsynnc RPC invocation (or an applicable error).
 type FutureRescanResult cmdRes
@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2015 The btcsuite developers
-// Copyright (c) 2015-2022 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -758,6 +758,62 @@ func (c *Client) Session(ctx context.Context) (*chainjson.SessionResult, error)
 	return c.SessionAsync(ctx).Receive()
 }
 
+// FutureResumeSessionResult is a future promise to deliver the result of a
+// ResumeSessionAsync RPC invocation (or an applicable error).
+type FutureResumeSessionResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// resumesession result.
+func (r *FutureResumeSessionResult) Receive() (*chainjson.ResumeSessionResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a resumesession result object.
+	var result chainjson.ResumeSessionResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ResumeSessionAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ResumeSession for the blocking version and more details.
+//
+// NOTE: This is a Decred extension.
+func (c *Client) ResumeSessionAsync(ctx context.Context, sessionID, lastSeq uint64) *FutureResumeSessionResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureResumeSessionResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	cmd := chainjson.NewResumeSessionCmd(sessionID, lastSeq)
+	return (*FutureResumeSessionResult)(c.sendCmd(ctx, cmd))
+}
+
+// ResumeSession resumes the session of a previous websocket connection and has
+// the server replay the blockconnected, blockdisconnected, and
+// relevanttxaccepted notifications with a sequence number after the provided
+// one.  An RPC error with the dcrjson.ErrRPCRescanRequired code is returned
+// when the server is unable to replay all of them.
+//
+// The client automatically resumes the session of the previous connection on
+// reconnect, so this only needs to be called directly by callers that manage
+// their own connections.
+//
+// This RPC requires the client to be running in websocket mode.
+//
+// NOTE: This is a Decred extension.
+func (c *Client) ResumeSession(ctx context.Context, sessionID, lastSeq uint64) (*chainjson.ResumeSessionResult, error) {
+	return c.ResumeSessionAsync(ctx, sessionID, lastSeq).Receive()
+}
+
 // FutureTicketFeeInfoResult is a future promise to deliver the result of a
 // TicketFeeInfoAsync RPC invocation (or an applicable error).
 type FutureTicketFeeInfoResult cmdRes
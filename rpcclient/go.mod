@@ -4,10 +4,10 @@ go 1.18
 
 require (
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.5
-	github.com/decred/dcrd/dcrjson/v4 v4.2.0
+	github.com/decred/dcrd/dcrjson/v4 v4.3.0
 	github.com/decred/dcrd/dcrutil/v4 v4.0.3
 	github.com/decred/dcrd/gcs/v4 v4.1.1
-	github.com/decred/dcrd/rpc/jsonrpc/types/v4 v4.3.0
+	github.com/decred/dcrd/rpc/jsonrpc/types/v4 v4.5.0
 	github.com/decred/dcrd/txscript/v4 v4.1.2
 	github.com/decred/dcrd/wire v1.7.1
 	github.com/decred/go-socks v1.1.0
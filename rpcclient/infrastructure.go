@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -177,6 +177,16 @@ type Client struct {
 	ntfnStateLock sync.Mutex
 	ntfnState     *notificationState
 
+	// Websocket session tracking used to resume the session of the previous
+	// connection on reconnect so the server replays the notifications that
+	// were missed while disconnected.  These fields are protected by
+	// sessionMtx.
+	sessionMtx      sync.Mutex
+	sessionID       uint64
+	haveSession     bool
+	fetchingSession bool
+	lastNtfnSeq     uint64
+
 	// Networking infrastructure.
 	sendChan        chan []byte
 	sendPostChan    chan *sendPostDetails
@@ -293,6 +303,10 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 
 	case *chainjson.NotifyBlocksCmd:
 		c.ntfnState.notifyBlocks = true
+		c.trackSession()
+
+	case *chainjson.LoadTxFilterCmd:
+		c.trackSession()
 
 	case *chainjson.NotifyNewTransactionsCmd:
 		if bcmd.Verbose != nil && *bcmd.Verbose {
@@ -306,6 +320,9 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 
 	case *chainjson.NotifyTSpendCmd:
 		c.ntfnState.notifyTSpend = true
+
+	case *chainjson.NotifyMempoolRemovalsCmd:
+		c.ntfnState.notifyTxRemoved = true
 	}
 }
 
@@ -325,6 +342,7 @@ type (
 	rawNotification struct {
 		Method string            `json:"method"`
 		Params []json.RawMessage `json:"params"`
+		Seq    *uint64           `json:"seq"`
 	}
 
 	// rawResponse is a partially-unmarshaled JSON-RPC response.  For this
@@ -383,6 +401,14 @@ func (c *Client) handleMessage(msg []byte) {
 			log.Warn("Malformed notification: missing params")
 			return
 		}
+		// Keep track of the sequence number of the most recent
+		// notification so the session can be resumed on reconnect.
+		if ntfn.Seq != nil {
+			c.sessionMtx.Lock()
+			c.lastNtfnSeq = *ntfn.Seq
+			c.sessionMtx.Unlock()
+		}
+
 		// Deliver the notification.
 		log.Tracef("Received notification [%s]", in.Method)
 		c.handleNotification(in.rawNotification)
@@ -539,6 +565,85 @@ func (c *Client) sendMessage(marshalledJSON []byte) {
 	}
 }
 
+// trackSession starts tracking the websocket session of the current connection
+// by requesting its ID when it is not already known so the session can be
+// resumed on reconnect.  The request is made in a separate goroutine since
+// this is called from the input handler.
+func (c *Client) trackSession() {
+	c.sessionMtx.Lock()
+	defer c.sessionMtx.Unlock()
+
+	if c.haveSession || c.fetchingSession {
+		return
+	}
+	c.fetchingSession = true
+	go func() {
+		session, err := c.Session(context.Background())
+		c.sessionMtx.Lock()
+		c.fetchingSession = false
+		if err == nil && !c.haveSession {
+			c.sessionID = session.SessionID
+			c.haveSession = true
+		}
+		c.sessionMtx.Unlock()
+		if err != nil {
+			log.Debugf("Unable to determine websocket session: %v", err)
+		}
+	}()
+}
+
+// resumeSession attempts to resume the websocket session of the previous
+// connection so the server replays the block and relevant transaction
+// notifications that were missed while disconnected.  The OnRescanRequired
+// notification handler is invoked when the server is unable to replay all of
+// them.  It should only be called on reconnect by the resendRequests function.
+func (c *Client) resumeSession(ctx context.Context) {
+	// Nothing to do if the caller is not interested in notifications.
+	if c.ntfnHandlers == nil {
+		return
+	}
+
+	// The sequence numbers of the notifications on the new connection start
+	// over unless the session is resumed.
+	c.sessionMtx.Lock()
+	sessionID, haveSession := c.sessionID, c.haveSession
+	lastSeq := c.lastNtfnSeq
+	c.haveSession = false
+	c.lastNtfnSeq = 0
+	c.sessionMtx.Unlock()
+	if !haveSession {
+		return
+	}
+
+	log.Debugf("Resuming session %d after notification %d", sessionID,
+		lastSeq)
+	result, err := c.ResumeSession(ctx, sessionID, lastSeq)
+	if err != nil {
+		var rpcErr *dcrjson.RPCError
+		if errors.As(err, &rpcErr) &&
+			rpcErr.Code == dcrjson.ErrRPCRescanRequired {
+
+			log.Infof("Unable to resume session %d: %v", sessionID, err)
+			if c.ntfnHandlers.OnRescanRequired != nil {
+				c.ntfnHandlers.OnRescanRequired()
+			}
+			return
+		}
+
+		// Servers that do not support resuming sessions are not treated
+		// as an error.
+		log.Debugf("Unable to resume session %d: %v", sessionID, err)
+		return
+	}
+
+	c.sessionMtx.Lock()
+	c.sessionID = sessionID
+	c.haveSession = true
+	c.sessionMtx.Unlock()
+	log.Debugf("Resumed session %d (%d notifications replayed)", sessionID,
+		result.Replayed)
+}
+
 // reregisterNtfns creates and sends commands needed to re-establish the current
 // notification state associated with the client.  It should only be called on
 // reconnect by the resendRequests function.
@@ -610,6 +715,14 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 		}
 	}
 
+	// Reregister notifymempoolremovals if needed.
+	if stateCopy.notifyTxRemoved {
+		log.Debugf("Reregistering [notifymempoolremovals]")
+		if err := c.NotifyMempoolRemovals(ctx); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -623,6 +736,10 @@ var ignoreResends = map[string]struct{}{
 // disconnected.  It is intended to be called once the client has reconnected as
 // a separate goroutine.
 func (c *Client) resendRequests(ctx context.Context) {
+	// Resume the session of the previous connection so any missed
+	// notifications are replayed prior to reregistering for notifications.
+	c.resumeSession(ctx)
+
 	// Set the notification state back up.  If anything goes wrong,
 	// disconnect the client.
 	if err := c.reregisterNtfns(ctx); err != nil {
@@ -1122,8 +1239,18 @@ func (c *Client) WaitForShutdown() {
 type ConnConfig struct {
 	// Host is the IP address and port of the RPC server you want to connect
 	// to.
+	//
+	// It is only used as the HTTP host when UnixSocket is set and defaults
+	// to localhost in that case.
 	Host string
 
+	// UnixSocket is the path of a Unix domain socket to connect to the RPC
+	// server over instead of connecting to Host.
+	//
+	// Unix domain sockets do not use TLS, so DisableTLS must be set and
+	// Proxy must not be set when it is specified.
+	UnixSocket string
+
 	// Endpoint is the websocket endpoint on the RPC server.  This is
 	// typically "ws".
 	Endpoint string
@@ -1215,6 +1342,16 @@ type ConnConfig struct {
 // newHTTPClient returns a new http client that is configured according to the
 // proxy and TLS settings in the associated connection configuration.
 func newHTTPClient(config *ConnConfig) (*http.Client, error) {
+	// Connect over the Unix domain socket when one is configured.
+	if config.UnixSocket != "" {
+		client := http.Client{
+			Transport: &http.Transport{
+				DialContext: unixSocketDialer(config.UnixSocket),
+			},
+		}
+		return &client, nil
+	}
+
 	// Set proxy function if there is a proxy configured.
 	var proxyFunc func(*http.Request) (*url.URL, error)
 	if config.Proxy != "" {
@@ -1255,6 +1392,15 @@ func newHTTPClient(config *ConnConfig) (*http.Client, error) {
 	return &client, nil
 }
 
+// unixSocketDialer returns a dial function that connects to the Unix domain
+// socket at the provided path regardless of the requested network address.
+func unixSocketDialer(path string) func(context.Context, string, string) (net.Conn, error) {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+}
+
 // dial opens a websocket connection using the passed connection configuration
 // details.
 func dial(config *ConnConfig) (*websocket.Conn, error) {
@@ -1295,6 +1441,11 @@ func dial(config *ConnConfig) (*websocket.Conn, error) {
 		dialer.NetDial = proxy.Dial
 	}
 
+	// Connect over the Unix domain socket when one is configured.
+	if config.UnixSocket != "" {
+		dialer.NetDialContext = unixSocketDialer(config.UnixSocket)
+	}
+
 	// The RPC server requires basic authorization, so create a custom
 	// request header with the Authorization header set.
 	login := config.User + ":" + config.Pass
@@ -1378,6 +1529,17 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 	default:
 		return nil, fmt.Errorf("unsupported authorization method %q", config.AuthType)
 	}
+	if config.UnixSocket != "" {
+		if !config.DisableTLS {
+			return nil, fmt.Errorf("disabletls must be set when connecting over a unix domain socket")
+		}
+		if config.Proxy != "" {
+			return nil, fmt.Errorf("proxy must not be set when connecting over a unix domain socket")
+		}
+		if config.Host == "" {
+			config.Host = "localhost"
+		}
+	}
 
 	// Either open a websocket connection or create an HTTP client depending
 	// on the HTTP POST mode.  Also, set the notification handlers to nil
@@ -1,10 +1,19 @@
-// Copyright (c) 2018 The Decred developers
+// Copyright (c) 2018-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
 package rpcclient
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
 
 func TestClientStringer(t *testing.T) {
 	type test struct {
@@ -36,3 +45,60 @@ func TestClientStringer(t *testing.T) {
 		}
 	}
 }
+
+// TestUnixSocket ensures clients in HTTP POST mode are able to issue requests
+// over a Unix domain socket and that the configuration is validated.
+func TestUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix domain sockets are not reliably available on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "rpc.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("unable to listen on %s: %v", path, err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"result":1234,"error":null,"id":%d}`, req.ID)
+	})}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	// Ensure TLS and proxies are rejected.
+	badConfigs := []*ConnConfig{
+		{UnixSocket: path, HTTPPostMode: true},
+		{UnixSocket: path, HTTPPostMode: true, DisableTLS: true,
+			Proxy: "127.0.0.1:9050"},
+	}
+	for _, cfg := range badConfigs {
+		if _, err := New(cfg, nil); err == nil {
+			t.Errorf("did not receive expected error for config %+v", cfg)
+		}
+	}
+
+	c, err := New(&ConnConfig{
+		UnixSocket:   path,
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	defer c.Shutdown()
+	count, err := c.GetBlockCount(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected request error: %v", err)
+	}
+	if count != 1234 {
+		t.Fatalf("unexpected block count: got %d, want 1234", count)
+	}
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2015 The btcsuite developers
-// Copyright (c) 2015-2022 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,7 +8,9 @@ package rpcclient
 import (
 	"context"
 	"encoding/json"
+	"time"
 
+	"github.com/decred/dcrd/dcrjson/v4"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 )
 
@@ -322,3 +324,129 @@ func (c *Client) GetNetworkInfoAsync(ctx context.Context) *FutureGetNetworkInfoR
 func (c *Client) GetNetworkInfo(ctx context.Context) (*chainjson.GetNetworkInfoResult, error) {
 	return c.GetNetworkInfoAsync(ctx).Receive()
 }
+
+// SetBanCommand enumerates the available commands that the SetBan function
+// accepts.
+type SetBanCommand string
+
+// Constants used to indicate the command for the SetBan function.
+const (
+	// SBAdd indicates the specified subnet should be banned.
+	SBAdd SetBanCommand = "add"
+
+	// SBRemove indicates the ban for the specified subnet should be removed.
+	SBRemove SetBanCommand = "remove"
+)
+
+// String returns the SetBanCommand in human-readable form.
+func (cmd SetBanCommand) String() string {
+	return string(cmd)
+}
+
+// FutureSetBanResult is a future promise to deliver the result of a
+// SetBanAsync RPC invocation (or an applicable error).
+type FutureSetBanResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when performing the specified command.
+func (r *FutureSetBanResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// SetBanAsync returns an instance of a type that can be used to get the result
+// of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See SetBan for the blocking version and more details.
+func (c *Client) SetBanAsync(ctx context.Context, subnet string, command SetBanCommand, until time.Time, reason string) *FutureSetBanResult {
+	var banTime *int64
+	var absolute *bool
+	if !until.IsZero() {
+		unix := until.Unix()
+		banTime = &unix
+		absolute = dcrjson.Bool(true)
+	}
+	var reasonParam *string
+	if reason != "" {
+		reasonParam = &reason
+	}
+	cmd := chainjson.NewSetBanCmd(subnet, chainjson.SetBanSubCmd(command),
+		banTime, absolute, reasonParam)
+	return (*FutureSetBanResult)(c.sendCmd(ctx, cmd))
+}
+
+// SetBan attempts to perform the passed command on the passed IP address or
+// subnet in CIDR notation.  For example, it can be used to ban a subnet until
+// the provided time for the provided reason or to remove an existing ban.
+//
+// A zero time bans the subnet for the default ban duration of the server and
+// an empty reason uses the default reason.  Both are ignored when removing a
+// ban.
+func (c *Client) SetBan(ctx context.Context, subnet string, command SetBanCommand, until time.Time, reason string) error {
+	return c.SetBanAsync(ctx, subnet, command, until, reason).Receive()
+}
+
+// FutureListBannedResult is a future promise to deliver the result of a
+// ListBannedAsync RPC invocation (or an applicable error).
+type FutureListBannedResult cmdRes
+
+// Receive waits for the response promised by the future and returns all
+// banned IP addresses and subnets.
+func (r *FutureListBannedResult) Receive() ([]chainjson.ListBannedResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of listbanned result objects.
+	var bans []chainjson.ListBannedResult
+	err = json.Unmarshal(res, &bans)
+	if err != nil {
+		return nil, err
+	}
+
+	return bans, nil
+}
+
+// ListBannedAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ListBanned for the blocking version and more details.
+func (c *Client) ListBannedAsync(ctx context.Context) *FutureListBannedResult {
+	cmd := chainjson.NewListBannedCmd()
+	return (*FutureListBannedResult)(c.sendCmd(ctx, cmd))
+}
+
+// ListBanned returns all banned IP addresses and subnets along with when each
+// ban was created, when it expires, and the reason for it.
+func (c *Client) ListBanned(ctx context.Context) ([]chainjson.ListBannedResult, error) {
+	return c.ListBannedAsync(ctx).Receive()
+}
+
+// FutureClearBannedResult is a future promise to deliver the result of a
+// ClearBannedAsync RPC invocation (or an applicable error).
+type FutureClearBannedResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when clearing the bans.
+func (r *FutureClearBannedResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// ClearBannedAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ClearBanned for the blocking version and more details.
+func (c *Client) ClearBannedAsync(ctx context.Context) *FutureClearBannedResult {
+	cmd := chainjson.NewClearBannedCmd()
+	return (*FutureClearBannedResult)(c.sendCmd(ctx, cmd))
+}
+
+// ClearBanned removes all bans.
+func (c *Client) ClearBanned(ctx context.Context) error {
+	return c.ClearBannedAsync(ctx).Receive()
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2022 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -40,6 +40,7 @@ type notificationState struct {
 	notifyNewTickets     bool
 	notifyNewTx          bool
 	notifyNewTxVerbose   bool
+	notifyTxRemoved      bool
 }
 
 // Copy returns a deep copy of the receiver.
@@ -52,6 +53,7 @@ func (s *notificationState) Copy() *notificationState {
 	stateCopy.notifyNewTickets = s.notifyNewTickets
 	stateCopy.notifyNewTx = s.notifyNewTx
 	stateCopy.notifyNewTxVerbose = s.notifyNewTxVerbose
+	stateCopy.notifyTxRemoved = s.notifyTxRemoved
 
 	return &stateCopy
 }
@@ -147,6 +149,23 @@ type NotificationHandlers struct {
 	// made to register for the notification and the function is non-nil.
 	OnTxAcceptedVerbose func(txDetails *chainjson.TxRawResult)
 
+	// OnTxRemoved is invoked when a transaction is removed from the memory
+	// pool along with the reason it was removed.  It will only be invoked if
+	// a preceding call to NotifyMempoolRemovals has been made to register for
+	// the notification and the function is non-nil.
+	OnTxRemoved func(hash *chainhash.Hash, reason string)
+
+	// OnRescanRequired is invoked after reconnecting to the RPC server when
+	// the session of the previous connection could not be resumed because
+	// the server is no longer able to replay all of the blockconnected,
+	// blockdisconnected, and relevanttxaccepted notifications that were
+	// missed while disconnected.  Callers that rely on those notifications
+	// should perform a rescan to recover the missed data.  Sessions are only
+	// resumed for clients that registered for block notifications or loaded
+	// a transaction filter.  This callback is run async with the rest of the
+	// notification handlers, and is safe for blocking client requests.
+	OnRescanRequired func()
+
 	// OnUnknownNotification is invoked when an unrecognized notification
 	// is received.  This typically means the notification handling code
 	// for this package needs to be updated for a new notification type or
@@ -342,6 +361,22 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		c.ntfnHandlers.OnTxAcceptedVerbose(rawTx)
 
+	// OnTxRemoved
+	case chainjson.TxRemovedNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnTxRemoved == nil {
+			return
+		}
+
+		hash, reason, err := parseTxRemovedNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid tx removed notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnTxRemoved(hash, reason)
+
 	default:
 		if c.ntfnHandlers.OnUnknownNotification == nil {
 			log.Tracef("unknown notification received")
@@ -689,6 +724,36 @@ func parseTxAcceptedVerboseNtfnParams(params []json.RawMessage) (*chainjson.TxRa
 	return &rawTx, nil
 }
 
+// parseTxRemovedNtfnParams parses out the transaction hash and removal reason
+// from the parameters of a txremoved notification.
+func parseTxRemovedNtfnParams(params []json.RawMessage) (*chainhash.Hash, string, error) {
+	if len(params) != 2 {
+		return nil, "", wrongNumParams(len(params))
+	}
+
+	// Unmarshal first parameter as a string.
+	var txHashStr string
+	err := json.Unmarshal(params[0], &txHashStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Unmarshal second parameter as a string.
+	var reason string
+	err = json.Unmarshal(params[1], &reason)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Decode string encoding of transaction hash.
+	txHash, err := chainhash.NewHashFromStr(txHashStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return txHash, reason, nil
+}
+
 // FutureNotifyBlocksResult is a future promise to deliver the result of a
 // NotifyBlocksAsync RPC invocation (or an applicable error).
 type FutureNotifyBlocksResult cmdRes
@@ -973,6 +1038,54 @@ func (c *Client) NotifyNewTransactions(ctx context.Context, verbose bool) error
 	return c.NotifyNewTransactionsAsync(ctx, verbose).Receive()
 }
 
+// FutureNotifyMempoolRemovalsResult is a future promise to deliver the result
+// of a NotifyMempoolRemovalsAsync RPC invocation (or an applicable error).
+type FutureNotifyMempoolRemovalsResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r *FutureNotifyMempoolRemovalsResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// NotifyMempoolRemovalsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyMempoolRemovals for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyMempoolRemovalsAsync(ctx context.Context) *FutureNotifyMempoolRemovalsResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyMempoolRemovalsResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyMempoolRemovalsResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyMempoolRemovalsCmd()
+	return (*FutureNotifyMempoolRemovalsResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyMempoolRemovals registers the client to receive notifications every
+// time a transaction is removed from the memory pool.  The notifications are
+// delivered to the notification handlers associated with the client.  Calling
+// this function has no effect if there are no notification handlers and will
+// result in an error if the client is configured to run in HTTP POST mode.
+//
+// The notifications delivered as a result of this call will be via
+// OnTxRemoved.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyMempoolRemovals(ctx context.Context) error {
+	return c.NotifyMempoolRemovalsAsync(ctx).Receive()
+}
+
 // FutureLoadTxFilterResult is a future promise to deliver the result
 // of a LoadTxFilterAsync RPC invocation (or an applicable error).
 type FutureLoadTxFilterResult cmdRes
@@ -1,5 +1,5 @@
 // Copyright (c) 2014-2016 The btcsuite developers
-// Copyright (c) 2015-2023 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -121,6 +121,119 @@ func (c *Client) GetRawTransactionVerbose(ctx context.Context, txHash *chainhash
 	return c.GetRawTransactionVerboseAsync(ctx, txHash).Receive()
 }
 
+// FutureSearchRawTransactionsResult is a future promise to deliver the result
+// of the SearchRawTransactionsAsync RPC invocation (or an applicable error).
+type FutureSearchRawTransactionsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// found raw transactions.
+func (r *FutureSearchRawTransactionsResult) Receive() ([]*wire.MsgTx, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal as an array of strings.
+	var searchRawTxnsResult []string
+	err = json.Unmarshal(res, &searchRawTxnsResult)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode and deserialize each transaction.
+	msgTxns := make([]*wire.MsgTx, 0, len(searchRawTxnsResult))
+	for _, hexTx := range searchRawTxnsResult {
+		// Decode the serialized transaction hex to raw bytes.
+		serializedTx, err := hex.DecodeString(hexTx)
+		if err != nil {
+			return nil, err
+		}
+
+		// Deserialize the transaction and add it to the result slice.
+		var msgTx wire.MsgTx
+		err = msgTx.Deserialize(bytes.NewReader(serializedTx))
+		if err != nil {
+			return nil, err
+		}
+		msgTxns = append(msgTxns, &msgTx)
+	}
+
+	return msgTxns, nil
+}
+
+// SearchRawTransactionsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SearchRawTransactions for the blocking version and more details.
+func (c *Client) SearchRawTransactionsAsync(ctx context.Context, address stdaddr.Address, skip, count int, reverse, includeMempool bool) *FutureSearchRawTransactionsResult {
+	addr := address.String()
+	verbose := dcrjson.Int(0)
+	cmd := chainjson.NewSearchRawTransactionsCmd(addr, verbose, &skip, &count,
+		&reverse, &includeMempool)
+	return (*FutureSearchRawTransactionsResult)(c.sendCmd(ctx, cmd))
+}
+
+// SearchRawTransactions returns transactions that involve the passed address.
+//
+// NOTE: Chain servers do not typically provide this capability unless it has
+// specifically been enabled.
+//
+// See SearchRawTransactionsVerbose to retrieve a list of data structures with
+// information about the transactions instead of the transactions themselves.
+func (c *Client) SearchRawTransactions(ctx context.Context, address stdaddr.Address, skip, count int, reverse, includeMempool bool) ([]*wire.MsgTx, error) {
+	return c.SearchRawTransactionsAsync(ctx, address, skip, count, reverse,
+		includeMempool).Receive()
+}
+
+// FutureSearchRawTransactionsVerboseResult is a future promise to deliver the
+// result of the SearchRawTransactionsVerboseAsync RPC invocation (or an
+// applicable error).
+type FutureSearchRawTransactionsVerboseResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// found raw transactions.
+func (r *FutureSearchRawTransactionsVerboseResult) Receive() ([]*chainjson.TxRawResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal as an array of raw transaction results.
+	var result []*chainjson.TxRawResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SearchRawTransactionsVerboseAsync returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the
+// Receive function on the returned instance.
+//
+// See SearchRawTransactionsVerbose for the blocking version and more details.
+func (c *Client) SearchRawTransactionsVerboseAsync(ctx context.Context, address stdaddr.Address, skip, count int, reverse, includeMempool bool) *FutureSearchRawTransactionsVerboseResult {
+	addr := address.String()
+	verbose := dcrjson.Int(1)
+	cmd := chainjson.NewSearchRawTransactionsCmd(addr, verbose, &skip, &count,
+		&reverse, &includeMempool)
+	return (*FutureSearchRawTransactionsVerboseResult)(c.sendCmd(ctx, cmd))
+}
+
+// SearchRawTransactionsVerbose returns a list of data structures that describe
+// transactions which involve the passed address.
+//
+// NOTE: Chain servers do not typically provide this capability unless it has
+// specifically been enabled.
+//
+// See SearchRawTransactions to retrieve a list of raw transactions instead.
+func (c *Client) SearchRawTransactionsVerbose(ctx context.Context, address stdaddr.Address, skip, count int, reverse, includeMempool bool) ([]*chainjson.TxRawResult, error) {
+	return c.SearchRawTransactionsVerboseAsync(ctx, address, skip, count,
+		reverse, includeMempool).Receive()
+}
+
 // FutureDecodeRawTransactionResult is a future promise to deliver the result
 // of a DecodeRawTransactionAsync RPC invocation (or an applicable error).
 type FutureDecodeRawTransactionResult cmdRes
@@ -394,3 +507,52 @@ func (c *Client) SendRawTransactionAsync(ctx context.Context, tx *wire.MsgTx, al
 func (c *Client) SendRawTransaction(ctx context.Context, tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
 	return c.SendRawTransactionAsync(ctx, tx, allowHighFees).Receive()
 }
+
+// FutureTestMempoolAcceptResult is a future promise to deliver the result of a
+// TestMempoolAcceptAsync RPC invocation (or an applicable error).
+type FutureTestMempoolAcceptResult cmdRes
+
+// Receive waits for the response promised by the future and returns whether
+// or not each of the tested transactions would be accepted to the mempool.
+func (r *FutureTestMempoolAcceptResult) Receive() ([]chainjson.TestMempoolAcceptResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of testmempoolaccept result objects.
+	var results []chainjson.TestMempoolAcceptResult
+	err = json.Unmarshal(res, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// TestMempoolAcceptAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See TestMempoolAccept for the blocking version and more details.
+func (c *Client) TestMempoolAcceptAsync(ctx context.Context, txns []*wire.MsgTx, allowHighFees bool) *FutureTestMempoolAcceptResult {
+	// Serialize the transactions and convert them to hex strings.
+	txHexes := make([]string, 0, len(txns))
+	for _, tx := range txns {
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return (*FutureTestMempoolAcceptResult)(newFutureError(ctx, err))
+		}
+		txHexes = append(txHexes, hex.EncodeToString(buf.Bytes()))
+	}
+
+	cmd := chainjson.NewTestMempoolAcceptCmd(txHexes, &allowHighFees)
+	return (*FutureTestMempoolAcceptResult)(c.sendCmd(ctx, cmd))
+}
+
+// TestMempoolAccept returns whether or not the provided transactions would be
+// accepted to the mempool of the server without submitting them.  The
+// transactions may spend outputs of earlier transactions in the provided slice.
+func (c *Client) TestMempoolAccept(ctx context.Context, txns []*wire.MsgTx, allowHighFees bool) ([]chainjson.TestMempoolAcceptResult, error) {
+	return c.TestMempoolAcceptAsync(ctx, txns, allowHighFees).Receive()
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/decred/dcrd/addrmgr/v3"
@@ -44,13 +45,16 @@ import (
 	"github.com/decred/dcrd/internal/mining/cpuminer"
 	"github.com/decred/dcrd/internal/netsync"
 	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/internal/staging/banmanager"
 	"github.com/decred/dcrd/internal/version"
+	"github.com/decred/dcrd/internal/zmq"
 	"github.com/decred/dcrd/math/uint256"
 	"github.com/decred/dcrd/mixing"
 	"github.com/decred/dcrd/mixing/mixpool"
 	"github.com/decred/dcrd/peer/v3"
 	"github.com/decred/dcrd/txscript/v4"
 	"github.com/decred/dcrd/wire"
+	"github.com/decred/go-socks/socks"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -76,8 +80,12 @@ const (
 	// number of retries such that there is a retry backoff.
 	connectionRetryInterval = time.Second * 5
 
+	// feelerHandshakeTimeout is the maximum amount of time to wait for a
+	// feeler connection to complete the version handshake.
+	feelerHandshakeTimeout = time.Second * 30
+
 	// maxProtocolVersion is the max protocol version the server supports.
-	maxProtocolVersion = wire.BatchedCFiltersV2Version
+	maxProtocolVersion = wire.StemTxVersion
 
 	// These fields are used to track known addresses on a per-peer basis.
 	//
@@ -135,6 +143,13 @@ const (
 	// expensive lottery data calculations for them.
 	maxReorgDepthNotify = 6
 
+	// maxHighBandwidthCmpctPeers is the maximum number of peers, not
+	// including persistent peers, that are asked to announce new blocks by
+	// sending compact blocks directly instead of first announcing their
+	// headers.  The peers that most recently delivered a new best chain tip
+	// first are selected.
+	maxHighBandwidthCmpctPeers = 3
+
 	// These fields are used to track recently confirmed transactions.
 	//
 	// maxRecentlyConfirmedTxns specifies the maximum number to track and is set
@@ -343,7 +358,7 @@ func (sc *naSubmissionCache) bestSubmission(net addrmgr.NetAddressType) *naSubmi
 }
 
 // peerState houses state of inbound, persistent, and outbound peers as well
-// as banned peers and outbound groups.
+// as outbound groups.
 type peerState struct {
 	sync.Mutex
 
@@ -351,7 +366,6 @@ type peerState struct {
 	inboundPeers    map[int32]*serverPeer
 	outboundPeers   map[int32]*serverPeer
 	persistentPeers map[int32]*serverPeer
-	banned          map[string]time.Time
 	outboundGroups  map[string]int
 
 	// subCache houses the network address submission cache and is protected
@@ -360,14 +374,12 @@ type peerState struct {
 }
 
 // makePeerState returns a peer state instance that is used to maintain the
-// state of inbound, persistent, and outbound peers as well as banned peers and
-// outbound groups.
+// state of inbound, persistent, and outbound peers as well as outbound groups.
 func makePeerState() peerState {
 	return peerState{
 		inboundPeers:    make(map[int32]*serverPeer),
 		persistentPeers: make(map[int32]*serverPeer),
 		outboundPeers:   make(map[int32]*serverPeer),
-		banned:          make(map[string]time.Time),
 		outboundGroups:  make(map[string]int),
 		subCache: &naSubmissionCache{
 			cache: make(map[string]*naSubmission, maxCachedNaSubmissions),
@@ -603,6 +615,8 @@ type server struct {
 	relayInv             chan relayMsg
 	broadcast            chan broadcastMsg
 	nat                  *upnpNAT
+	metricsServer        *metricsServer
+	zmqPublisher         *zmq.Publisher
 	db                   database.DB
 	timeSource           blockchain.MedianTimeSource
 	services             wire.ServiceFlag
@@ -614,8 +628,33 @@ type server struct {
 	// do not need to be protected for concurrent access.
 	indexSubscriber *indexers.IndexSubscriber
 	txIndex         *indexers.TxIndex
+	addrIndex       *indexers.AddrIndex
+	spendIndex      *indexers.SpendIndex
 	existsAddrIndex *indexers.ExistsAddrIndex
 
+	// mempoolDumpFile is the path to the file the mempool is persisted to.
+	// The mutex serializes writes to the file and mempoolLoaded is set once
+	// any existing dump has been restored to the mempool which prevents a
+	// partially restored mempool from overwriting it.
+	mempoolDumpFile string
+	mempoolDumpMtx  sync.Mutex
+	mempoolLoaded   atomic.Bool
+
+	// snapshotValidator validates the history prior to the chain state
+	// snapshot the chain was created from in the background.  It is nil when
+	// the chain was not created from a snapshot or the history has already
+	// been validated.
+	snapshotValidator *snapshotValidator
+
+	// banList houses the banned subnets and persists them to disk so they
+	// survive restarts.
+	banList *banmanager.BanList
+
+	// anchors houses the outbound peers that were in use during the previous
+	// shutdown.  They are connected to first on startup.  It is nil when
+	// running in connect-only mode.
+	anchors *anchorPeers
+
 	// These following fields are used to filter duplicate block lottery data
 	// anouncements.
 	lotteryDataBroadcastMtx sync.Mutex
@@ -625,6 +664,17 @@ type server struct {
 	// most recent blocks.
 	recentlyConfirmedTxns *apbf.Filter
 
+	// hbCmpctPeers houses the non-persistent peers that have been asked to
+	// announce new blocks via compact blocks in high bandwidth mode ordered
+	// from least to most recently selected.  It is protected by the
+	// associated mutex.
+	hbCmpctPeersMtx sync.Mutex
+	hbCmpctPeers    []*serverPeer
+
+	// stemRelay handles relaying transactions in the stem phase of
+	// Dandelion-style stem/fluff transaction relay.
+	stemRelay *stemRelay
+
 	// recentlyAdvertisedTxns caches transactions that have recently been
 	// advertised to other peers.  The cache handles automatic expiration and
 	// maximum entry limiting.
@@ -676,6 +726,11 @@ type serverPeer struct {
 	isWhitelisted bool
 	quit          chan struct{}
 
+	// overlayAddr is the address of outbound peers on overlay networks, such
+	// as Tor and I2P, that do not have an IP address.  It is nil for all other
+	// peers.
+	overlayAddr *addrmgr.NetAddress
+
 	// syncMgrPeer houses the network sync manager peer instance that wraps the
 	// underlying peer similar to the way this server peer itself wraps it.
 	syncMgrPeer *netsync.Peer
@@ -684,11 +739,12 @@ type serverPeer struct {
 	// otherwise modified during operation and thus need to consider whether or
 	// not they need to be protected for concurrent access.
 
-	connReq        atomic.Pointer[connmgr.ConnReq]
-	continueHash   atomic.Pointer[chainhash.Hash]
-	disableRelayTx atomic.Bool
-	knownAddresses *apbf.Filter
-	banScore       connmgr.DynamicBanScore
+	connReq          atomic.Pointer[connmgr.ConnReq]
+	continueHash     atomic.Pointer[chainhash.Hash]
+	disableRelayTx   atomic.Bool
+	wantsCmpctBlocks atomic.Bool
+	knownAddresses   *apbf.Filter
+	banScore         connmgr.DynamicBanScore
 
 	// addrsSent, getMiningStateSent and initState track whether or not the peer
 	// has already sent the respective request.  They are used to prevent more
@@ -762,9 +818,18 @@ func (sp *serverPeer) handleServeGetData(invVects []*wire.InvVect,
 			// to maintain a full transaction index which can be expensive.
 			// That ability is restricted to authenticated RPC only and requires
 			// the aforementioned full transaction index.
+			//
+			// Also note that transactions in the stem phase are never served
+			// via this path since that would allow peers to probe for them.
 			txHash := &iv.Hash
 			tx, ok := sp.server.recentlyAdvertisedTxns.Get(*txHash)
 			if !ok {
+				if sp.server.txMemPool.IsStemTransaction(txHash) {
+					peerLog.Debugf("Not serving stem tx %v to peer %s via a "+
+						"regular tx request", txHash, sp)
+					break
+				}
+
 				// Note that a call could be made to check for existence first,
 				// but simply trying to fetch a missing transaction results in
 				// the same behavior.
@@ -778,6 +843,18 @@ func (sp *serverPeer) handleServeGetData(invVects []*wire.InvVect,
 			}
 			dataMsg = tx.MsgTx()
 
+		case wire.InvTypeStemTx:
+			// Only serve transactions in the stem phase to the peer they were
+			// relayed to.
+			txHash := &iv.Hash
+			tx := sp.server.stemRelay.StemTx(sp, txHash)
+			if tx == nil {
+				peerLog.Debugf("Unable to fetch stem tx %v for peer %s", txHash,
+					sp)
+				break
+			}
+			dataMsg = tx.MsgTx()
+
 		case wire.InvTypeBlock:
 			blockHash := &iv.Hash
 			block, err := sp.server.chain.BlockByHash(blockHash)
@@ -806,6 +883,27 @@ func (sp *serverPeer) handleServeGetData(invVects []*wire.InvVect,
 			continueHash := sp.continueHash.Load()
 			sendInv = continueHash != nil && *continueHash == *blockHash
 
+		case wire.InvTypeCmpctBlock:
+			blockHash := &iv.Hash
+			block, err := sp.server.chain.BlockByHash(blockHash)
+			if err != nil {
+				peerLog.Debugf("Unable to fetch block hash %v for peer %s: %v",
+					blockHash, sp, err)
+				break
+			}
+
+			// Serve the full block when the peer does not support compact
+			// blocks or the block is too deep in the chain for the peer to
+			// reasonably be expected to have its transactions.
+			best := sp.server.chain.BestSnapshot()
+			if sp.ProtocolVersion() < wire.CompactBlockVersion ||
+				!netsync.CmpctBlockDepthOK(block.Height(), best.Height) {
+
+				dataMsg = block.MsgBlock()
+				break
+			}
+			dataMsg = netsync.NewCmpctBlock(block, rand.Uint64())
+
 		case wire.InvTypeMix:
 			mixHash := &iv.Hash
 			msg, ok := sp.server.mixMsgPool.RecentMessage(mixHash)
@@ -931,6 +1029,8 @@ func (sp *serverPeer) Run() {
 	srvr := sp.server
 	srvr.DonePeer(sp)
 	srvr.syncManager.OnPeerDisconnected(sp.syncMgrPeer)
+	srvr.removeHighBandwidthCmpctPeer(sp)
+	srvr.stemRelay.RemovePeer(sp)
 
 	if sp.VersionKnown() {
 		// Evict any remaining orphans that were sent by the peer.
@@ -998,12 +1098,107 @@ func addrmgrToWireNetAddress(netAddr *addrmgr.NetAddress) *wire.NetAddress {
 		netAddr.IP, netAddr.Port)
 }
 
-// pushAddrMsg sends an addr message to the connected peer using the provided
-// addresses.
+// isOverlayNetAddrType returns whether or not the provided network address type
+// belongs to an overlay network, such as Tor or I2P, that does not have an IP
+// address.
+func isOverlayNetAddrType(addrType addrmgr.NetAddressType) bool {
+	return addrType == addrmgr.TorV3Address || addrType == addrmgr.I2PAddress
+}
+
+// overlayNetAddress returns the address manager net address for the provided
+// address string when it refers to a host on an overlay network.  It returns
+// nil otherwise.
+func overlayNetAddress(addr string) *addrmgr.NetAddress {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil
+	}
+	addrType, addrBytes := addrmgr.EncodeHost(host)
+	if !isOverlayNetAddrType(addrType) {
+		return nil
+	}
+	na, err := addrmgr.NewNetAddressFromParams(addrType, addrBytes,
+		uint16(port), time.Now(), 0)
+	if err != nil {
+		return nil
+	}
+	return na
+}
+
+// remoteNetAddress returns the address manager net address of the peer.  Peers
+// on overlay networks are identified by their overlay address since they do
+// not have an IP address.
+func (sp *serverPeer) remoteNetAddress() *addrmgr.NetAddress {
+	if sp.overlayAddr != nil {
+		na := *sp.overlayAddr
+		na.Services = sp.NA().Services
+		return &na
+	}
+	return wireToAddrmgrNetAddress(sp.NA())
+}
+
+// wireToAddrmgrNetAddressV2 converts a wire NetAddressV2 to an address manager
+// NetAddress.  An error is returned when the address type is not supported by
+// the address manager or the encoded address is invalid for its type.
+func wireToAddrmgrNetAddressV2(netAddr *wire.NetAddressV2) (*addrmgr.NetAddress, error) {
+	var addrType addrmgr.NetAddressType
+	switch netAddr.Type {
+	case wire.IPv4Address:
+		addrType = addrmgr.IPv4Address
+	case wire.IPv6Address:
+		addrType = addrmgr.IPv6Address
+	case wire.TorV3Address:
+		addrType = addrmgr.TorV3Address
+	case wire.I2PAddress:
+		addrType = addrmgr.I2PAddress
+	default:
+		return nil, fmt.Errorf("unsupported network address type %v",
+			netAddr.Type)
+	}
+	return addrmgr.NewNetAddressFromParams(addrType, netAddr.EncodedAddr,
+		netAddr.Port, netAddr.Timestamp, netAddr.Services)
+}
+
+// addrmgrToWireNetAddressV2 converts an address manager net address to a wire
+// NetAddressV2.  It returns nil when the address type is not supported by the
+// addrv2 wire message.
+func addrmgrToWireNetAddressV2(netAddr *addrmgr.NetAddress) *wire.NetAddressV2 {
+	var addrType wire.NetAddressType
+	switch netAddr.Type {
+	case addrmgr.IPv4Address:
+		addrType = wire.IPv4Address
+	case addrmgr.IPv6Address:
+		addrType = wire.IPv6Address
+	case addrmgr.TorV3Address:
+		addrType = wire.TorV3Address
+	case addrmgr.I2PAddress:
+		addrType = wire.I2PAddress
+	default:
+		return nil
+	}
+	return wire.NewNetAddressV2(netAddr.Timestamp, netAddr.Services, addrType,
+		netAddr.IP, netAddr.Port)
+}
+
+// pushAddrMsg sends an addr or addrv2 message, depending on the negotiated
+// protocol version, to the connected peer using the provided addresses.
 func (sp *serverPeer) pushAddrMsg(addresses []*addrmgr.NetAddress) {
-	// Filter addresses already known to the peer.
+	if sp.ProtocolVersion() >= wire.AddrV2Version {
+		sp.pushAddrV2Msg(addresses)
+		return
+	}
+
+	// Filter addresses already known to the peer along with those that are
+	// not supported by the addr message.
 	addrs := make([]*wire.NetAddress, 0, len(addresses))
 	for _, addr := range addresses {
+		if !isSupportedNetAddrTypeV1(addr.Type) {
+			continue
+		}
 		if !sp.addressKnown(addr) {
 			wireNetAddr := addrmgrToWireNetAddress(addr)
 			addrs = append(addrs, wireNetAddr)
@@ -1020,6 +1215,35 @@ func (sp *serverPeer) pushAddrMsg(addresses []*addrmgr.NetAddress) {
 	sp.addKnownAddresses(knownNetAddrs)
 }
 
+// pushAddrV2Msg sends an addrv2 message to the connected peer using the
+// provided addresses.
+func (sp *serverPeer) pushAddrV2Msg(addresses []*addrmgr.NetAddress) {
+	// Filter addresses already known to the peer.
+	addrs := make([]*wire.NetAddressV2, 0, len(addresses))
+	for _, addr := range addresses {
+		if sp.addressKnown(addr) {
+			continue
+		}
+		if wireNetAddr := addrmgrToWireNetAddressV2(addr); wireNetAddr != nil {
+			addrs = append(addrs, wireNetAddr)
+		}
+	}
+	known, err := sp.PushAddrV2Msg(addrs)
+	if err != nil {
+		peerLog.Errorf("Can't push address message to %s: %v", sp, err)
+		sp.Disconnect()
+		return
+	}
+
+	for _, wireNetAddr := range known {
+		na, err := wireToAddrmgrNetAddressV2(wireNetAddr)
+		if err != nil {
+			continue
+		}
+		sp.addKnownAddress(na)
+	}
+}
+
 // addBanScore increases the persistent and decaying ban score fields by the
 // values passed as parameters. If the resulting score exceeds half of the ban
 // threshold, a warning is logged including the reason provided. Further, if
@@ -1071,9 +1295,40 @@ func isSupportedNetAddrTypeV1(addrType addrmgr.NetAddressType) bool {
 	return addrType == addrmgr.IPv4Address || addrType == addrmgr.IPv6Address
 }
 
+// isReachableNetAddrType returns whether or not addresses of the provided
+// network address type are reachable with the current configuration.  Tor v3
+// onion services require a proxy that is assumed to be Tor and I2P destinations
+// require an I2P proxy.
+func isReachableNetAddrType(addrType addrmgr.NetAddressType) bool {
+	switch addrType {
+	case addrmgr.IPv4Address, addrmgr.IPv6Address:
+		return true
+	case addrmgr.TorV3Address:
+		return !cfg.NoOnion && (cfg.OnionProxy != "" || cfg.Proxy != "")
+	case addrmgr.I2PAddress:
+		return cfg.I2PProxy != ""
+	}
+	return false
+}
+
+// isSupportedNetAddrTypeV2 is a filter which returns whether the provided
+// network address type is supported by the addrv2 wire message.
+func isSupportedNetAddrTypeV2(addrType addrmgr.NetAddressType) bool {
+	switch addrType {
+	case addrmgr.IPv4Address, addrmgr.IPv6Address, addrmgr.TorV3Address,
+		addrmgr.I2PAddress:
+
+		return true
+	}
+	return false
+}
+
 // natfSupported returns a filter for the address types supported by the
 // protocol version.
 func natfSupported(pver uint32) addrmgr.NetAddressTypeFilter {
+	if pver >= wire.AddrV2Version {
+		return isSupportedNetAddrTypeV2
+	}
 	return isSupportedNetAddrTypeV1
 }
 
@@ -1092,7 +1347,7 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
 	// it is updated regardless in the case a new minimum protocol version is
 	// enforced and the remote node has not upgraded yet.
 	isInbound := sp.Inbound()
-	remoteAddr := wireToAddrmgrNetAddress(sp.NA())
+	remoteAddr := sp.remoteNetAddress()
 	addrManager := sp.server.addrManager
 	if !cfg.SimNet && !cfg.RegNet && !isInbound {
 		err := addrManager.SetServices(remoteAddr, msg.Services)
@@ -1201,8 +1456,26 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
 // OnVerAck is invoked when a peer receives a verack wire message.  It creates
 // and sends a sendheaders message to request all block annoucements are made
 // via full headers instead of the inv message.
+//
+// It also announces support for compact blocks to peers that support them.
+// Persistent peers are always asked to announce new blocks via compact blocks
+// in high bandwidth mode while other peers are only asked to do so once they
+// prove to be among the first to deliver new blocks.
 func (sp *serverPeer) OnVerAck(_ *peer.Peer, msg *wire.MsgVerAck) {
 	sp.QueueMessage(wire.NewMsgSendHeaders(), nil)
+	if sp.ProtocolVersion() >= wire.CompactBlockVersion {
+		sp.QueueMessage(wire.NewMsgSendCmpct(sp.persistent,
+			wire.CmpctBlockEncodingVersion), nil)
+	}
+}
+
+// OnSendCmpct is invoked when a peer receives a sendcmpct wire message.  It
+// records whether or not the peer wants new blocks to be announced via compact
+// blocks and informs the net sync manager of its support for them.
+func (sp *serverPeer) OnSendCmpct(_ *peer.Peer, msg *wire.MsgSendCmpct) {
+	supported := msg.Version == wire.CmpctBlockEncodingVersion
+	sp.wantsCmpctBlocks.Store(supported && msg.HighBandwidth)
+	sp.server.syncManager.OnSendCmpct(sp.syncMgrPeer, msg)
 }
 
 // OnMemPool is invoked when a peer receives a mempool wire message.  It creates
@@ -1449,6 +1722,13 @@ func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
 	iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
 	sp.AddKnownInventory(iv)
 
+	// Handle transactions that were requested in the stem phase separately
+	// since they are not known to the net sync manager.
+	if sp.server.stemRelay.TakeRequest(sp, tx.Hash()) {
+		sp.handleStemTx(tx)
+		return
+	}
+
 	// Handle the transaction with the net sync manager.  Notice that this
 	// intentionally blocks further receives until the transaction is fully
 	// processed and known good or bad.  This helps prevent a malicious peer
@@ -1463,6 +1743,27 @@ func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
 	}
 }
 
+// handleStemTx attempts to add the provided transaction that was received in
+// the stem phase from the peer to the mempool and relays it accordingly when it
+// is accepted.
+func (sp *serverPeer) handleStemTx(tx *dcrutil.Tx) {
+	srvr := sp.server
+	acceptedTxns, err := srvr.txMemPool.ProcessStemTransaction(tx, false,
+		mempool.Tag(sp.ID()))
+	if err != nil {
+		peerLog.Debugf("Rejected stem tx %v from %s: %v", tx.Hash(), sp, err)
+		return
+	}
+
+	// Relay the transaction in the stem phase and announce any orphans that
+	// were accepted as a result of it being accepted as usual.  Notice that
+	// websocket and ZeroMQ clients are notified of the transaction itself
+	// immediately since they are local.
+	srvr.stemRelay.Relay(sp, tx)
+	srvr.relayTransactions(acceptedTxns[1:])
+	srvr.notifyNewTransactions(acceptedTxns)
+}
+
 // OnBlock is invoked when a peer receives a block wire message.  It blocks
 // until the network block has been fully processed.
 func (sp *serverPeer) OnBlock(_ *peer.Peer, msg *wire.MsgBlock, buf []byte) {
@@ -1479,7 +1780,68 @@ func (sp *serverPeer) OnBlock(_ *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	// and known good or bad.  This helps prevent a malicious peer from queuing
 	// up a bunch of bad blocks before disconnecting (or being disconnected) and
 	// wasting memory.
+	wasKnown := sp.server.chain.HaveBlock(block.Hash())
 	sp.server.syncManager.OnBlock(sp.syncMgrPeer, block)
+	sp.server.maybeSelectHighBandwidthCmpctPeer(sp, block.Hash(), wasKnown)
+}
+
+// OnCmpctBlock is invoked when a peer receives a cmpctblock wire message.  The
+// message is passed down to the net sync manager which reconstructs the block
+// and requests any missing transactions.
+func (sp *serverPeer) OnCmpctBlock(_ *peer.Peer, msg *wire.MsgCmpctBlock) {
+	blockHash := msg.BlockHash()
+	iv := wire.NewInvVect(wire.InvTypeBlock, &blockHash)
+	sp.AddKnownInventory(iv)
+
+	wasKnown := sp.server.chain.HaveBlock(&blockHash)
+	sp.server.syncManager.OnCmpctBlock(sp.syncMgrPeer, msg)
+	sp.server.maybeSelectHighBandwidthCmpctPeer(sp, &blockHash, wasKnown)
+}
+
+// OnBlockTxns is invoked when a peer receives a blocktxns wire message.  The
+// message is passed down to the net sync manager which completes the
+// reconstruction of the associated compact block.
+func (sp *serverPeer) OnBlockTxns(_ *peer.Peer, msg *wire.MsgBlockTxns) {
+	wasKnown := sp.server.chain.HaveBlock(&msg.BlockHash)
+	sp.server.syncManager.OnBlockTxns(sp.syncMgrPeer, msg)
+	sp.server.maybeSelectHighBandwidthCmpctPeer(sp, &msg.BlockHash, wasKnown)
+}
+
+// OnGetBlockTxns is invoked when a peer receives a getblocktxns wire message.
+// It responds with the requested transactions of a recent block the peer
+// received as a compact block.
+func (sp *serverPeer) OnGetBlockTxns(_ *peer.Peer, msg *wire.MsgGetBlockTxns) {
+	block, err := sp.server.chain.BlockByHash(&msg.BlockHash)
+	if err != nil {
+		peerLog.Debugf("Unable to fetch block hash %v for peer %s: %v",
+			msg.BlockHash, sp, err)
+		return
+	}
+
+	// Only allow transactions from recent blocks to be requested since
+	// compact blocks are not served for older blocks.
+	best := sp.server.chain.BestSnapshot()
+	if !netsync.CmpctBlockDepthOK(block.Height(), best.Height) {
+		peerLog.Debugf("%s requested transactions for block %v at height %d "+
+			"which is too old -- disconnecting", sp, msg.BlockHash,
+			block.Height())
+		sp.Disconnect()
+		return
+	}
+
+	txns := block.MsgBlock().Transactions
+	respTxns := make([]*wire.MsgTx, 0, len(msg.Indexes))
+	for _, idx := range msg.Indexes {
+		if idx >= uint32(len(txns)) {
+			reason := fmt.Sprintf("requested transaction index %d of block "+
+				"%v which only has %d transactions", idx, msg.BlockHash,
+				len(txns))
+			sp.server.BanPeer(sp, reason)
+			return
+		}
+		respTxns = append(respTxns, txns[idx])
+	}
+	sp.QueueMessage(wire.NewMsgBlockTxns(&msg.BlockHash, respTxns), nil)
 }
 
 // OnInv is invoked when a peer receives an inv wire message and is used to
@@ -1495,14 +1857,17 @@ func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
 	}
 
 	if !cfg.BlocksOnly {
-		sp.server.syncManager.OnInv(sp.syncMgrPeer, msg)
+		msg = sp.handleStemInv(msg)
+		if len(msg.InvList) > 0 {
+			sp.server.syncManager.OnInv(sp.syncMgrPeer, msg)
+		}
 		return
 	}
 
 	for _, invVect := range msg.InvList {
 		var typ string
 		switch invVect.Type {
-		case wire.InvTypeTx:
+		case wire.InvTypeTx, wire.InvTypeStemTx:
 			typ = "transactions"
 		case wire.InvTypeMix:
 			typ = "mix messages"
@@ -1517,6 +1882,52 @@ func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
 	sp.server.syncManager.OnInv(sp.syncMgrPeer, msg)
 }
 
+// handleStemInv requests any transactions that are announced in the stem phase
+// by the provided inventory message and notifies the stem relay of all
+// transactions that are announced in the fluff phase.  It returns an inventory
+// message with the stem phase announcements removed since they are not handled
+// by the net sync manager.
+func (sp *serverPeer) handleStemInv(msg *wire.MsgInv) *wire.MsgInv {
+	srvr := sp.server
+	var numStemTxns int
+	var getDataMsg *wire.MsgGetData
+	for _, iv := range msg.InvList {
+		switch iv.Type {
+		case wire.InvTypeTx:
+			srvr.stemRelay.Fluffed(&iv.Hash)
+
+		case wire.InvTypeStemTx:
+			numStemTxns++
+			txHash := &iv.Hash
+			if srvr.txMemPool.HaveTransaction(txHash) ||
+				srvr.recentlyConfirmedTxns.Contains(txHash[:]) ||
+				!srvr.stemRelay.AddRequest(sp, txHash) {
+
+				continue
+			}
+			if getDataMsg == nil {
+				getDataMsg = wire.NewMsgGetDataSizeHint(1)
+			}
+			getDataMsg.AddInvVect(iv)
+		}
+	}
+	if getDataMsg != nil {
+		sp.QueueMessage(getDataMsg, nil)
+	}
+	if numStemTxns == 0 {
+		return msg
+	}
+
+	// Remove the stem phase announcements.
+	filtered := wire.NewMsgInvSizeHint(uint(len(msg.InvList) - numStemTxns))
+	for _, iv := range msg.InvList {
+		if iv.Type != wire.InvTypeStemTx {
+			filtered.AddInvVect(iv)
+		}
+	}
+	return filtered
+}
+
 // OnHeaders is invoked when a peer receives a headers wire message.  The
 // message is passed down to the net sync manager.
 func (sp *serverPeer) OnHeaders(_ *peer.Peer, msg *wire.MsgHeaders) {
@@ -1780,8 +2191,46 @@ func (sp *serverPeer) OnAddr(_ *peer.Peer, msg *wire.MsgAddr) {
 		return
 	}
 
+	sp.addAddresses(wireToAddrmgrNetAddresses(msg.AddrList))
+}
+
+// OnAddrV2 is invoked when a peer receives an addrv2 wire message and is used
+// to notify the server about advertised addresses.
+func (sp *serverPeer) OnAddrV2(_ *peer.Peer, msg *wire.MsgAddrV2) {
+	// Ignore addresses when running on the simulation and regression test
+	// networks.  This helps prevent the networks from becoming another public
+	// test network since they will not be able to learn about other peers that
+	// have not specifically been provided.
+	if cfg.SimNet || cfg.RegNet {
+		return
+	}
+
+	// A message that has no addresses is invalid.
+	if len(msg.AddrList) == 0 {
+		// Ban peers sending empty address requests.
+		const reason = "sent an empty address list"
+		sp.server.BanPeer(sp, reason)
+		return
+	}
+
+	// Convert the addresses while skipping any that are invalid for their
+	// type.
+	addrList := make([]*addrmgr.NetAddress, 0, len(msg.AddrList))
+	for _, wireNetAddr := range msg.AddrList {
+		na, err := wireToAddrmgrNetAddressV2(wireNetAddr)
+		if err != nil {
+			peerLog.Debugf("Ignoring address from %v: %v", sp, err)
+			continue
+		}
+		addrList = append(addrList, na)
+	}
+	sp.addAddresses(addrList)
+}
+
+// addAddresses marks the provided addresses advertised by the peer as known to
+// it and adds them to the server address manager.
+func (sp *serverPeer) addAddresses(addrList []*addrmgr.NetAddress) {
 	now := time.Now()
-	addrList := wireToAddrmgrNetAddresses(msg.AddrList)
 	for _, na := range addrList {
 		// Don't add more address if we're disconnecting.
 		if !sp.Connected() {
@@ -1802,7 +2251,7 @@ func (sp *serverPeer) OnAddr(_ *peer.Peer, msg *wire.MsgAddr) {
 	// Add addresses to server address manager.  The address manager handles
 	// the details of things such as preventing duplicate addresses, max
 	// addresses, and last seen updates.
-	remoteAddr := wireToAddrmgrNetAddress(sp.NA())
+	remoteAddr := sp.remoteNetAddress()
 	sp.server.addrManager.AddAddresses(addrList, remoteAddr)
 }
 
@@ -1930,10 +2379,13 @@ func (sp *serverPeer) OnNotFound(_ *peer.Peer, msg *wire.MsgNotFound) {
 	var numBlocks, numTxns, numMixMsgs uint32
 	for _, inv := range msg.InvList {
 		switch inv.Type {
-		case wire.InvTypeBlock:
+		case wire.InvTypeBlock, wire.InvTypeCmpctBlock:
 			numBlocks++
 		case wire.InvTypeTx:
 			numTxns++
+		case wire.InvTypeStemTx:
+			sp.server.stemRelay.TakeRequest(sp, &inv.Hash)
+			numTxns++
 		case wire.InvTypeMix:
 			numMixMsgs++
 		default:
@@ -2025,8 +2477,16 @@ func (s *server) PruneRebroadcastInventory() {
 
 // relayTransactions generates and relays inventory vectors for all of the
 // passed transactions to all connected peers.
+//
+// Transactions that are in the stem phase are instead relayed along the stem
+// route for locally submitted transactions since transactions received in the
+// stem phase from peers are handed to the stem relay directly.
 func (s *server) relayTransactions(txns []*dcrutil.Tx) {
 	for _, tx := range txns {
+		if s.txMemPool.IsStemTransaction(tx.Hash()) {
+			s.stemRelay.Relay(nil, tx)
+			continue
+		}
 		iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
 		s.RelayInventory(iv, tx, false)
 	}
@@ -2049,11 +2509,46 @@ func (s *server) AnnounceNewTransactions(txns []*dcrutil.Tx) {
 	// Generate and relay inventory vectors for all newly accepted
 	// transactions.
 	s.relayTransactions(txns)
+	s.notifyNewTransactions(txns)
+}
 
+// notifyNewTransactions notifies websocket and ZeroMQ clients of the passed
+// newly accepted transactions.
+func (s *server) notifyNewTransactions(txns []*dcrutil.Tx) {
 	// Notify websocket clients of all newly accepted transactions.
 	if s.rpcServer != nil {
 		s.rpcServer.NotifyNewTransactions(txns)
 	}
+
+	// Publish all newly accepted transactions to ZeroMQ subscribers.
+	s.zmqPublishTransactions(txns)
+}
+
+// fluffStemTx transitions the provided transaction from the stem phase to the
+// fluff phase and relays it to all connected peers.  It does nothing when the
+// transaction is no longer in the stem phase such as when it was already
+// fluffed or removed from the mempool.
+func (s *server) fluffStemTx(tx *dcrutil.Tx) {
+	if !s.txMemPool.FluffTransaction(tx.Hash()) {
+		return
+	}
+	s.relayTransactions([]*dcrutil.Tx{tx})
+}
+
+// stemRelayCandidates returns the connected outbound peers that support stem
+// transaction relay and have not disabled transaction relay.
+func (s *server) stemRelayCandidates() []*serverPeer {
+	var candidates []*serverPeer
+	s.peerState.Lock()
+	s.peerState.forAllOutboundPeers(func(sp *serverPeer) {
+		if sp.Connected() && sp.ProtocolVersion() >= wire.StemTxVersion &&
+			!sp.disableRelayTx.Load() {
+
+			candidates = append(candidates, sp)
+		}
+	})
+	s.peerState.Unlock()
+	return candidates
 }
 
 // AnnounceMixMessages generates and relays inventory vectors of the passed
@@ -2133,16 +2628,33 @@ func (s *server) handleRelayPeerInvMsg(msg relayMsg, sp *serverPeer) {
 		sp.announcedBlock = &iv.Hash
 	}
 
+	// Send a compact block instead of any other type of block announcement
+	// when the peer requested high bandwidth compact block announcements.
+	if isBlockAnnouncement && sp.wantsCmpctBlocks.Load() &&
+		sp.ProtocolVersion() >= wire.CompactBlockVersion {
+
+		block, ok := msg.data.(*dcrutil.Block)
+		if !ok {
+			peerLog.Warn("Underlying data for block announcement is not a " +
+				"block")
+			return
+		}
+		sp.AddKnownInventory(iv)
+		sp.QueueMessage(netsync.NewCmpctBlock(block, rand.Uint64()), nil)
+		return
+	}
+
 	// Generate and send a headers message instead of an inventory message
 	// for block announcements when the peer prefers headers.
 	if isBlockAnnouncement && sp.WantsHeaders() {
-		blockHeader, ok := msg.data.(wire.BlockHeader)
+		block, ok := msg.data.(*dcrutil.Block)
 		if !ok {
-			peerLog.Warn("Underlying data for headers is not a block header")
+			peerLog.Warn("Underlying data for block announcement is not a " +
+				"block")
 			return
 		}
 		msgHeaders := wire.NewMsgHeaders()
-		if err := msgHeaders.AddBlockHeader(&blockHeader); err != nil {
+		if err := msgHeaders.AddBlockHeader(&block.MsgBlock().Header); err != nil {
 			peerLog.Errorf("Failed to add block header: %v", err)
 			return
 		}
@@ -2274,6 +2786,10 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			OnInitState:       sp.OnInitState,
 			OnTx:              sp.OnTx,
 			OnBlock:           sp.OnBlock,
+			OnCmpctBlock:      sp.OnCmpctBlock,
+			OnGetBlockTxns:    sp.OnGetBlockTxns,
+			OnBlockTxns:       sp.OnBlockTxns,
+			OnSendCmpct:       sp.OnSendCmpct,
 			OnMixPairReq:      sp.OnMixPairReq,
 			OnMixKeyExchange:  sp.OnMixKeyExchange,
 			OnMixCiphertexts:  sp.OnMixCiphertexts,
@@ -2294,6 +2810,7 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			OnGetCFTypes:      sp.OnGetCFTypes,
 			OnGetAddr:         sp.OnGetAddr,
 			OnAddr:            sp.OnAddr,
+			OnAddrV2:          sp.OnAddrV2,
 			OnRead:            sp.OnRead,
 			OnWrite:           sp.OnWrite,
 			OnNotFound:        sp.OnNotFound,
@@ -2304,6 +2821,13 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			if err != nil {
 				return nil, err
 			}
+
+			// Addresses on overlay networks can't be represented by a wire net
+			// address, so use the unspecified address for them instead.
+			if isOverlayNetAddrType(address.Type) {
+				return wire.NewNetAddressIPPort(net.IPv6zero, port,
+					services), nil
+			}
 			return addrmgrToWireNetAddress(address), nil
 		},
 		Proxy:             cfg.Proxy,
@@ -2338,6 +2862,7 @@ func (s *server) inboundPeerConnected(conn net.Conn) {
 // peer processing goroutines.
 func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	sp := newServerPeer(s, c.Permanent)
+	sp.overlayAddr = overlayNetAddress(c.Addr.String())
 	p, err := peer.NewOutboundPeer(newPeerConfig(sp), c.Addr.String())
 	if err != nil {
 		srvrLog.Debugf("Cannot create outbound peer %s: %v", c.Addr, err)
@@ -2352,6 +2877,44 @@ func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	go sp.Run()
 }
 
+// feelerConnected is invoked by the connection manager when a new feeler
+// connection is established.  It performs the version handshake with the remote
+// peer and marks the address as good in the address manager when the handshake
+// succeeds.  The connection is closed afterwards either way.
+func (s *server) feelerConnected(addr net.Addr, conn net.Conn) {
+	verAck := make(chan struct{}, 1)
+	sp := newServerPeer(s, false)
+	sp.overlayAddr = overlayNetAddress(addr.String())
+	peerCfg := newPeerConfig(sp)
+	peerCfg.Listeners = peer.MessageListeners{
+		OnVerAck: func(_ *peer.Peer, _ *wire.MsgVerAck) {
+			select {
+			case verAck <- struct{}{}:
+			default:
+			}
+		},
+	}
+	p, err := peer.NewOutboundPeer(peerCfg, addr.String())
+	if err != nil {
+		srvrLog.Debugf("Cannot create feeler peer %s: %v", addr, err)
+		conn.Close()
+		return
+	}
+	sp.Peer = p
+	p.AssociateConnection(conn)
+
+	select {
+	case <-verAck:
+		s.addrManager.Good(sp.remoteNetAddress())
+		srvrLog.Debugf("Feeler connection to %s succeeded", addr)
+	case <-time.After(feelerHandshakeTimeout):
+		srvrLog.Debugf("Feeler connection to %s timed out", addr)
+	case <-s.quit:
+	}
+	p.Disconnect()
+	p.WaitForDisconnect()
+}
+
 // peerHandler is used to handle peer operations such as inventory relay and
 // broadcasting messages to peers.
 //
@@ -2380,6 +2943,22 @@ out:
 		case <-ctx.Done():
 			close(s.quit)
 
+			// Save the current outbound peers so they are connected to first
+			// on the next startup.
+			if s.anchors != nil {
+				var anchors []string
+				s.peerState.Lock()
+				for _, sp := range s.peerState.outboundPeers {
+					anchors = append(anchors, sp.Addr())
+				}
+				s.peerState.Unlock()
+				if err := s.anchors.Save(anchors); err != nil {
+					srvrLog.Errorf("Unable to save anchor peers: %v", err)
+				} else {
+					srvrLog.Debugf("Saved %d anchor peers", len(anchors))
+				}
+			}
+
 			// Disconnect all peers on server shutdown.
 			s.peerState.ForAllPeers(func(sp *serverPeer) {
 				srvrLog.Tracef("Shutdown peer %s", sp)
@@ -2419,23 +2998,24 @@ func (s *server) handleAddPeer(sp *serverPeer) bool {
 		sp.Disconnect()
 		return false
 	}
-	if banEnd, ok := state.banned[host]; ok {
-		if time.Now().Before(banEnd) {
-			srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
-				host, time.Until(banEnd))
+	if ip := net.ParseIP(host); ip != nil {
+		if ban, ok := s.banList.Lookup(ip); ok {
+			srvrLog.Debugf("Peer %s is banned for another %v (%s) - "+
+				"disconnecting", host, time.Until(ban.Expires), ban.Reason)
 			sp.Disconnect()
 			return false
 		}
-
-		srvrLog.Infof("Peer %s is no longer banned", host)
-		delete(state.banned, host)
 	}
 
 	// Limit max number of connections from a single IP.  However, allow
 	// whitelisted inbound peers and localhost connections regardless.
 	isInboundWhitelisted := sp.isWhitelisted && sp.Inbound()
+	//
+	// Peers on overlay networks do not have an IP address, so they are not
+	// subject to the limit.
 	peerIP := sp.NA().IP
-	if cfg.MaxSameIP > 0 && !isInboundWhitelisted && !peerIP.IsLoopback() &&
+	if cfg.MaxSameIP > 0 && !isInboundWhitelisted && sp.overlayAddr == nil &&
+		!peerIP.IsLoopback() &&
 		state.connectionsWithIP(peerIP)+1 > cfg.MaxSameIP {
 
 		srvrLog.Infof("Max connections with %s reached [%d] - disconnecting "+
@@ -2479,8 +3059,8 @@ func (s *server) handleAddPeer(sp *serverPeer) bool {
 	}
 
 	// The peer is an outbound peer at this point.
-	remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-	state.outboundGroups[remoteAddr.GroupKey()]++
+	remoteAddr := sp.remoteNetAddress()
+	state.outboundGroups[s.addrManager.GroupKey(remoteAddr)]++
 	if sp.persistent {
 		state.persistentPeers[sp.ID()] = sp
 	} else {
@@ -2586,8 +3166,8 @@ func (s *server) DonePeer(sp *serverPeer) {
 	}
 	if _, ok := list[sp.ID()]; ok {
 		if !sp.Inbound() && sp.VersionKnown() {
-			remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-			state.outboundGroups[remoteAddr.GroupKey()]--
+			remoteAddr := sp.remoteNetAddress()
+			state.outboundGroups[s.addrManager.GroupKey(remoteAddr)]--
 		}
 		if !sp.Inbound() {
 			connReq := sp.connReq.Load()
@@ -2613,7 +3193,7 @@ func (s *server) DonePeer(sp *serverPeer) {
 	if !cfg.SimNet && !cfg.RegNet && sp.VerAckReceived() && sp.VersionKnown() &&
 		sp.NA() != nil {
 
-		remoteAddr := wireToAddrmgrNetAddress(sp.NA())
+		remoteAddr := sp.remoteNetAddress()
 		err := s.addrManager.Connected(remoteAddr)
 		if err != nil {
 			srvrLog.Errorf("Marking address as connected failed: %v", err)
@@ -2644,10 +3224,14 @@ func (s *server) BanPeer(sp *serverPeer, reason string) {
 	direction := directionString(sp.Inbound())
 	srvrLog.Warnf("Misbehaving peer %s (%s): %s -- banned for %v", host,
 		direction, reason, cfg.BanDuration)
-	bannedUntil := time.Now().Add(cfg.BanDuration)
-	s.peerState.Lock()
-	s.peerState.banned[host] = bannedUntil
-	s.peerState.Unlock()
+	if ip := net.ParseIP(host); ip != nil {
+		bannedUntil := time.Now().Add(cfg.BanDuration)
+		err := s.banList.Ban(banmanager.HostSubnet(ip), bannedUntil, reason,
+			true)
+		if err != nil {
+			srvrLog.Errorf("Unable to persist ban for peer %s: %v", host, err)
+		}
+	}
 	sp.Disconnect()
 }
 
@@ -2669,7 +3253,7 @@ func (s *server) RelayBlockAnnouncement(block *dcrutil.Block, reqServices wire.S
 	case <-s.quit:
 	case s.relayInv <- relayMsg{
 		invVect:     invVect,
-		data:        block.MsgBlock().Header,
+		data:        block,
 		immediate:   true,
 		reqServices: reqServices,
 	}:
@@ -2685,6 +3269,70 @@ func (s *server) BroadcastMessage(msg wire.Message, exclPeers ...*serverPeer) {
 	}
 }
 
+// maybeSelectHighBandwidthCmpctPeer potentially asks the given peer to announce
+// new blocks via compact blocks in high bandwidth mode when it was the first to
+// deliver the block with the given hash and that block is now the best chain
+// tip.  The least recently selected peer is asked to stop doing so when doing
+// so would otherwise exceed the maximum number of such peers.
+//
+// Persistent peers are not considered since they are always asked to announce
+// new blocks in high bandwidth mode.
+//
+// This function is safe for concurrent access.
+func (s *server) maybeSelectHighBandwidthCmpctPeer(sp *serverPeer, blockHash *chainhash.Hash, wasKnown bool) {
+	if wasKnown || sp.persistent || !sp.syncMgrPeer.SupportsCmpctBlocks() {
+		return
+	}
+	if s.chain.BestSnapshot().Hash != *blockHash {
+		return
+	}
+
+	s.hbCmpctPeersMtx.Lock()
+	for i, hbPeer := range s.hbCmpctPeers {
+		if hbPeer == sp {
+			// Move the peer to the most recently selected position.
+			copy(s.hbCmpctPeers[i:], s.hbCmpctPeers[i+1:])
+			s.hbCmpctPeers[len(s.hbCmpctPeers)-1] = sp
+			s.hbCmpctPeersMtx.Unlock()
+			return
+		}
+	}
+	var demoted *serverPeer
+	if len(s.hbCmpctPeers) >= maxHighBandwidthCmpctPeers {
+		demoted = s.hbCmpctPeers[0]
+		copy(s.hbCmpctPeers, s.hbCmpctPeers[1:])
+		s.hbCmpctPeers = s.hbCmpctPeers[:len(s.hbCmpctPeers)-1]
+	}
+	s.hbCmpctPeers = append(s.hbCmpctPeers, sp)
+	s.hbCmpctPeersMtx.Unlock()
+
+	if demoted != nil {
+		demoted.QueueMessage(wire.NewMsgSendCmpct(false,
+			wire.CmpctBlockEncodingVersion), nil)
+	}
+	sp.QueueMessage(wire.NewMsgSendCmpct(true, wire.CmpctBlockEncodingVersion),
+		nil)
+	peerLog.Debugf("Selected %s for high bandwidth compact block relay", sp)
+}
+
+// removeHighBandwidthCmpctPeer removes the given peer from the peers that have
+// been asked to announce new blocks via compact blocks in high bandwidth mode
+// if needed.
+//
+// This function is safe for concurrent access.
+func (s *server) removeHighBandwidthCmpctPeer(sp *serverPeer) {
+	s.hbCmpctPeersMtx.Lock()
+	for i, hbPeer := range s.hbCmpctPeers {
+		if hbPeer == sp {
+			copy(s.hbCmpctPeers[i:], s.hbCmpctPeers[i+1:])
+			s.hbCmpctPeers[len(s.hbCmpctPeers)-1] = nil
+			s.hbCmpctPeers = s.hbCmpctPeers[:len(s.hbCmpctPeers)-1]
+			break
+		}
+	}
+	s.hbCmpctPeersMtx.Unlock()
+}
+
 // ConnectedCount returns the number of currently connected peers.
 func (s *server) ConnectedCount() int32 {
 	var numConnected int32
@@ -2938,10 +3586,11 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 		txMemPool := s.txMemPool
 		handleConnectedBlockTxns := func(txns []*dcrutil.Tx) {
 			for _, tx := range txns {
-				txMemPool.RemoveTransaction(tx, false)
+				txMemPool.RemoveTransaction(tx, false,
+					mempool.RemovalReasonMined)
 				txMemPool.MaybeAcceptDependents(tx, isTreasuryEnabled)
 				txMemPool.RemoveDoubleSpends(tx)
-				txMemPool.RemoveOrphan(tx)
+				txMemPool.RemoveOrphan(tx, mempool.RemovalReasonMined)
 				acceptedTxs := txMemPool.ProcessOrphans(tx, ntfn.CheckTxFlags)
 				s.AnnounceNewTransactions(acceptedTxs)
 
@@ -2995,6 +3644,9 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 			r.NotifyBlockConnected(block)
 		}
 
+		// Publish the connected block to ZeroMQ subscribers.
+		s.zmqPublishBlockConnected(block)
+
 		if s.bg != nil {
 			s.bg.BlockConnected(block)
 		}
@@ -3057,10 +3709,11 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 		txMemPool := s.txMemPool
 		if !headerApprovesParent(&block.MsgBlock().Header) {
 			for _, tx := range parentBlock.Transactions()[1:] {
-				txMemPool.RemoveTransaction(tx, false)
+				txMemPool.RemoveTransaction(tx, false,
+					mempool.RemovalReasonMined)
 				txMemPool.MaybeAcceptDependents(tx, isTreasuryEnabled)
 				txMemPool.RemoveDoubleSpends(tx)
-				txMemPool.RemoveOrphan(tx)
+				txMemPool.RemoveOrphan(tx, mempool.RemovalReasonMined)
 				txMemPool.ProcessOrphans(tx, ntfn.CheckTxFlags)
 			}
 		}
@@ -3122,6 +3775,9 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 			r.NotifyBlockDisconnected(block)
 		}
 
+		// Publish the disconnected block to ZeroMQ subscribers.
+		s.zmqPublishBlockDisconnected(block)
+
 	// Chain reorganization has commenced.
 	case blockchain.NTChainReorgStarted:
 		// WARNING: The chain lock is not released before sending this
@@ -3229,7 +3885,15 @@ func (s *server) rebroadcastHandler(ctx context.Context) {
 		case <-timer.C:
 			// Any inventory we have has not made it into a block
 			// yet. We periodically resubmit them until they have.
+			//
+			// Transactions still in the stem phase are skipped since they are
+			// diffused once their embargo expires.
 			for iv, data := range pendingInvs {
+				if iv.Type == wire.InvTypeTx &&
+					s.txMemPool.IsStemTransaction(&iv.Hash) {
+
+					continue
+				}
 				ivCopy := iv
 				s.RelayInventory(&ivCopy, data, false)
 			}
@@ -3310,6 +3974,87 @@ func (s *server) querySeeders(ctx context.Context) {
 	}
 }
 
+// loadMempool restores the transactions in the mempool dump file, if it
+// exists, to the mempool.  The provided context may be cancelled to stop the
+// process early.
+func (s *server) loadMempool(ctx context.Context) {
+	f, err := os.Open(s.mempoolDumpFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			srvrLog.Errorf("Unable to open mempool dump: %v", err)
+		}
+		s.mempoolLoaded.Store(true)
+		return
+	}
+	defer f.Close()
+
+	// Enable fee estimation prior to restoring the transactions when the
+	// chain is already current so they are accounted for as of the height
+	// they were originally added to the mempool.
+	best := s.chain.BestSnapshot()
+	if s.chain.IsCurrent() && !s.feeEstimator.IsEnabled() {
+		s.feeEstimator.Enable(best.Height)
+	}
+
+	srvrLog.Infof("Loading mempool from %s", s.mempoolDumpFile)
+	start := time.Now()
+	stats, err := s.txMemPool.LoadDump(f, ctx.Done())
+	if err != nil {
+		srvrLog.Errorf("Unable to load mempool dump: %v", err)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	s.mempoolLoaded.Store(true)
+	if stats == nil {
+		return
+	}
+	srvrLog.Infof("Restored %d transactions to the mempool in %v (%d "+
+		"expired, %d stale, %d rejected)", stats.Accepted,
+		time.Since(start).Round(time.Millisecond), stats.Expired, stats.Stale,
+		stats.Rejected)
+}
+
+// saveMempool writes all transactions in the mempool to the mempool dump file
+// and returns the number of transactions written.  An error is returned when
+// the mempool has not finished being restored from a previous dump.
+//
+// This function is safe for concurrent access.
+func (s *server) saveMempool() (int, error) {
+	if !s.mempoolLoaded.Load() {
+		return 0, errors.New("the mempool has not finished loading")
+	}
+
+	s.mempoolDumpMtx.Lock()
+	defer s.mempoolDumpMtx.Unlock()
+
+	// Write a temporary dump file and then move it into place.
+	tmpFile := s.mempoolDumpFile + ".new"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		return 0, err
+	}
+	numTxns, err := s.txMemPool.WriteDump(f)
+	if err != nil {
+		f.Close()
+		os.Remove(tmpFile)
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpFile)
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpFile)
+		return 0, err
+	}
+	if err := os.Rename(tmpFile, s.mempoolDumpFile); err != nil {
+		return 0, err
+	}
+	return numTxns, nil
+}
+
 // Run starts the server and blocks until the provided context is cancelled.
 // This entails accepting connections from peers.
 func (s *server) Run(ctx context.Context) {
@@ -3348,6 +4093,37 @@ func (s *server) Run(ctx context.Context) {
 		}()
 	}
 
+	if s.metricsServer != nil {
+		wg.Add(1)
+		go func() {
+			s.metricsServer.Run(ctx)
+			wg.Done()
+		}()
+	}
+
+	if s.zmqPublisher != nil {
+		wg.Add(1)
+		go func() {
+			s.zmqPublisher.Run(ctx)
+			wg.Done()
+		}()
+	}
+
+	if s.snapshotValidator != nil {
+		wg.Add(1)
+		go func() {
+			s.snapshotValidator.Run(ctx)
+			wg.Done()
+		}()
+	}
+
+	// Start the stem relay embargo timer handler.
+	wg.Add(1)
+	go func() {
+		s.stemRelay.Run(ctx)
+		wg.Done()
+	}()
+
 	if !cfg.DisableRPC {
 		// Start the RPC server and rebroadcast handler which ensures
 		// transactions submitted to the RPC server are rebroadcast until being
@@ -3397,6 +4173,18 @@ func (s *server) Run(ctx context.Context) {
 		wg.Done()
 	}()
 
+	// Restore the mempool from the dump written during the previous shutdown
+	// when mempool persistence is enabled.
+	if !cfg.NoPersistMempool {
+		wg.Add(1)
+		go func() {
+			s.loadMempool(ctx)
+			wg.Done()
+		}()
+	} else {
+		s.mempoolLoaded.Store(true)
+	}
+
 	// Shutdown the server when the context is cancelled.
 	<-ctx.Done()
 	s.shutdown.Store(true)
@@ -3405,6 +4193,17 @@ func (s *server) Run(ctx context.Context) {
 	s.feeEstimator.Close()
 	s.chain.ShutdownUtxoCache()
 	wg.Wait()
+
+	// Persist the mempool now that all subsystems that modify it have stopped.
+	if !cfg.NoPersistMempool {
+		numTxns, err := s.saveMempool()
+		if err != nil {
+			srvrLog.Errorf("Unable to save mempool: %v", err)
+		} else {
+			srvrLog.Infof("Saved %d mempool transactions to %s", numTxns,
+				s.mempoolDumpFile)
+		}
+	}
 	srvrLog.Trace("Server stopped")
 }
 
@@ -3789,9 +4588,9 @@ func setupRPCListeners() ([]net.Listener, error) {
 		notifyAddrServer = newBoundAddrEventServer(outgoingPipeMessages)
 	}
 
-	// Setup TLS if not disabled.
+	// Setup TLS if not disabled and there are network listeners to use it.
 	listenFunc := net.Listen
-	if !cfg.DisableRPC && !cfg.DisableTLS {
+	if !cfg.DisableRPC && !cfg.DisableTLS && len(cfg.RPCListeners) > 0 {
 		// Generate the TLS cert and key file if both don't already exist.
 		keyFileExists := fileExists(cfg.RPCKey)
 		certFileExists := fileExists(cfg.RPCCert)
@@ -3844,6 +4643,108 @@ func setupRPCListeners() ([]net.Listener, error) {
 		notifyAddrServer.notifyRPCAddress(listener.Addr().String())
 	}
 
+	// Unix domain sockets are protected by their file permissions and owner
+	// instead of TLS, so they are always served without it.
+	for _, path := range cfg.rpcUnixPaths {
+		listener, err := listenUnixSocket(path, cfg.rpcUnixMode,
+			cfg.rpcUnixUID, cfg.rpcUnixGID)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// unixSocketDialTimeout is the maximum amount of time to wait when dialing an
+// existing Unix domain socket to determine if it is still in use.
+const unixSocketDialTimeout = time.Second
+
+// removeStaleUnixSocket removes the Unix domain socket at the provided path
+// when one exists that nothing is listening on, such as one left behind by an
+// unclean shutdown, so that it may be listened on again.  An error is returned
+// when another process, such as another running instance, is still listening
+// on it.  Other types of files are not removed.
+func removeStaleUnixSocket(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil || fi.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+
+	// Only remove the socket when connecting to it is refused since that means
+	// nothing is listening on it anymore.
+	conn, err := net.DialTimeout("unix", path, unixSocketDialTimeout)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("unix domain socket %s is already in use", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("unable to determine if unix domain socket %s is "+
+			"in use: %w", path, err)
+	}
+	return os.Remove(path)
+}
+
+// listenUnixSocket returns a listener for a Unix domain socket at the provided
+// path with the provided file permissions.  The owner and group of the socket
+// are also changed when the provided IDs are not -1.
+//
+// The permissions are applied immediately after the socket is created rather
+// than by modifying the process umask since the umask is shared by all
+// goroutines and would otherwise affect any files they create concurrently.
+func listenUnixSocket(path string, mode os.FileMode, uid, gid int) (net.Listener, error) {
+	if err := removeStaleUnixSocket(path); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if uid != -1 || gid != -1 {
+		if err := os.Chown(path, uid, gid); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+	return listener, nil
+}
+
+// setupRESTListeners returns a slice of listeners that are configured for use
+// with the REST interface depending on the configuration settings for the REST
+// listen address.  The REST interface is always served via plain HTTP.
+func setupRESTListeners() ([]net.Listener, error) {
+	if cfg.RESTListen == "" {
+		return nil, nil
+	}
+
+	listenAddrs := normalizeAddresses([]string{cfg.RESTListen}, "",
+		normalizeInterfaceAddrs)
+	netAddrs, err := parseListeners(listenAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("unable to listen on %s: %w",
+				cfg.RESTListen, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
 	return listeners, nil
 }
 
@@ -3855,8 +4756,64 @@ func newServer(ctx context.Context, profiler *profileServer,
 	chainParams *chaincfg.Params, dataDir string) (*server, error) {
 
 	amgr := addrmgr.New(cfg.DataDir)
+	if cfg.ASMap != "" {
+		data, err := os.ReadFile(cfg.ASMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read asmap: %w", err)
+		}
+		asmap, err := addrmgr.NewASMap(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load asmap %s: %w", cfg.ASMap,
+				err)
+		}
+		amgr.SetASMap(asmap)
+		srvrLog.Infof("Using IP to ASN map %s (%v)", cfg.ASMap, asmap.Hash())
+	}
 	services := defaultServices
 
+	// Determine whether or not block data is pruned.  A pruned node is not
+	// able to serve historical blocks, so it advertises the limited network
+	// service instead of the full network service.  The indexes which require
+	// the full block history are not supported on a pruned node either, even
+	// when pruning is no longer enabled, since the old block data is gone.
+	// The same applies when the chain was created from a chain state snapshot
+	// since the block data prior to it is never available.  The exists address
+	// index is still supported since it only indexes the blocks that remain.
+	snapshot, snapshotValidated, err := blockchain.FetchImportedSnapshot(db)
+	if err != nil {
+		return nil, err
+	}
+	pruned := cfg.Prune != 0 || snapshot != nil
+	if !pruned {
+		err := db.View(func(dbTx database.Tx) error {
+			pruner, ok := dbTx.(database.BlockPruner)
+			if !ok {
+				return nil
+			}
+			var err error
+			pruned, err = pruner.BeenPruned()
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pruned {
+		if cfg.TxIndex || cfg.AddrIndex || cfg.SpendIndex {
+			return nil, errors.New("the transaction, address, and spend " +
+				"indexes require the full block history and may not be " +
+				"enabled when block data has been pruned")
+		}
+		services &^= wire.SFNodeNetwork
+		services |= wire.SFNodeNetworkLimited
+	}
+
+	// Advertise support for the encrypted transport so it is negotiated with
+	// peers that also support it unless it is disabled.
+	if !cfg.NoP2PEncryption {
+		services |= wire.SFNodeEncryptedTransport
+	}
+
 	var listeners []net.Listener
 	var nat *upnpNAT
 	if !cfg.DisableListen {
@@ -3900,6 +4857,11 @@ func newServer(ctx context.Context, profiler *profileServer,
 			*dcrutil.Tx](maxRecentlyAdvertisedTxns, recentlyAdvertisedTxnsTTL),
 		lastAdvertisedTxnsEvictedLogged: time.Now(),
 	}
+	s.stemRelay = newStemRelay(cfg.StemRelay, s.stemRelayCandidates,
+		func(sp *serverPeer, tx *dcrutil.Tx) {
+			iv := wire.NewInvVect(wire.InvTypeStemTx, tx.Hash())
+			sp.QueueInventoryImmediate(iv)
+		}, s.fluffStemTx)
 
 	// Convert the minimum known work to a uint256 when it exists.  Ideally, the
 	// chain params should be updated to use the new type, but that will be a
@@ -3929,6 +4891,12 @@ func newServer(ctx context.Context, profiler *profileServer,
 		return nil, err
 	}
 	s.feeEstimator = fe
+	s.mempoolDumpFile = path.Join(dataDir, "mempool.dat")
+
+	s.banList = banmanager.NewBanList(dataDir)
+	if err := s.banList.Load(); err != nil {
+		srvrLog.Warnf("Unable to load ban list: %v", err)
+	}
 
 	if cfg.AllowOldForks {
 		srvrLog.Info("Processing forks deep in history is enabled")
@@ -3954,6 +4922,13 @@ func newServer(ctx context.Context, profiler *profileServer,
 		srvrLog.Info("Assume valid is disabled")
 	}
 
+	// Create the metrics about the server and its subsystems when the metrics
+	// server is enabled.
+	var srvrMetrics *serverMetrics
+	if cfg.MetricsListen != "" {
+		srvrMetrics = newServerMetrics()
+	}
+
 	// Create a new block chain instance with the appropriate configuration.
 	utxoBackend := blockchain.NewLevelDbUtxoBackend(utxoDb)
 	utxoCache := blockchain.NewUtxoCache(&blockchain.UtxoCacheConfig{
@@ -3961,23 +4936,34 @@ func newServer(ctx context.Context, profiler *profileServer,
 		FlushBlockDB: s.db.Flush,
 		MaxSize:      uint64(cfg.UtxoCacheMaxSize) * 1024 * 1024,
 	})
-	s.chain, err = blockchain.New(ctx,
-		&blockchain.Config{
-			DB:              s.db,
-			UtxoBackend:     utxoBackend,
-			ChainParams:     s.chainParams,
-			AssumeValid:     assumeValid,
-			TimeSource:      s.timeSource,
-			Notifications:   s.handleBlockchainNotification,
-			SigCache:        s.sigCache,
-			SubsidyCache:    s.subsidyCache,
-			IndexSubscriber: s.indexSubscriber,
-			UtxoCache:       utxoCache,
-		})
+	chainCfg := &blockchain.Config{
+		DB:              s.db,
+		UtxoBackend:     utxoBackend,
+		ChainParams:     s.chainParams,
+		AssumeValid:     assumeValid,
+		TimeSource:      s.timeSource,
+		Notifications:   s.handleBlockchainNotification,
+		SigCache:        s.sigCache,
+		SubsidyCache:    s.subsidyCache,
+		IndexSubscriber: s.indexSubscriber,
+		UtxoCache:       utxoCache,
+		PruneTarget:     cfg.Prune * 1024 * 1024,
+	}
+	s.chain, err = blockchain.New(ctx, chainCfg)
 	if err != nil {
 		return nil, err
 	}
 
+	// Validate the history prior to the chain state snapshot the chain was
+	// created from in the background when it has not been validated yet.
+	if snapshot != nil && !snapshotValidated {
+		s.snapshotValidator, err = newSnapshotValidator(ctx, s.chain,
+			snapshot, chainCfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	queryer := &blockchain.ChainQueryerAdapter{BlockChain: s.chain}
 	if cfg.TxIndex {
 		indxLog.Info("Transaction index is enabled")
@@ -3986,11 +4972,33 @@ func newServer(ctx context.Context, profiler *profileServer,
 			return nil, err
 		}
 	}
+	if cfg.AddrIndex {
+		indxLog.Info("Address index is enabled")
+		s.addrIndex, err = indexers.NewAddrIndex(s.indexSubscriber, db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SpendIndex {
+		indxLog.Info("Spend index is enabled")
+		s.spendIndex, err = indexers.NewSpendIndex(s.indexSubscriber, db,
+			queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if !cfg.NoExistsAddrIndex {
+		// The exists address index only starts from the oldest block with
+		// available block data when it is created on a pruned chain or one
+		// that was created from a chain state snapshot.
 		indxLog.Info("Exists address index is enabled")
 		s.existsAddrIndex, err = indexers.NewExistsAddrIndex(s.indexSubscriber,
 			db, queryer)
 		if err != nil {
+			if errors.Is(err, indexers.ErrMissingBlockData) {
+				return nil, fmt.Errorf("%w -- run dcrd with "+
+					"--dropexistsaddrindex to drop it", err)
+			}
 			return nil, err
 		}
 	}
@@ -4005,6 +5013,7 @@ func newServer(ctx context.Context, profiler *profileServer,
 			AcceptNonStd:           cfg.AcceptNonStd,
 			MaxOrphanTxs:           cfg.MaxOrphanTxs,
 			MaxOrphanTxSize:        mempool.MaxStandardTxSize,
+			MaxPoolSize:            int64(cfg.MaxMempool) * 1024 * 1024,
 			MaxSigOpsPerTx:         blockchain.MaxSigOpsPerBlock / 5,
 			MinRelayTxFee:          cfg.minRelayTxFee,
 			AllowOldVotes:          cfg.AllowOldVotes,
@@ -4040,6 +5049,7 @@ func newServer(ctx context.Context, profiler *profileServer,
 			return s.chain.BestSnapshot().MedianTime
 		},
 		ExistsAddrIndex:           s.existsAddrIndex,
+		AddrIndex:                 s.addrIndex,
 		AddTxToFeeEstimation:      s.feeEstimator.AddMemPoolTransaction,
 		RemoveTxFromFeeEstimation: s.feeEstimator.RemoveMemPoolTransaction,
 		OnVoteReceived: func(voteTx *dcrutil.Tx) {
@@ -4052,6 +5062,11 @@ func newServer(ctx context.Context, profiler *profileServer,
 				s.rpcServer.NotifyTSpend(tx)
 			}
 		},
+		OnTxRemoved: func(tx *dcrutil.Tx, reason mempool.RemovalReason) {
+			if s.rpcServer != nil {
+				s.rpcServer.NotifyMempoolRemoval(tx, reason)
+			}
+		},
 		IsTreasuryAgendaActive: func() (bool, error) {
 			tipHash := &s.chain.BestSnapshot().Hash
 			return s.chain.IsTreasuryAgendaActive(tipHash)
@@ -4086,7 +5101,7 @@ func newServer(ctx context.Context, profiler *profileServer,
 	if cfg.MaxPeers < targetOutbound {
 		targetOutbound = cfg.MaxPeers
 	}
-	s.syncManager = netsync.New(&netsync.Config{
+	syncMgrConfig := netsync.Config{
 		Chain:                 s.chain,
 		ChainParams:           s.chainParams,
 		TimeSource:            s.timeSource,
@@ -4097,7 +5112,35 @@ func newServer(ctx context.Context, profiler *profileServer,
 		MaxOrphanTxs:          cfg.MaxOrphanTxs,
 		RecentlyConfirmedTxns: s.recentlyConfirmedTxns,
 		MixPool:               s.mixMsgPool,
-	})
+	}
+	if srvrMetrics != nil {
+		syncMgrConfig.BlockProcessed = srvrMetrics.blockProcessed
+	}
+	if s.snapshotValidator != nil {
+		syncMgrConfig.BackgroundValidator = s.snapshotValidator
+	}
+	s.syncManager = netsync.New(&syncMgrConfig)
+
+	// Setup the metrics server when requested.
+	if srvrMetrics != nil {
+		srvrMetrics.registerCollectors(&s, utxoCache)
+		s.metricsServer, err = newMetricsServer(cfg.MetricsListen,
+			srvrMetrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Setup the ZeroMQ compatible publisher when requested.
+	if len(cfg.ZMQPubEndpoints) > 0 {
+		zmqListeners, err := setupZMQListeners()
+		if err != nil {
+			return nil, err
+		}
+		s.zmqPublisher = zmq.NewPublisher(&zmq.Config{
+			Listeners: zmqListeners,
+		})
+	}
 
 	// Dump the blockchain and quit if requested.
 	if cfg.DumpBlockchain != "" {
@@ -4201,26 +5244,51 @@ func newServer(ctx context.Context, profiler *profileServer,
 	// to specified peers and actively avoid advertising and connecting to
 	// discovered peers in order to prevent it from becoming a public test
 	// network.
-	var newAddressFunc func() (net.Addr, error)
+	var newAddressFunc, feelerAddressFunc func() (net.Addr, error)
 	if !cfg.SimNet && !cfg.RegNet && len(cfg.ConnectPeers) == 0 {
+		s.anchors = newAnchorPeers(dataDir)
+		if err := s.anchors.Load(); err != nil {
+			srvrLog.Warnf("Unable to load anchor peers: %v", err)
+		}
+
 		newAddressFunc = func() (net.Addr, error) {
+			// Reconnect to the outbound peers that were in use during the
+			// previous shutdown first.
+			for {
+				addr, ok := s.anchors.Next()
+				if !ok {
+					break
+				}
+				netAddr, err := addrStringToNetAddr(addr)
+				if err != nil {
+					srvrLog.Debugf("Skipping anchor peer %s: %v", addr, err)
+					continue
+				}
+				srvrLog.Debugf("Connecting to anchor peer %s", addr)
+				return netAddr, nil
+			}
+
 			for tries := 0; tries < 100; tries++ {
-				// Note that this does not filter by address type.  Unsupported
-				// network address types should be pruned from the address
-				// manager's internal storage prior to calling this function.
 				addr := s.addrManager.GetAddress()
 				if addr == nil {
 					break
 				}
 
+				// Skip addresses on networks that are not reachable with the
+				// current configuration.
+				netAddr := addr.NetAddress()
+				if !isReachableNetAddrType(netAddr.Type) {
+					continue
+				}
+
 				// Address will not be invalid, local or unroutable
 				// because addrmanager rejects those on addition.
 				// Just check that we don't already have an address
 				// in the same group so that we are not connecting
-				// to the same network segment at the expense of
-				// others.
-				netAddr := addr.NetAddress()
-				if s.OutboundGroupCount(netAddr.GroupKey()) != 0 {
+				// to the same network segment or autonomous system
+				// at the expense of others.
+				groupKey := s.addrManager.GroupKey(netAddr)
+				if s.OutboundGroupCount(groupKey) != 0 {
 					continue
 				}
 
@@ -4245,6 +5313,34 @@ func newServer(ctx context.Context, profiler *profileServer,
 
 			return nil, errors.New("no valid connect address")
 		}
+
+		// Feeler connections are only made to addresses that have not been
+		// successfully connected to yet in order to move them to the tried
+		// set of the address manager once they are confirmed to be reachable.
+		feelerAddressFunc = func() (net.Addr, error) {
+			for tries := 0; tries < 100; tries++ {
+				addr := s.addrManager.GetUntriedAddress()
+				if addr == nil {
+					break
+				}
+
+				// Skip addresses on networks that are not reachable with the
+				// current configuration along with recently attempted ones.
+				netAddr := addr.NetAddress()
+				if !isReachableNetAddrType(netAddr.Type) {
+					continue
+				}
+				lastAttempt := addr.LastAttempt()
+				if !lastAttempt.IsZero() &&
+					time.Since(lastAttempt) < 10*time.Minute {
+					continue
+				}
+
+				return addrStringToNetAddr(netAddr.Key())
+			}
+
+			return nil, errors.New("no valid feeler address")
+		}
 	}
 
 	// Create a connection manager.
@@ -4252,14 +5348,16 @@ func newServer(ctx context.Context, profiler *profileServer,
 		s.targetOutbound = uint32(cfg.MaxPeers)
 	}
 	cmgr, err := connmgr.New(&connmgr.Config{
-		Listeners:      listeners,
-		OnAccept:       s.inboundPeerConnected,
-		RetryDuration:  connectionRetryInterval,
-		TargetOutbound: s.targetOutbound,
-		Dial:           s.attemptDcrdDial,
-		Timeout:        cfg.DialTimeout,
-		OnConnection:   s.outboundPeerConnected,
-		GetNewAddress:  newAddressFunc,
+		Listeners:          listeners,
+		OnAccept:           s.inboundPeerConnected,
+		RetryDuration:      connectionRetryInterval,
+		TargetOutbound:     s.targetOutbound,
+		Dial:               s.attemptDcrdDial,
+		Timeout:            cfg.DialTimeout,
+		OnConnection:       s.outboundPeerConnected,
+		GetNewAddress:      newAddressFunc,
+		GetFeelerAddress:   feelerAddressFunc,
+		OnFeelerConnection: s.feelerConnected,
 	})
 	if err != nil {
 		return nil, err
@@ -4296,19 +5394,26 @@ func newServer(ctx context.Context, profiler *profileServer,
 			return nil, errors.New("no usable rpc listen addresses")
 		}
 
+		// Setup listeners for the REST interface when it is enabled.
+		restListeners, err := setupRESTListeners()
+		if err != nil {
+			return nil, err
+		}
+
 		rpcsConfig := rpcserver.Config{
-			Listeners:    rpcListeners,
-			ProfilerMgr:  profiler,
-			ConnMgr:      &rpcConnManager{&s},
-			SyncMgr:      &rpcSyncMgr{server: &s, syncMgr: s.syncManager},
-			FeeEstimator: s.feeEstimator,
-			TimeSource:   s.timeSource,
-			Services:     s.services,
-			AddrManager:  s.addrManager,
-			Clock:        &rpcClock{},
-			SubsidyCache: s.subsidyCache,
-			Chain:        &rpcChain{s.chain},
-			ChainParams:  chainParams,
+			Listeners:     rpcListeners,
+			RESTListeners: restListeners,
+			ProfilerMgr:   profiler,
+			ConnMgr:       &rpcConnManager{&s},
+			SyncMgr:       &rpcSyncMgr{server: &s, syncMgr: s.syncManager},
+			FeeEstimator:  s.feeEstimator,
+			TimeSource:    s.timeSource,
+			Services:      s.services,
+			AddrManager:   s.addrManager,
+			Clock:         &rpcClock{},
+			SubsidyCache:  s.subsidyCache,
+			Chain:         &rpcChain{s.chain},
+			ChainParams:   chainParams,
 			SanityChecker: &rpcSanityChecker{
 				chain:       s.chain,
 				timeSource:  s.timeSource,
@@ -4316,17 +5421,25 @@ func newServer(ctx context.Context, profiler *profileServer,
 			},
 			DB:                   db,
 			TxMempooler:          s.txMemPool,
+			MempoolSaver:         &rpcMempoolSaver{&s},
+			UtxoSnapshotter:      &rpcUtxoSnapshotter{&s},
 			CPUMiner:             &rpcCPUMiner{s.cpuMiner},
 			NetInfo:              cfg.generateNetworkInfo(),
 			MinRelayTxFee:        cfg.minRelayTxFee,
+			MaxMempoolSize:       int64(cfg.MaxMempool) * 1024 * 1024,
+			BanDuration:          cfg.BanDuration,
 			Proxy:                cfg.Proxy,
 			RPCUser:              cfg.RPCUser,
 			RPCPass:              cfg.RPCPass,
 			RPCLimitUser:         cfg.RPCLimitUser,
 			RPCLimitPass:         cfg.RPCLimitPass,
+			RPCAuthUsers:         cfg.rpcAuthUsers,
+			ClientCertGroups:     cfg.rpcCertGroups,
 			RPCMaxClients:        cfg.RPCMaxClients,
 			RPCMaxConcurrentReqs: cfg.RPCMaxConcurrentReqs,
 			RPCMaxWebsockets:     cfg.RPCMaxWebsockets,
+			RPCRateLimit:         cfg.RPCRateLimit,
+			RPCRateBurst:         cfg.RPCRateBurst,
 			TestNet:              cfg.TestNet,
 			MiningAddrs:          cfg.miningAddrs,
 			AllowUnsyncedMining:  cfg.AllowUnsyncedMining,
@@ -4345,6 +5458,15 @@ func newServer(ctx context.Context, profiler *profileServer,
 		if s.txIndex != nil {
 			rpcsConfig.TxIndexer = s.txIndex
 		}
+		if s.addrIndex != nil {
+			rpcsConfig.AddrIndexer = s.addrIndex
+		}
+		if s.spendIndex != nil {
+			rpcsConfig.SpendIndexer = s.spendIndex
+		}
+		if srvrMetrics != nil {
+			rpcsConfig.RequestProcessed = srvrMetrics.rpcRequestProcessed
+		}
 
 		s.rpcServer, err = rpcserver.New(&rpcsConfig)
 		if err != nil {
@@ -4449,13 +5571,24 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 
 // addrStringToNetAddr takes an address in the form of 'host:port' and returns
 // a net.Addr which maps to the original address with any host names resolved
-// to IP addresses.
+// to IP addresses.  Hosts on overlay networks, such as Tor v3 onion services and
+// I2P destinations, are not resolved since they are only reachable via a proxy.
 func addrStringToNetAddr(addr string) (net.Addr, error) {
 	host, strPort, err := net.SplitHostPort(addr)
 	if err != nil {
 		return nil, err
 	}
 
+	// Hosts on overlay networks can't be resolved to an IP address, so
+	// return a proxied address that is dialed by its host name instead.
+	if addrType, _ := addrmgr.EncodeHost(host); isOverlayNetAddrType(addrType) {
+		port, err := strconv.Atoi(strPort)
+		if err != nil {
+			return nil, err
+		}
+		return &socks.ProxiedAddr{Net: "tcp", Host: host, Port: port}, nil
+	}
+
 	// Attempt to look up an IP address associated with the parsed host.
 	// The dcrdLookup function will transparently handle performing the
 	// lookup over Tor if necessary.
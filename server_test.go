@@ -1,14 +1,20 @@
-// Copyright (c) 2024 The Decred developers
+// Copyright (c) 2024-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
 package main
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/decred/dcrd/addrmgr/v3"
 	"github.com/decred/dcrd/wire"
@@ -81,6 +87,22 @@ func TestHostToNetAddress(t *testing.T) {
 		wantErr:    false,
 		want: addrmgr.NewNetAddressFromIPPort(net.ParseIP("12.1.2.3"), 8333,
 			services),
+	}, {
+		name:       "valid tor v3 onion address",
+		host:       "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+		port:       8333,
+		lookupFunc: nil,
+		wantErr:    false,
+		want: func() *addrmgr.NetAddress {
+			pubKey, _ := hex.DecodeString("1d04a1d04a338c6e6ae970bfabee490" +
+				"49d6702250984ca950c01673f4ec034ad")
+			na, err := addrmgr.NewNetAddressFromParams(addrmgr.TorV3Address,
+				pubKey, 8333, time.Unix(time.Now().Unix(), 0), services)
+			if err != nil {
+				panic(err)
+			}
+			return na
+		}(),
 	}, {
 		name:       "valid IPv6 address",
 		host:       "2003::1",
@@ -102,3 +124,142 @@ func TestHostToNetAddress(t *testing.T) {
 		}
 	}
 }
+
+// TestNetAddressV2Conversion ensures address manager net addresses of all
+// supported types round trip through the addrv2 wire representation and that
+// invalid wire addresses are rejected.
+func TestNetAddressV2Conversion(t *testing.T) {
+	const services = wire.SFNodeNetwork
+	timestamp := time.Unix(time.Now().Unix(), 0)
+
+	tests := []struct {
+		name     string                 // test description
+		addrType addrmgr.NetAddressType // address manager address type
+		addr     []byte                 // encoded address
+		wireType wire.NetAddressType    // expected wire address type
+	}{{
+		name:     "ipv4",
+		addrType: addrmgr.IPv4Address,
+		addr:     []byte{12, 1, 2, 3},
+		wireType: wire.IPv4Address,
+	}, {
+		name:     "ipv6",
+		addrType: addrmgr.IPv6Address,
+		addr:     net.ParseIP("2003::1"),
+		wireType: wire.IPv6Address,
+	}, {
+		name:     "tor v3",
+		addrType: addrmgr.TorV3Address,
+		addr:     bytes.Repeat([]byte{0x01}, 32),
+		wireType: wire.TorV3Address,
+	}, {
+		name:     "i2p",
+		addrType: addrmgr.I2PAddress,
+		addr:     bytes.Repeat([]byte{0x02}, 32),
+		wireType: wire.I2PAddress,
+	}}
+
+	for _, test := range tests {
+		na, err := addrmgr.NewNetAddressFromParams(test.addrType, test.addr,
+			9108, timestamp, services)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		wireAddr := addrmgrToWireNetAddressV2(na)
+		if wireAddr == nil {
+			t.Errorf("%q: unexpected nil wire address", test.name)
+			continue
+		}
+		if wireAddr.Type != test.wireType {
+			t.Errorf("%q: unexpected wire type -- got %v, want %v",
+				test.name, wireAddr.Type, test.wireType)
+			continue
+		}
+		got, err := wireToAddrmgrNetAddressV2(wireAddr)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, na) {
+			t.Errorf("%q: mismatched address -- got %v, want %v", test.name,
+				got, na)
+			continue
+		}
+	}
+
+	// Ensure wire addresses with an unknown type or an invalid length for
+	// their type are rejected.
+	invalid := []*wire.NetAddressV2{
+		wire.NewNetAddressV2(timestamp, services, wire.UnknownAddressType,
+			[]byte{12, 1, 2, 3}, 9108),
+		wire.NewNetAddressV2(timestamp, services, wire.TorV3Address,
+			[]byte{12, 1, 2, 3}, 9108),
+	}
+	for _, wireAddr := range invalid {
+		if _, err := wireToAddrmgrNetAddressV2(wireAddr); err == nil {
+			t.Errorf("did not receive error for invalid %v address",
+				wireAddr.Type)
+		}
+	}
+}
+
+// TestListenUnixSocket ensures Unix domain socket listeners are created with
+// the requested permissions and that stale sockets are replaced while active
+// sockets and other files are left untouched.
+func TestListenUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix domain socket permissions are not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "rpc.sock")
+
+	// Leave a stale socket behind and ensure it is replaced.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("unable to create stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := listenUnixSocket(path, 0640, -1, -1)
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unable to stat socket: %v", err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0640 {
+		t.Fatalf("unexpected socket permissions: got %o, want %o", perm,
+			0640)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("unable to connect to socket: %v", err)
+	}
+	conn.Close()
+
+	// Ensure a socket that is still being listened on is not removed.
+	if _, err := listenUnixSocket(path, 0600, -1, -1); err == nil {
+		t.Fatal("did not receive expected error listening over an active " +
+			"socket")
+	}
+	conn, err = net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("active socket was removed: %v", err)
+	}
+	conn.Close()
+	listener.Close()
+
+	// Ensure regular files are not removed.
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+	if _, err := listenUnixSocket(path, 0600, -1, -1); err == nil {
+		t.Fatal("did not receive expected error listening over a file")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("regular file was removed: %v", err)
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/netsync"
+	"github.com/decred/dcrd/internal/progresslog"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// snapshotValidationDirName is the name of the directory in the data
+	// directory that houses the separate chain used to validate the history
+	// prior to the snapshot a chain was created from.
+	snapshotValidationDirName = "snapshotvalidation"
+
+	// snapshotValidationCacheSize is the maximum size in bytes of the UTXO
+	// cache of the chain used to validate the history prior to a snapshot.
+	snapshotValidationCacheSize = 100 * 1024 * 1024 // 100 MiB
+)
+
+// dumpUtxoSnapshot writes a snapshot of the chain state at the current main
+// chain tip to the file at the provided path and returns the full path to the
+// file along with the information that identifies the snapshot.  Relative paths
+// are relative to the data directory.  An error is returned when the file
+// already exists.
+//
+// This function is safe for concurrent access.
+func (s *server) dumpUtxoSnapshot(ctx context.Context, path string) (string, *blockchain.SnapshotInfo, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.DataDir, path)
+	}
+	if fileExists(path) {
+		return "", nil, fmt.Errorf("file %s already exists", path)
+	}
+
+	// Write a temporary snapshot file and then move it into place.
+	tmpFile := path + ".new"
+	f, err := os.OpenFile(tmpFile, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", nil, err
+	}
+	srvrLog.Infof("Writing chain state snapshot to %s", path)
+	info, err := s.chain.DumpUtxoSnapshot(ctx, f)
+	if err != nil {
+		f.Close()
+		os.Remove(tmpFile)
+		return "", nil, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpFile)
+		return "", nil, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpFile)
+		return "", nil, err
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return "", nil, err
+	}
+	srvrLog.Infof("Wrote chain state snapshot of block %s (height %d, "+
+		"snapshot hash %s)", info.BlockHash, info.Height, info.SnapshotHash)
+	return path, info, nil
+}
+
+// loadUtxoSnapshot initializes the provided empty block and UTXO databases
+// with the chain state from the snapshot file specified by the --loadsnapshot
+// option.  Nothing is done when the databases were already created from a
+// snapshot.
+func loadUtxoSnapshot(ctx context.Context, db database.DB, utxoDb *leveldb.DB, params *chaincfg.Params) error {
+	snapshot, _, err := blockchain.FetchImportedSnapshot(db)
+	if err != nil {
+		return err
+	}
+	if snapshot != nil {
+		dcrdLog.Infof("Ignoring --loadsnapshot since the chain was already "+
+			"created from the snapshot of block %s (height %d)",
+			snapshot.BlockHash, snapshot.Height)
+		return nil
+	}
+
+	f, err := os.Open(cfg.LoadSnapshot)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dcrdLog.Infof("Loading chain state snapshot from %s.  This might take a "+
+		"while...", cfg.LoadSnapshot)
+	utxoBackend := blockchain.NewLevelDbUtxoBackend(utxoDb)
+	snapshot, err = blockchain.ImportUtxoSnapshot(ctx, f, db, utxoBackend,
+		params)
+	if err != nil {
+		switch {
+		case errors.Is(err, blockchain.ErrSnapshotExistingChain):
+			return fmt.Errorf("%w: the --loadsnapshot option may only be "+
+				"used with a new data directory", err)
+
+		case errors.Is(err, blockchain.ErrUnknownSnapshot),
+			errors.Is(err, context.Canceled):
+			return err
+		}
+
+		return fmt.Errorf("%w: the data directory %s must be removed before "+
+			"trying again since it might contain a partially loaded chain "+
+			"state", err, cfg.DataDir)
+	}
+	dcrdLog.Infof("Loaded chain state snapshot of block %s (height %d)",
+		snapshot.BlockHash, snapshot.Height)
+	return nil
+}
+
+// snapshotValidator validates the history prior to the snapshot a chain was
+// created from by independently downloading and fully validating all of the
+// blocks up to the snapshot with a separate chain instance that is stored in
+// its own data directory.  Once that chain reaches the snapshot block, a
+// snapshot of its chain state is compared to the expected one and the separate
+// chain is removed when it matches.
+//
+// It implements the netsync.BackgroundValidator interface.
+type snapshotValidator struct {
+	// These fields are set at creation time and treated as immutable after
+	// that.
+	chain    *blockchain.BlockChain
+	snapshot *blockchain.SnapshotInfo
+	dataDir  string
+
+	progressLogger *progresslog.Logger
+
+	// ready is set once the headers up to the snapshot block are known by the
+	// validation chain and done is set once it no longer needs any blocks.
+	ready atomic.Bool
+	done  atomic.Bool
+
+	// reachedSnapshot is signalled when the validation chain reaches the
+	// snapshot block.
+	reachedSnapshot chan struct{}
+	reachedOnce     sync.Once
+
+	// These fields house the validation chain and its databases.  They are
+	// protected by the associated mutex and set to nil once they are closed.
+	mtx     sync.Mutex
+	vChain  *blockchain.BlockChain
+	db      database.DB
+	utxoDb  *leveldb.DB
+	closeDB func()
+}
+
+// Ensure snapshotValidator implements the netsync.BackgroundValidator
+// interface.
+var _ netsync.BackgroundValidator = (*snapshotValidator)(nil)
+
+// newSnapshotValidator returns a validator for the history prior to the
+// provided snapshot the chain was created from.  The validation chain is loaded
+// from the snapshot validation directory in the data directory so the process
+// resumes where it left off after restarts.
+func newSnapshotValidator(ctx context.Context, chain *blockchain.BlockChain, snapshot *blockchain.SnapshotInfo, chainCfg *blockchain.Config) (*snapshotValidator, error) {
+	params := chainCfg.ChainParams
+	dataDir := filepath.Join(cfg.DataDir, snapshotValidationDirName)
+	var db database.DB
+	var err error
+	if cfg.DbType == "memdb" {
+		db, err = database.Create(cfg.DbType)
+	} else {
+		dbPath := filepath.Join(dataDir, blockDbNamePrefix+"_"+cfg.DbType)
+		db, err = database.Open(cfg.DbType, dbPath, params.Net)
+		if errors.Is(err, database.ErrDbDoesNotExist) {
+			if err = os.MkdirAll(dataDir, 0700); err == nil {
+				db, err = database.Create(cfg.DbType, dbPath, params.Net)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	utxoDb, err := blockchain.LoadUtxoDB(ctx, params, dataDir)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	closeDB := func() {
+		utxoDb.Close()
+		db.Close()
+	}
+
+	utxoBackend := blockchain.NewLevelDbUtxoBackend(utxoDb)
+	vChain, err := blockchain.New(ctx, &blockchain.Config{
+		DB:           db,
+		UtxoBackend:  utxoBackend,
+		ChainParams:  params,
+		AssumeValid:  chainCfg.AssumeValid,
+		TimeSource:   chainCfg.TimeSource,
+		SigCache:     chainCfg.SigCache,
+		SubsidyCache: chainCfg.SubsidyCache,
+		UtxoCache: blockchain.NewUtxoCache(&blockchain.UtxoCacheConfig{
+			Backend:      utxoBackend,
+			FlushBlockDB: db.Flush,
+			MaxSize:      snapshotValidationCacheSize,
+		}),
+	})
+	if err != nil {
+		closeDB()
+		return nil, err
+	}
+
+	return &snapshotValidator{
+		chain:           chain,
+		snapshot:        snapshot,
+		dataDir:         dataDir,
+		progressLogger:  progresslog.New("Validated", srvrLog),
+		reachedSnapshot: make(chan struct{}),
+		vChain:          vChain,
+		db:              db,
+		utxoDb:          utxoDb,
+		closeDB:         closeDB,
+	}, nil
+}
+
+// PutNextNeededBlocks populates the provided slice with the hashes of the next
+// blocks the validation chain needs and returns a sub slice of it with the
+// number of entries populated.
+//
+// This function is safe for concurrent access and is part of the
+// netsync.BackgroundValidator interface implementation.
+func (v *snapshotValidator) PutNextNeededBlocks(out []chainhash.Hash) []chainhash.Hash {
+	if !v.ready.Load() || v.done.Load() {
+		return out[:0]
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if v.vChain == nil {
+		return out[:0]
+	}
+	return v.vChain.PutNextNeededBlocks(out)
+}
+
+// ProcessBlock validates the provided block with the validation chain.
+//
+// This function is safe for concurrent access and is part of the
+// netsync.BackgroundValidator interface implementation.
+func (v *snapshotValidator) ProcessBlock(block *dcrutil.Block) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if v.vChain == nil {
+		return nil
+	}
+
+	if _, err := v.vChain.ProcessBlock(block); err != nil {
+		var rErr blockchain.RuleError
+		if errors.As(err, &rErr) {
+			// The block headers are committed to by the main chain and the
+			// sync manager ensures the transactions match the header before
+			// providing the block, so a block that violates the consensus
+			// rules means the history prior to the snapshot is invalid.
+			srvrLog.Criticalf("Block %s prior to the chain state snapshot is "+
+				"invalid: %v", block.Hash(), err)
+			v.finish()
+		}
+		return err
+	}
+
+	best := v.vChain.BestSnapshot()
+	forceLog := best.Height >= v.snapshot.Height
+	v.progressLogger.LogProgress(block.MsgBlock(), forceLog, func() float64 {
+		return float64(best.Height) / float64(v.snapshot.Height) * 100
+	})
+	if best.Height >= v.snapshot.Height {
+		v.finish()
+	}
+	return nil
+}
+
+// Done returns whether or not the validator no longer needs any blocks.
+//
+// This function is safe for concurrent access and is part of the
+// netsync.BackgroundValidator interface implementation.
+func (v *snapshotValidator) Done() bool {
+	return v.done.Load()
+}
+
+// finish marks the validator as done and signals the main handler to finish
+// the validation process.
+func (v *snapshotValidator) finish() {
+	v.done.Store(true)
+	v.reachedOnce.Do(func() { close(v.reachedSnapshot) })
+}
+
+// close shuts down the validation chain and closes its databases.  The data of
+// the validation chain is removed when requested.
+func (v *snapshotValidator) close(remove bool) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if v.vChain == nil {
+		return
+	}
+
+	v.vChain.ShutdownUtxoCache()
+	v.closeDB()
+	v.vChain, v.db, v.utxoDb = nil, nil, nil
+	if remove {
+		if err := os.RemoveAll(v.dataDir); err != nil {
+			srvrLog.Errorf("Unable to remove %s: %v", v.dataDir, err)
+		}
+	}
+}
+
+// addHeaders adds the headers of the main chain up to the snapshot block that
+// are not already known to the validation chain.
+func (v *snapshotValidator) addHeaders(ctx context.Context) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	_, bestHeaderHeight := v.vChain.BestHeader()
+	for height := bestHeaderHeight + 1; height <= v.snapshot.Height; height++ {
+		if height%1000 == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		header, err := v.chain.HeaderByHeight(height)
+		if err != nil {
+			return err
+		}
+		if err := v.vChain.ProcessBlockHeader(&header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verify compares a snapshot of the chain state of the validation chain to the
+// expected one and marks the history of the chain as validated when they match.
+func (v *snapshotValidator) verify(ctx context.Context) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	best := v.vChain.BestSnapshot()
+	if best.Height != v.snapshot.Height || best.Hash != v.snapshot.BlockHash {
+		return fmt.Errorf("the validated chain ended at block %s (height %d) "+
+			"instead of the snapshot block %s (height %d)", best.Hash,
+			best.Height, v.snapshot.BlockHash, v.snapshot.Height)
+	}
+
+	info, err := v.vChain.DumpUtxoSnapshot(ctx, io.Discard)
+	if err != nil {
+		return err
+	}
+	if info.SnapshotHash != v.snapshot.SnapshotHash {
+		return fmt.Errorf("the chain state after validating the history has "+
+			"snapshot hash %s instead of the expected %s", info.SnapshotHash,
+			v.snapshot.SnapshotHash)
+	}
+	return v.chain.MarkSnapshotValidated()
+}
+
+// Run adds the headers needed by the validation chain and then waits for it to
+// reach the snapshot block in order to verify the resulting chain state.  A
+// shutdown is requested when the history is invalid.  It blocks until the
+// provided context is cancelled or the validation process is complete.
+func (v *snapshotValidator) Run(ctx context.Context) {
+	srvrLog.Infof("Validating the history prior to the chain state snapshot "+
+		"of block %s (height %d) in the background", v.snapshot.BlockHash,
+		v.snapshot.Height)
+	if err := v.addHeaders(ctx); err != nil {
+		if !errors.Is(err, context.Canceled) {
+			srvrLog.Errorf("Unable to load headers for the background "+
+				"validation: %v", err)
+		}
+		v.close(false)
+		return
+	}
+	v.ready.Store(true)
+
+	select {
+	case <-v.reachedSnapshot:
+	case <-ctx.Done():
+		v.close(false)
+		return
+	}
+
+	err := v.verify(ctx)
+	if errors.Is(err, context.Canceled) {
+		v.close(false)
+		return
+	}
+	if err != nil {
+		srvrLog.Criticalf("Validation of the history prior to the chain state "+
+			"snapshot failed: %v", err)
+		v.close(false)
+		select {
+		case shutdownRequestChannel <- struct{}{}:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	v.close(true)
+	srvrLog.Infof("Validated the history prior to the chain state snapshot "+
+		"of block %s (height %d)", v.snapshot.BlockHash, v.snapshot.Height)
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/crypto/rand"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+const (
+	// stemRouteEpoch is the amount of time the randomly selected stem routes
+	// are used before they are selected again.  Keeping the routes stable for
+	// a period of time makes it harder for an adversary to learn the origin
+	// of transactions by observing them over multiple routes.
+	stemRouteEpoch = 10 * time.Minute
+
+	// stemFluffPercent is the percentage chance that a transaction received in
+	// the stem phase from a peer is diffused (fluffed) instead of forwarded to
+	// the next peer along the stem route.
+	stemFluffPercent = 10
+
+	// stemEmbargoMin and stemEmbargoJitter define the random amount of time a
+	// transaction that is relayed in the stem phase is given to be diffused by
+	// the network before it is diffused directly.  The embargo is randomized
+	// so the node that ultimately diffuses the transaction when a peer along
+	// the stem route fails to relay it does not reveal its origin.
+	stemEmbargoMin    = 30 * time.Second
+	stemEmbargoJitter = 30 * time.Second
+
+	// stemRequestTimeout is the maximum amount of time to wait for a requested
+	// stem phase transaction to be received before forgetting the request.
+	stemRequestTimeout = 2 * time.Minute
+
+	// maxStemRequests is the maximum number of requested stem phase
+	// transactions that may be outstanding at any given time.
+	maxStemRequests = 1000
+
+	// stemCheckInterval is the interval at which the embargo timers and
+	// outstanding stem requests are checked for expiration.
+	stemCheckInterval = 5 * time.Second
+)
+
+// stemTxn houses a transaction that has been relayed in the stem phase along
+// with the peer it was relayed to and the time at which its embargo expires.
+type stemTxn struct {
+	tx      *dcrutil.Tx
+	dest    *serverPeer
+	embargo time.Time
+}
+
+// stemRequest houses a stem phase transaction that has been requested from a
+// peer along with the time at which the request expires.
+type stemRequest struct {
+	source  *serverPeer
+	expires time.Time
+}
+
+// stemRelay implements Dandelion-style stem/fluff transaction relay.
+//
+// Transactions in the stem phase are relayed to a single randomly selected
+// outbound peer via the stem transaction inventory type as opposed to being
+// announced to all peers.  Each peer along the stem route either forwards the
+// transaction to its own stem destination or, with a small probability,
+// diffuses it to all of its peers which is known as the fluff phase.
+//
+// Every transaction that is relayed in the stem phase has an embargo timer
+// associated with it that causes it to be diffused directly in the case it is
+// not announced back by the network in the fluff phase before it expires.
+// This ensures transactions still propagate when a peer along the stem route
+// misbehaves or disconnects.
+type stemRelay struct {
+	// forward specifies whether or not transactions received in the stem
+	// phase from peers are forwarded along the stem route.  They are always
+	// diffused immediately when it is not set.
+	forward bool
+
+	// candidates returns the peers that may be selected as stem destinations.
+	candidates func() []*serverPeer
+
+	// sendStem relays the provided stem phase transaction to the given peer.
+	sendStem func(sp *serverPeer, tx *dcrutil.Tx)
+
+	// fluff transitions the provided transaction to the fluff phase and
+	// diffuses it to all peers.
+	fluff func(tx *dcrutil.Tx)
+
+	// The following fields are protected by the mutex.
+	//
+	// epochEnd is the time at which the current stem routes expire.
+	//
+	// routes maps the peers stem phase transactions are received from to the
+	// peers they are forwarded to.  Transactions that are submitted locally
+	// use the nil peer.
+	//
+	// txns houses the transactions that are in the stem phase keyed by their
+	// hash.
+	//
+	// requests houses the stem phase transactions that have been requested
+	// from peers keyed by their hash.
+	mtx      sync.Mutex
+	epochEnd time.Time
+	routes   map[*serverPeer]*serverPeer
+	txns     map[chainhash.Hash]*stemTxn
+	requests map[chainhash.Hash]stemRequest
+}
+
+// newStemRelay returns a new stem relay that uses the provided functions to
+// select stem destinations, relay stem phase transactions, and fluff
+// transactions.  Transactions received in the stem phase from peers are only
+// forwarded along the stem route when the forward flag is set.
+func newStemRelay(forward bool, candidates func() []*serverPeer,
+	sendStem func(*serverPeer, *dcrutil.Tx), fluff func(*dcrutil.Tx)) *stemRelay {
+
+	return &stemRelay{
+		forward:    forward,
+		candidates: candidates,
+		sendStem:   sendStem,
+		fluff:      fluff,
+		routes:     make(map[*serverPeer]*serverPeer),
+		txns:       make(map[chainhash.Hash]*stemTxn),
+		requests:   make(map[chainhash.Hash]stemRequest),
+	}
+}
+
+// route returns the stem destination for transactions received from the
+// provided peer, which is nil for locally submitted transactions, selecting a
+// new one from the passed candidates as needed.  It returns nil when there are
+// no suitable candidates.
+//
+// This function MUST be called with the mutex held.
+func (r *stemRelay) route(source *serverPeer, candidates []*serverPeer, now time.Time) *serverPeer {
+	// Select new routes once the current epoch ends.
+	if !now.Before(r.epochEnd) {
+		clear(r.routes)
+		r.epochEnd = now.Add(stemRouteEpoch)
+	}
+
+	// Use the existing route so long as the destination is still a candidate.
+	if dest, ok := r.routes[source]; ok {
+		for _, sp := range candidates {
+			if sp == dest {
+				return dest
+			}
+		}
+	}
+
+	// Select a random candidate other than the source.
+	eligible := make([]*serverPeer, 0, len(candidates))
+	for _, sp := range candidates {
+		if sp != source {
+			eligible = append(eligible, sp)
+		}
+	}
+	if len(eligible) == 0 {
+		delete(r.routes, source)
+		return nil
+	}
+	dest := eligible[rand.IntN(len(eligible))]
+	r.routes[source] = dest
+	return dest
+}
+
+// Relay relays the provided transaction, which must already be marked as being
+// in the stem phase, that was received from the given peer or submitted
+// locally when the peer is nil.
+//
+// The transaction is either relayed to the stem destination for the peer or
+// fluffed.  Locally submitted transactions are always relayed in the stem
+// phase when there is a stem destination available.
+//
+// This function is safe for concurrent access.
+func (r *stemRelay) Relay(source *serverPeer, tx *dcrutil.Tx) {
+	// Randomly fluff transactions received in the stem phase from peers.
+	if source != nil && (!r.forward || rand.IntN(100) < stemFluffPercent) {
+		r.fluff(tx)
+		return
+	}
+
+	// Determine the candidates prior to acquiring the mutex since doing so
+	// involves the peer state.
+	candidates := r.candidates()
+
+	r.mtx.Lock()
+	now := time.Now()
+	dest := r.route(source, candidates, now)
+	if dest != nil {
+		embargo := stemEmbargoMin + rand.Duration(stemEmbargoJitter)
+		r.txns[*tx.Hash()] = &stemTxn{
+			tx:      tx,
+			dest:    dest,
+			embargo: now.Add(embargo),
+		}
+	}
+	r.mtx.Unlock()
+
+	// Fluff the transaction when there is not any stem destination available.
+	if dest == nil {
+		srvrLog.Debugf("No stem destination available for tx %v -- fluffing",
+			tx.Hash())
+		r.fluff(tx)
+		return
+	}
+
+	srvrLog.Debugf("Relaying tx %v in the stem phase to %v", tx.Hash(), dest)
+	r.sendStem(dest, tx)
+}
+
+// Fluffed is invoked when the transaction with the provided hash is announced
+// by a peer in the fluff phase.  It stops the associated embargo timer and
+// fluffs the transaction when it is in the stem phase since that means the
+// network has already diffused it.
+//
+// This function is safe for concurrent access.
+func (r *stemRelay) Fluffed(hash *chainhash.Hash) {
+	r.mtx.Lock()
+	stemTx, ok := r.txns[*hash]
+	delete(r.txns, *hash)
+	r.mtx.Unlock()
+
+	if ok {
+		r.fluff(stemTx.tx)
+	}
+}
+
+// StemTx returns the stem phase transaction with the provided hash when it was
+// relayed to the given peer.  Stem phase transactions are never served to any
+// other peers since that would allow them to be probed for.
+//
+// This function is safe for concurrent access.
+func (r *stemRelay) StemTx(sp *serverPeer, hash *chainhash.Hash) *dcrutil.Tx {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	stemTx, ok := r.txns[*hash]
+	if !ok || stemTx.dest != sp {
+		return nil
+	}
+	return stemTx.tx
+}
+
+// AddRequest tracks that the stem phase transaction with the provided hash is
+// about to be requested from the given peer.  It returns false when the
+// transaction should not be requested because it is already being tracked or
+// there are already too many outstanding requests.
+//
+// This function is safe for concurrent access.
+func (r *stemRelay) AddRequest(source *serverPeer, hash *chainhash.Hash) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := r.requests[*hash]; ok {
+		return false
+	}
+	if _, ok := r.txns[*hash]; ok {
+		return false
+	}
+	if len(r.requests) >= maxStemRequests {
+		return false
+	}
+	r.requests[*hash] = stemRequest{
+		source:  source,
+		expires: time.Now().Add(stemRequestTimeout),
+	}
+	return true
+}
+
+// TakeRequest removes the outstanding request for the stem phase transaction
+// with the provided hash and returns whether or not it was requested from the
+// given peer.
+//
+// This function is safe for concurrent access.
+func (r *stemRelay) TakeRequest(source *serverPeer, hash *chainhash.Hash) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	req, ok := r.requests[*hash]
+	if !ok || req.source != source {
+		return false
+	}
+	delete(r.requests, *hash)
+	return true
+}
+
+// RemovePeer removes all stem routes and outstanding requests that involve the
+// provided peer.  Stem phase transactions that were relayed to the peer are
+// left to be fluffed once their embargo expires since the peer might have
+// already forwarded them.
+//
+// This function is safe for concurrent access.
+func (r *stemRelay) RemovePeer(sp *serverPeer) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for source, dest := range r.routes {
+		if source == sp || dest == sp {
+			delete(r.routes, source)
+		}
+	}
+	for hash, req := range r.requests {
+		if req.source == sp {
+			delete(r.requests, hash)
+		}
+	}
+}
+
+// expire fluffs all stem phase transactions with an embargo that expired as of
+// the provided time and removes all outstanding requests that timed out.
+//
+// This function is safe for concurrent access.
+func (r *stemRelay) expire(now time.Time) {
+	var expired []*dcrutil.Tx
+	r.mtx.Lock()
+	for hash, stemTx := range r.txns {
+		if now.After(stemTx.embargo) {
+			expired = append(expired, stemTx.tx)
+			delete(r.txns, hash)
+		}
+	}
+	for hash, req := range r.requests {
+		if now.After(req.expires) {
+			delete(r.requests, hash)
+		}
+	}
+	r.mtx.Unlock()
+
+	for _, tx := range expired {
+		srvrLog.Debugf("Embargo for stem tx %v expired -- fluffing", tx.Hash())
+		r.fluff(tx)
+	}
+}
+
+// Run periodically fluffs stem phase transactions with expired embargo timers
+// until the provided context is cancelled.
+//
+// It must be run in a goroutine.
+func (r *stemRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(stemCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			r.expire(now)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+// stemRelayHarness provides a stem relay along with the stem destination
+// candidates and tracking for the transactions it relays.
+type stemRelayHarness struct {
+	relay      *stemRelay
+	candidates []*serverPeer
+	sent       map[chainhash.Hash]*serverPeer
+	fluffed    map[chainhash.Hash]int
+}
+
+// newStemRelayHarness returns a stem relay harness with the provided number of
+// stem destination candidates.
+func newStemRelayHarness(forward bool, numCandidates int) *stemRelayHarness {
+	h := &stemRelayHarness{
+		sent:    make(map[chainhash.Hash]*serverPeer),
+		fluffed: make(map[chainhash.Hash]int),
+	}
+	for i := 0; i < numCandidates; i++ {
+		h.candidates = append(h.candidates, &serverPeer{})
+	}
+	h.relay = newStemRelay(forward, func() []*serverPeer {
+		return h.candidates
+	}, func(sp *serverPeer, tx *dcrutil.Tx) {
+		h.sent[*tx.Hash()] = sp
+	}, func(tx *dcrutil.Tx) {
+		h.fluffed[*tx.Hash()]++
+	})
+	return h
+}
+
+// makeStemTestTx returns a unique transaction for the given seed.
+func makeStemTestTx(seed uint32) *dcrutil.Tx {
+	tx := wire.NewMsgTx()
+	prevOut := wire.NewOutPoint(&chainhash.Hash{}, seed, wire.TxTreeRegular)
+	tx.AddTxIn(wire.NewTxIn(prevOut, 0, nil))
+	tx.AddTxOut(wire.NewTxOut(int64(seed), []byte{0x51}))
+	return dcrutil.NewTx(tx)
+}
+
+// TestStemRelayRouting ensures transactions are relayed along stable stem
+// routes, only served to the peer they were relayed to, and fluffed when there
+// is no stem destination available.
+func TestStemRelayRouting(t *testing.T) {
+	t.Parallel()
+
+	// Ensure locally submitted transactions are fluffed when there are no stem
+	// destinations available.
+	h := newStemRelayHarness(true, 0)
+	tx := makeStemTestTx(0)
+	h.relay.Relay(nil, tx)
+	if h.fluffed[*tx.Hash()] != 1 || len(h.sent) != 0 {
+		t.Fatalf("tx not fluffed without candidates (fluffed %d, sent %d)",
+			h.fluffed[*tx.Hash()], len(h.sent))
+	}
+
+	// Ensure transactions from the same source are relayed to the same stem
+	// destination.
+	h = newStemRelayHarness(true, 8)
+	var dest *serverPeer
+	for i := uint32(0); i < 10; i++ {
+		tx := makeStemTestTx(i)
+		h.relay.Relay(nil, tx)
+		sentTo, ok := h.sent[*tx.Hash()]
+		if !ok {
+			t.Fatalf("tx %d not relayed in the stem phase", i)
+		}
+		if dest == nil {
+			dest = sentTo
+		}
+		if sentTo != dest {
+			t.Fatalf("tx %d relayed via a different stem route", i)
+		}
+	}
+	if len(h.fluffed) != 0 {
+		t.Fatalf("unexpected fluffed txns: %v", h.fluffed)
+	}
+
+	// Ensure stem transactions are only served to the stem destination.
+	tx = makeStemTestTx(0)
+	got := h.relay.StemTx(dest, tx.Hash())
+	if got == nil || *got.Hash() != *tx.Hash() {
+		t.Fatal("stem tx not served to stem destination")
+	}
+	for _, sp := range h.candidates {
+		if sp != dest && h.relay.StemTx(sp, tx.Hash()) != nil {
+			t.Fatal("stem tx served to peer other than stem destination")
+		}
+	}
+
+	// Ensure a new stem destination is selected once the destination is no
+	// longer a candidate and that transactions are never routed back to the
+	// peer they were received from.
+	h.relay.RemovePeer(dest)
+	h.candidates = []*serverPeer{dest}
+	tx = makeStemTestTx(100)
+	h.relay.Relay(dest, tx)
+	if h.fluffed[*tx.Hash()] != 1 {
+		t.Fatal("tx routed back to the peer it was received from")
+	}
+
+	// Ensure transactions received in the stem phase from peers are always
+	// fluffed when forwarding is disabled.
+	h = newStemRelayHarness(false, 8)
+	for i := uint32(0); i < 10; i++ {
+		tx := makeStemTestTx(i)
+		h.relay.Relay(h.candidates[0], tx)
+		if h.fluffed[*tx.Hash()] != 1 {
+			t.Fatalf("tx %d not fluffed with forwarding disabled", i)
+		}
+	}
+}
+
+// TestStemRelayEmbargo ensures stem phase transactions are fluffed exactly once
+// when they are either announced in the fluff phase or their embargo expires.
+func TestStemRelayEmbargo(t *testing.T) {
+	t.Parallel()
+
+	h := newStemRelayHarness(true, 2)
+	fluffedTx := makeStemTestTx(0)
+	embargoedTx := makeStemTestTx(1)
+	h.relay.Relay(nil, fluffedTx)
+	h.relay.Relay(nil, embargoedTx)
+
+	// Ensure a transaction announced in the fluff phase is fluffed and no
+	// longer served as a stem transaction.
+	h.relay.Fluffed(fluffedTx.Hash())
+	h.relay.Fluffed(fluffedTx.Hash())
+	if n := h.fluffed[*fluffedTx.Hash()]; n != 1 {
+		t.Fatalf("tx announced in the fluff phase fluffed %d times", n)
+	}
+	if h.relay.StemTx(h.sent[*fluffedTx.Hash()], fluffedTx.Hash()) != nil {
+		t.Fatal("fluffed tx still served as stem tx")
+	}
+
+	// Ensure the embargo timer does not fire early.
+	now := time.Now()
+	h.relay.expire(now)
+	if n := h.fluffed[*embargoedTx.Hash()]; n != 0 {
+		t.Fatal("tx fluffed prior to embargo expiration")
+	}
+
+	// Ensure the transaction is fluffed once its embargo expires.
+	h.relay.expire(now.Add(stemEmbargoMin + stemEmbargoJitter + time.Second))
+	h.relay.expire(now.Add(2 * (stemEmbargoMin + stemEmbargoJitter)))
+	if n := h.fluffed[*embargoedTx.Hash()]; n != 1 {
+		t.Fatalf("tx with expired embargo fluffed %d times", n)
+	}
+	if n := h.fluffed[*fluffedTx.Hash()]; n != 1 {
+		t.Fatalf("already fluffed tx fluffed %d times", n)
+	}
+}
+
+// TestStemRelayRequests ensures outstanding stem transaction requests are
+// tracked per peer, limited, and expired.
+func TestStemRelayRequests(t *testing.T) {
+	t.Parallel()
+
+	h := newStemRelayHarness(true, 2)
+	relay := h.relay
+	sp1, sp2 := h.candidates[0], h.candidates[1]
+	hash := makeStemTestTx(0).Hash()
+
+	// Ensure duplicate requests are not made and that the request is only
+	// satisfied by the peer it was made to.
+	if !relay.AddRequest(sp1, hash) {
+		t.Fatal("AddRequest: initial request rejected")
+	}
+	if relay.AddRequest(sp2, hash) {
+		t.Fatal("AddRequest: duplicate request accepted")
+	}
+	if relay.TakeRequest(sp2, hash) {
+		t.Fatal("TakeRequest: request satisfied by wrong peer")
+	}
+	if !relay.TakeRequest(sp1, hash) {
+		t.Fatal("TakeRequest: request not satisfied by requested peer")
+	}
+	if relay.TakeRequest(sp1, hash) {
+		t.Fatal("TakeRequest: request satisfied twice")
+	}
+
+	// Ensure transactions already in the stem phase are not requested.
+	tx := makeStemTestTx(1)
+	relay.Relay(nil, tx)
+	if relay.AddRequest(sp1, tx.Hash()) {
+		t.Fatal("AddRequest: request for tracked stem tx accepted")
+	}
+
+	// Ensure requests are removed when the peer is removed.
+	if !relay.AddRequest(sp1, hash) {
+		t.Fatal("AddRequest: request rejected")
+	}
+	relay.RemovePeer(sp1)
+	if relay.TakeRequest(sp1, hash) {
+		t.Fatal("TakeRequest: request for removed peer satisfied")
+	}
+
+	// Ensure the number of outstanding requests is limited and that requests
+	// expire.
+	for i := uint32(0); i < maxStemRequests; i++ {
+		hash := makeStemTestTx(i + 10).Hash()
+		if !relay.AddRequest(sp2, hash) {
+			t.Fatalf("AddRequest: request %d rejected", i)
+		}
+	}
+	if relay.AddRequest(sp2, hash) {
+		t.Fatal("AddRequest: request beyond limit accepted")
+	}
+	relay.expire(time.Now().Add(stemRequestTimeout + time.Second))
+	if !relay.AddRequest(sp2, hash) {
+		t.Fatal("AddRequest: request rejected after expiration")
+	}
+}
@@ -48,13 +48,16 @@ interactions in no particular order.
 
 	Peer A Sends                          Peer B Responds
 	----------------------------------------------------------------------------
-	getaddr message (MsgGetAddr)          addr message (MsgAddr)
+	getaddr message (MsgGetAddr)          addr message (MsgAddr) -or-
+	                                      addrv2 message (MsgAddrV2)
 	getblocks message (MsgGetBlocks)      inv message (MsgInv)
 	inv message (MsgInv)                  getdata message (MsgGetData)
 	getdata message (MsgGetData)          block message (MsgBlock) -or-
 	                                      tx message (MsgTx) -or-
 	                                      notfound message (MsgNotFound)
 	getheaders message (MsgGetHeaders)    headers message (MsgHeaders)
+	sendcmpct message (MsgSendCmpct)      cmpctblock message (MsgCmpctBlock)**
+	getblocktxns message (MsgGetBlockTxns) blocktxns message (MsgBlockTxns)
 	ping message (MsgPing)                pong message (MsgHeaders)* -or-
 	                                      (none -- Ability to send message is enough)
 
@@ -62,6 +65,10 @@ interactions in no particular order.
 	* The pong message was not added until later protocol versions as defined
 	  in BIP0031.  The BIP0031Version constant can be used to detect a recent
 	  enough protocol version for this purpose (version > BIP0031Version).
+	** Compact blocks are sent in response to getdata requests for the
+	  MSG_CMPCT_BLOCK inventory type and, when the high bandwidth mode is
+	  requested via sendcmpct, unsolicited to announce new blocks.  They were
+	  not added until protocol version CompactBlockVersion.
 
 # Common Parameters
 
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2015 The btcsuite developers
-// Copyright (c) 2015-2025 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -153,6 +153,10 @@ const (
 	// ErrTooManyCFilters is returned when the number of committed filters
 	// exceeds the maximum allowed in a batch.
 	ErrTooManyCFilters
+
+	// ErrUnknownNetAddrType is returned when a network address is of an
+	// unknown type.
+	ErrUnknownNetAddrType
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -193,6 +197,7 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrTooManyMixPairReqUTXOs:        "ErrTooManyMixPairReqUTXOs",
 	ErrTooManyPrevMixMsgs:            "ErrTooManyPrevMixMsgs",
 	ErrTooManyCFilters:               "ErrTooManyCFilters",
+	ErrUnknownNetAddrType:            "ErrUnknownNetAddrType",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -55,6 +55,7 @@ func TestMessageErrorCodeStringer(t *testing.T) {
 		{ErrTooManyMixPairReqUTXOs, "ErrTooManyMixPairReqUTXOs"},
 		{ErrTooManyPrevMixMsgs, "ErrTooManyPrevMixMsgs"},
 		{ErrTooManyCFilters, "ErrTooManyCFilters"},
+		{ErrUnknownNetAddrType, "ErrUnknownNetAddrType"},
 
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -31,6 +31,8 @@ const (
 	InvTypeBlock         InvType = 2
 	InvTypeFilteredBlock InvType = 3
 	InvTypeMix           InvType = 4
+	InvTypeCmpctBlock    InvType = 5
+	InvTypeStemTx        InvType = 6
 )
 
 // Map of service flags back to their constant names for pretty printing.
@@ -40,6 +42,8 @@ var ivStrings = map[InvType]string{
 	InvTypeBlock:         "MSG_BLOCK",
 	InvTypeFilteredBlock: "MSG_FILTERED_BLOCK",
 	InvTypeMix:           "MSG_MIX",
+	InvTypeCmpctBlock:    "MSG_CMPCT_BLOCK",
+	InvTypeStemTx:        "MSG_STEM_TX",
 }
 
 // String returns the InvType in human-readable form.
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -24,6 +24,8 @@ func TestInvTypeStringer(t *testing.T) {
 		{InvTypeTx, "MSG_TX"},
 		{InvTypeBlock, "MSG_BLOCK"},
 		{InvTypeMix, "MSG_MIX"},
+		{InvTypeCmpctBlock, "MSG_CMPCT_BLOCK"},
+		{InvTypeStemTx, "MSG_STEM_TX"},
 		{0xffffffff, "Unknown InvType (4294967295)"},
 	}
 
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -62,6 +62,11 @@ const (
 	CmdMixSecrets      = "mixsecrets"
 	CmdGetCFiltersV2   = "getcfsv2"
 	CmdCFiltersV2      = "cfiltersv2"
+	CmdAddrV2          = "addrv2"
+	CmdSendCmpct       = "sendcmpct"
+	CmdCmpctBlock      = "cmpctblock"
+	CmdGetBlockTxns    = "getblocktxns"
+	CmdBlockTxns       = "blocktxns"
 )
 
 const (
@@ -225,6 +230,21 @@ func makeEmptyMessage(command string) (Message, error) {
 	case CmdCFiltersV2:
 		msg = &MsgCFiltersV2{}
 
+	case CmdAddrV2:
+		msg = &MsgAddrV2{}
+
+	case CmdSendCmpct:
+		msg = &MsgSendCmpct{}
+
+	case CmdCmpctBlock:
+		msg = &MsgCmpctBlock{}
+
+	case CmdGetBlockTxns:
+		msg = &MsgGetBlockTxns{}
+
+	case CmdBlockTxns:
+		msg = &MsgBlockTxns{}
+
 	default:
 		str := fmt.Sprintf("unhandled command [%s]", command)
 		return nil, messageError(op, ErrUnknownCmd, str)
@@ -59,6 +59,7 @@ func TestMessage(t *testing.T) {
 	msgVerack := NewMsgVerAck()
 	msgGetAddr := NewMsgGetAddr()
 	msgAddr := NewMsgAddr()
+	msgAddrV2 := NewMsgAddrV2()
 	msgGetBlocks := NewMsgGetBlocks(&chainhash.Hash{})
 	msgBlock := &testBlock
 	msgInv := NewMsgInv()
@@ -90,6 +91,10 @@ func TestMessage(t *testing.T) {
 	msgMixDC := NewMsgMixDCNet([33]byte{}, [32]byte{}, 1, []MixVect{make(MixVect, 1)}, []chainhash.Hash{})
 	msgMixCM := NewMsgMixConfirm([33]byte{}, [32]byte{}, 1, NewMsgTx(), []chainhash.Hash{})
 	msgMixRS := NewMsgMixSecrets([33]byte{}, [32]byte{}, 1, [32]byte{}, [][]byte{}, MixVect{})
+	msgSendCmpct := NewMsgSendCmpct(true, CmpctBlockEncodingVersion)
+	msgCmpctBlock := testCmpctBlock()
+	msgGetBlockTxns := NewMsgGetBlockTxns(&chainhash.Hash{}, []uint32{1, 2})
+	msgBlockTxns := NewMsgBlockTxns(&chainhash.Hash{}, []*MsgTx{})
 
 	tests := []struct {
 		in     Message     // Value to encode
@@ -129,6 +134,11 @@ func TestMessage(t *testing.T) {
 		{msgMixDC, msgMixDC, pver, MainNet, 181},
 		{msgMixCM, msgMixCM, pver, MainNet, 173},
 		{msgMixRS, msgMixRS, pver, MainNet, 192},
+		{msgAddrV2, msgAddrV2, pver, MainNet, 25},
+		{msgSendCmpct, msgSendCmpct, pver, MainNet, 33},
+		{msgCmpctBlock, msgCmpctBlock, pver, MainNet, 544},
+		{msgGetBlockTxns, msgGetBlockTxns, pver, MainNet, 59},
+		{msgBlockTxns, msgBlockTxns, pver, MainNet, 57},
 	}
 
 	t.Logf("Running %d tests", len(tests))
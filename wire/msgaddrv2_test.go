@@ -0,0 +1,309 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestAddrV2 tests the MsgAddrV2 API.
+func TestAddrV2(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "addrv2"
+	msg := NewMsgAddrV2()
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgAddrV2: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Num addresses (size of varInt for max address) + max allowed addresses *
+	// (timestamp 4 bytes + services 8 bytes + type 1 byte + max address 32
+	// bytes + port 2 bytes).
+	wantPayload := uint32(47003)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol "+
+			"version %d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+
+	// Ensure max payload is zero for protocol versions prior to the addrv2
+	// message.
+	if maxPayload := msg.MaxPayloadLength(AddrV2Version - 1); maxPayload != 0 {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want 0", AddrV2Version-1, maxPayload)
+	}
+
+	// Ensure addresses are added properly.
+	na := NewNetAddressV2(time.Now(), SFNodeNetwork, IPv4Address,
+		[]byte{127, 0, 0, 1}, 9108)
+	err := msg.AddAddress(na)
+	if err != nil {
+		t.Errorf("AddAddress: %v", err)
+	}
+	if msg.AddrList[0] != na {
+		t.Errorf("AddAddress: wrong address added - got %v, want %v",
+			spew.Sprint(msg.AddrList[0]), spew.Sprint(na))
+	}
+
+	// Ensure the address list is cleared properly.
+	msg.ClearAddresses()
+	if len(msg.AddrList) != 0 {
+		t.Errorf("ClearAddresses: address list is not empty - got %v [%v], "+
+			"want %v", len(msg.AddrList), spew.Sprint(msg.AddrList[0]), 0)
+	}
+
+	// Ensure adding more than the max allowed addresses per message returns
+	// error.
+	for i := 0; i < MaxAddrPerV2Msg+1; i++ {
+		err = msg.AddAddress(na)
+	}
+	if !errors.Is(err, ErrTooManyAddrs) {
+		t.Errorf("AddAddress: wrong error on too many addresses - got %v, "+
+			"want %v", err, ErrTooManyAddrs)
+	}
+	err = msg.AddAddresses(na)
+	if !errors.Is(err, ErrTooManyAddrs) {
+		t.Errorf("AddAddresses: wrong error on too many addresses - got %v, "+
+			"want %v", err, ErrTooManyAddrs)
+	}
+}
+
+// TestAddrV2Wire tests the MsgAddrV2 wire encode and decode for various address
+// types.
+func TestAddrV2Wire(t *testing.T) {
+	// A few addresses of different types to use for testing.
+	timestamp := time.Unix(0x495fab29, 0) // 2009-01-03 12:15:05 -0600 CST
+	ipv4 := NewNetAddressV2(timestamp, SFNodeNetwork, IPv4Address,
+		[]byte{127, 0, 0, 1}, 9108)
+	ipv6 := NewNetAddressV2(timestamp, SFNodeNetwork, IPv6Address,
+		bytes.Repeat([]byte{0x20}, 16), 9108)
+	torV3 := NewNetAddressV2(timestamp, SFNodeNetwork, TorV3Address,
+		bytes.Repeat([]byte{0x01}, 32), 9108)
+	i2p := NewNetAddressV2(timestamp, SFNodeNetwork, I2PAddress,
+		bytes.Repeat([]byte{0x02}, 32), 0)
+
+	// Empty address message.
+	noAddr := NewMsgAddrV2()
+	noAddrEncoded := []byte{
+		0x00, // Varint for number of addresses
+	}
+
+	// Address message with one address of each type.
+	multiAddr := NewMsgAddrV2()
+	multiAddr.AddAddresses(ipv4, ipv6, torV3, i2p)
+	multiAddrEncoded := []byte{
+		0x04,                   // Varint for number of addresses
+		0x29, 0xab, 0x5f, 0x49, // Timestamp
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+		0x01,                   // IPv4Address
+		0x7f, 0x00, 0x00, 0x01, // IP 127.0.0.1
+		0x23, 0x94, // Port 9108 in big-endian
+		0x29, 0xab, 0x5f, 0x49, // Timestamp
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+		0x02,                                           // IPv6Address
+		0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, // IP 2020:2020:...
+		0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20,
+		0x23, 0x94, // Port 9108 in big-endian
+		0x29, 0xab, 0x5f, 0x49, // Timestamp
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+		0x04,                                           // TorV3Address
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, // Public key
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+		0x23, 0x94, // Port 9108 in big-endian
+		0x29, 0xab, 0x5f, 0x49, // Timestamp
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+		0x05,                                           // I2PAddress
+		0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, // Destination hash
+		0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+		0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+		0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02,
+		0x00, 0x00, // Port 0 in big-endian
+	}
+
+	tests := []struct {
+		in   *MsgAddrV2 // Message to encode
+		out  *MsgAddrV2 // Expected decoded message
+		buf  []byte     // Wire encoding
+		pver uint32     // Protocol version for wire encoding
+	}{
+		// Latest protocol version with no addresses.
+		{
+			noAddr,
+			noAddr,
+			noAddrEncoded,
+			ProtocolVersion,
+		},
+
+		// Latest protocol version with multiple addresses.
+		{
+			multiAddr,
+			multiAddr,
+			multiAddrEncoded,
+			ProtocolVersion,
+		},
+
+		// Protocol version AddrV2Version with multiple addresses.
+		{
+			multiAddr,
+			multiAddr,
+			multiAddrEncoded,
+			AddrV2Version,
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgAddrV2
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestAddrV2WireErrors performs negative tests against wire encode and decode
+// of MsgAddrV2 to confirm error paths work correctly.
+func TestAddrV2WireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	oldPver := AddrV2Version - 1
+
+	timestamp := time.Unix(0x495fab29, 0) // 2009-01-03 12:15:05 -0600 CST
+	na := NewNetAddressV2(timestamp, SFNodeNetwork, IPv4Address,
+		[]byte{127, 0, 0, 1}, 9108)
+
+	// Address message with a single address.
+	baseAddr := NewMsgAddrV2()
+	baseAddr.AddAddress(na)
+	baseAddrEncoded := []byte{
+		0x01,                   // Varint for number of addresses
+		0x29, 0xab, 0x5f, 0x49, // Timestamp
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+		0x01,                   // IPv4Address
+		0x7f, 0x00, 0x00, 0x01, // IP 127.0.0.1
+		0x23, 0x94, // Port 9108 in big-endian
+	}
+
+	// Message that forces an error by having more than the max allowed
+	// addresses.
+	maxAddr := NewMsgAddrV2()
+	for i := 0; i < MaxAddrPerV2Msg; i++ {
+		maxAddr.AddAddress(na)
+	}
+	maxAddr.AddrList = append(maxAddr.AddrList, na)
+	maxAddrEncoded := []byte{
+		0xfd, 0xe9, 0x03, // Varint for number of addresses (1001)
+	}
+
+	// Message that forces an error by having an address of an unknown type.
+	unknownTypeAddr := NewMsgAddrV2()
+	unknownTypeAddr.AddAddress(NewNetAddressV2(timestamp, SFNodeNetwork, 3,
+		[]byte{127, 0, 0, 1}, 9108))
+	unknownTypeAddrEncoded := []byte{
+		0x01,                   // Varint for number of addresses
+		0x29, 0xab, 0x5f, 0x49, // Timestamp
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // SFNodeNetwork
+		0x03, // Unknown type
+	}
+
+	// Message that forces an error by having an address with a length that
+	// does not match its type.  The wire encoding is not used since it is
+	// impossible to encode.
+	badLenAddr := NewMsgAddrV2()
+	badLenAddr.AddAddress(NewNetAddressV2(timestamp, SFNodeNetwork,
+		TorV3Address, []byte{127, 0, 0, 1}, 9108))
+
+	tests := []struct {
+		in       *MsgAddrV2 // Value to encode
+		buf      []byte     // Wire encoding
+		pver     uint32     // Protocol version for wire encoding
+		max      int        // Max size of fixed buffer to induce errors
+		writeErr error      // Expected write error
+		readErr  error      // Expected read error
+	}{
+		// Latest protocol version with intentional read/write errors.
+		// Force error in addresses count.
+		{baseAddr, baseAddrEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in address timestamp.
+		{baseAddr, baseAddrEncoded, pver, 1, io.ErrShortWrite, io.EOF},
+		// Force error in address type.
+		{baseAddr, baseAddrEncoded, pver, 13, io.ErrShortWrite, io.EOF},
+		// Force error in encoded address.
+		{baseAddr, baseAddrEncoded, pver, 14, io.ErrShortWrite, io.EOF},
+		// Force error in port.
+		{baseAddr, baseAddrEncoded, pver, 18, io.ErrShortWrite, io.EOF},
+		// Force error with greater than max addresses.
+		{maxAddr, maxAddrEncoded, pver, 3, ErrTooManyAddrs, ErrTooManyAddrs},
+		// Force error with an unknown address type.
+		{unknownTypeAddr, unknownTypeAddrEncoded, pver, 14,
+			ErrUnknownNetAddrType, ErrUnknownNetAddrType},
+		// Force error with an address length that does not match its type.
+		{badLenAddr, baseAddrEncoded, pver, 100, ErrInvalidMsg, nil},
+		// Force error with protocol version prior to the addrv2 message.
+		{baseAddr, baseAddrEncoded, oldPver, 100, ErrMsgInvalidForPVer,
+			ErrMsgInvalidForPVer},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgAddrV2
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
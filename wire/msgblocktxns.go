@@ -0,0 +1,126 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// MsgBlockTxns implements the Message interface and represents a blocktxns
+// message.  It is used to deliver the transactions of a block requested by a
+// getblocktxns message (MsgGetBlockTxns) in the order they were requested.
+//
+// This message was not added until protocol version CompactBlockVersion.
+type MsgBlockTxns struct {
+	BlockHash    chainhash.Hash
+	Transactions []*MsgTx
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgBlockTxns) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgBlockTxns.BtcDecode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	// Prevent more transactions than could possibly fit into the regular
+	// tx tree.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	msg.Transactions = make([]*MsgTx, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var tx MsgTx
+		err := tx.BtcDecode(r, pver)
+		if err != nil {
+			return err
+		}
+		msg.Transactions = append(msg.Transactions, &tx)
+	}
+
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgBlockTxns) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgBlockTxns.BtcEncode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count := uint64(len(msg.Transactions))
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	err = WriteVarInt(w, pver, count)
+	if err != nil {
+		return err
+	}
+	for _, tx := range msg.Transactions {
+		err = tx.BtcEncode(w, pver)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgBlockTxns) Command() string {
+	return CmdBlockTxns
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgBlockTxns) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlockVersion {
+		return 0
+	}
+
+	// Block hash + the transactions which can never exceed the max size of
+	// a block.
+	return chainhash.HashSize + MaxBlockPayload
+}
+
+// NewMsgBlockTxns returns a new Decred blocktxns message that conforms to the
+// Message interface using the passed parameters.
+func NewMsgBlockTxns(blockHash *chainhash.Hash, txns []*MsgTx) *MsgBlockTxns {
+	return &MsgBlockTxns{
+		BlockHash:    *blockHash,
+		Transactions: txns,
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestBlockTxns tests the MsgBlockTxns API.
+func TestBlockTxns(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "blocktxns"
+	msg := NewMsgBlockTxns(&chainhash.Hash{}, nil)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgBlockTxns: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Block hash 32 bytes + max block payload.
+	wantPayload := uint32(1310752)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure the message is rejected for protocol versions prior to its
+	// introduction.
+	oldPver := CompactBlockVersion - 1
+	if maxPayload := msg.MaxPayloadLength(oldPver); maxPayload != 0 {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want 0", oldPver, maxPayload)
+	}
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcEncode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+	var readMsg MsgBlockTxns
+	err = readMsg.BtcDecode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcDecode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestBlockTxnsWire tests the MsgBlockTxns wire encode and decode.
+func TestBlockTxnsWire(t *testing.T) {
+	hash := testBlock.Header.BlockHash()
+	msg := NewMsgBlockTxns(&hash, testBlock.Transactions)
+
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	wantLen := chainhash.HashSize + 1
+	for _, tx := range testBlock.Transactions {
+		wantLen += tx.SerializeSize()
+	}
+	if buf.Len() != wantLen {
+		t.Fatalf("BtcEncode: unexpected length - got %d, want %d", buf.Len(),
+			wantLen)
+	}
+	encoded := buf.Bytes()
+
+	var readMsg MsgBlockTxns
+	err := readMsg.BtcDecode(bytes.NewReader(encoded), ProtocolVersion)
+	if err != nil {
+		t.Fatalf("BtcDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&readMsg, msg) {
+		t.Fatalf("BtcDecode\n got: %s want: %s", spew.Sdump(&readMsg),
+			spew.Sdump(msg))
+	}
+
+	// Ensure truncated encodings are detected.
+	for _, max := range []int{0, 16, chainhash.HashSize + 1, len(encoded) - 1} {
+		r := newFixedReader(max, encoded)
+		err := readMsg.BtcDecode(r, ProtocolVersion)
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("BtcDecode: wrong error with max %d: %v", max, err)
+		}
+	}
+
+	// Ensure too many transactions are rejected.
+	tooMany := append(append([]byte(nil), hash[:]...), 0xfe, 0xff, 0xff,
+		0xff, 0xff)
+	err = readMsg.BtcDecode(bytes.NewReader(tooMany), ProtocolVersion)
+	if !errors.Is(err, ErrTooManyTxs) {
+		t.Errorf("BtcDecode: unexpected error - got %v, want %v", err,
+			ErrTooManyTxs)
+	}
+}
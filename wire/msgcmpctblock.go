@@ -0,0 +1,306 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// CmpctShortIDSize is the number of bytes used to encode the short
+	// transaction ids of a compact block.
+	CmpctShortIDSize = 6
+
+	// MaxCmpctShortID is the maximum value of a short transaction id of a
+	// compact block.
+	MaxCmpctShortID = 1<<(CmpctShortIDSize*8) - 1
+)
+
+// PrefilledTx houses a transaction that is included in full in a compact block
+// along with its index in the regular transaction tree of the block.
+type PrefilledTx struct {
+	Index uint32
+	Tx    *MsgTx
+}
+
+// MsgCmpctBlock implements the Message interface and represents a cmpctblock
+// message.  It is used to relay a block in a compact form that identifies the
+// transactions of the regular transaction tree which the receiver is expected
+// to already have by short transaction ids instead of including them in full.
+//
+// The regular transaction tree of the block consists of the prefilled
+// transactions at their specified indexes with the remaining positions filled,
+// in order, by the transactions identified by the short ids.  The stake
+// transaction tree is always included in full.
+//
+// The short ids are calculated by the users of this message.  The nonce is
+// chosen by the sender and is intended to be used when calculating them so
+// that collisions differ between peers.
+//
+// Transactions that the receiver does not have may be requested with a
+// getblocktxns message (MsgGetBlockTxns).
+//
+// This message was not added until protocol version CompactBlockVersion.
+type MsgCmpctBlock struct {
+	Header        BlockHeader
+	Nonce         uint64
+	ShortIDs      []uint64
+	PrefilledTxs  []PrefilledTx
+	STransactions []*MsgTx
+}
+
+// NumTransactions returns the number of transactions in the regular
+// transaction tree of the block the compact block represents.
+func (msg *MsgCmpctBlock) NumTransactions() int {
+	return len(msg.ShortIDs) + len(msg.PrefilledTxs)
+}
+
+// BlockHash computes the block identifier hash for the block the compact block
+// represents.
+func (msg *MsgCmpctBlock) BlockHash() chainhash.Hash {
+	return msg.Header.BlockHash()
+}
+
+// readShortID reads a short transaction id of a compact block from r.
+func readShortID(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:CmpctShortIDSize]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+// writeShortID writes a short transaction id of a compact block to w.
+func writeShortID(w io.Writer, shortID uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], shortID)
+	_, err := w.Write(buf[:CmpctShortIDSize])
+	return err
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgCmpctBlock.BtcDecode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readBlockHeader(r, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = readElement(r, &msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	// Prevent more short ids than there could possibly be transactions in
+	// the regular tx tree.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	shortIDCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if shortIDCount > maxTxPerTree {
+		msg := fmt.Sprintf("too many short ids to fit into a block "+
+			"[count %d, max %d]", shortIDCount, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+	msg.ShortIDs = make([]uint64, 0, shortIDCount)
+	for i := uint64(0); i < shortIDCount; i++ {
+		shortID, err := readShortID(r)
+		if err != nil {
+			return err
+		}
+		msg.ShortIDs = append(msg.ShortIDs, shortID)
+	}
+
+	// Prevent more prefilled transactions than could possibly fit into the
+	// regular tx tree along with the short ids.
+	prefilledCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if prefilledCount > maxTxPerTree-shortIDCount {
+		msg := fmt.Sprintf("too many prefilled transactions to fit into a "+
+			"block [count %d, max %d]", prefilledCount,
+			maxTxPerTree-shortIDCount)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	// The indexes of the prefilled transactions must be strictly increasing
+	// and refer to a position in the regular tx tree.
+	numTxns := shortIDCount + prefilledCount
+	msg.PrefilledTxs = make([]PrefilledTx, 0, prefilledCount)
+	for i := uint64(0); i < prefilledCount; i++ {
+		index, err := ReadVarInt(r, pver)
+		if err != nil {
+			return err
+		}
+		if index >= numTxns || (i > 0 &&
+			index <= uint64(msg.PrefilledTxs[i-1].Index)) {
+
+			msg := fmt.Sprintf("invalid prefilled transaction index %d",
+				index)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		var tx MsgTx
+		err = tx.BtcDecode(r, pver)
+		if err != nil {
+			return err
+		}
+		msg.PrefilledTxs = append(msg.PrefilledTxs, PrefilledTx{
+			Index: uint32(index),
+			Tx:    &tx,
+		})
+	}
+
+	// Prevent more transactions than could possibly fit into the stake
+	// tx tree.
+	stakeTxCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if stakeTxCount > maxTxPerTree {
+		msg := fmt.Sprintf("too many stransactions to fit into a block "+
+			"[count %d, max %d]", stakeTxCount, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+	msg.STransactions = make([]*MsgTx, 0, stakeTxCount)
+	for i := uint64(0); i < stakeTxCount; i++ {
+		var tx MsgTx
+		err := tx.BtcDecode(r, pver)
+		if err != nil {
+			return err
+		}
+		msg.STransactions = append(msg.STransactions, &tx)
+	}
+
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgCmpctBlock.BtcEncode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	numTxns := uint64(msg.NumTransactions())
+	if numTxns > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", numTxns, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+	stakeTxCount := uint64(len(msg.STransactions))
+	if stakeTxCount > maxTxPerTree {
+		msg := fmt.Sprintf("too many stransactions to fit into a block "+
+			"[count %d, max %d]", stakeTxCount, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	err := writeBlockHeader(w, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = writeElement(w, msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	err = WriteVarInt(w, pver, uint64(len(msg.ShortIDs)))
+	if err != nil {
+		return err
+	}
+	for _, shortID := range msg.ShortIDs {
+		if shortID > MaxCmpctShortID {
+			msg := fmt.Sprintf("short id %x exceeds the max allowed value",
+				shortID)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		err = writeShortID(w, shortID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = WriteVarInt(w, pver, uint64(len(msg.PrefilledTxs)))
+	if err != nil {
+		return err
+	}
+	for i := range msg.PrefilledTxs {
+		prefilled := &msg.PrefilledTxs[i]
+		if uint64(prefilled.Index) >= numTxns || (i > 0 &&
+			prefilled.Index <= msg.PrefilledTxs[i-1].Index) {
+
+			msg := fmt.Sprintf("invalid prefilled transaction index %d",
+				prefilled.Index)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		err = WriteVarInt(w, pver, uint64(prefilled.Index))
+		if err != nil {
+			return err
+		}
+		err = prefilled.Tx.BtcEncode(w, pver)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = WriteVarInt(w, pver, stakeTxCount)
+	if err != nil {
+		return err
+	}
+	for _, tx := range msg.STransactions {
+		err = tx.BtcEncode(w, pver)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgCmpctBlock) Command() string {
+	return CmdCmpctBlock
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlockVersion {
+		return 0
+	}
+
+	// A compact block is never larger than the block it represents aside from
+	// the nonce 8 bytes + the additional varint for the number of prefilled
+	// transactions + the index varint of each prefilled transaction.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	indexSize := uint32(VarIntSerializeSize(maxTxPerTree))
+	return MaxBlockPayload + 8 + indexSize + uint32(maxTxPerTree)*indexSize
+}
+
+// NewMsgCmpctBlock returns a new Decred cmpctblock message that conforms to the
+// Message interface using the passed parameters and defaults for the remaining
+// fields.
+func NewMsgCmpctBlock(header *BlockHeader, nonce uint64) *MsgCmpctBlock {
+	return &MsgCmpctBlock{
+		Header: *header,
+		Nonce:  nonce,
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// testCmpctBlock returns a compact block for the test block with the coinbase
+// prefilled, mock short ids for the remaining regular transactions, and the
+// full stake transaction tree.
+func testCmpctBlock() *MsgCmpctBlock {
+	msg := NewMsgCmpctBlock(&testBlock.Header, 0x0102030405060708)
+	msg.PrefilledTxs = []PrefilledTx{{Index: 0, Tx: testBlock.Transactions[0]}}
+	msg.ShortIDs = []uint64{0x0000010203040506, MaxCmpctShortID}
+	msg.STransactions = testBlock.STransactions
+	return msg
+}
+
+// TestCmpctBlock tests the MsgCmpctBlock API.
+func TestCmpctBlock(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "cmpctblock"
+	msg := testCmpctBlock()
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgCmpctBlock: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure the block hash and number of transactions are those of the
+	// block.
+	if got, want := msg.BlockHash(), testBlock.BlockHash(); got != want {
+		t.Errorf("BlockHash: wrong hash - got %v, want %v", got, want)
+	}
+	if got := msg.NumTransactions(); got != 3 {
+		t.Errorf("NumTransactions: wrong count - got %d, want 3", got)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Max block payload + nonce 8 bytes + num prefilled (varInt) 3 bytes +
+	// max transactions 43691 * max index size (varInt) 3 bytes.
+	wantPayload := uint32(1441804)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol "+
+			"version %d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+
+	// Ensure the message is rejected for protocol versions prior to its
+	// introduction.
+	oldPver := CompactBlockVersion - 1
+	if maxPayload := msg.MaxPayloadLength(oldPver); maxPayload != 0 {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want 0", oldPver, maxPayload)
+	}
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcEncode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+	var readMsg MsgCmpctBlock
+	err = readMsg.BtcDecode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcDecode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestCmpctBlockWire tests the MsgCmpctBlock wire encode and decode.
+func TestCmpctBlockWire(t *testing.T) {
+	msg := testCmpctBlock()
+
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	encoded := buf.Bytes()
+
+	// Header + nonce 8 bytes + num short ids 1 byte + 2 short ids * 6 bytes +
+	// num prefilled 1 byte + index 1 byte + coinbase + num stake txns 1 byte +
+	// stake txns.
+	wantLen := MaxBlockHeaderPayload + 8 + 1 + 2*CmpctShortIDSize + 1 + 1 +
+		testBlock.Transactions[0].SerializeSize() + 1
+	for _, tx := range testBlock.STransactions {
+		wantLen += tx.SerializeSize()
+	}
+	if len(encoded) != wantLen {
+		t.Fatalf("BtcEncode: unexpected length - got %d, want %d",
+			len(encoded), wantLen)
+	}
+	shortIDsOffset := MaxBlockHeaderPayload + 8 + 1
+	wantShortIDs := []byte{
+		0x06, 0x05, 0x04, 0x03, 0x02, 0x01,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	}
+	gotShortIDs := encoded[shortIDsOffset : shortIDsOffset+len(wantShortIDs)]
+	if !bytes.Equal(gotShortIDs, wantShortIDs) {
+		t.Fatalf("BtcEncode: unexpected short ids\n got: %s want: %s",
+			spew.Sdump(gotShortIDs), spew.Sdump(wantShortIDs))
+	}
+
+	var readMsg MsgCmpctBlock
+	err := readMsg.BtcDecode(bytes.NewReader(encoded), ProtocolVersion)
+	if err != nil {
+		t.Fatalf("BtcDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&readMsg, msg) {
+		t.Fatalf("BtcDecode\n got: %s want: %s", spew.Sdump(&readMsg),
+			spew.Sdump(msg))
+	}
+
+	// Ensure truncated encodings are detected.
+	for _, max := range []int{0, MaxBlockHeaderPayload, shortIDsOffset + 3,
+		shortIDsOffset + 13, len(encoded) - 1} {
+
+		r := newFixedReader(max, encoded)
+		err := readMsg.BtcDecode(r, ProtocolVersion)
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("BtcDecode: wrong error with max %d: %v", max, err)
+		}
+	}
+}
+
+// TestCmpctBlockWireErrors performs negative tests against wire encode and
+// decode of MsgCmpctBlock to confirm invalid messages are rejected.
+func TestCmpctBlockWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure short ids that do not fit are rejected.
+	msg := testCmpctBlock()
+	msg.ShortIDs[0] = MaxCmpctShortID + 1
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, pver); !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcEncode: unexpected error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+
+	// Ensure prefilled transaction indexes past the end of the regular
+	// transaction tree are rejected.
+	msg = testCmpctBlock()
+	msg.PrefilledTxs[0].Index = 3
+	buf.Reset()
+	if err := msg.BtcEncode(&buf, pver); !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcEncode: unexpected error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+
+	// Ensure decoding rejects the same invalid index by modifying a valid
+	// encoding.
+	msg = testCmpctBlock()
+	buf.Reset()
+	if err := msg.BtcEncode(&buf, pver); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	encoded := buf.Bytes()
+	indexOffset := MaxBlockHeaderPayload + 8 + 1 + 2*CmpctShortIDSize + 1
+	encoded[indexOffset] = 0x03
+	var readMsg MsgCmpctBlock
+	err := readMsg.BtcDecode(bytes.NewReader(encoded), pver)
+	if !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcDecode: unexpected error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+
+	// Ensure duplicate prefilled transaction indexes are rejected.
+	msg = testCmpctBlock()
+	msg.ShortIDs = msg.ShortIDs[:1]
+	msg.PrefilledTxs = append(msg.PrefilledTxs, msg.PrefilledTxs[0])
+	buf.Reset()
+	if err := msg.BtcEncode(&buf, pver); !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcEncode: unexpected error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+
+	// Ensure too many short ids are rejected.
+	buf.Reset()
+	if err := writeBlockHeader(&buf, pver, &testBlock.Header); err != nil {
+		t.Fatalf("writeBlockHeader error %v", err)
+	}
+	buf.Write(make([]byte, 8))
+	buf.Write([]byte{0xfe, 0xff, 0xff, 0xff, 0xff})
+	err = readMsg.BtcDecode(bytes.NewReader(buf.Bytes()), pver)
+	if !errors.Is(err, ErrTooManyTxs) {
+		t.Errorf("BtcDecode: unexpected error - got %v, want %v", err,
+			ErrTooManyTxs)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// MsgGetBlockTxns implements the Message interface and represents a
+// getblocktxns message.  It is used to request the transactions at the
+// specified indexes of the regular transaction tree of a block that were not
+// available when reconstructing it from a compact block (MsgCmpctBlock).
+//
+// The indexes must be strictly increasing.  The requested transactions are
+// delivered with a blocktxns message (MsgBlockTxns).
+//
+// This message was not added until protocol version CompactBlockVersion.
+type MsgGetBlockTxns struct {
+	BlockHash chainhash.Hash
+	Indexes   []uint32
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxns) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgGetBlockTxns.BtcDecode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	// Prevent more indexes than there could possibly be transactions in the
+	// regular tx tree.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transaction indexes for message "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	msg.Indexes = make([]uint32, 0, count)
+	for i := uint64(0); i < count; i++ {
+		index, err := ReadVarInt(r, pver)
+		if err != nil {
+			return err
+		}
+		if index >= maxTxPerTree || (i > 0 &&
+			index <= uint64(msg.Indexes[i-1])) {
+
+			msg := fmt.Sprintf("invalid transaction index %d", index)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		msg.Indexes = append(msg.Indexes, uint32(index))
+	}
+
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxns) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgGetBlockTxns.BtcEncode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count := uint64(len(msg.Indexes))
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transaction indexes for message "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	err = WriteVarInt(w, pver, count)
+	if err != nil {
+		return err
+	}
+	for i, index := range msg.Indexes {
+		if uint64(index) >= maxTxPerTree || (i > 0 &&
+			index <= msg.Indexes[i-1]) {
+
+			msg := fmt.Sprintf("invalid transaction index %d", index)
+			return messageError(op, ErrInvalidMsg, msg)
+		}
+		err = WriteVarInt(w, pver, uint64(index))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgGetBlockTxns) Command() string {
+	return CmdGetBlockTxns
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxns) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlockVersion {
+		return 0
+	}
+
+	// Block hash + num indexes (varInt) + max allowed indexes * max index
+	// size (varInt).
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	indexSize := uint32(VarIntSerializeSize(maxTxPerTree))
+	return chainhash.HashSize + indexSize + uint32(maxTxPerTree)*indexSize
+}
+
+// NewMsgGetBlockTxns returns a new Decred getblocktxns message that conforms to
+// the Message interface using the passed parameters.
+func NewMsgGetBlockTxns(blockHash *chainhash.Hash, indexes []uint32) *MsgGetBlockTxns {
+	return &MsgGetBlockTxns{
+		BlockHash: *blockHash,
+		Indexes:   indexes,
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestGetBlockTxns tests the MsgGetBlockTxns API.
+func TestGetBlockTxns(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "getblocktxns"
+	msg := NewMsgGetBlockTxns(&chainhash.Hash{}, []uint32{1, 2})
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgGetBlockTxns: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Block hash 32 bytes + num indexes (varInt) 3 bytes + max indexes
+	// 43691 * max index size (varInt) 3 bytes.
+	wantPayload := uint32(131108)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure the message is rejected for protocol versions prior to its
+	// introduction.
+	oldPver := CompactBlockVersion - 1
+	if maxPayload := msg.MaxPayloadLength(oldPver); maxPayload != 0 {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want 0", oldPver, maxPayload)
+	}
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcEncode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+	var readMsg MsgGetBlockTxns
+	err = readMsg.BtcDecode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcDecode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestGetBlockTxnsWire tests the MsgGetBlockTxns wire encode and decode along
+// with the rejection of indexes that are not strictly increasing.
+func TestGetBlockTxnsWire(t *testing.T) {
+	hash := chainhash.Hash{0x01}
+	msg := NewMsgGetBlockTxns(&hash, []uint32{1, 3, 300})
+	encoded := append(append([]byte(nil), hash[:]...),
+		0x03,             // Num indexes
+		0x01,             // Index 1
+		0x03,             // Index 3
+		0xfd, 0x2c, 0x01, // Index 300
+	)
+
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), encoded) {
+		t.Fatalf("BtcEncode\n got: %s want: %s", spew.Sdump(buf.Bytes()),
+			spew.Sdump(encoded))
+	}
+	var readMsg MsgGetBlockTxns
+	err := readMsg.BtcDecode(bytes.NewReader(encoded), ProtocolVersion)
+	if err != nil {
+		t.Fatalf("BtcDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&readMsg, msg) {
+		t.Fatalf("BtcDecode\n got: %s want: %s", spew.Sdump(&readMsg),
+			spew.Sdump(msg))
+	}
+
+	// Ensure indexes that are not strictly increasing are rejected.
+	badMsg := NewMsgGetBlockTxns(&hash, []uint32{3, 3})
+	err = badMsg.BtcEncode(&buf, ProtocolVersion)
+	if !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcEncode: unexpected error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+	badEncoded := append(append([]byte(nil), hash[:]...), 0x02, 0x03, 0x01)
+	err = readMsg.BtcDecode(bytes.NewReader(badEncoded), ProtocolVersion)
+	if !errors.Is(err, ErrInvalidMsg) {
+		t.Errorf("BtcDecode: unexpected error - got %v, want %v", err,
+			ErrInvalidMsg)
+	}
+
+	// Ensure too many indexes are rejected.
+	tooMany := append(append([]byte(nil), hash[:]...), 0xfe, 0xff, 0xff,
+		0xff, 0xff)
+	err = readMsg.BtcDecode(bytes.NewReader(tooMany), ProtocolVersion)
+	if !errors.Is(err, ErrTooManyTxs) {
+		t.Errorf("BtcDecode: unexpected error - got %v, want %v", err,
+			ErrTooManyTxs)
+	}
+}
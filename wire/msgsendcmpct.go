@@ -0,0 +1,82 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// CmpctBlockEncodingVersion is the version of the compact block encoding
+// defined by the cmpctblock, getblocktxns, and blocktxns messages.
+const CmpctBlockEncodingVersion = 1
+
+// MsgSendCmpct implements the Message interface and represents a sendcmpct
+// message.  It is used to signal support for receiving compact blocks with the
+// specified encoding version.
+//
+// When HighBandwidth is set, the receiver is requested to announce new blocks
+// by sending a cmpctblock message (MsgCmpctBlock) without waiting for it to be
+// requested.  Otherwise, new blocks are announced as usual and compact blocks
+// may be requested via getdata messages (MsgGetData) with the
+// InvTypeCmpctBlock inventory type.
+//
+// This message was not added until protocol version CompactBlockVersion.
+type MsgSendCmpct struct {
+	HighBandwidth bool
+	Version       uint64
+}
+
+// BtcDecode decodes r using the Decred protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgSendCmpct) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgSendCmpct.BtcDecode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return readElements(r, &msg.HighBandwidth, &msg.Version)
+}
+
+// BtcEncode encodes the receiver to w using the Decred protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgSendCmpct) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgSendCmpct.BtcEncode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return writeElements(w, msg.HighBandwidth, msg.Version)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgSendCmpct) Command() string {
+	return CmdSendCmpct
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgSendCmpct) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlockVersion {
+		return 0
+	}
+
+	// High bandwidth flag 1 byte + version 8 bytes.
+	return 9
+}
+
+// NewMsgSendCmpct returns a new Decred sendcmpct message that conforms to the
+// Message interface using the passed parameters.
+func NewMsgSendCmpct(highBandwidth bool, version uint64) *MsgSendCmpct {
+	return &MsgSendCmpct{
+		HighBandwidth: highBandwidth,
+		Version:       version,
+	}
+}
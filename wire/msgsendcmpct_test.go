@@ -0,0 +1,115 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestSendCmpct tests the MsgSendCmpct API against the latest protocol version
+// and the protocol version prior to its introduction.
+func TestSendCmpct(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Ensure the command is expected value.
+	wantCmd := "sendcmpct"
+	msg := NewMsgSendCmpct(true, CmpctBlockEncodingVersion)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgSendCmpct: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// High bandwidth flag 1 byte + version 8 bytes.
+	wantPayload := uint32(9)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload is zero and the message is rejected for protocol
+	// versions prior to its introduction.
+	oldPver := CompactBlockVersion - 1
+	if maxPayload := msg.MaxPayloadLength(oldPver); maxPayload != 0 {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want 0", oldPver, maxPayload)
+	}
+	var buf bytes.Buffer
+	err := msg.BtcEncode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcEncode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+	var readMsg MsgSendCmpct
+	err = readMsg.BtcDecode(&buf, oldPver)
+	if !errors.Is(err, ErrMsgInvalidForPVer) {
+		t.Errorf("BtcDecode: unexpected error for protocol version %d - got "+
+			"%v, want %v", oldPver, err, ErrMsgInvalidForPVer)
+	}
+}
+
+// TestSendCmpctWire tests the MsgSendCmpct wire encode and decode.
+func TestSendCmpctWire(t *testing.T) {
+	tests := []struct {
+		in  *MsgSendCmpct // Message to encode
+		buf []byte        // Wire encoding
+	}{{
+		NewMsgSendCmpct(false, 1),
+		[]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	}, {
+		NewMsgSendCmpct(true, 2),
+		[]byte{0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	}}
+
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, ProtocolVersion)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgSendCmpct
+		err = msg.BtcDecode(bytes.NewReader(test.buf), ProtocolVersion)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.in) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i, spew.Sdump(&msg),
+				spew.Sdump(test.in))
+			continue
+		}
+
+		// Ensure short reads and writes are detected.
+		for _, max := range []int{0, 1, 5} {
+			w := newFixedWriter(max)
+			if err := test.in.BtcEncode(w, ProtocolVersion); err == nil {
+				t.Errorf("BtcEncode #%d did not fail with max %d", i, max)
+			}
+			r := newFixedReader(max, test.buf)
+			if err := msg.BtcDecode(r, ProtocolVersion); !errors.Is(err,
+				io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+
+				t.Errorf("BtcDecode #%d wrong error with max %d: %v", i, max,
+					err)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NetAddressType identifies the network a NetAddressV2 belongs to.
+type NetAddressType uint8
+
+// These constants define the network address types supported by the addrv2
+// message.
+//
+// NOTE: These values are used in serialization and must not be changed or
+// re-used.  The value 3 was formerly used by Tor v2 onion addresses which are
+// no longer supported.
+const (
+	// UnknownAddressType is the zero value and is not valid on the wire.
+	UnknownAddressType NetAddressType = 0
+
+	// IPv4Address identifies a 4-byte IPv4 address.
+	IPv4Address NetAddressType = 1
+
+	// IPv6Address identifies a 16-byte IPv6 address.
+	IPv6Address NetAddressType = 2
+
+	// TorV3Address identifies a Tor v3 onion service which is encoded as its
+	// 32-byte ed25519 public key.
+	TorV3Address NetAddressType = 4
+
+	// I2PAddress identifies an I2P destination which is encoded as the
+	// 32-byte SHA-256 hash of the destination.
+	I2PAddress NetAddressType = 5
+)
+
+// netAddressTypeStrings is a map of network address types back to their
+// constant names for pretty printing.
+var netAddressTypeStrings = map[NetAddressType]string{
+	UnknownAddressType: "UnknownAddressType",
+	IPv4Address:        "IPv4Address",
+	IPv6Address:        "IPv6Address",
+	TorV3Address:       "TorV3Address",
+	I2PAddress:         "I2PAddress",
+}
+
+// String returns the NetAddressType in human-readable form.
+func (t NetAddressType) String() string {
+	if s, ok := netAddressTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown NetAddressType (%d)", uint8(t))
+}
+
+// maxNetAddressV2Size is the size of the largest encoded address supported by
+// any of the network address types.
+const maxNetAddressV2Size = 32
+
+// netAddressV2Size returns the size of the encoded address for the provided
+// network address type along with whether or not the type is known.
+func netAddressV2Size(addrType NetAddressType) (int, bool) {
+	switch addrType {
+	case IPv4Address:
+		return 4, true
+	case IPv6Address:
+		return 16, true
+	case TorV3Address, I2PAddress:
+		return 32, true
+	}
+	return 0, false
+}
+
+// maxNetAddressV2Payload returns the max payload size for a NetAddressV2 based
+// on the protocol version.
+func maxNetAddressV2Payload(pver uint32) uint32 {
+	// Timestamp 4 bytes + services 8 bytes + type 1 byte + max address size +
+	// port 2 bytes.
+	return 4 + 8 + 1 + maxNetAddressV2Size + 2
+}
+
+// NetAddressV2 defines information about a peer on the network including the
+// time it was last seen, the services it supports, its address, and port.
+//
+// Unlike NetAddress, the address is of variable length depending on its type
+// which allows it to describe addresses that do not fit into an IPv6 address
+// such as Tor v3 onion services and I2P destinations.
+type NetAddressV2 struct {
+	// Timestamp is the last time the address was seen.  This is encoded as a
+	// uint32 on the wire and therefore is limited to 2106.
+	Timestamp time.Time
+
+	// Services is a bitfield which identifies the services supported by the
+	// address.
+	Services ServiceFlag
+
+	// Type is the network the address belongs to.
+	Type NetAddressType
+
+	// EncodedAddr is the address encoded according to its type.  Its length
+	// must match the size required by the type.
+	EncodedAddr []byte
+
+	// Port is the port the peer is using.  This is encoded in big endian on
+	// the wire.
+	Port uint16
+}
+
+// HasService returns whether the specified service is supported by the address.
+func (na *NetAddressV2) HasService(service ServiceFlag) bool {
+	return na.Services&service == service
+}
+
+// AddService adds service as a supported service by the peer generating the
+// message.
+func (na *NetAddressV2) AddService(service ServiceFlag) {
+	na.Services |= service
+}
+
+// NewNetAddressV2 returns a new NetAddressV2 using the provided timestamp,
+// services, address type, encoded address, and port.  The timestamp is rounded
+// to single second precision.
+func NewNetAddressV2(timestamp time.Time, services ServiceFlag, addrType NetAddressType, encodedAddr []byte, port uint16) *NetAddressV2 {
+	return &NetAddressV2{
+		Timestamp:   time.Unix(timestamp.Unix(), 0),
+		Services:    services,
+		Type:        addrType,
+		EncodedAddr: encodedAddr,
+		Port:        port,
+	}
+}
+
+// readNetAddressV2 reads an encoded NetAddressV2 from r depending on the
+// protocol version.
+func readNetAddressV2(op string, r io.Reader, pver uint32, na *NetAddressV2) error {
+	err := readElements(r, (*uint32Time)(&na.Timestamp), &na.Services)
+	if err != nil {
+		return err
+	}
+	var addrType uint8
+	if err := readElement(r, &addrType); err != nil {
+		return err
+	}
+	size, ok := netAddressV2Size(NetAddressType(addrType))
+	if !ok {
+		msg := fmt.Sprintf("unknown network address type %d", addrType)
+		return messageError(op, ErrUnknownNetAddrType, msg)
+	}
+	encodedAddr := make([]byte, size)
+	if _, err := io.ReadFull(r, encodedAddr); err != nil {
+		return err
+	}
+	port, err := binarySerializer.Uint16(r, bigEndian)
+	if err != nil {
+		return err
+	}
+
+	na.Type = NetAddressType(addrType)
+	na.EncodedAddr = encodedAddr
+	na.Port = port
+	return nil
+}
+
+// writeNetAddressV2 serializes a NetAddressV2 to w depending on the protocol
+// version.
+func writeNetAddressV2(op string, w io.Writer, pver uint32, na *NetAddressV2) error {
+	size, ok := netAddressV2Size(na.Type)
+	if !ok {
+		msg := fmt.Sprintf("unknown network address type %d", uint8(na.Type))
+		return messageError(op, ErrUnknownNetAddrType, msg)
+	}
+	if len(na.EncodedAddr) != size {
+		msg := fmt.Sprintf("invalid %v address length %d (expected %d)",
+			na.Type, len(na.EncodedAddr), size)
+		return messageError(op, ErrInvalidMsg, msg)
+	}
+
+	err := writeElements(w, uint32(na.Timestamp.Unix()), na.Services,
+		uint8(na.Type))
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(na.EncodedAddr); err != nil {
+		return err
+	}
+	return binary.Write(w, bigEndian, na.Port)
+}
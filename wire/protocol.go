@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2016 The btcsuite developers
-// Copyright (c) 2015-2024 The Decred developers
+// Copyright (c) 2015-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -17,7 +17,7 @@ const (
 	InitialProcotolVersion uint32 = 1
 
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 11
+	ProtocolVersion uint32 = 14
 
 	// NodeBloomVersion is the protocol version which added the SFNodeBloom
 	// service flag (unused).
@@ -58,6 +58,22 @@ const (
 	// BatchedCFiltersV2Version is the protocol version which adds support
 	// for the batched getcfsv2 and cfiltersv2 messages.
 	BatchedCFiltersV2Version uint32 = 11
+
+	// AddrV2Version is the protocol version which adds the addrv2 message
+	// that supports relaying network addresses of varying lengths such as
+	// Tor v3 onion and I2P addresses.
+	AddrV2Version uint32 = 12
+
+	// CompactBlockVersion is the protocol version which adds the sendcmpct,
+	// cmpctblock, getblocktxns, and blocktxns messages used to relay blocks
+	// as compact blocks that omit the transactions the receiver is expected
+	// to already have.
+	CompactBlockVersion uint32 = 13
+
+	// StemTxVersion is the protocol version which adds the stem transaction
+	// inventory type used to relay transactions along a single path of peers
+	// before they are announced to the rest of the network.
+	StemTxVersion uint32 = 14
 )
 
 // ServiceFlag identifies services supported by a Decred peer.
@@ -74,13 +90,25 @@ const (
 	// SFNodeCF is a flag used to indicate a peer supports v1 gcs filters
 	// (CFs).
 	SFNodeCF
+
+	// SFNodeNetworkLimited is a flag used to indicate a peer is a full node
+	// that has pruned old block data and is therefore only capable of serving
+	// recent blocks.
+	SFNodeNetworkLimited
+
+	// SFNodeEncryptedTransport is a flag used to indicate a peer supports
+	// encrypting and authenticating all messages after the version handshake
+	// with keys derived from an ephemeral key exchange.
+	SFNodeEncryptedTransport
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeCF:      "SFNodeCF",
+	SFNodeNetwork:            "SFNodeNetwork",
+	SFNodeBloom:              "SFNodeBloom",
+	SFNodeCF:                 "SFNodeCF",
+	SFNodeNetworkLimited:     "SFNodeNetworkLimited",
+	SFNodeEncryptedTransport: "SFNodeEncryptedTransport",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -89,6 +117,8 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeNetwork,
 	SFNodeBloom,
 	SFNodeCF,
+	SFNodeNetworkLimited,
+	SFNodeEncryptedTransport,
 }
 
 // String returns the ServiceFlag in human-readable form.
@@ -17,7 +17,9 @@ func TestServiceFlagStringer(t *testing.T) {
 		{SFNodeNetwork, "SFNodeNetwork"},
 		{SFNodeBloom, "SFNodeBloom"},
 		{SFNodeCF, "SFNodeCF"},
-		{0xffffffff, "SFNodeNetwork|SFNodeBloom|SFNodeCF|0xfffffff8"},
+		{SFNodeNetworkLimited, "SFNodeNetworkLimited"},
+		{SFNodeEncryptedTransport, "SFNodeEncryptedTransport"},
+		{0xffffffff, "SFNodeNetwork|SFNodeBloom|SFNodeCF|SFNodeNetworkLimited|SFNodeEncryptedTransport|0xffffffe0"},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -0,0 +1,104 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/zmq"
+)
+
+// setupZMQListeners returns a slice of listeners for all of the configured
+// ZeroMQ compatible publisher endpoints.  Stale Unix domain socket files left
+// behind by an unclean shutdown are removed before listening on them.
+func setupZMQListeners() ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, len(cfg.ZMQPubEndpoints))
+	closeListeners := func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}
+	for _, endpoint := range cfg.ZMQPubEndpoints {
+		network, addr, err := zmq.ParseEndpoint(endpoint)
+		if err != nil {
+			closeListeners()
+			return nil, err
+		}
+		if network == "unix" {
+			if err := removeStaleUnixSocket(addr); err != nil {
+				closeListeners()
+				return nil, err
+			}
+		}
+		listener, err := net.Listen(network, addr)
+		if err != nil {
+			closeListeners()
+			return nil, fmt.Errorf("unable to listen on %s: %w", endpoint,
+				err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// displayHashBytes returns the bytes of the provided hash in the byte-reversed
+// order used when displaying hashes.
+func displayHashBytes(hash *chainhash.Hash) []byte {
+	b := make([]byte, chainhash.HashSize)
+	for i := range hash {
+		b[chainhash.HashSize-1-i] = hash[i]
+	}
+	return b
+}
+
+// zmqPublishTransactions publishes the hash and serialized bytes of the
+// provided transactions when the ZeroMQ compatible publisher is enabled.
+func (s *server) zmqPublishTransactions(txns []*dcrutil.Tx) {
+	if s.zmqPublisher == nil {
+		return
+	}
+	for _, tx := range txns {
+		txBytes, err := tx.MsgTx().Bytes()
+		if err != nil {
+			srvrLog.Errorf("Failed to serialize transaction %v: %v",
+				tx.Hash(), err)
+			continue
+		}
+		s.zmqPublisher.Publish(zmq.TopicHashTx, displayHashBytes(tx.Hash()))
+		s.zmqPublisher.Publish(zmq.TopicRawTx, txBytes)
+	}
+}
+
+// zmqPublishBlockConnected publishes all of the transactions in the provided
+// block followed by its hash and serialized bytes when the ZeroMQ compatible
+// publisher is enabled.
+func (s *server) zmqPublishBlockConnected(block *dcrutil.Block) {
+	if s.zmqPublisher == nil {
+		return
+	}
+	s.zmqPublishTransactions(block.Transactions())
+	s.zmqPublishTransactions(block.STransactions())
+
+	blockBytes, err := block.Bytes()
+	if err != nil {
+		srvrLog.Errorf("Failed to serialize block %v: %v", block.Hash(), err)
+		return
+	}
+	s.zmqPublisher.Publish(zmq.TopicHashBlock, displayHashBytes(block.Hash()))
+	s.zmqPublisher.Publish(zmq.TopicRawBlock, blockBytes)
+}
+
+// zmqPublishBlockDisconnected publishes the hash of the provided block when the
+// ZeroMQ compatible publisher is enabled.
+func (s *server) zmqPublishBlockDisconnected(block *dcrutil.Block) {
+	if s.zmqPublisher == nil {
+		return
+	}
+	s.zmqPublisher.Publish(zmq.TopicBlockDisconnected,
+		displayHashBytes(block.Hash()))
+}